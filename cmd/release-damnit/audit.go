@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+
+	"github.com/dsswift/release-damnit/internal/audit"
+)
+
+// auditRecorder is the --audit-log file and the run being recorded in it.
+type auditRecorder struct {
+	path string
+	run  *audit.Run
+}
+
+// auditLog is nil without --audit-log. It's global so exitWith records
+// failed runs too.
+var auditLog *auditRecorder
+
+// recordAudit appends the run to the audit log with how it ended. Only the
+// first call records; failures are logged, not fatal.
+func recordAudit(code int, errMsg string) {
+	if auditLog == nil {
+		return
+	}
+	path, run := auditLog.path, auditLog.run
+	auditLog = nil
+
+	run.ExitCode, run.Error = code, errMsg
+	run.Outcome = outcomeFor(code, run.Inputs["dry-run"] == "true")
+	if err := audit.Append(path, audit.NewEntry(run)); err != nil {
+		slog.Warn("failed to write audit log", "path", path, "error", err)
+	}
+}
+
+// outcomeFor names how a run with the given exit code ended.
+func outcomeFor(code int, dryRun bool) string {
+	switch code {
+	case exitOK:
+		if dryRun {
+			return "planned"
+		}
+		return "released"
+	case exitNoReleases:
+		return "no-releases"
+	case exitPartialRelease:
+		return "partial-release"
+	case exitFrozen:
+		return "frozen"
+	case exitPendingApproval:
+		return "pending-approval"
+	default:
+		return "failed"
+	}
+}
+
+// flagInputs returns the flags set on the command line, for the audit log.
+func flagInputs(fs *flag.FlagSet) map[string]string {
+	inputs := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		inputs[f.Name] = f.Value.String()
+	})
+	return inputs
+}
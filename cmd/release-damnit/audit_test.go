@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/audit"
+)
+
+func TestOutcomeFor(t *testing.T) {
+	tests := []struct {
+		code   int
+		dryRun bool
+		want   string
+	}{
+		{exitOK, false, "released"},
+		{exitOK, true, "planned"},
+		{exitNoReleases, false, "no-releases"},
+		{exitPartialRelease, false, "partial-release"},
+		{exitFrozen, false, "frozen"},
+		{exitPendingApproval, false, "pending-approval"},
+		{exitConfig, false, "failed"},
+		{exitInternalError, false, "failed"},
+	}
+	for _, tc := range tests {
+		if got := outcomeFor(tc.code, tc.dryRun); got != tc.want {
+			t.Errorf("outcomeFor(%d, %v) = %q, want %q", tc.code, tc.dryRun, got, tc.want)
+		}
+	}
+}
+
+func TestFlagInputs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("dry-run", false, "")
+	fs.String("branch", "", "")
+	fs.String("remote", "origin", "")
+	if err := fs.Parse([]string{"--dry-run", "--branch", "release/1.x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := flagInputs(fs)
+	if len(got) != 2 || got["dry-run"] != "true" || got["branch"] != "release/1.x" {
+		t.Errorf("expected only the flags set, got %v", got)
+	}
+}
+
+func TestRecordAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release-audit.ndjson")
+	auditLog = &auditRecorder{path: path, run: &audit.Run{
+		Start:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Actor:  "octocat",
+		Inputs: map[string]string{"dry-run": "true"},
+	}}
+	t.Cleanup(func() { auditLog = nil })
+
+	recordAudit(exitOK, "")
+	recordAudit(exitError, "recorded once")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry audit.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", data, err)
+	}
+	if entry.Outcome != "planned" || entry.ExitCode != exitOK || entry.Actor != "octocat" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
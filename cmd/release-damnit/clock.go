@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// releaseClock returns the clock changelog dates come from: --release-date
+// (YYYY-MM-DD) if set, else SOURCE_DATE_EPOCH (Unix seconds, the
+// reproducible-builds convention) from sourceDateEpoch, else nil for the
+// current time.
+func releaseClock(releaseDate, sourceDateEpoch string) (func() time.Time, error) {
+	var date time.Time
+	switch {
+	case releaseDate != "":
+		d, err := time.Parse("2006-01-02", releaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --release-date %q (expected YYYY-MM-DD)", releaseDate)
+		}
+		date = d
+	case strings.TrimSpace(sourceDateEpoch) != "":
+		secs, err := strconv.ParseInt(strings.TrimSpace(sourceDateEpoch), 10, 64)
+		if err != nil || secs < 0 {
+			return nil, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q (expected Unix seconds)", sourceDateEpoch)
+		}
+		date = time.Unix(secs, 0).UTC()
+	default:
+		return nil, nil
+	}
+	return func() time.Time { return date }, nil
+}
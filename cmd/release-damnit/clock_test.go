@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReleaseClock(t *testing.T) {
+	tests := []struct {
+		name        string
+		releaseDate string
+		epoch       string
+		want        string // "" for no clock
+		wantErr     string
+	}{
+		{name: "neither"},
+		{name: "release date", releaseDate: "2024-03-01", want: "2024-03-01"},
+		{name: "SOURCE_DATE_EPOCH", epoch: "1709337600", want: "2024-03-02"},
+		{name: "release date wins", releaseDate: "2024-03-01", epoch: "1709337600", want: "2024-03-01"},
+		{name: "bad release date", releaseDate: "03/01/2024", wantErr: "--release-date"},
+		{name: "bad epoch", epoch: "yesterday", wantErr: "SOURCE_DATE_EPOCH"},
+		{name: "negative epoch", epoch: "-1", wantErr: "SOURCE_DATE_EPOCH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock, err := releaseClock(tt.releaseDate, tt.epoch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %s error, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("releaseClock failed: %v", err)
+			}
+			if tt.want == "" {
+				if clock != nil {
+					t.Errorf("expected no clock, got %v", clock())
+				}
+				return
+			}
+			if got := clock().Format(time.DateOnly); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/fixture"
+)
+
+const devtoolUsage = "Usage: release-damnit devtool make-fixture [--scenario NAME[,NAME...]] [--dir DIR] [--merge] [--origin REMOTE] [--list]"
+
+// runDevtoolCommand runs the contributor tools. make-fixture builds a local
+// repository for one or more release scenarios.
+func runDevtoolCommand(args []string) {
+	if len(args) == 0 || args[0] != "make-fixture" {
+		exitWith(exitUsage, devtoolUsage)
+	}
+	fs := flag.NewFlagSet("devtool make-fixture", flag.ExitOnError)
+	scenario := fs.String("scenario", "", "Scenarios to create branches for, comma-separated (default: all)")
+	dir := fs.String("dir", "", "Directory to create the repository in (default: a new temporary directory)")
+	merge := fs.Bool("merge", false, "Merge the scenario's branch into main with --no-ff (requires one scenario)")
+	origin := fs.String("origin", "", "Remote to force-push the branches to; a missing local path becomes a bare repo")
+	list := fs.Bool("list", false, "List the scenarios")
+	if err := fs.Parse(args[1:]); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+
+	if *list {
+		printScenarios(os.Stdout)
+		return
+	}
+
+	opts := &fixture.Options{Dir: *dir, Merge: *merge, Origin: *origin}
+	if *scenario != "" {
+		for _, name := range strings.Split(*scenario, ",") {
+			name = strings.TrimSpace(name)
+			if _, err := fixture.Lookup(name); err != nil {
+				exitWith(exitUsage, "%v", err)
+			}
+			opts.Scenarios = append(opts.Scenarios, name)
+		}
+	}
+	if *merge && len(opts.Scenarios) != 1 {
+		exitWith(exitUsage, "--merge requires exactly one --scenario")
+	}
+	if opts.Dir == "" {
+		tmp, err := os.MkdirTemp("", "release-damnit-fixture-")
+		if err != nil {
+			fatal("Failed to create a directory: %v", err)
+		}
+		opts.Dir = tmp
+	}
+
+	f, err := fixture.Make(opts)
+	if err != nil {
+		fatal("Failed to make fixture: %v", err)
+	}
+	printFixture(os.Stdout, f)
+}
+
+// printScenarios lists the scenarios with what each tests.
+func printScenarios(w io.Writer) {
+	for _, s := range fixture.Scenarios() {
+		fmt.Fprintf(w, "%-18s %s\n", s.Name, s.Description)
+	}
+}
+
+// printFixture describes a created fixture and how to use it.
+func printFixture(w io.Writer, f *fixture.Fixture) {
+	fmt.Fprintf(w, "Created fixture in %s\n", f.Dir)
+	for _, branch := range f.Branches {
+		fmt.Fprintf(w, "  %s\n", branch)
+	}
+	if f.Merged != "" {
+		fmt.Fprintf(w, "\nMerged %s into %s. To see the release:\n", f.Merged, fixture.MainBranch)
+	} else {
+		fmt.Fprintf(w, "\nTo see a scenario's release, merge its branch:\n")
+		fmt.Fprintf(w, "  git -C %s merge --no-ff <branch>\n", f.Dir)
+	}
+	fmt.Fprintf(w, "  release-damnit --repo-path %s --dry-run\n", f.Dir)
+}
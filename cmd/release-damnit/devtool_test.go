@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/fixture"
+)
+
+func TestPrintScenarios(t *testing.T) {
+	var out bytes.Buffer
+	printScenarios(&out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(fixture.Scenarios()) {
+		t.Fatalf("expected a line per scenario, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "single-feat ") || !strings.Contains(lines[0], "jarvis 0.1.0 -> 0.1.1") {
+		t.Errorf("unexpected first line: %q", lines[0])
+	}
+}
+
+func TestPrintFixture(t *testing.T) {
+	var out bytes.Buffer
+	printFixture(&out, &fixture.Fixture{Dir: "/tmp/fx", Branches: []string{"feature/linked-versions"}})
+	if got := out.String(); !strings.Contains(got, "  feature/linked-versions\n") || !strings.Contains(got, "git -C /tmp/fx merge --no-ff <branch>") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	out.Reset()
+	printFixture(&out, &fixture.Fixture{Dir: "/tmp/fx", Branches: []string{"feature/linked-versions"}, Merged: "feature/linked-versions"})
+	if got := out.String(); !strings.Contains(got, "Merged feature/linked-versions into main") || strings.Contains(got, "<branch>") {
+		t.Errorf("unexpected merged output:\n%s", got)
+	}
+	if !strings.Contains(out.String(), "release-damnit --repo-path /tmp/fx --dry-run") {
+		t.Errorf("expected dry run hint:\n%s", out.String())
+	}
+}
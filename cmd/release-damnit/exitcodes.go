@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// Exit codes let scripts branch on the outcome without parsing output.
+// They're documented in the README and --help; don't renumber them.
+const (
+	// exitOK means releases were applied (or, with --dry-run, planned).
+	exitOK = 0
+
+	// exitError is an unexpected failure (git, filesystem, hooks).
+	exitError = 1
+
+	// exitUsage is an invalid flag or subcommand. The flag package also uses 2.
+	exitUsage = 2
+
+	// exitNoReleases means there was nothing to release, or the operator
+	// aborted --interactive review. No files were changed.
+	exitNoReleases = 3
+
+	// exitConfig means release-please-config.json or the manifest is invalid.
+	exitConfig = 4
+
+	// exitPartialRelease means files were updated but creating or verifying
+	// one or more GitHub releases failed.
+	exitPartialRelease = 5
+
+	// exitFrozen means releases are pending but a release freeze is in
+	// effect. No files were changed.
+	exitFrozen = 6
+
+	// exitPendingApproval means releases are waiting for approval on the
+	// config's approval issue. No files were changed.
+	exitPendingApproval = 7
+
+	// exitInternalError is a contract violation (EX_SOFTWARE).
+	exitInternalError = 70
+)
+
+// exitCodeFor classifies an analysis or apply error.
+func exitCodeFor(err error) int {
+	var cfgErr *release.ConfigError
+	if errors.As(err, &cfgErr) {
+		return exitConfig
+	}
+	var freezeErr *release.FreezeError
+	if errors.As(err, &freezeErr) {
+		return exitFrozen
+	}
+	return exitError
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	cfgErr := &release.ConfigError{Err: errors.New("package a missing component name")}
+	if got := exitCodeFor(cfgErr); got != exitConfig {
+		t.Errorf("expected exitConfig for config error, got %d", got)
+	}
+	if got := exitCodeFor(fmt.Errorf("wrapped: %w", cfgErr)); got != exitConfig {
+		t.Errorf("expected exitConfig for wrapped config error, got %d", got)
+	}
+	if got := exitCodeFor(&release.FreezeError{Freeze: &config.Freeze{Name: "year-end"}}); got != exitFrozen {
+		t.Errorf("expected exitFrozen for freeze error, got %d", got)
+	}
+	if got := exitCodeFor(errors.New("git failed")); got != exitError {
+		t.Errorf("expected exitError, got %d", got)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "info", "text")
+	if err != nil {
+		t.Fatalf("newLogger failed: %v", err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("created release", "tag", "jarvis-v0.2.0")
+
+	got := buf.String()
+	if strings.Contains(got, "hidden") {
+		t.Errorf("debug message logged at info level: %s", got)
+	}
+	if got != "level=INFO msg=\"created release\" tag=jarvis-v0.2.0\n" {
+		t.Errorf("unexpected text log: %q", got)
+	}
+}
+
+func TestNewLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "DEBUG", "json")
+	if err != nil {
+		t.Fatalf("newLogger failed: %v", err)
+	}
+
+	logger.Debug("analyzing", "repo", "/tmp/repo")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "DEBUG" || entry["msg"] != "analyzing" || entry["repo"] != "/tmp/repo" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if _, ok := entry["time"]; !ok {
+		t.Error("expected JSON logs to keep timestamps")
+	}
+}
+
+func TestNewLogger_Invalid(t *testing.T) {
+	if _, err := newLogger(&bytes.Buffer{}, "loud", "text"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
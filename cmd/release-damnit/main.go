@@ -4,12 +4,50 @@
 // Usage:
 //
 //	release-damnit [options]
+//	release-damnit report schema [release_report|analysis_input]
+//	release-damnit report verify --key PUB.pem --signature SIG.json REPORT.json
+//	release-damnit config migrate [--write]
+//	release-damnit export --format html|hugo|atom [--output DIR]
+//	release-damnit pending [--branch NAME]
+//	release-damnit status
+//	release-damnit rerelease TAG [--force] [--dry-run]
+//	release-damnit remote OWNER/REPO --ref SHA [--branch NAME] [--json]
+//	release-damnit devtool make-fixture [--scenario NAME] [--dir DIR] [--merge]
 //
 // Options:
 //
 //	--dry-run          Show what would be done without making changes
-//	--create-releases  Create GitHub releases (requires gh CLI)
+//	--create-releases  Create GitHub releases (requires GITHUB_TOKEN or gh CLI)
+//	--github-backend B GitHub access: auto (token, then gh CLI), api, or gh
+//	--close-milestones Close matching milestones when creating releases
+//	--generate-notes merge Add GitHub's generated release notes to ours
+//	--check-run        Post a check run summarizing the analysis on HEAD
+//	--interactive      Review, toggle, and edit releases before applying
+//	--commit MODE      Commit the release changes: single or per-package
+//	--commit-via-api   Create the --commit commits through the GitHub API instead of locally
+//	--release-date DATE Date changelog entries are written with (YYYY-MM-DD)
+//	--build-metadata SRC Stamp +<metadata> on version files: sha, date, or a custom value
+//	--timings          Report how long each phase took
+//	--audit-log PATH   Append a record of the run's decisions to an NDJSON file
+//	--provenance DIR   Write an in-toto/SLSA provenance statement per release
+//	--attach-provenance Attach the provenance statements to the GitHub releases
+//	--sign-report MODE Sign release_report: key (--signing-key) or keyless (cosign)
+//	--signing-key PATH PEM private key for --sign-report key
 //	--repo-url URL     GitHub repository URL (auto-detected if not provided)
+//	--remote NAME      Git remote the repository URL is detected from (default origin)
+//	--branch NAME      Branch to apply branch rules for (default: the checked-out branch)
+//	--cherry-pick-dedup Skip commits patch-equivalent to already released ones
+//	--accumulate       Release everything since each package's last release tag
+//	--path-scope PREFIX Only analyze packages under a path prefix
+//	--no-cache         List commits' files from git instead of .git/release-damnit-cache
+//	--override-freeze  Release even during a configured release freeze
+//	--approval-wait DURATION Wait this long for the approval issue to be approved
+//	--pr-title-fallback Parse non-conventional commits from their PR title and labels
+//	--repo-path PATH   Repository to operate on (default: the one containing the current directory)
+//	--config-file PATH    Config file to use instead of discovering one
+//	--manifest-file PATH  Manifest file (default: next to the config)
+//	--log-level LEVEL  Diagnostics level: debug, info, warn, error (default info)
+//	--log-format FMT   Diagnostics format on stderr: text or json (default text)
 //	--help             Show this help
 package main
 
@@ -17,11 +55,25 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/dsswift/release-damnit/internal/audit"
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/diff"
+	"github.com/dsswift/release-damnit/internal/export"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/interactive"
+	"github.com/dsswift/release-damnit/internal/jira"
+	"github.com/dsswift/release-damnit/internal/notify"
 	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/internal/telemetry"
+	"github.com/dsswift/release-damnit/pkg/contracts"
 )
 
 var (
@@ -30,11 +82,76 @@ var (
 )
 
 func main() {
+	defer recoverViolation()
+
+	// Subcommands are dispatched before flag parsing
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		runReportCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfigCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExportCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "pending" {
+		runPendingCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "status" {
+		runStatusCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rerelease" {
+		runRereleaseCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "remote" {
+		runRemoteCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "devtool" {
+		runDevtoolCommand(os.Args[2:])
+		return
+	}
+
 	// Define flags
 	dryRun := flag.Bool("dry-run", false, "Show what would be done without making changes")
 	createReleases := flag.Bool("create-releases", false, "Create GitHub releases")
+	githubBackend := flag.String("github-backend", release.GitHubBackendAuto, "GitHub access: auto (API with GITHUB_TOKEN, else an authenticated gh CLI), api, or gh")
+	generateNotes := flag.String("generate-notes", "", "Add GitHub's generated release notes to ours: merge")
+	closeMilestones := flag.Bool("close-milestones", false, "Close matching GitHub milestones and open the next ones")
+	checkRun := flag.Bool("check-run", false, "Post a GitHub check run summarizing the analysis on HEAD")
 	repoURL := flag.String("repo-url", "", "GitHub repository URL (auto-detected if not provided)")
+	remote := flag.String("remote", release.DefaultRemote, "Git remote the repository URL is detected from")
+	branch := flag.String("branch", "", "Branch to apply branch rules for (default: the checked-out branch)")
+	prTitleFallback := flag.Bool("pr-title-fallback", false, "Parse non-conventional commits from their pull request's title and labels (requires GitHub access)")
+	cherryPickDedup := flag.Bool("cherry-pick-dedup", false, "Skip commits patch-equivalent to commits already released under another tag")
+	approvalWait := flag.Duration("approval-wait", 0, "How long to wait for the config's approval issue to be approved (default: check once)")
+	overrideFreeze := flag.Bool("override-freeze", false, "Apply and create releases even during a configured release freeze")
+	accumulate := flag.Bool("accumulate", false, "Release everything since each package's last release tag, not just what HEAD brought in")
+	pathScope := flag.String("path-scope", "", "Only analyze packages under this path prefix (e.g. workloads/jarvis/...)")
+	noCache := flag.Bool("no-cache", false, "List every commit's changed files from git instead of the commit cache in .git/release-damnit-cache")
+	repoDir := flag.String("repo-path", ".", "Repository (or a directory inside it) to operate on")
+	configFile := flag.String("config-file", "", "Config file (.json or .yaml) to use instead of discovering one")
+	manifestFile := flag.String("manifest-file", "", "Manifest file (default: release-please-manifest.json next to the config)")
 	verbose := flag.Bool("verbose", false, "Show detailed analysis output")
+	interactiveMode := flag.Bool("interactive", false, "Review releases interactively before applying")
+	commitMode := flag.String("commit", "", "Commit the release changes: single (one commit) or per-package (one per release)")
+	commitViaAPI := flag.Bool("commit-via-api", false, "Create the --commit commits through the GitHub Git Data API and move the branch to them (requires GitHub access)")
+	timings := flag.Bool("timings", false, "Report how long each phase took (also as metrics in release_report)")
+	auditLogPath := flag.String("audit-log", "", "Append a record of the run's inputs and decisions to this NDJSON file")
+	provenanceDir := flag.String("provenance", "", "Write an in-toto/SLSA provenance statement for each release to this directory")
+	attachProvenance := flag.Bool("attach-provenance", false, "Attach the --provenance statements to the GitHub releases as assets")
+	signReport := flag.String("sign-report", "", "Sign release_report and output the signature as release_report_signature: key or keyless")
+	signingKey := flag.String("signing-key", "", "PEM private key for --sign-report key (default: $"+signingKeyEnv+")")
+	buildMetadata := flag.String("build-metadata", "", "Append +<metadata> to the versions written to version files and release_report: sha, date, or a custom value")
+	releaseDate := flag.String("release-date", "", "Date changelog entries are written with, as YYYY-MM-DD (default: SOURCE_DATE_EPOCH, then today)")
+	logLevel := flag.String("log-level", "info", "Diagnostics log level: debug, info, warn, error")
+	logFormat := flag.String("log-format", "text", "Diagnostics log format: text or json")
 	showVersion := flag.Bool("version", false, "Show version information")
 	help := flag.Bool("help", false, "Show help")
 
@@ -42,83 +159,416 @@ func main() {
 
 	if *showVersion {
 		fmt.Printf("release-damnit %s (%s)\n", version, gitSha)
-		os.Exit(0)
+		os.Exit(exitOK)
 	}
 
 	if *help {
 		printHelp()
-		os.Exit(0)
+		os.Exit(exitOK)
 	}
 
-	// Get repository path
-	repoPath, err := os.Getwd()
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
 	if err != nil {
-		fatal("Failed to get current directory: %v", err)
+		exitWith(exitUsage, "%v", err)
+	}
+	if *commitMode != "" && *commitMode != release.CommitSingle && *commitMode != release.CommitPerPackage {
+		exitWith(exitUsage, "invalid --commit %q: must be %s or %s", *commitMode, release.CommitSingle, release.CommitPerPackage)
+	}
+	if *commitViaAPI && *commitMode == "" {
+		exitWith(exitUsage, "--commit-via-api requires --commit")
+	}
+	if *generateNotes != "" {
+		if err := release.ValidateGenerateNotes(*generateNotes); err != nil {
+			exitWith(exitUsage, "%v", err)
+		}
+	}
+	if *attachProvenance && *provenanceDir == "" {
+		exitWith(exitUsage, "--attach-provenance requires --provenance")
+	}
+	if reportSigner, err = newReportSigner(*signReport, *signingKey, os.Getenv(signingKeyEnv)); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	if *buildMetadata != "" {
+		if err := release.ValidateBuildMetadata(*buildMetadata); err != nil {
+			exitWith(exitUsage, "%v", err)
+		}
+	}
+	if err := release.ValidateGitHubBackend(*githubBackend); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	clock, err := releaseClock(*releaseDate, os.Getenv("SOURCE_DATE_EPOCH"))
+	if err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	slog.SetDefault(logger)
+	runStart := time.Now()
+
+	// Find the repository root, so running from a package directory works
+	repoPath, workDir, err := resolveRepo(*repoDir)
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
 	}
 
-	// Auto-detect repo URL if not provided
-	if *repoURL == "" {
-		*repoURL = detectRepoURL(repoPath)
+	// A bare repository (e.g. a server's, from a post-receive hook) has no
+	// working tree to apply releases to, so it's only analyzed
+	if bare, err := git.IsBareRepository(repoPath); err == nil && bare && !*dryRun {
+		slog.Info("bare repository, analyzing without applying")
+		*dryRun = true
 	}
 
+	// Paths on the command line are relative to the working directory
+	for _, path := range []*string{configFile, manifestFile, auditLogPath, provenanceDir} {
+		if *path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*path)
+		if err != nil {
+			fatal("Failed to resolve %s: %v", *path, err)
+		}
+		*path = abs
+	}
+
+	if *auditLogPath != "" {
+		auditLog = &auditRecorder{path: *auditLogPath, run: &audit.Run{
+			Start:   runStart,
+			Actor:   audit.Actor(),
+			Version: version,
+			Inputs:  flagInputs(flag.CommandLine),
+		}}
+	}
+
+	// Check GitHub credentials before anything is written, so a missing
+	// token or gh fails here instead of after the release commit
+	githubSelected := false
+	selectGitHub := func() {
+		backend, err := release.SelectGitHubBackend(*githubBackend, release.DetectGitHubCredentials())
+		if err != nil {
+			fatal("GitHub access unavailable: %v", err)
+		}
+		slog.Debug("using GitHub backend", "backend", backend)
+		githubSelected = true
+	}
+	if *checkRun || *prTitleFallback || (!*dryRun && (*createReleases || *commitViaAPI)) {
+		selectGitHub()
+	}
+
+	slog.Debug("analyzing repository", "path", repoPath)
+
 	// Run analysis
 	opts := &release.Options{
 		RepoPath:             repoPath,
 		DryRun:               *dryRun,
 		RepoURL:              *repoURL,
+		Remote:               *remote,
+		Branch:               *branch,
+		CherryPickDedup:      *cherryPickDedup,
+		Accumulate:           *accumulate,
+		PathScope:            *pathScope,
+		NoCache:              *noCache,
+		OverrideFreeze:       *overrideFreeze,
+		PRTitleFallback:      *prTitleFallback,
 		TreatPreMajorAsMinor: true, // Default behavior for pre-1.0 packages
+		ConfigFile:           *configFile,
+		ManifestFile:         *manifestFile,
+		WorkDir:              workDir,
+		Clock:                clock,
+		Timings:              *timings,
+		BuildMetadata:        *buildMetadata,
 	}
 
 	result, err := release.Analyze(opts)
 	if err != nil {
-		fatal("Analysis failed: %v", err)
+		exitWith(exitCodeFor(err), "Analysis failed: %v", err)
 	}
 
+	// The URL may come from the config or the remote if not provided
+	*repoURL = result.RepoURL
+	if auditLog != nil {
+		auditLog.run.Result, auditLog.run.RepoURL = result, *repoURL
+	}
+	slog.Debug("resolved repository URL", "repo_url", *repoURL)
+
 	// Print analysis results
 	printAnalysis(result, *verbose)
 
+	// Let the operator adjust releases before anything is written
+	if *interactiveMode && len(result.Releases) > 0 {
+		confirmed, err := interactive.Review(result, os.Stdin, os.Stdout)
+		if err != nil {
+			fatal("Interactive review failed: %v", err)
+		}
+		if !confirmed {
+			fmt.Println("Aborted, no changes made.")
+			exit(exitNoReleases)
+		}
+	}
+
+	// Regulated teams sign off on the releases before anything is written
+	var approvalReq *release.ApprovalRequest
+	if !*dryRun && len(result.Releases) > 0 && result.Config.Approval != nil && release.CheckFreeze(result) == nil {
+		if !githubSelected {
+			selectGitHub()
+		}
+		approvalReq, err = release.WaitForApproval(repoPath, result, *approvalWait)
+		if err != nil {
+			fatal("Failed to check release approval: %v", err)
+		}
+		if approvalReq.Approved {
+			slog.Info("releases approved", "by", approvalReq.ApprovedBy, "url", approvalReq.URL)
+		}
+		if auditLog != nil {
+			auditLog.run.Approval = approvalReq
+		}
+	}
+
 	// Output for GitHub Actions (always output, even with no releases)
 	// This ensures downstream jobs can safely call fromJSON on release_report
 	if os.Getenv("GITHUB_OUTPUT") != "" {
-		writeGitHubOutput(result, *repoURL)
+		writeGitHubOutput(result, *repoURL, *dryRun || (approvalReq != nil && !approvalReq.Approved))
+	}
+
+	// Post check run (also in dry-run, so decisions are visible on the commit)
+	if *checkRun {
+		run := release.BuildCheckRun(result, *dryRun)
+		if err := release.CreateCheckRun(repoPath, run); err != nil {
+			slog.Warn("failed to post check run", "error", err)
+		} else {
+			slog.Info("posted check run", "conclusion", run.Conclusion, "url", run.HTMLURL)
+		}
 	}
 
 	if len(result.Releases) == 0 {
 		fmt.Println("\nNo releasable changes.")
-		os.Exit(0)
+		if *timings {
+			printTimings(os.Stdout, result)
+		}
+		sendTelemetry(result, *repoURL, &telemetry.Run{Start: runStart, DryRun: *dryRun})
+		exit(exitNoReleases)
 	}
 
 	// Apply changes
 	if *dryRun {
+		if result.Bare {
+			fmt.Println("\nBare repository: there's no working tree to plan changes against.")
+		} else {
+			changes, err := release.PlanChanges(result)
+			if err != nil {
+				fatal("Failed to plan changes: %v", err)
+			}
+			fmt.Println("\nPlanned changes:")
+			for _, change := range changes {
+				fmt.Println()
+				fmt.Print(diff.Unified(change.Path, change.Old, change.New))
+			}
+		}
+		if *createReleases {
+			ghOpts := &release.GitHubReleaseOptions{
+				RepoPath:      repoPath,
+				DryRun:        true,
+				Milestones:    *closeMilestones,
+				GenerateNotes: *generateNotes,
+			}
+			if *attachProvenance {
+				ghOpts.ProvenanceDir = *provenanceDir
+			}
+			ghReleases, err := release.CreateGitHubReleases(result, ghOpts)
+			if err != nil {
+				fatal("Failed to plan GitHub releases: %v", err)
+			}
+			printPlannedReleases(ghReleases, *closeMilestones, *generateNotes)
+		}
 		fmt.Println("\n--dry-run specified, no changes made.")
+		if *timings {
+			printTimings(os.Stdout, result)
+		}
+		sendTelemetry(result, *repoURL, &telemetry.Run{Start: runStart, DryRun: true})
 	} else {
-		fmt.Println("\nApplying changes...")
-		if err := release.Apply(result, false); err != nil {
+		if err := release.CheckFreeze(result); err != nil {
+			if *timings {
+				printTimings(os.Stdout, result)
+			}
+			sendTelemetry(result, *repoURL, &telemetry.Run{Start: runStart})
+			exitWith(exitFrozen, "%v", err)
+		}
+		if approvalReq != nil && !approvalReq.Approved {
+			fmt.Printf("\nWaiting for approval: %s\n", approvalReq.URL)
+			if *timings {
+				printTimings(os.Stdout, result)
+			}
+			sendTelemetry(result, *repoURL, &telemetry.Run{Start: runStart})
+			exit(exitPendingApproval)
+		}
+		if err := release.RunHooks(result, config.HookPreApply); err != nil {
+			fatal("%v", err)
+		}
+
+		slog.Info("applying changes", "releases", len(result.Releases))
+		applyStart := time.Now()
+		if *commitMode != "" {
+			// Releases are tagged at their commits, which GitHub must have first
+			shas, err := release.ApplyAndCommit(result, &release.CommitOptions{Mode: *commitMode, ViaAPI: *commitViaAPI, Push: *createReleases})
+			if err != nil {
+				fatal("Failed to apply and commit changes: %v", err)
+			}
+			fmt.Printf("Committed %d release commit(s)\n", len(shas))
+			if os.Getenv("GITHUB_OUTPUT") != "" {
+				writeReleaseCommitOutputs(result, *repoURL)
+			}
+		} else if err := release.Apply(result, false); err != nil {
 			fatal("Failed to apply changes: %v", err)
 		}
+		result.Timings.Since(release.PhaseApply, applyStart)
+
+		if err := release.RunHooks(result, config.HookPostApply); err != nil {
+			fatal("%v", err)
+		}
 
 		// Print what was updated
 		for _, rel := range result.Releases {
 			fmt.Printf("  Updated %s: %s → %s\n", rel.Package.Component, rel.OldVersion, rel.NewVersion)
 		}
 
+		// Record how each version was produced, for supply-chain verification
+		if *provenanceDir != "" {
+			paths, err := release.WriteProvenance(result, *provenanceDir, provenanceOptions(runStart, os.Getenv))
+			if err != nil {
+				fatal("Failed to write provenance: %v", err)
+			}
+			for _, path := range paths {
+				slog.Info("wrote provenance", "path", path)
+			}
+		}
+
 		// Create GitHub releases if requested
+		releaseFailed := false
+		releasesCreated := 0
+		var ghReleases []*release.GitHubRelease
 		if *createReleases {
-			fmt.Println("\nCreating GitHub releases...")
+			slog.Info("creating GitHub releases")
 			ghOpts := &release.GitHubReleaseOptions{
-				RepoPath: repoPath,
-				DryRun:   false,
+				RepoPath:      repoPath,
+				DryRun:        false,
+				Milestones:    *closeMilestones,
+				GenerateNotes: *generateNotes,
+			}
+			if *attachProvenance {
+				ghOpts.ProvenanceDir = *provenanceDir
+			}
+			releaseStart := time.Now()
+			var err error
+			ghReleases, err = release.CreateGitHubReleases(result, ghOpts)
+			result.Timings.Since(release.PhaseReleaseCreation, releaseStart)
+			releasesCreated = len(ghReleases)
+			if auditLog != nil {
+				for _, ghRel := range ghReleases {
+					auditLog.run.Tags = append(auditLog.run.Tags, ghRel.TagName)
+				}
 			}
-			ghReleases, err := release.CreateGitHubReleases(result, ghOpts)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				slog.Error("failed to create GitHub releases", "created", len(ghReleases), "total", len(result.Releases), "error", err)
+				releaseFailed = true
 			}
 			for _, ghRel := range ghReleases {
-				fmt.Printf("  Created release %s\n", ghRel.TagName)
+				slog.Info("created release", "tag", ghRel.TagName, "url", ghRel.URL)
+				if ghRel.DiscussionURL != "" {
+					slog.Info("opened release discussion", "tag", ghRel.TagName, "url", ghRel.DiscussionURL)
+				}
+				if ghRel.Milestone != nil {
+					slog.Info("closed milestone", "title", ghRel.Milestone.Title, "url", ghRel.Milestone.HTMLURL)
+				}
+			}
+
+			// The outputs written before creating releases had no verification results
+			if os.Getenv("GITHUB_OUTPUT") != "" {
+				writeReleaseCreatedOutputs(result, ghReleases)
+				if result.Timings == nil {
+					writeReleaseReportOutput(result, *repoURL)
+				}
+			}
+		}
+
+		// Jira and notifications only hear about releases that exist, not
+		// ones whose creation failed or never ran
+		released := result
+		if *createReleases {
+			released = createdReleases(result, ghReleases)
+		}
+
+		// Update referenced Jira issues
+		if result.Config.Jira.UpdatesIssues() && len(released.Releases) > 0 {
+			slog.Info("updating Jira issues")
+			if err := updateJiraIssues(released, *repoURL); err != nil {
+				slog.Warn("failed to update Jira issues", "error", err)
+			}
+		}
+
+		// Send release notifications
+		if result.Config.Notifications != nil && len(released.Releases) > 0 {
+			slog.Info("sending notifications")
+			report := release.BuildReleaseReport(released, *repoURL)
+			deliveries, err := notify.Send(result.Config.Notifications, report, nil)
+			if err != nil {
+				slog.Warn("failed to send notifications", "error", err)
+			}
+			for _, d := range deliveries {
+				if d.StatusCode >= 200 && d.StatusCode < 300 {
+					slog.Info("sent notification", "kind", d.Kind, "target", d.Target, "components", d.Components)
+				}
 			}
 		}
+
+		// The approval issue is done once the releases are out
+		if approvalReq != nil && !releaseFailed {
+			if err := release.CloseApproval(repoPath, approvalReq); err != nil {
+				slog.Warn("failed to close approval issue", "error", err)
+			}
+		}
+
+		// Rewrite the report so its metrics include apply and release creation
+		if result.Timings != nil && os.Getenv("GITHUB_OUTPUT") != "" {
+			writeReleaseReportOutput(result, *repoURL)
+		}
+		if *timings {
+			printTimings(os.Stdout, result)
+		}
+
+		run := &telemetry.Run{Start: runStart, ReleasesCreated: releasesCreated}
+		if releaseFailed {
+			run.Failures = len(result.Releases) - releasesCreated
+		}
+		sendTelemetry(result, *repoURL, run)
+
+		if releaseFailed {
+			exit(exitPartialRelease)
+		}
 	}
+	recordAudit(exitOK, "")
+}
 
+// printPlannedReleases shows the GitHub releases --create-releases would
+// create, with the gh command for each. The notes go to the command on stdin.
+func printPlannedReleases(ghReleases []*release.GitHubRelease, milestones bool, generateNotes string) {
+	fmt.Println("\nPlanned GitHub releases:")
+	for _, ghRel := range ghReleases {
+		fmt.Printf("\n  %s\n", ghRel.TagName)
+		fmt.Printf("    Title:     %s\n", ghRel.Title)
+		fmt.Printf("    Target:    %s\n", ghRel.TargetSHA)
+		if milestones {
+			fmt.Printf("    Milestone: %s (closed if open)\n", release.MilestoneTitle(ghRel.PackageInfo.Package.Component, ghRel.PackageInfo.NewVersion))
+		}
+		if ghRel.DiscussionCategory != "" {
+			fmt.Printf("    Discussion: in %s\n", ghRel.DiscussionCategory)
+		}
+		fmt.Printf("    Command:   %s\n", ghRel.Command())
+		if generateNotes == release.GenerateNotesMerge {
+			fmt.Println("    Notes (GitHub's generated notes are added at release time):")
+		} else {
+			fmt.Println("    Notes:")
+		}
+		for _, line := range strings.Split(strings.TrimRight(ghRel.Notes, "\n"), "\n") {
+			fmt.Println(strings.TrimRight("      "+line, " "))
+		}
+	}
 }
 
 func printHelp() {
@@ -126,17 +576,132 @@ func printHelp() {
 
 Usage:
   release-damnit [options]
+  release-damnit report schema [release_report|analysis_input]
+  release-damnit report verify --key PUB.pem --signature SIG.json REPORT.json
+  release-damnit config migrate [--write]
+  release-damnit export --format html|hugo|atom [--output DIR]
+  release-damnit devtool make-fixture [--scenario NAME] [--dir DIR] [--merge]
 
 Options:
   --dry-run          Show what would be done without making changes
-  --create-releases  Create GitHub releases (requires gh CLI)
-  --repo-url URL     GitHub repository URL (auto-detected if not provided)
+  --create-releases  Create GitHub releases (requires GITHUB_TOKEN or gh CLI)
+  --github-backend B How to reach GitHub: auto (default: the API with GITHUB_TOKEN or
+                     GH_TOKEN, else an authenticated gh CLI), api, or gh. Credentials are
+                     checked before anything is written, listing what was found
+  --close-milestones Close the milestone matching each release (e.g. "jarvis 0.2.0")
+                     and open the next one (requires --create-releases)
+  --generate-notes merge
+                     Add the "What's Changed" and "New Contributors" sections of GitHub's
+                     generated release notes to ours (with --create-releases); if GitHub
+                     can't generate them, the release goes ahead with ours
+  --check-run        Post a GitHub check run summarizing the analysis on HEAD
+                     (also in --dry-run; requires a token with checks:write)
+  --repo-url URL     GitHub repository URL (default: remotes.canonical-url from the config,
+                     then the URL of --remote)
+  --remote NAME      Git remote the repository URL is detected from (default origin)
+  --branch NAME      Branch whose rules (e.g. max-bump) apply (default: the checked-out
+                     branch; with HEAD detached, the branch of the GitHub Actions run or
+                     the only branch whose tip HEAD is)
+  --pr-title-fallback
+                     Parse non-conventional commits (e.g. squash merges) from the title
+                     and labels of their pull request (requires GitHub access)
+  --cherry-pick-dedup
+                     Skip commits patch-equivalent to a commit already released under
+                     another tag (e.g. a hotfix cherry-picked from a maintenance branch)
+  --accumulate       Analyze every commit since each package's last release tag instead
+                     of the commits HEAD brought in, for scheduled release trains (e.g. a
+                     weekly cron workflow); packages without a tag use all history
+  --path-scope PREFIX
+                     Only analyze and release the packages under a path prefix (e.g.
+                     workloads/jarvis/...), for per-team release pipelines; packages
+                     linked to one in scope come along
+  --no-cache         List every commit's changed files from git instead of the commit
+                     cache in .git/release-damnit-cache, which reruns over the same
+                     commits (a dry run, then the real run) otherwise reuse
+  --override-freeze  Apply and create releases even while one of the config's
+                     freeze-windows is in effect (analysis always runs)
+  --approval-wait DURATION
+                     With an approval section in the config, how long to wait for the
+                     approval issue to be approved (e.g. 2h; default: check once and
+                     exit 7 if it isn't)
+  --repo-path PATH   Repository, or a directory inside it, to operate on (default: the
+                     current directory); the repo root is found with git rev-parse
+  --config-file PATH Config file (.json, or .yaml/.yml for the native format) to use
+                     instead of discovering one; it's read on its own, without merging
+  --manifest-file PATH
+                     Manifest file (default: release-please-manifest.json next to the config)
   --verbose          Show detailed analysis output (unmatched directories, commit details)
+  --log-level LEVEL  Diagnostics level: debug, info, warn, error (default info)
+  --log-format FMT   Diagnostics format: text or json (default text). Diagnostics go to
+                     stderr; the human-readable summary stays on stdout
+  --interactive      Review releases before applying: toggle packages, edit target
+                     versions, preview changelogs, then confirm
+  --commit MODE      Commit the release changes: single (one commit for all releases)
+                     or per-package (one commit per release, e.g. "chore(jarvis): release 0.2.0")
+  --commit-via-api   Create the --commit commits through the GitHub Git Data API and move
+                     the branch to them, for branches that forbid direct pushes; the
+                     token's owner (e.g. a GitHub App) authors them
+  --timings          Report how long config load, git traversal, file listing, analysis,
+                     apply, and release creation took; release_report gets a metrics block
+  --audit-log PATH   Append one JSON line per run to PATH (e.g. release-audit.ndjson):
+                     who ran it, the analyzed range, inputs, decisions, and created tags
+  --provenance DIR   After applying, write an in-toto statement with SLSA provenance for
+                     each release to DIR/<tag>.intoto.json (builder, commit range,
+                     config digest, tag)
+  --attach-provenance
+                     Upload each release's provenance statement as a release asset
+                     (with --provenance and --create-releases)
+  --release-date DATE
+                     Date changelog entries are written with, as YYYY-MM-DD (default:
+                     SOURCE_DATE_EPOCH if set, for reproducible output; otherwise today)
+  --build-metadata SRC
+                     Append +<metadata> to the versions written to version files and to
+                     release_report's build_version: sha (short HEAD SHA), date
+                     (YYYYMMDD release date), or a custom value (e.g. ci.1234); tags,
+                     the manifest, and changelogs keep the plain version
   --version          Show version information
   --help             Show this help
 
+Commands:
+  report schema      Print the JSON Schema for release_report (default) or analysis_input
+  report verify      Check a release_report against its release_report_signature and
+                     the signing key's PEM public key (--sign-report key signatures)
+  config migrate     Print release-please-config.json converted to .release-damnit.yaml
+                     (--write saves it instead; release-please-config.json is left as is)
+  export             Render changelogs as an HTML or Hugo release notes site, or Atom
+                     feeds (--format html|hugo|atom, --output DIR)
+  pending            List packages with commits since their last release tag and the
+                     versions releasing them would give, whatever HEAD is (exits 3 if
+                     nothing is pending)
+  status             Compare each component's latest release tag with the versions in
+                     the configured environments' deployment manifests
+  rerelease TAG      Regenerate a published release's notes from history and update its
+                     title and body in place (--force deletes and recreates it at the
+                     tag; --dry-run shows the diff)
+  remote OWNER/REPO --ref SHA
+                     Analyze a commit of a GitHub repository through the GitHub API,
+                     without a clone (read-only; --json prints release_report)
+  devtool make-fixture
+                     Build a local repo with the e2e mock monorepo and a branch per
+                     release scenario, to reproduce bugs (--list shows the scenarios;
+                     --merge merges one scenario's branch into main)
+
+Exit Codes:
+  0   Releases applied (or planned, with --dry-run)
+  1   Unexpected error (git, filesystem, hooks)
+  2   Invalid flag or command
+  3   No releasable changes (or --interactive review aborted); nothing changed
+  4   Invalid release-please-config.json or manifest
+  5   Files updated, but creating or verifying one or more GitHub releases failed
+  6   Releases pending, but a release freeze is in effect; nothing changed
+  7   Releases waiting for approval on the approval issue; nothing changed
+  70  Internal check failed (see the hint printed with the error)
+
 Environment Variables:
   GITHUB_OUTPUT      Path to GitHub Actions output file (set automatically in Actions)
+  GITHUB_TOKEN       Token for the GitHub API (GH_TOKEN also works)
+  GITHUB_API_URL     GitHub API URL for GitHub Enterprise (default https://api.github.com)
+  GITHUB_REPOSITORY  owner/repo for API requests (default: from the origin remote)
 
 Examples:
   # See what would be released
@@ -148,21 +713,172 @@ Examples:
   # Also create GitHub releases
   release-damnit --create-releases
 
+  # Review and adjust releases by hand before applying
+  release-damnit --interactive
+
   # Debug: show why commits weren't matched to packages
   release-damnit --dry-run --verbose`)
 }
 
+func runReportCommand(args []string) {
+	if len(args) > 0 && args[0] == "verify" {
+		runReportVerifyCommand(args[1:])
+		return
+	}
+	if len(args) == 0 || args[0] != "schema" {
+		exitWith(exitUsage, "Usage: release-damnit report schema [release_report|analysis_input]")
+	}
+
+	name := "release_report"
+	if len(args) > 1 {
+		name = args[1]
+	}
+
+	schema, err := release.Schema(name)
+	if err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	fmt.Println(schema)
+}
+
+func runConfigCommand(args []string) {
+	fs := flag.NewFlagSet("config migrate", flag.ExitOnError)
+	write := fs.Bool("write", false, "Write .release-damnit.yaml instead of printing it")
+	if len(args) == 0 || args[0] != "migrate" {
+		exitWith(exitUsage, "Usage: release-damnit config migrate [--write]")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+
+	repoPath, _, err := resolveRepo(".")
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
+	}
+
+	out, err := config.Migrate(repoPath)
+	if err != nil {
+		exitWith(exitConfig, "%v", err)
+	}
+	if !*write {
+		fmt.Print(out)
+		return
+	}
+
+	path := filepath.Join(repoPath, config.NativeConfigFile)
+	if _, err := os.Stat(path); err == nil {
+		fatal("%s already exists; remove it or run without --write", config.NativeConfigFile)
+	}
+	if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+		fatal("Failed to write %s: %v", config.NativeConfigFile, err)
+	}
+	fmt.Printf("Wrote %s. Settings in it take precedence over %s.\n", config.NativeConfigFile, config.ReleasePleaseConfigFile)
+}
+
+func runExportCommand(args []string) {
+	const usage = "Usage: release-damnit export --format html|hugo|atom [--output DIR] [--repo-url URL] [--remote NAME]"
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "Output format: html (standalone pages), hugo (content with front matter), or atom (feeds)")
+	output := fs.String("output", "release-notes", "Directory to write the files to")
+	repoURL := fs.String("repo-url", "", "Repository URL for release links in feeds (auto-detected if not provided)")
+	remote := fs.String("remote", release.DefaultRemote, "Git remote the repository URL is detected from")
+	if err := fs.Parse(args); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	switch *format {
+	case export.FormatHTML, export.FormatHugo, export.FormatAtom:
+	default:
+		exitWith(exitUsage, usage)
+	}
+
+	repoPath, _, err := resolveRepo(".")
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
+	}
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		exitWith(exitConfig, "%v", err)
+	}
+
+	opts := &export.Options{
+		Format:  *format,
+		OutDir:  *output,
+		RepoURL: release.ResolveRepoURL(&release.Options{RepoPath: repoPath, RepoURL: *repoURL, Remote: *remote}, cfg),
+	}
+	paths, err := export.Export(cfg, opts)
+	if err != nil {
+		fatal("Failed to export changelogs: %v", err)
+	}
+	fmt.Printf("Wrote %d file(s) to %s\n", len(paths), *output)
+}
+
 func printAnalysis(result *release.AnalysisResult, verbose bool) {
-	if result.MergeInfo.IsMerge {
+	if result.Accumulated {
+		fmt.Printf("Accumulating commits since each package's last release tag up to %s...\n", result.MergeInfo.HeadSHA[:7])
+		fmt.Printf("Commits: %d\n", len(result.Commits))
+	} else if result.MergeInfo.IsMerge {
 		fmt.Printf("Analyzing merge commit %s...\n", result.MergeInfo.HeadSHA[:7])
 		fmt.Printf("Merge range: %s..%s (%d commits)\n",
 			result.MergeInfo.MergeBase[:7],
 			result.MergeInfo.MergeHead[:7],
 			len(result.Commits))
+		if n := len(result.MergeInfo.MergeHeads); n > 1 {
+			fmt.Printf("Octopus merge: %d branches merged\n", n)
+		}
 	} else {
 		fmt.Printf("Analyzing commit %s...\n", result.MergeInfo.HeadSHA[:7])
 		fmt.Printf("Commits: %d\n", len(result.Commits))
 	}
+	if branch := result.Config.BranchFor(result.Branch); branch.Capped() {
+		fmt.Printf("Branch %s: bumps capped at %s\n", result.Branch, branch.MaxBump)
+	}
+	if f := result.Freeze; f != nil {
+		status := "releases won't be applied"
+		if result.FreezeOverridden {
+			status = "overridden"
+		}
+		fmt.Printf("Release freeze %q in effect until %s (%s)\n", f.Name, f.End.Format(time.RFC3339), status)
+	}
+
+	if result.Stats != nil && result.Stats.PRTitleCommits > 0 {
+		fmt.Printf("Parsed %d commit(s) from pull request titles\n", result.Stats.PRTitleCommits)
+	}
+	if result.Stats != nil && result.Stats.ReleaseCommits > 0 {
+		fmt.Printf("Ignored %d release commit(s)\n", result.Stats.ReleaseCommits)
+	}
+	if result.Stats != nil && result.Stats.IgnoredCommits > 0 {
+		fmt.Printf("Ignored %d commit(s) touching only ignore-files\n", result.Stats.IgnoredCommits)
+	}
+	if result.Stats != nil && result.Stats.BinaryCommits > 0 {
+		fmt.Printf("Ignored %d commit(s) touching only binary files\n", result.Stats.BinaryCommits)
+	}
+	if result.Stats != nil && len(result.Stats.CherryPicked) > 0 {
+		fmt.Printf("Skipped %d commit(s) already released via cherry-pick\n", len(result.Stats.CherryPicked))
+		if verbose {
+			for _, c := range result.Stats.CherryPicked {
+				fmt.Printf("  %s %s\n", c.ShortSHA, c.Description)
+			}
+		}
+	}
+
+	if result.Stats != nil {
+		if result.Stats.ReleasedCommits > 0 {
+			fmt.Printf("Passed over %d commit(s) already released by every package they touch\n", result.Stats.ReleasedCommits)
+		}
+		if result.Stats.HugeMergeFiles > 0 {
+			fmt.Printf("Merge changed %d files: commits mapped to packages by path\n", result.Stats.HugeMergeFiles)
+		}
+		for _, d := range result.Stats.Deferred {
+			fmt.Printf("Deferred %s: %d of %d releasable commit(s) pending (min-commits)\n", d.Package.Component, d.Pending, d.Package.MinCommits)
+		}
+		for _, c := range result.Stats.TagCollisions {
+			if c.NewTag == "" {
+				fmt.Printf("Skipped %s: tag %s already exists at %.7s (tag-collision)\n", c.Package.Component, c.Tag, c.Commit)
+			} else {
+				fmt.Printf("Bumped %s again: tag %s already exists at %.7s, releasing %s (tag-collision)\n", c.Package.Component, c.Tag, c.Commit, c.NewTag)
+			}
+		}
+	}
 
 	// Always show summary line when there are unmatched commits
 	if result.Stats != nil && result.Stats.TotalCommits > 0 {
@@ -218,32 +934,149 @@ func printAnalysis(result *release.AnalysisResult, verbose bool) {
 				rel.BumpType,
 				rel.NewVersion,
 				commitCount)
+			if rel.FirstRelease {
+				fmt.Printf("  %-20s   first release\n", "")
+			}
+			if rel.ReconciledFrom != "" {
+				fmt.Printf("  %-20s   was %s, bumped from linked group's %s\n", "", rel.OldVersion, rel.ReconciledFrom)
+			}
+			if len(rel.DependencyChain) > 0 {
+				fmt.Printf("  %-20s   dependency update: %s → %s\n", "", strings.Join(rel.DependencyChain, " → "), rel.Package.Component)
+			}
+		}
+	}
+}
+
+// sendTelemetry exports the run's metrics to the configured telemetry
+// exporters. Failures are logged, not fatal.
+func sendTelemetry(result *release.AnalysisResult, repoURL string, run *telemetry.Run) {
+	if result.Config.Telemetry == nil {
+		return
+	}
+	slog.Info("sending telemetry")
+	run.Report = release.BuildReleaseReport(result, repoURL)
+	deliveries, err := telemetry.Send(result.Config.Telemetry, run, nil)
+	if err != nil {
+		slog.Warn("failed to send telemetry", "error", err)
+	}
+	for _, d := range deliveries {
+		if d.StatusCode >= 200 && d.StatusCode < 300 {
+			slog.Info("sent telemetry", "kind", d.Kind, "target", d.Target)
+		}
+	}
+}
+
+func updateJiraIssues(result *release.AnalysisResult, repoURL string) error {
+	client, err := jira.NewClient(result.Config.Jira)
+	if err != nil {
+		return err
+	}
+
+	var releases []*jira.Release
+	for _, rel := range result.Releases {
+		jiraRel := &jira.Release{
+			Component: rel.Package.Component,
+			Version:   rel.NewVersion,
 		}
+		jiraRel.URL = changelog.BuildReleaseURL(repoURL, fmt.Sprintf("%s-v%s", rel.Package.Component, rel.NewVersion))
+		for _, c := range rel.Commits {
+			jiraRel.Messages = append(jiraRel.Messages, c.Description)
+		}
+		releases = append(releases, jiraRel)
 	}
+
+	updates, err := client.UpdateIssues(releases)
+	for _, u := range updates {
+		if u.Commented || u.Transitioned {
+			slog.Info("updated Jira issue", "key", u.Key, "commented", u.Commented, "transitioned", u.Transitioned)
+		}
+	}
+	return err
+}
+
+// createdReleases returns a copy of result holding only the releases whose
+// GitHub release was created and verified.
+func createdReleases(result *release.AnalysisResult, ghReleases []*release.GitHubRelease) *release.AnalysisResult {
+	created := *result
+	created.Releases = nil
+	for _, ghRel := range ghReleases {
+		if rel := ghRel.PackageInfo; rel != nil && rel.Verified != nil && *rel.Verified {
+			created.Releases = append(created.Releases, rel)
+		}
+	}
+	return &created
 }
 
-func detectRepoURL(repoPath string) string {
-	cmd := exec.Command("git", "remote", "get-url", "origin")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+// writeReleaseReportOutput appends release_report to GITHUB_OUTPUT again.
+// The last value written for an output wins.
+func writeReleaseReportOutput(result *release.AnalysisResult, repoURL string) {
+	f, err := os.OpenFile(os.Getenv("GITHUB_OUTPUT"), os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
-		return ""
+		slog.Warn("failed to open GITHUB_OUTPUT", "error", err)
+		return
 	}
+	defer f.Close()
+	writeReleaseReport(f, result, repoURL)
+}
 
-	url := strings.TrimSpace(string(output))
+// writeReleaseCreatedOutputs appends releases_created and each component's
+// release_created and release_url to GITHUB_OUTPUT again once
+// CreateGitHubReleases ran, so only releases that exist are reported as
+// created. The URL of one that doesn't is cleared.
+func writeReleaseCreatedOutputs(result *release.AnalysisResult, ghReleases []*release.GitHubRelease) {
+	f, err := os.OpenFile(os.Getenv("GITHUB_OUTPUT"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		slog.Warn("failed to open GITHUB_OUTPUT", "error", err)
+		return
+	}
+	defer f.Close()
 
-	// Convert SSH URL to HTTPS
-	if strings.HasPrefix(url, "git@github.com:") {
-		url = strings.Replace(url, "git@github.com:", "https://github.com/", 1)
+	urls := make(map[*release.PackageRelease]string)
+	for _, ghRel := range ghReleases {
+		if rel := ghRel.PackageInfo; rel != nil && rel.Verified != nil && *rel.Verified {
+			urls[rel] = ghRel.URL
+		}
+	}
+	fmt.Fprintf(f, "releases_created=%t\n", len(urls) > 0)
+	for _, rel := range result.Releases {
+		url, ok := urls[rel]
+		fmt.Fprintf(f, "%s--release_created=%t\n", rel.Package.Component, ok)
+		fmt.Fprintf(f, "%s--release_url=%s\n", rel.Package.Component, url)
 	}
+}
 
-	// Remove .git suffix
-	url = strings.TrimSuffix(url, ".git")
+// writeReleaseCommitOutputs appends release_report and each component's sha
+// to GITHUB_OUTPUT again once the release commits exist, so they name the
+// commits the releases are tagged at.
+func writeReleaseCommitOutputs(result *release.AnalysisResult, repoURL string) {
+	f, err := os.OpenFile(os.Getenv("GITHUB_OUTPUT"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		slog.Warn("failed to open GITHUB_OUTPUT", "error", err)
+		return
+	}
+	defer f.Close()
+	writeReleaseReport(f, result, repoURL)
+	for _, rel := range result.Releases {
+		fmt.Fprintf(f, "%s--sha=%s\n", rel.Package.Component, result.TargetSHA(rel))
+	}
+}
 
-	return url
+// writeReleaseReport writes the release_report output line, and its
+// release_report_signature with --sign-report.
+func writeReleaseReport(w io.Writer, result *release.AnalysisResult, repoURL string) {
+	releaseReportJSON, err := json.Marshal(release.BuildReleaseReport(result, repoURL))
+	if err != nil {
+		slog.Warn("failed to marshal output", "output", "release_report", "error", err)
+		return
+	}
+	fmt.Fprintf(w, "release_report=%s\n", string(releaseReportJSON))
+	writeReportSignature(w, releaseReportJSON)
 }
 
-func writeGitHubOutput(result *release.AnalysisResult, repoURL string) {
+// writeGitHubOutput writes the action outputs. heldBack means the releases
+// won't be created this run: it's a dry run, or they're waiting on the
+// approval issue.
+func writeGitHubOutput(result *release.AnalysisResult, repoURL string, heldBack bool) {
 	outputFile := os.Getenv("GITHUB_OUTPUT")
 	if outputFile == "" {
 		return
@@ -251,46 +1084,113 @@ func writeGitHubOutput(result *release.AnalysisResult, repoURL string) {
 
 	f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to open GITHUB_OUTPUT: %v\n", err)
+		slog.Warn("failed to open GITHUB_OUTPUT", "error", err)
 		return
 	}
 	defer f.Close()
 
-	// releases_created (simple boolean for quick checks)
-	if len(result.Releases) > 0 {
-		fmt.Fprintln(f, "releases_created=true")
-	} else {
-		fmt.Fprintln(f, "releases_created=false")
-	}
+	// releases_created (simple boolean for quick checks). A freeze, a dry
+	// run, or a pending approval holds the releases back, so downstream jobs
+	// mustn't build them.
+	created := len(result.Releases) > 0 && release.CheckFreeze(result) == nil && !heldBack
+	fmt.Fprintf(f, "releases_created=%t\n", created)
 
 	// Build and output release_report JSON
-	releaseReport := release.BuildReleaseReport(result, repoURL)
-	releaseReportJSON, err := json.Marshal(releaseReport)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to marshal release_report: %v\n", err)
-	} else {
-		fmt.Fprintf(f, "release_report=%s\n", string(releaseReportJSON))
-	}
+	writeReleaseReport(f, result, repoURL)
 
 	// Build and output analysis_input JSON
 	analysisInput := release.BuildAnalysisInput(result)
 	analysisInputJSON, err := json.Marshal(analysisInput)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to marshal analysis_input: %v\n", err)
+		slog.Warn("failed to marshal output", "output", "analysis_input", "error", err)
 	} else {
 		fmt.Fprintf(f, "analysis_input=%s\n", string(analysisInputJSON))
 	}
 
+	// unreleased_changes: releasable changes no package owns, for a step
+	// that flags configuration gaps
+	unreleasedJSON, err := json.Marshal(release.BuildUnreleasedChanges(result))
+	if err != nil {
+		slog.Warn("failed to marshal output", "output", "unreleased_changes", "error", err)
+	} else {
+		fmt.Fprintf(f, "unreleased_changes=%s\n", string(unreleasedJSON))
+	}
+
+	// paths_released and versions (Release Please compatibility)
+	pathsReleased := make([]string, 0, len(result.Releases))
+	versions := make(map[string]string, len(result.Releases))
+	for _, rel := range result.Releases {
+		pathsReleased = append(pathsReleased, rel.Package.Path)
+		versions[rel.Package.Component] = rel.NewVersion
+	}
+	pathsReleasedJSON, err := json.Marshal(pathsReleased)
+	if err != nil {
+		slog.Warn("failed to marshal output", "output", "paths_released", "error", err)
+	} else {
+		fmt.Fprintf(f, "paths_released=%s\n", string(pathsReleasedJSON))
+	}
+	versionsJSON, err := json.Marshal(versions)
+	if err != nil {
+		slog.Warn("failed to marshal output", "output", "versions", "error", err)
+	} else {
+		fmt.Fprintf(f, "versions=%s\n", string(versionsJSON))
+	}
+
 	// Per-component outputs (backward compatibility)
 	for _, rel := range result.Releases {
 		component := rel.Package.Component
-		fmt.Fprintf(f, "%s--release_created=true\n", component)
+		tagName := fmt.Sprintf("%s-v%s", component, rel.NewVersion)
+		fmt.Fprintf(f, "%s--release_created=%t\n", component, created)
 		fmt.Fprintf(f, "%s--version=%s\n", component, rel.NewVersion)
-		fmt.Fprintf(f, "%s--tag_name=%s-v%s\n", component, component, rel.NewVersion)
+		fmt.Fprintf(f, "%s--tag_name=%s\n", component, tagName)
+		fmt.Fprintf(f, "%s--sha=%s\n", component, result.TargetSHA(rel))
+		fmt.Fprintf(f, "%s--path=%s\n", component, rel.Package.Path)
+		// Release Please only links releases that exist
+		if releaseURL := changelog.BuildReleaseURL(repoURL, tagName); created && releaseURL != "" {
+			fmt.Fprintf(f, "%s--release_url=%s\n", component, releaseURL)
+		}
+	}
+}
+
+// recoverViolation turns a contract violation into an error message and exit
+// code instead of a stack trace. Violations almost always come from input the
+// code didn't expect, so the hint points at configuration first.
+func recoverViolation() {
+	r := recover()
+	if r == nil {
+		return
 	}
+	v, ok := r.(contracts.ContractViolation)
+	if !ok {
+		panic(r)
+	}
+
+	slog.Error("internal check failed: "+v.Message, "check", v.Type, "location", v.Location)
+	fmt.Fprintln(os.Stderr, `
+This usually means the repository or configuration is in a state release-damnit
+doesn't expect, for example a package without a "component", an invalid version
+in release-please-manifest.json, or a shallow clone missing the merge base.
+Check release-please-config.json and re-run with --log-level debug. If it keeps
+happening, report it at https://github.com/dsswift/release-damnit/issues with
+the message above.`)
+	recordAudit(exitInternalError, "internal check failed: "+v.Message)
+	os.Exit(exitInternalError)
 }
 
 func fatal(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
-	os.Exit(1)
+	exitWith(exitError, format, args...)
+}
+
+// exitWith logs an error and exits with the given code.
+func exitWith(code int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	slog.Error(msg)
+	recordAudit(code, msg)
+	os.Exit(code)
+}
+
+// exit records the run in the audit log and exits with the given code.
+func exit(code int) {
+	recordAudit(code, "")
+	os.Exit(code)
 }
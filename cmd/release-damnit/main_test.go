@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestCreatedReleases(t *testing.T) {
+	verified, unverified := true, false
+	api := &release.PackageRelease{Package: &config.Package{Component: "api"}, NewVersion: "1.1.0", Verified: &verified}
+	web := &release.PackageRelease{Package: &config.Package{Component: "web"}, NewVersion: "2.0.0", Verified: &unverified}
+	cli := &release.PackageRelease{Package: &config.Package{Component: "cli"}, NewVersion: "0.3.0"}
+	result := &release.AnalysisResult{Releases: []*release.PackageRelease{api, web, cli}}
+
+	// web was created but never showed up; cli was never attempted
+	created := createdReleases(result, []*release.GitHubRelease{{PackageInfo: api}, {PackageInfo: web}})
+	if len(created.Releases) != 1 || created.Releases[0] != api {
+		t.Errorf("expected only api announced, got %v", created.Releases)
+	}
+	if len(result.Releases) != 3 {
+		t.Errorf("expected the result left alone, got %d releases", len(result.Releases))
+	}
+}
+
+// outputResult returns a result releasing api, already committed at its
+// release commit, and web, not yet committed.
+func outputResult() *release.AnalysisResult {
+	api := &config.Package{Path: "services/api", Component: "api"}
+	web := &config.Package{Path: "apps/web", Component: "web"}
+	return &release.AnalysisResult{
+		Config:    &config.Config{Packages: map[string]*config.Package{api.Path: api, web.Path: web}},
+		MergeInfo: &git.MergeInfo{HeadSHA: "head1234567890"},
+		Releases: []*release.PackageRelease{
+			{Package: api, OldVersion: "1.0.0", NewVersion: "1.1.0", ReleaseSHA: "rel1234567890"},
+			{Package: web, OldVersion: "2.0.0", NewVersion: "2.0.1"},
+		},
+	}
+}
+
+// readOutputs reads a GITHUB_OUTPUT file; a key written again overrides the
+// earlier value, as in GitHub Actions.
+func readOutputs(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		outputs[key] = value
+	}
+	return outputs
+}
+
+func TestWriteGitHubOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", path)
+
+	writeGitHubOutput(outputResult(), "https://github.com/acme/repo", false)
+	outputs := readOutputs(t, path)
+
+	want := map[string]string{
+		"releases_created":     "true",
+		"paths_released":       `["services/api","apps/web"]`,
+		"versions":             `{"api":"1.1.0","web":"2.0.1"}`,
+		"api--release_created": "true",
+		"api--version":         "1.1.0",
+		"api--tag_name":        "api-v1.1.0",
+		"api--sha":             "rel1234567890",
+		"api--path":            "services/api",
+		"api--release_url":     "https://github.com/acme/repo/releases/tag/api-v1.1.0",
+		"web--sha":             "head1234567890",
+		"web--tag_name":        "web-v2.0.1",
+	}
+	for key, value := range want {
+		if outputs[key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, outputs[key])
+		}
+	}
+	for _, key := range []string{"release_report", "analysis_input", "unreleased_changes"} {
+		if !strings.HasPrefix(outputs[key], "{") && !strings.HasPrefix(outputs[key], "[") {
+			t.Errorf("%s: expected JSON, got %q", key, outputs[key])
+		}
+	}
+}
+
+func TestWriteGitHubOutput_HeldBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", path)
+
+	writeGitHubOutput(outputResult(), "https://github.com/acme/repo", true)
+	outputs := readOutputs(t, path)
+
+	if outputs["releases_created"] != "false" || outputs["api--release_created"] != "false" {
+		t.Errorf("expected no releases created while held back, got %v", outputs)
+	}
+	if _, ok := outputs["api--release_url"]; ok {
+		t.Error("expected no release URL for a release that doesn't exist")
+	}
+}
+
+func TestWriteReleaseCreatedOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", path)
+
+	result := outputResult()
+	writeGitHubOutput(result, "https://github.com/acme/repo", false)
+
+	// api was created and verified; web's release never showed up
+	verified, unverified := true, false
+	api, web := result.Releases[0], result.Releases[1]
+	api.Verified, web.Verified = &verified, &unverified
+	writeReleaseCreatedOutputs(result, []*release.GitHubRelease{
+		{TagName: "api-v1.1.0", URL: "https://github.com/acme/repo/releases/tag/api-v1.1.0", PackageInfo: api},
+		{TagName: "web-v2.0.1", PackageInfo: web},
+	})
+	outputs := readOutputs(t, path)
+
+	want := map[string]string{
+		"releases_created":     "true",
+		"api--release_created": "true",
+		"api--release_url":     "https://github.com/acme/repo/releases/tag/api-v1.1.0",
+		"web--release_created": "false",
+		"web--release_url":     "",
+	}
+	for key, value := range want {
+		if outputs[key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, outputs[key])
+		}
+	}
+
+	// None verified means nothing was released
+	api.Verified = &unverified
+	writeReleaseCreatedOutputs(result, []*release.GitHubRelease{{PackageInfo: api}, {PackageInfo: web}})
+	if outputs := readOutputs(t, path); outputs["releases_created"] != "false" || outputs["api--release_url"] != "" {
+		t.Errorf("expected no releases created, got %v", outputs)
+	}
+}
+
+func TestWriteReleaseCommitOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", path)
+
+	result := outputResult()
+	writeGitHubOutput(result, "", false)
+
+	// Committing the release afterwards points web's outputs at its commit
+	result.Releases[1].ReleaseSHA = "web1234567890"
+	writeReleaseCommitOutputs(result, "")
+	outputs := readOutputs(t, path)
+
+	if outputs["web--sha"] != "web1234567890" || outputs["api--sha"] != "rel1234567890" {
+		t.Errorf("expected the release commits, got api %q, web %q", outputs["api--sha"], outputs["web--sha"])
+	}
+	if !strings.Contains(outputs["release_report"], "web1234567890") {
+		t.Errorf("expected the release report rewritten with the release commit, got %s", outputs["release_report"])
+	}
+}
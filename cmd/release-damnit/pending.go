@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// runPendingCommand lists the packages with unreleased changes and the
+// versions releasing them would give. It exits with exitNoReleases when
+// nothing is pending.
+func runPendingCommand(args []string) {
+	fs := flag.NewFlagSet("pending", flag.ExitOnError)
+	branch := fs.String("branch", "", "Branch to apply branch rules for (default: the checked-out branch)")
+	configFile := fs.String("config-file", "", "Config file to use instead of discovering one")
+	manifestFile := fs.String("manifest-file", "", "Manifest file (default: next to the config)")
+	if err := fs.Parse(args); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	if fs.NArg() > 0 {
+		exitWith(exitUsage, "Usage: release-damnit pending [--branch NAME] [--config-file PATH] [--manifest-file PATH]")
+	}
+
+	repoPath, workDir, err := resolveRepo(".")
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
+	}
+	report, err := release.Pending(&release.Options{
+		RepoPath:             repoPath,
+		Branch:               *branch,
+		TreatPreMajorAsMinor: true,
+		ConfigFile:           *configFile,
+		ManifestFile:         *manifestFile,
+		WorkDir:              workDir,
+	})
+	if err != nil {
+		exitWith(exitCodeFor(err), "Analysis failed: %v", err)
+	}
+
+	printPending(os.Stdout, report)
+	if !report.HasPending() {
+		os.Exit(exitNoReleases)
+	}
+}
+
+// printPending lists each package's version, latest tag, and pending
+// release, then any packages whose manifest version and tags disagree.
+func printPending(w io.Writer, report *release.PendingReport) {
+	fmt.Fprintln(w, "Packages (commits since each package's last release tag):")
+	var drifted []*release.PendingPackage
+	for _, p := range report.Packages {
+		since := p.LatestTag
+		if since == "" {
+			since = "no release tag"
+		}
+		switch {
+		case p.Release != nil:
+			fmt.Fprintf(w, "  %-20s %s → %s (%s) [%d commit(s) since %s]\n",
+				p.Package.Component, p.Version, p.Release.NewVersion, p.Release.BumpType, len(p.Release.Commits), since)
+			if len(p.Release.DependencyChain) > 0 {
+				fmt.Fprintf(w, "  %-20s   dependency update: %s → %s\n", "", strings.Join(p.Release.DependencyChain, " → "), p.Package.Component)
+			}
+		case p.Deferred > 0:
+			fmt.Fprintf(w, "  %-20s %s, held back by min-commits: %d of %d commit(s)\n",
+				p.Package.Component, p.Version, p.Deferred, p.Package.MinCommits)
+		default:
+			fmt.Fprintf(w, "  %-20s %s, up to date (%s)\n", p.Package.Component, p.Version, since)
+		}
+		if p.TagDrift() {
+			drifted = append(drifted, p)
+		}
+	}
+
+	if len(drifted) > 0 {
+		fmt.Fprintln(w, "\nManifest and tags disagree:")
+		for _, p := range drifted {
+			fmt.Fprintf(w, "  %-20s manifest is at %s but the latest tag is %s\n", p.Package.Component, p.Version, p.LatestTag)
+		}
+	}
+	if !report.HasPending() {
+		fmt.Fprintln(w, "\nNo pending releases")
+	}
+}
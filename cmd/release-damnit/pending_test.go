@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+	semver "github.com/dsswift/release-damnit/internal/version"
+)
+
+func TestPrintPending(t *testing.T) {
+	api := &config.Package{Path: "services/api", Component: "api"}
+	web := &config.Package{Path: "apps/web", Component: "web"}
+	docs := &config.Package{Path: "docs", Component: "docs", MinCommits: 3}
+	cli := &config.Package{Path: "apps/cli", Component: "cli"}
+	apiRelease := &release.PackageRelease{Package: api, BumpType: semver.Minor, OldVersion: "1.1.0", NewVersion: "1.2.0", Commits: []*git.Commit{{}, {}}}
+	webRelease := &release.PackageRelease{Package: web, BumpType: semver.Patch, OldVersion: "0.4.0", NewVersion: "0.4.1", DependencyChain: []string{"api"}}
+	report := &release.PendingReport{
+		Result: &release.AnalysisResult{Releases: []*release.PackageRelease{apiRelease, webRelease}},
+		Packages: []*release.PendingPackage{
+			{Package: api, Version: "1.1.0", LatestTag: "api-v1.1.0", Release: apiRelease},
+			{Package: web, Version: "0.4.0", Release: webRelease},
+			{Package: docs, Version: "2.0.0", LatestTag: "docs-v2.0.0", Deferred: 1},
+			{Package: cli, Version: "3.1.0", LatestTag: "cli-v3.0.0"},
+		},
+	}
+
+	var out bytes.Buffer
+	printPending(&out, report)
+	got := out.String()
+	for _, want := range []string{
+		"api                  1.1.0 → 1.2.0 (minor) [2 commit(s) since api-v1.1.0]\n",
+		"web                  0.4.0 → 0.4.1 (patch) [0 commit(s) since no release tag]\n",
+		"dependency update: api → web\n",
+		"docs                 2.0.0, held back by min-commits: 1 of 3 commit(s)\n",
+		"cli                  3.1.0, up to date (cli-v3.0.0)\n",
+		"Manifest and tags disagree:\n  cli                  manifest is at 3.1.0 but the latest tag is cli-v3.0.0\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "No pending releases") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	out.Reset()
+	printPending(&out, &release.PendingReport{Result: &release.AnalysisResult{}})
+	if !strings.Contains(out.String(), "No pending releases") {
+		t.Errorf("expected nothing pending, got:\n%s", out.String())
+	}
+}
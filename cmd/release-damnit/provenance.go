@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// provenanceBuilderID identifies release-damnit as the builder outside
+// GitHub Actions.
+const provenanceBuilderID = "https://github.com/dsswift/release-damnit"
+
+// provenanceOptions describes this run for provenance statements. In GitHub
+// Actions the workflow is the builder and the workflow run the invocation.
+func provenanceOptions(start time.Time, getenv func(string) string) *release.ProvenanceOptions {
+	opts := &release.ProvenanceOptions{
+		Builder: release.ProvenanceBuilder{
+			ID:      provenanceBuilderID,
+			Version: map[string]string{"release-damnit": version},
+		},
+		StartedOn: start,
+	}
+
+	server := getenv("GITHUB_SERVER_URL")
+	if server == "" {
+		return opts
+	}
+	if ref := getenv("GITHUB_WORKFLOW_REF"); ref != "" {
+		opts.Builder.ID = server + "/" + ref
+	}
+	if repo, runID := getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID"); repo != "" && runID != "" {
+		opts.InvocationID = fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, runID)
+		if attempt := getenv("GITHUB_RUN_ATTEMPT"); attempt != "" {
+			opts.InvocationID += "/attempts/" + attempt
+		}
+	}
+	return opts
+}
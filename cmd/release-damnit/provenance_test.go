@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProvenanceOptions(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	local := provenanceOptions(start, func(string) string { return "" })
+	if local.Builder.ID != provenanceBuilderID || local.InvocationID != "" || !local.StartedOn.Equal(start) {
+		t.Errorf("unexpected local options: %+v", local)
+	}
+	if local.Builder.Version["release-damnit"] != version {
+		t.Errorf("expected the release-damnit version, got %v", local.Builder.Version)
+	}
+
+	env := map[string]string{
+		"GITHUB_SERVER_URL":   "https://github.com",
+		"GITHUB_WORKFLOW_REF": "acme/platform/.github/workflows/release.yml@refs/heads/main",
+		"GITHUB_REPOSITORY":   "acme/platform",
+		"GITHUB_RUN_ID":       "42",
+		"GITHUB_RUN_ATTEMPT":  "2",
+	}
+	actions := provenanceOptions(start, func(name string) string { return env[name] })
+	if want := "https://github.com/acme/platform/.github/workflows/release.yml@refs/heads/main"; actions.Builder.ID != want {
+		t.Errorf("expected builder %s, got %s", want, actions.Builder.ID)
+	}
+	if want := "https://github.com/acme/platform/actions/runs/42/attempts/2"; actions.InvocationID != want {
+		t.Errorf("expected invocation %s, got %s", want, actions.InvocationID)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+const remoteUsage = "Usage: release-damnit remote OWNER/REPO --ref SHA [--branch NAME] [--config-file PATH] [--manifest-file PATH] [--json] [--github-backend B]"
+
+// runRemoteCommand analyzes a commit of a GitHub repository through the
+// GitHub API, without a clone.
+func runRemoteCommand(args []string) {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		exitWith(exitUsage, remoteUsage)
+	}
+	repo := args[0]
+	fs := flag.NewFlagSet("remote", flag.ExitOnError)
+	ref := fs.String("ref", "", "Commit to analyze, as HEAD would be: a SHA, branch, or tag")
+	branch := fs.String("branch", "", "Branch whose rules (e.g. max-bump) apply")
+	configFile := fs.String("config-file", "", "Config file in the repository (default: release-please-config.json and .release-damnit.yaml)")
+	manifestFile := fs.String("manifest-file", "", "Manifest file in the repository (default: next to the config)")
+	repoURL := fs.String("repo-url", "", "Repository URL for links (default: the repository on GITHUB_SERVER_URL)")
+	jsonOut := fs.Bool("json", false, "Print release_report as JSON instead of the summary")
+	verbose := fs.Bool("verbose", false, "Show detailed analysis output")
+	githubBackend := fs.String("github-backend", release.GitHubBackendAuto, "GitHub access: auto, api, or gh")
+	if err := fs.Parse(args[1:]); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	if fs.NArg() > 0 || *ref == "" {
+		exitWith(exitUsage, remoteUsage)
+	}
+	if err := release.ValidateGitHubBackend(*githubBackend); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+
+	backend, err := release.SelectGitHubBackend(*githubBackend, release.DetectGitHubCredentials())
+	if err != nil {
+		fatal("GitHub access unavailable: %v", err)
+	}
+	slog.Debug("using GitHub backend", "backend", backend)
+
+	result, err := release.AnalyzeRemote(&release.RemoteOptions{
+		Repo:                 repo,
+		Ref:                  *ref,
+		ConfigFile:           *configFile,
+		ManifestFile:         *manifestFile,
+		Branch:               *branch,
+		RepoURL:              *repoURL,
+		TreatPreMajorAsMinor: true,
+	})
+	if err != nil {
+		exitWith(exitCodeFor(err), "Analysis failed: %v", err)
+	}
+
+	if os.Getenv("GITHUB_OUTPUT") != "" {
+		writeGitHubOutput(result, result.RepoURL, false)
+	}
+	if *jsonOut {
+		if err := printRemoteReport(os.Stdout, result); err != nil {
+			fatal("%v", err)
+		}
+	} else {
+		printAnalysis(result, *verbose)
+	}
+	if len(result.Releases) == 0 {
+		exit(exitNoReleases)
+	}
+}
+
+// printRemoteReport prints a remote analysis's release_report as indented
+// JSON.
+func printRemoteReport(w io.Writer, result *release.AnalysisResult) error {
+	data, err := json.MarshalIndent(release.BuildReleaseReport(result, result.RepoURL), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode release report: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
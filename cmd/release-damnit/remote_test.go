@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestPrintRemoteReport(t *testing.T) {
+	result := &release.AnalysisResult{
+		Remote:    "o/r",
+		RepoURL:   "https://github.com/o/r",
+		MergeInfo: &git.MergeInfo{HeadSHA: "abc1234567890"},
+	}
+
+	var out bytes.Buffer
+	if err := printRemoteReport(&out, result); err != nil {
+		t.Fatalf("printRemoteReport failed: %v", err)
+	}
+	var report release.ReleaseReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected a JSON report, got %q: %v", out.String(), err)
+	}
+	if report.Schema != release.ReleaseReportSchemaURL || report.Summary.TotalReleases != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
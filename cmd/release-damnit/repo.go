@@ -0,0 +1,30 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// resolveRepo finds the repository containing dir. It returns the repo root
+// and dir as the directory config discovery starts from, both with symlinks
+// resolved so the discovery walk can reach the root (git reports the
+// physical path, e.g. /private/var rather than /var on macOS).
+func resolveRepo(dir string) (root, workDir string, err error) {
+	workDir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if workDir, err = filepath.EvalSymlinks(workDir); err != nil {
+		return "", "", err
+	}
+
+	root, err = git.RepoRoot(workDir)
+	if err != nil {
+		return "", "", err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", "", err
+	}
+	return root, workDir, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveRepo_FromSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	sub := filepath.Join(dir, "workloads", "jarvis")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRoot, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, workDir, err := resolveRepo(sub)
+	if err != nil {
+		t.Fatalf("resolveRepo failed: %v", err)
+	}
+	if root != wantRoot {
+		t.Errorf("root = %s, want %s", root, wantRoot)
+	}
+	if workDir != filepath.Join(wantRoot, "workloads", "jarvis") {
+		t.Errorf("workDir = %s, want the subdirectory", workDir)
+	}
+}
+
+func TestResolveRepo_NotARepo(t *testing.T) {
+	if _, _, err := resolveRepo(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/diff"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+const rereleaseUsage = "Usage: release-damnit rerelease TAG [--force] [--dry-run] [--repo-url URL] [--remote NAME] [--github-backend B]"
+
+// runRereleaseCommand regenerates the notes of a published release from
+// history and updates it on GitHub.
+func runRereleaseCommand(args []string) {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		exitWith(exitUsage, rereleaseUsage)
+	}
+	tag := args[0]
+	fs := flag.NewFlagSet("rerelease", flag.ExitOnError)
+	force := fs.Bool("force", false, "Delete the release and create it again instead of editing it (the tag is kept)")
+	dryRun := fs.Bool("dry-run", false, "Show how the release would change without changing it")
+	repoURL := fs.String("repo-url", "", "Repository URL for commit and compare links (auto-detected if not provided)")
+	remote := fs.String("remote", release.DefaultRemote, "Git remote the repository URL is detected from")
+	githubBackend := fs.String("github-backend", release.GitHubBackendAuto, "GitHub access: auto, api, or gh")
+	if err := fs.Parse(args[1:]); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	if fs.NArg() > 0 {
+		exitWith(exitUsage, rereleaseUsage)
+	}
+	if err := release.ValidateGitHubBackend(*githubBackend); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+
+	repoPath, _, err := resolveRepo(".")
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
+	}
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		exitWith(exitConfig, "%v", err)
+	}
+	backend, err := release.SelectGitHubBackend(*githubBackend, release.DetectGitHubCredentials())
+	if err != nil {
+		fatal("GitHub access unavailable: %v", err)
+	}
+	slog.Debug("using GitHub backend", "backend", backend)
+
+	result, err := release.Rerelease(cfg, tag, &release.RereleaseOptions{
+		RepoPath: repoPath,
+		RepoURL:  *repoURL,
+		Remote:   *remote,
+		Force:    *force,
+		DryRun:   *dryRun,
+	})
+	if err != nil {
+		fatal("Failed to rerelease %s: %v", tag, err)
+	}
+	printRerelease(os.Stdout, result, *dryRun)
+}
+
+// printRerelease shows how a release's title and notes changed.
+func printRerelease(w io.Writer, result *release.RereleaseResult, dryRun bool) {
+	ghRelease := result.Release
+	if !result.Changed() && !result.Recreated {
+		fmt.Fprintf(w, "Release %s is up to date\n", ghRelease.TagName)
+		return
+	}
+
+	if result.Release.Title != result.OldTitle {
+		fmt.Fprintf(w, "Title: %s → %s\n", result.OldTitle, ghRelease.Title)
+	}
+	fmt.Fprint(w, diff.Unified(ghRelease.TagName, result.OldNotes, ghRelease.Notes))
+
+	switch {
+	case dryRun:
+		fmt.Fprintf(w, "\nDry run: release %s not changed\n", ghRelease.TagName)
+	case result.Recreated:
+		fmt.Fprintf(w, "\nRecreated release %s: %s\n", ghRelease.TagName, ghRelease.URL)
+	default:
+		fmt.Fprintf(w, "\nUpdated release %s: %s\n", ghRelease.TagName, ghRelease.URL)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestPrintRerelease(t *testing.T) {
+	result := &release.RereleaseResult{
+		Release: &release.GitHubRelease{
+			TagName: "api-v1.2.0",
+			Title:   "api v1.2.0",
+			Notes:   "## api v1.2.0\n\n* fix typo\n",
+			URL:     "https://github.com/o/r/releases/tag/api-v1.2.0",
+		},
+		OldTitle: "api v1.2.0",
+		OldNotes: "## api v1.2.0\n\n* fix tpyo\n",
+	}
+
+	var out bytes.Buffer
+	printRerelease(&out, result, true)
+	got := out.String()
+	if !strings.Contains(got, "-* fix tpyo\n+* fix typo\n") {
+		t.Errorf("expected a diff of the notes, got:\n%s", got)
+	}
+	if strings.Contains(got, "Title:") || !strings.Contains(got, "Dry run: release api-v1.2.0 not changed") {
+		t.Errorf("unexpected dry run output:\n%s", got)
+	}
+
+	out.Reset()
+	result.Release.Title = "api 1.2.0"
+	printRerelease(&out, result, false)
+	if got := out.String(); !strings.Contains(got, "Title: api v1.2.0 → api 1.2.0") || !strings.Contains(got, "Updated release api-v1.2.0: https://github.com/o/r/releases/tag/api-v1.2.0") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	out.Reset()
+	result.Release.Title, result.Release.Notes = result.OldTitle, result.OldNotes
+	printRerelease(&out, result, false)
+	if got := out.String(); got != "Release api-v1.2.0 is up to date\n" {
+		t.Errorf("unexpected output for unchanged notes: %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/signing"
+)
+
+// testSigningKey returns an Ed25519 private key PEM and its public key PEM.
+func testSigningKey(t *testing.T) (string, []byte) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+}
+
+func TestNewReportSigner(t *testing.T) {
+	keyPEM, _ := testSigningKey(t)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyPath, []byte(keyPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, mode, keyPath, envKey string
+		wantSigner                  bool
+		wantErr                     string
+	}{
+		{name: "unsigned"},
+		{name: "keyless", mode: "keyless", wantSigner: true},
+		{name: "key file", mode: "key", keyPath: keyPath, wantSigner: true},
+		{name: "key from env", mode: "key", envKey: keyPEM, wantSigner: true},
+		{name: "no key", mode: "key", wantErr: "requires --signing-key"},
+		{name: "key without mode", keyPath: keyPath, wantErr: "requires --sign-report key"},
+		{name: "unknown mode", mode: "gpg", wantErr: "invalid --sign-report"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := newReportSigner(tc.mode, tc.keyPath, tc.envKey)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("expected %q error, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newReportSigner failed: %v", err)
+			}
+			if (signer != nil) != tc.wantSigner {
+				t.Errorf("expected signer %v, got %v", tc.wantSigner, signer)
+			}
+		})
+	}
+}
+
+func TestWriteReportSignature(t *testing.T) {
+	keyPEM, pubPEM := testSigningKey(t)
+	signer, err := signing.NewKeySigner([]byte(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportSigner = signer
+	t.Cleanup(func() { reportSigner = nil })
+
+	report := []byte(`{"components":["api"]}`)
+	var out bytes.Buffer
+	writeReportSignature(&out, report)
+
+	line, ok := strings.CutPrefix(strings.TrimSuffix(out.String(), "\n"), "release_report_signature=")
+	if !ok {
+		t.Fatalf("expected a release_report_signature output, got %q", out.String())
+	}
+	var sig signing.Signature
+	if err := json.Unmarshal([]byte(line), &sig); err != nil {
+		t.Fatalf("invalid signature JSON: %v", err)
+	}
+	if err := signing.Verify(trimNewline(append(report, '\n')), &sig, pubPEM); err != nil {
+		t.Errorf("expected the report to verify: %v", err)
+	}
+
+	reportSigner = nil
+	out.Reset()
+	writeReportSignature(&out, report)
+	if out.Len() != 0 {
+		t.Errorf("expected no output without --sign-report, got %q", out.String())
+	}
+}
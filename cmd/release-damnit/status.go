@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// runStatusCommand shows each component's latest release next to the
+// versions deployed to the config's environments.
+func runStatusCommand(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		exitWith(exitUsage, "%v", err)
+	}
+	if fs.NArg() > 0 {
+		exitWith(exitUsage, "Usage: release-damnit status")
+	}
+
+	repoPath, _, err := resolveRepo(".")
+	if err != nil {
+		fatal("Failed to find repository: %v", err)
+	}
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		exitWith(exitConfig, "%v", err)
+	}
+	statuses, err := release.Status(repoPath, cfg)
+	if err != nil {
+		exitWith(exitConfig, "%v", err)
+	}
+	printStatus(os.Stdout, cfg.Environments, statuses)
+}
+
+// printStatus prints a table of released and deployed versions, marking
+// deployments behind the latest release with "*", then lists what's
+// released but not deployed. Components deployed nowhere are left out.
+func printStatus(w io.Writer, envs []*config.Environment, statuses []*release.ComponentStatus) {
+	fmt.Fprintf(w, "%-20s %-12s", "Component", "Released")
+	for _, env := range envs {
+		fmt.Fprintf(w, " %-12s", env.Name)
+	}
+	fmt.Fprintln(w)
+
+	var behind, unreadable []string
+	for _, s := range statuses {
+		if !s.Deployed() {
+			continue
+		}
+		fmt.Fprintf(w, "%-20s %-12s", s.Package.Component, orDash(s.Released))
+		for _, d := range s.Deployments {
+			cell := orDash(d.Version)
+			switch {
+			case d.Err != nil:
+				cell = "?"
+				unreadable = append(unreadable, fmt.Sprintf("%s in %s: %v", s.Package.Component, d.Environment.Name, d.Err))
+			case d.Behind:
+				cell += "*"
+			}
+			fmt.Fprintf(w, " %-12s", cell)
+		}
+		fmt.Fprintln(w)
+
+		if undeployed := s.Undeployed(); len(undeployed) > 0 {
+			var where []string
+			for _, d := range undeployed {
+				where = append(where, fmt.Sprintf("%s (at %s)", d.Environment.Name, d.Version))
+			}
+			behind = append(behind, fmt.Sprintf("%s %s: %s", s.Package.Component, s.Released, strings.Join(where, ", ")))
+		}
+	}
+
+	if len(behind) > 0 {
+		fmt.Fprintln(w, "\nReleased but not deployed:")
+		for _, line := range behind {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	} else {
+		fmt.Fprintln(w, "\nEvery environment runs the latest release")
+	}
+	if len(unreadable) > 0 {
+		fmt.Fprintln(w, "\nCouldn't read:")
+		for _, line := range unreadable {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// orDash returns s, or "-" if it's empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestPrintStatus(t *testing.T) {
+	staging := &config.Environment{Name: "staging"}
+	prod := &config.Environment{Name: "prod"}
+	envs := []*config.Environment{staging, prod}
+	statuses := []*release.ComponentStatus{
+		{
+			Package:  &config.Package{Component: "api"},
+			Released: "1.2.0",
+			Deployments: []*release.Deployment{
+				{Environment: staging, Version: "1.2.0"},
+				{Environment: prod, Version: "1.1.0", Behind: true},
+			},
+		},
+		{
+			Package:  &config.Package{Component: "web"},
+			Released: "0.4.1",
+			Deployments: []*release.Deployment{
+				{Environment: staging, Version: "latest", Err: errors.New(`deployed version "latest" is not a version`)},
+				{Environment: prod},
+			},
+		},
+		{
+			Package:     &config.Package{Component: "shared"},
+			Released:    "2.0.0",
+			Deployments: []*release.Deployment{{Environment: staging}, {Environment: prod}},
+		},
+	}
+
+	var out bytes.Buffer
+	printStatus(&out, envs, statuses)
+	got := out.String()
+	for _, want := range []string{
+		"Component            Released     staging      prod        \n",
+		"api                  1.2.0        1.2.0        1.1.0*      \n",
+		"web                  0.4.1        ?            -           \n",
+		"Released but not deployed:\n  api 1.2.0: prod (at 1.1.0)\n",
+		"Couldn't read:\n  web in staging: deployed version \"latest\" is not a version\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "shared") {
+		t.Errorf("expected components deployed nowhere left out:\n%s", got)
+	}
+
+	out.Reset()
+	printStatus(&out, envs, statuses[2:])
+	if !strings.Contains(out.String(), "Every environment runs the latest release") {
+		t.Errorf("expected everything up to date, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// printTimings writes the --timings summary: each phase's duration, the
+// total, and how many commits and files were analyzed.
+func printTimings(w io.Writer, result *release.AnalysisResult) {
+	metrics := release.BuildMetrics(result)
+	if metrics == nil {
+		return
+	}
+
+	fmt.Fprintln(w, "\nTimings:")
+	for _, p := range result.Timings.Phases {
+		fmt.Fprintf(w, "  %-18s %10s\n", p.Name, formatDuration(p.Duration))
+	}
+	fmt.Fprintf(w, "  %-18s %10s\n", "total", formatDuration(result.Timings.Total()))
+	fmt.Fprintf(w, "  (%d commit(s), %d changed file(s))\n", metrics.Commits, metrics.Files)
+}
+
+// formatDuration rounds d for display: to 0.1ms under a second, to 10ms
+// above.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(100 * time.Microsecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestPrintTimings(t *testing.T) {
+	var buf bytes.Buffer
+	result := &release.AnalysisResult{
+		Commits: []*git.Commit{{SHA: "aaa1111", Files: []string{"a.go", "b.go"}}},
+	}
+	printTimings(&buf, result)
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing without timings, got %q", buf.String())
+	}
+
+	result.Timings = &release.Timings{}
+	result.Timings.Add(release.PhaseConfigLoad, 12345*time.Microsecond)
+	result.Timings.Add(release.PhaseFileListing, 2345*time.Millisecond)
+	printTimings(&buf, result)
+
+	want := `
+Timings:
+  config_load            12.3ms
+  file_listing            2.35s
+  total                   2.36s
+  (1 commit(s), 2 changed file(s))
+`
+	if buf.String() != want {
+		t.Errorf("printTimings() =\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
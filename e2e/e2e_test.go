@@ -1,17 +1,22 @@
-//go:build e2e
-// +build e2e
-
-// Package e2e contains end-to-end tests that run against the mock--gitops-playground
-// GitHub repository. These tests require network access and GitHub authentication.
+// Package e2e contains end-to-end tests of the mock--gitops-playground
+// repository scenarios.
+//
+// By default they run offline against a local fixture of the mock repo, with
+// a fake gh creating releases (see harness_test.go). With the e2e tag they run
+// against the GitHub repository, which requires network access and GitHub
+// authentication.
 //
 // The mock repo mirrors sh-monorepo structure with:
 // - Nested packages (jarvis, jarvis-web inside jarvis/clients/web)
 // - Linked versions (ma-observe-client and ma-observe-server)
 // - Multiple feature branches testing different scenarios
 //
-// Run with: go test -tags=e2e ./e2e/...
+// Run offline with: go test ./e2e/...
+// Run against GitHub with: go test -tags=e2e ./e2e/...
+//
+// Reset the mock repo with:
 //
-// Reset the mock repo with: ./scripts/setup-mock-repo.sh
+//	go run ./cmd/release-damnit devtool make-fixture --origin git@github.com:dsswift/mock--gitops-playground.git
 package e2e
 
 import (
@@ -30,13 +35,14 @@ import (
 const mockRepoURL = "git@github.com:dsswift/mock--gitops-playground.git"
 const mockRepoHTTPS = "https://github.com/dsswift/mock--gitops-playground"
 
-// cloneMockRepo clones the mock repo to a temp directory.
+// cloneMockRepo clones the mock repo (or its local fixture) to a temp directory.
 func cloneMockRepo(t *testing.T) string {
 	t.Helper()
 
+	source := mockRepo(t)
 	dir := t.TempDir()
 
-	cmd := exec.Command("git", "clone", mockRepoURL, dir)
+	cmd := exec.Command("git", "clone", source, dir)
 	if out, err := cmd.CombinedOutput(); err != nil {
 		t.Fatalf("failed to clone mock repo: %v\n%s", err, out)
 	}
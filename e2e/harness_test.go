@@ -0,0 +1,305 @@
+package e2e
+
+// The offline harness runs the e2e scenarios without network access. The
+// mock repo is built locally by the fixture package (which also resets the
+// GitHub repo, through devtool make-fixture), and a fake gh records releases
+// and tags them in the local origin, so the full release flow runs in CI.
+//
+// The fake gh is this test binary: TestMain links it into a bin directory as
+// "gh", puts that first on PATH, and when run as gh, it serves the command
+// instead of running tests.
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/fixture"
+)
+
+// fakeGHEnv names the directory the fake gh keeps releases in. When it's
+// set, the test binary acts as gh.
+const fakeGHEnv = "RELEASE_DAMNIT_FAKE_GH"
+
+var (
+	// harnessDir holds the fake gh, its releases, and the fixture repo.
+	harnessDir string
+
+	fixtureOnce   sync.Once
+	fixtureOrigin string
+	fixtureErr    error
+)
+
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(fakeGHEnv); dir != "" {
+		os.Exit(runFakeGH(dir, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	flag.Parse()
+	if live {
+		os.Exit(m.Run())
+	}
+	os.Exit(runOffline(m))
+}
+
+// runOffline installs the fake gh and runs the tests.
+func runOffline(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "release-damnit-e2e-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create harness dir: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	harnessDir = dir
+
+	if err := installFakeGH(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to install fake gh: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+// installFakeGH links the test binary into dir/bin as gh and puts it first
+// on PATH.
+func installFakeGH(dir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	bin := filepath.Join(dir, "bin")
+	releases := filepath.Join(dir, "releases")
+	for _, d := range []string{bin, releases} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.Symlink(exe, filepath.Join(bin, "gh")); err != nil {
+		return err
+	}
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	os.Setenv(fakeGHEnv, releases)
+	return nil
+}
+
+// mockRepo returns what cloneMockRepo clones: the GitHub mock repo when
+// live, otherwise the local fixture, built on first use.
+func mockRepo(t *testing.T) string {
+	t.Helper()
+	if live {
+		return mockRepoURL
+	}
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	fixtureOnce.Do(func() {
+		origin := filepath.Join(harnessDir, "mock-origin")
+		_, fixtureErr = fixture.Make(&fixture.Options{Dir: filepath.Join(harnessDir, "mock"), Origin: origin})
+		fixtureOrigin = origin
+	})
+	if fixtureErr != nil {
+		t.Fatalf("failed to build mock repo fixture: %v", fixtureErr)
+	}
+	return fixtureOrigin
+}
+
+// fakeRelease is a release as the fake gh stores it, in the shape of the
+// GitHub API's release object.
+type fakeRelease struct {
+	TagName         string `json:"tag_name"`
+	Name            string `json:"name"`
+	Body            string `json:"body"`
+	TargetCommitish string `json:"target_commitish"`
+	HTMLURL         string `json:"html_url"`
+	Draft           bool   `json:"draft"`
+}
+
+// runFakeGH serves the gh commands release-damnit and these tests use,
+// keeping releases as JSON files in dir. Returns the exit code.
+func runFakeGH(dir string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	err := fakeGH(dir, args, stdin, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "gh: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func fakeGH(dir string, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return fmt.Errorf("fake gh: unsupported command: %s", strings.Join(args, " "))
+	}
+	switch cmd := args[0] + " " + args[1]; {
+	case cmd == "auth status":
+		return nil
+
+	case cmd == "release create" && len(args) > 2:
+		flags := parseFlags(args[3:])
+		rel := &fakeRelease{
+			TagName:         args[2],
+			Name:            flags["--title"],
+			TargetCommitish: flags["--target"],
+			HTMLURL:         mockRepoHTTPS + "/releases/tag/" + args[2],
+		}
+		if flags["--notes-file"] == "-" {
+			notes, err := io.ReadAll(stdin)
+			if err != nil {
+				return err
+			}
+			rel.Body = string(notes)
+		}
+		if _, err := loadFakeRelease(dir, rel.TagName); err == nil {
+			return fmt.Errorf("release %s already exists (HTTP 422)", rel.TagName)
+		}
+		target := rel.TargetCommitish
+		if target == "" {
+			target = "HEAD"
+		}
+		// GitHub creates the tag when it creates the release
+		if out, err := exec.Command("git", "push", "origin", target+":refs/tags/"+rel.TagName).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create tag %s: %v\n%s", rel.TagName, err, out)
+		}
+		data, err := json.Marshal(rel)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, rel.HTMLURL)
+		return os.WriteFile(fakeReleasePath(dir, rel.TagName), data, 0644)
+
+	case cmd == "release view" && len(args) > 2:
+		rel, err := loadFakeRelease(dir, args[2])
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(stdout).Encode(map[string]string{
+			"tagName": rel.TagName,
+			"name":    rel.Name,
+			"body":    rel.Body,
+			"url":     rel.HTMLURL,
+		})
+
+	case cmd == "release delete" && len(args) > 2:
+		if err := os.Remove(fakeReleasePath(dir, args[2])); err != nil {
+			return fmt.Errorf("release not found")
+		}
+		if _, cleanup := parseFlags(args[3:])["--cleanup-tag"]; cleanup {
+			_ = exec.Command("git", "push", "origin", ":refs/tags/"+args[2]).Run()
+		}
+		return nil
+
+	case args[0] == "api" && strings.HasPrefix(args[1], "repos/{owner}/{repo}/releases/tags/"):
+		rel, err := loadFakeRelease(dir, strings.TrimPrefix(args[1], "repos/{owner}/{repo}/releases/tags/"))
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(stdout).Encode(rel)
+	}
+	return fmt.Errorf("fake gh: unsupported command: %s", strings.Join(args, " "))
+}
+
+// parseFlags maps each --flag to the argument after it, or to "" for
+// trailing and boolean flags.
+func parseFlags(args []string) map[string]string {
+	flags := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") {
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			flags[args[i]] = args[i+1]
+			i++
+		} else {
+			flags[args[i]] = ""
+		}
+	}
+	return flags
+}
+
+func fakeReleasePath(dir, tag string) string {
+	return filepath.Join(dir, url.PathEscape(tag)+".json")
+}
+
+func loadFakeRelease(dir, tag string) (*fakeRelease, error) {
+	data, err := os.ReadFile(fakeReleasePath(dir, tag))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("release not found (HTTP 404)")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rel fakeRelease
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// TestFakeGH_ReleaseLifecycle checks the fake gh against the commands the
+// release flow runs: create, look up through the API, view, and delete.
+func TestFakeGH_ReleaseLifecycle(t *testing.T) {
+	if live {
+		t.Skip("the fake gh is only used offline")
+	}
+	dir := cloneMockRepo(t)
+	head := strings.TrimSpace(runCmd(t, dir, "git", "rev-parse", "HEAD"))
+
+	gh := func(stdin string, args ...string) (string, error) {
+		cmd := exec.Command("gh", args...)
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader(stdin)
+		out, err := cmd.Output()
+		return string(out), err
+	}
+
+	if _, err := gh("", "auth", "status"); err != nil {
+		t.Fatalf("auth status failed: %v", err)
+	}
+	if _, err := gh("notes", "release", "create", "fake-v1.0.0", "--title", "fake v1.0.0", "--notes-file", "-", "--target", head); err != nil {
+		t.Fatalf("release create failed: %v", err)
+	}
+	t.Cleanup(func() { _, _ = gh("", "release", "delete", "fake-v1.0.0", "--yes", "--cleanup-tag") })
+
+	if _, err := gh("notes", "release", "create", "fake-v1.0.0", "--title", "again", "--notes-file", "-"); err == nil {
+		t.Error("expected creating a duplicate release to fail")
+	}
+	if tags := runCmd(t, dir, "git", "ls-remote", "--tags", "origin", "fake-v1.0.0"); !strings.HasPrefix(tags, head) {
+		t.Errorf("expected tag on origin at %s, got %q", head, tags)
+	}
+
+	out, err := gh("", "api", "repos/{owner}/{repo}/releases/tags/fake-v1.0.0")
+	if err != nil {
+		t.Fatalf("api lookup failed: %v", err)
+	}
+	var rel fakeRelease
+	if err := json.Unmarshal([]byte(out), &rel); err != nil {
+		t.Fatalf("failed to parse release: %v", err)
+	}
+	if rel.Name != "fake v1.0.0" || rel.Body != "notes" || rel.TargetCommitish != head || rel.Draft {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+
+	if out, err := gh("", "release", "view", "fake-v1.0.0", "--json", "tagName,name"); err != nil || !strings.Contains(out, `"tagName":"fake-v1.0.0"`) {
+		t.Errorf("release view failed: %v %s", err, out)
+	}
+
+	if _, err := gh("", "release", "delete", "fake-v1.0.0", "--yes", "--cleanup-tag"); err != nil {
+		t.Fatalf("release delete failed: %v", err)
+	}
+	if _, err := gh("", "release", "view", "fake-v1.0.0"); err == nil {
+		t.Error("expected deleted release to be gone")
+	}
+	if tags := runCmd(t, dir, "git", "ls-remote", "--tags", "origin", "fake-v1.0.0"); tags != "" {
+		t.Errorf("expected tag to be cleaned up, got %q", tags)
+	}
+	if _, err := gh("", "pr", "list"); err == nil {
+		t.Error("expected unsupported commands to fail")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+// live runs the tests against the mock GitHub repo with the real gh CLI.
+const live = true
@@ -0,0 +1,8 @@
+//go:build !e2e
+// +build !e2e
+
+package e2e
+
+// live is false without the e2e tag: the tests run against a local fixture
+// of the mock repo, with the fake gh creating releases (see harness_test.go).
+const live = false
@@ -0,0 +1,232 @@
+// Package audit keeps an append-only log of release decisions for release
+// traceability. Each run appends one JSON object to an NDJSON file: when it
+// ran, who ran it, which commits it analyzed, the inputs it was given, what
+// it decided for each package, and the tags it created.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Decisions made for a package.
+const (
+	DecisionRelease = "release"
+	DecisionDefer   = "defer"
+)
+
+// Run describes one release-damnit run.
+type Run struct {
+	// Result is the analysis, or nil if the run failed before it finished.
+	Result *release.AnalysisResult
+
+	// RepoURL is the repository URL release links point at.
+	RepoURL string
+
+	// Start is when the run started.
+	Start time.Time
+
+	// Actor is who ran it (see Actor).
+	Actor string
+
+	// Version is the release-damnit version.
+	Version string
+
+	// Inputs are the options the run was given, by flag name.
+	Inputs map[string]string
+
+	// Outcome summarizes how the run ended (e.g., "released", "frozen"), and
+	// ExitCode is the process exit code.
+	Outcome  string
+	ExitCode int
+
+	// Error is the error the run stopped with, if any.
+	Error string
+
+	// Tags are the release tags the run created.
+	Tags []string
+
+	// Approval is the approval issue the releases waited on, if any.
+	Approval *release.ApprovalRequest
+}
+
+// Entry is one line of the audit log.
+type Entry struct {
+	// Timestamp is when the run started (RFC 3339, UTC).
+	Timestamp string `json:"timestamp"`
+
+	Actor      string `json:"actor"`
+	Version    string `json:"version"`
+	Repository string `json:"repository,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+
+	// Range is the analyzed commit range. Absent if analysis failed.
+	Range *Range `json:"range,omitempty"`
+
+	Inputs    map[string]string `json:"inputs"`
+	Decisions []Decision        `json:"decisions"`
+	Tags      []string          `json:"tags"`
+
+	// Freeze names the freeze window in effect, if any.
+	Freeze string `json:"freeze,omitempty"`
+
+	// Approval is the approval issue URL and who approved, if any.
+	Approval *Approval `json:"approval,omitempty"`
+
+	Outcome  string `json:"outcome"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Range is an analyzed commit range: the commits after Base up to Head.
+type Range struct {
+	// Base is empty for a root commit and when Accumulated.
+	Base string `json:"base,omitempty"`
+	Head string `json:"head"`
+
+	// Accumulated is true if each package's commits since its last release
+	// tag were analyzed instead (--accumulate).
+	Accumulated bool `json:"accumulated"`
+
+	// Commits is the number of commits analyzed.
+	Commits int `json:"commits"`
+}
+
+// Decision is what the run decided for one package.
+type Decision struct {
+	Component string `json:"component"`
+
+	// Decision is DecisionRelease or DecisionDefer.
+	Decision string `json:"decision"`
+
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	BumpType   string `json:"bump_type,omitempty"`
+	TagName    string `json:"tag_name,omitempty"`
+
+	// Commits are the SHAs of the commits behind a release.
+	Commits []string `json:"commits,omitempty"`
+
+	// Reason explains a deferred release.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Approval records a release approval issue.
+type Approval struct {
+	URL        string `json:"url"`
+	ApprovedBy string `json:"approved_by,omitempty"`
+}
+
+// NewEntry builds the audit log entry for a run.
+func NewEntry(run *Run) *Entry {
+	contracts.RequireNotNil(run, "run")
+
+	entry := &Entry{
+		Timestamp: run.Start.UTC().Format(time.RFC3339),
+		Actor:     run.Actor,
+		Version:   run.Version,
+		Inputs:    run.Inputs,
+		Decisions: []Decision{},
+		Tags:      run.Tags,
+		Outcome:   run.Outcome,
+		ExitCode:  run.ExitCode,
+		Error:     run.Error,
+	}
+	if entry.Inputs == nil {
+		entry.Inputs = map[string]string{}
+	}
+	if entry.Tags == nil {
+		entry.Tags = []string{}
+	}
+	if run.Approval != nil {
+		entry.Approval = &Approval{URL: run.Approval.URL, ApprovedBy: run.Approval.ApprovedBy}
+	}
+
+	result := run.Result
+	if result == nil {
+		return entry
+	}
+	entry.Repository = result.RepoURL
+	entry.Branch = result.Branch
+	entry.Range = &Range{
+		Base:        result.Base,
+		Accumulated: result.Accumulated,
+		Commits:     len(result.Commits),
+	}
+	if result.MergeInfo != nil {
+		entry.Range.Head = result.MergeInfo.HeadSHA
+	}
+	if result.Freeze != nil {
+		entry.Freeze = result.Freeze.Name
+	}
+
+	for _, rel := range release.BuildReleaseReport(result, run.RepoURL).Releases {
+		d := Decision{
+			Component:  rel.Component,
+			Decision:   DecisionRelease,
+			OldVersion: rel.OldVersion,
+			NewVersion: rel.NewVersion,
+			BumpType:   rel.BumpType,
+			TagName:    rel.TagName,
+		}
+		for _, c := range rel.Commits {
+			d.Commits = append(d.Commits, c.SHA)
+		}
+		entry.Decisions = append(entry.Decisions, d)
+	}
+	if result.Stats != nil {
+		for _, d := range result.Stats.Deferred {
+			entry.Decisions = append(entry.Decisions, Decision{
+				Component: d.Package.Component,
+				Decision:  DecisionDefer,
+				Reason:    fmt.Sprintf("min-commits: %d of %d releasable commit(s) pending", d.Pending, d.Package.MinCommits),
+			})
+		}
+	}
+	return entry
+}
+
+// Append adds entry to the audit log at path as one line, creating the file
+// and its directory if needed. Existing lines are never rewritten.
+func Append(path string, entry *Entry) error {
+	contracts.RequireNotEmpty(path, "path")
+	contracts.RequireNotNil(entry, "entry")
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	// One write per entry, so concurrent runs don't interleave lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	return nil
+}
+
+// Actor returns who is running release-damnit: the GitHub Actions actor, or
+// the local user.
+func Actor() string {
+	for _, name := range []string{"GITHUB_ACTOR", "USER", "USERNAME"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return "unknown"
+}
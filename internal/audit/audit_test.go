@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// testRun returns a run that released one package and deferred another.
+func testRun() *Run {
+	api := &config.Package{Path: "services/api", Component: "api"}
+	web := &config.Package{Path: "apps/web", Component: "web", MinCommits: 3}
+	return &Run{
+		Result: &release.AnalysisResult{
+			MergeInfo: &git.MergeInfo{IsMerge: true, HeadSHA: "cccc"},
+			Base:      "aaaa",
+			Commits:   []*git.Commit{{SHA: "bbbb"}, {SHA: "dddd"}},
+			Releases: []*release.PackageRelease{{
+				Package:    api,
+				BumpType:   version.Minor,
+				OldVersion: "1.0.0",
+				NewVersion: "1.1.0",
+				Commits:    []*git.Commit{{SHA: "bbbb", Type: "feat", Description: "add thing"}},
+			}},
+			Config:  &config.Config{},
+			RepoURL: "https://github.com/o/r",
+			Branch:  "main",
+			Stats: &release.AnalysisStats{
+				Deferred: []*release.DeferredRelease{{Package: web, Pending: 1}},
+			},
+			Freeze: &config.Freeze{Name: "year-end"},
+		},
+		Start:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)),
+		Actor:    "octocat",
+		Version:  "1.2.3",
+		Inputs:   map[string]string{"create-releases": "true"},
+		Outcome:  "released",
+		Tags:     []string{"api-v1.1.0"},
+		Approval: &release.ApprovalRequest{URL: "https://github.com/o/r/issues/7", Approved: true, ApprovedBy: "alice"},
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	entry := NewEntry(testRun())
+
+	if entry.Timestamp != "2024-03-01T11:00:00Z" {
+		t.Errorf("expected a UTC timestamp, got %s", entry.Timestamp)
+	}
+	if entry.Repository != "https://github.com/o/r" || entry.Branch != "main" || entry.Freeze != "year-end" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if r := entry.Range; r == nil || r.Base != "aaaa" || r.Head != "cccc" || r.Commits != 2 || r.Accumulated {
+		t.Errorf("unexpected range: %+v", r)
+	}
+	if len(entry.Decisions) != 2 {
+		t.Fatalf("expected two decisions, got %+v", entry.Decisions)
+	}
+	rel, def := entry.Decisions[0], entry.Decisions[1]
+	if rel.Decision != DecisionRelease || rel.TagName != "api-v1.1.0" || rel.BumpType != "minor" || len(rel.Commits) != 1 || rel.Commits[0] != "bbbb" {
+		t.Errorf("unexpected release decision: %+v", rel)
+	}
+	if def.Decision != DecisionDefer || def.Component != "web" || def.Reason != "min-commits: 1 of 3 releasable commit(s) pending" {
+		t.Errorf("unexpected deferred decision: %+v", def)
+	}
+	if entry.Approval == nil || entry.Approval.ApprovedBy != "alice" {
+		t.Errorf("expected the approval recorded, got %+v", entry.Approval)
+	}
+}
+
+func TestNewEntry_FailedAnalysis(t *testing.T) {
+	entry := NewEntry(&Run{Start: time.Now(), Outcome: "failed", ExitCode: 4, Error: "invalid config"})
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["range"]; ok {
+		t.Errorf("expected no range without an analysis, got %s", data)
+	}
+	for _, key := range []string{"inputs", "decisions", "tags"} {
+		if fields[key] == nil {
+			t.Errorf("expected %s to be empty, not null: %s", key, data)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "release-audit.ndjson")
+
+	first := NewEntry(testRun())
+	second := NewEntry(&Run{Start: time.Now(), Outcome: "no-releases", ExitCode: 3})
+	for _, entry := range []*Entry{first, second} {
+		if err := Append(path, entry); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var outcomes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		outcomes = append(outcomes, entry.Outcome)
+	}
+	if len(outcomes) != 2 || outcomes[0] != "released" || outcomes[1] != "no-releases" {
+		t.Errorf("expected both entries in order, got %v", outcomes)
+	}
+}
+
+func TestActor(t *testing.T) {
+	t.Setenv("GITHUB_ACTOR", "octocat")
+	t.Setenv("USER", "runner")
+	if got := Actor(); got != "octocat" {
+		t.Errorf("expected the Actions actor, got %q", got)
+	}
+	t.Setenv("GITHUB_ACTOR", "")
+	if got := Actor(); got != "runner" {
+		t.Errorf("expected the local user, got %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// Bullet is one changelog bullet: a commit, and the later commits in the
+// same range with the same type, scope, and description, such as cherry
+// picks or a change merged twice, which share its line.
+type Bullet struct {
+	Commit     *git.Commit
+	Duplicates []*git.Commit
+}
+
+// Commits returns the bullet's commits, first one first.
+func (b *Bullet) Commits() []*git.Commit {
+	return append([]*git.Commit{b.Commit}, b.Duplicates...)
+}
+
+// Notes returns the detail lines of the bullet's commits, without repeats.
+func (b *Bullet) Notes() []string {
+	var notes []string
+	for _, c := range b.Commits() {
+		for _, note := range c.Notes {
+			if !slices.Contains(notes, note) {
+				notes = append(notes, note)
+			}
+		}
+	}
+	return notes
+}
+
+// Bullets collapses commits with the same type, scope, and description
+// into one bullet each, in order of first appearance.
+func Bullets(commits []*git.Commit) []*Bullet {
+	type key struct{ typ, scope, description string }
+	var bullets []*Bullet
+	seen := make(map[key]*Bullet)
+	for _, c := range commits {
+		k := key{c.Type, c.Scope, c.Description}
+		if b, ok := seen[k]; ok {
+			b.Duplicates = append(b.Duplicates, c)
+			continue
+		}
+		b := &Bullet{Commit: c}
+		seen[k] = b
+		bullets = append(bullets, b)
+	}
+	return bullets
+}
+
+// BulletGroup is a changelog section and its bullets.
+type BulletGroup struct {
+	Section string
+	Bullets []*Bullet
+}
+
+// Layout returns the bullets of each section (see GroupBySection and
+// Bullets), sorted per sortOrder (see config.Package.ChangelogSort) and
+// capped at maxBullets in all, or uncapped if it's 0. It also returns how
+// many bullets the cap left out; sections left empty are dropped.
+func Layout(commits []*git.Commit, sections []config.ChangelogSection, sortOrder string, maxBullets int) ([]*BulletGroup, int) {
+	var groups []*BulletGroup
+	listed, omitted := 0, 0
+	for _, section := range GroupBySection(commits, sections) {
+		bullets := Bullets(section.Commits)
+		if sortOrder == config.ChangelogSortScope {
+			sortByScope(bullets)
+		}
+		if maxBullets > 0 && listed+len(bullets) > maxBullets {
+			omitted += listed + len(bullets) - maxBullets
+			bullets = bullets[:maxBullets-listed]
+		}
+		listed += len(bullets)
+		if len(bullets) > 0 {
+			groups = append(groups, &BulletGroup{Section: section.Section, Bullets: bullets})
+		}
+	}
+	return groups, omitted
+}
+
+// sortByScope sorts bullets by scope, unscoped last, then by description,
+// ignoring case.
+func sortByScope(bullets []*Bullet) {
+	sort.SliceStable(bullets, func(i, j int) bool {
+		a, b := bullets[i].Commit, bullets[j].Commit
+		if a.Scope != b.Scope {
+			if a.Scope == "" || b.Scope == "" {
+				return b.Scope == ""
+			}
+			return strings.ToLower(a.Scope) < strings.ToLower(b.Scope)
+		}
+		return strings.ToLower(a.Description) < strings.ToLower(b.Description)
+	})
+}
+
+// MoreChanges is the line ending a capped entry: how many bullets were
+// left out, linking the compare view when there's one.
+func MoreChanges(omitted int, compareURL string) string {
+	noun := "changes"
+	if omitted == 1 {
+		noun = "change"
+	}
+	if compareURL == "" {
+		return fmt.Sprintf("…and %d more %s\n", omitted, noun)
+	}
+	return fmt.Sprintf("…and %d more %s: [full changelog](%s)\n", omitted, noun, compareURL)
+}
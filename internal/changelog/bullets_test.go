@@ -0,0 +1,108 @@
+package changelog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestBullets(t *testing.T) {
+	commits := []*git.Commit{
+		{SHA: "aaa1111111111", Type: "fix", Scope: "api", Description: "fix crash", Notes: []string{"Null check"}},
+		{SHA: "bbb2222222222", Type: "fix", Description: "fix crash"},
+		{SHA: "ccc3333333333", Type: "fix", Scope: "api", Description: "fix crash", Notes: []string{"Null check", "Retry"}},
+		{SHA: "ddd4444444444", Type: "feat", Scope: "api", Description: "fix crash"},
+	}
+
+	bullets := Bullets(commits)
+	if len(bullets) != 3 {
+		t.Fatalf("expected 3 bullets, got %d", len(bullets))
+	}
+	if b := bullets[0]; b.Commit != commits[0] || !slices.Equal(b.Commits(), []*git.Commit{commits[0], commits[2]}) {
+		t.Errorf("expected the api fixes collapsed, got %+v", b)
+	}
+	if notes := bullets[0].Notes(); !slices.Equal(notes, []string{"Null check", "Retry"}) {
+		t.Errorf("expected notes without repeats, got %q", notes)
+	}
+	if bullets[1].Commit != commits[1] || bullets[2].Commit != commits[3] {
+		t.Error("expected other scopes and types kept apart, in order")
+	}
+}
+
+func TestGenerate_DuplicateCommits(t *testing.T) {
+	entry := &Entry{
+		Version: "1.0.1",
+		Date:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		RepoURL: "https://github.com/owner/repo",
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "fix", Scope: "auth", Description: "fix login bug"},
+			{SHA: "def5678901234", ShortSHA: "def5678", Type: "fix", Scope: "auth", Description: "fix login bug"},
+		},
+	}
+
+	result := Generate(entry)
+	want := "* **auth:** fix login bug ([abc1234](https://github.com/owner/repo/commit/abc1234567890), [def5678](https://github.com/owner/repo/commit/def5678901234))\n\n"
+	if !strings.Contains(result, "### Bug Fixes\n\n"+want) {
+		t.Errorf("expected one bullet with both commits, got:\n%s", result)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	commits := []*git.Commit{
+		{SHA: "1", Type: "fix", Description: "zap warning"},
+		{SHA: "2", Type: "feat", Scope: "web", Description: "add dark mode"},
+		{SHA: "3", Type: "fix", Scope: "api", Description: "fix timeout"},
+		{SHA: "4", Type: "fix", Scope: "api", Description: "Fix crash"},
+		{SHA: "5", Type: "fix", Scope: "Auth", Description: "fix login"},
+	}
+	describe := func(groups []*BulletGroup) string {
+		var parts []string
+		for _, g := range groups {
+			for _, b := range g.Bullets {
+				parts = append(parts, g.Section+":"+b.Commit.SHA)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+
+	groups, omitted := Layout(commits, nil, "", 0)
+	if got := describe(groups); got != "Features:2 Bug Fixes:1 Bug Fixes:3 Bug Fixes:4 Bug Fixes:5" || omitted != 0 {
+		t.Errorf("expected commit order, got %q (%d omitted)", got, omitted)
+	}
+
+	groups, _ = Layout(commits, nil, config.ChangelogSortScope, 0)
+	if got := describe(groups); got != "Features:2 Bug Fixes:4 Bug Fixes:3 Bug Fixes:5 Bug Fixes:1" {
+		t.Errorf("expected bullets sorted by scope, then description, got %q", got)
+	}
+
+	groups, omitted = Layout(commits, nil, config.ChangelogSortScope, 3)
+	if got := describe(groups); got != "Features:2 Bug Fixes:4 Bug Fixes:3" || omitted != 2 {
+		t.Errorf("expected 3 bullets and 2 omitted, got %q (%d omitted)", got, omitted)
+	}
+	if groups, omitted = Layout(commits, nil, "", 1); len(groups) != 1 || omitted != 4 {
+		t.Errorf("expected the bug fixes section dropped, got %d groups (%d omitted)", len(groups), omitted)
+	}
+}
+
+func TestGenerate_MaxBullets(t *testing.T) {
+	entry := &Entry{
+		Version:    "1.1.0",
+		Date:       time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		CompareURL: "https://github.com/owner/repo/compare/api-v1.0.0...api-v1.1.0",
+		MaxBullets: 1,
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "add export"},
+			{SHA: "def5678901234", ShortSHA: "def5678", Type: "fix", Description: "fix crash"},
+		},
+	}
+
+	result := Generate(entry)
+	want := "### Features\n\n* add export (abc1234)\n\n…and 1 more change: [full changelog](https://github.com/owner/repo/compare/api-v1.0.0...api-v1.1.0)\n\n"
+	if !strings.HasSuffix(result, want) {
+		t.Errorf("expected a capped entry, got:\n%s", result)
+	}
+}
@@ -4,10 +4,13 @@ package changelog
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/dsswift/release-damnit/internal/config"
 	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/jira"
 	"github.com/dsswift/release-damnit/pkg/contracts"
 )
 
@@ -20,14 +23,38 @@ type Entry struct {
 	Component   string
 	RepoURL     string
 	PrevVersion string
+
+	// Sections are the sections commits are listed under, by type. Nil
+	// means config.DefaultChangelogSections.
+	Sections []config.ChangelogSection
+
+	// FirstRelease marks the package's first release, which is noted under
+	// the header.
+	FirstRelease bool
+
+	// Sort and MaxBullets order and cap the bullets (see Layout).
+	Sort       string
+	MaxBullets int
+
+	// Note is written instead of commit sections for an entry without
+	// commits, such as a linked-versions bump.
+	Note string
+
+	// JiraBaseURL, when set, turns Jira keys in descriptions into issue links.
+	JiraBaseURL  string
+	JiraProjects []string // Limit linked keys to these project prefixes
 }
 
-// Generate creates a changelog entry string from the given commits.
-// Format matches Release Please's conventional-changelog output.
+// InitialReleaseNote opens the entry of a package's first release.
+const InitialReleaseNote = "Initial release."
+
+// Generate creates a changelog entry string from the given commits, or from
+// its Note if it has none. Format matches Release Please's
+// conventional-changelog output.
 func Generate(entry *Entry) string {
 	contracts.RequireNotNil(entry, "entry")
 	contracts.RequireNotEmpty(entry.Version, "version")
-	contracts.Require(len(entry.Commits) > 0, "commits cannot be empty")
+	contracts.Require(len(entry.Commits) > 0 || entry.Note != "", "commits cannot be empty without a note")
 
 	var sb strings.Builder
 
@@ -40,54 +67,71 @@ func Generate(entry *Entry) string {
 		sb.WriteString(fmt.Sprintf("## [%s] (%s)\n\n", entry.Version, dateStr))
 	}
 
-	// Group commits by type
-	features := filterCommitsByType(entry.Commits, "feat")
-	fixes := filterCommitsByType(entry.Commits, "fix")
-	perfs := filterCommitsByType(entry.Commits, "perf")
+	if entry.FirstRelease {
+		sb.WriteString(InitialReleaseNote + "\n\n")
+	}
+
+	if len(entry.Commits) == 0 {
+		sb.WriteString(entry.Note + "\n\n")
+		return sb.String()
+	}
+
 	breaking := filterBreakingChanges(entry.Commits)
 
 	// Breaking changes section (if any)
 	if len(breaking) > 0 {
 		sb.WriteString("### ⚠ BREAKING CHANGES\n\n")
-		for _, c := range breaking {
-			sb.WriteString(formatCommitLine(c, entry.RepoURL))
-		}
-		sb.WriteString("\n")
-	}
-
-	// Features section
-	if len(features) > 0 {
-		sb.WriteString("### Features\n\n")
-		for _, c := range features {
-			sb.WriteString(formatCommitLine(c, entry.RepoURL))
+		for _, b := range Bullets(breaking) {
+			sb.WriteString(formatBreakingLine(b, entry))
 		}
 		sb.WriteString("\n")
 	}
 
-	// Bug Fixes section
-	if len(fixes) > 0 {
-		sb.WriteString("### Bug Fixes\n\n")
-		for _, c := range fixes {
-			sb.WriteString(formatCommitLine(c, entry.RepoURL))
+	// A section per listed commit type: Features, Bug Fixes, ...
+	groups, omitted := Layout(entry.Commits, entry.Sections, entry.Sort, entry.MaxBullets)
+	for _, group := range groups {
+		sb.WriteString("### " + group.Section + "\n\n")
+		for _, b := range group.Bullets {
+			sb.WriteString(formatCommitLine(b, entry))
 		}
 		sb.WriteString("\n")
 	}
-
-	// Performance Improvements section
-	if len(perfs) > 0 {
-		sb.WriteString("### Performance Improvements\n\n")
-		for _, c := range perfs {
-			sb.WriteString(formatCommitLine(c, entry.RepoURL))
-		}
-		sb.WriteString("\n")
+	if omitted > 0 {
+		sb.WriteString(MoreChanges(omitted, entry.CompareURL) + "\n")
 	}
 
 	return sb.String()
 }
 
-// Prepend adds a new entry to the top of an existing changelog.
-// It preserves any content after the first "## " header.
+// InsertMarker marks where Prepend inserts new entries. It's for changelogs
+// whose intro or headers the version header search gets wrong.
+const InsertMarker = "<!-- release-damnit-insert -->"
+
+// versionHeaderRegex matches the header of a version entry, such as
+// "## [1.2.0](https://...) (2024-01-15)", "## 1.2.0", or "## v1.2.0".
+var versionHeaderRegex = regexp.MustCompile(`^##\s+\[?v?\d+\.\d+`)
+
+// Prepend adds a new entry to the top of an existing changelog: right after
+// an InsertMarker line if there is one, otherwise before the first version
+// header. Without either, the entry is appended. A changelog with CRLF line
+// endings keeps them, new entry included.
 func Prepend(existingChangelog, newEntry string) string {
+	if strings.Contains(existingChangelog, "\r\n") {
+		lf := strings.ReplaceAll(existingChangelog, "\r\n", "\n")
+		return strings.ReplaceAll(Prepend(lf, strings.ReplaceAll(newEntry, "\r\n", "\n")), "\n", "\r\n")
+	}
+	if i := strings.Index(existingChangelog, InsertMarker); i >= 0 {
+		end := i + len(InsertMarker)
+		if nl := strings.IndexByte(existingChangelog[end:], '\n'); nl >= 0 {
+			end += nl + 1
+		} else {
+			existingChangelog += "\n"
+			end = len(existingChangelog)
+		}
+		rest := strings.TrimLeft(existingChangelog[end:], "\n")
+		return existingChangelog[:end] + "\n" + newEntry + rest
+	}
+
 	// Find where to insert (after the title, before first version entry)
 	lines := strings.Split(existingChangelog, "\n")
 	var headerLines []string
@@ -95,8 +139,7 @@ func Prepend(existingChangelog, newEntry string) string {
 	foundHeader := false
 
 	for i, line := range lines {
-		// Look for first version header (## [x.x.x] or ## x.x.x)
-		if strings.HasPrefix(line, "## ") && (strings.Contains(line, "[") || strings.Contains(line, "(")) {
+		if versionHeaderRegex.MatchString(line) {
 			foundHeader = true
 			restLines = lines[i:]
 			break
@@ -124,7 +167,54 @@ func Prepend(existingChangelog, newEntry string) string {
 	return result
 }
 
-// BuildCompareURL creates a GitHub compare URL between two versions.
+// ParsedEntry is a version entry read back from a changelog.
+type ParsedEntry struct {
+	// Version is the version in the entry's header.
+	Version string
+
+	// Date is the header's date (YYYY-MM-DD), or "" if it has none.
+	Date string
+
+	// Markdown is the entry, header included.
+	Markdown string
+}
+
+var (
+	headerVersionRegex = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.-]+)?`)
+	headerDateRegex    = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+// ParseEntries splits a changelog into its version entries, in file order.
+// Anything before the first version header (the title and intro) is left out.
+func ParseEntries(content string) []*ParsedEntry {
+	var entries []*ParsedEntry
+	var lines []string
+	flush := func() {
+		if len(entries) == 0 {
+			return
+		}
+		entries[len(entries)-1].Markdown = strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if versionHeaderRegex.MatchString(line) {
+			flush()
+			entries = append(entries, &ParsedEntry{
+				Version: headerVersionRegex.FindString(line),
+				Date:    headerDateRegex.FindString(line),
+			})
+			lines = nil
+		}
+		if len(entries) > 0 && !strings.Contains(line, InsertMarker) {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+// BuildCompareURL creates a compare URL between two versions, laid out for
+// the repository's hosting platform.
 func BuildCompareURL(repoURL, component, prevVersion, newVersion string) string {
 	if repoURL == "" || prevVersion == "" {
 		return ""
@@ -137,18 +227,50 @@ func BuildCompareURL(repoURL, component, prevVersion, newVersion string) string
 	prevTag := fmt.Sprintf("%s-v%s", component, prevVersion)
 	newTag := fmt.Sprintf("%s-v%s", component, newVersion)
 
-	return fmt.Sprintf("%s/compare/%s...%s", repoURL, prevTag, newTag)
+	return URLBuilderFor(repoURL).CompareURL(repoURL, prevTag, newTag)
 }
 
-// filterCommitsByType returns commits matching the given type.
-func filterCommitsByType(commits []*git.Commit, commitType string) []*git.Commit {
-	var result []*git.Commit
+// SectionGroup is a changelog section and the commits listed under it.
+type SectionGroup struct {
+	Section string
+	Commits []*git.Commit
+}
+
+// GroupBySection groups commits under their type's section, in section
+// order. Types with the same heading share a group, and commits of hidden
+// or unlisted types are left out. Nil sections means
+// config.DefaultChangelogSections.
+func GroupBySection(commits []*git.Commit, sections []config.ChangelogSection) []*SectionGroup {
+	if sections == nil {
+		sections = config.DefaultChangelogSections
+	}
+	var groups []*SectionGroup
+	bySection := make(map[string]*SectionGroup)
+	byType := make(map[string]*SectionGroup)
+	for _, s := range sections {
+		if s.Hidden {
+			continue
+		}
+		group, ok := bySection[s.Section]
+		if !ok {
+			group = &SectionGroup{Section: s.Section}
+			bySection[s.Section] = group
+			groups = append(groups, group)
+		}
+		byType[s.Type] = group
+	}
 	for _, c := range commits {
-		if c.Type == commitType {
-			result = append(result, c)
+		if group := byType[c.Type]; group != nil {
+			group.Commits = append(group.Commits, c)
 		}
 	}
-	return result
+	kept := groups[:0]
+	for _, group := range groups {
+		if len(group.Commits) > 0 {
+			kept = append(kept, group)
+		}
+	}
+	return kept
 }
 
 // filterBreakingChanges returns commits that are breaking changes.
@@ -162,19 +284,41 @@ func filterBreakingChanges(commits []*git.Commit) []*git.Commit {
 	return result
 }
 
-// formatCommitLine formats a single commit as a changelog bullet point.
-func formatCommitLine(commit *git.Commit, repoURL string) string {
-	desc := commit.Description
+// formatCommitLine formats a bullet as a changelog bullet point, linking
+// each of its commits.
+func formatCommitLine(b *Bullet, entry *Entry) string {
+	commit := b.Commit
+	desc := jira.Linkify(commit.Description, entry.JiraBaseURL, entry.JiraProjects)
 	if commit.Scope != "" {
 		desc = fmt.Sprintf("**%s:** %s", commit.Scope, desc)
 	}
 
-	if repoURL != "" {
-		commitURL := fmt.Sprintf("%s/commit/%s", strings.TrimSuffix(repoURL, "/"), commit.SHA)
-		return fmt.Sprintf("* %s ([%s](%s))\n", desc, commit.ShortSHA, commitURL)
+	var links []string
+	for _, c := range b.Commits() {
+		if commitURL := BuildCommitURL(entry.RepoURL, c.SHA); commitURL != "" {
+			links = append(links, fmt.Sprintf("[%s](%s)", c.ShortSHA, commitURL))
+		} else {
+			links = append(links, c.ShortSHA)
+		}
+	}
+	line := fmt.Sprintf("* %s (%s)\n", desc, strings.Join(links, ", "))
+
+	// Detail lines from the commit body go underneath as sub-bullets
+	for _, note := range b.Notes() {
+		line += fmt.Sprintf("  * %s\n", jira.Linkify(note, entry.JiraBaseURL, entry.JiraProjects))
 	}
+	return line
+}
 
-	return fmt.Sprintf("* %s (%s)\n", desc, commit.ShortSHA)
+// formatBreakingLine formats a breaking commit for the BREAKING CHANGES
+// section: its bullet, then its breaking description as an indented
+// paragraph.
+func formatBreakingLine(b *Bullet, entry *Entry) string {
+	line := formatCommitLine(b, entry)
+	if desc := b.Commit.BreakingDescription; desc != "" {
+		line += fmt.Sprintf("\n  %s\n", jira.Linkify(desc, entry.JiraBaseURL, entry.JiraProjects))
+	}
+	return line
 }
 
 // InitialChangelog returns the template for a new CHANGELOG.md file.
@@ -185,3 +329,34 @@ All notable changes to this project will be documented in this file.
 
 `
 }
+
+// InitialIndex returns the template for a new changelog index, which lists
+// the per-version changelog files newest first.
+func InitialIndex() string {
+	return "# Changelog\n\n" + InsertMarker + "\n"
+}
+
+// IndexEntry returns the index line linking to a version's changelog file.
+func IndexEntry(version, file string, date time.Time) string {
+	return fmt.Sprintf("* [%s](%s) (%s)\n", version, file, date.Format("2006-01-02"))
+}
+
+// PrependIndex adds an IndexEntry line to the top of an index: after its
+// InsertMarker if it has one, otherwise before the first list item.
+func PrependIndex(existingIndex, line string) string {
+	if strings.Contains(existingIndex, InsertMarker) {
+		return Prepend(existingIndex, line)
+	}
+
+	offset := 0
+	for _, l := range strings.SplitAfter(existingIndex, "\n") {
+		if strings.HasPrefix(l, "* ") || strings.HasPrefix(l, "- ") {
+			return existingIndex[:offset] + line + existingIndex[offset:]
+		}
+		offset += len(l)
+	}
+	if existingIndex != "" && !strings.HasSuffix(existingIndex, "\n") {
+		existingIndex += "\n"
+	}
+	return existingIndex + line
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dsswift/release-damnit/internal/config"
 	"github.com/dsswift/release-damnit/internal/git"
 )
 
@@ -49,6 +50,27 @@ func TestGenerate_BasicEntry(t *testing.T) {
 	}
 }
 
+func TestGenerate_FirstRelease(t *testing.T) {
+	entry := &Entry{
+		Version:      "1.0.0",
+		Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		FirstRelease: true,
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "add new feature"},
+		},
+	}
+
+	result := Generate(entry)
+	if !strings.Contains(result, "(2024-01-15)\n\nInitial release.\n\n### Features") {
+		t.Errorf("expected the initial release note under the header, got:\n%s", result)
+	}
+
+	entry.FirstRelease = false
+	if strings.Contains(Generate(entry), InitialReleaseNote) {
+		t.Error("expected no initial release note on a later release")
+	}
+}
+
 func TestGenerate_BreakingChanges(t *testing.T) {
 	entry := &Entry{
 		Version: "2.0.0",
@@ -65,6 +87,30 @@ func TestGenerate_BreakingChanges(t *testing.T) {
 	}
 }
 
+func TestGenerate_BreakingDescription(t *testing.T) {
+	entry := &Entry{
+		Version: "2.0.0",
+		Date:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Scope: "api", Description: "v2 endpoints", IsBreaking: true, BreakingDescription: "The /v1 endpoints are removed."},
+			{SHA: "def4567890123", ShortSHA: "def4567", Type: "fix", Description: "drop flag", IsBreaking: true},
+		},
+	}
+
+	result := Generate(entry)
+
+	want := "### ⚠ BREAKING CHANGES\n\n" +
+		"* **api:** v2 endpoints (abc1234)\n\n  The /v1 endpoints are removed.\n" +
+		"* drop flag (def4567)\n\n" +
+		"### Features\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected breaking description as a paragraph, got:\n%s", result)
+	}
+	if strings.Count(result, "The /v1 endpoints are removed.") != 1 {
+		t.Errorf("expected the description only in BREAKING CHANGES, got:\n%s", result)
+	}
+}
+
 func TestGenerate_WithCompareURL(t *testing.T) {
 	entry := &Entry{
 		Version:    "1.2.0",
@@ -98,6 +144,99 @@ func TestGenerate_PerformanceSection(t *testing.T) {
 	}
 }
 
+func TestGenerate_Note(t *testing.T) {
+	entry := &Entry{
+		Version: "1.2.0",
+		Date:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Note:    "Version bump to stay in sync with services; see api 1.2.0.",
+	}
+
+	want := "## [1.2.0] (2024-01-15)\n\nVersion bump to stay in sync with services; see api 1.2.0.\n\n"
+	if got := Generate(entry); got != want {
+		t.Errorf("Generate() = %q, want %q", got, want)
+	}
+
+	// Commits win over the note
+	entry.Commits = []*git.Commit{{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "fix", Description: "fix bug"}}
+	if got := Generate(entry); strings.Contains(got, "stay in sync") || !strings.Contains(got, "### Bug Fixes") {
+		t.Errorf("expected the commits rendered without the note, got:\n%s", got)
+	}
+}
+
+func TestGenerate_JiraLinks(t *testing.T) {
+	entry := &Entry{
+		Version:      "1.0.1",
+		Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		JiraBaseURL:  "https://acme.atlassian.net",
+		JiraProjects: []string{"PROJ"},
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "fix", Description: "handle UTF-8 names PROJ-42"},
+		},
+	}
+
+	result := Generate(entry)
+
+	if !strings.Contains(result, "* handle UTF-8 names [PROJ-42](https://acme.atlassian.net/browse/PROJ-42) (abc1234)") {
+		t.Errorf("expected linked Jira key, got:\n%s", result)
+	}
+}
+
+func TestGenerate_CommitNotes(t *testing.T) {
+	entry := &Entry{
+		Version:     "1.1.0",
+		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		JiraBaseURL: "https://acme.atlassian.net",
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "add export", Notes: []string{"Exports CSV", "See PROJ-7"}},
+		},
+	}
+
+	result := Generate(entry)
+
+	want := "* add export (abc1234)\n  * Exports CSV\n  * See [PROJ-7](https://acme.atlassian.net/browse/PROJ-7)\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected notes as sub-bullets, got:\n%s", result)
+	}
+}
+
+func TestGenerate_Sections(t *testing.T) {
+	entry := &Entry{
+		Version: "1.1.0",
+		Date:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "docs", Description: "document flags"},
+			{SHA: "def5678901234", ShortSHA: "def5678", Type: "feat", Description: "add export"},
+			{SHA: "0123456789abc", ShortSHA: "0123456", Type: "chore", Description: "tidy"},
+			{SHA: "9876543210fed", ShortSHA: "9876543", Type: "test", Description: "cover export"},
+			{SHA: "fedcba9876543", ShortSHA: "fedcba9", Type: "ci", Description: "cache modules"},
+		},
+		Sections: []config.ChangelogSection{
+			{Type: "feat", Section: "Features"},
+			{Type: "docs", Section: "Documentation"},
+			{Type: "test", Section: "Maintenance"},
+			{Type: "ci", Section: "Maintenance"},
+			{Type: "chore", Section: "Chores", Hidden: true},
+		},
+	}
+
+	want := "### Features\n\n* add export (def5678)\n\n" +
+		"### Documentation\n\n* document flags (abc1234)\n\n" +
+		"### Maintenance\n\n* cover export (9876543)\n* cache modules (fedcba9)\n\n"
+	result := Generate(entry)
+	if !strings.HasSuffix(result, want) {
+		t.Errorf("expected sections in config order, got:\n%s", result)
+	}
+	if strings.Contains(result, "tidy") {
+		t.Errorf("expected hidden chore commit left out, got:\n%s", result)
+	}
+
+	// Without sections, only features, fixes, and performance improvements
+	entry.Sections = nil
+	if result := Generate(entry); strings.Contains(result, "document flags") || !strings.Contains(result, "### Features") {
+		t.Errorf("expected the default sections, got:\n%s", result)
+	}
+}
+
 func TestPrepend_ExistingChangelog(t *testing.T) {
 	existing := `# Changelog
 
@@ -157,6 +296,112 @@ All notable changes to this project will be documented in this file.
 	}
 }
 
+func TestPrepend_InsertMarker(t *testing.T) {
+	existing := `# Changelog
+
+## Versioning (policy)
+
+We follow semver.
+
+<!-- release-damnit-insert -->
+
+## [1.0.0] (2024-01-01)
+
+* initial release
+`
+	newEntry := "## [1.1.0] (2024-01-15)\n\n* fix a bug\n\n"
+
+	want := `# Changelog
+
+## Versioning (policy)
+
+We follow semver.
+
+<!-- release-damnit-insert -->
+
+## [1.1.0] (2024-01-15)
+
+* fix a bug
+
+## [1.0.0] (2024-01-01)
+
+* initial release
+`
+	if got := Prepend(existing, newEntry); got != want {
+		t.Errorf("Prepend() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrepend_InsertMarkerAtEnd(t *testing.T) {
+	existing := "# Changelog\n\n" + InsertMarker
+	newEntry := "## [1.0.0] (2024-01-15)\n\n* initial release\n\n"
+
+	want := "# Changelog\n\n" + InsertMarker + "\n\n" + newEntry
+	if got := Prepend(existing, newEntry); got != want {
+		t.Errorf("Prepend() = %q, want %q", got, want)
+	}
+}
+
+func TestPrepend_SkipsIntroHeaders(t *testing.T) {
+	existing := `# Changelog
+
+## Upgrading (read first)
+
+See the docs.
+
+## 1.0.0
+
+* initial release
+`
+	result := Prepend(existing, "## 1.1.0\n\n* fix a bug\n\n")
+
+	intro := strings.Index(result, "## Upgrading")
+	idx110 := strings.Index(result, "## 1.1.0")
+	idx100 := strings.Index(result, "## 1.0.0")
+	if !(intro < idx110 && idx110 < idx100) {
+		t.Errorf("expected new entry between intro and 1.0.0, got:\n%s", result)
+	}
+}
+
+func TestParseEntries(t *testing.T) {
+	content := `# Changelog
+
+## Upgrading (read first)
+
+See the docs.
+
+## [1.1.0](https://github.com/org/repo/compare/api-v1.0.0...api-v1.1.0) (2024-01-15)
+
+### Bug Fixes
+
+* fix a bug
+
+
+## v1.0.0-rc.1
+
+* initial release
+`
+	entries := ParseEntries(content)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Version != "1.1.0" || entries[0].Date != "2024-01-15" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	want := "## [1.1.0](https://github.com/org/repo/compare/api-v1.0.0...api-v1.1.0) (2024-01-15)\n\n### Bug Fixes\n\n* fix a bug\n"
+	if entries[0].Markdown != want {
+		t.Errorf("Markdown = %q, want %q", entries[0].Markdown, want)
+	}
+	if entries[1].Version != "1.0.0-rc.1" || entries[1].Date != "" || entries[1].Markdown != "## v1.0.0-rc.1\n\n* initial release\n" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+
+	if entries := ParseEntries(InitialChangelog()); len(entries) != 0 {
+		t.Errorf("expected no entries, got %+v", entries)
+	}
+}
+
 func TestBuildCompareURL(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -220,3 +465,60 @@ func TestInitialChangelog(t *testing.T) {
 		t.Error("expected standard description")
 	}
 }
+
+func TestIndexEntry(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	want := "* [1.2.0](1.2.0.md) (2024-01-15)\n"
+	if got := IndexEntry("1.2.0", "1.2.0.md", date); got != want {
+		t.Errorf("IndexEntry() = %q, want %q", got, want)
+	}
+}
+
+func TestPrependIndex(t *testing.T) {
+	line := "* [1.1.0](1.1.0.md) (2024-01-15)\n"
+	old := "* [1.0.0](1.0.0.md) (2024-01-01)\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "new index",
+			existing: InitialIndex(),
+			want:     "# Changelog\n\n" + InsertMarker + "\n\n" + line,
+		},
+		{
+			name:     "marker",
+			existing: "# Changelog\n\n" + InsertMarker + "\n\n" + old,
+			want:     "# Changelog\n\n" + InsertMarker + "\n\n" + line + old,
+		},
+		{
+			name:     "no marker",
+			existing: "# Releases\n\nOlder releases are in the wiki.\n\n" + old,
+			want:     "# Releases\n\nOlder releases are in the wiki.\n\n" + line + old,
+		},
+		{
+			name:     "no entries",
+			existing: "# Releases",
+			want:     "# Releases\n" + line,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrependIndex(tt.existing, line); got != tt.want {
+				t.Errorf("PrependIndex() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepend_CRLF(t *testing.T) {
+	existing := "# Changelog\r\n\r\n## [1.0.0] (2024-01-01)\r\n\r\n* initial release\r\n"
+	newEntry := "## [1.1.0] (2024-01-15)\n\n* fix a bug\n"
+
+	want := "# Changelog\r\n\r\n## [1.1.0] (2024-01-15)\r\n\r\n* fix a bug\r\n## [1.0.0] (2024-01-01)\r\n\r\n* initial release\r\n"
+	if got := Prepend(existing, newEntry); got != want {
+		t.Errorf("Prepend() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// JSONVersion is one release in a CHANGELOG.json sidecar, which holds the
+// same history as the markdown changelog as an array, newest first.
+type JSONVersion struct {
+	// Version is the released version.
+	Version string `json:"version"`
+
+	// PreviousVersion is the version this release follows.
+	PreviousVersion string `json:"previous_version,omitempty"`
+
+	// Date is the release date (YYYY-MM-DD).
+	Date string `json:"date"`
+
+	// Tag is the release tag (component-vX.Y.Z).
+	Tag string `json:"tag"`
+
+	// CompareURL links to the changes since PreviousVersion.
+	CompareURL string `json:"compare_url,omitempty"`
+
+	// ReleaseURL links to the release page for Tag.
+	ReleaseURL string `json:"release_url,omitempty"`
+
+	// Changes are the commits listed in the markdown entry.
+	Changes []JSONChange `json:"changes"`
+
+	// Note explains a release without changes, such as a linked-versions
+	// bump (see Entry.Note).
+	Note string `json:"note,omitempty"`
+}
+
+// JSONChange is a commit in a JSONVersion.
+type JSONChange struct {
+	// SHA is the full commit hash.
+	SHA string `json:"sha"`
+
+	// Type is the conventional commit type (feat, fix, perf).
+	Type string `json:"type"`
+
+	// Scope is the commit scope (optional).
+	Scope string `json:"scope,omitempty"`
+
+	// Description is the commit subject without its type and scope.
+	Description string `json:"description"`
+
+	// Breaking indicates if this is a breaking change.
+	Breaking bool `json:"breaking"`
+
+	// BreakingDescription explains the breaking change (optional).
+	BreakingDescription string `json:"breaking_description,omitempty"`
+
+	// URL links to the commit.
+	URL string `json:"url,omitempty"`
+
+	// Notes are the detail lines shown as sub-bullets.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// GenerateJSON returns the JSONVersion for an entry. Its changes are the
+// commits Generate lists, in the same order.
+func GenerateJSON(entry *Entry) *JSONVersion {
+	contracts.RequireNotNil(entry, "entry")
+	contracts.RequireNotEmpty(entry.Version, "version")
+
+	tag := fmt.Sprintf("%s-v%s", entry.Component, entry.Version)
+	v := &JSONVersion{
+		Version:         entry.Version,
+		PreviousVersion: entry.PrevVersion,
+		Date:            entry.Date.Format("2006-01-02"),
+		Tag:             tag,
+		CompareURL:      entry.CompareURL,
+		ReleaseURL:      BuildReleaseURL(entry.RepoURL, tag),
+		Changes:         []JSONChange{},
+	}
+	if len(entry.Commits) == 0 {
+		v.Note = entry.Note
+	}
+
+	// Breaking changes first, like the markdown entry, without repeating them
+	listed := make(map[string]bool)
+	commits := filterBreakingChanges(entry.Commits)
+	for _, group := range GroupBySection(entry.Commits, entry.Sections) {
+		commits = append(commits, group.Commits...)
+	}
+	for _, c := range commits {
+		if listed[c.SHA] {
+			continue
+		}
+		listed[c.SHA] = true
+		v.Changes = append(v.Changes, JSONChange{
+			SHA:                 c.SHA,
+			Type:                c.Type,
+			Scope:               c.Scope,
+			Description:         c.Description,
+			Breaking:            c.IsBreaking,
+			BreakingDescription: c.BreakingDescription,
+			URL:                 BuildCommitURL(entry.RepoURL, c.SHA),
+			Notes:               c.Notes,
+		})
+	}
+	return v
+}
+
+// PrependJSON adds a version to the top of a CHANGELOG.json sidecar,
+// replacing any existing record of the same version. Other records are kept
+// as they are, including fields this version doesn't know. An empty
+// existing sidecar starts a new one.
+func PrependJSON(existing string, v *JSONVersion) (string, error) {
+	contracts.RequireNotNil(v, "version")
+
+	var records []json.RawMessage
+	if existing != "" {
+		if err := json.Unmarshal([]byte(existing), &records); err != nil {
+			return "", fmt.Errorf("failed to parse changelog JSON: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode changelog JSON: %w", err)
+	}
+	updated := []json.RawMessage{data}
+	for _, record := range records {
+		var r struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(record, &r); err == nil && r.Version == v.Version {
+			continue
+		}
+		updated = append(updated, record)
+	}
+
+	out, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode changelog JSON: %w", err)
+	}
+	return string(out) + "\n", nil
+}
@@ -0,0 +1,92 @@
+package changelog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestGenerateJSON(t *testing.T) {
+	entry := &Entry{
+		Version:     "1.1.0",
+		PrevVersion: "1.0.0",
+		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		CompareURL:  "https://github.com/org/repo/compare/api-v1.0.0...api-v1.1.0",
+		Component:   "api",
+		RepoURL:     "https://github.com/org/repo",
+		Commits: []*git.Commit{
+			{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "fix", Description: "fix crash"},
+			{SHA: "bbb2222222222", ShortSHA: "bbb2222", Type: "chore", Description: "tidy"},
+			{SHA: "ccc3333333333", ShortSHA: "ccc3333", Type: "feat", Scope: "auth", Description: "add tokens", IsBreaking: true, BreakingDescription: "Sessions must be renewed.", Notes: []string{"Old tokens expire"}},
+		},
+	}
+
+	v := GenerateJSON(entry)
+
+	if v.Version != "1.1.0" || v.PreviousVersion != "1.0.0" || v.Date != "2024-01-15" || v.Tag != "api-v1.1.0" {
+		t.Errorf("unexpected version fields: %+v", v)
+	}
+	if v.ReleaseURL != "https://github.com/org/repo/releases/tag/api-v1.1.0" {
+		t.Errorf("unexpected release URL: %s", v.ReleaseURL)
+	}
+	if len(v.Changes) != 2 {
+		t.Fatalf("expected the breaking feat and the fix, got %+v", v.Changes)
+	}
+	first := v.Changes[0]
+	if first.SHA != "ccc3333333333" || !first.Breaking || first.BreakingDescription != "Sessions must be renewed." || first.Scope != "auth" || len(first.Notes) != 1 {
+		t.Errorf("expected the breaking change first, got %+v", first)
+	}
+	if first.URL != "https://github.com/org/repo/commit/ccc3333333333" {
+		t.Errorf("unexpected commit URL: %s", first.URL)
+	}
+	if v.Changes[1].Type != "fix" {
+		t.Errorf("expected the fix second, got %+v", v.Changes[1])
+	}
+}
+
+func TestGenerateJSON_Note(t *testing.T) {
+	v := GenerateJSON(&Entry{
+		Version:   "1.2.0",
+		Date:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Component: "web",
+		Note:      "Version bump to stay in sync with services; see api 1.2.0.",
+	})
+	if len(v.Changes) != 0 || v.Note != "Version bump to stay in sync with services; see api 1.2.0." {
+		t.Errorf("expected no changes and the note, got %+v", v)
+	}
+}
+
+func TestPrependJSON(t *testing.T) {
+	existing := `[
+  {"version": "1.1.0", "date": "2024-01-10", "changes": []},
+  {"version": "1.0.0", "date": "2024-01-01", "changes": [], "custom": true}
+]`
+	got, err := PrependJSON(existing, &JSONVersion{Version: "1.1.0", Date: "2024-01-15", Changes: []JSONChange{}})
+	if err != nil {
+		t.Fatalf("PrependJSON failed: %v", err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &records); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, got)
+	}
+	if len(records) != 2 || records[0]["date"] != "2024-01-15" || records[1]["version"] != "1.0.0" {
+		t.Errorf("expected 1.1.0 replaced and 1.0.0 kept, got %v", records)
+	}
+	if records[1]["custom"] != true {
+		t.Errorf("expected unknown fields kept, got %v", records[1])
+	}
+	if !strings.HasSuffix(got, "\n") {
+		t.Error("expected a trailing newline")
+	}
+
+	if got, err := PrependJSON("", &JSONVersion{Version: "1.0.0"}); err != nil || !strings.HasPrefix(got, "[\n  {") {
+		t.Errorf("expected a new array, got %q, %v", got, err)
+	}
+	if _, err := PrependJSON("{}", &JSONVersion{Version: "1.0.0"}); err == nil {
+		t.Error("expected an error for a non-array sidecar")
+	}
+}
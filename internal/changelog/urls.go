@@ -0,0 +1,121 @@
+package changelog
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLBuilder renders web links into a hosted repository. Hosting platforms
+// lay out compare, commit, and release pages differently.
+type URLBuilder interface {
+	// CompareURL links to the diff between two refs.
+	CompareURL(repoURL, fromRef, toRef string) string
+
+	// CommitURL links to a single commit.
+	CommitURL(repoURL, sha string) string
+
+	// ReleaseURL links to the release (or tag) page for a tag.
+	ReleaseURL(repoURL, tag string) string
+}
+
+// GitHub builds GitHub and GitHub Enterprise links.
+type GitHub struct{}
+
+func (GitHub) CompareURL(repoURL, fromRef, toRef string) string {
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, fromRef, toRef)
+}
+
+func (GitHub) CommitURL(repoURL, sha string) string {
+	return fmt.Sprintf("%s/commit/%s", repoURL, sha)
+}
+
+func (GitHub) ReleaseURL(repoURL, tag string) string {
+	return fmt.Sprintf("%s/releases/tag/%s", repoURL, tag)
+}
+
+// GitLab builds GitLab links, which live under the /-/ scope.
+type GitLab struct{}
+
+func (GitLab) CompareURL(repoURL, fromRef, toRef string) string {
+	return fmt.Sprintf("%s/-/compare/%s...%s", repoURL, fromRef, toRef)
+}
+
+func (GitLab) CommitURL(repoURL, sha string) string {
+	return fmt.Sprintf("%s/-/commit/%s", repoURL, sha)
+}
+
+func (GitLab) ReleaseURL(repoURL, tag string) string {
+	return fmt.Sprintf("%s/-/releases/%s", repoURL, tag)
+}
+
+// Gitea builds Gitea and Forgejo (e.g., Codeberg) links.
+type Gitea struct{}
+
+func (Gitea) CompareURL(repoURL, fromRef, toRef string) string {
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, fromRef, toRef)
+}
+
+func (Gitea) CommitURL(repoURL, sha string) string {
+	return fmt.Sprintf("%s/commit/%s", repoURL, sha)
+}
+
+func (Gitea) ReleaseURL(repoURL, tag string) string {
+	return fmt.Sprintf("%s/releases/tag/%s", repoURL, tag)
+}
+
+// Bitbucket builds Bitbucket Cloud links. Bitbucket has no release pages, so
+// ReleaseURL links to the source tree at the tag.
+type Bitbucket struct{}
+
+func (Bitbucket) CompareURL(repoURL, fromRef, toRef string) string {
+	// Bitbucket compares "to" against "from", separated by an encoded CR
+	return fmt.Sprintf("%s/branches/compare/%s%%0D%s", repoURL, toRef, fromRef)
+}
+
+func (Bitbucket) CommitURL(repoURL, sha string) string {
+	return fmt.Sprintf("%s/commits/%s", repoURL, sha)
+}
+
+func (Bitbucket) ReleaseURL(repoURL, tag string) string {
+	return fmt.Sprintf("%s/src/%s", repoURL, tag)
+}
+
+// URLBuilderFor picks the URL builder for a repository by its host. Hosts
+// named like gitlab.*, gitea.*, or bitbucket.* (and codeberg.org) get their
+// platform's layout; everything else is treated as GitHub.
+func URLBuilderFor(repoURL string) URLBuilder {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return GitHub{}
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return GitLab{}
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"), host == "codeberg.org":
+		return Gitea{}
+	case strings.Contains(host, "bitbucket"):
+		return Bitbucket{}
+	}
+	return GitHub{}
+}
+
+// BuildCommitURL creates a link to a commit. Returns "" without a repo URL.
+func BuildCommitURL(repoURL, sha string) string {
+	if repoURL == "" {
+		return ""
+	}
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	return URLBuilderFor(repoURL).CommitURL(repoURL, sha)
+}
+
+// BuildReleaseURL creates a link to the release page for a tag. Returns ""
+// without a repo URL.
+func BuildReleaseURL(repoURL, tag string) string {
+	if repoURL == "" {
+		return ""
+	}
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	return URLBuilderFor(repoURL).ReleaseURL(repoURL, tag)
+}
@@ -0,0 +1,78 @@
+package changelog
+
+import "testing"
+
+func TestURLBuilderFor(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    URLBuilder
+	}{
+		{"https://github.com/acme/app", GitHub{}},
+		{"https://github.example.com/acme/app", GitHub{}},
+		{"https://gitlab.com/group/sub/app", GitLab{}},
+		{"https://gitlab.example.com:8443/group/app", GitLab{}},
+		{"https://gitea.example.com/acme/app", Gitea{}},
+		{"https://codeberg.org/acme/app", Gitea{}},
+		{"https://bitbucket.org/acme/app", Bitbucket{}},
+		{"", GitHub{}},
+	}
+	for _, tc := range tests {
+		if got := URLBuilderFor(tc.repoURL); got != tc.want {
+			t.Errorf("URLBuilderFor(%q) = %T, want %T", tc.repoURL, got, tc.want)
+		}
+	}
+}
+
+func TestURLBuilders(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		compare string
+		commit  string
+		release string
+	}{
+		{
+			repoURL: "https://github.com/acme/app",
+			compare: "https://github.com/acme/app/compare/app-v1.0.0...app-v1.1.0",
+			commit:  "https://github.com/acme/app/commit/abc1234",
+			release: "https://github.com/acme/app/releases/tag/app-v1.1.0",
+		},
+		{
+			repoURL: "https://gitlab.com/group/sub/app",
+			compare: "https://gitlab.com/group/sub/app/-/compare/app-v1.0.0...app-v1.1.0",
+			commit:  "https://gitlab.com/group/sub/app/-/commit/abc1234",
+			release: "https://gitlab.com/group/sub/app/-/releases/app-v1.1.0",
+		},
+		{
+			repoURL: "https://codeberg.org/acme/app",
+			compare: "https://codeberg.org/acme/app/compare/app-v1.0.0...app-v1.1.0",
+			commit:  "https://codeberg.org/acme/app/commit/abc1234",
+			release: "https://codeberg.org/acme/app/releases/tag/app-v1.1.0",
+		},
+		{
+			repoURL: "https://bitbucket.org/acme/app",
+			compare: "https://bitbucket.org/acme/app/branches/compare/app-v1.1.0%0Dapp-v1.0.0",
+			commit:  "https://bitbucket.org/acme/app/commits/abc1234",
+			release: "https://bitbucket.org/acme/app/src/app-v1.1.0",
+		},
+	}
+	for _, tc := range tests {
+		if got := BuildCompareURL(tc.repoURL, "app", "1.0.0", "1.1.0"); got != tc.compare {
+			t.Errorf("compare: got %s, want %s", got, tc.compare)
+		}
+		if got := BuildCommitURL(tc.repoURL+"/", "abc1234"); got != tc.commit {
+			t.Errorf("commit: got %s, want %s", got, tc.commit)
+		}
+		if got := BuildReleaseURL(tc.repoURL, "app-v1.1.0"); got != tc.release {
+			t.Errorf("release: got %s, want %s", got, tc.release)
+		}
+	}
+}
+
+func TestBuildURLs_NoRepoURL(t *testing.T) {
+	if got := BuildCommitURL("", "abc1234"); got != "" {
+		t.Errorf("expected no commit URL, got %s", got)
+	}
+	if got := BuildReleaseURL("", "app-v1.0.0"); got != "" {
+		t.Errorf("expected no release URL, got %s", got)
+	}
+}
@@ -1,19 +1,130 @@
 // Package config handles parsing of Release Please configuration files.
 // This package reads release-please-config.json and release-please-manifest.json
 // to understand package structure, linked versions, and current versions.
+//
+// An optional .release-damnit.yaml uses the same keys as
+// release-please-config.json. When both exist they're merged, with the YAML
+// file taking precedence, so repos can keep release-please-config.json for
+// Release Please compatibility and put release-damnit-only settings in YAML.
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/internal/yaml"
 	"github.com/dsswift/release-damnit/pkg/contracts"
 )
 
+// Config file names, relative to the repository root.
+const (
+	ReleasePleaseConfigFile = "release-please-config.json"
+	NativeConfigFile        = ".release-damnit.yaml"
+	ManifestFile            = "release-please-manifest.json"
+)
+
+// RootPath is the package path of a component covering the whole repo.
+const RootPath = "."
+
+// DefaultVersionFile is the version file updated in each package unless
+// version-file says otherwise.
+const DefaultVersionFile = "VERSION"
+
+// noVersionFile is the version-file value for packages without one.
+const noVersionFile = "none"
+
+// Changelog layouts, for a package's changelog-layout setting.
+const (
+	// ChangelogLayoutFile prepends each release to a single changelog file.
+	ChangelogLayoutFile = "file"
+
+	// ChangelogLayoutDirectory writes each release to <version>.md in a
+	// changelog directory, listed newest first in its index file.
+	ChangelogLayoutDirectory = "directory"
+)
+
+// PrereleaseStrictZero is the prerelease-semantics value that versions 0.x
+// packages as semver recommends: breaking changes bump minor and features
+// bump patch.
+const PrereleaseStrictZero = "strict-zero"
+
+// DefaultChangelogDir is the changelog directory for the directory layout
+// unless changelog-path says otherwise.
+const DefaultChangelogDir = "changelogs"
+
+// ChangelogIndexFile is the index file in a changelog directory.
+const ChangelogIndexFile = "README.md"
+
+// Version sources, for the version-source setting.
+const (
+	// VersionSourceManifest reads current versions from the manifest.
+	VersionSourceManifest = "manifest"
+
+	// VersionSourceTags leaves current versions to be derived from the latest
+	// component-v* tag reachable from HEAD; no manifest is read or written.
+	VersionSourceTags = "tags"
+)
+
+// Merge commit modes, for the merge-commits setting.
+const (
+	// MergeCommitsIgnore analyzes only the commits a merge brought in.
+	MergeCommitsIgnore = "ignore"
+
+	// MergeCommitsInclude also analyzes a conventional merge commit subject.
+	MergeCommitsInclude = "include"
+
+	// MergeCommitsOnly analyzes a conventional merge commit subject instead of
+	// the merged commits.
+	MergeCommitsOnly = "only"
+)
+
+// Tag collision policies, for the tag-collision setting: what to do when a
+// release's tag already exists at a commit other than HEAD.
+const (
+	// TagCollisionError fails the run.
+	TagCollisionError = "error"
+
+	// TagCollisionSkip leaves the package out of the run's releases.
+	TagCollisionSkip = "skip"
+
+	// TagCollisionBumpAgain releases the next patch version whose tag is
+	// free instead.
+	TagCollisionBumpAgain = "bump-again"
+)
+
+// DefaultReleaseCommitPattern matches the subjects of release commits made by
+// Release Please and release-damnit (e.g., "chore: release main" or
+// "chore(main): release service-a 1.2.0").
+const DefaultReleaseCommitPattern = `^chore(\([^)]*\))?!?: release\b`
+
+var defaultReleaseCommitRegex = regexp.MustCompile(DefaultReleaseCommitPattern)
+
+// ReleaseTitlePlaceholders are the placeholders a release-title-pattern may
+// use, as ${name}.
+var ReleaseTitlePlaceholders = []string{"component", "version", "date"}
+
+var titlePlaceholderRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// DefaultPushgatewayJob is the Pushgateway job run metrics are pushed under
+// unless telemetry.pushgateway.job says otherwise.
+const DefaultPushgatewayJob = "release_damnit"
+
+// DefaultOTLPServiceName is the service.name of exported traces unless
+// telemetry.otlp.service-name says otherwise.
+const DefaultOTLPServiceName = "release-damnit"
+
 // Config represents the parsed release-please-config.json and manifest.
 type Config struct {
 	// Packages maps path (relative to repo root) to package configuration.
@@ -22,10 +133,117 @@ type Config struct {
 
 	// LinkedGroups maps group name to the set of component names that are linked.
 	// When any component in a group is bumped, all are bumped to the same version.
+	// Groups nested in another are folded into the outermost one.
 	LinkedGroups map[string][]string
 
 	// RepoRoot is the absolute path to the repository root.
 	RepoRoot string
+
+	// ManifestPath is the manifest's path relative to RepoRoot. Empty when
+	// VersionSource is VersionSourceTags.
+	ManifestPath string
+
+	// Digest is the hex SHA-256 of the effective config: the merged config
+	// files as JSON with sorted keys, so formatting doesn't change it.
+	Digest string
+
+	// VersionSource is where current versions come from
+	// (VersionSourceManifest or VersionSourceTags). With VersionSourceTags,
+	// Package.CurrentVersion is empty after Load and filled in by analysis.
+	VersionSource string
+
+	// Notifications configures where release announcements are sent.
+	Notifications *Notifications
+
+	// Telemetry configures where run metrics and traces are exported.
+	Telemetry *Telemetry
+
+	// Jira configures linking and updating Jira issues referenced in commits.
+	Jira *Jira
+
+	// Hooks are shell commands run around applying and releasing.
+	Hooks *Hooks
+
+	// Remotes configures the canonical repository URL and mirror remotes.
+	Remotes *Remotes
+
+	// Approval requires a maintainer's sign-off on a tracking issue before
+	// releases are applied, or is nil for no approval step.
+	Approval *Approval
+
+	// Branches configures releases from branches whose names match a glob
+	// pattern (e.g., "1.x" or "release/*").
+	Branches map[string]*Branch
+
+	// BumpRules overrides the bump for conventional commit types
+	// (e.g., {"perf": patch, "docs": patch, "refactor": none}).
+	BumpRules map[string]version.BumpType
+
+	// ReleaseCommitPattern matches the subjects of release commits, which are
+	// left out of analysis. Defaults to DefaultReleaseCommitPattern.
+	ReleaseCommitPattern *regexp.Regexp
+
+	// MergeCommits controls whether a merge commit's own subject is analyzed
+	// (MergeCommitsIgnore, MergeCommitsInclude, or MergeCommitsOnly).
+	// Defaults to MergeCommitsIgnore.
+	MergeCommits string
+
+	// Dependencies maps a component to the components it depends on
+	// (e.g., {"jarvis-web": ["jarvis-api-client"]}). Releasing a dependency
+	// schedules at least a patch release of its dependents.
+	Dependencies map[string][]string
+
+	// DiscussionCategory, if set, is the GitHub Discussions category a
+	// discussion is created in for each release (e.g., "Announcements").
+	DiscussionCategory string
+
+	// Environments are deployment targets whose manifests record the
+	// deployed version of each component, in the order they're promoted
+	// through.
+	Environments []*Environment
+
+	// TagCollision is what to do when a release's tag already exists at
+	// another commit (TagCollisionError, TagCollisionSkip, or
+	// TagCollisionBumpAgain). Defaults to TagCollisionError.
+	TagCollision string
+
+	// IncludeCommitBody if true, renders commit bodies as sub-bullets under
+	// changelog entries. Release-Note: trailers are rendered regardless.
+	IncludeCommitBody bool
+
+	// SubmoduleLog if true, adds the subjects of the commits a submodule
+	// update brings in as sub-bullets under its entry, when the submodule
+	// is checked out.
+	SubmoduleLog bool
+
+	// SummarizeBinaryFiles if true, adds a count of the binary and Git LFS
+	// files a commit changes as a sub-bullet under its entry.
+	SummarizeBinaryFiles bool
+
+	// IgnoreBinaryFiles if true, leaves binary and Git LFS files out of the
+	// files that attribute commits to packages, like IgnoreFiles.
+	IgnoreBinaryFiles bool
+
+	// CommitParser adjusts how subjects are parsed as conventional commits,
+	// or is nil for the standard format.
+	CommitParser *CommitParser
+
+	// IgnoreFiles are glob patterns for files whose changes never count
+	// toward a release, such as lockfiles and generated code (see
+	// IsIgnoredFile).
+	IgnoreFiles []string
+
+	// ScopeAliases maps shorthand commit scopes, lowercased, to the scope
+	// they stand for (e.g., {"ui": "jarvis-web"}). See NormalizeScope.
+	ScopeAliases map[string]string
+
+	// FreezeWindows are periods during which releases are analyzed but not
+	// applied (see FreezeAt).
+	FreezeWindows []*FreezeWindow
+
+	// pathIndex speeds up FindPackageForPath. Load and ScopeTo build it;
+	// configs built by hand aren't indexed, so lookups scan their packages.
+	pathIndex *pathTrie
 }
 
 // Package represents a single package's configuration.
@@ -37,25 +255,504 @@ type Package struct {
 	Component string
 
 	// ChangelogPath is the relative path to changelog from package root.
-	// Defaults to "CHANGELOG.md".
+	// Defaults to "CHANGELOG.md", or DefaultChangelogDir for the directory
+	// layout.
 	ChangelogPath string
 
+	// ChangelogLayout is ChangelogLayoutFile or ChangelogLayoutDirectory.
+	ChangelogLayout string
+
+	// ChangelogJSON if true, also keeps a machine-readable CHANGELOG.json
+	// alongside the changelog.
+	ChangelogJSON bool
+
+	// VersionFile is the path of the version file relative to the package
+	// root. Defaults to "VERSION"; empty means the package has none.
+	VersionFile string
+
 	// CurrentVersion is the current version from the manifest.
 	CurrentVersion string
 
 	// LinkedGroup is the name of the linked-versions group, if any.
 	LinkedGroup string
+
+	// Versioning names the version strategy (see pkg/extension). Empty means "default".
+	Versioning string
+
+	// PrereleaseSemantics controls bumps while the version is 0.x. Empty
+	// follows the analyzer (release.Options.TreatPreMajorAsMinor);
+	// PrereleaseStrictZero bumps minor for breaking changes and patch for
+	// features.
+	PrereleaseSemantics string
+
+	// ExtraFiles are additional files whose version is updated on release.
+	ExtraFiles []*ExtraFile
+
+	// DockerfileArgs are the names of Dockerfile ARGs whose defaults follow
+	// the package version (e.g., ["VERSION"]). Other ARGs are left alone.
+	DockerfileArgs []string
+
+	// ExcludePaths are paths (relative to repo root) whose changes never
+	// count toward this package, e.g. sub-packages of a root component.
+	ExcludePaths []string
+
+	// MinCommits is the number of releasable commits that must be pending
+	// since the package's last release tag before it's released again.
+	// Zero releases on every releasable commit.
+	MinCommits int
+
+	// ReleaseOnTypes are commit types (or "breaking") that release the
+	// package right away, however few commits are pending.
+	ReleaseOnTypes []string
+
+	// ReleaseTitlePattern is the GitHub release title, with ${component},
+	// ${version}, and ${date} placeholders (e.g., "v${version}"). Empty
+	// means "<component> v<version>".
+	ReleaseTitlePattern string
+
+	// ChangelogSections are the changelog and release notes sections commits
+	// are listed under, by type, in order. Defaults to the config's
+	// changelog-sections, or DefaultChangelogSections.
+	ChangelogSections []ChangelogSection
+
+	// ChangelogSort orders the bullets within each changelog section: ""
+	// for commit order or ChangelogSortScope. From the config's
+	// changelog-sort.
+	ChangelogSort string
+
+	// ChangelogMaxEntries caps the bullets listed in a changelog entry or
+	// release notes, or is 0 for no cap. From the config's
+	// changelog-max-entries.
+	ChangelogMaxEntries int
+
+	// InitialVersion, if set, is the version of the package's first
+	// release, whatever its commits' bump. Empty bumps from 0.0.0.
+	InitialVersion string
+
+	// Jira links issue keys in the package's release notes, or is nil. From
+	// the config's jira.
+	Jira *Jira
+}
+
+// ChangelogSortScope sorts changelog bullets by scope, unscoped last, then
+// alphabetically by description.
+const ChangelogSortScope = "scope"
+
+// ChangelogSection is an entry in changelog-sections: the heading commits
+// of a type are listed under. Types that aren't listed, or are hidden, are
+// left out of changelogs; either way they bump versions as usual.
+type ChangelogSection struct {
+	// Type is the conventional commit type (e.g., "docs").
+	Type string `json:"type"`
+
+	// Section is the heading (e.g., "Documentation"). Types with the same
+	// heading share one section.
+	Section string `json:"section"`
+
+	// Hidden leaves the type out of changelogs.
+	Hidden bool `json:"hidden"`
+}
+
+// DefaultChangelogSections are the sections of Release Please's default
+// changelog.
+var DefaultChangelogSections = []ChangelogSection{
+	{Type: "feat", Section: "Features"},
+	{Type: "fix", Section: "Bug Fixes"},
+	{Type: "perf", Section: "Performance Improvements"},
+}
+
+// validateChangelogSections returns the problems with a changelog-sections
+// list, each prefixed with what names the list.
+func validateChangelogSections(name string, sections []ChangelogSection) []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for i, s := range sections {
+		switch {
+		case s.Type == "":
+			problems = append(problems, fmt.Sprintf("%s[%d] has no type", name, i))
+		case seen[s.Type]:
+			problems = append(problems, fmt.Sprintf("%s[%d] repeats type %q", name, i, s.Type))
+		case s.Section == "" && !s.Hidden:
+			problems = append(problems, fmt.Sprintf("%s[%d] (%s) has no section", name, i, s.Type))
+		}
+		seen[s.Type] = true
+	}
+	return problems
+}
+
+// ExtraFile is an entry in a package's extra-files list. Entries are either a
+// path string (updated by the "generic" updater) or an object with a type, a
+// path, and any options the updater accepts.
+type ExtraFile struct {
+	// Type names the file updater (see pkg/extension).
+	Type string
+
+	// Path is relative to the package root.
+	Path string
+
+	// Options holds the entry's remaining keys (e.g., "jsonpath").
+	Options map[string]interface{}
+}
+
+// UnmarshalJSON accepts both the string and object forms of an extra-files entry.
+func (e *ExtraFile) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*e = ExtraFile{Type: "generic", Path: path}
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("extra-files entry must be a path or an object: %w", err)
+	}
+	typ, _ := fields["type"].(string)
+	path, _ = fields["path"].(string)
+	delete(fields, "type")
+	delete(fields, "path")
+	if typ == "" {
+		typ = "generic"
+	}
+	*e = ExtraFile{Type: typ, Path: path, Options: fields}
+	return nil
+}
+
+// Notifications configures post-release notification targets.
+type Notifications struct {
+	// Webhooks are generic HTTP endpoints that receive the release report.
+	Webhooks []*Webhook `json:"webhooks"`
+
+	// Slack posts Block Kit release announcements to Slack.
+	Slack []*Slack `json:"slack"`
+
+	// Discord posts embed release announcements to Discord webhooks.
+	Discord []*ChatWebhook `json:"discord"`
+
+	// Teams posts Adaptive Card release announcements to Microsoft Teams webhooks.
+	Teams []*ChatWebhook `json:"teams"`
+}
+
+// NotificationFilter limits which releases a notification target receives.
+type NotificationFilter struct {
+	// Components limits the target to these components. Empty means all.
+	Components []string `json:"components"`
+
+	// BumpTypes limits the target to these bump types ("major", "minor", "patch"). Empty means all.
+	BumpTypes []string `json:"bump-types"`
+}
+
+// Webhook configures a generic HTTP webhook fired after successful releases.
+type Webhook struct {
+	// URL is the endpoint to POST to. ${VAR} references are expanded from the environment.
+	URL string `json:"url"`
+
+	// Headers are extra HTTP headers. Values support ${VAR} expansion.
+	Headers map[string]string `json:"headers"`
+
+	// SecretEnv names the environment variable holding the HMAC-SHA256 signing secret.
+	// When set, the payload signature is sent in the X-Release-Damnit-Signature header.
+	SecretEnv string `json:"secret-env"`
+
+	NotificationFilter
+}
+
+// Slack configures a Slack release announcement. Either WebhookURL or
+// TokenEnv + Channel must be set.
+type Slack struct {
+	// WebhookURL is a Slack incoming webhook URL. Supports ${VAR} expansion.
+	WebhookURL string `json:"webhook-url"`
+
+	// TokenEnv names the environment variable holding a bot token (xoxb-...).
+	TokenEnv string `json:"token-env"`
+
+	// Channel is the channel to post to when using a bot token.
+	Channel string `json:"channel"`
+
+	NotificationFilter
+}
+
+// ChatWebhook configures a chat service that only needs an incoming webhook URL
+// (Discord, Microsoft Teams).
+type ChatWebhook struct {
+	// WebhookURL is the incoming webhook URL. Supports ${VAR} expansion.
+	WebhookURL string `json:"webhook-url"`
+
+	NotificationFilter
+}
+
+// Telemetry configures exporting run metrics, for release-damnit running as
+// a service or on a schedule.
+type Telemetry struct {
+	// Pushgateway pushes run metrics to a Prometheus Pushgateway.
+	Pushgateway *Pushgateway `json:"pushgateway"`
+
+	// OTLP sends a trace of the run to an OpenTelemetry collector.
+	OTLP *OTLP `json:"otlp"`
+}
+
+// Pushgateway configures pushing run metrics to a Prometheus Pushgateway.
+type Pushgateway struct {
+	// URL is the Pushgateway's base URL (e.g., "http://pushgateway:9091").
+	// Supports ${VAR} expansion.
+	URL string `json:"url"`
+
+	// Job is the job label metrics are grouped under. Defaults to
+	// DefaultPushgatewayJob.
+	Job string `json:"job"`
+
+	// Grouping adds labels to the grouping key (e.g., {"repo": "acme/api"}),
+	// so runs for different repos don't replace each other's metrics.
+	Grouping map[string]string `json:"grouping"`
+
+	// Headers are extra HTTP headers. Values support ${VAR} expansion.
+	Headers map[string]string `json:"headers"`
+}
+
+// OTLP configures sending a trace of each run over OTLP/HTTP.
+type OTLP struct {
+	// Endpoint is the collector's OTLP/HTTP base URL
+	// (e.g., "http://collector:4318"). Traces go to /v1/traces under it
+	// unless it already ends in that path. Supports ${VAR} expansion.
+	Endpoint string `json:"endpoint"`
+
+	// ServiceName is the trace's service.name. Defaults to
+	// DefaultOTLPServiceName.
+	ServiceName string `json:"service-name"`
+
+	// Headers are extra HTTP headers. Values support ${VAR} expansion.
+	Headers map[string]string `json:"headers"`
+}
+
+// Jira configures the Jira issue integration.
+type Jira struct {
+	// BaseURL is the Jira site (e.g., "https://acme.atlassian.net").
+	// Issue keys in changelog entries are linked to {BaseURL}/browse/{KEY}.
+	BaseURL string `json:"base-url"`
+
+	// Projects limits key detection to these project prefixes (e.g., ["PROJ"]).
+	// Empty matches any KEY-123 pattern.
+	Projects []string `json:"projects"`
+
+	// TokenEnv names the environment variable holding the API token.
+	TokenEnv string `json:"token-env"`
+
+	// EmailEnv names the environment variable holding the account email.
+	// When set, basic auth is used (Jira Cloud); otherwise the token is a bearer token.
+	EmailEnv string `json:"email-env"`
+
+	// Comment if true, adds a "Released in ..." comment to each referenced issue.
+	Comment bool `json:"comment"`
+
+	// TransitionTo is the transition name to apply to referenced issues (e.g., "Done").
+	TransitionTo string `json:"transition-to"`
+}
+
+// UpdatesIssues reports whether the Jira config requests any API calls.
+func (j *Jira) UpdatesIssues() bool {
+	return j != nil && (j.Comment || j.TransitionTo != "")
+}
+
+// CommitParser configures parsing of commit subjects that don't quite follow
+// the conventional commit format, such as "✨ feat: ..." or
+// "[PROJ-1] feat: ...".
+type CommitParser struct {
+	// StripPrefixes are Go regular expressions removed from the start of a
+	// subject, repeatedly, before it's parsed (e.g., ["\\p{So}", "\\[[A-Z]+-\\d+\\]"]).
+	StripPrefixes []string `json:"strip-prefixes"`
+
+	// ScopeDelimiters are the pairs of characters a scope may be enclosed
+	// in (e.g., ["()", "[]"]). Defaults to ["()"].
+	ScopeDelimiters []string `json:"scope-delimiters"`
+
+	// CaseSensitive if true, only lowercase types are conventional, so
+	// "Fix: ..." isn't.
+	CaseSensitive bool `json:"case-sensitive"`
+}
+
+// ParserOptions returns the git parser options for the config. Safe to call
+// on a nil CommitParser.
+func (p *CommitParser) ParserOptions() *git.ParserOptions {
+	if p == nil {
+		return nil
+	}
+	return &git.ParserOptions{
+		StripPrefixes:   p.StripPrefixes,
+		ScopeDelimiters: p.ScopeDelimiters,
+		CaseSensitive:   p.CaseSensitive,
+	}
+}
+
+// Remotes configures repositories that are mirrored or forked, where the
+// remote the tool runs against isn't the one links should point at.
+type Remotes struct {
+	// CanonicalURL is the repository URL used for changelog and release links
+	// (e.g., "https://github.com/acme/app"). Overrides the URL detected from
+	// the git remote.
+	CanonicalURL string `json:"canonical-url"`
+
+	// Mirrors are git remotes each release tag is also pushed to, along with
+	// its commit, after the GitHub release is created.
+	Mirrors []string `json:"mirrors"`
+}
+
+// DefaultApprovalLabel and DefaultApprovalCommand are the approval
+// section's defaults.
+const (
+	DefaultApprovalLabel   = "release-approval"
+	DefaultApprovalCommand = "/approve"
+)
+
+// Approval configures the tracking issue releases wait on until a maintainer
+// approves them by commenting.
+type Approval struct {
+	// Label marks approval issues. Defaults to DefaultApprovalLabel.
+	Label string `json:"label"`
+
+	// Approvers are the GitHub logins whose approval counts. Empty means
+	// any owner, member, or collaborator of the repository.
+	Approvers []string `json:"approvers"`
+
+	// Command is the comment that approves, matched case-insensitively.
+	// Defaults to DefaultApprovalCommand.
+	Command string `json:"command"`
+}
+
+// Environment is a deployment target, such as a GitOps overlay, whose YAML
+// manifest records the version of each component deployed to it. Path and
+// JSONPath may use ${component}, so one entry covers every component.
+type Environment struct {
+	// Name identifies the environment (e.g., "staging").
+	Name string `json:"name"`
+
+	// Path is the manifest, relative to the repo root
+	// (e.g., "deploy/prod/${component}/values.yaml").
+	Path string `json:"path"`
+
+	// JSONPath locates the version in the manifest
+	// (e.g., "$.images[?(@.name=='ghcr.io/acme/${component}')].newTag").
+	JSONPath string `json:"jsonpath"`
+}
+
+// ManifestPath returns the environment's manifest for a component.
+func (e *Environment) ManifestPath(component string) string {
+	return strings.ReplaceAll(e.Path, "${component}", component)
+}
+
+// VersionPath returns the jsonpath of a component's version.
+func (e *Environment) VersionPath(component string) string {
+	return strings.ReplaceAll(e.JSONPath, "${component}", component)
+}
+
+// Branch configures releases from a long-lived branch, such as a maintenance
+// branch that only gets backported fixes.
+type Branch struct {
+	// MaxBump caps version bumps on the branch, so a maintenance branch's
+	// versions can't catch up with mainline's. None means no cap.
+	MaxBump version.BumpType
+}
+
+// Capped reports whether the branch limits version bumps. Safe to call on a
+// nil Branch.
+func (b *Branch) Capped() bool {
+	return b != nil && b.MaxBump != version.None
+}
+
+// Hook stages, in the order they run.
+const (
+	HookPreApply    = "pre-apply"
+	HookPostApply   = "post-apply"
+	HookPreRelease  = "pre-release"
+	HookPostRelease = "post-release"
+)
+
+// Hooks configures shell commands run for each release at each stage.
+// Commands run with sh -c from the repo root.
+type Hooks struct {
+	// PreApply runs before VERSION, CHANGELOG, and manifest files are written.
+	PreApply []string `json:"pre-apply"`
+
+	// PostApply runs after the files are written (e.g., to regenerate lockfiles).
+	PostApply []string `json:"post-apply"`
+
+	// PreRelease runs before each GitHub release is created.
+	PreRelease []string `json:"pre-release"`
+
+	// PostRelease runs after each GitHub release is created.
+	PostRelease []string `json:"post-release"`
+}
+
+// Commands returns the commands for a stage. Safe to call on a nil Hooks.
+func (h *Hooks) Commands(stage string) []string {
+	if h == nil {
+		return nil
+	}
+	switch stage {
+	case HookPreApply:
+		return h.PreApply
+	case HookPostApply:
+		return h.PostApply
+	case HookPreRelease:
+		return h.PreRelease
+	case HookPostRelease:
+		return h.PostRelease
+	}
+	return nil
 }
 
 // releasePleaseConfig represents the JSON structure of release-please-config.json.
 type releasePleaseConfig struct {
-	Packages map[string]packageConfig `json:"packages"`
-	Plugins  []pluginConfig           `json:"plugins"`
+	Packages             map[string]packageConfig `json:"packages"`
+	Plugins              []pluginConfig           `json:"plugins"`
+	Notifications        *Notifications           `json:"notifications"`
+	Telemetry            *Telemetry               `json:"telemetry"`
+	Jira                 *Jira                    `json:"jira"`
+	Hooks                *Hooks                   `json:"hooks"`
+	Remotes              *Remotes                 `json:"remotes"`
+	Approval             *Approval                `json:"approval"`
+	Branches             map[string]branchConfig  `json:"branches"`
+	BumpRules            map[string]string        `json:"bump-rules"`
+	ReleaseCommitPattern *string                  `json:"release-commit-pattern"`
+	IncludeCommitBody    bool                     `json:"include-commit-body"`
+	SubmoduleLog         bool                     `json:"submodule-log"`
+	SummarizeBinaryFiles bool                     `json:"summarize-binary-files"`
+	IgnoreBinaryFiles    bool                     `json:"ignore-binary-files"`
+	ChangelogSections    []ChangelogSection       `json:"changelog-sections"`
+	ChangelogSort        string                   `json:"changelog-sort"`
+	ChangelogMaxEntries  int                      `json:"changelog-max-entries"`
+	InitialVersion       string                   `json:"initial-version"`
+	CommitParser         *CommitParser            `json:"commit-parser"`
+	ScopeAliases         map[string]string        `json:"scope-aliases"`
+	IgnoreFiles          []string                 `json:"ignore-files"`
+	FreezeWindows        []freezeWindowConfig     `json:"freeze-windows"`
+	AllowMissingVersions bool                     `json:"allow-missing-versions"`
+	VersionSource        string                   `json:"version-source"`
+	MergeCommits         string                   `json:"merge-commits"`
+	TagCollision         string                   `json:"tag-collision"`
+	Dependencies         map[string][]string      `json:"dependencies"`
+	DiscussionCategory   *string                  `json:"discussion-category"`
+	Environments         []*Environment           `json:"environments"`
 }
 
 type packageConfig struct {
-	Component     string `json:"component"`
-	ChangelogPath string `json:"changelog-path"`
+	Component           string             `json:"component"`
+	ChangelogPath       string             `json:"changelog-path"`
+	ChangelogLayout     string             `json:"changelog-layout"`
+	ChangelogJSON       bool               `json:"changelog-json"`
+	VersionFile         *string            `json:"version-file"`
+	Versioning          string             `json:"versioning"`
+	PrereleaseSemantics string             `json:"prerelease-semantics"`
+	ExtraFiles          []*ExtraFile       `json:"extra-files"`
+	DockerfileArgs      []string           `json:"dockerfile-args"`
+	ExcludePaths        []string           `json:"exclude-paths"`
+	MinCommits          int                `json:"min-commits"`
+	ReleaseOnTypes      []string           `json:"release-on-types"`
+	ReleaseTitlePattern *string            `json:"release-title-pattern"`
+	ChangelogSections   []ChangelogSection `json:"changelog-sections"`
+	InitialVersion      string             `json:"initial-version"`
+}
+
+type branchConfig struct {
+	MaxBump string `json:"max-bump"`
 }
 
 type pluginConfig struct {
@@ -64,116 +761,859 @@ type pluginConfig struct {
 	Components []string `json:"components"`
 }
 
+// LoadOptions overrides where Load looks for the config and manifest.
+// Package paths in the config are always relative to the repo root.
+type LoadOptions struct {
+	// ConfigFile is an explicit config file, read on its own (no merging).
+	// Files ending in .yaml or .yml are read as native YAML, anything else
+	// as JSON. Relative paths are resolved against the repo root.
+	ConfigFile string
+
+	// ManifestFile is an explicit manifest. Defaults to
+	// release-please-manifest.json next to the config.
+	ManifestFile string
+
+	// SearchDir is where config discovery starts when ConfigFile is empty.
+	// Discovery walks up to the repo root and stops at the first directory
+	// containing release-please-config.json or .release-damnit.yaml.
+	// Empty means the repo root.
+	SearchDir string
+
+	// FS, if set, is where the config and manifest files are read from
+	// instead of the disk, with paths relative to the repo root; for a bare
+	// repository, the tree of the analyzed commit (see git.TreeFS).
+	FS fs.FS
+}
+
 // Load reads and parses the Release Please configuration from the given directory.
 // It expects release-please-config.json and release-please-manifest.json to exist.
 func Load(repoRoot string) (*Config, error) {
+	return LoadWithOptions(repoRoot, nil)
+}
+
+// LoadWithOptions is Load with custom config and manifest locations.
+// A nil opts behaves like Load.
+func LoadWithOptions(repoRoot string, opts *LoadOptions) (*Config, error) {
 	contracts.RequireNotEmpty(repoRoot, "repoRoot")
 
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+
 	absRoot, err := filepath.Abs(repoRoot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
 	}
 
-	configPath := filepath.Join(absRoot, "release-please-config.json")
-	manifestPath := filepath.Join(absRoot, "release-please-manifest.json")
+	// Read config files
+	files := fileReader{root: absRoot, fsys: opts.FS}
+	var doc map[string]interface{}
+	var configDir string
+	if opts.ConfigFile != "" {
+		configPath := resolvePath(absRoot, opts.ConfigFile)
+		configDir = filepath.Dir(configPath)
+		doc, err = loadFile(files, configPath)
+	} else {
+		configDir = discoverConfigDir(files, absRoot, resolvePath(absRoot, opts.SearchDir))
+		doc, err = loadDocument(files, configDir)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	// Read config file
-	configData, err := os.ReadFile(configPath)
+	manifestPath := filepath.Join(configDir, ManifestFile)
+	if opts.ManifestFile != "" {
+		manifestPath = resolvePath(absRoot, opts.ManifestFile)
+	}
+	manifestName, err := filepath.Rel(absRoot, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve manifest path: %w", err)
+	}
+	configData, err := json.Marshal(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read release-please-config.json: %w", err)
+		return nil, fmt.Errorf("failed to encode config: %w", err)
 	}
 
 	var rpConfig releasePleaseConfig
 	if err := json.Unmarshal(configData, &rpConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse release-please-config.json: %w", err)
+		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	// Read manifest file
-	manifestData, err := os.ReadFile(manifestPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read release-please-manifest.json: %w", err)
+	// Build config
+	config := &Config{
+		Packages:      make(map[string]*Package),
+		LinkedGroups:  make(map[string][]string),
+		RepoRoot:      absRoot,
+		ManifestPath:  filepath.ToSlash(manifestName),
+		VersionSource: VersionSourceManifest,
 	}
+	digest := sha256.Sum256(configData)
+	config.Digest = hex.EncodeToString(digest[:])
 
+	// Read manifest file, unless versions come from tags
 	var manifest map[string]string
-	if err := json.Unmarshal(manifestData, &manifest); err != nil {
-		return nil, fmt.Errorf("failed to parse release-please-manifest.json: %w", err)
+	switch rpConfig.VersionSource {
+	case "", VersionSourceManifest:
+		manifestData, err := files.readFile(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", manifestName, err)
+		}
+		if err := json.Unmarshal(manifestData, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", manifestName, err)
+		}
+	case VersionSourceTags:
+		config.VersionSource = VersionSourceTags
+		config.ManifestPath = ""
+	default:
+		return nil, fmt.Errorf("version-source must be %s or %s", VersionSourceManifest, VersionSourceTags)
 	}
 
-	// Build config
-	config := &Config{
-		Packages:     make(map[string]*Package),
-		LinkedGroups: make(map[string][]string),
-		RepoRoot:     absRoot,
+	// Validate notification targets
+	if rpConfig.Notifications != nil {
+		if err := validateNotifications(rpConfig.Notifications); err != nil {
+			return nil, err
+		}
+		config.Notifications = rpConfig.Notifications
+	}
+
+	// Validate telemetry exporters
+	if rpConfig.Telemetry != nil {
+		if err := validateTelemetry(rpConfig.Telemetry); err != nil {
+			return nil, err
+		}
+		config.Telemetry = rpConfig.Telemetry
+	}
+
+	// Validate Jira integration
+	if rpConfig.Jira != nil {
+		if rpConfig.Jira.BaseURL == "" {
+			return nil, fmt.Errorf("jira missing base-url")
+		}
+		if rpConfig.Jira.UpdatesIssues() && rpConfig.Jira.TokenEnv == "" {
+			return nil, fmt.Errorf("jira requires token-env when comment or transition-to is set")
+		}
+		config.Jira = rpConfig.Jira
+	}
+
+	// Validate hooks
+	if rpConfig.Hooks != nil {
+		for _, stage := range []string{HookPreApply, HookPostApply, HookPreRelease, HookPostRelease} {
+			for i, command := range rpConfig.Hooks.Commands(stage) {
+				if strings.TrimSpace(command) == "" {
+					return nil, fmt.Errorf("hooks.%s[%d] is empty", stage, i)
+				}
+			}
+		}
+		config.Hooks = rpConfig.Hooks
+	}
+
+	// Validate remotes
+	if rpConfig.Remotes != nil {
+		for i, remote := range rpConfig.Remotes.Mirrors {
+			if strings.TrimSpace(remote) == "" {
+				return nil, fmt.Errorf("remotes.mirrors[%d] is empty", i)
+			}
+		}
+		config.Remotes = rpConfig.Remotes
+	}
+
+	// Validate approval
+	if rpConfig.Approval != nil {
+		approval := rpConfig.Approval
+		approval.Label = strings.TrimSpace(approval.Label)
+		if approval.Label == "" {
+			approval.Label = DefaultApprovalLabel
+		}
+		approval.Command = strings.TrimSpace(approval.Command)
+		if approval.Command == "" {
+			approval.Command = DefaultApprovalCommand
+		}
+		for i, login := range approval.Approvers {
+			if strings.TrimSpace(login) == "" {
+				return nil, fmt.Errorf("approval.approvers[%d] is empty", i)
+			}
+		}
+		config.Approval = approval
+	}
+
+	// Validate branches
+	if len(rpConfig.Branches) > 0 {
+		config.Branches = make(map[string]*Branch, len(rpConfig.Branches))
+		for pattern, branchCfg := range rpConfig.Branches {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("branches.%s: invalid pattern: %w", pattern, err)
+			}
+			branch := &Branch{}
+			if branchCfg.MaxBump != "" {
+				bt, err := version.ParseBumpType(branchCfg.MaxBump)
+				if err != nil || bt == version.None {
+					return nil, fmt.Errorf("branches.%s: max-bump must be patch, minor, or major", pattern)
+				}
+				branch.MaxBump = bt
+			}
+			config.Branches[pattern] = branch
+		}
+	}
+
+	// Validate bump rules
+	if len(rpConfig.BumpRules) > 0 {
+		config.BumpRules = make(map[string]version.BumpType, len(rpConfig.BumpRules))
+		for commitType, bump := range rpConfig.BumpRules {
+			bt, err := version.ParseBumpType(bump)
+			if err != nil {
+				return nil, fmt.Errorf("bump-rules.%s: %w", commitType, err)
+			}
+			config.BumpRules[commitType] = bt
+		}
+	}
+
+	if problems := validateChangelogSections("changelog-sections", rpConfig.ChangelogSections); len(problems) > 0 {
+		return nil, fmt.Errorf("%s", problems[0])
+	}
+	if s := rpConfig.ChangelogSort; s != "" && s != ChangelogSortScope {
+		return nil, fmt.Errorf("changelog-sort must be %q, got %q", ChangelogSortScope, s)
+	}
+	if rpConfig.ChangelogMaxEntries < 0 {
+		return nil, fmt.Errorf("changelog-max-entries must not be negative")
+	}
+	if v := rpConfig.InitialVersion; v != "" {
+		if _, err := version.Parse(v); err != nil {
+			return nil, fmt.Errorf("initial-version: %w", err)
+		}
+	}
+
+	// Validate ignored file patterns
+	for i, pattern := range rpConfig.IgnoreFiles {
+		if !validGlob(pattern) {
+			return nil, fmt.Errorf("ignore-files[%d]: invalid pattern %q", i, pattern)
+		}
+	}
+	config.IgnoreFiles = rpConfig.IgnoreFiles
+
+	// Validate scope aliases
+	if len(rpConfig.ScopeAliases) > 0 {
+		aliases, err := buildScopeAliases(rpConfig.ScopeAliases)
+		if err != nil {
+			return nil, err
+		}
+		config.ScopeAliases = aliases
+	}
+
+	// Validate freeze windows
+	for i, raw := range rpConfig.FreezeWindows {
+		window, err := buildFreezeWindow(&raw)
+		if err != nil {
+			return nil, fmt.Errorf("freeze-windows[%d]: %w", i, err)
+		}
+		config.FreezeWindows = append(config.FreezeWindows, window)
+	}
+
+	config.IncludeCommitBody = rpConfig.IncludeCommitBody
+	config.SubmoduleLog = rpConfig.SubmoduleLog
+	config.SummarizeBinaryFiles = rpConfig.SummarizeBinaryFiles
+	config.IgnoreBinaryFiles = rpConfig.IgnoreBinaryFiles
+
+	// Validate merge commit mode
+	switch rpConfig.MergeCommits {
+	case "":
+		config.MergeCommits = MergeCommitsIgnore
+	case MergeCommitsIgnore, MergeCommitsInclude, MergeCommitsOnly:
+		config.MergeCommits = rpConfig.MergeCommits
+	default:
+		return nil, fmt.Errorf("merge-commits must be %s, %s, or %s", MergeCommitsIgnore, MergeCommitsInclude, MergeCommitsOnly)
+	}
+
+	// Validate tag collision policy
+	switch rpConfig.TagCollision {
+	case "":
+		config.TagCollision = TagCollisionError
+	case TagCollisionError, TagCollisionSkip, TagCollisionBumpAgain:
+		config.TagCollision = rpConfig.TagCollision
+	default:
+		return nil, fmt.Errorf("tag-collision must be %s, %s, or %s", TagCollisionError, TagCollisionSkip, TagCollisionBumpAgain)
+	}
+
+	// Validate commit parser options
+	if rpConfig.CommitParser != nil {
+		if _, err := git.NewParser(rpConfig.CommitParser.ParserOptions()); err != nil {
+			return nil, fmt.Errorf("commit-parser: %w", err)
+		}
+		config.CommitParser = rpConfig.CommitParser
+	}
+
+	if rpConfig.DiscussionCategory != nil {
+		config.DiscussionCategory = strings.TrimSpace(*rpConfig.DiscussionCategory)
+		if config.DiscussionCategory == "" {
+			return nil, fmt.Errorf("discussion-category must name a GitHub Discussions category")
+		}
 	}
 
-	// Build linked groups lookup (component name -> group name)
-	componentToGroup := make(map[string]string)
-	for _, plugin := range rpConfig.Plugins {
-		if plugin.Type == "linked-versions" {
-			config.LinkedGroups[plugin.GroupName] = plugin.Components
-			for _, comp := range plugin.Components {
-				componentToGroup[comp] = plugin.GroupName
+	// Validate environments
+	envNames := make(map[string]bool)
+	for i, env := range rpConfig.Environments {
+		env.Name = strings.TrimSpace(env.Name)
+		switch {
+		case env.Name == "":
+			return nil, fmt.Errorf("environments[%d] has no name", i)
+		case envNames[env.Name]:
+			return nil, fmt.Errorf("environments[%d]: environment %q is listed twice", i, env.Name)
+		case strings.TrimSpace(env.Path) == "" || filepath.IsAbs(env.Path) || !filepath.IsLocal(env.ManifestPath("x")):
+			return nil, fmt.Errorf("environment %s path %q must be a path inside the repository", env.Name, env.Path)
+		case strings.TrimSpace(env.JSONPath) == "":
+			return nil, fmt.Errorf("environment %s needs a jsonpath locating the version in %s", env.Name, env.Path)
+		}
+		for _, field := range []string{env.Path, env.JSONPath} {
+			for _, m := range titlePlaceholderRegex.FindAllStringSubmatch(field, -1) {
+				if m[1] != "component" {
+					return nil, fmt.Errorf("environment %s has unknown placeholder ${%s}; use ${component}", env.Name, m[1])
+				}
 			}
 		}
+		envNames[env.Name] = true
+	}
+	config.Environments = rpConfig.Environments
+
+	// Validate release commit pattern
+	config.ReleaseCommitPattern = defaultReleaseCommitRegex
+	if rpConfig.ReleaseCommitPattern != nil {
+		re, err := regexp.Compile(*rpConfig.ReleaseCommitPattern)
+		if err != nil {
+			return nil, fmt.Errorf("release-commit-pattern: %w", err)
+		}
+		config.ReleaseCommitPattern = re
+	}
+
+	// Build linked groups lookup (component name -> outermost group name)
+	componentNames := make(map[string]bool)
+	for _, pkgConfig := range rpConfig.Packages {
+		componentNames[pkgConfig.Component] = true
 	}
+	linked, componentToGroup, linkedProblems := linkedGroups(rpConfig.Plugins, componentNames)
+	config.LinkedGroups = linked
+
+	// Build packages in key order so problems are reported deterministically
+	keys := make([]string, 0, len(rpConfig.Packages))
+	for key := range rpConfig.Packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	pathKeys := make(map[string]string)       // normalized path -> config key
+	componentPaths := make(map[string]string) // component -> package path
+	for _, key := range keys {
+		pkgConfig := rpConfig.Packages[key]
 
-	// Build packages
-	for path, pkgConfig := range rpConfig.Packages {
 		// Normalize path (remove leading ./ or trailing /)
-		path = normalizePath(path)
+		path := normalizePath(key)
+		if other, ok := pathKeys[path]; ok {
+			problems = append(problems, fmt.Sprintf("packages %q and %q are the same path %s; merge them into one entry", other, key, path))
+			continue
+		}
+		pathKeys[path] = key
+
+		currentVersion, ok := manifest[path]
+		if !ok {
+			currentVersion, ok = manifest[key]
+		}
+		if !ok && config.VersionSource == VersionSourceManifest {
+			if rpConfig.AllowMissingVersions {
+				currentVersion = "0.0.0"
+			} else {
+				problems = append(problems, fmt.Sprintf("package %s has no version in %s; add one (e.g. \"%s\": \"0.0.0\") or set allow-missing-versions", path, manifestName, path))
+			}
+		}
 
 		pkg := &Package{
-			Path:           path,
-			Component:      pkgConfig.Component,
-			ChangelogPath:  pkgConfig.ChangelogPath,
-			CurrentVersion: manifest[path],
-			LinkedGroup:    componentToGroup[pkgConfig.Component],
+			Path:                path,
+			Component:           pkgConfig.Component,
+			ChangelogPath:       pkgConfig.ChangelogPath,
+			ChangelogJSON:       pkgConfig.ChangelogJSON,
+			CurrentVersion:      currentVersion,
+			LinkedGroup:         componentToGroup[pkgConfig.Component],
+			Versioning:          pkgConfig.Versioning,
+			ExtraFiles:          pkgConfig.ExtraFiles,
+			DockerfileArgs:      pkgConfig.DockerfileArgs,
+			PrereleaseSemantics: pkgConfig.PrereleaseSemantics,
+			MinCommits:          pkgConfig.MinCommits,
+		}
+		pkg.ChangelogSections = DefaultChangelogSections
+		if rpConfig.ChangelogSections != nil {
+			pkg.ChangelogSections = rpConfig.ChangelogSections
+		}
+		if pkgConfig.ChangelogSections != nil {
+			pkg.ChangelogSections = pkgConfig.ChangelogSections
+			problems = append(problems, validateChangelogSections("package "+path+" changelog-sections", pkgConfig.ChangelogSections)...)
+		}
+		pkg.ChangelogSort = rpConfig.ChangelogSort
+		pkg.InitialVersion = rpConfig.InitialVersion
+		if v := pkgConfig.InitialVersion; v != "" {
+			pkg.InitialVersion = v
+			if _, err := version.Parse(v); err != nil {
+				problems = append(problems, fmt.Sprintf("package %s initial-version: %v", path, err))
+			}
+		}
+		pkg.ChangelogMaxEntries = rpConfig.ChangelogMaxEntries
+		pkg.Jira = rpConfig.Jira
+		for _, commitType := range pkgConfig.ReleaseOnTypes {
+			pkg.ReleaseOnTypes = append(pkg.ReleaseOnTypes, strings.ToLower(strings.TrimSpace(commitType)))
+		}
+		for _, exclude := range pkgConfig.ExcludePaths {
+			pkg.ExcludePaths = append(pkg.ExcludePaths, normalizePath(exclude))
 		}
 
-		// Default changelog path
-		if pkg.ChangelogPath == "" {
-			pkg.ChangelogPath = "CHANGELOG.md"
+		// Default changelog layout and path
+		switch pkgConfig.ChangelogLayout {
+		case "", ChangelogLayoutFile:
+			pkg.ChangelogLayout = ChangelogLayoutFile
+			if pkg.ChangelogPath == "" {
+				pkg.ChangelogPath = "CHANGELOG.md"
+			}
+		case ChangelogLayoutDirectory:
+			pkg.ChangelogLayout = ChangelogLayoutDirectory
+			if pkg.ChangelogPath == "" {
+				pkg.ChangelogPath = DefaultChangelogDir
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("package %s changelog-layout must be %s or %s", path, ChangelogLayoutFile, ChangelogLayoutDirectory))
+		}
+
+		// Default version file; "none" opts out
+		pkg.VersionFile = DefaultVersionFile
+		if pkgConfig.VersionFile != nil {
+			switch versionFile := strings.TrimSpace(*pkgConfig.VersionFile); {
+			case versionFile == noVersionFile:
+				pkg.VersionFile = ""
+			case versionFile == "" || filepath.IsAbs(versionFile) || !filepath.IsLocal(versionFile):
+				problems = append(problems, fmt.Sprintf("package %s version-file %q must be a path inside the package, or %q", path, *pkgConfig.VersionFile, noVersionFile))
+			default:
+				pkg.VersionFile = filepath.ToSlash(filepath.Clean(versionFile))
+			}
 		}
 
 		// Validate
 		if pkg.Component == "" {
-			return nil, fmt.Errorf("package %s missing component name", path)
+			problems = append(problems, fmt.Sprintf("package %s missing component name", path))
+		} else if other, ok := componentPaths[pkg.Component]; ok {
+			problems = append(problems, fmt.Sprintf("component %q is used by packages %s and %s; component names must be unique", pkg.Component, other, path))
+		} else {
+			componentPaths[pkg.Component] = path
+		}
+		for i, extra := range pkg.ExtraFiles {
+			if extra.Path == "" {
+				problems = append(problems, fmt.Sprintf("package %s extra-files[%d] missing path", path, i))
+			}
+		}
+		for i, exclude := range pkg.ExcludePaths {
+			if exclude == RootPath {
+				problems = append(problems, fmt.Sprintf("package %s exclude-paths[%d] excludes the whole repo", path, i))
+			}
+		}
+		if pkg.PrereleaseSemantics != "" && pkg.PrereleaseSemantics != PrereleaseStrictZero {
+			problems = append(problems, fmt.Sprintf("package %s prerelease-semantics must be %s", path, PrereleaseStrictZero))
+		}
+		if pkg.MinCommits < 0 {
+			problems = append(problems, fmt.Sprintf("package %s min-commits must not be negative", path))
+		}
+		if pkg.MinCommits > 0 && pkg.LinkedGroup != "" {
+			problems = append(problems, fmt.Sprintf("package %s min-commits can't be used with linked-versions group %q", path, pkg.LinkedGroup))
+		}
+		if pkgConfig.ReleaseTitlePattern != nil {
+			pkg.ReleaseTitlePattern = strings.TrimSpace(*pkgConfig.ReleaseTitlePattern)
+			if pkg.ReleaseTitlePattern == "" {
+				problems = append(problems, fmt.Sprintf("package %s release-title-pattern is empty", path))
+			}
+			for _, m := range titlePlaceholderRegex.FindAllStringSubmatch(pkg.ReleaseTitlePattern, -1) {
+				if !slices.Contains(ReleaseTitlePlaceholders, m[1]) {
+					problems = append(problems, fmt.Sprintf("package %s release-title-pattern has unknown placeholder ${%s}; use ${%s}", path, m[1], strings.Join(ReleaseTitlePlaceholders, "}, ${")))
+				}
+			}
+		}
+		if len(pkg.ReleaseOnTypes) > 0 && pkg.MinCommits == 0 {
+			problems = append(problems, fmt.Sprintf("package %s release-on-types requires min-commits", path))
+		}
+		for i, commitType := range pkg.ReleaseOnTypes {
+			if commitType == "" {
+				problems = append(problems, fmt.Sprintf("package %s release-on-types[%d] is empty", path, i))
+			}
 		}
 
 		config.Packages[path] = pkg
 	}
 
+	// Linked groups may only name configured components and other groups
+	problems = append(problems, linkedProblems...)
+
+	// Dependencies may only name configured components
+	dependents := make([]string, 0, len(rpConfig.Dependencies))
+	for component := range rpConfig.Dependencies {
+		dependents = append(dependents, component)
+	}
+	sort.Strings(dependents)
+	for _, component := range dependents {
+		if !componentNames[component] {
+			problems = append(problems, fmt.Sprintf("dependencies references unknown component %q", component))
+			continue
+		}
+		for _, dep := range rpConfig.Dependencies[component] {
+			switch {
+			case !componentNames[dep]:
+				problems = append(problems, fmt.Sprintf("dependencies of %q references unknown component %q", component, dep))
+			case dep == component:
+				problems = append(problems, fmt.Sprintf("component %q depends on itself", component))
+			}
+		}
+	}
+	config.Dependencies = rpConfig.Dependencies
+
+	if len(problems) > 0 {
+		return nil, &IntegrityError{Problems: problems}
+	}
+
+	config.pathIndex = newPathTrie(config.Packages)
+
 	contracts.Ensure(config.Packages != nil, "packages map must be initialized")
 	contracts.Ensure(config.RepoRoot != "", "repo root must be set")
 
 	return config, nil
 }
 
+// IntegrityError reports every problem found in the packages and linked
+// groups of a config, so they can all be fixed in one pass.
+type IntegrityError struct {
+	Problems []string
+}
+
+func (e *IntegrityError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("%d config problems:\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// resolvePath returns path as an absolute path, resolving relative paths
+// against repoRoot. An empty path resolves to repoRoot.
+func resolvePath(repoRoot, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(repoRoot, path)
+}
+
+// fileReader reads the config's files from the disk, or from
+// LoadOptions.FS with paths made relative to root.
+type fileReader struct {
+	root string
+	fsys fs.FS
+}
+
+// readFile reads the file at an absolute path.
+func (r fileReader) readFile(path string) ([]byte, error) {
+	if r.fsys == nil {
+		return os.ReadFile(path)
+	}
+	rel, err := filepath.Rel(r.root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return fs.ReadFile(r.fsys, filepath.ToSlash(rel))
+}
+
+// exists reports whether a file exists at an absolute path.
+func (r fileReader) exists(path string) bool {
+	if r.fsys == nil {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	_, err := r.readFile(path)
+	return err == nil
+}
+
+// discoverConfigDir walks up from dir to repoRoot and returns the first
+// directory containing a config file. Falls back to repoRoot when dir is
+// outside the repo or nothing is found, so the missing-file error names the
+// repo root.
+func discoverConfigDir(files fileReader, repoRoot, dir string) string {
+	rel, err := filepath.Rel(repoRoot, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return repoRoot
+	}
+	for {
+		for _, name := range []string{ReleasePleaseConfigFile, NativeConfigFile} {
+			if files.exists(filepath.Join(dir, name)) {
+				return dir
+			}
+		}
+		if dir == repoRoot {
+			return repoRoot
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// loadFile reads a single explicitly named config file, choosing the format
+// by extension.
+func loadFile(files fileReader, path string) (map[string]interface{}, error) {
+	name := filepath.Base(path)
+	data, err := files.readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return doc, nil
+	}
+
+	parsed, err := yaml.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	doc, ok := parsed.(map[string]interface{})
+	if parsed != nil && !ok {
+		return nil, fmt.Errorf("failed to parse %s: top level must be a mapping", name)
+	}
+	return doc, nil
+}
+
+// loadDocument reads release-please-config.json and .release-damnit.yaml in dir
+// as generic JSON values and merges them. At least one must exist.
+func loadDocument(files fileReader, dir string) (map[string]interface{}, error) {
+	var base map[string]interface{}
+	jsonData, jsonErr := files.readFile(filepath.Join(dir, ReleasePleaseConfigFile))
+	if jsonErr == nil {
+		if err := json.Unmarshal(jsonData, &base); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ReleasePleaseConfigFile, err)
+		}
+	} else if !os.IsNotExist(jsonErr) {
+		return nil, fmt.Errorf("failed to read %s: %w", ReleasePleaseConfigFile, jsonErr)
+	}
+
+	yamlData, err := files.readFile(filepath.Join(dir, NativeConfigFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", NativeConfigFile, err)
+		}
+		if jsonErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", ReleasePleaseConfigFile, jsonErr)
+		}
+		return base, nil
+	}
+
+	parsed, err := yaml.Unmarshal(yamlData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", NativeConfigFile, err)
+	}
+	overlay, ok := parsed.(map[string]interface{})
+	if parsed != nil && !ok {
+		return nil, fmt.Errorf("failed to parse %s: top level must be a mapping", NativeConfigFile)
+	}
+
+	return mergeDocuments(base, overlay), nil
+}
+
+// mergeDocuments deep-merges overlay into base. Mappings are merged key by key
+// (so packages from both files combine); any other overlay value replaces the
+// base value, including lists.
+func mergeDocuments(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		overlayMap, overlayIsMap := v.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merged[k] = mergeDocuments(baseMap, overlayMap)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// Migrate converts release-please-config.json in repoRoot to the native YAML
+// format. The Release Please $schema reference is dropped.
+func Migrate(repoRoot string) (string, error) {
+	contracts.RequireNotEmpty(repoRoot, "repoRoot")
+
+	data, err := os.ReadFile(filepath.Join(repoRoot, ReleasePleaseConfigFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", ReleasePleaseConfigFile, err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", ReleasePleaseConfigFile, err)
+	}
+	delete(doc, "$schema")
+
+	return fmt.Sprintf("# release-damnit configuration (migrated from %s)\n%s", ReleasePleaseConfigFile, yaml.Marshal(doc)), nil
+}
+
+// BumpFor returns the version bump for a conventional commit type, applying
+// bump-rules overrides before the default mapping.
+func (c *Config) BumpFor(commitType string) version.BumpType {
+	if bt, ok := c.BumpRules[commitType]; ok {
+		return bt
+	}
+	return version.CommitTypeToBump(commitType)
+}
+
+// IsReleaseCommit reports whether a commit subject is a release commit. An
+// empty release-commit-pattern disables the check.
+func (c *Config) IsReleaseCommit(subject string) bool {
+	re := c.ReleaseCommitPattern
+	if re == nil {
+		re = defaultReleaseCommitRegex
+	}
+	return re.String() != "" && re.MatchString(subject)
+}
+
+// IsIgnoredFile reports whether a file path matches an ignore-files
+// pattern. "**" matches any number of directories, and a pattern without a
+// slash matches the file name in any directory.
+func (c *Config) IsIgnoredFile(filePath string) bool {
+	filePath = normalizePath(filePath)
+	for _, pattern := range c.IgnoreFiles {
+		if globMatch(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeScope returns the canonical form of a commit scope: the target of
+// a matching scope-aliases entry, or the name of the component it matches,
+// ignoring case. Other scopes are returned unchanged.
+func (c *Config) NormalizeScope(scope string) string {
+	if scope == "" {
+		return ""
+	}
+	if target, ok := c.ScopeAliases[strings.ToLower(scope)]; ok {
+		return target
+	}
+	for _, pkg := range c.Packages {
+		if strings.EqualFold(pkg.Component, scope) {
+			return pkg.Component
+		}
+	}
+	return scope
+}
+
+// BranchFor returns the configuration for a branch name, or nil if no
+// pattern matches. An exact name wins over globs; among globs, the
+// lexically first matching pattern wins so the choice is deterministic.
+func (c *Config) BranchFor(name string) *Branch {
+	if name == "" {
+		return nil
+	}
+	if branch, ok := c.Branches[name]; ok {
+		return branch
+	}
+
+	patterns := make([]string, 0, len(c.Branches))
+	for pattern := range c.Branches {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return c.Branches[pattern]
+		}
+	}
+	return nil
+}
+
 // FindPackageForPath returns the package that owns a given file path.
-// Uses deepest-match-wins logic for nested packages.
-// Returns nil if no package matches.
+// Uses deepest-match-wins logic for nested packages; a root package (".")
+// has the lowest precedence. Files under a package's exclude-paths don't
+// match it. Returns nil if no package matches.
 func (c *Config) FindPackageForPath(filePath string) *Package {
 	contracts.RequireNotEmpty(filePath, "filePath")
 
 	// Normalize the input path
 	filePath = normalizePath(filePath)
 
-	var bestMatch *Package
-	var bestMatchLen int
+	if c.pathIndex == nil {
+		return c.findPackageLinear(filePath)
+	}
+	return c.pathIndex.find(filePath)
+}
 
+// findPackageLinear is FindPackageForPath for configs without a path index:
+// it checks every package for the deepest owner of a normalized file path.
+func (c *Config) findPackageLinear(filePath string) *Package {
+	var bestMatch *Package
+	bestMatchLen := -1
 	for path, pkg := range c.Packages {
-		// Check if the file path starts with this package path
-		if strings.HasPrefix(filePath, path+"/") || filePath == path {
-			if len(path) > bestMatchLen {
-				bestMatch = pkg
-				bestMatchLen = len(path)
-			}
+		if !pkg.owns(filePath) {
+			continue
+		}
+		matchLen := len(path)
+		if path == RootPath {
+			matchLen = 0
+		}
+		if matchLen > bestMatchLen {
+			bestMatch = pkg
+			bestMatchLen = matchLen
 		}
 	}
-
 	return bestMatch
 }
 
+// owns reports whether a normalized file path is inside the package and not
+// excluded from it.
+func (p *Package) owns(filePath string) bool {
+	if p.Path != RootPath && !isWithin(filePath, p.Path) {
+		return false
+	}
+	for _, exclude := range p.ExcludePaths {
+		if isWithin(filePath, exclude) {
+			return false
+		}
+	}
+	return true
+}
+
+// Pathspecs returns git pathspecs matching the files FindPackageForPath
+// assigns to pkg, less ignore-files: the package's directory minus nested
+// packages and exclude-paths. Files under an exclude-path of a nested
+// package fall to pkg in FindPackageForPath, but aren't matched here.
+func (c *Config) Pathspecs(pkg *Package) []string {
+	contracts.RequireNotNil(pkg, "pkg")
+
+	var specs []string
+	if pkg.Path == RootPath {
+		specs = append(specs, ":(literal).")
+	} else {
+		specs = append(specs, ":(literal)"+pkg.Path)
+	}
+	for _, other := range c.PackagesSortedByPath() {
+		if other != pkg && other.Path != RootPath && pkg.owns(other.Path) {
+			specs = append(specs, ":(exclude,literal)"+other.Path)
+		}
+	}
+	for _, exclude := range pkg.ExcludePaths {
+		specs = append(specs, ":(exclude,literal)"+exclude)
+	}
+	for _, pattern := range c.IgnoreFiles {
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		specs = append(specs, ":(exclude,glob)"+pattern)
+	}
+	return specs
+}
+
+// isWithin reports whether filePath is dir or a path below it.
+func isWithin(filePath, dir string) bool {
+	return filePath == dir || strings.HasPrefix(filePath, dir+"/")
+}
+
 // GetLinkedPackages returns all packages in the same linked group as the given package.
 // Returns just the package itself if it's not in a linked group.
 func (c *Config) GetLinkedPackages(pkg *Package) []*Package {
@@ -202,6 +1642,78 @@ func (c *Config) GetLinkedPackages(pkg *Package) []*Package {
 	return result
 }
 
+// PackageForComponent returns the package with the given component name,
+// or nil if there is none.
+func (c *Config) PackageForComponent(component string) *Package {
+	for _, pkg := range c.Packages {
+		if pkg.Component == component {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// Dependents returns the components that depend on the given one, sorted.
+func (c *Config) Dependents(component string) []string {
+	var result []string
+	for dependent, deps := range c.Dependencies {
+		if slices.Contains(deps, component) {
+			result = append(result, dependent)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ScopeTo drops the packages outside a path prefix (e.g. "workloads/jarvis"
+// or "workloads/jarvis/..."), so analysis only sees that slice of the
+// monorepo. Linked groups stay whole: members outside the prefix are kept
+// with the rest of their group. Dependencies on or of dropped packages are
+// dropped too. Returns an error if no package is under the prefix.
+func (c *Config) ScopeTo(prefix string) error {
+	scope := normalizePath(strings.TrimSuffix(prefix, "..."))
+
+	inScope := func(pkg *Package) bool {
+		return scope == RootPath || pkg.Path == scope || strings.HasPrefix(pkg.Path, scope+"/")
+	}
+	groups := make(map[string]bool)
+	var matched int
+	for _, pkg := range c.Packages {
+		if inScope(pkg) {
+			matched++
+			if pkg.LinkedGroup != "" {
+				groups[pkg.LinkedGroup] = true
+			}
+		}
+	}
+	if matched == 0 {
+		return fmt.Errorf("no packages under %s", scope)
+	}
+
+	components := make(map[string]bool)
+	for path, pkg := range c.Packages {
+		if !inScope(pkg) && !groups[pkg.LinkedGroup] {
+			delete(c.Packages, path)
+			continue
+		}
+		components[pkg.Component] = true
+	}
+	for dependent, deps := range c.Dependencies {
+		if !components[dependent] {
+			delete(c.Dependencies, dependent)
+			continue
+		}
+		deps = slices.DeleteFunc(deps, func(dep string) bool { return !components[dep] })
+		if len(deps) == 0 {
+			delete(c.Dependencies, dependent)
+			continue
+		}
+		c.Dependencies[dependent] = deps
+	}
+	c.pathIndex = newPathTrie(c.Packages)
+	return nil
+}
+
 // PackagesSortedByPath returns all packages sorted by path.
 // Useful for deterministic output.
 func (c *Config) PackagesSortedByPath() []*Package {
@@ -215,13 +1727,118 @@ func (c *Config) PackagesSortedByPath() []*Package {
 	return result
 }
 
-// normalizePath cleans up a path for consistent comparison.
+// validateNotifications checks that every notification target is usable.
+func validateNotifications(n *Notifications) error {
+	for i, hook := range n.Webhooks {
+		if hook == nil || hook.URL == "" {
+			return fmt.Errorf("notifications.webhooks[%d] missing url", i)
+		}
+		if err := validateFilter(hook.NotificationFilter); err != nil {
+			return fmt.Errorf("notifications.webhooks[%d]: %w", i, err)
+		}
+	}
+	for i, slack := range n.Slack {
+		if slack == nil || (slack.WebhookURL == "" && slack.TokenEnv == "") {
+			return fmt.Errorf("notifications.slack[%d] requires webhook-url or token-env", i)
+		}
+		if slack.WebhookURL == "" && slack.Channel == "" {
+			return fmt.Errorf("notifications.slack[%d] requires channel when using token-env", i)
+		}
+		if err := validateFilter(slack.NotificationFilter); err != nil {
+			return fmt.Errorf("notifications.slack[%d]: %w", i, err)
+		}
+	}
+	if err := validateChatWebhooks("discord", n.Discord); err != nil {
+		return err
+	}
+	return validateChatWebhooks("teams", n.Teams)
+}
+
+// validateChatWebhooks checks a list of webhook-only chat targets.
+func validateChatWebhooks(name string, hooks []*ChatWebhook) error {
+	for i, hook := range hooks {
+		if hook == nil || hook.WebhookURL == "" {
+			return fmt.Errorf("notifications.%s[%d] missing webhook-url", name, i)
+		}
+		if err := validateFilter(hook.NotificationFilter); err != nil {
+			return fmt.Errorf("notifications.%s[%d]: %w", name, i, err)
+		}
+	}
+	return nil
+}
+
+// validateTelemetry checks that every telemetry exporter is usable and
+// fills in defaults.
+func validateTelemetry(t *Telemetry) error {
+	if t.Pushgateway != nil {
+		if t.Pushgateway.URL == "" {
+			return fmt.Errorf("telemetry.pushgateway missing url")
+		}
+		if t.Pushgateway.Job == "" {
+			t.Pushgateway.Job = DefaultPushgatewayJob
+		}
+		for label := range t.Pushgateway.Grouping {
+			if label == "" || label == "job" {
+				return fmt.Errorf("telemetry.pushgateway.grouping has invalid label %q", label)
+			}
+		}
+	}
+	if t.OTLP != nil {
+		if t.OTLP.Endpoint == "" {
+			return fmt.Errorf("telemetry.otlp missing endpoint")
+		}
+		if t.OTLP.ServiceName == "" {
+			t.OTLP.ServiceName = DefaultOTLPServiceName
+		}
+	}
+	return nil
+}
+
+// buildScopeAliases validates scope-aliases and keys it by lowercased alias.
+// An alias can't point at another alias, so lookups need one step.
+func buildScopeAliases(raw map[string]string) (map[string]string, error) {
+	aliases := make(map[string]string, len(raw))
+	for alias, target := range raw {
+		key := strings.ToLower(strings.TrimSpace(alias))
+		if key == "" || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("scope-aliases has an empty alias or scope (%q: %q)", alias, target)
+		}
+		if _, dup := aliases[key]; dup {
+			return nil, fmt.Errorf("scope-aliases has %q more than once", key)
+		}
+		aliases[key] = strings.TrimSpace(target)
+	}
+	for alias, target := range aliases {
+		if _, chained := aliases[strings.ToLower(target)]; chained && strings.ToLower(target) != alias {
+			return nil, fmt.Errorf("scope-aliases.%s points at another alias, %q", alias, target)
+		}
+	}
+	return aliases, nil
+}
+
+// validateFilter checks that a notification filter only names known bump types.
+func validateFilter(f NotificationFilter) error {
+	for _, bt := range f.BumpTypes {
+		if bt != "major" && bt != "minor" && bt != "patch" {
+			return fmt.Errorf("invalid bump type %q", bt)
+		}
+	}
+	return nil
+}
+
+// normalizePath cleans up a path for consistent comparison. Backslashes,
+// as in paths written on Windows, become slashes like git's.
 func normalizePath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
 	// Remove leading ./
 	path = strings.TrimPrefix(path, "./")
 	// Remove trailing /
 	path = strings.TrimSuffix(path, "/")
 	// Remove leading /
 	path = strings.TrimPrefix(path, "/")
+	// The repo root itself ("", "./", "/")
+	if path == "" {
+		return RootPath
+	}
 	return path
 }
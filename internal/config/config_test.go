@@ -1,9 +1,16 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+
+	"github.com/dsswift/release-damnit/internal/version"
 )
 
 // createTestRepo creates a temporary directory with release-please config files.
@@ -16,163 +23,1408 @@ func createTestRepo(t *testing.T, configJSON, manifestJSON string) string {
 		t.Fatalf("failed to write config: %v", err)
 	}
 
-	if err := os.WriteFile(filepath.Join(dir, "release-please-manifest.json"), []byte(manifestJSON), 0644); err != nil {
-		t.Fatalf("failed to write manifest: %v", err)
+	if err := os.WriteFile(filepath.Join(dir, "release-please-manifest.json"), []byte(manifestJSON), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	return dir
+}
+
+// writeTestFile writes a file under dir, creating parent directories.
+func writeTestFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+
+	fullPath := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoad_BasicConfig(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/service-a": {
+				"component": "service-a",
+				"changelog-path": "CHANGELOG.md"
+			},
+			"workloads/service-b": {
+				"component": "service-b"
+			}
+		}
+	}`
+
+	manifestJSON := `{
+		"workloads/service-a": "1.2.3",
+		"workloads/service-b": "0.1.0"
+	}`
+
+	dir := createTestRepo(t, configJSON, manifestJSON)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Check packages
+	if len(cfg.Packages) != 2 {
+		t.Errorf("expected 2 packages, got %d", len(cfg.Packages))
+	}
+
+	// Check service-a
+	pkgA := cfg.Packages["workloads/service-a"]
+	if pkgA == nil {
+		t.Fatal("expected package workloads/service-a")
+	}
+	if pkgA.Component != "service-a" {
+		t.Errorf("expected component service-a, got %s", pkgA.Component)
+	}
+	if pkgA.CurrentVersion != "1.2.3" {
+		t.Errorf("expected version 1.2.3, got %s", pkgA.CurrentVersion)
+	}
+	if pkgA.ChangelogPath != "CHANGELOG.md" {
+		t.Errorf("expected changelog-path CHANGELOG.md, got %s", pkgA.ChangelogPath)
+	}
+
+	// Check service-b (default changelog path)
+	pkgB := cfg.Packages["workloads/service-b"]
+	if pkgB == nil {
+		t.Fatal("expected package workloads/service-b")
+	}
+	if pkgB.ChangelogPath != "CHANGELOG.md" {
+		t.Errorf("expected default changelog-path CHANGELOG.md, got %s", pkgB.ChangelogPath)
+	}
+}
+
+func TestLoad_LinkedVersions(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"},
+			"workloads/service-c": {"component": "service-c"}
+		},
+		"plugins": [
+			{
+				"type": "linked-versions",
+				"groupName": "services-ab",
+				"components": ["service-a", "service-b"]
+			}
+		]
+	}`
+
+	manifestJSON := `{
+		"workloads/service-a": "1.0.0",
+		"workloads/service-b": "1.0.0",
+		"workloads/service-c": "2.0.0"
+	}`
+
+	dir := createTestRepo(t, configJSON, manifestJSON)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Check linked groups
+	if len(cfg.LinkedGroups) != 1 {
+		t.Errorf("expected 1 linked group, got %d", len(cfg.LinkedGroups))
+	}
+
+	components := cfg.LinkedGroups["services-ab"]
+	if len(components) != 2 {
+		t.Errorf("expected 2 components in group, got %d", len(components))
+	}
+
+	// Check package linked group assignment
+	pkgA := cfg.Packages["workloads/service-a"]
+	if pkgA.LinkedGroup != "services-ab" {
+		t.Errorf("expected LinkedGroup services-ab, got %s", pkgA.LinkedGroup)
+	}
+
+	pkgC := cfg.Packages["workloads/service-c"]
+	if pkgC.LinkedGroup != "" {
+		t.Errorf("expected no LinkedGroup for service-c, got %s", pkgC.LinkedGroup)
+	}
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Error("expected error for missing config file")
+	}
+}
+
+func TestLoad_MissingManifestFile(t *testing.T) {
+	dir := t.TempDir()
+	configJSON := `{"packages": {}}`
+	os.WriteFile(filepath.Join(dir, "release-please-config.json"), []byte(configJSON), 0644)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Error("expected error for missing manifest file")
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "release-please-config.json"), []byte("not json"), 0644)
+	os.WriteFile(filepath.Join(dir, "release-please-manifest.json"), []byte("{}"), 0644)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLoad_MissingComponent(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/service-a": {}
+		}
+	}`
+	manifestJSON := `{}`
+
+	dir := createTestRepo(t, configJSON, manifestJSON)
+
+	_, err := Load(dir)
+	if err == nil {
+		t.Error("expected error for missing component")
+	}
+}
+
+func TestLoad_IntegrityProblems(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/a": {"component": "shared"},
+			"workloads/b": {"component": "shared"},
+			"./workloads/c/": {"component": "c"},
+			"workloads/c": {"component": "c2"},
+			"workloads/d": {"component": "d"}
+		},
+		"plugins": [
+			{"type": "linked-versions", "groupName": "suite", "components": ["shared", "ghost"]}
+		]
+	}`
+	manifest := `{"workloads/a": "1.0.0", "workloads/b": "1.0.0", "workloads/c": "1.0.0"}`
+	dir := createTestRepo(t, configJSON, manifest)
+
+	_, err := Load(dir)
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("expected IntegrityError, got %v", err)
+	}
+
+	wants := []string{
+		`"./workloads/c/" and "workloads/c" are the same path`,
+		`component "shared" is used by packages workloads/a and workloads/b`,
+		`package workloads/d has no version in release-please-manifest.json`,
+		`linked-versions group "suite" references unknown component "ghost"`,
+	}
+	if len(integrityErr.Problems) != len(wants) {
+		t.Fatalf("expected %d problems, got %d:\n%v", len(wants), len(integrityErr.Problems), err)
+	}
+	for _, want := range wants {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_AllowMissingVersions(t *testing.T) {
+	configJSON := `{
+		"packages": {"workloads/new": {"component": "new"}},
+		"allow-missing-versions": true
+	}`
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["workloads/new"].CurrentVersion; got != "0.0.0" {
+		t.Errorf("expected missing version to default to 0.0.0, got %q", got)
+	}
+}
+
+func TestLoad_VersionSourceTags(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, ReleasePleaseConfigFile, `{
+		"packages": {"workloads/api": {"component": "api"}},
+		"version-source": "tags"
+	}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed without a manifest: %v", err)
+	}
+	if cfg.VersionSource != VersionSourceTags {
+		t.Errorf("expected version source tags, got %q", cfg.VersionSource)
+	}
+	if cfg.ManifestPath != "" {
+		t.Errorf("expected no manifest path, got %q", cfg.ManifestPath)
+	}
+	if got := cfg.Packages["workloads/api"].CurrentVersion; got != "" {
+		t.Errorf("expected version to be left for analysis, got %q", got)
+	}
+
+	writeTestFile(t, dir, ReleasePleaseConfigFile, `{"packages": {}, "version-source": "git"}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for invalid version-source")
+	}
+}
+
+func TestLoad_VersionFile(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/a": {"component": "a"},
+			"workloads/b": {"component": "b", "version-file": "internal/version/version.go"},
+			"workloads/c": {"component": "c", "version-file": "none"}
+		}
+	}`
+	manifestJSON := `{"workloads/a": "1.0.0", "workloads/b": "1.0.0", "workloads/c": "1.0.0"}`
+	dir := createTestRepo(t, configJSON, manifestJSON)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := map[string]string{
+		"workloads/a": DefaultVersionFile,
+		"workloads/b": "internal/version/version.go",
+		"workloads/c": "",
+	}
+	for path, versionFile := range want {
+		if got := cfg.Packages[path].VersionFile; got != versionFile {
+			t.Errorf("%s: expected version file %q, got %q", path, versionFile, got)
+		}
+	}
+
+	for _, bad := range []string{"../VERSION", "/etc/VERSION", ""} {
+		configJSON := fmt.Sprintf(`{"packages": {"workloads/a": {"component": "a", "version-file": %q}}}`, bad)
+		dir := createTestRepo(t, configJSON, `{"workloads/a": "1.0.0"}`)
+		if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "version-file") {
+			t.Errorf("expected version-file error for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoad_ChangelogLayout(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/a": {"component": "a"},
+			"workloads/b": {"component": "b", "changelog-layout": "directory"},
+			"workloads/c": {"component": "c", "changelog-layout": "directory", "changelog-path": "docs/releases"}
+		}
+	}`
+	manifestJSON := `{"workloads/a": "1.0.0", "workloads/b": "1.0.0", "workloads/c": "1.0.0"}`
+	dir := createTestRepo(t, configJSON, manifestJSON)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := map[string][2]string{
+		"workloads/a": {ChangelogLayoutFile, "CHANGELOG.md"},
+		"workloads/b": {ChangelogLayoutDirectory, DefaultChangelogDir},
+		"workloads/c": {ChangelogLayoutDirectory, "docs/releases"},
+	}
+	for path, w := range want {
+		pkg := cfg.Packages[path]
+		if pkg.ChangelogLayout != w[0] || pkg.ChangelogPath != w[1] {
+			t.Errorf("%s: expected layout %q at %q, got %q at %q", path, w[0], w[1], pkg.ChangelogLayout, pkg.ChangelogPath)
+		}
+	}
+
+	dir = createTestRepo(t, `{"packages": {"workloads/a": {"component": "a", "changelog-layout": "split"}}}`, `{"workloads/a": "1.0.0"}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "changelog-layout") {
+		t.Errorf("expected changelog-layout error, got %v", err)
+	}
+}
+
+func TestLoad_Notifications(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"}
+		},
+		"notifications": {
+			"webhooks": [
+				{
+					"url": "https://hooks.example.com/release",
+					"headers": {"Authorization": "Bearer ${TOKEN}"},
+					"secret-env": "WEBHOOK_SECRET",
+					"components": ["service-a"],
+					"bump-types": ["major", "minor"]
+				}
+			]
+		}
+	}`
+
+	dir := createTestRepo(t, configJSON, `{"workloads/service-a": "1.0.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Notifications == nil || len(cfg.Notifications.Webhooks) != 1 {
+		t.Fatalf("expected 1 webhook, got %+v", cfg.Notifications)
+	}
+	hook := cfg.Notifications.Webhooks[0]
+	if hook.URL != "https://hooks.example.com/release" {
+		t.Errorf("unexpected url: %s", hook.URL)
+	}
+	if hook.SecretEnv != "WEBHOOK_SECRET" {
+		t.Errorf("unexpected secret-env: %s", hook.SecretEnv)
+	}
+	if len(hook.BumpTypes) != 2 || len(hook.Components) != 1 {
+		t.Errorf("unexpected filters: %+v", hook)
+	}
+}
+
+func TestLoad_InvalidNotifications(t *testing.T) {
+	tests := []struct {
+		name       string
+		configJSON string
+	}{
+		{"missing url", `{"packages": {}, "notifications": {"webhooks": [{}]}}`},
+		{"bad bump type", `{"packages": {}, "notifications": {"webhooks": [{"url": "https://x", "bump-types": ["huge"]}]}}`},
+		{"slack without target", `{"packages": {}, "notifications": {"slack": [{"channel": "#releases"}]}}`},
+		{"slack token without channel", `{"packages": {}, "notifications": {"slack": [{"token-env": "SLACK_TOKEN"}]}}`},
+		{"discord missing url", `{"packages": {}, "notifications": {"discord": [{}]}}`},
+		{"teams bad bump type", `{"packages": {}, "notifications": {"teams": [{"webhook-url": "https://x", "bump-types": ["nope"]}]}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.configJSON, `{}`)
+			if _, err := Load(dir); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestLoad_Telemetry(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"telemetry": {
+			"pushgateway": {"url": "http://pushgateway:9091", "grouping": {"repo": "acme/api"}},
+			"otlp": {"endpoint": "http://collector:4318", "headers": {"Authorization": "Bearer ${OTLP_TOKEN}"}}
+		}
+	}`
+
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Telemetry == nil || cfg.Telemetry.Pushgateway == nil || cfg.Telemetry.OTLP == nil {
+		t.Fatalf("expected pushgateway and otlp, got %+v", cfg.Telemetry)
+	}
+	push := cfg.Telemetry.Pushgateway
+	if push.URL != "http://pushgateway:9091" || push.Job != DefaultPushgatewayJob || push.Grouping["repo"] != "acme/api" {
+		t.Errorf("unexpected pushgateway: %+v", push)
+	}
+	otlp := cfg.Telemetry.OTLP
+	if otlp.ServiceName != DefaultOTLPServiceName || otlp.Headers["Authorization"] == "" {
+		t.Errorf("unexpected otlp: %+v", otlp)
+	}
+}
+
+func TestLoad_InvalidTelemetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		configJSON string
+	}{
+		{"pushgateway missing url", `{"packages": {}, "telemetry": {"pushgateway": {"job": "x"}}}`},
+		{"pushgateway job grouping", `{"packages": {}, "telemetry": {"pushgateway": {"url": "http://x", "grouping": {"job": "y"}}}}`},
+		{"otlp missing endpoint", `{"packages": {}, "telemetry": {"otlp": {}}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.configJSON, `{}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "telemetry") {
+				t.Errorf("expected telemetry error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_CommitParser(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}}`, `{}`)
+	yamlConfig := `commit-parser:
+  strip-prefixes:
+    - '\p{So}'
+    - '\[[A-Z]+-\d+\]'
+  scope-delimiters:
+    - "()"
+    - "[]"
+  case-sensitive: true
+`
+	if err := os.WriteFile(filepath.Join(dir, NativeConfigFile), []byte(yamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	opts := cfg.CommitParser.ParserOptions()
+	if opts == nil || len(opts.StripPrefixes) != 2 || opts.StripPrefixes[1] != `\[[A-Z]+-\d+\]` ||
+		len(opts.ScopeDelimiters) != 2 || !opts.CaseSensitive {
+		t.Errorf("unexpected parser options: %+v", opts)
+	}
+
+	var none *CommitParser
+	if none.ParserOptions() != nil {
+		t.Error("expected nil options for a nil commit parser")
+	}
+}
+
+func TestLoad_InvalidCommitParser(t *testing.T) {
+	tests := []struct {
+		name       string
+		configJSON string
+	}{
+		{"bad prefix", `{"packages": {}, "commit-parser": {"strip-prefixes": ["(unclosed"]}}`},
+		{"bad delimiter", `{"packages": {}, "commit-parser": {"scope-delimiters": ["<"]}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.configJSON, `{}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "commit-parser") {
+				t.Errorf("expected commit-parser error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_ScopeAliases(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/jarvis": {"component": "jarvis"},
+			"workloads/jarvis/clients/web": {"component": "jarvis-web"}
+		},
+		"scope-aliases": {"UI": "jarvis-web", "be": "jarvis", "docs": "docs"}
+	}`
+	dir := createTestRepo(t, configJSON, `{"workloads/jarvis": "0.1.0", "workloads/jarvis/clients/web": "0.1.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"ui":         "jarvis-web",
+		"Ui":         "jarvis-web",
+		"be":         "jarvis",
+		"Jarvis-Web": "jarvis-web",
+		"docs":       "docs",
+		"api":        "api",
+		"":           "",
+	}
+	for scope, want := range tests {
+		if got := cfg.NormalizeScope(scope); got != want {
+			t.Errorf("NormalizeScope(%q) = %q, want %q", scope, got, want)
+		}
+	}
+}
+
+func TestLoad_InvalidScopeAliases(t *testing.T) {
+	tests := []struct {
+		name       string
+		configJSON string
+	}{
+		{"empty target", `{"packages": {}, "scope-aliases": {"ui": ""}}`},
+		{"duplicate alias", `{"packages": {}, "scope-aliases": {"ui": "web", "UI": "web"}}`},
+		{"chained alias", `{"packages": {}, "scope-aliases": {"fe": "ui", "ui": "web"}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.configJSON, `{}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "scope-aliases") {
+				t.Errorf("expected scope-aliases error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_IgnoreFiles(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}, "ignore-files": ["**/package-lock.json", "gen/**"]}`, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	for file, want := range map[string]bool{
+		"workloads/jarvis/package-lock.json": true,
+		"./gen/api.go":                       true,
+		"workloads/jarvis/src/main.go":       false,
+	} {
+		if got := cfg.IsIgnoredFile(file); got != want {
+			t.Errorf("IsIgnoredFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "ignore-files": ["gen/[a-"]}`, `{}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "ignore-files[0]") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestLoad_MinCommits(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {"tools/cli": {"component": "cli", "min-commits": 3, "release-on-types": ["Feat", "breaking"]}}}`, `{"tools/cli": "1.0.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	pkg := cfg.Packages["tools/cli"]
+	if pkg.MinCommits != 3 || len(pkg.ReleaseOnTypes) != 2 || pkg.ReleaseOnTypes[0] != "feat" {
+		t.Errorf("unexpected package: %+v", pkg)
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"negative", `{"packages": {"tools/cli": {"component": "cli", "min-commits": -1}}}`, "must not be negative"},
+		{"types without min-commits", `{"packages": {"tools/cli": {"component": "cli", "release-on-types": ["feat"]}}}`, "requires min-commits"},
+		{"empty type", `{"packages": {"tools/cli": {"component": "cli", "min-commits": 2, "release-on-types": [" "]}}}`, "release-on-types[0] is empty"},
+		{"linked", `{"packages": {"tools/cli": {"component": "cli", "min-commits": 2}, "tools/lib": {"component": "lib"}}, "plugins": [{"type": "linked-versions", "groupName": "tools", "components": ["cli", "lib"]}]}`, "linked-versions group"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.config, `{"tools/cli": "1.0.0", "tools/lib": "1.0.0"}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_PrereleaseSemantics(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {"tools/cli": {"component": "cli", "prerelease-semantics": "strict-zero"}, "tools/lib": {"component": "lib"}}}`, `{"tools/cli": "0.3.0", "tools/lib": "0.1.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["tools/cli"].PrereleaseSemantics; got != PrereleaseStrictZero {
+		t.Errorf("expected strict-zero, got %q", got)
+	}
+	if got := cfg.Packages["tools/lib"].PrereleaseSemantics; got != "" {
+		t.Errorf("expected the analyzer default, got %q", got)
+	}
+
+	dir = createTestRepo(t, `{"packages": {"tools/cli": {"component": "cli", "prerelease-semantics": "loose"}}}`, `{"tools/cli": "0.3.0"}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "prerelease-semantics must be strict-zero") {
+		t.Errorf("expected an invalid prerelease-semantics error, got %v", err)
+	}
+}
+
+func TestLoad_Jira(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"jira": {
+			"base-url": "https://acme.atlassian.net",
+			"projects": ["PROJ"],
+			"token-env": "JIRA_TOKEN",
+			"email-env": "JIRA_EMAIL",
+			"comment": true,
+			"transition-to": "Done"
+		}
+	}`
+
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Jira == nil || cfg.Jira.BaseURL != "https://acme.atlassian.net" {
+		t.Fatalf("unexpected jira config: %+v", cfg.Jira)
+	}
+	if !cfg.Jira.UpdatesIssues() {
+		t.Error("expected UpdatesIssues to be true")
+	}
+
+	// Links only: no token needed
+	dir = createTestRepo(t, `{"packages": {}, "jira": {"base-url": "https://x"}}`, `{}`)
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Jira.UpdatesIssues() {
+		t.Error("expected UpdatesIssues to be false for links-only config")
+	}
+
+	// Updates require a token
+	dir = createTestRepo(t, `{"packages": {}, "jira": {"base-url": "https://x", "comment": true}}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for comment without token-env")
+	}
+}
+
+func TestLoad_Hooks(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"hooks": {
+			"post-apply": ["npm install --package-lock-only"],
+			"pre-release": ["make dist"]
+		}
+	}`
+
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Hooks.Commands(HookPostApply); len(got) != 1 || got[0] != "npm install --package-lock-only" {
+		t.Errorf("unexpected post-apply hooks: %v", got)
+	}
+	if got := cfg.Hooks.Commands(HookPreApply); len(got) != 0 {
+		t.Errorf("expected no pre-apply hooks, got %v", got)
+	}
+
+	// Nil hooks have no commands
+	var none *Hooks
+	if none.Commands(HookPreRelease) != nil {
+		t.Error("expected nil commands for nil hooks")
+	}
+
+	// Empty commands are rejected
+	dir = createTestRepo(t, `{"packages": {}, "hooks": {"pre-apply": [" "]}}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for empty hook command")
+	}
+}
+
+func TestLoad_Remotes(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"remotes": {
+			"canonical-url": "https://github.com/acme/app",
+			"mirrors": ["enterprise", "backup"]
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Remotes.CanonicalURL != "https://github.com/acme/app" {
+		t.Errorf("unexpected canonical-url: %s", cfg.Remotes.CanonicalURL)
+	}
+	if len(cfg.Remotes.Mirrors) != 2 || cfg.Remotes.Mirrors[1] != "backup" {
+		t.Errorf("unexpected mirrors: %v", cfg.Remotes.Mirrors)
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "remotes": {"mirrors": [""]}}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for empty mirror name")
+	}
+}
+
+func TestLoad_Approval(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}, "approval": {"approvers": ["alice"]}}`, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	a := cfg.Approval
+	if a == nil || a.Label != DefaultApprovalLabel || a.Command != DefaultApprovalCommand || len(a.Approvers) != 1 {
+		t.Errorf("expected defaults filled in, got %+v", a)
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "approval": {"label": "ship-it", "command": "LGTM"}}`, `{}`)
+	if cfg, err = Load(dir); err != nil || cfg.Approval.Label != "ship-it" || cfg.Approval.Command != "LGTM" {
+		t.Errorf("expected custom label and command, got %+v, %v", cfg.Approval, err)
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "approval": {"approvers": [" "]}}`, `{}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "approval.approvers[0]") {
+		t.Errorf("expected empty approver error, got %v", err)
+	}
+}
+
+func TestLoad_Branches(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"branches": {
+			"1.x": {"max-bump": "patch"},
+			"release/*": {"max-bump": "minor"},
+			"release/legacy": {}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if b := cfg.BranchFor("1.x"); b == nil || b.MaxBump != version.Patch {
+		t.Errorf("expected 1.x capped at patch, got %+v", b)
+	}
+	if b := cfg.BranchFor("release/2024"); b == nil || b.MaxBump != version.Minor {
+		t.Errorf("expected release/* capped at minor, got %+v", b)
+	}
+	if b := cfg.BranchFor("release/legacy"); b == nil || b.MaxBump != version.None {
+		t.Errorf("expected exact match to win over glob, got %+v", b)
+	}
+	if b := cfg.BranchFor("main"); b != nil || b.Capped() {
+		t.Errorf("expected no config for main, got %+v", b)
+	}
+	if cfg.BranchFor("release/legacy").Capped() {
+		t.Error("expected a branch without max-bump not to be capped")
+	}
+	if b := cfg.BranchFor(""); b != nil {
+		t.Errorf("expected no config for detached HEAD, got %+v", b)
+	}
+}
+
+func TestLoad_InvalidBranches(t *testing.T) {
+	tests := map[string]string{
+		"max-bump none": `{"packages": {}, "branches": {"1.x": {"max-bump": "none"}}}`,
+		"max-bump typo": `{"packages": {}, "branches": {"1.x": {"max-bump": "pach"}}}`,
+		"bad pattern":   `{"packages": {}, "branches": {"release/[": {"max-bump": "patch"}}}`,
+	}
+	for name, configJSON := range tests {
+		dir := createTestRepo(t, configJSON, `{}`)
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad_ReleaseCommitPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		subject string
+		want    bool
+	}{
+		{"default plain", `{"packages": {}}`, "chore: release main", true},
+		{"default scoped", `{"packages": {}}`, "chore(main): release service-a 1.2.0", true},
+		{"default other chore", `{"packages": {}}`, "chore: releases are hard", false},
+		{"default feat", `{"packages": {}}`, "feat: release notes page", false},
+		{"custom", `{"packages": {}, "release-commit-pattern": "^ci: publish"}`, "ci: publish v2", true},
+		{"custom replaces default", `{"packages": {}, "release-commit-pattern": "^ci: publish"}`, "chore: release main", false},
+		{"empty disables", `{"packages": {}, "release-commit-pattern": ""}`, "chore: release main", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, tc.config, `{}`)
+			cfg, err := Load(dir)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if got := cfg.IsReleaseCommit(tc.subject); got != tc.want {
+				t.Errorf("IsReleaseCommit(%q) = %v, want %v", tc.subject, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoad_IncludeCommitBody(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}}`, `{}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.IncludeCommitBody || cfg.SubmoduleLog || cfg.SummarizeBinaryFiles || cfg.IgnoreBinaryFiles {
+		t.Error("expected commit bodies, submodule logs, and binary file handling to be off by default")
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "include-commit-body": true, "submodule-log": true, "summarize-binary-files": true, "ignore-binary-files": true}`, `{}`)
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.IncludeCommitBody || !cfg.SubmoduleLog || !cfg.SummarizeBinaryFiles || !cfg.IgnoreBinaryFiles {
+		t.Error("expected include-commit-body, submodule-log, and the binary file options to be set")
+	}
+}
+
+func TestLoad_MergeCommits(t *testing.T) {
+	tests := map[string]string{
+		`{"packages": {}}`: MergeCommitsIgnore,
+		`{"packages": {}, "merge-commits": "include"}`: MergeCommitsInclude,
+		`{"packages": {}, "merge-commits": "only"}`:    MergeCommitsOnly,
+	}
+	for configJSON, want := range tests {
+		dir := createTestRepo(t, configJSON, `{}`)
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.MergeCommits != want {
+			t.Errorf("%s: expected merge-commits %q, got %q", configJSON, want, cfg.MergeCommits)
+		}
+	}
+
+	dir := createTestRepo(t, `{"packages": {}, "merge-commits": "always"}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for invalid merge-commits")
+	}
+}
+
+func TestLoad_TagCollision(t *testing.T) {
+	tests := map[string]string{
+		`{"packages": {}}`:                                TagCollisionError,
+		`{"packages": {}, "tag-collision": "skip"}`:       TagCollisionSkip,
+		`{"packages": {}, "tag-collision": "bump-again"}`: TagCollisionBumpAgain,
+	}
+	for configJSON, want := range tests {
+		dir := createTestRepo(t, configJSON, `{}`)
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.TagCollision != want {
+			t.Errorf("%s: expected tag-collision %q, got %q", configJSON, want, cfg.TagCollision)
+		}
+	}
+
+	dir := createTestRepo(t, `{"packages": {}, "tag-collision": "overwrite"}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for invalid tag-collision")
+	}
+}
+
+func TestLoad_Dependencies(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"apps/web": {"component": "jarvis-web"},
+			"apps/cli": {"component": "jarvis-cli"},
+			"libs/client": {"component": "jarvis-api-client"}
+		},
+		"dependencies": {
+			"jarvis-web": ["jarvis-api-client"],
+			"jarvis-cli": ["jarvis-api-client"]
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"apps/web": "1.0.0", "apps/cli": "1.0.0", "libs/client": "1.0.0"}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	got := cfg.Dependents("jarvis-api-client")
+	if len(got) != 2 || got[0] != "jarvis-cli" || got[1] != "jarvis-web" {
+		t.Errorf("expected jarvis-cli and jarvis-web to depend on jarvis-api-client, got %v", got)
+	}
+	if got := cfg.Dependents("jarvis-web"); len(got) != 0 {
+		t.Errorf("expected no dependents of jarvis-web, got %v", got)
+	}
+	if pkg := cfg.PackageForComponent("jarvis-web"); pkg == nil || pkg.Path != "apps/web" {
+		t.Errorf("expected jarvis-web at apps/web, got %+v", pkg)
+	}
+	if pkg := cfg.PackageForComponent("missing"); pkg != nil {
+		t.Errorf("expected no package for an unknown component, got %+v", pkg)
+	}
+}
+
+func TestLoad_DependenciesProblems(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"apps/web": {"component": "jarvis-web"}
+		},
+		"dependencies": {
+			"jarvis-web": ["jarvis-api-client", "jarvis-web"],
+			"jarvis-admin": ["jarvis-web"]
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"apps/web": "1.0.0"}`)
+	_, err := Load(dir)
+	var integrity *IntegrityError
+	if !errors.As(err, &integrity) {
+		t.Fatalf("expected an IntegrityError, got %v", err)
+	}
+	want := []string{
+		`dependencies references unknown component "jarvis-admin"`,
+		`dependencies of "jarvis-web" references unknown component "jarvis-api-client"`,
+		`component "jarvis-web" depends on itself`,
+	}
+	if strings.Join(integrity.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", integrity.Problems, want)
+	}
+}
+
+func TestLoad_ReleaseTitlePattern(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"apps/web": {"component": "web", "release-title-pattern": "v${version}"},
+			"apps/api": {"component": "api"}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"apps/web": "1.0.0", "apps/api": "1.0.0"}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["apps/web"].ReleaseTitlePattern; got != "v${version}" {
+		t.Errorf("expected web's pattern, got %q", got)
+	}
+	if got := cfg.Packages["apps/api"].ReleaseTitlePattern; got != "" {
+		t.Errorf("expected no pattern for api, got %q", got)
+	}
+
+	configJSON = `{
+		"packages": {
+			"apps/web": {"component": "web", "release-title-pattern": "${component} ${ver}"},
+			"apps/api": {"component": "api", "release-title-pattern": " "}
+		}
+	}`
+	dir = createTestRepo(t, configJSON, `{"apps/web": "1.0.0", "apps/api": "1.0.0"}`)
+	_, err = Load(dir)
+	var integrity *IntegrityError
+	if !errors.As(err, &integrity) {
+		t.Fatalf("expected an IntegrityError, got %v", err)
+	}
+	want := []string{
+		"package apps/api release-title-pattern is empty",
+		"package apps/web release-title-pattern has unknown placeholder ${ver}; use ${component}, ${version}, ${date}",
+	}
+	if strings.Join(integrity.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", integrity.Problems, want)
+	}
+}
+
+func TestLoad_ChangelogSections(t *testing.T) {
+	configJSON := `{
+		"changelog-sections": [
+			{"type": "feat", "section": "Features"},
+			{"type": "docs", "section": "Documentation", "hidden": false}
+		],
+		"packages": {
+			"apps/web": {"component": "web", "changelog-sections": [{"type": "fix", "section": "Fixes"}]},
+			"apps/api": {"component": "api"}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"apps/web": "1.0.0", "apps/api": "1.0.0"}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["apps/api"].ChangelogSections; len(got) != 2 || got[1] != (ChangelogSection{Type: "docs", Section: "Documentation"}) {
+		t.Errorf("expected the top-level sections for api, got %+v", got)
+	}
+	if got := cfg.Packages["apps/web"].ChangelogSections; len(got) != 1 || got[0].Section != "Fixes" {
+		t.Errorf("expected web's own sections, got %+v", got)
+	}
+
+	dir = createTestRepo(t, `{"packages": {"apps/api": {"component": "api"}}}`, `{"apps/api": "1.0.0"}`)
+	if cfg, err = Load(dir); err != nil || !slices.Equal(cfg.Packages["apps/api"].ChangelogSections, DefaultChangelogSections) {
+		t.Errorf("expected the default sections, got %v", err)
+	}
+
+	dir = createTestRepo(t, `{"changelog-sort": "scope", "changelog-max-entries": 50, "packages": {"apps/api": {"component": "api"}}}`, `{"apps/api": "1.0.0"}`)
+	if cfg, err = Load(dir); err != nil || cfg.Packages["apps/api"].ChangelogSort != ChangelogSortScope || cfg.Packages["apps/api"].ChangelogMaxEntries != 50 {
+		t.Errorf("expected changelog-sort and changelog-max-entries on api, got %v", err)
+	}
+	for _, configJSON := range []string{`{"changelog-sort": "date", "packages": {}}`, `{"changelog-max-entries": -1, "packages": {}}`} {
+		if _, err := Load(createTestRepo(t, configJSON, `{}`)); err == nil {
+			t.Errorf("expected %s to be rejected", configJSON)
+		}
+	}
+
+	dir = createTestRepo(t, `{"initial-version": "1.0.0", "packages": {"apps/api": {"component": "api"}, "apps/web": {"component": "web", "initial-version": "0.5.0"}}}`, `{"apps/api": "0.0.0", "apps/web": "0.0.0"}`)
+	if cfg, err = Load(dir); err != nil || cfg.Packages["apps/api"].InitialVersion != "1.0.0" || cfg.Packages["apps/web"].InitialVersion != "0.5.0" {
+		t.Errorf("expected initial-version 1.0.0 on api and 0.5.0 on web, got %v", err)
+	}
+	if _, err := Load(createTestRepo(t, `{"initial-version": "one", "packages": {}}`, `{}`)); err == nil || !strings.Contains(err.Error(), "initial-version") {
+		t.Errorf("expected an invalid initial-version error, got %v", err)
+	}
+	dir = createTestRepo(t, `{"packages": {"apps/api": {"component": "api", "initial-version": "v1"}}}`, `{"apps/api": "0.0.0"}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "package apps/api initial-version") {
+		t.Errorf("expected an invalid package initial-version error, got %v", err)
+	}
+
+	dir = createTestRepo(t, `{"changelog-sections": [{"type": "docs"}], "packages": {}}`, `{}`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "changelog-sections[0] (docs) has no section") {
+		t.Errorf("expected a missing section error, got %v", err)
+	}
+
+	configJSON = `{
+		"packages": {
+			"apps/web": {"component": "web", "changelog-sections": [{"type": "fix", "section": "Fixes"}, {"type": "fix", "hidden": true}, {"section": "Other"}]}
+		}
+	}`
+	dir = createTestRepo(t, configJSON, `{"apps/web": "1.0.0"}`)
+	_, err = Load(dir)
+	var integrity *IntegrityError
+	if !errors.As(err, &integrity) {
+		t.Fatalf("expected an IntegrityError, got %v", err)
+	}
+	want := []string{
+		`package apps/web changelog-sections[1] repeats type "fix"`,
+		"package apps/web changelog-sections[2] has no type",
+	}
+	if strings.Join(integrity.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", integrity.Problems, want)
+	}
+}
+
+func TestLoad_DiscussionCategory(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}, "discussion-category": " Announcements "}`, `{}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DiscussionCategory != "Announcements" {
+		t.Errorf("expected category Announcements, got %q", cfg.DiscussionCategory)
+	}
+
+	dir = createTestRepo(t, `{"packages": {}}`, `{}`)
+	if cfg, err := Load(dir); err != nil || cfg.DiscussionCategory != "" {
+		t.Errorf("expected no category by default, got %q, %v", cfg.DiscussionCategory, err)
+	}
+
+	dir = createTestRepo(t, `{"packages": {}, "discussion-category": ""}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for an empty discussion-category")
+	}
+}
+
+func TestLoad_Environments(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}, "environments": [
+		{"name": " staging ", "path": "deploy/staging/values.yaml", "jsonpath": "$.${component}.image.tag"},
+		{"name": "prod", "path": "deploy/prod/${component}/values.yaml", "jsonpath": "$.image.tag"}
+	]}`, `{}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Environments) != 2 || cfg.Environments[0].Name != "staging" || cfg.Environments[1].Name != "prod" {
+		t.Fatalf("expected staging then prod, got %+v", cfg.Environments)
+	}
+	if got := cfg.Environments[0].VersionPath("web"); got != "$.web.image.tag" {
+		t.Errorf("unexpected version path %q", got)
+	}
+	if got := cfg.Environments[1].ManifestPath("web"); got != "deploy/prod/web/values.yaml" {
+		t.Errorf("unexpected manifest path %q", got)
+	}
+
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"no name", `{"path": "a.yaml", "jsonpath": "$.v"}`, "has no name"},
+		{"no path", `{"name": "prod", "jsonpath": "$.v"}`, "must be a path inside the repository"},
+		{"outside repo", `{"name": "prod", "path": "../other/a.yaml", "jsonpath": "$.v"}`, "must be a path inside the repository"},
+		{"no jsonpath", `{"name": "prod", "path": "a.yaml"}`, "needs a jsonpath"},
+		{"unknown placeholder", `{"name": "prod", "path": "${env}/a.yaml", "jsonpath": "$.v"}`, "unknown placeholder ${env}"},
+		{"duplicate", `{"name": "prod", "path": "a.yaml", "jsonpath": "$.v"}, {"name": "prod", "path": "b.yaml", "jsonpath": "$.v"}`, "listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := createTestRepo(t, `{"packages": {}, "environments": [`+tt.env+`]}`, `{}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidReleaseCommitPattern(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}, "release-commit-pattern": "chore(: release"}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for invalid release-commit-pattern")
 	}
-
-	return dir
 }
 
-func TestLoad_BasicConfig(t *testing.T) {
+func TestLoad_VersioningAndExtraFiles(t *testing.T) {
 	configJSON := `{
 		"packages": {
-			"workloads/service-a": {
-				"component": "service-a",
-				"changelog-path": "CHANGELOG.md"
-			},
-			"workloads/service-b": {
-				"component": "service-b"
+			"services/api": {
+				"component": "api",
+				"versioning": "always-bump-minor",
+				"extra-files": [
+					"src/version.go",
+					{"type": "json", "path": "package.json", "jsonpath": "$.version"}
+				]
 			}
 		}
 	}`
 
-	manifestJSON := `{
-		"workloads/service-a": "1.2.3",
-		"workloads/service-b": "0.1.0"
-	}`
-
-	dir := createTestRepo(t, configJSON, manifestJSON)
+	dir := createTestRepo(t, configJSON, `{"services/api": "1.0.0"}`)
 
 	cfg, err := Load(dir)
 	if err != nil {
 		t.Fatalf("Load failed: %v", err)
 	}
 
-	// Check packages
-	if len(cfg.Packages) != 2 {
-		t.Errorf("expected 2 packages, got %d", len(cfg.Packages))
+	pkg := cfg.Packages["services/api"]
+	if pkg.Versioning != "always-bump-minor" {
+		t.Errorf("expected versioning always-bump-minor, got %q", pkg.Versioning)
 	}
-
-	// Check service-a
-	pkgA := cfg.Packages["workloads/service-a"]
-	if pkgA == nil {
-		t.Fatal("expected package workloads/service-a")
+	if len(pkg.ExtraFiles) != 2 {
+		t.Fatalf("expected 2 extra files, got %d", len(pkg.ExtraFiles))
 	}
-	if pkgA.Component != "service-a" {
-		t.Errorf("expected component service-a, got %s", pkgA.Component)
+	if pkg.ExtraFiles[0].Type != "generic" || pkg.ExtraFiles[0].Path != "src/version.go" {
+		t.Errorf("unexpected string entry: %+v", pkg.ExtraFiles[0])
 	}
-	if pkgA.CurrentVersion != "1.2.3" {
-		t.Errorf("expected version 1.2.3, got %s", pkgA.CurrentVersion)
+	obj := pkg.ExtraFiles[1]
+	if obj.Type != "json" || obj.Path != "package.json" || obj.Options["jsonpath"] != "$.version" {
+		t.Errorf("unexpected object entry: %+v", obj)
 	}
-	if pkgA.ChangelogPath != "CHANGELOG.md" {
-		t.Errorf("expected changelog-path CHANGELOG.md, got %s", pkgA.ChangelogPath)
+	if _, ok := obj.Options["type"]; ok {
+		t.Error("type should not be repeated in options")
 	}
 
-	// Check service-b (default changelog path)
-	pkgB := cfg.Packages["workloads/service-b"]
-	if pkgB == nil {
-		t.Fatal("expected package workloads/service-b")
+	// Object entries need a path
+	dir = createTestRepo(t, `{"packages": {"a": {"component": "a", "extra-files": [{"type": "json"}]}}}`, `{}`)
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for extra-files entry without path")
 	}
-	if pkgB.ChangelogPath != "CHANGELOG.md" {
-		t.Errorf("expected default changelog-path CHANGELOG.md, got %s", pkgB.ChangelogPath)
+}
+
+func TestLoad_NativeYAMLOnly(t *testing.T) {
+	dir := createTestRepo(t, `{}`, `{"workloads/jarvis": "0.1.0"}`)
+	if err := os.Remove(filepath.Join(dir, ReleasePleaseConfigFile)); err != nil {
+		t.Fatal(err)
+	}
+	yamlConfig := `packages:
+  workloads/jarvis:
+    component: jarvis
+hooks:
+  post-apply:
+    - npm install
+`
+	if err := os.WriteFile(filepath.Join(dir, NativeConfigFile), []byte(yamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	pkg := cfg.Packages["workloads/jarvis"]
+	if pkg == nil || pkg.Component != "jarvis" || pkg.CurrentVersion != "0.1.0" {
+		t.Fatalf("unexpected package: %+v", pkg)
+	}
+	if got := cfg.Hooks.Commands(HookPostApply); len(got) != 1 || got[0] != "npm install" {
+		t.Errorf("unexpected hooks: %v", got)
 	}
 }
 
-func TestLoad_LinkedVersions(t *testing.T) {
+func TestLoad_MergesJSONAndYAML(t *testing.T) {
 	configJSON := `{
 		"packages": {
-			"workloads/service-a": {"component": "service-a"},
-			"workloads/service-b": {"component": "service-b"},
-			"workloads/service-c": {"component": "service-c"}
-		},
-		"plugins": [
-			{
-				"type": "linked-versions",
-				"groupName": "services-ab",
-				"components": ["service-a", "service-b"]
-			}
-		]
-	}`
-
-	manifestJSON := `{
-		"workloads/service-a": "1.0.0",
-		"workloads/service-b": "1.0.0",
-		"workloads/service-c": "2.0.0"
+			"workloads/jarvis": {"component": "jarvis"},
+			"workloads/web": {"component": "web", "changelog-path": "CHANGES.md"}
+		}
 	}`
-
-	dir := createTestRepo(t, configJSON, manifestJSON)
+	dir := createTestRepo(t, configJSON, `{"workloads/jarvis": "0.1.0", "workloads/web": "1.0.0", "workloads/api": "2.0.0"}`)
+	yamlConfig := `packages:
+  workloads/web:
+    changelog-path: docs/CHANGELOG.md
+  workloads/api:
+    component: api
+bump-rules:
+  perf: patch
+  refactor: patch
+  docs: none
+`
+	if err := os.WriteFile(filepath.Join(dir, NativeConfigFile), []byte(yamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
 
 	cfg, err := Load(dir)
 	if err != nil {
 		t.Fatalf("Load failed: %v", err)
 	}
-
-	// Check linked groups
-	if len(cfg.LinkedGroups) != 1 {
-		t.Errorf("expected 1 linked group, got %d", len(cfg.LinkedGroups))
+	if len(cfg.Packages) != 3 {
+		t.Fatalf("expected packages from both files, got %d", len(cfg.Packages))
+	}
+	web := cfg.Packages["workloads/web"]
+	if web.Component != "web" || web.ChangelogPath != "docs/CHANGELOG.md" {
+		t.Errorf("expected YAML to override changelog-path and keep component, got %+v", web)
 	}
 
-	components := cfg.LinkedGroups["services-ab"]
-	if len(components) != 2 {
-		t.Errorf("expected 2 components in group, got %d", len(components))
+	if cfg.BumpFor("refactor") != version.Patch {
+		t.Errorf("expected refactor to bump patch, got %s", cfg.BumpFor("refactor"))
 	}
+	if cfg.BumpFor("feat") != version.Minor {
+		t.Errorf("expected feat to keep the default minor bump, got %s", cfg.BumpFor("feat"))
+	}
+}
 
-	// Check package linked group assignment
-	pkgA := cfg.Packages["workloads/service-a"]
-	if pkgA.LinkedGroup != "services-ab" {
-		t.Errorf("expected LinkedGroup services-ab, got %s", pkgA.LinkedGroup)
+func TestLoad_InvalidNativeConfig(t *testing.T) {
+	tests := map[string]string{
+		"syntax":    "packages:\n\tbad: 1\n",
+		"not a map": "- a\n- b\n",
+		"bump rule": "bump-rules:\n  feat: huge\n",
+	}
+	for name, yamlConfig := range tests {
+		dir := createTestRepo(t, `{"packages": {}}`, `{}`)
+		if err := os.WriteFile(filepath.Join(dir, NativeConfigFile), []byte(yamlConfig), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
 	}
+}
 
-	pkgC := cfg.Packages["workloads/service-c"]
-	if pkgC.LinkedGroup != "" {
-		t.Errorf("expected no LinkedGroup for service-c, got %s", pkgC.LinkedGroup)
+func TestLoadWithOptions_ExplicitFiles(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {"a": {"component": "a"}}}`, `{"a": "1.0.0"}`)
+	writeTestFile(t, dir, "trains/beta.yaml", "packages:\n  b:\n    component: b\n")
+	writeTestFile(t, dir, "trains/beta-manifest.json", `{"b": "2.0.0"}`)
+
+	cfg, err := LoadWithOptions(dir, &LoadOptions{
+		ConfigFile:   "trains/beta.yaml",
+		ManifestFile: filepath.Join(dir, "trains/beta-manifest.json"),
+	})
+	if err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+	if len(cfg.Packages) != 1 || cfg.Packages["b"] == nil {
+		t.Fatalf("expected only the explicit config's packages, got %v", cfg.Packages)
+	}
+	if cfg.Packages["b"].CurrentVersion != "2.0.0" {
+		t.Errorf("expected version from explicit manifest, got %s", cfg.Packages["b"].CurrentVersion)
+	}
+	if cfg.ManifestPath != "trains/beta-manifest.json" {
+		t.Errorf("expected repo-relative manifest path, got %s", cfg.ManifestPath)
 	}
 }
 
-func TestLoad_MissingConfigFile(t *testing.T) {
+func TestLoadWithOptions_ManifestDefaultsNextToConfig(t *testing.T) {
 	dir := t.TempDir()
+	writeTestFile(t, dir, "release/config.json", `{"packages": {"a": {"component": "a"}}}`)
+	writeTestFile(t, dir, "release/release-please-manifest.json", `{"a": "0.3.0"}`)
 
-	_, err := Load(dir)
-	if err == nil {
-		t.Error("expected error for missing config file")
+	cfg, err := LoadWithOptions(dir, &LoadOptions{ConfigFile: "release/config.json"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+	if cfg.ManifestPath != "release/release-please-manifest.json" {
+		t.Errorf("unexpected manifest path: %s", cfg.ManifestPath)
+	}
+	if cfg.Packages["a"].CurrentVersion != "0.3.0" {
+		t.Errorf("unexpected version: %s", cfg.Packages["a"].CurrentVersion)
 	}
 }
 
-func TestLoad_MissingManifestFile(t *testing.T) {
-	dir := t.TempDir()
-	configJSON := `{"packages": {}}`
-	os.WriteFile(filepath.Join(dir, "release-please-config.json"), []byte(configJSON), 0644)
+func TestLoadWithOptions_DiscoversConfigUpTree(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {"root": {"component": "root"}}}`, `{"root": "1.0.0"}`)
+	writeTestFile(t, dir, "trains/beta/release-please-config.json", `{"packages": {"beta": {"component": "beta"}}}`)
+	writeTestFile(t, dir, "trains/beta/release-please-manifest.json", `{"beta": "1.0.0"}`)
+	if err := os.MkdirAll(filepath.Join(dir, "trains/beta/sub/dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
 
-	_, err := Load(dir)
-	if err == nil {
-		t.Error("expected error for missing manifest file")
+	tests := map[string]string{
+		"trains/beta/sub/dir": "beta",
+		"trains/beta":         "beta",
+		"trains":              "root",
+		"":                    "root",
+		"/elsewhere":          "root",
+	}
+	for searchDir, want := range tests {
+		cfg, err := LoadWithOptions(dir, &LoadOptions{SearchDir: searchDir})
+		if err != nil {
+			t.Fatalf("%q: LoadWithOptions failed: %v", searchDir, err)
+		}
+		var got []string
+		for _, pkg := range cfg.PackagesSortedByPath() {
+			got = append(got, pkg.Component)
+		}
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("%q: expected %s config, got %v", searchDir, want, got)
+		}
 	}
 }
 
-func TestLoad_InvalidJSON(t *testing.T) {
+func TestLoadWithOptions_FS(t *testing.T) {
+	// Nothing on disk; the files come from FS, as from a bare repository's tree
 	dir := t.TempDir()
-	os.WriteFile(filepath.Join(dir, "release-please-config.json"), []byte("not json"), 0644)
-	os.WriteFile(filepath.Join(dir, "release-please-manifest.json"), []byte("{}"), 0644)
+	fsys := fstest.MapFS{
+		"release-please-config.json":               {Data: []byte(`{"packages": {"root": {"component": "root"}}}`)},
+		"release-please-manifest.json":             {Data: []byte(`{"root": "1.0.0"}`)},
+		"trains/beta/.release-damnit.yaml":         {Data: []byte("packages:\n  beta:\n    component: beta\n")},
+		"trains/beta/release-please-manifest.json": {Data: []byte(`{"beta": "2.0.0"}`)},
+	}
 
-	_, err := Load(dir)
-	if err == nil {
-		t.Error("expected error for invalid JSON")
+	cfg, err := LoadWithOptions(dir, &LoadOptions{FS: fsys})
+	if err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+	if pkg := cfg.Packages["root"]; pkg == nil || pkg.CurrentVersion != "1.0.0" {
+		t.Errorf("expected root 1.0.0 from FS, got %+v", cfg.Packages)
+	}
+
+	cfg, err = LoadWithOptions(dir, &LoadOptions{FS: fsys, SearchDir: "trains/beta"})
+	if err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+	if pkg := cfg.Packages["beta"]; pkg == nil || pkg.CurrentVersion != "2.0.0" {
+		t.Errorf("expected beta 2.0.0 discovered in FS, got %+v", cfg.Packages)
+	}
+
+	if _, err := LoadWithOptions(dir, &LoadOptions{FS: fstest.MapFS{}}); err == nil {
+		t.Error("expected an error when FS has no config")
 	}
 }
 
-func TestLoad_MissingComponent(t *testing.T) {
+func TestLoadWithOptions_MissingExplicitFile(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {}}`, `{}`)
+
+	if _, err := LoadWithOptions(dir, &LoadOptions{ConfigFile: "missing.json"}); err == nil {
+		t.Error("expected error for missing config file")
+	}
+	if _, err := LoadWithOptions(dir, &LoadOptions{ManifestFile: "missing.json"}); err == nil {
+		t.Error("expected error for missing manifest file")
+	}
+}
+
+func TestMigrate(t *testing.T) {
 	configJSON := `{
-		"packages": {
-			"workloads/service-a": {}
-		}
+		"$schema": "https://raw.githubusercontent.com/googleapis/release-please/main/schemas/config.json",
+		"packages": {"workloads/jarvis": {"component": "jarvis"}},
+		"plugins": [{"type": "linked-versions", "groupName": "g", "components": ["jarvis"]}]
 	}`
-	manifestJSON := `{}`
+	dir := createTestRepo(t, configJSON, `{"workloads/jarvis": "1.0.0"}`)
 
-	dir := createTestRepo(t, configJSON, manifestJSON)
+	out, err := Migrate(dir)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if strings.Contains(out, "$schema") {
+		t.Errorf("expected $schema to be dropped:\n%s", out)
+	}
 
-	_, err := Load(dir)
-	if err == nil {
-		t.Error("expected error for missing component")
+	// The migrated config alone loads to the same packages
+	if err := os.Remove(filepath.Join(dir, ReleasePleaseConfigFile)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, NativeConfigFile), []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load of migrated config failed: %v\n%s", err, out)
+	}
+	if cfg.Packages["workloads/jarvis"].LinkedGroup != "g" {
+		t.Errorf("expected linked group to survive migration:\n%s", out)
 	}
 }
 
@@ -250,6 +1502,126 @@ func TestFindPackageForPath_DeepestMatchWins(t *testing.T) {
 	}
 }
 
+func TestFindPackageForPath_RootPackage(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			".": {"component": "monorepo", "exclude-paths": ["docs/", "./tools"]},
+			"workloads/jarvis": {"component": "jarvis"}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{".": "1.0.0", "workloads/jarvis": "0.1.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if root := cfg.Packages[RootPath]; root == nil || root.CurrentVersion != "1.0.0" {
+		t.Fatalf("expected root package with manifest version, got %+v", root)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"workloads/jarvis/main.go", "jarvis"},
+		{"workloads/other/main.go", "monorepo"},
+		{"README.md", "monorepo"},
+		{"docs/guide.md", ""},
+		{"tools/lint.sh", ""},
+		{"toolsmith/x.go", "monorepo"},
+	}
+	for _, tc := range tests {
+		pkg := cfg.FindPackageForPath(tc.path)
+		got := ""
+		if pkg != nil {
+			got = pkg.Component
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.path, tc.expected, got)
+		}
+	}
+}
+
+func TestLoad_DockerfileArgs(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/jarvis": {"component": "jarvis", "dockerfile-args": ["VERSION", "APP_VERSION"]},
+			"workloads/other": {"component": "other"}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"workloads/jarvis": "0.1.0", "workloads/other": "0.1.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["workloads/jarvis"].DockerfileArgs; !slices.Equal(got, []string{"VERSION", "APP_VERSION"}) {
+		t.Errorf("expected jarvis's dockerfile-args, got %v", got)
+	}
+	if got := cfg.Packages["workloads/other"].DockerfileArgs; got != nil {
+		t.Errorf("expected no dockerfile-args by default, got %v", got)
+	}
+}
+
+func TestFindPackageForPath_ExcludePathsInSubPackage(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/jarvis": {"component": "jarvis", "exclude-paths": ["workloads/jarvis/testdata"]}
+		}
+	}`
+	dir := createTestRepo(t, configJSON, `{"workloads/jarvis": "1.0.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if pkg := cfg.FindPackageForPath("workloads/jarvis/testdata/fixture.json"); pkg != nil {
+		t.Errorf("expected excluded file to match nothing, got %s", pkg.Component)
+	}
+	if pkg := cfg.FindPackageForPath("workloads/jarvis/main.go"); pkg == nil {
+		t.Error("expected jarvis to own main.go")
+	}
+}
+
+func TestPathspecs(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			".": {"component": "root"},
+			"workloads/jarvis": {"component": "jarvis", "exclude-paths": ["workloads/jarvis/testdata"]},
+			"workloads/jarvis/web": {"component": "jarvis-web"},
+			"workloads/jarvis-admin": {"component": "jarvis-admin"}
+		},
+		"ignore-files": ["package-lock.json", "**/*.pb.go"]
+	}`
+	dir := createTestRepo(t, configJSON, `{".": "1.0.0", "workloads/jarvis": "1.0.0", "workloads/jarvis/web": "1.0.0", "workloads/jarvis-admin": "1.0.0"}`)
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	got := strings.Join(cfg.Pathspecs(cfg.PackageForComponent("jarvis")), " ")
+	want := ":(literal)workloads/jarvis :(exclude,literal)workloads/jarvis/web :(exclude,literal)workloads/jarvis/testdata " +
+		":(exclude,glob)**/package-lock.json :(exclude,glob)**/*.pb.go"
+	if got != want {
+		t.Errorf("jarvis pathspecs:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = strings.Join(cfg.Pathspecs(cfg.PackageForComponent("root")), " ")
+	want = ":(literal). :(exclude,literal)workloads/jarvis :(exclude,literal)workloads/jarvis-admin :(exclude,literal)workloads/jarvis/web " +
+		":(exclude,glob)**/package-lock.json :(exclude,glob)**/*.pb.go"
+	if got != want {
+		t.Errorf("root pathspecs:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLoad_ExcludeWholeRepo(t *testing.T) {
+	dir := createTestRepo(t, `{"packages": {".": {"component": "root", "exclude-paths": ["./"]}}}`, `{}`)
+
+	if _, err := Load(dir); err == nil {
+		t.Error("expected error for exclude-paths covering the whole repo")
+	}
+}
+
 func TestGetLinkedPackages_Linked(t *testing.T) {
 	configJSON := `{
 		"packages": {
@@ -354,6 +1726,11 @@ func TestNormalizePath(t *testing.T) {
 		{"/workloads/jarvis", "workloads/jarvis"},
 		{"./workloads/jarvis/", "workloads/jarvis"},
 		{"workloads/jarvis", "workloads/jarvis"},
+		{".", "."},
+		{"./", "."},
+		{"/", "."},
+		{`.\workloads\jarvis\`, "workloads/jarvis"},
+		{`workloads\jarvis`, "workloads/jarvis"},
 	}
 
 	for _, tc := range tests {
@@ -365,3 +1742,87 @@ func TestNormalizePath(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_Digest(t *testing.T) {
+	manifest := `{"workloads/jarvis": "1.0.0"}`
+	a := createTestRepo(t, `{"packages": {"workloads/jarvis": {"component": "jarvis"}}}`, manifest)
+	b := createTestRepo(t, `{
+		"packages": {
+			"workloads/jarvis": {
+				"component": "jarvis"
+			}
+		}
+	}`, manifest)
+	c := createTestRepo(t, `{"packages": {"workloads/jarvis": {"component": "jarvis-api"}}}`, manifest)
+
+	var digests []string
+	for _, dir := range []string{a, b, c} {
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		digests = append(digests, cfg.Digest)
+	}
+	if len(digests[0]) != 64 || digests[0] != digests[1] {
+		t.Errorf("expected formatting not to change the digest, got %v", digests)
+	}
+	if digests[0] == digests[2] {
+		t.Errorf("expected a different config to change the digest, got %v", digests)
+	}
+}
+
+func TestScopeTo(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"workloads/jarvis": {"component": "jarvis"},
+			"workloads/jarvis/web": {"component": "jarvis-web"},
+			"workloads/jarvis-admin": {"component": "jarvis-admin"},
+			"workloads/billing": {"component": "billing"},
+			"libs/client": {"component": "api-client"}
+		},
+		"plugins": [{"type": "linked-versions", "groupName": "ui", "components": ["jarvis-web", "jarvis-admin"]}],
+		"dependencies": {
+			"jarvis": ["api-client"],
+			"jarvis-web": ["jarvis", "api-client"],
+			"billing": ["api-client"]
+		}
+	}`
+	manifest := `{"workloads/jarvis": "1.0.0", "workloads/jarvis/web": "1.0.0", "workloads/jarvis-admin": "1.0.0", "workloads/billing": "1.0.0", "libs/client": "1.0.0"}`
+	dir := createTestRepo(t, configJSON, manifest)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := cfg.ScopeTo("./workloads/jarvis/..."); err != nil {
+		t.Fatalf("ScopeTo failed: %v", err)
+	}
+
+	var paths []string
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		paths = append(paths, pkg.Path)
+	}
+	// jarvis-admin isn't under the prefix, but is linked to jarvis-web
+	if got := strings.Join(paths, ","); got != "workloads/jarvis,workloads/jarvis-admin,workloads/jarvis/web" {
+		t.Errorf("unexpected packages in scope: %s", got)
+	}
+	if len(cfg.Dependencies) != 1 || strings.Join(cfg.Dependencies["jarvis-web"], ",") != "jarvis" {
+		t.Errorf("expected only jarvis-web's dependency on jarvis kept, got %v", cfg.Dependencies)
+	}
+	if pkg := cfg.FindPackageForPath("workloads/billing/main.go"); pkg != nil {
+		t.Errorf("expected a package out of scope to own nothing, got %s", pkg.Component)
+	}
+	if pkg := cfg.FindPackageForPath("workloads/jarvis/web/app.ts"); pkg == nil || pkg.Component != "jarvis-web" {
+		t.Errorf("expected jarvis-web to own its files, got %v", pkg)
+	}
+
+	cfg, _ = Load(dir)
+	if err := cfg.ScopeTo("workloads/payments"); err == nil || !strings.Contains(err.Error(), "no packages under workloads/payments") {
+		t.Errorf("expected an error for a scope without packages, got %v", err)
+	}
+
+	cfg, _ = Load(dir)
+	if err := cfg.ScopeTo("./..."); err != nil || len(cfg.Packages) != 5 {
+		t.Errorf("expected the root scope to keep every package, got %d, %v", len(cfg.Packages), err)
+	}
+}
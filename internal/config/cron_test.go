@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * *", "must have 5 fields"},
+		{"60 * * * *", "minute"},
+		{"* 24 * * *", "hour"},
+		{"* * 0 * *", "day of month"},
+		{"* * * 13 *", "month"},
+		{"* * * * 8", "day of week"},
+		{"*/0 * * * *", "invalid step"},
+		{"5-1 * * * *", "out of range"},
+		{"a * * * *", "invalid value"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			if _, err := parseCron(tc.expr); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Matches(t *testing.T) {
+	// 2024-03-01 was a Friday
+	friday := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"* * * * *", friday, true},
+		{"0 18 * * 5", friday, true},
+		{"0 18 * * 4", friday, false},
+		{"*/15 9-17 * * 1-5", time.Date(2024, 3, 1, 9, 45, 0, 0, time.UTC), true},
+		{"*/15 9-17 * * 1-5", time.Date(2024, 3, 1, 9, 50, 0, 0, time.UTC), false},
+		{"0 0 * * 0", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 * * 7", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), true},
+		{"0 18 15 * 5", friday, true},   // either restricted day field
+		{"0 18 1 * *", friday, true},    // day of month only
+		{"0 18 2 * *", friday, false},   // wrong day of month
+		{"0 18 * 1,2 *", friday, false}, // wrong month
+		{"30/10 * * * *", time.Date(2024, 3, 1, 18, 50, 0, 0, time.UTC), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := parseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("parseCron failed: %v", err)
+			}
+			if got := s.matches(tc.at); got != tc.want {
+				t.Errorf("matches(%s) = %v, want %v", tc.at, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_LastFiring(t *testing.T) {
+	s, err := parseCron("0 18 * * 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saturday := time.Date(2024, 3, 2, 12, 30, 15, 0, time.UTC)
+	got, ok := s.lastFiring(saturday, 24*time.Hour)
+	if want := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("expected %s, got %s (%v)", want, got, ok)
+	}
+	if _, ok := s.lastFiring(saturday, 18*time.Hour); ok {
+		t.Error("expected no firing within 18h")
+	}
+}
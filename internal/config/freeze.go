@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxCronFreeze bounds how long a recurring freeze window may last.
+const maxCronFreeze = 31 * 24 * time.Hour
+
+// FreezeWindow is a period during which releases may be analyzed but not
+// applied. It's either a date range (From and To) or recurring, starting
+// whenever Cron fires and lasting Duration.
+type FreezeWindow struct {
+	// Name identifies the window in output (e.g., "year-end").
+	Name string
+
+	// From and To bound a date range window, inclusive of From and
+	// exclusive of To.
+	From, To time.Time
+
+	// Duration is how long a recurring window lasts after each firing.
+	Duration time.Duration
+
+	// Location is the time zone dates and cron expressions are read in.
+	Location *time.Location
+
+	cron *cronSchedule
+}
+
+// Freeze is a freeze window in effect.
+type Freeze struct {
+	// Name is the window's name.
+	Name string
+
+	// Start and End are when this occurrence of the window began and ends.
+	Start, End time.Time
+}
+
+// freezeWindowConfig is a freeze-windows entry: a date range, or a cron
+// expression and a duration.
+type freezeWindowConfig struct {
+	Name     string `json:"name"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Cron     string `json:"cron"`
+	Duration string `json:"duration"`
+	Timezone string `json:"timezone"`
+}
+
+// buildFreezeWindow validates a freeze-windows entry.
+func buildFreezeWindow(raw *freezeWindowConfig) (*FreezeWindow, error) {
+	w := &FreezeWindow{Name: raw.Name, Location: time.UTC}
+	if w.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	if raw.Timezone != "" {
+		loc, err := time.LoadLocation(raw.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", raw.Timezone, err)
+		}
+		w.Location = loc
+	}
+
+	isRange := raw.From != "" || raw.To != ""
+	isCron := raw.Cron != "" || raw.Duration != ""
+	switch {
+	case isRange && isCron:
+		return nil, fmt.Errorf("set either from and to, or cron and duration")
+	case isRange:
+		if raw.From == "" || raw.To == "" {
+			return nil, fmt.Errorf("a date range needs both from and to")
+		}
+		from, err := parseFreezeTime(raw.From, w.Location, false)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseFreezeTime(raw.To, w.Location, true)
+		if err != nil {
+			return nil, err
+		}
+		if !to.After(from) {
+			return nil, fmt.Errorf("to %s is not after from %s", raw.To, raw.From)
+		}
+		w.From, w.To = from, to
+	case isCron:
+		if raw.Cron == "" || raw.Duration == "" {
+			return nil, fmt.Errorf("a recurring window needs both cron and duration")
+		}
+		cron, err := parseCron(raw.Cron)
+		if err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(raw.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", raw.Duration, err)
+		}
+		if d < time.Minute || d > maxCronFreeze {
+			return nil, fmt.Errorf("duration %s must be between 1m and %s", raw.Duration, maxCronFreeze)
+		}
+		w.cron, w.Duration = cron, d
+	default:
+		return nil, fmt.Errorf("set from and to, or cron and duration")
+	}
+	return w, nil
+}
+
+// parseFreezeTime parses an RFC 3339 time or a YYYY-MM-DD date in loc. A
+// date used as the end of a range covers that whole day.
+func parseFreezeTime(value string, loc *time.Location, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(value), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (expected YYYY-MM-DD or RFC 3339)", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Active returns the occurrence of the window that t falls in, if any.
+func (w *FreezeWindow) Active(t time.Time) (*Freeze, bool) {
+	if w.cron == nil {
+		if t.Before(w.From) || !t.Before(w.To) {
+			return nil, false
+		}
+		return &Freeze{Name: w.Name, Start: w.From, End: w.To}, true
+	}
+	start, ok := w.cron.lastFiring(t.In(w.Location), w.Duration)
+	if !ok {
+		return nil, false
+	}
+	return &Freeze{Name: w.Name, Start: start, End: start.Add(w.Duration)}, true
+}
+
+// FreezeAt returns the freeze in effect at t, or nil. When windows overlap,
+// the one ending last wins.
+func (c *Config) FreezeAt(t time.Time) *Freeze {
+	var active *Freeze
+	for _, w := range c.FreezeWindows {
+		if f, ok := w.Active(t); ok && (active == nil || f.End.After(active.End)) {
+			active = f
+		}
+	}
+	return active
+}
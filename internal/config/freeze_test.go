@@ -0,0 +1,107 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_FreezeWindows(t *testing.T) {
+	configJSON := `{
+		"packages": {},
+		"freeze-windows": [
+			{"name": "year-end", "from": "2024-12-20", "to": "2025-01-02"},
+			{"name": "weekend", "cron": "0 18 * * 5", "duration": "63h", "timezone": "America/New_York"}
+		]
+	}`
+	dir := createTestRepo(t, configJSON, `{}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.FreezeWindows) != 2 {
+		t.Fatalf("expected 2 freeze windows, got %d", len(cfg.FreezeWindows))
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+		end  time.Time
+	}{
+		{"before range", time.Date(2024, 12, 19, 23, 59, 0, 0, time.UTC), "", time.Time{}},
+		{"range start", time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), "year-end", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"last day of range", time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC), "year-end", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"after range", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), "", time.Time{}},
+		{"weekday", time.Date(2024, 3, 6, 12, 0, 0, 0, ny), "", time.Time{}},
+		{"friday evening", time.Date(2024, 3, 1, 19, 0, 0, 0, ny), "weekend", time.Date(2024, 3, 4, 9, 0, 0, 0, ny)},
+		{"monday morning", time.Date(2024, 3, 4, 8, 59, 0, 0, ny), "weekend", time.Date(2024, 3, 4, 9, 0, 0, 0, ny)},
+		{"monday after", time.Date(2024, 3, 4, 9, 0, 0, 0, ny), "", time.Time{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := cfg.FreezeAt(tc.at)
+			if tc.want == "" {
+				if f != nil {
+					t.Errorf("expected no freeze, got %+v", f)
+				}
+				return
+			}
+			if f == nil || f.Name != tc.want || !f.End.Equal(tc.end) {
+				t.Errorf("expected %s until %s, got %+v", tc.want, tc.end, f)
+			}
+		})
+	}
+}
+
+func TestFreezeAt_Overlapping(t *testing.T) {
+	cfg := &Config{}
+	for _, raw := range []freezeWindowConfig{
+		{Name: "short", From: "2024-12-20", To: "2024-12-24"},
+		{Name: "long", From: "2024-12-22", To: "2025-01-02"},
+	} {
+		w, err := buildFreezeWindow(&raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.FreezeWindows = append(cfg.FreezeWindows, w)
+	}
+
+	if f := cfg.FreezeAt(time.Date(2024, 12, 23, 0, 0, 0, 0, time.UTC)); f == nil || f.Name != "long" {
+		t.Errorf("expected the window ending last, got %+v", f)
+	}
+}
+
+func TestLoad_FreezeWindowsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		window string
+		want   string
+	}{
+		{"no name", `{"from": "2024-12-20", "to": "2025-01-02"}`, "missing name"},
+		{"empty", `{"name": "x"}`, "set from and to, or cron and duration"},
+		{"both kinds", `{"name": "x", "from": "2024-12-20", "to": "2025-01-02", "cron": "* * * * *", "duration": "1h"}`, "either"},
+		{"no to", `{"name": "x", "from": "2024-12-20"}`, "both from and to"},
+		{"bad date", `{"name": "x", "from": "12/20/2024", "to": "2025-01-02"}`, "invalid time"},
+		{"reversed", `{"name": "x", "from": "2025-01-02", "to": "2024-12-20"}`, "not after"},
+		{"no duration", `{"name": "x", "cron": "0 18 * * 5"}`, "both cron and duration"},
+		{"bad cron", `{"name": "x", "cron": "0 18 * *", "duration": "1h"}`, "5 fields"},
+		{"bad duration", `{"name": "x", "cron": "0 18 * * 5", "duration": "a week"}`, "invalid duration"},
+		{"long duration", `{"name": "x", "cron": "0 18 * * 5", "duration": "1000h"}`, "must be between"},
+		{"bad timezone", `{"name": "x", "cron": "0 18 * * 5", "duration": "1h", "timezone": "Mars/Olympus"}`, "invalid timezone"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := createTestRepo(t, `{"packages": {}, "freeze-windows": [`+tc.window+`]}`, `{}`)
+			if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), tc.want) || !strings.Contains(err.Error(), "freeze-windows[0]") {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/package-lock.json", "package-lock.json", true},
+		{"**/package-lock.json", "workloads/jarvis/package-lock.json", true},
+		{"package-lock.json", "workloads/jarvis/package-lock.json", true},
+		{"package-lock.json", "workloads/jarvis/package-lock.json.bak", false},
+		{"**/*.pb.go", "api/v1/service.pb.go", true},
+		{"**/*.pb.go", "api/v1/service.go", false},
+		{"gen/**", "gen/a/b.go", true},
+		{"gen/**", "src/gen/b.go", false},
+		{"workloads/*/dist/**", "workloads/jarvis/dist/app.js", true},
+		{"workloads/*/dist/**", "workloads/jarvis/web/dist/app.js", false},
+		{"workloads/**/dist/*.js", "workloads/jarvis/web/dist/app.js", true},
+		{"a/**/**/b", "a/b", true},
+		{"docs/*.md", "docs/guide/intro.md", false},
+	}
+
+	for _, tc := range tests {
+		if got := globMatch(tc.pattern, tc.path); got != tc.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestValidGlob(t *testing.T) {
+	for pattern, want := range map[string]bool{
+		"**/*.pb.go": true,
+		"gen/[a-z]*": true,
+		"gen/[a-":    false,
+		"":           false,
+	} {
+		if got := validGlob(pattern); got != want {
+			t.Errorf("validGlob(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// linkedVersionsPlugin is the plugin type that links component versions.
+const linkedVersionsPlugin = "linked-versions"
+
+// linkedGroups builds the linked-versions groups from the config's plugins.
+// A group's components may name other groups, linking all of their
+// components too, so product families can be linked hierarchically.
+// components is the set of configured component names: a member naming one
+// is a component, even if a group has the same name.
+//
+// It returns the components of each outermost group (nested groups are
+// folded into it), the outermost group of each component, and problems: a
+// group defined twice, a component or group in more than one group, nesting
+// cycles, and members that are neither a component nor a group.
+func linkedGroups(plugins []pluginConfig, components map[string]bool) (map[string][]string, map[string]string, []string) {
+	var problems []string
+
+	members := make(map[string][]string)
+	for _, plugin := range plugins {
+		if plugin.Type != linkedVersionsPlugin {
+			continue
+		}
+		if _, ok := members[plugin.GroupName]; ok {
+			problems = append(problems, fmt.Sprintf("linked-versions group %q is defined more than once; list all its components in one entry", plugin.GroupName))
+			continue
+		}
+		members[plugin.GroupName] = plugin.Components
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Each component and nested group may have one parent group
+	owner := make(map[string]string)
+	parent := make(map[string]string)
+	for _, group := range names {
+		for _, member := range members[group] {
+			_, isGroup := members[member]
+			switch {
+			case components[member]:
+				if other, ok := owner[member]; ok && other != group {
+					problems = append(problems, fmt.Sprintf("component %q is in linked-versions groups %q and %q; a component can be in one group (nest one group in the other to link them)", member, other, group))
+					continue
+				}
+				owner[member] = group
+			case isGroup:
+				if other, ok := parent[member]; ok && other != group {
+					problems = append(problems, fmt.Sprintf("linked-versions group %q is nested in both %q and %q; a group can be nested in one group", member, other, group))
+					continue
+				}
+				parent[member] = group
+			default:
+				problems = append(problems, fmt.Sprintf("linked-versions group %q references unknown component %q", group, member))
+			}
+		}
+	}
+
+	// Parents that lead back to a group form a cycle, reported once from
+	// its first group by name
+	for _, group := range names {
+		cycle := []string{group}
+		for g := parent[group]; g != "" && len(cycle) <= len(names); g = parent[g] {
+			if g == group {
+				if slices.Min(cycle) == group {
+					problems = append(problems, fmt.Sprintf("linked-versions groups are nested in a cycle: %s", strings.Join(append(cycle, group), " → ")))
+				}
+				break
+			}
+			cycle = append(cycle, g)
+		}
+	}
+
+	groups := make(map[string][]string)
+	componentGroup := make(map[string]string)
+	for _, root := range names {
+		if _, nested := parent[root]; nested {
+			continue
+		}
+		seen := make(map[string]bool)
+		var flatten func(group string)
+		flatten = func(group string) {
+			for _, member := range members[group] {
+				if seen[member] {
+					continue
+				}
+				seen[member] = true
+				if components[member] {
+					if owner[member] != group {
+						continue // listed in another group too, a problem
+					}
+					groups[root] = append(groups[root], member)
+					componentGroup[member] = root
+				} else if parent[member] == group {
+					flatten(member)
+				}
+			}
+		}
+		flatten(root)
+		if _, ok := groups[root]; !ok {
+			groups[root] = []string{}
+		}
+	}
+	return groups, componentGroup, problems
+}
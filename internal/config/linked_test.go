@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkedGroups_Nested(t *testing.T) {
+	plugins := []pluginConfig{
+		{Type: "linked-versions", GroupName: "platform", Components: []string{"observe", "billing", "gateway"}},
+		{Type: "linked-versions", GroupName: "observe", Components: []string{"observe-client", "observe-server"}},
+		{Type: "linked-versions", GroupName: "billing", Components: []string{"billing-api"}},
+		{Type: "linked-versions", GroupName: "tools", Components: []string{"cli"}},
+		{Type: "sentence-case"},
+	}
+	components := map[string]bool{"observe-client": true, "observe-server": true, "billing-api": true, "gateway": true, "cli": true}
+
+	groups, componentGroup, problems := linkedGroups(plugins, components)
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	want := map[string][]string{
+		"platform": {"observe-client", "observe-server", "billing-api", "gateway"},
+		"tools":    {"cli"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+	for _, c := range []string{"observe-client", "billing-api", "gateway"} {
+		if componentGroup[c] != "platform" {
+			t.Errorf("%s: expected group platform, got %q", c, componentGroup[c])
+		}
+	}
+}
+
+func TestLinkedGroups_ComponentNamedLikeGroup(t *testing.T) {
+	// A group may share its name with one of its components
+	plugins := []pluginConfig{{Type: "linked-versions", GroupName: "jarvis", Components: []string{"jarvis", "jarvis-web"}}}
+	groups, _, problems := linkedGroups(plugins, map[string]bool{"jarvis": true, "jarvis-web": true})
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if !reflect.DeepEqual(groups["jarvis"], []string{"jarvis", "jarvis-web"}) {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+}
+
+func TestLinkedGroups_Problems(t *testing.T) {
+	components := map[string]bool{"a": true, "b": true, "c": true}
+	tests := []struct {
+		name    string
+		plugins []pluginConfig
+		want    string
+	}{
+		{
+			name: "component in two groups",
+			plugins: []pluginConfig{
+				{Type: "linked-versions", GroupName: "g1", Components: []string{"a", "b"}},
+				{Type: "linked-versions", GroupName: "g2", Components: []string{"b", "c"}},
+			},
+			want: `component "b" is in linked-versions groups "g1" and "g2"`,
+		},
+		{
+			name: "group nested twice",
+			plugins: []pluginConfig{
+				{Type: "linked-versions", GroupName: "inner", Components: []string{"a"}},
+				{Type: "linked-versions", GroupName: "outer1", Components: []string{"inner", "b"}},
+				{Type: "linked-versions", GroupName: "outer2", Components: []string{"inner", "c"}},
+			},
+			want: `linked-versions group "inner" is nested in both "outer1" and "outer2"`,
+		},
+		{
+			name: "cycle",
+			plugins: []pluginConfig{
+				{Type: "linked-versions", GroupName: "g1", Components: []string{"a", "g2"}},
+				{Type: "linked-versions", GroupName: "g2", Components: []string{"b", "g1"}},
+			},
+			want: "linked-versions groups are nested in a cycle: g1 → g2 → g1",
+		},
+		{
+			name: "defined twice",
+			plugins: []pluginConfig{
+				{Type: "linked-versions", GroupName: "g", Components: []string{"a"}},
+				{Type: "linked-versions", GroupName: "g", Components: []string{"b"}},
+			},
+			want: `linked-versions group "g" is defined more than once`,
+		},
+		{
+			name:    "unknown member",
+			plugins: []pluginConfig{{Type: "linked-versions", GroupName: "g", Components: []string{"a", "ghost"}}},
+			want:    `linked-versions group "g" references unknown component "ghost"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, problems := linkedGroups(tt.plugins, components)
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Errorf("expected one problem containing %q, got %v", tt.want, problems)
+			}
+		})
+	}
+}
+
+func TestLoad_NestedLinkedGroups(t *testing.T) {
+	configJSON := `{
+		"packages": {
+			"observe/client": {"component": "observe-client"},
+			"observe/server": {"component": "observe-server"},
+			"gateway": {"component": "gateway"}
+		},
+		"plugins": [
+			{"type": "linked-versions", "groupName": "observe", "components": ["observe-client", "observe-server"]},
+			{"type": "linked-versions", "groupName": "platform", "components": ["observe", "gateway"]}
+		]
+	}`
+	dir := createTestRepo(t, configJSON, `{"observe/client": "1.0.0", "observe/server": "1.0.0", "gateway": "1.0.0"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Packages["observe/client"].LinkedGroup; got != "platform" {
+		t.Errorf("expected observe-client in platform, got %q", got)
+	}
+	if got := len(cfg.GetLinkedPackages(cfg.Packages["gateway"])); got != 3 {
+		t.Errorf("expected gateway linked with 3 packages, got %d", got)
+	}
+
+	configJSON = `{
+		"packages": {"a": {"component": "a"}, "b": {"component": "b"}},
+		"plugins": [
+			{"type": "linked-versions", "groupName": "g1", "components": ["a", "b"]},
+			{"type": "linked-versions", "groupName": "g2", "components": ["a"]}
+		]
+	}`
+	dir = createTestRepo(t, configJSON, `{"a": "1.0.0", "b": "1.0.0"}`)
+	var integrityErr *IntegrityError
+	if _, err := Load(dir); !errors.As(err, &integrityErr) || !strings.Contains(err.Error(), `component "a" is in linked-versions groups "g1" and "g2"`) {
+		t.Errorf("expected an integrity error for a component in two groups, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import "strings"
+
+// pathTrie indexes packages by path segment so the owner of a file is found
+// in time proportional to the file's depth rather than the number of
+// packages. FindPackageForPath runs for every file of every analyzed commit,
+// which dominates analysis of large merges in repos with hundreds of packages.
+type pathTrie struct {
+	// pkg is the package rooted at this node, if any.
+	pkg *Package
+
+	children map[string]*pathTrie
+}
+
+// newPathTrie builds a trie over packages, keyed by normalized path.
+func newPathTrie(packages map[string]*Package) *pathTrie {
+	root := &pathTrie{}
+	for _, pkg := range packages {
+		root.insert(pkg)
+	}
+	return root
+}
+
+// insert adds a package at its path. The root package sits at the root node.
+func (t *pathTrie) insert(pkg *Package) {
+	node := t
+	if pkg.Path != RootPath {
+		for _, segment := range strings.Split(pkg.Path, "/") {
+			child := node.children[segment]
+			if child == nil {
+				child = &pathTrie{}
+				if node.children == nil {
+					node.children = make(map[string]*pathTrie)
+				}
+				node.children[segment] = child
+			}
+			node = child
+		}
+	}
+	node.pkg = pkg
+}
+
+// find returns the deepest package that owns a normalized file path, falling
+// back to shallower packages when a deeper one excludes the file.
+func (t *pathTrie) find(filePath string) *Package {
+	// Packages along the path, shallowest first
+	var candidates []*Package
+	node := t
+	if node.pkg != nil {
+		candidates = append(candidates, node.pkg)
+	}
+	for _, segment := range strings.Split(filePath, "/") {
+		node = node.children[segment]
+		if node == nil {
+			break
+		}
+		if node.pkg != nil {
+			candidates = append(candidates, node.pkg)
+		}
+	}
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if candidates[i].owns(filePath) {
+			return candidates[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+// largeConfig returns an indexed config with n service packages, each with a
+// nested plugin package, plus a root package excluding the services.
+func largeConfig(n int) *Config {
+	cfg := &Config{Packages: make(map[string]*Package)}
+	cfg.Packages[RootPath] = &Package{Path: RootPath, Component: "root", ExcludePaths: []string{"services"}}
+	for i := 0; i < n; i++ {
+		path := fmt.Sprintf("services/svc-%03d", i)
+		cfg.Packages[path] = &Package{Path: path, Component: fmt.Sprintf("svc-%03d", i)}
+		plugin := path + "/plugins/core"
+		cfg.Packages[plugin] = &Package{Path: plugin, Component: fmt.Sprintf("svc-%03d-core", i), ExcludePaths: []string{plugin + "/vendor"}}
+	}
+	cfg.pathIndex = newPathTrie(cfg.Packages)
+	return cfg
+}
+
+func TestPathTrie_MatchesLinearLookup(t *testing.T) {
+	cfg := largeConfig(20)
+	files := []string{
+		"README.md",
+		"docs/guide.md",
+		"services/README.md",
+		"services/svc-007/main.go",
+		"services/svc-007/plugins/core/plugin.go",
+		"services/svc-007/plugins/core/vendor/lib.go",
+		"services/svc-007/plugins/other/plugin.go",
+		"services/svc-0071/main.go",
+		"./services/svc-019/",
+		"services/svc-999/main.go",
+	}
+
+	for _, file := range files {
+		got := cfg.FindPackageForPath(file)
+		want := cfg.findPackageLinear(normalizePath(file))
+		if got != want {
+			t.Errorf("%s: trie found %v, linear lookup found %v", file, got, want)
+		}
+	}
+}
+
+func TestPathTrie_NoRootPackage(t *testing.T) {
+	cfg := &Config{Packages: map[string]*Package{
+		"a/b": {Path: "a/b", Component: "b", ExcludePaths: []string{"a/b/c"}},
+	}}
+
+	if pkg := cfg.FindPackageForPath("a/b/c/file.go"); pkg != nil {
+		t.Errorf("expected excluded file to match nothing, got %s", pkg.Component)
+	}
+	if pkg := cfg.FindPackageForPath("a/file.go"); pkg != nil {
+		t.Errorf("expected file above the package to match nothing, got %s", pkg.Component)
+	}
+	if pkg := cfg.FindPackageForPath("a/b"); pkg == nil || pkg.Component != "b" {
+		t.Errorf("expected the package directory itself to match b, got %v", pkg)
+	}
+}
+
+var benchmarkFiles = []string{
+	"services/svc-250/src/handlers/http/server.go",
+	"services/svc-499/plugins/core/plugin.go",
+	"services/svc-001/plugins/core/vendor/lib.go",
+	"docs/architecture/overview.md",
+}
+
+func BenchmarkFindPackageForPath(b *testing.B) {
+	for _, n := range []int{50, 500, 5000} {
+		cfg := largeConfig(n)
+		b.Run(fmt.Sprintf("packages=%d", 2*n+1), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cfg.FindPackageForPath(benchmarkFiles[i%len(benchmarkFiles)])
+			}
+		})
+	}
+}
+
+func BenchmarkFindPackageForPath_Linear(b *testing.B) {
+	for _, n := range []int{50, 500, 5000} {
+		cfg := largeConfig(n)
+		b.Run(fmt.Sprintf("packages=%d", 2*n+1), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cfg.findPackageLinear(benchmarkFiles[i%len(benchmarkFiles)])
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package diff renders unified diffs between two versions of a text file.
+// It's used by --dry-run to show exactly what Apply would write.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// opKind is the kind of a single line in an edit script.
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is one line of the edit script.
+type op struct {
+	kind opKind
+	text string
+	oldN int // 0-based line in old (for equal/delete)
+	newN int // 0-based line in new (for equal/insert)
+}
+
+// Unified returns a unified diff of oldText → newText labeled with path.
+// An empty oldText is treated as a new file (/dev/null). Returns "" if the
+// texts are identical.
+func Unified(path, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	ops := editScript(oldLines, newLines)
+
+	var sb strings.Builder
+	if oldText == "" {
+		sb.WriteString("--- /dev/null\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("--- a/%s\n", path))
+	}
+	sb.WriteString(fmt.Sprintf("+++ b/%s\n", path))
+
+	for _, h := range hunks(ops) {
+		writeHunk(&sb, ops[h[0]:h[1]])
+	}
+	return sb.String()
+}
+
+// splitLines splits text into lines, keeping a trailing-newline-free last line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// editScript computes a line-level edit script. Common prefix and suffix are
+// stripped first (release edits are usually a prepend or a one-line change),
+// then an LCS table is built over the remaining middle.
+func editScript(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, text: a[i], oldN: i, newN: i})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] = length of LCS of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, op{kind: opEqual, text: midA[i], oldN: prefix + i, newN: prefix + j})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Prefer deletions first so a replaced line reads -old then +new
+			ops = append(ops, op{kind: opDelete, text: midA[i], oldN: prefix + i, newN: prefix + j})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, text: midB[j], oldN: prefix + i, newN: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		ai := len(a) - suffix + k
+		bi := len(b) - suffix + k
+		ops = append(ops, op{kind: opEqual, text: a[ai], oldN: ai, newN: bi})
+	}
+
+	return ops
+}
+
+// hunks groups changed ops with surrounding context into [start, end) ranges,
+// merging hunks whose context overlaps.
+func hunks(ops []op) [][2]int {
+	var result [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == opEqual {
+			continue
+		}
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		// Extend through changes until a gap of more than 2*context equal lines
+		end := i
+		equalRun := 0
+		for end < len(ops) {
+			if ops[end].kind == opEqual {
+				if equalRun == 2*contextLines {
+					break
+				}
+				equalRun++
+			} else {
+				equalRun = 0
+			}
+			end++
+		}
+		end -= equalRun
+		end += contextLines
+		if end > len(ops) {
+			end = len(ops)
+		}
+		result = append(result, [2]int{start, end})
+		i = end - 1
+	}
+	return result
+}
+
+// writeHunk writes a single hunk with its @@ header.
+func writeHunk(sb *strings.Builder, ops []op) {
+	oldStart, newStart := ops[0].oldN+1, ops[0].newN+1
+	var oldCount, newCount int
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			oldCount++
+			newCount++
+		case opDelete:
+			oldCount++
+		case opInsert:
+			newCount++
+		}
+	}
+	// Empty ranges start at the line before (matches GNU diff)
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			sb.WriteString(" " + o.text + "\n")
+		case opDelete:
+			sb.WriteString("-" + o.text + "\n")
+		case opInsert:
+			sb.WriteString("+" + o.text + "\n")
+		}
+	}
+}
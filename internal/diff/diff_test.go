@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified_Identical(t *testing.T) {
+	if got := Unified("VERSION", "1.0.0\n", "1.0.0\n"); got != "" {
+		t.Errorf("expected empty diff, got:\n%s", got)
+	}
+}
+
+func TestUnified_SingleLineChange(t *testing.T) {
+	got := Unified("VERSION", "1.0.0 # x-release-please-version\n", "1.1.0 # x-release-please-version\n")
+	want := `--- a/VERSION
++++ b/VERSION
+@@ -1,1 +1,1 @@
+-1.0.0 # x-release-please-version
++1.1.0 # x-release-please-version
+`
+	if got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	got := Unified("CHANGELOG.md", "", "# Changelog\n")
+	want := `--- /dev/null
++++ b/CHANGELOG.md
+@@ -0,0 +1,1 @@
++# Changelog
+`
+	if got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_InsertWithContext(t *testing.T) {
+	oldText := "# Changelog\n\n## [1.0.0]\n\n* first\n* second\n* third\n* fourth\n"
+	newText := "# Changelog\n\n## [1.1.0]\n\n* new\n\n## [1.0.0]\n\n* first\n* second\n* third\n* fourth\n"
+
+	got := Unified("CHANGELOG.md", oldText, newText)
+
+	if !strings.Contains(got, "@@ -1,5 +1,9 @@\n") {
+		t.Errorf("unexpected hunk header:\n%s", got)
+	}
+	if !strings.Contains(got, "+## [1.1.0]\n+\n+* new\n+\n") {
+		t.Errorf("expected inserted lines:\n%s", got)
+	}
+	// Only 3 lines of trailing context
+	if strings.Contains(got, "* second") {
+		t.Errorf("expected context to be limited:\n%s", got)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a' + i))
+		oldLines = append(oldLines, line)
+		newLines = append(newLines, line)
+	}
+	newLines[1] = "B"
+	newLines[18] = "S"
+
+	got := Unified("f", strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n")
+
+	if strings.Count(got, "@@ -") != 2 {
+		t.Errorf("expected two hunks:\n%s", got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n") {
+		t.Errorf("unexpected first hunk:\n%s", got)
+	}
+	if !strings.Contains(got, "@@ -16,5 +16,5 @@\n p\n q\n r\n-s\n+S\n t\n") {
+		t.Errorf("unexpected second hunk:\n%s", got)
+	}
+}
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+)
+
+// maxFeedEntries caps each feed at its most recent releases.
+const maxFeedEntries = 50
+
+// atomNamespace is the Atom 1.0 XML namespace.
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Links    []atomLink   `xml:"link"`
+	Category atomCategory `xml:"category"`
+	Content  atomContent  `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feedItem is a release in a feed.
+type feedItem struct {
+	page  *Page
+	entry *changelog.ParsedEntry
+}
+
+// renderAtom renders feed.xml, with the latest releases of every component,
+// and a <slug>.xml feed per component. Releases without a date in their
+// changelog header are left out. With repoURL, entries link to their GitHub
+// release.
+func renderAtom(pages []*Page, repoURL string) (map[string]string, error) {
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	files := make(map[string]string, len(pages)+1)
+
+	var all []feedItem
+	for _, p := range pages {
+		var items []feedItem
+		for _, e := range p.Entries {
+			if e.Date == "" {
+				slog.Debug("leaving undated release out of feeds", "component", p.Component, "version", e.Version)
+				continue
+			}
+			items = append(items, feedItem{page: p, entry: e})
+		}
+		all = append(all, items...)
+
+		out, err := renderFeed(p.Component+" releases", feedID(repoURL, p.Slug), repoURL, items)
+		if err != nil {
+			return nil, err
+		}
+		files[p.Slug+".xml"] = out
+	}
+
+	out, err := renderFeed("Releases", feedID(repoURL, ""), repoURL, all)
+	if err != nil {
+		return nil, err
+	}
+	files["feed.xml"] = out
+	return files, nil
+}
+
+// renderFeed renders a feed of items, newest first.
+func renderFeed(title, id, repoURL string, items []feedItem) (string, error) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].entry.Date > items[j].entry.Date
+	})
+	if len(items) > maxFeedEntries {
+		items = items[:maxFeedEntries]
+	}
+
+	feed := atomFeed{
+		XMLNS:   atomNamespace,
+		ID:      id,
+		Title:   title,
+		Updated: "1970-01-01T00:00:00Z",
+		Author:  atomAuthor{Name: feedAuthor(repoURL)},
+	}
+	if repoURL != "" {
+		feed.Links = []atomLink{{Rel: "alternate", Href: repoURL + "/releases"}}
+	}
+	if len(items) > 0 {
+		feed.Updated = atomDate(items[0].entry.Date)
+	}
+
+	for _, item := range items {
+		tag := fmt.Sprintf("%s-v%s", item.page.Component, item.entry.Version)
+		entry := atomEntry{
+			ID:       feedID(repoURL, item.page.Slug+"/"+item.entry.Version),
+			Title:    fmt.Sprintf("%s %s", item.page.Component, item.entry.Version),
+			Updated:  atomDate(item.entry.Date),
+			Category: atomCategory{Term: item.page.Component},
+			Content:  atomContent{Type: "html", Body: markdownToHTML(item.entry.Markdown, "")},
+		}
+		if releaseURL := changelog.BuildReleaseURL(repoURL, tag); releaseURL != "" {
+			entry.ID = releaseURL
+			entry.Links = []atomLink{{Rel: "alternate", Href: releaseURL}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s feed: %w", title, err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// feedID returns a stable ID for a feed or entry, named by slash-separated
+// parts: under the repository's releases URL if it's known, otherwise a
+// release-damnit URN.
+func feedID(repoURL, name string) string {
+	var parts []string
+	if name != "" {
+		for _, part := range strings.Split(name, "/") {
+			parts = append(parts, url.PathEscape(part))
+		}
+	}
+	if repoURL != "" {
+		if len(parts) == 0 {
+			return repoURL + "/releases"
+		}
+		return repoURL + "/releases#" + strings.Join(parts, "/")
+	}
+	return strings.Join(append([]string{"urn:release-damnit:releases"}, parts...), ":")
+}
+
+// feedAuthor names the repository (owner/repo), or release-damnit if the
+// repository URL isn't known.
+func feedAuthor(repoURL string) string {
+	if u, err := url.Parse(repoURL); err == nil && strings.Trim(u.Path, "/") != "" {
+		return strings.Trim(u.Path, "/")
+	}
+	return "release-damnit"
+}
+
+// atomDate converts a changelog date (YYYY-MM-DD) to an Atom timestamp.
+func atomDate(date string) string {
+	return date + "T00:00:00Z"
+}
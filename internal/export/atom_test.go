@@ -0,0 +1,92 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+)
+
+func testFeedPages() []*Page {
+	return []*Page{
+		{
+			Component: "api",
+			Slug:      "api",
+			Entries: []*changelog.ParsedEntry{
+				{Version: "1.1.0", Date: "2024-03-01", Markdown: "## [1.1.0] (2024-03-01)\n\n* **auth:** add tokens\n"},
+				{Version: "1.0.0", Date: "2024-01-01", Markdown: "## [1.0.0] (2024-01-01)\n\n* first\n"},
+			},
+		},
+		{
+			Component: "worker",
+			Slug:      "worker",
+			Entries: []*changelog.ParsedEntry{
+				{Version: "0.2.0", Date: "2024-02-01", Markdown: "## [0.2.0] (2024-02-01)\n\n* second\n"},
+				{Version: "0.1.0", Markdown: "## 0.1.0\n\n* undated\n"},
+			},
+		},
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	files, err := Render(testFeedPages(), &Options{Format: FormatAtom, RepoURL: "https://github.com/org/repo/"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected feed.xml, api.xml, and worker.xml, got %d files", len(files))
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(files["feed.xml"]), &feed); err != nil {
+		t.Fatalf("invalid feed.xml: %v", err)
+	}
+	if feed.ID != "https://github.com/org/repo/releases" || feed.Author.Name != "org/repo" || feed.Updated != "2024-03-01T00:00:00Z" {
+		t.Errorf("unexpected feed: %+v", feed)
+	}
+	var titles []string
+	for _, e := range feed.Entries {
+		titles = append(titles, e.Title)
+	}
+	if got := strings.Join(titles, ","); got != "api 1.1.0,worker 0.2.0,api 1.0.0" {
+		t.Errorf("expected dated releases newest first, got %s", got)
+	}
+
+	first := feed.Entries[0]
+	if first.ID != "https://github.com/org/repo/releases/tag/api-v1.1.0" || len(first.Links) != 1 || first.Links[0].Href != first.ID {
+		t.Errorf("expected the entry to link to its release, got %+v", first)
+	}
+	if first.Category.Term != "api" || first.Content.Type != "html" || !strings.Contains(first.Content.Body, "<strong>auth:</strong> add tokens") {
+		t.Errorf("unexpected entry: %+v", first)
+	}
+	if !strings.Contains(files["feed.xml"], "&lt;strong&gt;") {
+		t.Error("expected the HTML content to be escaped")
+	}
+
+	var worker atomFeed
+	if err := xml.Unmarshal([]byte(files["worker.xml"]), &worker); err != nil {
+		t.Fatalf("invalid worker.xml: %v", err)
+	}
+	if worker.Title != "worker releases" || len(worker.Entries) != 1 || worker.Entries[0].Title != "worker 0.2.0" {
+		t.Errorf("unexpected worker feed: %+v", worker)
+	}
+}
+
+func TestRenderAtom_NoRepoURL(t *testing.T) {
+	files, err := Render(testFeedPages(), &Options{Format: FormatAtom})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(files["api.xml"]), &feed); err != nil {
+		t.Fatalf("invalid api.xml: %v", err)
+	}
+	if feed.ID != "urn:release-damnit:releases:api" || feed.Author.Name != "release-damnit" || len(feed.Links) != 0 {
+		t.Errorf("unexpected feed: %+v", feed)
+	}
+	if id := feed.Entries[0].ID; id != "urn:release-damnit:releases:api:1.1.0" {
+		t.Errorf("unexpected entry ID: %s", id)
+	}
+}
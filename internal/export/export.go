@@ -0,0 +1,216 @@
+// Package export renders package changelogs as a static release notes site.
+// It reads every package's changelog back into version entries and writes
+// one page per component plus an index, as plain HTML or as Hugo content, or
+// Atom feeds of the releases.
+package export
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Export formats.
+const (
+	// FormatHTML writes standalone HTML pages.
+	FormatHTML = "html"
+
+	// FormatHugo writes markdown content with front matter for Hugo.
+	FormatHugo = "hugo"
+
+	// FormatAtom writes Atom feeds, one per component and one of all releases.
+	FormatAtom = "atom"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{FormatHTML, FormatHugo, FormatAtom}
+
+// Options configures Export.
+type Options struct {
+	// Format is one of Formats.
+	Format string
+
+	// OutDir is the directory files are written to.
+	OutDir string
+
+	// RepoURL is the repository's web URL, used for release links in feeds.
+	// Optional.
+	RepoURL string
+}
+
+// Page is one component's changelog.
+type Page struct {
+	// Component is the package's component name.
+	Component string
+
+	// Path is the package path relative to the repo root.
+	Path string
+
+	// Slug is the component's file name on the site.
+	Slug string
+
+	// Entries are the component's releases, newest first.
+	Entries []*changelog.ParsedEntry
+}
+
+// Latest returns the newest entry.
+func (p *Page) Latest() *changelog.ParsedEntry {
+	return p.Entries[0]
+}
+
+// LoadPages reads the changelogs of every package in cfg, in component
+// order. Packages without changelog entries are left out.
+func LoadPages(cfg *config.Config) ([]*Page, error) {
+	contracts.RequireNotNil(cfg, "cfg")
+
+	var pages []*Page
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		entries, err := loadEntries(cfg.RepoRoot, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changelog for %s: %w", pkg.Component, err)
+		}
+		if len(entries) == 0 {
+			slog.Debug("skipping package without changelog entries", "component", pkg.Component)
+			continue
+		}
+		pages = append(pages, &Page{
+			Component: pkg.Component,
+			Path:      pkg.Path,
+			Slug:      slugify(pkg.Component),
+			Entries:   entries,
+		})
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].Component < pages[j].Component
+	})
+	return pages, nil
+}
+
+// loadEntries reads a package's entries, newest first: from its changelog
+// file, or from every version file in its changelog directory.
+func loadEntries(repoRoot string, pkg *config.Package) ([]*changelog.ParsedEntry, error) {
+	path := filepath.Join(repoRoot, pkg.Path, pkg.ChangelogPath)
+	if pkg.ChangelogLayout != config.ChangelogLayoutDirectory {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return changelog.ParseEntries(string(data)), nil
+	}
+
+	files, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []*changelog.ParsedEntry
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".md" || f.Name() == config.ChangelogIndexFile {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, changelog.ParseEntries(string(data))...)
+	}
+	sortNewestFirst(entries)
+	return entries, nil
+}
+
+// sortNewestFirst orders entries by descending version. Entries whose
+// version doesn't parse go last.
+func sortNewestFirst(entries []*changelog.ParsedEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, errA := version.Parse(entries[i].Version)
+		b, errB := version.Parse(entries[j].Version)
+		switch {
+		case errA != nil || errB != nil:
+			return errA == nil && errB != nil
+		default:
+			return a.Compare(b) > 0
+		}
+	})
+}
+
+// Render returns the site's files, keyed by path relative to the output
+// directory.
+func Render(pages []*Page, opts *Options) (map[string]string, error) {
+	contracts.RequireNotNil(opts, "opts")
+
+	switch opts.Format {
+	case FormatHTML:
+		return renderHTML(pages)
+	case FormatHugo:
+		return renderHugo(pages), nil
+	case FormatAtom:
+		return renderAtom(pages, opts.RepoURL)
+	}
+	return nil, fmt.Errorf("unknown export format %q (want one of %s)", opts.Format, strings.Join(Formats, ", "))
+}
+
+// Export renders the changelogs in cfg and writes them under opts.OutDir.
+// Returns the paths written, relative to opts.OutDir.
+func Export(cfg *config.Config, opts *Options) ([]string, error) {
+	contracts.RequireNotNil(opts, "opts")
+	contracts.RequireNotEmpty(opts.OutDir, "outDir")
+
+	pages, err := LoadPages(cfg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := Render(pages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		full := filepath.Join(opts.OutDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := os.WriteFile(full, []byte(files[path]), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return paths, nil
+}
+
+// slugify turns a component name into a file name: lowercase, with runs of
+// anything but letters, digits, dots, and underscores replaced by "-".
+func slugify(component string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(component) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(sb.String(), "-")
+	if slug == "" {
+		return "component"
+	}
+	return slug
+}
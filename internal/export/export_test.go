@@ -0,0 +1,113 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func writeTestFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// testConfig returns a config with a file-layout package (api), a
+// directory-layout package (worker), and a package without a changelog (web).
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "services/api/CHANGELOG.md", `# Changelog
+
+## [1.1.0](https://github.com/org/repo/compare/api-v1.0.0...api-v1.1.0) (2024-02-01)
+
+### Features
+
+* **auth:** add tokens ([abc1234](https://github.com/org/repo/commit/abc1234))
+
+## [1.0.0] (2024-01-01)
+
+* initial release
+`)
+	writeTestFile(t, dir, "services/worker/changelogs/README.md", "# Changelog\n\n* [0.10.0](0.10.0.md) (2024-03-01)\n* [0.9.0](0.9.0.md) (2024-02-01)\n")
+	writeTestFile(t, dir, "services/worker/changelogs/0.9.0.md", "## [0.9.0] (2024-02-01)\n\n* first\n")
+	writeTestFile(t, dir, "services/worker/changelogs/0.10.0.md", "## [0.10.0] (2024-03-01)\n\n* second\n")
+
+	return &config.Config{
+		RepoRoot: dir,
+		Packages: map[string]*config.Package{
+			"services/api":    {Path: "services/api", Component: "api", ChangelogPath: "CHANGELOG.md", ChangelogLayout: config.ChangelogLayoutFile},
+			"services/worker": {Path: "services/worker", Component: "Worker Jobs", ChangelogPath: "changelogs", ChangelogLayout: config.ChangelogLayoutDirectory},
+			"services/web":    {Path: "services/web", Component: "web", ChangelogPath: "CHANGELOG.md", ChangelogLayout: config.ChangelogLayoutFile},
+		},
+	}
+}
+
+func TestLoadPages(t *testing.T) {
+	pages, err := LoadPages(testConfig(t))
+	if err != nil {
+		t.Fatalf("LoadPages failed: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected pages for api and Worker Jobs, got %d", len(pages))
+	}
+
+	api, worker := pages[1], pages[0]
+	if api.Component != "api" || api.Slug != "api" || len(api.Entries) != 2 || api.Latest().Version != "1.1.0" {
+		t.Errorf("unexpected api page: %+v", api)
+	}
+	if worker.Slug != "worker-jobs" || len(worker.Entries) != 2 {
+		t.Fatalf("unexpected worker page: %+v", worker)
+	}
+	if worker.Entries[0].Version != "0.10.0" || worker.Entries[1].Version != "0.9.0" {
+		t.Errorf("expected directory entries newest first, got %s, %s", worker.Entries[0].Version, worker.Entries[1].Version)
+	}
+}
+
+func TestExport(t *testing.T) {
+	out := t.TempDir()
+	paths, err := Export(testConfig(t), &Options{Format: FormatHugo, OutDir: out})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if strings.Join(paths, ",") != "_index.md,api.md,worker-jobs.md" {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "api.md"))
+	if err != nil {
+		t.Fatalf("failed to read api.md: %v", err)
+	}
+	if !strings.Contains(string(data), "* **auth:** add tokens") {
+		t.Errorf("expected the changelog in api.md, got:\n%s", data)
+	}
+
+	if _, err := Export(testConfig(t), &Options{Format: "pdf", OutDir: out}); err == nil || !strings.Contains(err.Error(), "unknown export format") {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"api":          "api",
+		"Worker Jobs":  "worker-jobs",
+		"@scope/pkg":   "scope-pkg",
+		"lib_v2.core":  "lib_v2.core",
+		"--":           "component",
+		"a//b":         "a-b",
+		"trailing!":    "trailing",
+		"UPPER-lower1": "upper-lower1",
+	}
+	for in, want := range tests {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
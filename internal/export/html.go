@@ -0,0 +1,182 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// pageTemplate lays out both the index and the component pages.
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+a { color: #0969da; }
+code { background: #f3f3f3; padding: 0 .2em; border-radius: 3px; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .3em; margin-top: 2em; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: .3em 1em .3em 0; }
+</style>
+</head>
+<body>
+{{if .Back}}<p><a href="index.html">&larr; All components</a></p>
+{{end}}<h1>{{.Title}}</h1>
+{{.Body}}
+</body>
+</html>
+`))
+
+type htmlPage struct {
+	Title string
+	Back  bool
+	Body  template.HTML
+}
+
+// renderHTML renders index.html, listing each component's latest release,
+// and a <slug>.html page per component.
+func renderHTML(pages []*Page) (map[string]string, error) {
+	files := make(map[string]string, len(pages)+1)
+
+	var index strings.Builder
+	if len(pages) == 0 {
+		index.WriteString("<p>No releases yet.</p>\n")
+	} else {
+		index.WriteString("<table>\n<tr><th>Component</th><th>Latest</th><th>Released</th></tr>\n")
+		for _, p := range pages {
+			latest := p.Latest()
+			fmt.Fprintf(&index, "<tr><td><a href=\"%s.html\">%s</a></td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(p.Slug), html.EscapeString(p.Component), html.EscapeString(latest.Version), html.EscapeString(latest.Date))
+		}
+		index.WriteString("</table>\n")
+	}
+	out, err := executePage(htmlPage{Title: "Release Notes", Body: template.HTML(index.String())})
+	if err != nil {
+		return nil, err
+	}
+	files["index.html"] = out
+
+	for _, p := range pages {
+		var body strings.Builder
+		for _, e := range p.Entries {
+			body.WriteString(markdownToHTML(e.Markdown, "v"+e.Version))
+		}
+		out, err := executePage(htmlPage{Title: p.Component, Back: true, Body: template.HTML(body.String())})
+		if err != nil {
+			return nil, err
+		}
+		files[p.Slug+".html"] = out
+	}
+	return files, nil
+}
+
+func executePage(page htmlPage) (string, error) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, page); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", page.Title, err)
+	}
+	return buf.String(), nil
+}
+
+var (
+	markdownLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownBoldRegex = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownCodeRegex = regexp.MustCompile("`([^`]+)`")
+)
+
+// markdownToHTML converts a changelog entry to HTML. It handles what
+// changelog.Generate writes (headers, nested bullets, links, bold, and code
+// spans), treating other lines as paragraphs. The entry's first header gets
+// id as its anchor.
+func markdownToHTML(markdown, id string) string {
+	var sb strings.Builder
+	depth := 0 // open <ul> levels
+	closeLists := func(to int) {
+		for ; depth > to; depth-- {
+			sb.WriteString("</li></ul>\n")
+		}
+	}
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+
+		case strings.HasPrefix(trimmed, "#"):
+			closeLists(0)
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 {
+				level = 6
+			}
+			attr := ""
+			if id != "" {
+				attr = fmt.Sprintf(" id=%q", html.EscapeString(id))
+				id = ""
+			}
+			fmt.Fprintf(&sb, "<h%d%s>%s</h%d>\n", level, attr, inlineHTML(strings.TrimSpace(trimmed[level:])), level)
+
+		case strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "- "):
+			level := indentOf(line)/2 + 1
+			if level > depth+1 {
+				level = depth + 1
+			}
+			switch {
+			case level > depth:
+				sb.WriteString("<ul>\n")
+				depth = level
+			case level < depth:
+				closeLists(level)
+				sb.WriteString("</li>\n")
+			default:
+				sb.WriteString("</li>\n")
+			}
+			fmt.Fprintf(&sb, "<li>%s", inlineHTML(trimmed[2:]))
+
+		default:
+			closeLists(0)
+			fmt.Fprintf(&sb, "<p>%s</p>\n", inlineHTML(trimmed))
+		}
+	}
+	closeLists(0)
+	return sb.String()
+}
+
+// inlineHTML escapes text and converts its links, bold, and code spans.
+func inlineHTML(text string) string {
+	text = html.EscapeString(text)
+	text = markdownCodeRegex.ReplaceAllString(text, "<code>$1</code>")
+	text = markdownBoldRegex.ReplaceAllString(text, "<strong>$1</strong>")
+	return markdownLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		m := markdownLinkRegex.FindStringSubmatch(link)
+		if !safeURL(html.UnescapeString(m[2])) {
+			return m[1]
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, m[2], m[1])
+	})
+}
+
+// safeURL reports whether a link target is http(s), mailto, or relative, so
+// commit text can't add script links to the page.
+func safeURL(u string) bool {
+	scheme, _, found := strings.Cut(u, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// indentOf returns the number of leading spaces on a line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
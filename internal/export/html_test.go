@@ -0,0 +1,70 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	markdown := "## [1.1.0](https://example.com/compare) (2024-02-01)\n\n" +
+		"### Features\n\n" +
+		"* **auth:** add `Token` <type> ([abc1234](https://example.com/c/abc1234))\n" +
+		"  * Old tokens expire\n" +
+		"* second\n"
+
+	want := `<h2 id="v1.1.0"><a href="https://example.com/compare">1.1.0</a> (2024-02-01)</h2>
+<h3>Features</h3>
+<ul>
+<li><strong>auth:</strong> add <code>Token</code> &lt;type&gt; (<a href="https://example.com/c/abc1234">abc1234</a>)<ul>
+<li>Old tokens expire</li></ul>
+</li>
+<li>second</li></ul>
+`
+	if got := markdownToHTML(markdown, "v1.1.0"); got != want {
+		t.Errorf("markdownToHTML() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInlineHTML_UnsafeLinks(t *testing.T) {
+	tests := map[string]string{
+		"[x](javascript:alert(1))":  "x)",
+		"[x](JavaScript:void)":      "x",
+		"[x](mailto:a@example.com)": `<a href="mailto:a@example.com">x</a>`,
+		"[x](docs/page.html)":       `<a href="docs/page.html">x</a>`,
+		"[x](/a?b=c:d)":             `<a href="/a?b=c:d">x</a>`,
+	}
+	for in, want := range tests {
+		if got := inlineHTML(in); got != want {
+			t.Errorf("inlineHTML(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	pages := []*Page{{
+		Component: "a<b",
+		Slug:      "a-b",
+		Entries:   []*changelog.ParsedEntry{{Version: "1.0.0", Date: "2024-01-01", Markdown: "## 1.0.0\n\n* first\n"}},
+	}}
+	files, err := Render(pages, &Options{Format: FormatHTML})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected index.html and a-b.html, got %d files", len(files))
+	}
+	if index := files["index.html"]; !strings.Contains(index, `<a href="a-b.html">a&lt;b</a></td><td>1.0.0</td><td>2024-01-01</td>`) {
+		t.Errorf("unexpected index:\n%s", index)
+	}
+	page := files["a-b.html"]
+	if !strings.Contains(page, "<title>a&lt;b</title>") || !strings.Contains(page, `<h2 id="v1.0.0">1.0.0</h2>`) {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+
+	files, err = Render(nil, &Options{Format: FormatHTML})
+	if err != nil || !strings.Contains(files["index.html"], "No releases yet") {
+		t.Errorf("expected an empty index, got %v, %v", files, err)
+	}
+}
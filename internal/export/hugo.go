@@ -0,0 +1,50 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// renderHugo renders Hugo content: a _index.md section page and a <slug>.md
+// page per component, each with YAML front matter. Copy the output into a
+// content directory (e.g., content/releases) to publish it.
+func renderHugo(pages []*Page) map[string]string {
+	files := make(map[string]string, len(pages)+1)
+	files["_index.md"] = frontMatter([][2]string{{"title", strconv.Quote("Release Notes")}}) +
+		"\nRelease notes for each component, newest release first.\n"
+
+	for i, p := range pages {
+		latest := p.Latest()
+		fields := [][2]string{
+			{"title", strconv.Quote(p.Component)},
+			{"component", strconv.Quote(p.Component)},
+			{"path", strconv.Quote(p.Path)},
+			{"version", strconv.Quote(latest.Version)},
+		}
+		if latest.Date != "" {
+			fields = append(fields, [2]string{"date", latest.Date})
+		}
+		fields = append(fields, [2]string{"weight", strconv.Itoa(i + 1)})
+
+		var body strings.Builder
+		for _, e := range p.Entries {
+			body.WriteString("\n")
+			body.WriteString(e.Markdown)
+		}
+		files[p.Slug+".md"] = frontMatter(fields) + body.String()
+	}
+	return files
+}
+
+// frontMatter returns a YAML front matter block with the fields in order.
+// Values are written as given, so strings must already be quoted.
+func frontMatter(fields [][2]string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	for _, f := range fields {
+		fmt.Fprintf(&sb, "%s: %s\n", f[0], f[1])
+	}
+	sb.WriteString("---\n")
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+)
+
+func TestRenderHugo(t *testing.T) {
+	pages := []*Page{{
+		Component: `my "api"`,
+		Path:      "services/api",
+		Slug:      "my-api",
+		Entries: []*changelog.ParsedEntry{
+			{Version: "1.1.0", Date: "2024-02-01", Markdown: "## [1.1.0] (2024-02-01)\n\n* second\n"},
+			{Version: "1.0.0", Date: "2024-01-01", Markdown: "## [1.0.0] (2024-01-01)\n\n* first\n"},
+		},
+	}}
+	files, err := Render(pages, &Options{Format: FormatHugo})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	want := `---
+title: "my \"api\""
+component: "my \"api\""
+path: "services/api"
+version: "1.1.0"
+date: 2024-02-01
+weight: 1
+---
+
+## [1.1.0] (2024-02-01)
+
+* second
+
+## [1.0.0] (2024-01-01)
+
+* first
+`
+	if got := files["my-api.md"]; got != want {
+		t.Errorf("my-api.md =\n%s\nwant:\n%s", got, want)
+	}
+	if files["_index.md"] == "" {
+		t.Error("expected a section page")
+	}
+}
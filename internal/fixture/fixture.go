@@ -0,0 +1,346 @@
+// Package fixture builds local git repositories for release scenarios: the
+// mock monorepo the e2e tests run against, with a feature branch per
+// scenario (nested packages, linked versions, breaking changes, ...).
+// Contributors build them with `release-damnit devtool make-fixture` to
+// reproduce bugs locally.
+//
+// Fixtures are deterministic: commit authors and dates are fixed, so the
+// same scenario always has the same commit SHAs.
+package fixture
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Scenario names.
+const (
+	ScenarioSingleFeat     = "single-feat"
+	ScenarioMultiPackage   = "multi-package"
+	ScenarioBreakingChange = "breaking-change"
+	ScenarioLinkedVersions = "linked-versions"
+	ScenarioStackedCommits = "stacked-commits"
+	ScenarioNestedPackage  = "nested-package"
+	ScenarioComplexMerge   = "complex-merge"
+)
+
+// MainBranch is the fixture's default branch. A "dev" branch is created at
+// the same commit.
+const MainBranch = "main"
+
+// Commit authorship and dates, fixed so fixtures are reproducible.
+const (
+	authorName  = "release-damnit Test"
+	authorEmail = "release-damnit-test@dsswift.io"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Scenario is a feature branch off main that exercises one release case.
+type Scenario struct {
+	// Name identifies the scenario (e.g., "linked-versions").
+	Name string
+
+	// Description says what the scenario tests and what should be released.
+	Description string
+
+	// Commits are the branch's commits, oldest first.
+	Commits []Commit
+}
+
+// Branch returns the scenario's branch name (e.g., "feature/linked-versions").
+func (s *Scenario) Branch() string {
+	return "feature/" + s.Name
+}
+
+// Commit is one commit on a scenario branch: a line appended to a file.
+type Commit struct {
+	Path    string
+	Line    string
+	Message string
+}
+
+// scenarios are the known scenarios, in the order their branches are made.
+var scenarios = []*Scenario{
+	{
+		Name:        ScenarioSingleFeat,
+		Description: "1 feat commit: jarvis 0.1.0 -> 0.1.1",
+		Commits: []Commit{
+			{"workloads/jarvis/backend/src/main.go", "// New calendar integration", "feat(jarvis): add calendar integration"},
+		},
+	},
+	{
+		Name:        ScenarioMultiPackage,
+		Description: "3 feat commits: jarvis, sandbox-portal, and infrastructure each bump",
+		Commits: []Commit{
+			{"workloads/jarvis/backend/src/main.go", "// Recipe improvements", "feat(jarvis): improve recipe search"},
+			{"platforms/sandbox/portal/src/main.py", "// Dashboard redesign", "feat(sandbox-portal): redesign dashboard"},
+			{"infrastructure/terraform/modules/README.md", "# Add monitoring module", "feat(infrastructure): add monitoring module"},
+		},
+	},
+	{
+		Name:        ScenarioBreakingChange,
+		Description: "1 feat! commit: sandbox-portal 0.1.0 -> 1.0.0",
+		Commits: []Commit{
+			{"platforms/sandbox/portal/src/main.py", "// BREAKING: New API structure", "feat(sandbox-portal)!: redesign API with breaking changes"},
+		},
+	},
+	{
+		Name:        ScenarioLinkedVersions,
+		Description: "1 commit to ma-observe-client: client and server both bump (linked versions)",
+		Commits: []Commit{
+			{"workloads/ma-observe/client/src/client.go", "// New metrics collector", "feat(ma-observe-client): add metrics collector"},
+		},
+	},
+	{
+		Name:        ScenarioStackedCommits,
+		Description: "chore, fix, feat, fix to jarvis-discord: one bump, feat wins",
+		Commits: []Commit{
+			{"workloads/jarvis/clients/discord/src/bot.ts", "// Chore: cleanup", "chore(jarvis-discord): cleanup old code"},
+			{"workloads/jarvis/clients/discord/src/bot.ts", "// Fix: handle rate limits", "fix(jarvis-discord): handle rate limits properly"},
+			{"workloads/jarvis/clients/discord/src/bot.ts", "// Feature: slash commands", "feat(jarvis-discord): add slash commands support"},
+			{"workloads/jarvis/clients/discord/src/bot.ts", "// Another fix", "fix(jarvis-discord): fix command parsing"},
+		},
+	},
+	{
+		Name:        ScenarioNestedPackage,
+		Description: "1 commit inside jarvis/clients/web: jarvis-web bumps, jarvis doesn't",
+		Commits: []Commit{
+			{"workloads/jarvis/clients/web/src/App.tsx", "// New React component", "feat(jarvis-web): add notification component"},
+		},
+	},
+	{
+		Name:        ScenarioComplexMerge,
+		Description: "11 commits: all 8 packages bump, sandbox-portal to 1.0.0, ma-observe-server through its link",
+		Commits: []Commit{
+			{"workloads/jarvis/backend/src/main.go", "// Add recipe feature", "feat(jarvis): add recipe recommendation engine"},
+			{"workloads/jarvis/backend/src/main.go", "// Fix recipe bug", "fix(jarvis): handle missing ingredients gracefully"},
+			{"workloads/jarvis/clients/web/src/App.tsx", "// Fix button styling", "fix(jarvis-web): fix button alignment on mobile"},
+			{"workloads/jarvis/clients/web/src/App.tsx", "// Add dark mode", "feat(jarvis-web): add dark mode support"},
+			{"workloads/jarvis/clients/web/src/App.tsx", "// Cleanup imports", "chore(jarvis-web): cleanup unused imports"},
+			{"workloads/jarvis/clients/discord/src/bot.ts", "// Handle rate limit", "fix(jarvis-discord): handle Discord API rate limits"},
+			{"workloads/ma-observe/client/src/client.go", "// Add new metric type", "feat(ma-observe-client): add CPU temperature metric"},
+			{"workloads/ma-observe/client/src/client.go", "// Fix metric parsing", "fix(ma-observe-client): fix metric timestamp parsing"},
+			{"platforms/sandbox/portal/src/main.py", "// BREAKING: New API v2", "feat(sandbox-portal)!: redesign REST API with v2 schema\n\nBREAKING CHANGE: All API endpoints now use /api/v2 prefix.\nOld /api/v1 endpoints are removed."},
+			{"platforms/sandbox/images/claude-code/Dockerfile", "RUN apt-get update", "feat(sandbox-image-claude-code): add system package updates"},
+			{"infrastructure/terraform/modules/README.md", "# Fix module path", "fix(infrastructure): correct module source path"},
+		},
+	},
+}
+
+// Scenarios returns the known scenarios.
+func Scenarios() []*Scenario {
+	return scenarios
+}
+
+// Lookup returns the named scenario.
+func Lookup(name string) (*Scenario, error) {
+	names := make([]string, 0, len(scenarios))
+	for _, s := range scenarios {
+		if s.Name == name {
+			return s, nil
+		}
+		names = append(names, s.Name)
+	}
+	return nil, fmt.Errorf("unknown scenario %q (want one of %s)", name, strings.Join(names, ", "))
+}
+
+// Options configures Make.
+type Options struct {
+	// Dir is where the repository is created. It must not exist or be empty.
+	Dir string
+
+	// Scenarios are the names of the scenario branches to create. Empty
+	// means all of them.
+	Scenarios []string
+
+	// Merge if true, merges the scenario's branch into main with --no-ff,
+	// leaving HEAD on the merge commit release-damnit analyzes. Requires
+	// exactly one scenario.
+	Merge bool
+
+	// Origin is a remote to force-push main, dev, and the scenario branches
+	// to, as "origin". A local path that doesn't exist is created as a bare
+	// repository. Optional.
+	Origin string
+}
+
+// Fixture is a created repository.
+type Fixture struct {
+	// Dir is the repository's work tree.
+	Dir string
+
+	// Branches are the scenario branches created.
+	Branches []string
+
+	// Merged is the branch merged into main, if any.
+	Merged string
+}
+
+// Make creates a repository with the mock repo on main and dev, and a
+// branch per scenario.
+func Make(opts *Options) (*Fixture, error) {
+	contracts.RequireNotNil(opts, "opts")
+	contracts.RequireNotEmpty(opts.Dir, "Dir")
+
+	selected := scenarios
+	if len(opts.Scenarios) > 0 {
+		selected = nil
+		for _, name := range opts.Scenarios {
+			s, err := Lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, s)
+		}
+	}
+	if opts.Merge && len(selected) != 1 {
+		return nil, fmt.Errorf("merging requires exactly one scenario, got %d", len(selected))
+	}
+
+	if entries, err := os.ReadDir(opts.Dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", opts.Dir)
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", opts.Dir, err)
+	}
+
+	r := &repo{dir: opts.Dir}
+	r.git("init", "--initial-branch="+MainBranch)
+	r.git("config", "user.name", authorName)
+	r.git("config", "user.email", authorEmail)
+	r.git("config", "commit.gpgsign", "false")
+
+	files := mockFiles()
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		r.write(path, files[path])
+	}
+	r.git("add", "-A")
+	r.commit("chore: initial repository structure")
+	r.git("branch", "dev")
+
+	fixture := &Fixture{Dir: opts.Dir}
+	for _, s := range selected {
+		r.git("checkout", "-b", s.Branch())
+		for _, c := range s.Commits {
+			r.append(c.Path, c.Line+"\n")
+			r.git("add", c.Path)
+			r.commit(c.Message)
+		}
+		r.git("checkout", MainBranch)
+		fixture.Branches = append(fixture.Branches, s.Branch())
+	}
+
+	if opts.Origin != "" {
+		r.pushOrigin(opts.Origin, append([]string{MainBranch, "dev"}, fixture.Branches...))
+	}
+
+	if opts.Merge {
+		branch := selected[0].Branch()
+		r.commit("Merge "+branch, "merge", "--no-ff", branch)
+		fixture.Merged = branch
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	return fixture, nil
+}
+
+// repo runs the steps that build a fixture. After the first failure, later
+// steps do nothing and err holds the failure.
+type repo struct {
+	dir     string
+	commits int
+	err     error
+}
+
+// git runs a git command in the repo.
+func (r *repo) git(args ...string) {
+	r.gitEnv(nil, args...)
+}
+
+func (r *repo) gitEnv(env []string, args ...string) {
+	if r.err != nil {
+		return
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		r.err = fmt.Errorf("git %s failed: %v\nstderr: %s", strings.Join(args, " "), err, stderr.String())
+	}
+}
+
+// commit commits with the fixed author and the next fixed date. With args,
+// it runs them (e.g., a merge) with message instead of "commit".
+func (r *repo) commit(message string, args ...string) {
+	if len(args) == 0 {
+		args = []string{"commit"}
+	}
+	date := epoch.Add(time.Duration(r.commits) * time.Minute).Format(time.RFC3339)
+	r.commits++
+	r.gitEnv([]string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}, append(args, "-m", message)...)
+}
+
+func (r *repo) write(path, content string) {
+	if r.err != nil {
+		return
+	}
+	full := filepath.Join(r.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		r.err = err
+		return
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		r.err = err
+	}
+}
+
+func (r *repo) append(path, content string) {
+	if r.err != nil {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(r.dir, filepath.FromSlash(path)), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		r.err = err
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		r.err = err
+	}
+}
+
+// pushOrigin adds origin and force-pushes branches to it, creating a bare
+// repository first if origin is a local path that doesn't exist.
+func (r *repo) pushOrigin(origin string, branches []string) {
+	if r.err != nil {
+		return
+	}
+	if !strings.Contains(origin, ":") {
+		if _, err := os.Stat(origin); os.IsNotExist(err) {
+			if out, err := exec.Command("git", "init", "--bare", "--initial-branch="+MainBranch, origin).CombinedOutput(); err != nil {
+				r.err = fmt.Errorf("failed to create %s: %v\n%s", origin, err, out)
+				return
+			}
+		}
+	}
+	r.git("remote", "add", "origin", origin)
+	r.git(append([]string{"push", "--force", "origin"}, branches...)...)
+}
@@ -0,0 +1,148 @@
+package fixture
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestLookup(t *testing.T) {
+	s, err := Lookup(ScenarioLinkedVersions)
+	if err != nil || s.Branch() != "feature/linked-versions" {
+		t.Errorf("unexpected scenario %+v, %v", s, err)
+	}
+	if _, err := Lookup("nope"); err == nil || !strings.Contains(err.Error(), ScenarioComplexMerge) {
+		t.Errorf("expected unknown scenario error listing scenarios, got %v", err)
+	}
+}
+
+func TestMake_AllScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	fixture, err := Make(&Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Make failed: %v", err)
+	}
+	if len(fixture.Branches) != len(Scenarios()) || fixture.Merged != "" {
+		t.Fatalf("unexpected fixture: %+v", fixture)
+	}
+
+	if branch := gitOutput(t, dir, "branch", "--show-current"); branch != MainBranch {
+		t.Errorf("expected %s checked out, got %s", MainBranch, branch)
+	}
+	for _, s := range Scenarios() {
+		count := gitOutput(t, dir, "rev-list", "--count", MainBranch+".."+s.Branch())
+		if count != strconv.Itoa(len(s.Commits)) {
+			t.Errorf("%s: expected %d commits, got %s", s.Name, len(s.Commits), count)
+		}
+	}
+	if dev := gitOutput(t, dir, "rev-parse", "dev"); dev != gitOutput(t, dir, "rev-parse", MainBranch) {
+		t.Error("expected dev at main")
+	}
+}
+
+func TestMake_Deterministic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	var heads []string
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		if _, err := Make(&Options{Dir: dir, Scenarios: []string{ScenarioSingleFeat}}); err != nil {
+			t.Fatalf("Make failed: %v", err)
+		}
+		heads = append(heads, gitOutput(t, dir, "rev-parse", "feature/single-feat"))
+	}
+	if heads[0] != heads[1] {
+		t.Errorf("expected the same SHAs each time, got %v", heads)
+	}
+}
+
+func TestMake_Merge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	fixture, err := Make(&Options{Dir: dir, Scenarios: []string{ScenarioLinkedVersions}, Merge: true})
+	if err != nil {
+		t.Fatalf("Make failed: %v", err)
+	}
+	if fixture.Merged != "feature/linked-versions" {
+		t.Errorf("unexpected merged branch: %q", fixture.Merged)
+	}
+
+	result, err := release.Analyze(&release.Options{RepoPath: dir, DryRun: true, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.MergeInfo.IsMerge || len(result.Releases) != 2 {
+		t.Fatalf("expected a merge releasing both linked packages, got %d release(s)", len(result.Releases))
+	}
+	for _, rel := range result.Releases {
+		if rel.NewVersion != "0.1.1" {
+			t.Errorf("%s: expected 0.1.1, got %s", rel.Package.Component, rel.NewVersion)
+		}
+	}
+}
+
+func TestMake_Origin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	if _, err := Make(&Options{Dir: t.TempDir(), Scenarios: []string{ScenarioNestedPackage}, Origin: origin}); err != nil {
+		t.Fatalf("Make failed: %v", err)
+	}
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("clone failed: %v\n%s", err, out)
+	}
+	branches := gitOutput(t, clone, "branch", "-r")
+	for _, want := range []string{"origin/main", "origin/dev", "origin/feature/nested-package"} {
+		if !strings.Contains(branches, want) {
+			t.Errorf("expected %s in %q", want, branches)
+		}
+	}
+}
+
+func TestMake_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *Options
+		want string
+	}{
+		{"unknown scenario", &Options{Dir: t.TempDir(), Scenarios: []string{"nope"}}, "unknown scenario"},
+		{"merge without one scenario", &Options{Dir: t.TempDir(), Merge: true}, "exactly one scenario"},
+		{"non-empty dir", &Options{Dir: filepath.Dir(t.TempDir())}, "not empty"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Make(tc.opts); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected %q error, got %v", tc.want, err)
+			}
+		})
+	}
+}
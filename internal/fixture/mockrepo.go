@@ -0,0 +1,261 @@
+package fixture
+
+// The mock repo mirrors a real GitOps monorepo: nested packages (jarvis-web
+// inside jarvis), a linked-versions pair (ma-observe-client and
+// ma-observe-server), and the release workflow that runs release-damnit.
+
+// InitialVersion is every package's version in the mock repo.
+const InitialVersion = "0.1.0"
+
+// mockPackages are the mock repo's packages: path to component.
+var mockPackages = []struct{ Path, Component string }{
+	{"workloads/jarvis", "jarvis"},
+	{"workloads/jarvis/clients/web", "jarvis-web"},
+	{"workloads/jarvis/clients/discord", "jarvis-discord"},
+	{"workloads/ma-observe/client", "ma-observe-client"},
+	{"workloads/ma-observe/server", "ma-observe-server"},
+	{"platforms/sandbox/portal", "sandbox-portal"},
+	{"platforms/sandbox/images/claude-code", "sandbox-image-claude-code"},
+	{"infrastructure/terraform", "infrastructure"},
+}
+
+// mockSources are the placeholder source files scenarios append to.
+var mockSources = map[string]string{
+	"workloads/jarvis/backend/src/main.go":            "// Jarvis backend service\n",
+	"workloads/jarvis/clients/web/src/App.tsx":        "// Jarvis web client\n",
+	"workloads/jarvis/clients/discord/src/bot.ts":     "// Jarvis Discord bot\n",
+	"workloads/ma-observe/client/src/client.go":       "// MA-Observe client\n",
+	"workloads/ma-observe/server/src/server.go":       "// MA-Observe server\n",
+	"platforms/sandbox/portal/src/main.py":            "// Sandbox portal\n",
+	"platforms/sandbox/images/claude-code/Dockerfile": "FROM ubuntu:22.04\n",
+	"infrastructure/terraform/modules/README.md":      "# Terraform modules\n",
+}
+
+// mockFiles returns the mock repo's files on main: path to content.
+func mockFiles() map[string]string {
+	files := map[string]string{
+		"release-please-config.json":           mockConfig,
+		"release-please-manifest.json":         mockManifest,
+		".github/workflows/release-damnit.yml": mockWorkflow,
+		"README.md":                            mockReadme,
+	}
+	for path, content := range mockSources {
+		files[path] = content
+	}
+	for _, pkg := range mockPackages {
+		files[pkg.Path+"/VERSION"] = InitialVersion + " # x-release-please-version\n"
+		files[pkg.Path+"/CHANGELOG.md"] = mockChangelog
+	}
+	return files
+}
+
+const mockChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [0.1.0] - Initial Release
+
+Initial release.
+`
+
+const mockConfig = `{
+  "$schema": "https://raw.githubusercontent.com/googleapis/release-please/main/schemas/config.json",
+  "release-type": "simple",
+  "bump-minor-pre-major": true,
+  "bump-patch-for-minor-pre-major": true,
+  "include-component-in-tag": true,
+  "tag-separator": "-",
+  "packages": {
+    "workloads/jarvis": {
+      "component": "jarvis",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "workloads/jarvis/clients/web": {
+      "component": "jarvis-web",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "workloads/jarvis/clients/discord": {
+      "component": "jarvis-discord",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "workloads/ma-observe/client": {
+      "component": "ma-observe-client",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "workloads/ma-observe/server": {
+      "component": "ma-observe-server",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "platforms/sandbox/portal": {
+      "component": "sandbox-portal",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "platforms/sandbox/images/claude-code": {
+      "component": "sandbox-image-claude-code",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    },
+    "infrastructure/terraform": {
+      "component": "infrastructure",
+      "changelog-path": "CHANGELOG.md",
+      "extra-files": [{"type": "generic", "path": "VERSION"}]
+    }
+  },
+  "plugins": [
+    {
+      "type": "linked-versions",
+      "groupName": "ma-observe",
+      "components": ["ma-observe-client", "ma-observe-server"]
+    }
+  ]
+}
+`
+
+const mockManifest = `{
+  "workloads/jarvis": "0.1.0",
+  "workloads/jarvis/clients/web": "0.1.0",
+  "workloads/jarvis/clients/discord": "0.1.0",
+  "workloads/ma-observe/client": "0.1.0",
+  "workloads/ma-observe/server": "0.1.0",
+  "platforms/sandbox/portal": "0.1.0",
+  "platforms/sandbox/images/claude-code": "0.1.0",
+  "infrastructure/terraform": "0.1.0"
+}
+`
+
+const mockWorkflow = `name: Release (release-damnit)
+
+on:
+  push:
+    branches: [main]
+  workflow_dispatch:
+    inputs:
+      dry_run:
+        description: 'Dry run mode (no changes)'
+        required: false
+        default: 'false'
+        type: boolean
+
+permissions:
+  contents: write
+
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    outputs:
+      releases_created: ${{ steps.release.outputs.releases_created }}
+      jarvis_release_created: ${{ steps.release.outputs.jarvis_release_created }}
+      jarvis_web_release_created: ${{ steps.release.outputs.jarvis-web_release_created }}
+      jarvis_discord_release_created: ${{ steps.release.outputs.jarvis-discord_release_created }}
+      ma_observe_client_release_created: ${{ steps.release.outputs.ma-observe-client_release_created }}
+      ma_observe_server_release_created: ${{ steps.release.outputs.ma-observe-server_release_created }}
+      sandbox_portal_release_created: ${{ steps.release.outputs.sandbox-portal_release_created }}
+      sandbox_image_claude_code_release_created: ${{ steps.release.outputs.sandbox-image-claude-code_release_created }}
+      infrastructure_release_created: ${{ steps.release.outputs.infrastructure_release_created }}
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+
+      - name: Run release-damnit
+        id: release
+        uses: dsswift/release-damnit@main
+        with:
+          token: ${{ secrets.GITHUB_TOKEN }}
+          dry_run: ${{ inputs.dry_run || 'false' }}
+          create_releases: 'true'
+
+      - name: Show results
+        run: |
+          echo "Releases created: ${{ steps.release.outputs.releases_created }}"
+          echo "---"
+          echo "jarvis: ${{ steps.release.outputs.jarvis_release_created }}"
+          echo "jarvis-web: ${{ steps.release.outputs.jarvis-web_release_created }}"
+          echo "jarvis-discord: ${{ steps.release.outputs.jarvis-discord_release_created }}"
+          echo "ma-observe-client: ${{ steps.release.outputs.ma-observe-client_release_created }}"
+          echo "ma-observe-server: ${{ steps.release.outputs.ma-observe-server_release_created }}"
+          echo "sandbox-portal: ${{ steps.release.outputs.sandbox-portal_release_created }}"
+          echo "sandbox-image-claude-code: ${{ steps.release.outputs.sandbox-image-claude-code_release_created }}"
+          echo "infrastructure: ${{ steps.release.outputs.infrastructure_release_created }}"
+
+  # Example downstream build jobs (would be real builds in sh-monorepo)
+  build-jarvis:
+    needs: release
+    if: needs.release.outputs.jarvis_release_created == 'true'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "Building jarvis..."
+
+  build-jarvis-web:
+    needs: release
+    if: needs.release.outputs.jarvis_web_release_created == 'true'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "Building jarvis-web..."
+
+  build-ma-observe:
+    needs: release
+    if: needs.release.outputs.ma_observe_client_release_created == 'true' || needs.release.outputs.ma_observe_server_release_created == 'true'
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "Building ma-observe (client and/or server)..."
+`
+
+const mockReadme = `# mock--gitops-playground
+
+Test repository for release-damnit E2E tests. **DO NOT USE FOR PRODUCTION.**
+
+This repository is periodically reset by the release-damnit test suite.
+
+## Structure
+
+` + "`" + `` + "`" + `` + "`" + `
+workloads/
+├── jarvis/                    # jarvis (backend)
+│   └── clients/
+│       ├── web/               # jarvis-web (nested under jarvis)
+│       └── discord/           # jarvis-discord (nested under jarvis)
+└── ma-observe/
+    ├── client/                # ma-observe-client (linked with server)
+    └── server/                # ma-observe-server (linked with client)
+platforms/
+└── sandbox/
+    ├── portal/                # sandbox-portal
+    └── images/
+        └── claude-code/       # sandbox-image-claude-code
+infrastructure/
+└── terraform/                 # infrastructure
+` + "`" + `` + "`" + `` + "`" + `
+
+## Package Behaviors
+
+| Package | Behavior |
+|---------|----------|
+| jarvis | Standalone - changes to jarvis/ (but not clients/) |
+| jarvis-web | Nested - changes to jarvis/clients/web/ |
+| jarvis-discord | Nested - changes to jarvis/clients/discord/ |
+| ma-observe-client | Linked - bumps together with ma-observe-server |
+| ma-observe-server | Linked - bumps together with ma-observe-client |
+| sandbox-portal | Standalone |
+| sandbox-image-claude-code | Standalone |
+| infrastructure | Standalone |
+
+## Feature Branches
+
+These branches test different release scenarios:
+
+| Branch | Scenario |
+|--------|----------|
+| feature/single-feat | One feat commit to jarvis |
+| feature/multi-package | Commits to jarvis, sandbox-portal, infrastructure |
+| feature/breaking-change | feat!: breaking change (major bump) |
+| feature/linked-versions | Change to ma-observe-client (triggers both) |
+| feature/stacked-commits | Multiple commits with different severities |
+| feature/nested-package | Change to jarvis-web (nested inside jarvis) |
+`
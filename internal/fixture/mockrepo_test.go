@@ -0,0 +1,50 @@
+package fixture
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestMockFiles_Config(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range mockFiles() {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Packages) != len(mockPackages) {
+		t.Fatalf("expected %d packages, got %d", len(mockPackages), len(cfg.Packages))
+	}
+	for _, want := range mockPackages {
+		pkg, ok := cfg.Packages[want.Path]
+		if !ok || pkg.Component != want.Component || pkg.CurrentVersion != InitialVersion {
+			t.Errorf("expected %s (%s) at %s, got %+v", want.Path, want.Component, InitialVersion, pkg)
+		}
+	}
+	if group := cfg.LinkedGroups["ma-observe"]; len(group) != 2 {
+		t.Errorf("expected the ma-observe linked group, got %v", cfg.LinkedGroups)
+	}
+}
+
+func TestMockFiles_Manifest(t *testing.T) {
+	var manifest map[string]string
+	if err := json.Unmarshal([]byte(mockManifest), &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if len(manifest) != len(mockPackages) {
+		t.Errorf("expected %d manifest entries, got %d", len(mockPackages), len(manifest))
+	}
+}
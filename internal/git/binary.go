@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// BinaryFiles returns the files a commit changes that git treats as
+// binary, such as images and archives, in diff order.
+func BinaryFiles(repoPath, sha string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(sha, "sha")
+
+	output, err := runGit(repoPath, "diff-tree", "--no-commit-id", "-r", "--root", "--numstat", "-z", sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list binary files of %s: %w", shortSHA(sha), err)
+	}
+	var files []string
+	for _, record := range strings.Split(output, "\x00") {
+		// <added>\t<deleted>\t<path>, with "-" counts for binary files
+		added, rest, _ := strings.Cut(record, "\t")
+		deleted, path, _ := strings.Cut(rest, "\t")
+		if added == "-" && deleted == "-" && path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// LFSFiles returns the files, of those given, that .gitattributes stores in
+// Git LFS (filter=lfs). LFS files are committed as small text pointers, so
+// BinaryFiles doesn't list them. Attributes are read from the working tree.
+func LFSFiles(repoPath string, files []string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+	output, err := runGitInput(repoPath, strings.Join(files, "\x00")+"\x00", "check-attr", "--stdin", "-z", "filter")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check LFS attributes: %w", err)
+	}
+	// <path>\0filter\0<value>\0 per file
+	fields := strings.Split(output, "\x00")
+	var lfs []string
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			lfs = append(lfs, fields[i])
+		}
+	}
+	return lfs, nil
+}
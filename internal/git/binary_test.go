@@ -0,0 +1,36 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBinaryFiles(t *testing.T) {
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, ".gitattributes", "*.psd filter=lfs diff=lfs merge=lfs -text\n")
+	writeFile(t, dir, "README.md", "# Test\n")
+	writeFile(t, dir, "assets/logo.png", "\x89PNG\x00\x01\x02")
+	writeFile(t, dir, "assets/hero.psd", "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 123\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add assets")
+	head, _ := runGit(dir, "rev-parse", "HEAD")
+
+	binary, err := BinaryFiles(dir, head)
+	if err != nil {
+		t.Fatalf("BinaryFiles failed: %v", err)
+	}
+	if !slices.Equal(binary, []string{"assets/logo.png"}) {
+		t.Errorf("expected [assets/logo.png], got %v", binary)
+	}
+
+	lfs, err := LFSFiles(dir, []string{"README.md", "assets/hero.psd", "assets/logo.png"})
+	if err != nil {
+		t.Fatalf("LFSFiles failed: %v", err)
+	}
+	if !slices.Equal(lfs, []string{"assets/hero.psd"}) {
+		t.Errorf("expected [assets/hero.psd], got %v", lfs)
+	}
+	if lfs, err := LFSFiles(dir, nil); err != nil || lfs != nil {
+		t.Errorf("expected no LFS files, got %v, %v", lfs, err)
+	}
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// CacheFile is the name of the commit cache in the repository's git
+// directory.
+const CacheFile = "release-damnit-cache"
+
+// cacheVersion is bumped whenever what's cached, or how it's computed,
+// changes. A cache of another version is discarded.
+const cacheVersion = 1
+
+// maxCacheEntries bounds the cache. A save over it keeps only the commits
+// the run used, so commits of rewritten history age out.
+var maxCacheEntries = 50000
+
+// Cache remembers commits' changed files by SHA across runs, so a dry run,
+// the real run, and any rerun don't list the same commits' files again. A
+// commit's files never change, so entries stay valid as long as the commit
+// exists. Only the file lists are cached; commit messages are still read
+// from git, which is cheap. Listings use the cache through its methods,
+// such as Cache.GetMergedCommits, so concurrent analyses of different
+// repositories each use their own.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	used    map[string]bool
+	dirty   bool
+}
+
+// cacheFile is the cache's on-disk format.
+type cacheFile struct {
+	Version int                    `json:"version"`
+	Commits map[string]*cacheEntry `json:"commits"`
+}
+
+// cacheEntry is what's cached for one commit.
+type cacheEntry struct {
+	Files []string `json:"files"`
+}
+
+// OpenCache loads the commit cache of repoPath. A missing, unreadable, or
+// outdated cache starts empty. It returns nil in a shallow clone, where
+// commits at the boundary list every file they contain, and in a bare
+// repository or one whose git directory can't be written, such as a
+// read-only mirror; a nil Cache lists files from git, and Close is a no-op.
+func OpenCache(repoPath string) (*Cache, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+
+	shallow, err := runGit(repoPath, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open commit cache: %w", err)
+	}
+	if shallow == "true" {
+		slog.Debug("shallow clone, not caching commits")
+		return nil, nil
+	}
+	bare, err := IsBareRepository(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open commit cache: %w", err)
+	}
+	if bare {
+		slog.Debug("bare repository, not caching commits")
+		return nil, nil
+	}
+	gitDir, err := runGit(repoPath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open commit cache: %w", err)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoPath, gitDir)
+	}
+	if !dirWritable(gitDir) {
+		slog.Debug("git directory isn't writable, not caching commits", "path", gitDir)
+		return nil, nil
+	}
+
+	c := &Cache{
+		path:    filepath.Join(gitDir, CacheFile),
+		entries: make(map[string]*cacheEntry),
+		used:    make(map[string]bool),
+	}
+	c.load()
+	return c, nil
+}
+
+// dirWritable reports whether files can be created in dir.
+func dirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, CacheFile+".probe-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// load reads the cache file, leaving the cache empty if it can't be used.
+func (c *Cache) load() {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Debug("ignoring unreadable commit cache", "path", c.path, "error", err)
+		}
+		return
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		slog.Debug("ignoring corrupt commit cache", "path", c.path, "error", err)
+		c.dirty = true
+		return
+	}
+	if file.Version != cacheVersion {
+		slog.Debug("ignoring outdated commit cache", "path", c.path, "version", file.Version)
+		c.dirty = true
+		return
+	}
+	for sha, entry := range file.Commits {
+		if entry != nil {
+			c.entries[sha] = entry
+		}
+	}
+}
+
+// Close saves the cache if anything was added. The cache must not be used
+// afterwards.
+func (c *Cache) Close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) > maxCacheEntries {
+		for sha := range c.entries {
+			if !c.used[sha] {
+				delete(c.entries, sha)
+			}
+		}
+		c.dirty = true
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(&cacheFile{Version: cacheVersion, Commits: c.entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode commit cache: %w", err)
+	}
+	// Write and rename, so a concurrent run never reads half a cache
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write commit cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write commit cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// files returns a commit's cached files.
+func (c *Cache) files(sha string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[sha]
+	if ok {
+		c.used[sha] = true
+		return entry.Files, true
+	}
+	return nil, false
+}
+
+// add caches a commit's files.
+func (c *Cache) add(sha string, files []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[sha] = &cacheEntry{Files: files}
+	c.used[sha] = true
+	c.dirty = true
+}
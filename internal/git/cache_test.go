@@ -0,0 +1,238 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitWithFile creates a repo with a commit adding file.txt and returns
+// the repo and the commit's SHA.
+func commitWithFile(t *testing.T) (string, string) {
+	t.Helper()
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "README.md", "readme")
+	runCmd(t, dir, "git", "add", "README.md")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	writeFile(t, dir, "file.txt", "content")
+	runCmd(t, dir, "git", "add", "file.txt")
+	runCmd(t, dir, "git", "commit", "-m", "feat: add file")
+	sha, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, sha
+}
+
+// headFiles lists HEAD's files the way commit listings do, through cache.
+func headFiles(t *testing.T, cache *Cache, dir, sha string) []string {
+	t.Helper()
+	commit := &Commit{SHA: sha}
+	if err := listChangedFiles(dir, cache, commit); err != nil {
+		t.Fatalf("listChangedFiles failed: %v", err)
+	}
+	return commit.Files
+}
+
+func TestCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, sha := commitWithFile(t)
+	path := filepath.Join(dir, ".git", CacheFile)
+
+	cache, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if files := headFiles(t, cache, dir, sha); len(files) != 1 || files[0] != "file.txt" {
+		t.Fatalf("expected file.txt, got %v", files)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the cache saved: %v", err)
+	}
+
+	// A later run reads the files from the cache, not git
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "file.txt", "cached.txt", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache, err = OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if files := headFiles(t, cache, dir, sha); len(files) != 1 || files[0] != "cached.txt" {
+		t.Errorf("expected the cached files, got %v", files)
+	}
+	commits, err := cache.GetCommitsInRange(dir, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsInRange failed: %v", err)
+	}
+	if len(commits) != 1 || len(commits[0].Files) != 1 || commits[0].Files[0] != "cached.txt" {
+		t.Errorf("expected the listing to use the cache, got %v", commits)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Listings given no cache ask git, even while another one is open
+	other, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	defer other.Close()
+	commits, err = GetCommitsInRange(dir, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsInRange failed: %v", err)
+	}
+	if len(commits) != 1 || len(commits[0].Files) != 1 || commits[0].Files[0] != "file.txt" {
+		t.Errorf("expected files from git without a cache, got %v", commits)
+	}
+	if files := headFiles(t, nil, dir, sha); len(files) != 1 || files[0] != "file.txt" {
+		t.Errorf("expected files from git without a cache, got %v", files)
+	}
+}
+
+func TestCache_Invalid(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	for name, content := range map[string]string{
+		"corrupt":  `{"version": 1, "commits": `,
+		"outdated": `{"version": 0, "commits": {"SHA": {"files": ["stale.txt"]}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir, sha := commitWithFile(t)
+			path := filepath.Join(dir, ".git", CacheFile)
+			if err := os.WriteFile(path, []byte(strings.Replace(content, "SHA", sha, 1)), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cache, err := OpenCache(dir)
+			if err != nil {
+				t.Fatalf("OpenCache failed: %v", err)
+			}
+			if files := headFiles(t, cache, dir, sha); len(files) != 1 || files[0] != "file.txt" {
+				t.Errorf("expected files from git, got %v", files)
+			}
+			if err := cache.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `"version":1`) || strings.Contains(string(data), "stale.txt") {
+				t.Errorf("expected the cache rewritten, got %s", data)
+			}
+		})
+	}
+}
+
+func TestCache_Prune(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	old := maxCacheEntries
+	maxCacheEntries = 1
+	t.Cleanup(func() { maxCacheEntries = old })
+
+	dir, sha := commitWithFile(t)
+	path := filepath.Join(dir, ".git", CacheFile)
+	stale := `{"version": 1, "commits": {"0000000000000000000000000000000000000000": {"files": ["gone.txt"]}}}`
+	if err := os.WriteFile(path, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	headFiles(t, cache, dir, sha)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gone.txt") || !strings.Contains(string(data), sha) {
+		t.Errorf("expected only the commit this run used kept, got %s", data)
+	}
+}
+
+func TestOpenCache_Shallow(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	src, _ := commitWithFile(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	runCmd(t, t.TempDir(), "git", "clone", "--depth", "1", "file://"+src, dir)
+
+	cache, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if cache != nil {
+		t.Error("expected no cache in a shallow clone")
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("expected Close on nil to be a no-op, got %v", err)
+	}
+}
+
+func TestOpenCache_Bare(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	src, _ := commitWithFile(t)
+	dir := filepath.Join(t.TempDir(), "mirror.git")
+	runCmd(t, t.TempDir(), "git", "clone", "--bare", src, dir)
+
+	cache, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if cache != nil {
+		t.Error("expected no cache in a bare repository")
+	}
+	if _, err := os.Stat(filepath.Join(dir, CacheFile)); !os.IsNotExist(err) {
+		t.Errorf("expected no cache file in the bare repository, got %v", err)
+	}
+}
+
+func TestOpenCache_ReadOnlyGitDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+
+	dir, _ := commitWithFile(t)
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.Chmod(gitDir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(gitDir, 0755) })
+
+	cache, err := OpenCache(dir)
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if cache != nil {
+		t.Error("expected no cache with a read-only git directory")
+	}
+}
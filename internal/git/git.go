@@ -4,15 +4,31 @@
 package git
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/dsswift/release-damnit/pkg/contracts"
 )
 
+// fileListingNanos accumulates the time spent listing commits' changed files
+// across the process (see FileListingTime).
+var fileListingNanos atomic.Int64
+
+// FileListingTime returns the total time spent so far listing commits'
+// changed files. Time a step by the difference across it.
+func FileListingTime() time.Duration {
+	return time.Duration(fileListingNanos.Load())
+}
+
 // Commit represents a parsed git commit.
 type Commit struct {
 	// SHA is the full commit hash.
@@ -21,6 +37,16 @@ type Commit struct {
 	// ShortSHA is the first 7 characters of the hash.
 	ShortSHA string
 
+	// Subject is the full first line of the commit message.
+	Subject string
+
+	// Body is the commit message after the subject, trimmed.
+	Body string
+
+	// Notes are detail lines rendered as sub-bullets under the commit's
+	// changelog and release notes entry (see ReleaseNotes).
+	Notes []string
+
 	// Type is the conventional commit type (feat, fix, chore, etc.).
 	Type string
 
@@ -33,6 +59,11 @@ type Commit struct {
 	// IsBreaking indicates if this is a breaking change (! suffix or BREAKING CHANGE footer).
 	IsBreaking bool
 
+	// BreakingDescription explains a breaking change: the BREAKING CHANGE
+	// footer's text, or the body's first paragraph for a breaking commit
+	// without one (see ParseBreakingChange).
+	BreakingDescription string
+
 	// Files is the list of files changed by this commit.
 	Files []string
 }
@@ -43,18 +74,189 @@ type MergeInfo struct {
 	IsMerge bool
 
 	// MergeBase is the common ancestor of the merge (parent of first parent).
+	// For octopus merges it's the common ancestor of all parents.
 	MergeBase string
 
 	// MergeHead is the tip of the merged branch (second parent).
 	MergeHead string
 
+	// FirstParent is the tip of the branch that was merged into (HEAD^1).
+	FirstParent string
+
+	// MergeHeads are the tips of all merged branches: HEAD^2 and, for octopus
+	// merges, HEAD^3 onwards.
+	MergeHeads []string
+
 	// HeadSHA is the SHA of HEAD.
 	HeadSHA string
 }
 
-// conventionalCommitRegex parses conventional commit messages.
-// Format: type(scope)!: description  OR  type!: description  OR  type: description
-var conventionalCommitRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]+)\))?(!)?\s*:\s*(.+)$`)
+// RepoRoot returns the top-level directory of the working tree containing dir,
+// or the git directory of a bare repository.
+func RepoRoot(dir string) (string, error) {
+	contracts.RequireNotEmpty(dir, "dir")
+
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		// A bare repository has no working tree; its git directory is the root
+		if bare, bareErr := IsBareRepository(dir); bareErr == nil && bare {
+			if root, err = runGit(dir, "rev-parse", "--absolute-git-dir"); err == nil {
+				return filepath.FromSlash(root), nil
+			}
+		}
+		return "", fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	return filepath.FromSlash(root), nil
+}
+
+// IsBareRepository reports whether dir is (inside) a bare repository, one
+// without a working tree such as a server's.
+func IsBareRepository(dir string) (bool, error) {
+	contracts.RequireNotEmpty(dir, "dir")
+
+	bare, err := runGit(dir, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	return bare == "true", nil
+}
+
+// CurrentBranch returns the name of the checked-out branch, or "" when HEAD
+// is detached.
+func CurrentBranch(repoPath string) (string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+
+	branch, err := runGit(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// BranchesAt returns the names of the local and remote-tracking branches
+// whose tip is rev, sorted and without their remote ("origin/main" is
+// "main"). A detached checkout of a branch tip, as in CI, is on one of them.
+func BranchesAt(repoPath, rev string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(rev, "rev")
+
+	output, err := runGit(repoPath, "for-each-ref", "--points-at", rev, "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches at %s: %w", rev, err)
+	}
+	var branches []string
+	for _, ref := range strings.Fields(output) {
+		name, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok {
+			// refs/remotes/<remote>/<branch>
+			_, name, _ = strings.Cut(strings.TrimPrefix(ref, "refs/remotes/"), "/")
+		}
+		if name != "" && name != "HEAD" && !slices.Contains(branches, name) {
+			branches = append(branches, name)
+		}
+	}
+	slices.Sort(branches)
+	return branches, nil
+}
+
+// ResolveCommit returns the SHA of the commit rev names.
+func ResolveCommit(repoPath, rev string) (string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(rev, "rev")
+
+	sha, err := runGit(repoPath, "rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return sha, nil
+}
+
+// TagExists reports whether a tag exists in the local repository.
+func TagExists(repoPath, tag string) (bool, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(tag, "tag")
+
+	output, err := runGit(repoPath, "tag", "--list", tag)
+	if err != nil {
+		return false, err
+	}
+	return output != "", nil
+}
+
+// RemoteURL returns the fetch URL of the named remote.
+func RemoteURL(repoPath, remote string) (string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(remote, "remote")
+
+	return runGit(repoPath, "remote", "get-url", remote)
+}
+
+// PushTag creates or updates tag on remote so it points at sha. The commit is
+// pushed along with the tag if the remote doesn't have it yet.
+func PushTag(repoPath, remote, tag, sha string) error {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(remote, "remote")
+	contracts.RequireNotEmpty(tag, "tag")
+	contracts.RequireNotEmpty(sha, "sha")
+
+	_, err := runGit(repoPath, "push", remote, fmt.Sprintf("%s:refs/tags/%s", sha, tag))
+	return err
+}
+
+// PushBranch pushes a commit to a branch on a remote, fast-forwarding it.
+func PushBranch(repoPath, remote, sha, branch string) error {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(remote, "remote")
+	contracts.RequireNotEmpty(sha, "sha")
+	contracts.RequireNotEmpty(branch, "branch")
+
+	_, err := runGit(repoPath, "push", remote, fmt.Sprintf("%s:refs/heads/%s", sha, branch))
+	return err
+}
+
+// CommitPaths stages the given paths and commits just them, leaving anything
+// else in the index out of the commit. Returns the new commit's SHA.
+func CommitPaths(repoPath, message string, paths []string) (string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(message, "message")
+	contracts.Require(len(paths) > 0, "paths must not be empty")
+
+	if _, err := runGit(repoPath, append([]string{"add", "--"}, paths...)...); err != nil {
+		return "", fmt.Errorf("failed to stage files: %w", err)
+	}
+	if _, err := runGit(repoPath, append([]string{"commit", "--quiet", "-m", message, "--"}, paths...)...); err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+	return runGit(repoPath, "rev-parse", "HEAD")
+}
+
+// FileModes returns the tree entry modes (e.g., "100644", or "100755" for an
+// executable) of the given files at rev, by path. Files that don't exist at
+// rev are left out.
+func FileModes(repoPath, rev string, paths []string) (map[string]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(rev, "rev")
+
+	modes := make(map[string]string, len(paths))
+	if len(paths) == 0 {
+		return modes, nil
+	}
+	output, err := runGit(repoPath, append([]string{"ls-tree", "-z", "--full-tree", rev, "--"}, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file modes at %s: %w", rev, err)
+	}
+	for _, entry := range strings.Split(output, "\x00") {
+		// <mode> <type> <object>\t<path>
+		meta, path, ok := strings.Cut(entry, "\t")
+		if mode, _, _ := strings.Cut(meta, " "); ok {
+			modes[path] = mode
+		}
+	}
+	return modes, nil
+}
 
 // AnalyzeHead determines if HEAD is a merge commit and returns merge information.
 func AnalyzeHead(repoPath string) (*MergeInfo, error) {
@@ -69,24 +271,25 @@ func AnalyzeHead(repoPath string) (*MergeInfo, error) {
 	}
 	info.HeadSHA = headSHA
 
-	// Try to get second parent (HEAD^2) - if this fails, it's not a merge commit
-	mergeHead, err := runGit(repoPath, "rev-parse", "HEAD^2")
+	// A merge commit has more than one parent
+	parents, err := runGit(repoPath, "show", "-s", "--format=%P", "HEAD")
 	if err != nil {
+		return nil, fmt.Errorf("failed to get parents of HEAD: %w", err)
+	}
+	parentSHAs := strings.Fields(parents)
+	if len(parentSHAs) < 2 {
 		// Not a merge commit - fall back to HEAD~1..HEAD
 		info.IsMerge = false
 		return info, nil
 	}
 
 	info.IsMerge = true
-	info.MergeHead = mergeHead
+	info.FirstParent = parentSHAs[0]
+	info.MergeHeads = parentSHAs[1:]
+	info.MergeHead = info.MergeHeads[0]
 
-	// Get merge base (common ancestor)
-	firstParent, err := runGit(repoPath, "rev-parse", "HEAD^1")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get first parent: %w", err)
-	}
-
-	mergeBase, err := runGit(repoPath, "merge-base", firstParent, mergeHead)
+	// Get merge base (common ancestor of all parents)
+	mergeBase, err := runGit(repoPath, append([]string{"merge-base", "--octopus"}, parentSHAs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge base: %w", err)
 	}
@@ -98,53 +301,488 @@ func AnalyzeHead(repoPath string) (*MergeInfo, error) {
 // GetCommitsInRange returns all commits in the range base..head (exclusive of base).
 // If head is empty, it defaults to HEAD.
 func GetCommitsInRange(repoPath, base, head string) ([]*Commit, error) {
+	return (*Cache)(nil).GetCommitsInRange(repoPath, base, head)
+}
+
+// GetCommitsInRange is GetCommitsInRange, reading changed files from the
+// cache. A nil cache lists them all from git.
+func (c *Cache) GetCommitsInRange(repoPath, base, head string) ([]*Commit, error) {
+	return collectCommits(func(fn func(*Commit) error) error {
+		return c.EachCommitInRange(repoPath, base, head, fn)
+	})
+}
+
+// EachCommitInRange calls fn with each commit GetCommitsInRange returns,
+// oldest first, as git lists them, so a range of any size is never held in
+// memory at once. It stops at fn's first error and returns it.
+func EachCommitInRange(repoPath, base, head string, fn func(*Commit) error) error {
+	return (*Cache)(nil).EachCommitInRange(repoPath, base, head, fn)
+}
+
+// EachCommitInRange is EachCommitInRange, reading changed files from the
+// cache. A nil cache lists them all from git.
+func (c *Cache) EachCommitInRange(repoPath, base, head string, fn func(*Commit) error) error {
 	contracts.RequireNotEmpty(repoPath, "repoPath")
 	contracts.RequireNotEmpty(base, "base")
+	contracts.RequireNotNil(fn, "fn")
 
 	if head == "" {
 		head = "HEAD"
 	}
 
-	// Get commit list with format: SHA|subject
 	rangeSpec := fmt.Sprintf("%s..%s", base, head)
-	output, err := runGit(repoPath, "log", "--format=%H|%s", "--reverse", rangeSpec)
+	return eachCommit(repoPath, rangeSpec, c, true, []string{rangeSpec}, fn)
+}
+
+// GetCommitsSinceTag returns the commits reachable from head but not from
+// tag, oldest first. An empty tag means every commit reachable from head.
+func GetCommitsSinceTag(repoPath, tag, head string) ([]*Commit, error) {
+	return (*Cache)(nil).GetCommitsSinceTag(repoPath, tag, head)
+}
+
+// GetCommitsSinceTag is GetCommitsSinceTag, reading changed files from the
+// cache. A nil cache lists them all from git.
+func (c *Cache) GetCommitsSinceTag(repoPath, tag, head string) ([]*Commit, error) {
+	return collectCommits(func(fn func(*Commit) error) error {
+		return c.EachCommitSinceTag(repoPath, tag, head, fn)
+	})
+}
+
+// EachCommitSinceTag calls fn with each commit GetCommitsSinceTag returns,
+// as EachCommitInRange does.
+func EachCommitSinceTag(repoPath, tag, head string, fn func(*Commit) error) error {
+	return (*Cache)(nil).EachCommitSinceTag(repoPath, tag, head, fn)
+}
+
+// EachCommitSinceTag is EachCommitSinceTag, reading changed files from the
+// cache. A nil cache lists them all from git.
+func (c *Cache) EachCommitSinceTag(repoPath, tag, head string, fn func(*Commit) error) error {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(head, "head")
+	contracts.RequireNotNil(fn, "fn")
+
+	rangeSpec := head
+	if tag != "" {
+		rangeSpec = fmt.Sprintf("%s..%s", tag, head)
+	}
+	return eachCommit(repoPath, rangeSpec, c, true, []string{rangeSpec}, fn)
+}
+
+// collectCommits gathers the commits an Each* function streams.
+func collectCommits(each func(fn func(*Commit) error) error) ([]*Commit, error) {
+	var commits []*Commit
+	err := each(func(c *Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get commits in range %s: %w", rangeSpec, err)
+		return nil, err
 	}
+	return commits, nil
+}
+
+// ListSHAsSinceTag returns the SHAs of the commits GetCommitsSinceTag
+// returns, without listing their files.
+func ListSHAsSinceTag(repoPath, tag, head string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(head, "head")
 
+	rangeSpec := head
+	if tag != "" {
+		rangeSpec = fmt.Sprintf("%s..%s", tag, head)
+	}
+	output, err := runGit(repoPath, "rev-list", rangeSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in range %s: %w", rangeSpec, err)
+	}
 	if output == "" {
 		return nil, nil
 	}
+	return strings.Split(output, "\n"), nil
+}
+
+// MergeBase returns the best common ancestor of all revisions.
+func MergeBase(repoPath string, revisions ...string) (string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.Require(len(revisions) > 0, "revisions must not be empty")
+
+	base, err := runGit(repoPath, append([]string{"merge-base", "--octopus"}, revisions...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get merge base of %s: %w", strings.Join(revisions, " "), err)
+	}
+	return base, nil
+}
+
+// GetMergedCommits returns the commits a merge brought in: everything
+// reachable from any merged branch tip but not from the first parent. This
+// covers octopus merges and nested merges inside the merged branches, and
+// leaves out mainline commits that were back-merged into a feature branch.
+//
+// Mainline commits that reached a branch by rebase or cherry-pick have new
+// SHAs, so they're also dropped when they're patch-equivalent to a commit on
+// the first parent (git log --right-only --cherry-pick). Otherwise they would
+// be counted a second time and could release a package twice.
+func GetMergedCommits(repoPath string, info *MergeInfo) ([]*Commit, error) {
+	return getMergedCommits(repoPath, info, nil, true)
+}
+
+// GetMergedCommits is GetMergedCommits, reading changed files from the
+// cache. A nil cache lists them all from git.
+func (c *Cache) GetMergedCommits(repoPath string, info *MergeInfo) ([]*Commit, error) {
+	return getMergedCommits(repoPath, info, c, true)
+}
+
+// GetMergedCommitsWithoutFiles returns the commits GetMergedCommits returns,
+// without listing their changed files, for merges too large to hold every
+// file in memory. Commit.Files is nil; see MergedCommitsTouching.
+func GetMergedCommitsWithoutFiles(repoPath string, info *MergeInfo) ([]*Commit, error) {
+	return getMergedCommits(repoPath, info, nil, false)
+}
+
+func getMergedCommits(repoPath string, info *MergeInfo, cache *Cache, withFiles bool) ([]*Commit, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotNil(info, "info")
+	contracts.Require(info.IsMerge, "info must describe a merge commit")
+	contracts.RequireNotEmpty(info.FirstParent, "info.FirstParent")
 
-	lines := strings.Split(output, "\n")
+	// Symmetric ranges have one left side each, so octopus heads are listed
+	// separately. Heads can share history; keep the first occurrence.
+	seen := make(map[string]bool)
 	var commits []*Commit
+	for _, revisions := range mergedRevisions(info) {
+		desc := revisions[len(revisions)-1]
+		headCommits, err := listCommitsWithoutFiles(repoPath, desc, revisions...)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range headCommits {
+			if seen[c.SHA] {
+				continue
+			}
+			seen[c.SHA] = true
+			if withFiles {
+				if err := listChangedFiles(repoPath, cache, c); err != nil {
+					return nil, err
+				}
+			}
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
 
-	for _, line := range lines {
-		if line == "" {
-			continue
+// MergedCommitsTouching returns the SHAs of the merged commits (as
+// GetMergedCommits lists them) that change a file matching the pathspecs,
+// such as "workloads/jarvis" and ":(exclude)workloads/jarvis/web". Only SHAs
+// are read, so this stays cheap for merges touching many files.
+func MergedCommitsTouching(repoPath string, info *MergeInfo, pathspecs []string) (map[string]bool, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotNil(info, "info")
+	contracts.Require(len(pathspecs) > 0, "pathspecs must not be empty")
+
+	shas := make(map[string]bool)
+	for _, revisions := range mergedRevisions(info) {
+		args := append(append([]string{"rev-list"}, revisions...), "--")
+		output, err := runGit(repoPath, append(args, pathspecs...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits in %s touching %s: %w", revisions[len(revisions)-1], strings.Join(pathspecs, " "), err)
 		}
+		for _, sha := range strings.Split(output, "\n") {
+			if sha != "" {
+				shas[sha] = true
+			}
+		}
+	}
+	return shas, nil
+}
 
-		parts := strings.SplitN(line, "|", 2)
-		if len(parts) != 2 {
-			continue
+// mergedRevisions returns the git log arguments listing the commits each
+// merged head brought in, with the range last.
+func mergedRevisions(info *MergeInfo) [][]string {
+	var revisions [][]string
+	for _, head := range info.MergeHeads {
+		rangeSpec := fmt.Sprintf("%s...%s", info.FirstParent, head)
+		revisions = append(revisions, []string{"--right-only", "--cherry-pick", rangeSpec})
+	}
+	return revisions
+}
+
+// CountChangedFiles returns the number of files that differ between two
+// revisions.
+func CountChangedFiles(repoPath, base, head string) (int, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(base, "base")
+	contracts.RequireNotEmpty(head, "head")
+
+	// " 3 files changed, 10 insertions(+)", or "" for no changes
+	output, err := runGit(repoPath, "diff", "--shortstat", base, head)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count changed files in %s..%s: %w", base, head, err)
+	}
+	if output == "" {
+		return 0, nil
+	}
+	count, _, _ := strings.Cut(strings.TrimSpace(output), " ")
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected git diff --shortstat output %q", output)
+	}
+	return n, nil
+}
+
+// ListMergedTags returns the tags matching a glob pattern that are reachable
+// from HEAD.
+func ListMergedTags(repoPath, pattern string) ([]string, error) {
+	return ListTagsReachableFrom(repoPath, "HEAD", pattern)
+}
+
+// ListTagsReachableFrom returns the tags matching a glob pattern that are
+// reachable from rev, including tags pointing at rev itself.
+func ListTagsReachableFrom(repoPath, rev, pattern string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(rev, "rev")
+	contracts.RequireNotEmpty(pattern, "pattern")
+
+	output, err := runGit(repoPath, "tag", "--merged", rev, "--list", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags matching %s: %w", pattern, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// ReleaseTagPattern matches the tags release-damnit creates (component-vX.Y.Z).
+const ReleaseTagPattern = "*-v*"
+
+// GetReleasedEquivalents returns the SHAs of the given commits that are
+// patch-equivalent to a commit already shipped under a release tag matching
+// tagPattern but not reachable from HEAD. The usual case is a hotfix released
+// from a maintenance branch and later cherry-picked onto main: it has a new
+// SHA, but releasing it again would duplicate the changelog entry.
+func GetReleasedEquivalents(repoPath, tagPattern string, commits []*Commit) (map[string]bool, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(tagPattern, "tagPattern")
+
+	equivalent := make(map[string]bool)
+	if len(commits) == 0 {
+		return equivalent, nil
+	}
+
+	released, err := patchIDs(repoPath, "--tags="+tagPattern, "--not", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch IDs of released commits: %w", err)
+	}
+	if len(released) == 0 {
+		return equivalent, nil
+	}
+	releasedIDs := make(map[string]bool, len(released))
+	for _, id := range released {
+		releasedIDs[id] = true
+	}
+
+	revisions := []string{"--no-walk"}
+	for _, c := range commits {
+		revisions = append(revisions, c.SHA)
+	}
+	analyzed, err := patchIDs(repoPath, revisions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch IDs of analyzed commits: %w", err)
+	}
+	for sha, id := range analyzed {
+		if releasedIDs[id] {
+			equivalent[sha] = true
 		}
+	}
+	return equivalent, nil
+}
 
-		sha := parts[0]
-		subject := parts[1]
+// patchIDs returns the stable patch ID of every non-merge commit selected by
+// revisions, keyed by SHA. Commits with an empty diff have no patch ID.
+func patchIDs(repoPath string, revisions ...string) (map[string]string, error) {
+	args := append([]string{"log", "-p", "--no-merges", "--format=commit %H"}, revisions...)
+	patches, err := runGit(repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
 
-		commit := parseCommit(sha, subject)
+	ids := make(map[string]string)
+	if patches == "" {
+		return ids, nil
+	}
 
-		// Get changed files for this commit
-		files, err := getChangedFiles(repoPath, sha)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get changed files for %s: %w", sha[:7], err)
+	output, err := runGitInput(repoPath, patches+"\n", "patch-id", "--stable")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		// Each line is "<patch-id> <commit-sha>"
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			ids[fields[1]] = fields[0]
 		}
-		commit.Files = files
+	}
+	return ids, nil
+}
 
-		commits = append(commits, commit)
+// GetMergeCommit returns the merge commit itself (HEAD), with its subject
+// parsed and the files it changed relative to the first parent, i.e.
+// everything the merge brought in.
+func GetMergeCommit(repoPath string, info *MergeInfo) (*Commit, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotNil(info, "info")
+	contracts.Require(info.IsMerge, "info must describe a merge commit")
+
+	output, err := runGit(repoPath, "show", "-s", "--format=%s%x1f%b", info.HeadSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merge commit: %w", err)
 	}
+	subject, body, _ := strings.Cut(output, "\x1f")
 
-	return commits, nil
+	commit := parseCommit(info.HeadSHA, subject)
+	commit.Body = strings.TrimSpace(body)
+	commit.ParseBreakingChange()
+
+	files, err := runGit(repoPath, "diff", "--name-only", info.FirstParent, info.HeadSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get files changed by merge: %w", err)
+	}
+	for _, f := range strings.Split(files, "\n") {
+		if f != "" {
+			commit.Files = append(commit.Files, f)
+		}
+	}
+	return commit, nil
+}
+
+// listCommits runs git log over revisions (oldest first) and parses each
+// commit with its changed files. desc names the range in errors.
+func listCommits(repoPath, desc string, revisions ...string) ([]*Commit, error) {
+	return collectCommits(func(fn func(*Commit) error) error {
+		return eachCommit(repoPath, desc, nil, true, revisions, fn)
+	})
+}
+
+// listCommitsWithoutFiles is listCommits without the changed files.
+func listCommitsWithoutFiles(repoPath, desc string, revisions ...string) ([]*Commit, error) {
+	return collectCommits(func(fn func(*Commit) error) error {
+		return eachCommit(repoPath, desc, nil, false, revisions, fn)
+	})
+}
+
+// maxCommitRecord bounds the size of one commit's log record (SHA, subject,
+// and body) read by eachCommit.
+const maxCommitRecord = 16 << 20
+
+// eachCommit runs git log over revisions (oldest first) and calls fn with
+// each commit as its record is read, listing its changed files first if
+// withFiles, through cache unless it's nil. desc names the range in errors.
+// If fn fails, git is stopped.
+func eachCommit(repoPath, desc string, cache *Cache, withFiles bool, revisions []string, fn func(*Commit) error) error {
+	// Records of SHA, subject, and body. Bodies span lines, so fields and
+	// records use ASCII unit/record separators.
+	args := append([]string{"log", "--format=%H%x1f%s%x1f%b%x1e", "--reverse"}, revisions...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get commits in range %s: %w", desc, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to get commits in range %s: %w", desc, err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(nil, maxCommitRecord)
+	scanner.Split(scanRecords)
+
+	var fnErr error
+	for scanner.Scan() {
+		commit := parseRecord(scanner.Text())
+		if commit == nil {
+			continue
+		}
+		if withFiles {
+			if fnErr = listChangedFiles(repoPath, cache, commit); fnErr != nil {
+				break
+			}
+		}
+		if fnErr = fn(commit); fnErr != nil {
+			break
+		}
+	}
+	if fnErr != nil {
+		// Don't wait for git to list commits nobody will read
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fnErr
+	}
+	scanErr := scanner.Err()
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to get commits in range %s: git %s failed: %v\nstderr: %s", desc, strings.Join(args, " "), err, stderr.String())
+	}
+	if scanErr != nil {
+		return fmt.Errorf("failed to read commits in range %s: %w", desc, scanErr)
+	}
+	return nil
+}
+
+// scanRecords is a bufio.SplitFunc for the \x1e-terminated records of
+// eachCommit's git log format.
+func scanRecords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, '\x1e'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// parseRecord parses one git log record of SHA, subject, and body, or
+// returns nil for a blank or malformed record.
+func parseRecord(record string) *Commit {
+	record = strings.TrimSpace(record)
+	if record == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(record, "\x1f", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+
+	commit := parseCommit(parts[0], parts[1])
+	commit.Body = strings.TrimSpace(parts[2])
+	commit.ParseBreakingChange()
+	return commit
+}
+
+// listChangedFiles sets a commit's changed files, from cache unless it's
+// nil.
+func listChangedFiles(repoPath string, cache *Cache, commit *Commit) error {
+	start := time.Now()
+	defer func() { fileListingNanos.Add(int64(time.Since(start))) }()
+
+	if cache != nil {
+		if files, ok := cache.files(commit.SHA); ok {
+			commit.Files = slices.Clone(files)
+			return nil
+		}
+	}
+
+	files, err := getChangedFiles(repoPath, commit.SHA)
+	if err != nil {
+		return fmt.Errorf("failed to get changed files for %s: %w", shortSHA(commit.SHA), err)
+	}
+	commit.Files = files
+	if cache != nil {
+		cache.add(commit.SHA, slices.Clone(files))
+	}
+	return nil
 }
 
 // GetCommitsSinceLastTag returns commits since the last tag matching the pattern.
@@ -163,21 +801,29 @@ func GetCommitsSinceLastTag(repoPath, tagPattern string) ([]*Commit, error) {
 	return GetCommitsInRange(repoPath, tag, "HEAD")
 }
 
+// NewCommit parses a commit from its SHA, full message, and changed files,
+// for commits listed by something other than git, such as the GitHub API.
+func NewCommit(sha, message string, files []string) *Commit {
+	contracts.RequireNotEmpty(sha, "sha")
+
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	commit := parseCommit(sha, strings.TrimSpace(subject))
+	commit.Body = strings.TrimSpace(body)
+	commit.Files = files
+	commit.ParseBreakingChange()
+	return commit
+}
+
 // parseCommit parses a commit SHA and subject into a Commit struct.
 func parseCommit(sha, subject string) *Commit {
 	commit := &Commit{
 		SHA:      sha,
-		ShortSHA: sha[:7],
+		ShortSHA: shortSHA(sha),
+		Subject:  subject,
 	}
 
 	// Try to parse as conventional commit
-	matches := conventionalCommitRegex.FindStringSubmatch(subject)
-	if matches != nil {
-		commit.Type = strings.ToLower(matches[1])
-		commit.Scope = matches[2]
-		commit.IsBreaking = matches[3] == "!"
-		commit.Description = matches[4]
-	} else {
+	if !commit.ParseConventional(subject) {
 		// Not a conventional commit - treat as unknown type
 		commit.Type = ""
 		commit.Description = subject
@@ -186,6 +832,158 @@ func parseCommit(sha, subject string) *Commit {
 	return commit
 }
 
+// ParseConventional sets the commit's type, scope, breaking flag, and
+// description from a conventional commit subject, such as a pull request
+// title. Returns false, leaving the commit unchanged, if subject isn't
+// conventional.
+func (c *Commit) ParseConventional(subject string) bool {
+	return defaultParser.Parse(c, subject)
+}
+
+// shortSHA returns the 7-character prefix of a SHA, or the SHA itself if
+// it's shorter.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// breakingChangeRegex matches the first line of a BREAKING CHANGE footer.
+var breakingChangeRegex = regexp.MustCompile(`^BREAKING[ -]CHANGE:\s*(.*)$`)
+
+// ParseBreakingChange sets BreakingDescription from the body. A BREAKING
+// CHANGE footer also marks a conventional commit breaking; its text runs to
+// the next blank line or trailer. A breaking commit without the footer is
+// described by the body's first paragraph, if there is one.
+func (c *Commit) ParseBreakingChange() {
+	lines := strings.Split(c.Body, "\n")
+	for i, line := range lines {
+		m := breakingChangeRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		text := []string{m[1]}
+		for _, next := range lines[i+1:] {
+			next = strings.TrimSpace(next)
+			if next == "" || trailerRegex.MatchString(next) {
+				break
+			}
+			text = append(text, next)
+		}
+		c.BreakingDescription = strings.TrimSpace(strings.Join(text, " "))
+		if c.Type != "" {
+			c.IsBreaking = true
+		}
+		return
+	}
+
+	if c.IsBreaking {
+		if paragraphs := splitParagraphs(c.Body); len(paragraphs) > 0 && !isTrailerBlock(paragraphs[0]) {
+			c.BreakingDescription = strings.Join(paragraphs[0], " ")
+		}
+	}
+}
+
+// releaseNoteTrailer is the trailer authors use to write a release note
+// explicitly.
+const releaseNoteTrailer = "release-note"
+
+// trailerRegex matches a git trailer line ("Key: value"), including the
+// conventional "BREAKING CHANGE: ..." footer.
+var trailerRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|BREAKING CHANGE): (.*)$`)
+
+// ReleaseNotes returns the detail lines for the commit's release notes.
+// Release-Note: trailers, when present, are used as written. Otherwise, if
+// includeBody is set, each paragraph of the body becomes one line, leaving
+// out the trailer block and BREAKING CHANGE footer (see BreakingDescription);
+// list items in a paragraph are kept separate.
+func (c *Commit) ReleaseNotes(includeBody bool) []string {
+	paragraphs := splitParagraphs(c.Body)
+
+	var trailers []string
+	if n := len(paragraphs); n > 0 && isTrailerBlock(paragraphs[n-1]) {
+		trailers = paragraphs[n-1]
+		paragraphs = paragraphs[:n-1]
+	}
+
+	var notes []string
+	for _, line := range trailers {
+		m := trailerRegex.FindStringSubmatch(line)
+		if strings.EqualFold(m[1], releaseNoteTrailer) && strings.TrimSpace(m[2]) != "" {
+			notes = append(notes, strings.TrimSpace(m[2]))
+		}
+	}
+	if len(notes) > 0 || !includeBody {
+		return notes
+	}
+
+	for _, paragraph := range paragraphs {
+		// The breaking change footer is rendered on its own
+		if breakingChangeRegex.MatchString(paragraph[0]) {
+			continue
+		}
+		var current []string
+		flush := func() {
+			if len(current) > 0 {
+				notes = append(notes, strings.Join(current, " "))
+				current = nil
+			}
+		}
+		for _, line := range paragraph {
+			if item, ok := cutListMarker(line); ok {
+				flush()
+				current = append(current, item)
+				continue
+			}
+			current = append(current, line)
+		}
+		flush()
+	}
+	return notes
+}
+
+// splitParagraphs splits text into paragraphs of trimmed, non-empty lines.
+func splitParagraphs(text string) [][]string {
+	var paragraphs [][]string
+	var current []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// isTrailerBlock reports whether every line of a paragraph is a trailer.
+func isTrailerBlock(lines []string) bool {
+	for _, line := range lines {
+		if !trailerRegex.MatchString(line) {
+			return false
+		}
+	}
+	return len(lines) > 0
+}
+
+// cutListMarker strips a leading "- " or "* " list marker.
+func cutListMarker(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):]), true
+		}
+	}
+	return line, false
+}
+
 // getChangedFiles returns the list of files changed by a commit.
 func getChangedFiles(repoPath, sha string) ([]string, error) {
 	output, err := runGit(repoPath, "diff-tree", "--no-commit-id", "--name-only", "-r", sha)
@@ -209,8 +1007,17 @@ func getChangedFiles(repoPath, sha string) ([]string, error) {
 
 // runGit executes a git command and returns stdout as a string.
 func runGit(repoPath string, args ...string) (string, error) {
+	return runGitInput(repoPath, "", args...)
+}
+
+// runGitInput executes a git command with input on stdin and returns stdout
+// as a string.
+func runGitInput(repoPath, input string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = repoPath
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -1,9 +1,13 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -109,6 +113,9 @@ func TestParseCommit_ConventionalCommit(t *testing.T) {
 			if commit.Description != tc.wantDesc {
 				t.Errorf("description: got %s, want %s", commit.Description, tc.wantDesc)
 			}
+			if commit.Subject != tc.subject {
+				t.Errorf("subject: got %s, want %s", commit.Subject, tc.subject)
+			}
 		})
 	}
 }
@@ -134,6 +141,365 @@ func TestParseCommit_NonConventionalCommit(t *testing.T) {
 	}
 }
 
+func TestParseCommit_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		sha       string
+		subject   string
+		wantType  string
+		wantScope string
+		wantDesc  string
+	}{
+		{"nested parens in scope", "abc1234567890", "fix(api(v2)): handle nulls", "fix", "api(v2)", "handle nulls"},
+		{"unicode scope", "abc1234567890", "feat(größe): add sizes", "feat", "größe", "add sizes"},
+		{"unbalanced scope", "abc1234567890", "feat(api: oops", "", "", "feat(api: oops"},
+		{"blank description", "abc1234567890", "feat:   ", "", "", "feat:   "},
+		{"padded description", "abc1234567890", "fix:  trim me  ", "fix", "", "trim me"},
+		{"short sha", "abc", "fix: short", "fix", "", "short"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := parseCommit(tc.sha, tc.subject)
+			if commit.Type != tc.wantType || commit.Scope != tc.wantScope || commit.Description != tc.wantDesc {
+				t.Errorf("got type %q scope %q description %q, want %q %q %q",
+					commit.Type, commit.Scope, commit.Description, tc.wantType, tc.wantScope, tc.wantDesc)
+			}
+			if !strings.HasPrefix(tc.sha, commit.ShortSHA) || len(commit.ShortSHA) > 7 {
+				t.Errorf("unexpected short SHA %q for %q", commit.ShortSHA, tc.sha)
+			}
+		})
+	}
+}
+
+// FuzzParseCommit checks that no subject panics parseCommit and that parsed
+// commits are well formed. Run with:
+//
+//	go test ./internal/git -fuzz FuzzParseCommit
+func FuzzParseCommit(f *testing.F) {
+	for _, seed := range []string{
+		"feat: add feature",
+		"fix(auth)!: breaking fix",
+		"feat(api(v2)): nested",
+		"feat((((: x",
+		"feat(日本語): unicode scope",
+		"Merge branch 'main' into feature",
+		"chore!:",
+		"",
+	} {
+		f.Add("abc1234567890", seed)
+	}
+	f.Add("", "fix: empty sha")
+
+	f.Fuzz(func(t *testing.T, sha, subject string) {
+		commit := parseCommit(sha, subject)
+		if !strings.HasPrefix(sha, commit.ShortSHA) || len(commit.ShortSHA) > 7 {
+			t.Errorf("short SHA %q isn't a prefix of %q", commit.ShortSHA, sha)
+		}
+		if commit.Type == "" {
+			if commit.Description != subject {
+				t.Errorf("non-conventional description %q, want the subject %q", commit.Description, subject)
+			}
+			return
+		}
+		if commit.Type != strings.ToLower(commit.Type) {
+			t.Errorf("type %q isn't lowercase", commit.Type)
+		}
+		if commit.Description == "" || commit.Description != strings.TrimSpace(commit.Description) {
+			t.Errorf("description %q is blank or untrimmed", commit.Description)
+		}
+	})
+}
+
+func TestCommitReleaseNotes(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		includeBody bool
+		want        []string
+	}{
+		{"empty body", "", true, nil},
+		{"body excluded by default", "Adds CSV export.", false, nil},
+		{"paragraphs joined", "Adds CSV export\nfor reports.\n\nAlso handles JSON.", true, []string{"Adds CSV export for reports.", "Also handles JSON."}},
+		{"list items kept separate", "Changes:\n- one\n- two\n  continued", true, []string{"Changes:", "one", "two continued"}},
+		{"trailer block dropped", "Adds export.\n\nSigned-off-by: A <a@b.c>\nRefs: #12", true, []string{"Adds export."}},
+		{"release note trailer wins", "Long internal detail.\n\nRelease-Note: Adds CSV export\nSigned-off-by: A <a@b.c>", true, []string{"Adds CSV export"}},
+		{"release note trailer without body", "Detail.\n\nrelease-note: Faster startup", false, []string{"Faster startup"}},
+		{"breaking footer dropped", "Moves the API.\n\nBREAKING CHANGE: v1 is gone.\nUse v2.", true, []string{"Moves the API."}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Commit{Body: tc.body}
+			got := c.ReleaseNotes(tc.includeBody)
+			if strings.Join(got, "|") != strings.Join(tc.want, "|") || len(got) != len(tc.want) {
+				t.Errorf("ReleaseNotes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommitParseBreakingChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		commit       Commit
+		wantBreaking bool
+		wantDesc     string
+	}{
+		{"no footer", Commit{Type: "feat", Body: "Adds export."}, false, ""},
+		{"footer", Commit{Type: "feat", Body: "Details.\n\nBREAKING CHANGE: v1 is gone."}, true, "v1 is gone."},
+		{"multi-line footer", Commit{Type: "feat", Body: "BREAKING CHANGE: v1 is gone.\nUse v2.\nRefs: #12"}, true, "v1 is gone. Use v2."},
+		{"hyphenated footer", Commit{Type: "fix", Body: "BREAKING-CHANGE: drops Go 1.20"}, true, "drops Go 1.20"},
+		{"bang without footer uses body", Commit{Type: "feat", IsBreaking: true, Body: "The config\nmoved.\n\nMore detail."}, true, "The config moved."},
+		{"bang with only trailers", Commit{Type: "feat", IsBreaking: true, Body: "Signed-off-by: A <a@b.c>"}, true, ""},
+		{"non-conventional footer", Commit{Body: "BREAKING CHANGE: maybe"}, false, "maybe"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.commit
+			c.ParseBreakingChange()
+			if c.IsBreaking != tc.wantBreaking || c.BreakingDescription != tc.wantDesc {
+				t.Errorf("got breaking %v description %q, want %v %q", c.IsBreaking, c.BreakingDescription, tc.wantBreaking, tc.wantDesc)
+			}
+		})
+	}
+}
+
+func TestGetCommitsInRange_Body(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	writeFile(t, dir, "file.txt", "changed")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: add thing", "-m", "Multi-line\nbody | with pipes.")
+
+	commits, err := GetCommitsInRange(dir, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsInRange failed: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(commits))
+	}
+	if commits[0].Description != "add thing" {
+		t.Errorf("description: got %q", commits[0].Description)
+	}
+	if commits[0].Body != "Multi-line\nbody | with pipes." {
+		t.Errorf("body: got %q", commits[0].Body)
+	}
+}
+
+func TestGetCommitsInRange_BreakingFooter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	writeFile(t, dir, "file.txt", "changed")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: move config", "-m", "BREAKING CHANGE: config moved to .release/")
+
+	commits, err := GetCommitsInRange(dir, "HEAD~1", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsInRange failed: %v", err)
+	}
+	if len(commits) != 1 || !commits[0].IsBreaking || commits[0].BreakingDescription != "config moved to .release/" {
+		t.Errorf("expected a breaking commit described by its footer, got %+v", commits[0])
+	}
+}
+
+func TestGetCommitsSinceTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	for i, subject := range []string{"chore: initial commit", "fix: one", "fix: two"} {
+		writeFile(t, dir, "file.txt", subject)
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", subject)
+		if i == 1 {
+			runCmd(t, dir, "git", "tag", "app-v1.0.1")
+		}
+	}
+
+	commits, err := GetCommitsSinceTag(dir, "app-v1.0.1", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsSinceTag failed: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Description != "two" {
+		t.Errorf("expected only the commit after the tag, got %+v", commits)
+	}
+
+	commits, err = GetCommitsSinceTag(dir, "", "HEAD~1")
+	if err != nil {
+		t.Fatalf("GetCommitsSinceTag failed: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Description != "initial commit" || commits[1].Description != "one" {
+		t.Errorf("expected every commit up to HEAD~1, oldest first, got %+v", commits)
+	}
+}
+
+func TestListSHAsSinceTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	for i, subject := range []string{"chore: initial commit", "fix: one", "fix: two"} {
+		writeFile(t, dir, "file.txt", subject)
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", subject)
+		if i == 0 {
+			runCmd(t, dir, "git", "tag", "app-v1.0.0")
+		}
+	}
+
+	commits, err := GetCommitsSinceTag(dir, "app-v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shas, err := ListSHAsSinceTag(dir, "app-v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatalf("ListSHAsSinceTag failed: %v", err)
+	}
+	if len(shas) != 2 || !slices.Contains(shas, commits[0].SHA) || !slices.Contains(shas, commits[1].SHA) {
+		t.Errorf("expected the SHAs of %+v, got %v", commits, shas)
+	}
+
+	if shas, err := ListSHAsSinceTag(dir, "", "HEAD"); err != nil || len(shas) != 3 {
+		t.Errorf("expected every SHA, got %v, %v", shas, err)
+	}
+}
+
+func TestMergeBase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "tag", "a-v1.0.0")
+	writeFile(t, dir, "file.txt", "changed")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: change")
+	runCmd(t, dir, "git", "tag", "b-v1.0.0")
+
+	base, err := MergeBase(dir, "a-v1.0.0", "b-v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatalf("MergeBase failed: %v", err)
+	}
+	want, err := runGit(dir, "rev-parse", "a-v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != want {
+		t.Errorf("expected %s, got %s", want, base)
+	}
+}
+
+func TestRepoRoot(t *testing.T) {
+	dir := createTestGitRepo(t)
+	sub := filepath.Join(dir, "workloads", "jarvis")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, start := range []string{dir, sub} {
+		root, err := RepoRoot(start)
+		if err != nil {
+			t.Fatalf("RepoRoot(%s) failed: %v", start, err)
+		}
+		if root != want {
+			t.Errorf("RepoRoot(%s) = %s, want %s", start, root, want)
+		}
+	}
+
+	if _, err := RepoRoot(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
+
+func TestCurrentBranchAndTagExists(t *testing.T) {
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "content")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: initial")
+	runCmd(t, dir, "git", "checkout", "-b", "1.x")
+
+	branch, err := CurrentBranch(dir)
+	if err != nil {
+		t.Fatalf("CurrentBranch failed: %v", err)
+	}
+	if branch != "1.x" {
+		t.Errorf("expected 1.x, got %q", branch)
+	}
+
+	runCmd(t, dir, "git", "checkout", "--detach")
+	if branch, err := CurrentBranch(dir); err != nil || branch != "" {
+		t.Errorf("expected no branch when detached, got %q (%v)", branch, err)
+	}
+
+	runCmd(t, dir, "git", "tag", "app-v1.0.0")
+	if exists, err := TagExists(dir, "app-v1.0.0"); err != nil || !exists {
+		t.Errorf("expected tag to exist (%v)", err)
+	}
+	if exists, err := TagExists(dir, "app-v1.0.1"); err != nil || exists {
+		t.Errorf("expected tag not to exist (%v)", err)
+	}
+}
+
+func TestRemoteURLAndPushTag(t *testing.T) {
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "content")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: initial")
+
+	mirror := t.TempDir()
+	runCmd(t, mirror, "git", "init", "--bare")
+	runCmd(t, dir, "git", "remote", "add", "mirror", mirror)
+
+	url, err := RemoteURL(dir, "mirror")
+	if err != nil {
+		t.Fatalf("RemoteURL failed: %v", err)
+	}
+	if url != mirror {
+		t.Errorf("expected %s, got %s", mirror, url)
+	}
+	if _, err := RemoteURL(dir, "origin"); err == nil {
+		t.Error("expected error for missing remote")
+	}
+
+	head, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PushTag(dir, "mirror", "app-v1.0.0", head); err != nil {
+		t.Fatalf("PushTag failed: %v", err)
+	}
+	tagged, err := runGit(mirror, "rev-parse", "app-v1.0.0")
+	if err != nil {
+		t.Fatalf("tag missing on mirror: %v", err)
+	}
+	if tagged != head {
+		t.Errorf("mirror tag points at %s, want %s", tagged, head)
+	}
+}
+
 func TestAnalyzeHead_NonMergeCommit(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -261,6 +627,7 @@ func TestGetCommitsInRange_WithFiles(t *testing.T) {
 	runCmd(t, dir, "git", "add", "-A")
 	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add service")
 
+	listingBefore := FileListingTime()
 	commits, err := GetCommitsInRange(dir, initialSHA, "HEAD")
 	if err != nil {
 		t.Fatalf("GetCommitsInRange failed: %v", err)
@@ -273,6 +640,10 @@ func TestGetCommitsInRange_WithFiles(t *testing.T) {
 	if len(commits[0].Files) != 2 {
 		t.Errorf("expected 2 files, got %d", len(commits[0].Files))
 	}
+
+	if FileListingTime() <= listingBefore {
+		t.Errorf("expected file listing time to be recorded")
+	}
 }
 
 func TestGetCommitsInRange_MergeCommit(t *testing.T) {
@@ -351,6 +722,314 @@ func TestGetCommitsInRange_MergeCommit(t *testing.T) {
 	}
 }
 
+func TestGetMergedCommits_Octopus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	for _, branch := range []string{"a", "b", "c"} {
+		runCmd(t, dir, "git", "checkout", "-b", branch, "main")
+		writeFile(t, dir, branch+".txt", branch)
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "feat: add "+branch)
+	}
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "a", "b", "c", "-m", "Merge branches a, b and c")
+
+	info, err := AnalyzeHead(dir)
+	if err != nil {
+		t.Fatalf("AnalyzeHead failed: %v", err)
+	}
+	if !info.IsMerge || len(info.MergeHeads) != 3 {
+		t.Fatalf("expected octopus merge with 3 heads, got %+v", info)
+	}
+	if info.MergeHead != info.MergeHeads[0] {
+		t.Errorf("expected MergeHead to be the second parent")
+	}
+
+	commits, err := GetMergedCommits(dir, info)
+	if err != nil {
+		t.Fatalf("GetMergedCommits failed: %v", err)
+	}
+	var descriptions []string
+	for _, c := range commits {
+		descriptions = append(descriptions, c.Description)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("expected a commit from each merged branch, got %v", descriptions)
+	}
+}
+
+func TestGetMergedCommits_NestedAndBackMerged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	// Feature branch with a nested sub-feature merge
+	runCmd(t, dir, "git", "checkout", "-b", "feature")
+	writeFile(t, dir, "feature.txt", "feature")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: feature work")
+	runCmd(t, dir, "git", "checkout", "-b", "sub-feature")
+	writeFile(t, dir, "sub.txt", "sub")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: sub-feature work")
+	runCmd(t, dir, "git", "checkout", "feature")
+	runCmd(t, dir, "git", "merge", "--no-ff", "sub-feature", "-m", "Merge branch 'sub-feature' into feature")
+
+	// Mainline moves on and is back-merged into the feature branch
+	runCmd(t, dir, "git", "checkout", "main")
+	writeFile(t, dir, "main.txt", "main")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: mainline work")
+	runCmd(t, dir, "git", "checkout", "feature")
+	runCmd(t, dir, "git", "merge", "--no-ff", "main", "-m", "Merge branch 'main' into feature")
+
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature", "-m", "Merge branch 'feature'")
+
+	info, err := AnalyzeHead(dir)
+	if err != nil {
+		t.Fatalf("AnalyzeHead failed: %v", err)
+	}
+	commits, err := GetMergedCommits(dir, info)
+	if err != nil {
+		t.Fatalf("GetMergedCommits failed: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, c := range commits {
+		found[c.Description] = true
+	}
+	if !found["feature work"] || !found["sub-feature work"] {
+		t.Errorf("expected feature and nested sub-feature commits, got %v", found)
+	}
+	if found["mainline work"] {
+		t.Errorf("back-merged mainline commit should be excluded, got %v", found)
+	}
+}
+
+func TestGetMergedCommits_ExcludesRebasedMainlineCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "branch", "feature")
+
+	// Mainline gets a fix, which the feature branch picks up by cherry-pick
+	writeFile(t, dir, "main.txt", "main")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: mainline fix")
+	mainFix, _ := runGit(dir, "rev-parse", "HEAD")
+
+	runCmd(t, dir, "git", "checkout", "feature")
+	runCmd(t, dir, "git", "cherry-pick", mainFix)
+	writeFile(t, dir, "feature.txt", "feature")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: feature work")
+
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature", "-m", "Merge branch 'feature'")
+
+	info, err := AnalyzeHead(dir)
+	if err != nil {
+		t.Fatalf("AnalyzeHead failed: %v", err)
+	}
+	commits, err := GetMergedCommits(dir, info)
+	if err != nil {
+		t.Fatalf("GetMergedCommits failed: %v", err)
+	}
+
+	if len(commits) != 1 || commits[0].Description != "feature work" {
+		var descriptions []string
+		for _, c := range commits {
+			descriptions = append(descriptions, c.Description)
+		}
+		t.Errorf("expected only the feature commit, got %v", descriptions)
+	}
+}
+
+func TestGetReleasedEquivalents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	// Hotfix released from a maintenance branch
+	runCmd(t, dir, "git", "checkout", "-b", "release-1.x")
+	writeFile(t, dir, "file.txt", "hotfix")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: hotfix")
+	hotfix, _ := runGit(dir, "rev-parse", "HEAD")
+	runCmd(t, dir, "git", "tag", "service-v1.0.1")
+
+	// Main gains an unrelated fix, then picks up the hotfix
+	runCmd(t, dir, "git", "checkout", "main")
+	writeFile(t, dir, "other.txt", "other")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: other fix")
+	runCmd(t, dir, "git", "cherry-pick", hotfix)
+
+	commits, err := GetCommitsInRange(dir, "HEAD~2", "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitsInRange failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(commits))
+	}
+
+	equivalent, err := GetReleasedEquivalents(dir, ReleaseTagPattern, commits)
+	if err != nil {
+		t.Fatalf("GetReleasedEquivalents failed: %v", err)
+	}
+
+	if len(equivalent) != 1 || !equivalent[commits[1].SHA] {
+		t.Errorf("expected only the cherry-picked hotfix %s, got %v", commits[1].ShortSHA, equivalent)
+	}
+
+	// Tags outside the pattern aren't treated as releases
+	equivalent, err = GetReleasedEquivalents(dir, "nomatch-*", commits)
+	if err != nil {
+		t.Fatalf("GetReleasedEquivalents failed: %v", err)
+	}
+	if len(equivalent) != 0 {
+		t.Errorf("expected no equivalents for unmatched tag pattern, got %v", equivalent)
+	}
+}
+
+func TestGetMergeCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "checkout", "-b", "feature")
+	writeFile(t, dir, "api/handler.go", "package api")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "wip")
+	runCmd(t, dir, "git", "checkout", "main")
+	writeFile(t, dir, "main.txt", "main")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: mainline change")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature", "-m", "feat(api)!: add handler", "-m", "Details.")
+
+	info, err := AnalyzeHead(dir)
+	if err != nil {
+		t.Fatalf("AnalyzeHead failed: %v", err)
+	}
+	commit, err := GetMergeCommit(dir, info)
+	if err != nil {
+		t.Fatalf("GetMergeCommit failed: %v", err)
+	}
+
+	if commit.SHA != info.HeadSHA {
+		t.Errorf("expected HEAD %s, got %s", info.HeadSHA, commit.SHA)
+	}
+	if commit.Type != "feat" || commit.Scope != "api" || !commit.IsBreaking || commit.Description != "add handler" {
+		t.Errorf("unexpected parse: %+v", commit)
+	}
+	if commit.Body != "Details." {
+		t.Errorf("expected body %q, got %q", "Details.", commit.Body)
+	}
+	// Only what the merge brought in, not mainline changes
+	if len(commit.Files) != 1 || commit.Files[0] != "api/handler.go" {
+		t.Errorf("expected files [api/handler.go], got %v", commit.Files)
+	}
+}
+
+func TestListMergedTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "tag", "api-v1.0.0")
+	runCmd(t, dir, "git", "tag", "web-v2.0.0")
+
+	// A tag on an unmerged branch isn't reachable from HEAD
+	runCmd(t, dir, "git", "checkout", "-b", "other")
+	writeFile(t, dir, "other.txt", "other")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: other")
+	runCmd(t, dir, "git", "tag", "api-v1.1.0")
+	runCmd(t, dir, "git", "checkout", "main")
+
+	tags, err := ListMergedTags(dir, "api-v*")
+	if err != nil {
+		t.Fatalf("ListMergedTags failed: %v", err)
+	}
+	if len(tags) != 1 || tags[0] != "api-v1.0.0" {
+		t.Errorf("expected [api-v1.0.0], got %v", tags)
+	}
+
+	tags, err = ListMergedTags(dir, "none-v*")
+	if err != nil {
+		t.Fatalf("ListMergedTags failed: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
+
+func TestCommitPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "a/VERSION", "1.0.0")
+	writeFile(t, dir, "b/VERSION", "1.0.0")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	writeFile(t, dir, "a/VERSION", "1.1.0")
+	writeFile(t, dir, "a/CHANGELOG.md", "# Changelog")
+	writeFile(t, dir, "b/VERSION", "2.0.0")
+	runCmd(t, dir, "git", "add", "b/VERSION")
+
+	sha, err := CommitPaths(dir, "chore(a): release 1.1.0", []string{"a/VERSION", "a/CHANGELOG.md"})
+	if err != nil {
+		t.Fatalf("CommitPaths failed: %v", err)
+	}
+	if head, _ := runGit(dir, "rev-parse", "HEAD"); head != sha {
+		t.Errorf("expected HEAD %s, got %s", sha, head)
+	}
+
+	files, _ := runGit(dir, "show", "--name-only", "--format=%s", "HEAD")
+	if files != "chore(a): release 1.1.0\n\na/CHANGELOG.md\na/VERSION" {
+		t.Errorf("unexpected commit contents:\n%s", files)
+	}
+
+	// Other staged changes stay staged
+	if staged, _ := runGit(dir, "diff", "--cached", "--name-only"); staged != "b/VERSION" {
+		t.Errorf("expected b/VERSION to stay staged, got %q", staged)
+	}
+}
+
 func TestIsValidSHA(t *testing.T) {
 	tests := []struct {
 		sha   string
@@ -373,3 +1052,229 @@ func TestIsValidSHA(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "tag", "-a", "v1.0.0", "-m", "annotated")
+
+	sha, err := ResolveCommit(dir, "v1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveCommit failed: %v", err)
+	}
+	want, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha != want {
+		t.Errorf("expected the tagged commit %s, got %s", want, sha)
+	}
+
+	if _, err := ResolveCommit(dir, "HEAD~1"); err == nil {
+		t.Error("expected an error for a missing parent")
+	}
+}
+
+func TestListTagsReachableFrom(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "tag", "api-v1.0.0")
+	writeFile(t, dir, "file.txt", "second")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: second")
+	runCmd(t, dir, "git", "tag", "api-v1.1.0")
+	writeFile(t, dir, "file.txt", "third")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: third")
+	runCmd(t, dir, "git", "tag", "api-v1.2.0")
+
+	tags, err := ListTagsReachableFrom(dir, "refs/tags/api-v1.1.0", "api-v*")
+	if err != nil {
+		t.Fatalf("ListTagsReachableFrom failed: %v", err)
+	}
+	if strings.Join(tags, ",") != "api-v1.0.0,api-v1.1.0" {
+		t.Errorf("expected [api-v1.0.0 api-v1.1.0], got %v", tags)
+	}
+}
+
+func TestMergedCommitsTouching(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	runCmd(t, dir, "git", "checkout", "-b", "feature")
+	writeFile(t, dir, "apps/web/main.go", "// Web\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: web")
+	writeFile(t, dir, "apps/web/admin/main.go", "// Admin\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: admin")
+	writeFile(t, dir, "apps/web/package-lock.json", "{}\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: lockfile")
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature", "-m", "Merge feature")
+
+	info, err := AnalyzeHead(dir)
+	if err != nil {
+		t.Fatalf("AnalyzeHead failed: %v", err)
+	}
+
+	commits, err := GetMergedCommitsWithoutFiles(dir, info)
+	if err != nil {
+		t.Fatalf("GetMergedCommitsWithoutFiles failed: %v", err)
+	}
+	if len(commits) != 3 || commits[0].Description != "web" || commits[0].Files != nil {
+		t.Fatalf("expected the 3 merged commits without files, got %+v", commits)
+	}
+
+	shas, err := MergedCommitsTouching(dir, info, []string{"apps/web", ":(exclude)apps/web/admin", ":(exclude,glob)**/package-lock.json"})
+	if err != nil {
+		t.Fatalf("MergedCommitsTouching failed: %v", err)
+	}
+	if len(shas) != 1 || !shas[commits[0].SHA] {
+		t.Errorf("expected only the web commit, got %v", shas)
+	}
+
+	n, err := CountChangedFiles(dir, info.FirstParent, "HEAD")
+	if err != nil {
+		t.Fatalf("CountChangedFiles failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 changed files, got %d", n)
+	}
+	if n, err := CountChangedFiles(dir, "HEAD", "HEAD"); err != nil || n != 0 {
+		t.Errorf("expected no changed files, got %d, %v", n, err)
+	}
+}
+
+func TestEachCommitInRange(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "initial")
+	runCmd(t, dir, "git", "add", "file.txt")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	initialSHA, _ := runGit(dir, "rev-parse", "HEAD")
+
+	for i, subject := range []string{"feat: one", "fix: two", "docs: three"} {
+		writeFile(t, dir, "file.txt", subject)
+		runCmd(t, dir, "git", "add", "file.txt")
+		runCmd(t, dir, "git", "commit", "-m", subject+"\n\nBody "+strconv.Itoa(i))
+	}
+
+	var types []string
+	err := EachCommitInRange(dir, initialSHA, "HEAD", func(c *Commit) error {
+		if len(c.Files) != 1 || c.Files[0] != "file.txt" {
+			t.Errorf("%s: expected file.txt listed, got %v", c.Subject, c.Files)
+		}
+		types = append(types, c.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachCommitInRange failed: %v", err)
+	}
+	if strings.Join(types, ",") != "feat,fix,docs" {
+		t.Errorf("expected commits oldest first, got %v", types)
+	}
+
+	// Stopping early returns fn's error
+	stop := errors.New("stop")
+	var seen int
+	err = EachCommitSinceTag(dir, "", "HEAD", func(c *Commit) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+	if seen != 1 {
+		t.Errorf("expected iteration to stop after 1 commit, got %d", seen)
+	}
+
+	if err := EachCommitInRange(dir, "no-such-rev", "HEAD", func(*Commit) error { return nil }); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
+
+func TestBranchesAt(t *testing.T) {
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "file.txt", "content")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: initial")
+	runCmd(t, dir, "git", "branch", "1.x")
+	runCmd(t, dir, "git", "update-ref", "refs/remotes/origin/main", "HEAD")
+	runCmd(t, dir, "git", "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+
+	branches, err := BranchesAt(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("BranchesAt failed: %v", err)
+	}
+	if strings.Join(branches, ",") != "1.x,main" {
+		t.Errorf("expected [1.x main], got %v", branches)
+	}
+
+	writeFile(t, dir, "file.txt", "changed")
+	runCmd(t, dir, "git", "commit", "-am", "fix: change")
+	if branches, err := BranchesAt(dir, "HEAD"); err != nil || strings.Join(branches, ",") != "main" {
+		t.Errorf("expected only main at the new commit, got %v (%v)", branches, err)
+	}
+}
+
+func TestNewCommit(t *testing.T) {
+	commit := NewCommit("abc1234567890", "feat(api)!: add v2 endpoints\n\nBREAKING CHANGE: v1 is gone\n", []string{"api/main.go"})
+	if commit.Type != "feat" || commit.Scope != "api" || commit.Description != "add v2 endpoints" {
+		t.Errorf("unexpected commit: %+v", commit)
+	}
+	if !commit.IsBreaking || commit.Body != "BREAKING CHANGE: v1 is gone" {
+		t.Errorf("expected a breaking commit with its body, got %+v", commit)
+	}
+	if commit.ShortSHA != "abc1234" || len(commit.Files) != 1 {
+		t.Errorf("unexpected SHA or files: %+v", commit)
+	}
+
+	plain := NewCommit("def1234567890", "Update docs", nil)
+	if plain.Type != "" || plain.Description != "Update docs" || plain.Body != "" {
+		t.Errorf("unexpected non-conventional commit: %+v", plain)
+	}
+}
+
+func TestFileModes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "a/VERSION", "1.0.0")
+	writeFile(t, dir, "a/run.sh", "#!/bin/sh")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "update-index", "--chmod=+x", "a/run.sh")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	modes, err := FileModes(dir, "HEAD", []string{"a/VERSION", "a/run.sh", "a/CHANGELOG.md"})
+	if err != nil {
+		t.Fatalf("FileModes failed: %v", err)
+	}
+	if len(modes) != 2 || modes["a/VERSION"] != "100644" || modes["a/run.sh"] != "100755" {
+		t.Errorf("unexpected modes %v", modes)
+	}
+}
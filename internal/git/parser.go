@@ -0,0 +1,147 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultScopeDelimiters are the characters a scope is enclosed in, as in
+// "fix(api): ...".
+var DefaultScopeDelimiters = []string{"()"}
+
+// ParserOptions configures NewParser for repos whose subjects don't quite
+// follow the conventional commit format.
+type ParserOptions struct {
+	// StripPrefixes are regular expressions removed from the start of a
+	// subject, repeatedly, before it's parsed: an emoji ("✨ feat: ...") or
+	// a ticket ("[PROJ-1] feat: ...").
+	StripPrefixes []string
+
+	// ScopeDelimiters are the pairs of opening and closing characters a
+	// scope may be enclosed in (e.g., "()" or "[]"). Empty means
+	// DefaultScopeDelimiters.
+	ScopeDelimiters []string
+
+	// CaseSensitive if true, only lowercase types are conventional, so
+	// "Fix: ..." isn't. Types are otherwise lowercased.
+	CaseSensitive bool
+}
+
+// Parser parses conventional commit subjects:
+// type(scope)!: description  OR  type!: description  OR  type: description
+type Parser struct {
+	stripPrefixes []*regexp.Regexp
+	regex         *regexp.Regexp
+	caseSensitive bool
+}
+
+// defaultParser parses subjects with the default options.
+var defaultParser = mustNewParser(nil)
+
+// NewParser returns a parser with opts, which may be nil for the defaults.
+func NewParser(opts *ParserOptions) (*Parser, error) {
+	if opts == nil {
+		opts = &ParserOptions{}
+	}
+	p := &Parser{caseSensitive: opts.CaseSensitive}
+
+	for _, pattern := range opts.StripPrefixes {
+		re, err := regexp.Compile(`^(?:` + pattern + `)`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix pattern %q: %w", pattern, err)
+		}
+		p.stripPrefixes = append(p.stripPrefixes, re)
+	}
+
+	delimiters := opts.ScopeDelimiters
+	if len(delimiters) == 0 {
+		delimiters = DefaultScopeDelimiters
+	}
+	var scopes []string
+	for _, pair := range delimiters {
+		if utf8.RuneCountInString(pair) != 2 {
+			return nil, fmt.Errorf("scope delimiter %q must be an opening and a closing character", pair)
+		}
+		open, size := utf8.DecodeRuneInString(pair)
+		closing := pair[size:]
+		if pair == "()" {
+			// Scopes may hold one level of parentheses, as in "fix(api(v2)): ..."
+			scopes = append(scopes, `\(((?:[^()]|\([^()]*\))+)\)`)
+			continue
+		}
+		scopes = append(scopes, regexp.QuoteMeta(string(open))+`([^`+regexp.QuoteMeta(closing)+`]+)`+regexp.QuoteMeta(closing))
+	}
+	p.regex = regexp.MustCompile(`^(\w+)(?:` + strings.Join(scopes, "|") + `)?(!)?\s*:\s*(.+)$`)
+	return p, nil
+}
+
+func mustNewParser(opts *ParserOptions) *Parser {
+	p, err := NewParser(opts)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Parse sets the commit's type, scope, breaking flag, and description from a
+// conventional commit subject. Returns false, leaving the commit unchanged,
+// if subject isn't conventional.
+func (p *Parser) Parse(c *Commit, subject string) bool {
+	matches := p.regex.FindStringSubmatch(p.strip(subject))
+	if matches == nil {
+		return false
+	}
+	n := len(matches)
+
+	// "feat:   " has nothing to release
+	description := strings.TrimSpace(matches[n-1])
+	if description == "" {
+		return false
+	}
+	commitType := matches[1]
+	if p.caseSensitive && commitType != strings.ToLower(commitType) {
+		return false
+	}
+
+	// One group per scope delimiter; at most one matched
+	var scope string
+	for _, group := range matches[2 : n-2] {
+		if group != "" {
+			scope = group
+			break
+		}
+	}
+
+	c.Type = strings.ToLower(commitType)
+	c.Scope = strings.TrimSpace(scope)
+	c.IsBreaking = matches[n-2] == "!"
+	c.Description = description
+	return true
+}
+
+// Reparse parses the commit's subject and body again with p, replacing
+// what they were parsed as before.
+func (p *Parser) Reparse(c *Commit) {
+	c.Type, c.Scope, c.IsBreaking, c.BreakingDescription = "", "", false, ""
+	if !p.Parse(c, c.Subject) {
+		c.Description = c.Subject
+	}
+	c.ParseBreakingChange()
+}
+
+// strip removes the configured prefixes from the start of subject until
+// none matches.
+func (p *Parser) strip(subject string) string {
+	for stripped := true; stripped; {
+		stripped = false
+		for _, re := range p.stripPrefixes {
+			if loc := re.FindStringIndex(subject); loc != nil && loc[1] > 0 {
+				subject = strings.TrimLeft(subject[loc[1]:], " \t")
+				stripped = true
+			}
+		}
+	}
+	return subject
+}
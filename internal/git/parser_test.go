@@ -0,0 +1,92 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewParser_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ParserOptions
+		want string
+	}{
+		{"bad prefix pattern", &ParserOptions{StripPrefixes: []string{"[unclosed"}}, "invalid prefix pattern"},
+		{"one-character delimiter", &ParserOptions{ScopeDelimiters: []string{"("}}, "opening and a closing"},
+		{"three-character delimiter", &ParserOptions{ScopeDelimiters: []string{"(()"}}, "opening and a closing"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewParser(tc.opts); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestParser_Parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      *ParserOptions
+		subject   string
+		wantOK    bool
+		wantType  string
+		wantScope string
+		wantBreak bool
+		wantDesc  string
+	}{
+		{"defaults", nil, "feat(api)!: new API", true, "feat", "api", true, "new API"},
+		{"defaults lowercase type", nil, "Fix: typo", true, "fix", "", false, "typo"},
+		{"defaults leave emoji", nil, "✨ feat: sparkle", false, "", "", false, ""},
+		{"emoji prefix", &ParserOptions{StripPrefixes: []string{`\p{So}`}}, "✨ feat: sparkle", true, "feat", "", false, "sparkle"},
+		{"gitmoji shortcode", &ParserOptions{StripPrefixes: []string{`:\w+:`}}, ":sparkles: feat(ui): sparkle", true, "feat", "ui", false, "sparkle"},
+		{"repeated prefixes", &ParserOptions{StripPrefixes: []string{`\p{So}`, `\[[A-Z]+-\d+\]`}}, "[PROJ-1] ✨ fix: crash", true, "fix", "", false, "crash"},
+		{"prefix only at start", &ParserOptions{StripPrefixes: []string{`\[[A-Z]+-\d+\]`}}, "fix: [PROJ-1] crash", true, "fix", "", false, "[PROJ-1] crash"},
+		{"bracket scope", &ParserOptions{ScopeDelimiters: []string{"()", "[]"}}, "feat[web]: dark mode", true, "feat", "web", false, "dark mode"},
+		{"paren scope still works", &ParserOptions{ScopeDelimiters: []string{"()", "[]"}}, "feat(api(v2)): nested", true, "feat", "api(v2)", false, "nested"},
+		{"bracket scope only", &ParserOptions{ScopeDelimiters: []string{"[]"}}, "feat(web): dark mode", false, "", "", false, ""},
+		{"case sensitive", &ParserOptions{CaseSensitive: true}, "Fix: typo", false, "", "", false, ""},
+		{"case sensitive lowercase", &ParserOptions{CaseSensitive: true}, "fix: typo", true, "fix", "", false, "typo"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewParser(tc.opts)
+			if err != nil {
+				t.Fatalf("NewParser failed: %v", err)
+			}
+			var c Commit
+			if ok := p.Parse(&c, tc.subject); ok != tc.wantOK {
+				t.Fatalf("Parse(%q) = %v, want %v", tc.subject, ok, tc.wantOK)
+			}
+			if c.Type != tc.wantType || c.Scope != tc.wantScope || c.IsBreaking != tc.wantBreak || c.Description != tc.wantDesc {
+				t.Errorf("got %+v", c)
+			}
+		})
+	}
+}
+
+func TestParser_Reparse(t *testing.T) {
+	p, err := NewParser(&ParserOptions{StripPrefixes: []string{`\p{So}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := parseCommit("abc1234567890", "💥 feat: drop v1")
+	c.Body = "BREAKING CHANGE: v1 is gone."
+	if c.Type != "" {
+		t.Fatalf("expected the default parser to reject the emoji, got %+v", c)
+	}
+	p.Reparse(c)
+	if c.Type != "feat" || c.Description != "drop v1" || !c.IsBreaking || c.BreakingDescription != "v1 is gone." {
+		t.Errorf("unexpected reparsed commit: %+v", c)
+	}
+
+	c = parseCommit("abc1234567890", "feat!: old")
+	c.Subject = "Update things"
+	p.Reparse(c)
+	if c.Type != "" || c.IsBreaking || c.Description != "Update things" {
+		t.Errorf("expected a non-conventional commit after reparsing, got %+v", c)
+	}
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// gitlinkMode is the tree entry mode of a submodule's pinned commit.
+const gitlinkMode = "160000"
+
+// SubmoduleUpdate is a commit's change to the commit a submodule is pinned
+// at.
+type SubmoduleUpdate struct {
+	// Path is the submodule's path in the repository.
+	Path string
+
+	// Old is the previously pinned commit, or empty if the commit adds the
+	// submodule.
+	Old string
+
+	// New is the newly pinned commit, or empty if the commit removes the
+	// submodule.
+	New string
+}
+
+// SubmodulePaths returns the paths of the submodules in .gitmodules as of
+// HEAD, or nil if there are none.
+func SubmodulePaths(repoPath string) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+
+	if _, err := runGit(repoPath, "cat-file", "-e", "HEAD:.gitmodules"); err != nil {
+		return nil, nil
+	}
+	output, err := runGit(repoPath, "config", "--blob", "HEAD:.gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil {
+		// No submodule has a path
+		return nil, nil
+	}
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		if _, path, ok := strings.Cut(line, " "); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// SubmoduleUpdates returns the submodule pointers a commit changes.
+func SubmoduleUpdates(repoPath, sha string) ([]SubmoduleUpdate, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(sha, "sha")
+
+	output, err := runGit(repoPath, "diff-tree", "--no-commit-id", "-r", "--raw", "--no-abbrev", sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submodule updates of %s: %w", shortSHA(sha), err)
+	}
+	var updates []SubmoduleUpdate
+	for _, line := range strings.Split(output, "\n") {
+		// :<old mode> <new mode> <old sha> <new sha> <status>\t<path>
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(strings.TrimPrefix(meta, ":"))
+		if !ok || len(fields) < 4 || (fields[0] != gitlinkMode && fields[1] != gitlinkMode) {
+			continue
+		}
+		update := SubmoduleUpdate{Path: path}
+		if fields[0] == gitlinkMode {
+			update.Old = fields[2]
+		}
+		if fields[1] == gitlinkMode {
+			update.New = fields[3]
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// SubmoduleLog returns the subjects of the commits an update brings into
+// the submodule, newest first. The submodule must be checked out, with both
+// commits fetched.
+func SubmoduleLog(repoPath string, update SubmoduleUpdate) ([]string, error) {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+
+	if update.Old == "" || update.New == "" {
+		return nil, fmt.Errorf("submodule %s was added or removed", update.Path)
+	}
+	dir := filepath.Join(repoPath, filepath.FromSlash(update.Path))
+	// Without its own .git, git would run in the superproject instead
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil, fmt.Errorf("submodule %s is not checked out", update.Path)
+	}
+	output, err := runGit(dir, "log", "--format=%s", update.Old+".."+update.New)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of submodule %s: %w", update.Path, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
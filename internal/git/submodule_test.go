@@ -0,0 +1,78 @@
+package git
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// addSubmodule commits a repo with two commits as a submodule at path, and
+// returns the submodule's source repo.
+func addSubmodule(t *testing.T, dir, path string) string {
+	t.Helper()
+	lib := createTestGitRepo(t)
+	writeFile(t, lib, "lib.go", "// v1\n")
+	runCmd(t, lib, "git", "add", "-A")
+	runCmd(t, lib, "git", "commit", "-m", "feat: first")
+
+	runCmd(t, dir, "git", "-c", "protocol.file.allow=always", "submodule", "add", lib, path)
+	runCmd(t, dir, "git", "commit", "-m", "chore: add submodule")
+	return lib
+}
+
+func TestSubmoduleUpdates(t *testing.T) {
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "README.md", "# Test\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	if paths, err := SubmodulePaths(dir); err != nil || paths != nil {
+		t.Fatalf("expected no submodules, got %v, %v", paths, err)
+	}
+
+	lib := addSubmodule(t, dir, "pkg/lib")
+	old, _ := runGit(dir, "rev-parse", "HEAD:pkg/lib")
+	writeFile(t, lib, "lib.go", "// v2\n")
+	runCmd(t, lib, "git", "commit", "-am", "fix: second")
+	writeFile(t, lib, "lib.go", "// v3\n")
+	runCmd(t, lib, "git", "commit", "-am", "feat: third")
+	runCmd(t, dir+"/pkg/lib", "git", "pull", "-q", "origin", "main")
+	runCmd(t, dir, "git", "commit", "-am", "chore: bump lib")
+	head, _ := runGit(dir, "rev-parse", "HEAD")
+	newSHA, _ := runGit(dir, "rev-parse", "HEAD:pkg/lib")
+
+	paths, err := SubmodulePaths(dir)
+	if err != nil || !slices.Equal(paths, []string{"pkg/lib"}) {
+		t.Fatalf("expected [pkg/lib], got %v, %v", paths, err)
+	}
+
+	updates, err := SubmoduleUpdates(dir, head)
+	if err != nil {
+		t.Fatalf("SubmoduleUpdates failed: %v", err)
+	}
+	want := []SubmoduleUpdate{{Path: "pkg/lib", Old: old, New: newSHA}}
+	if !slices.Equal(updates, want) {
+		t.Fatalf("expected %+v, got %+v", want, updates)
+	}
+
+	subjects, err := SubmoduleLog(dir, updates[0])
+	if err != nil {
+		t.Fatalf("SubmoduleLog failed: %v", err)
+	}
+	if got := strings.Join(subjects, "|"); got != "feat: third|fix: second" {
+		t.Errorf("unexpected subjects %q", got)
+	}
+
+	added, err := SubmoduleUpdates(dir, head+"~1")
+	if err != nil || len(added) != 1 || added[0].Old != "" || added[0].New != old {
+		t.Errorf("expected the submodule added at %s, got %+v, %v", old, added, err)
+	}
+	if _, err := SubmoduleLog(dir, added[0]); err == nil {
+		t.Error("expected no log for an added submodule")
+	}
+
+	runCmd(t, dir, "git", "submodule", "deinit", "-q", "pkg/lib")
+	if _, err := SubmoduleLog(dir, updates[0]); err == nil || !strings.Contains(err.Error(), "not checked out") {
+		t.Errorf("expected a not checked out error, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"bytes"
+	"io/fs"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// treeFS reads the files of a commit's tree (see TreeFS).
+type treeFS struct {
+	repoPath string
+	rev      string
+}
+
+// TreeFS returns the files of rev's tree as an fs.FS, for reading a bare
+// repository's config and manifest, which have no working tree to read
+// from. Only regular files can be opened.
+func TreeFS(repoPath, rev string) fs.FS {
+	contracts.RequireNotEmpty(repoPath, "repoPath")
+	contracts.RequireNotEmpty(rev, "rev")
+	return &treeFS{repoPath: repoPath, rev: rev}
+}
+
+// Open implements fs.FS.
+func (t *treeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	// "<mode> <type> <object>\t<path>", or nothing if name isn't there
+	entry, err := runGit(t.repoPath, "ls-tree", "--full-tree", t.rev, "--", name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	meta, _, ok := strings.Cut(entry, "\t")
+	fields := strings.Fields(meta)
+	if !ok || len(fields) != 3 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if fields[1] != "blob" {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	cmd := exec.Command("git", "cat-file", "blob", fields[2])
+	cmd.Dir = t.repoPath
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &treeFile{Reader: bytes.NewReader(data), name: path.Base(name)}, nil
+}
+
+// treeFile is an open file of a treeFS.
+type treeFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *treeFile) Close() error               { return nil }
+
+// Name, Mode, ModTime, IsDir, and Sys, with the Reader's Size, implement
+// fs.FileInfo.
+func (f *treeFile) Name() string       { return f.name }
+func (f *treeFile) Mode() fs.FileMode  { return 0444 }
+func (f *treeFile) ModTime() time.Time { return time.Time{} }
+func (f *treeFile) IsDir() bool        { return false }
+func (f *treeFile) Sys() any           { return nil }
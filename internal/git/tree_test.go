@@ -0,0 +1,61 @@
+package git
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+)
+
+func TestBareRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestGitRepo(t)
+	writeFile(t, dir, "config/app.json", `{"name": "app"}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: initial")
+
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	runCmd(t, dir, "git", "clone", "--bare", dir, bare)
+
+	if isBare, err := IsBareRepository(dir); err != nil || isBare {
+		t.Errorf("expected a checkout not to be bare, got %t (%v)", isBare, err)
+	}
+	if isBare, err := IsBareRepository(bare); err != nil || !isBare {
+		t.Errorf("expected the clone to be bare, got %t (%v)", isBare, err)
+	}
+	root, err := RepoRoot(bare)
+	if err != nil {
+		t.Fatalf("RepoRoot of a bare repository failed: %v", err)
+	}
+	if want, _ := filepath.EvalSymlinks(bare); root != want && root != bare {
+		t.Errorf("expected the git directory %s as the root, got %s", bare, root)
+	}
+	if _, err := RepoRoot(t.TempDir()); err == nil {
+		t.Error("expected an error outside a repository")
+	}
+
+	tree := TreeFS(bare, "HEAD")
+	data, err := fs.ReadFile(tree, "config/app.json")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != `{"name": "app"}` {
+		t.Errorf("expected the committed content, got %q", data)
+	}
+	info, err := fs.Stat(tree, "config/app.json")
+	if err != nil || info.Name() != "app.json" || info.Size() != int64(len(data)) || info.IsDir() {
+		t.Errorf("unexpected file info %v (%v)", info, err)
+	}
+	if _, err := fs.ReadFile(tree, "missing.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist for a missing file, got %v", err)
+	}
+	if _, err := tree.Open("config"); err == nil {
+		t.Error("expected an error opening a directory")
+	}
+	if _, err := tree.Open("../outside"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected fs.ErrInvalid for an invalid path, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Package golden compares rendered output against golden files stored under
+// testdata, so a change to the changelog or release notes format shows up
+// as a diff in review. Run the tests with UPDATE_GOLDEN=1 to rewrite the
+// golden files from the current output.
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/diff"
+)
+
+// UpdateEnv names the environment variable that, when set to anything but
+// "" or "0", makes Assert rewrite golden files instead of comparing.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Update reports whether golden files should be rewritten.
+func Update() bool {
+	v := os.Getenv(UpdateEnv)
+	return v != "" && v != "0"
+}
+
+// Assert compares got against the golden file at path, failing the test with
+// a unified diff when they differ. With UPDATE_GOLDEN set, it writes got to
+// path instead.
+func Assert(t testing.TB, path, got string) {
+	t.Helper()
+
+	if Update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with %s=1 to create it): %v", UpdateEnv, err)
+	}
+	if d := diff.Unified(filepath.ToSlash(path), string(want), got); d != "" {
+		t.Errorf("output differs from %s (run with %s=1 to update it):\n%s", path, UpdateEnv, d)
+	}
+}
@@ -0,0 +1,91 @@
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recorder is a testing.TB that records failures instead of failing.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestUpdate(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "1": true, "true": true} {
+		t.Setenv(UpdateEnv, value)
+		if got := Update(); got != want {
+			t.Errorf("%s=%q: expected %v, got %v", UpdateEnv, value, want, got)
+		}
+	}
+}
+
+func TestAssert_Match(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(path, []byte("## jarvis v0.1.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{TB: t}
+	Assert(r, path, "## jarvis v0.1.1\n")
+	if len(r.failures) > 0 {
+		t.Errorf("expected no failures, got %v", r.failures)
+	}
+}
+
+func TestAssert_Mismatch(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(path, []byte("## jarvis v0.1.1\n\n### Features\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{TB: t}
+	Assert(r, path, "## jarvis v0.1.1\n\n### Bug Fixes\n")
+	if len(r.failures) != 1 {
+		t.Fatalf("expected one failure, got %v", r.failures)
+	}
+	for _, want := range []string{"-### Features", "+### Bug Fixes", "UPDATE_GOLDEN=1"} {
+		if !strings.Contains(r.failures[0], want) {
+			t.Errorf("expected %q in failure:\n%s", want, r.failures[0])
+		}
+	}
+}
+
+func TestAssert_Missing(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	r := &recorder{TB: t}
+	Assert(r, filepath.Join(t.TempDir(), "missing.md"), "content\n")
+	if len(r.failures) == 0 || !strings.Contains(r.failures[0], "to create it") {
+		t.Errorf("expected a missing golden file failure, got %v", r.failures)
+	}
+}
+
+func TestAssert_Update(t *testing.T) {
+	t.Setenv(UpdateEnv, "1")
+	path := filepath.Join(t.TempDir(), "scenario", "notes.md")
+
+	r := &recorder{TB: t}
+	Assert(r, path, "updated\n")
+	if len(r.failures) > 0 {
+		t.Fatalf("expected no failures, got %v", r.failures)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "updated\n" {
+		t.Errorf("expected golden file to be written, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,177 @@
+// Package interactive implements the --interactive review mode, a small
+// line-oriented terminal UI for manual release drivers. The operator can
+// toggle packages, override target versions, and preview changelog entries
+// before confirming that Apply should run.
+package interactive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// item is a release under review.
+type item struct {
+	rel     *release.PackageRelease
+	enabled bool
+}
+
+// Review runs the review loop, reading commands from in and writing to out.
+// On confirmation, result.Releases is replaced by the enabled releases (with any
+// version overrides applied) and true is returned. Returns false if the operator
+// quits or input ends before confirming.
+func Review(result *release.AnalysisResult, in io.Reader, out io.Writer) (bool, error) {
+	contracts.RequireNotNil(result, "result")
+
+	items := make([]*item, 0, len(result.Releases))
+	for _, rel := range result.Releases {
+		items = append(items, &item{rel: rel, enabled: true})
+	}
+
+	scanner := bufio.NewScanner(in)
+	printList(out, items)
+
+	for {
+		fmt.Fprint(out, "\n> ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return false, err
+			}
+			fmt.Fprintln(out)
+			return false, nil
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "y", "yes", "apply":
+			var selected []*release.PackageRelease
+			for _, it := range items {
+				if it.enabled {
+					selected = append(selected, it.rel)
+				}
+			}
+			result.Releases = selected
+			return true, nil
+
+		case "q", "quit":
+			return false, nil
+
+		case "l", "list":
+			printList(out, items)
+
+		case "t", "toggle":
+			it, err := selectItem(items, fields)
+			if err != nil {
+				fmt.Fprintf(out, "%v\n", err)
+				continue
+			}
+			it.enabled = !it.enabled
+			printList(out, items)
+
+		case "v", "version":
+			it, err := selectItem(items, fields)
+			if err != nil {
+				fmt.Fprintf(out, "%v\n", err)
+				continue
+			}
+			if len(fields) < 3 {
+				fmt.Fprintln(out, "usage: v <n> <version>")
+				continue
+			}
+			if err := setVersion(it.rel, fields[2]); err != nil {
+				fmt.Fprintf(out, "%v\n", err)
+				continue
+			}
+			printList(out, items)
+
+		case "p", "preview":
+			it, err := selectItem(items, fields)
+			if err != nil {
+				fmt.Fprintf(out, "%v\n", err)
+				continue
+			}
+			fmt.Fprintln(out)
+			fmt.Fprint(out, previewChangelog(it.rel, result))
+
+		case "h", "help", "?":
+			printHelp(out)
+
+		default:
+			fmt.Fprintf(out, "unknown command %q (h for help)\n", fields[0])
+		}
+	}
+}
+
+// printList shows the releases with their selection state.
+func printList(out io.Writer, items []*item) {
+	fmt.Fprintln(out, "\nReleases:")
+	for i, it := range items {
+		mark := "x"
+		if !it.enabled {
+			mark = " "
+		}
+		fmt.Fprintf(out, "  %d. [%s] %-20s %s → %s (%s) [%d commit(s)]\n",
+			i+1, mark, it.rel.Package.Component, it.rel.OldVersion, it.rel.NewVersion,
+			it.rel.BumpType, len(it.rel.Commits))
+	}
+	fmt.Fprintln(out, "\nCommands: t <n> toggle, v <n> <version> set version, p <n> preview, y apply, q quit, h help")
+}
+
+// printHelp describes the available commands.
+func printHelp(out io.Writer) {
+	fmt.Fprintln(out, `  l                  list releases
+  t <n>              toggle release n on/off
+  v <n> <version>    set the target version for release n
+  p <n>              preview the changelog entry for release n
+  y                  apply the selected releases
+  q                  quit without applying`)
+}
+
+// selectItem parses the 1-based index argument of a command.
+func selectItem(items []*item, fields []string) (*item, error) {
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("usage: %s <n>", fields[0])
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil || n < 1 || n > len(items) {
+		return nil, fmt.Errorf("no release %q (expected 1-%d)", fields[1], len(items))
+	}
+	return items[n-1], nil
+}
+
+// setVersion overrides the target version, which must be valid semver and
+// greater than the current version.
+func setVersion(rel *release.PackageRelease, s string) error {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return fmt.Errorf("version cannot be empty")
+	}
+	newVersion, err := version.Parse(s)
+	if err != nil {
+		return err
+	}
+	oldVersion, err := version.Parse(rel.OldVersion)
+	if err == nil && newVersion.Compare(oldVersion) <= 0 {
+		return fmt.Errorf("version %s must be greater than %s", newVersion, rel.OldVersion)
+	}
+	rel.NewVersion = newVersion.String()
+	return nil
+}
+
+// previewChangelog renders the changelog entry Apply would write.
+func previewChangelog(rel *release.PackageRelease, result *release.AnalysisResult) string {
+	if len(rel.Commits) == 0 && rel.Package.LinkedGroup == "" && len(rel.DependencyChain) == 0 {
+		return "(no changelog entry: release without commits)\n"
+	}
+	return release.RenderChangelog(result, rel)
+}
@@ -0,0 +1,98 @@
+package interactive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// reviewResult returns an analysis with two releases.
+func reviewResult() *release.AnalysisResult {
+	commit := &git.Commit{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "add endpoint"}
+	return &release.AnalysisResult{
+		Releases: []*release.PackageRelease{
+			{Package: &config.Package{Path: "api", Component: "api"}, BumpType: version.Minor, OldVersion: "1.0.0", NewVersion: "1.1.0", Commits: []*git.Commit{commit}},
+			{Package: &config.Package{Path: "web", Component: "web", LinkedGroup: "apps"}, BumpType: version.Patch, OldVersion: "0.3.0", NewVersion: "0.3.1"},
+		},
+		Config: &config.Config{},
+	}
+}
+
+func TestReview_ToggleAndConfirm(t *testing.T) {
+	result := reviewResult()
+	var out bytes.Buffer
+
+	confirmed, err := Review(result, strings.NewReader("t 2\ny\n"), &out)
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if !confirmed {
+		t.Fatal("expected confirmation")
+	}
+	if len(result.Releases) != 1 || result.Releases[0].Package.Component != "api" {
+		t.Errorf("expected only api to remain, got %d releases", len(result.Releases))
+	}
+	if !strings.Contains(out.String(), "2. [ ] web") {
+		t.Errorf("expected web to be shown as disabled, got:\n%s", out.String())
+	}
+}
+
+func TestReview_SetVersion(t *testing.T) {
+	result := reviewResult()
+	var out bytes.Buffer
+
+	input := "v 1 0.9.0\nv 1 nope\nv 1 v\nv 1 v2.0.0\ny\n"
+	confirmed, err := Review(result, strings.NewReader(input), &out)
+	if err != nil || !confirmed {
+		t.Fatalf("expected confirmation, got %v, %v", confirmed, err)
+	}
+
+	if result.Releases[0].NewVersion != "2.0.0" {
+		t.Errorf("expected 2.0.0, got %s", result.Releases[0].NewVersion)
+	}
+	if !strings.Contains(out.String(), "must be greater than 1.0.0") {
+		t.Errorf("expected downgrade to be rejected, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "invalid semver: nope") {
+		t.Errorf("expected invalid version to be rejected, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "version cannot be empty") {
+		t.Errorf("expected empty version to be rejected, got:\n%s", out.String())
+	}
+}
+
+func TestReview_Preview(t *testing.T) {
+	var out bytes.Buffer
+
+	if _, err := Review(reviewResult(), strings.NewReader("p 1\np 2\nq\n"), &out); err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "### Features") || !strings.Contains(out.String(), "* add endpoint") {
+		t.Errorf("expected changelog preview, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Version bump to stay in sync with apps.") {
+		t.Errorf("expected note for linked bump without commits, got:\n%s", out.String())
+	}
+}
+
+func TestReview_QuitAndEOF(t *testing.T) {
+	for _, input := range []string{"q\n", "", "t 9\nbogus\n"} {
+		result := reviewResult()
+		confirmed, err := Review(result, strings.NewReader(input), &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("Review(%q) failed: %v", input, err)
+		}
+		if confirmed {
+			t.Errorf("Review(%q) should not confirm", input)
+		}
+		if len(result.Releases) != 2 {
+			t.Errorf("Review(%q) should leave releases untouched", input)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package jira links Jira issues referenced in commit messages to releases.
+// It extracts issue keys (PROJ-123), renders them as links in changelogs, and
+// optionally comments on and transitions the issues via the Jira REST API.
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// keyRegex matches Jira issue keys like PROJ-123.
+var keyRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+)-([1-9][0-9]*)\b`)
+
+// defaultTimeout bounds each Jira API request.
+const defaultTimeout = 15 * time.Second
+
+// Keys returns the unique Jira keys found in text, in order of appearance.
+// If projects is non-empty, only keys for those project prefixes are returned;
+// otherwise well-known lookalikes such as "UTF-8" or "SHA-256" are skipped.
+func Keys(text string, projects []string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range keyRegex.FindAllStringSubmatch(text, -1) {
+		if !allowedProject(m[1], projects) || seen[m[0]] {
+			continue
+		}
+		seen[m[0]] = true
+		keys = append(keys, m[0])
+	}
+	return keys
+}
+
+// Linkify replaces Jira keys in text with markdown links to the issue.
+// Returns text unchanged if baseURL is empty.
+func Linkify(text, baseURL string, projects []string) string {
+	if baseURL == "" {
+		return text
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return keyRegex.ReplaceAllStringFunc(text, func(key string) string {
+		m := keyRegex.FindStringSubmatch(key)
+		if !allowedProject(m[1], projects) {
+			return key
+		}
+		return fmt.Sprintf("[%s](%s/browse/%s)", key, baseURL, key)
+	})
+}
+
+// notProjects are prefixes that look like issue keys but are standards or
+// algorithm names (UTF-8, SHA-256, ISO-8601). They are skipped when no project
+// filter is configured.
+var notProjects = map[string]bool{
+	"UTF": true, "UCS": true, "SHA": true, "MD": true, "ISO": true,
+	"RFC": true, "CVE": true, "CWE": true, "AES": true, "RSA": true,
+	"HTTP": true, "TLS": true, "IPV": true,
+}
+
+// allowedProject reports whether a project prefix passes the filter.
+func allowedProject(project string, projects []string) bool {
+	if len(projects) == 0 {
+		return !notProjects[project]
+	}
+	for _, p := range projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+// Release describes a released component for issue updates.
+type Release struct {
+	Component string
+	Version   string
+	URL       string
+
+	// Messages are the commit messages included in the release.
+	Messages []string
+}
+
+// Update records the outcome for a single issue.
+type Update struct {
+	Key          string
+	Releases     []string // "component vX.Y.Z"
+	Commented    bool
+	Transitioned bool
+}
+
+// Client talks to the Jira REST API (v2).
+type Client struct {
+	cfg  *config.Jira
+	http *http.Client
+
+	// auth sets credentials on a request.
+	auth func(req *http.Request)
+}
+
+// NewClient creates a client from config, reading credentials from the environment.
+// With email-env set it uses basic auth (Jira Cloud API tokens); otherwise the
+// token is sent as a bearer token (Jira Data Center personal access tokens).
+func NewClient(cfg *config.Jira) (*Client, error) {
+	contracts.RequireNotNil(cfg, "cfg")
+
+	token := os.Getenv(cfg.TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("jira token env %s is not set", cfg.TokenEnv)
+	}
+
+	c := &Client{cfg: cfg, http: &http.Client{Timeout: defaultTimeout}}
+	if cfg.EmailEnv != "" {
+		email := os.Getenv(cfg.EmailEnv)
+		if email == "" {
+			return nil, fmt.Errorf("jira email env %s is not set", cfg.EmailEnv)
+		}
+		c.auth = func(req *http.Request) { req.SetBasicAuth(email, token) }
+	} else {
+		c.auth = func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	}
+	return c, nil
+}
+
+// UpdateIssues comments on and/or transitions every issue referenced by the
+// releases, per config. An issue referenced by several releases gets one
+// comment listing all of them. Errors for individual issues are collected and
+// don't stop processing of the others.
+func (c *Client) UpdateIssues(releases []*Release) ([]*Update, error) {
+	byKey := make(map[string]*Update)
+	for _, rel := range releases {
+		label := fmt.Sprintf("%s v%s", rel.Component, rel.Version)
+		if rel.URL != "" {
+			label = fmt.Sprintf("[%s|%s]", label, rel.URL)
+		}
+		for _, msg := range rel.Messages {
+			for _, key := range Keys(msg, c.cfg.Projects) {
+				u := byKey[key]
+				if u == nil {
+					u = &Update{Key: key}
+					byKey[key] = u
+				}
+				if !containsString(u.Releases, label) {
+					u.Releases = append(u.Releases, label)
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var updates []*Update
+	var errs []error
+	for _, key := range keys {
+		u := byKey[key]
+		updates = append(updates, u)
+
+		if c.cfg.Comment {
+			body := "Released in " + strings.Join(u.Releases, ", ")
+			if err := c.addComment(key, body); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			} else {
+				u.Commented = true
+			}
+		}
+
+		if c.cfg.TransitionTo != "" {
+			if err := c.transition(key, c.cfg.TransitionTo); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			} else {
+				u.Transitioned = true
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return updates, errors.Join(errs...)
+	}
+	return updates, nil
+}
+
+// addComment posts a comment on an issue.
+func (c *Client) addComment(key, body string) error {
+	return c.do(http.MethodPost, "/rest/api/2/issue/"+key+"/comment", map[string]string{"body": body}, nil)
+}
+
+// transition moves an issue to the transition with the given name
+// (case-insensitive). Issues already past that state have no such transition
+// available; that's reported as an error so it shows up in the run output.
+func (c *Client) transition(key, name string) error {
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := c.do(http.MethodGet, "/rest/api/2/issue/"+key+"/transitions", nil, &available); err != nil {
+		return err
+	}
+
+	for _, t := range available.Transitions {
+		if strings.EqualFold(t.Name, name) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return c.do(http.MethodPost, "/rest/api/2/issue/"+key+"/transitions", payload, nil)
+		}
+	}
+	return fmt.Errorf("no transition named %q available", name)
+}
+
+// do performs a JSON API request, decoding the response into out if non-nil.
+func (c *Client) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.cfg.BaseURL, "/")+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.auth(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("invalid response from %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
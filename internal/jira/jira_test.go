@@ -0,0 +1,175 @@
+package jira
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		projects []string
+		want     []string
+	}{
+		{"single", "fix login PROJ-123", nil, []string{"PROJ-123"}},
+		{"multiple unique", "PROJ-1 and OPS-22, again PROJ-1", nil, []string{"PROJ-1", "OPS-22"}},
+		{"none", "fix typo", nil, nil},
+		{"lowercase ignored", "proj-123", nil, nil},
+		{"project filter", "PROJ-1 uses UTF-8 and SHA-256", []string{"PROJ"}, []string{"PROJ-1"}},
+		{"no filter skips lookalikes", "UTF-8, SHA-256 and ISO-8601 for PROJ-4", nil, []string{"PROJ-4"}},
+		{"filter allows listed lookalike", "fixes ISO-8", []string{"ISO"}, []string{"ISO-8"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Keys(tc.text, tc.projects)
+			if len(got) != len(tc.want) {
+				t.Fatalf("Keys(%q) = %v, want %v", tc.text, got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("Keys(%q) = %v, want %v", tc.text, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkify(t *testing.T) {
+	got := Linkify("fix PROJ-12 (UTF-8)", "https://acme.atlassian.net/", []string{"PROJ"})
+	want := "fix [PROJ-12](https://acme.atlassian.net/browse/PROJ-12) (UTF-8)"
+	if got != want {
+		t.Errorf("Linkify = %q, want %q", got, want)
+	}
+
+	got = Linkify("use SHA-256 for PROJ-12", "https://acme.atlassian.net", nil)
+	want = "use SHA-256 for [PROJ-12](https://acme.atlassian.net/browse/PROJ-12)"
+	if got != want {
+		t.Errorf("Linkify without projects = %q, want %q", got, want)
+	}
+
+	if got := Linkify("fix PROJ-12", "", nil); got != "fix PROJ-12" {
+		t.Errorf("expected text unchanged without base URL, got %q", got)
+	}
+}
+
+// fakeJira records API calls and serves a fixed transition list.
+type fakeJira struct {
+	mu          sync.Mutex
+	comments    map[string]string
+	transitions map[string]string
+	auth        string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = r.Header.Get("Authorization")
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/")
+	key, action := parts[0], parts[1]
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case action == "comment" && r.Method == http.MethodPost:
+		var c struct{ Body string }
+		json.Unmarshal(body, &c)
+		f.comments[key] = c.Body
+		w.WriteHeader(http.StatusCreated)
+	case action == "transitions" && r.Method == http.MethodGet:
+		w.Write([]byte(`{"transitions": [{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}]}`))
+	case action == "transitions" && r.Method == http.MethodPost:
+		var tr struct {
+			Transition struct{ ID string } `json:"transition"`
+		}
+		json.Unmarshal(body, &tr)
+		f.transitions[key] = tr.Transition.ID
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUpdateIssues(t *testing.T) {
+	fake := &fakeJira{comments: map[string]string{}, transitions: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	t.Setenv("TEST_JIRA_TOKEN", "pat")
+
+	client, err := NewClient(&config.Jira{
+		BaseURL:      server.URL,
+		TokenEnv:     "TEST_JIRA_TOKEN",
+		Comment:      true,
+		TransitionTo: "done",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	updates, err := client.UpdateIssues([]*Release{
+		{Component: "api", Version: "1.2.0", Messages: []string{"add endpoint PROJ-1", "fix PROJ-2"}},
+		{Component: "web", Version: "0.3.1", URL: "https://github.com/o/r/releases/tag/web-v0.3.1", Messages: []string{"use endpoint PROJ-1"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateIssues failed: %v", err)
+	}
+
+	if len(updates) != 2 || updates[0].Key != "PROJ-1" || updates[1].Key != "PROJ-2" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if fake.auth != "Bearer pat" {
+		t.Errorf("expected bearer auth, got %q", fake.auth)
+	}
+	want := "Released in api v1.2.0, [web v0.3.1|https://github.com/o/r/releases/tag/web-v0.3.1]"
+	if fake.comments["PROJ-1"] != want {
+		t.Errorf("unexpected comment: %q", fake.comments["PROJ-1"])
+	}
+	if fake.transitions["PROJ-1"] != "31" || fake.transitions["PROJ-2"] != "31" {
+		t.Errorf("expected Done transition, got %v", fake.transitions)
+	}
+}
+
+func TestUpdateIssues_MissingTransition(t *testing.T) {
+	fake := &fakeJira{comments: map[string]string{}, transitions: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	t.Setenv("TEST_JIRA_TOKEN", "token")
+	t.Setenv("TEST_JIRA_EMAIL", "bot@example.com")
+
+	client, err := NewClient(&config.Jira{
+		BaseURL:      server.URL,
+		TokenEnv:     "TEST_JIRA_TOKEN",
+		EmailEnv:     "TEST_JIRA_EMAIL",
+		TransitionTo: "Released",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	updates, err := client.UpdateIssues([]*Release{{Component: "api", Version: "1.0.0", Messages: []string{"PROJ-9"}}})
+	if err == nil || !strings.Contains(err.Error(), `no transition named "Released"`) {
+		t.Errorf("expected missing transition error, got %v", err)
+	}
+	if len(updates) != 1 || updates[0].Transitioned {
+		t.Errorf("unexpected updates: %+v", updates)
+	}
+	if !strings.HasPrefix(fake.auth, "Basic ") {
+		t.Errorf("expected basic auth with email, got %q", fake.auth)
+	}
+}
+
+func TestNewClient_MissingToken(t *testing.T) {
+	if _, err := NewClient(&config.Jira{BaseURL: "https://x", TokenEnv: "TEST_JIRA_UNSET_TOKEN"}); err == nil {
+		t.Error("expected error when token env is unset")
+	}
+}
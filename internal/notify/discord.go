@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// Embed colors by bump type (Discord uses decimal RGB).
+const (
+	discordColorMajor = 0xE74C3C
+	discordColorMinor = 0x2ECC71
+	discordColorPatch = 0x3498DB
+)
+
+// maxDiscordEmbeds, maxDiscordContent, and maxDiscordDescription are the
+// most embeds Discord accepts in one message, the longest message content,
+// and the longest embed description, in characters.
+const (
+	maxDiscordEmbeds      = 10
+	maxDiscordContent     = 2000
+	maxDiscordDescription = 4096
+)
+
+// DiscordMessage is a Discord webhook payload.
+type DiscordMessage struct {
+	Content string         `json:"content"`
+	Embeds  []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordEmbed is a single rich embed in a Discord message.
+type DiscordEmbed struct {
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Color       int    `json:"color"`
+}
+
+// BuildDiscordMessage renders one embed per released component. Past
+// Discord's limit, the last embed lists the remaining components instead.
+func BuildDiscordMessage(report *release.ReleaseReport) *DiscordMessage {
+	msg := &DiscordMessage{
+		Content: discordContent(report),
+	}
+
+	for i, rel := range report.Releases {
+		if i == maxDiscordEmbeds-1 && len(report.Releases) > maxDiscordEmbeds {
+			msg.Embeds = append(msg.Embeds, discordOverflowEmbed(report.Releases[i:]))
+			break
+		}
+		description := fmt.Sprintf("%s bump from %s", rel.BumpType, rel.OldVersion)
+		if highlights := highlightSummary(rel.Commits, "⚠"); highlights != "" {
+			description += "\n" + highlights
+		}
+
+		msg.Embeds = append(msg.Embeds, DiscordEmbed{
+			Title:       fmt.Sprintf("%s %s", rel.Component, rel.NewVersion),
+			URL:         rel.ReleaseURL,
+			Description: description,
+			Color:       discordColor(rel.BumpType),
+		})
+	}
+
+	return msg
+}
+
+// discordContent lists the released components, as many as fit in the
+// message content.
+func discordContent(report *release.ReleaseReport) string {
+	var content strings.Builder
+	fmt.Fprintf(&content, "Released %d component(s): ", len(report.Releases))
+	for i, component := range report.Components {
+		if i > 0 {
+			content.WriteString(", ")
+		}
+		// Leave room for the count of the rest
+		if utf8.RuneCountInString(content.String())+utf8.RuneCountInString(component)+40 > maxDiscordContent {
+			fmt.Fprintf(&content, "and %d more", len(report.Components)-i)
+			break
+		}
+		content.WriteString(component)
+	}
+	return content.String()
+}
+
+// discordOverflowEmbed summarizes the releases that don't get an embed of
+// their own, one line each, as many as fit in the description.
+func discordOverflowEmbed(releases []release.ComponentRelease) DiscordEmbed {
+	var description strings.Builder
+	for i, rel := range releases {
+		line := fmt.Sprintf("%s %s", rel.Component, rel.NewVersion)
+		if rel.ReleaseURL != "" {
+			line = fmt.Sprintf("[%s](%s)", line, rel.ReleaseURL)
+		}
+		// Leave room for the count of the rest
+		if utf8.RuneCountInString(description.String())+utf8.RuneCountInString(line)+40 > maxDiscordDescription {
+			fmt.Fprintf(&description, "and %d more", len(releases)-i)
+			break
+		}
+		description.WriteString("• " + line + "\n")
+	}
+	return DiscordEmbed{
+		Title:       fmt.Sprintf("%d more component(s)", len(releases)),
+		Description: strings.TrimSuffix(description.String(), "\n"),
+		Color:       discordColorPatch,
+	}
+}
+
+// discordColor picks the embed color for a bump type.
+func discordColor(bumpType string) int {
+	switch bumpType {
+	case "major":
+		return discordColorMajor
+	case "minor":
+		return discordColorMinor
+	default:
+		return discordColorPatch
+	}
+}
+
+// discordTarget builds a target that posts to a Discord webhook.
+func discordTarget(hook *config.ChatWebhook) *target {
+	return &target{
+		kind:   "discord",
+		filter: hook.NotificationFilter,
+		build: func(report *release.ReleaseReport) (*http.Request, error) {
+			payload, err := json.Marshal(BuildDiscordMessage(report))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal payload: %w", err)
+			}
+			return newJSONRequest(os.ExpandEnv(hook.WebhookURL), payload)
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestBuildDiscordMessage(t *testing.T) {
+	report := testReport()
+	report.Releases[0].ReleaseURL = "https://github.com/test/repo/releases/tag/service-a-v1.1.0"
+	report.Releases[0].Commits = []release.CommitInfo{{Type: "feat"}, {Type: "fix", Breaking: true}}
+
+	msg := BuildDiscordMessage(report)
+
+	if len(msg.Embeds) != 2 {
+		t.Fatalf("expected 2 embeds, got %d", len(msg.Embeds))
+	}
+	if !strings.Contains(msg.Content, "service-a, service-b") {
+		t.Errorf("expected content to list components, got %q", msg.Content)
+	}
+
+	embed := msg.Embeds[0]
+	if embed.Title != "service-a 1.1.0" {
+		t.Errorf("unexpected title: %s", embed.Title)
+	}
+	if embed.URL != report.Releases[0].ReleaseURL {
+		t.Errorf("unexpected url: %s", embed.URL)
+	}
+	if embed.Color != discordColorMinor {
+		t.Errorf("expected minor color, got %d", embed.Color)
+	}
+	if !strings.Contains(embed.Description, "⚠ 1 breaking") || !strings.Contains(embed.Description, "1 feature(s)") {
+		t.Errorf("expected highlights, got %q", embed.Description)
+	}
+	if msg.Embeds[1].Color != discordColorPatch {
+		t.Errorf("expected patch color, got %d", msg.Embeds[1].Color)
+	}
+}
+
+func TestBuildDiscordMessage_Overflow(t *testing.T) {
+	report := &release.ReleaseReport{}
+	for i := 0; i < 12; i++ {
+		component := fmt.Sprintf("service-%02d", i)
+		report.Releases = append(report.Releases, release.ComponentRelease{Component: component, NewVersion: "1.0.1", BumpType: "patch"})
+		report.Components = append(report.Components, component)
+	}
+
+	msg := BuildDiscordMessage(report)
+	if len(msg.Embeds) != maxDiscordEmbeds {
+		t.Fatalf("expected %d embeds, got %d", maxDiscordEmbeds, len(msg.Embeds))
+	}
+	last := msg.Embeds[maxDiscordEmbeds-1]
+	if last.Title != "3 more component(s)" || !strings.Contains(last.Description, "• service-09 1.0.1\n• service-10 1.0.1\n• service-11 1.0.1") {
+		t.Errorf("expected the rest listed in the last embed, got %q: %q", last.Title, last.Description)
+	}
+
+	// A long tail is cut to fit the description
+	for i := 12; i < 500; i++ {
+		report.Releases = append(report.Releases, release.ComponentRelease{Component: fmt.Sprintf("service-%03d", i), NewVersion: "1.0.1", BumpType: "patch"})
+	}
+	last = BuildDiscordMessage(report).Embeds[maxDiscordEmbeds-1]
+	if n := utf8.RuneCountInString(last.Description); n > maxDiscordDescription || !strings.HasSuffix(last.Description, "more") {
+		t.Errorf("expected a description within the limit ending with the count, got %d characters", n)
+	}
+}
+
+func TestBuildDiscordMessage_LongContent(t *testing.T) {
+	report := &release.ReleaseReport{}
+	for i := 0; i < 500; i++ {
+		component := fmt.Sprintf("service-%03d", i)
+		report.Releases = append(report.Releases, release.ComponentRelease{Component: component, NewVersion: "1.0.1", BumpType: "patch"})
+		report.Components = append(report.Components, component)
+	}
+
+	content := BuildDiscordMessage(report).Content
+	if n := utf8.RuneCountInString(content); n > maxDiscordContent || !strings.HasSuffix(content, "more") {
+		t.Errorf("expected content within the limit ending with the count, got %d characters: %q", n, content)
+	}
+	if !strings.HasPrefix(content, "Released 500 component(s): service-000, service-001") {
+		t.Errorf("expected the first components listed, got %q", content)
+	}
+}
+
+func TestSend_Discord(t *testing.T) {
+	var got DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifications := &config.Notifications{
+		Discord: []*config.ChatWebhook{
+			{WebhookURL: server.URL, NotificationFilter: config.NotificationFilter{Components: []string{"service-b"}}},
+		},
+	}
+
+	deliveries, err := Send(notifications, testReport(), nil)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Kind != "discord" {
+		t.Fatalf("expected one discord delivery, got %+v", deliveries)
+	}
+	if len(got.Embeds) != 1 || got.Embeds[0].Title != "service-b 0.2.1" {
+		t.Errorf("expected filtered embed for service-b, got %+v", got.Embeds)
+	}
+}
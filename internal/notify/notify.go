@@ -0,0 +1,260 @@
+// Package notify sends release announcements to external systems after a
+// successful release. Targets are configured in the "notifications" section
+// of release-please-config.json.
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of the payload.
+// Format: "sha256=<hex digest>" (same scheme GitHub uses for its webhooks).
+const SignatureHeader = "X-Release-Damnit-Signature"
+
+// defaultTimeout bounds each notification request.
+const defaultTimeout = 10 * time.Second
+
+// Options configures notification delivery.
+type Options struct {
+	// DryRun if true, build payloads but don't send them.
+	DryRun bool
+
+	// Client is the HTTP client to use. Defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// Delivery records the outcome of a single notification.
+type Delivery struct {
+	// Kind is the target type ("webhook", "slack", "discord", "teams").
+	Kind string
+
+	// Target identifies the destination for display. Only the scheme and host
+	// are kept, since webhook URLs often embed credentials.
+	Target string
+
+	// Components lists the components included in the payload.
+	Components []string
+
+	// StatusCode is the HTTP status returned (0 if not sent).
+	StatusCode int
+}
+
+// target is a single configured notification destination.
+type target struct {
+	kind   string
+	filter config.NotificationFilter
+
+	// build creates the HTTP request for a report already narrowed by filter.
+	build func(report *release.ReleaseReport) (*http.Request, error)
+
+	// check optionally validates a 2xx response body (for APIs that report
+	// errors in the body rather than the status code).
+	check func(body []byte) error
+}
+
+// Send fires every configured notification whose filters match at least one
+// release. Each target receives the report narrowed to the matching releases.
+// Failures on one target don't prevent delivery to the others; all errors are
+// combined into the returned error.
+func Send(notifications *config.Notifications, report *release.ReleaseReport, opts *Options) ([]*Delivery, error) {
+	contracts.RequireNotNil(report, "report")
+
+	if notifications == nil || len(report.Releases) == 0 {
+		return nil, nil
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	var targets []*target
+	for _, hook := range notifications.Webhooks {
+		targets = append(targets, webhookTarget(hook))
+	}
+	for _, slack := range notifications.Slack {
+		targets = append(targets, slackTarget(slack))
+	}
+	for _, discord := range notifications.Discord {
+		targets = append(targets, discordTarget(discord))
+	}
+	for _, teams := range notifications.Teams {
+		targets = append(targets, teamsTarget(teams))
+	}
+
+	var deliveries []*Delivery
+	var errs []error
+
+	for _, t := range targets {
+		filtered := FilterReport(report, t.filter.Components, t.filter.BumpTypes)
+		if len(filtered.Releases) == 0 {
+			continue
+		}
+
+		req, err := t.build(filtered)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.kind, err))
+			continue
+		}
+
+		delivery := &Delivery{
+			Kind:       t.kind,
+			Target:     redactURL(req.URL),
+			Components: filtered.Components,
+		}
+		deliveries = append(deliveries, delivery)
+
+		if opts.DryRun {
+			continue
+		}
+
+		status, err := do(client, req, t.check)
+		delivery.StatusCode = status
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", t.kind, delivery.Target, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return deliveries, errors.Join(errs...)
+	}
+	return deliveries, nil
+}
+
+// FilterReport returns a copy of the report containing only releases that
+// match the given components and bump types. Empty filters match everything.
+func FilterReport(report *release.ReleaseReport, components, bumpTypes []string) *release.ReleaseReport {
+	contracts.RequireNotNil(report, "report")
+
+	filtered := *report
+	filtered.Releases = make([]release.ComponentRelease, 0, len(report.Releases))
+	filtered.Components = make([]string, 0, len(report.Releases))
+	filtered.Summary.TotalReleases = 0
+	filtered.Summary.ByBumpType = release.BumpTypeCounts{}
+
+	for _, rel := range report.Releases {
+		if !matches(components, rel.Component) || !matches(bumpTypes, rel.BumpType) {
+			continue
+		}
+		filtered.Releases = append(filtered.Releases, rel)
+		filtered.Components = append(filtered.Components, rel.Component)
+		filtered.Summary.TotalReleases++
+		switch rel.BumpType {
+		case "major":
+			filtered.Summary.ByBumpType.Major++
+		case "minor":
+			filtered.Summary.ByBumpType.Minor++
+		case "patch":
+			filtered.Summary.ByBumpType.Patch++
+		}
+	}
+
+	return &filtered
+}
+
+// Sign returns the signature header value for a payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookTarget builds a target that POSTs the report JSON as-is.
+func webhookTarget(hook *config.Webhook) *target {
+	return &target{
+		kind:   "webhook",
+		filter: hook.NotificationFilter,
+		build: func(report *release.ReleaseReport) (*http.Request, error) {
+			payload, err := json.Marshal(report)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal payload: %w", err)
+			}
+
+			req, err := newJSONRequest(os.ExpandEnv(hook.URL), payload)
+			if err != nil {
+				return nil, err
+			}
+			for name, value := range hook.Headers {
+				req.Header.Set(name, os.ExpandEnv(value))
+			}
+
+			if hook.SecretEnv != "" {
+				secret := os.Getenv(hook.SecretEnv)
+				if secret == "" {
+					return nil, fmt.Errorf("signing secret env %s is not set", hook.SecretEnv)
+				}
+				req.Header.Set(SignatureHeader, Sign(secret, payload))
+			}
+			return req, nil
+		},
+	}
+}
+
+// newJSONRequest creates a POST request with a JSON body.
+func newJSONRequest(target string, payload []byte) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "release-damnit")
+	return req, nil
+}
+
+// do sends a request and validates the response.
+func do(client *http.Client, req *http.Request, check func(body []byte) error) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if check != nil {
+		if err := check(body); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// matches reports whether value is in allowed, treating an empty list as "all".
+func matches(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL strips everything but the scheme and host from a URL.
+func redactURL(u *url.URL) string {
+	if u == nil || u.Host == "" {
+		return "(unknown)"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// testReport returns a report with one minor and one patch release.
+func testReport() *release.ReleaseReport {
+	return &release.ReleaseReport{
+		SchemaVersion: release.ReportSchemaVersion,
+		Releases: []release.ComponentRelease{
+			{Component: "service-a", NewVersion: "1.1.0", BumpType: "minor", TagName: "service-a-v1.1.0"},
+			{Component: "service-b", NewVersion: "0.2.1", BumpType: "patch", TagName: "service-b-v0.2.1"},
+		},
+		Components: []string{"service-a", "service-b"},
+		Summary: release.ReleaseSummary{
+			TotalReleases: 2,
+			ByBumpType:    release.BumpTypeCounts{Minor: 1, Patch: 1},
+		},
+	}
+}
+
+func TestFilterReport_NoFilters(t *testing.T) {
+	filtered := FilterReport(testReport(), nil, nil)
+
+	if len(filtered.Releases) != 2 {
+		t.Errorf("expected 2 releases, got %d", len(filtered.Releases))
+	}
+	if filtered.Summary.TotalReleases != 2 {
+		t.Errorf("expected total 2, got %d", filtered.Summary.TotalReleases)
+	}
+}
+
+func TestFilterReport_ByComponentAndBumpType(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []string
+		bumpTypes  []string
+		want       []string
+	}{
+		{"component", []string{"service-b"}, nil, []string{"service-b"}},
+		{"bump type", nil, []string{"minor", "major"}, []string{"service-a"}},
+		{"both", []string{"service-a"}, []string{"patch"}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := FilterReport(testReport(), tc.components, tc.bumpTypes)
+			if len(filtered.Components) != len(tc.want) {
+				t.Fatalf("expected components %v, got %v", tc.want, filtered.Components)
+			}
+			for i, c := range tc.want {
+				if filtered.Components[i] != c {
+					t.Errorf("expected components %v, got %v", tc.want, filtered.Components)
+				}
+			}
+			if filtered.Summary.TotalReleases != len(tc.want) {
+				t.Errorf("expected total %d, got %d", len(tc.want), filtered.Summary.TotalReleases)
+			}
+		})
+	}
+}
+
+func TestSend_PostsSignedReport(t *testing.T) {
+	var gotBody []byte
+	var gotSig, gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(SignatureHeader)
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cret")
+	t.Setenv("TEST_WEBHOOK_TOKEN", "tok")
+
+	notifications := &config.Notifications{
+		Webhooks: []*config.Webhook{
+			{
+				URL:                server.URL,
+				Headers:            map[string]string{"Authorization": "Bearer ${TEST_WEBHOOK_TOKEN}"},
+				SecretEnv:          "TEST_WEBHOOK_SECRET",
+				NotificationFilter: config.NotificationFilter{Components: []string{"service-a"}},
+			},
+		},
+	}
+
+	deliveries, err := Send(notifications, testReport(), nil)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("expected one successful delivery, got %+v", deliveries)
+	}
+	if gotAuth != "Bearer tok" {
+		t.Errorf("expected expanded Authorization header, got %q", gotAuth)
+	}
+	if gotSig != Sign("s3cret", gotBody) {
+		t.Errorf("signature mismatch: got %q", gotSig)
+	}
+
+	var payload release.ReleaseReport
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("payload is not a release report: %v", err)
+	}
+	if len(payload.Components) != 1 || payload.Components[0] != "service-a" {
+		t.Errorf("expected payload filtered to service-a, got %v", payload.Components)
+	}
+}
+
+func TestSend_SkipsUnmatchedAndReportsErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifications := &config.Notifications{
+		Webhooks: []*config.Webhook{
+			{URL: server.URL, NotificationFilter: config.NotificationFilter{BumpTypes: []string{"major"}}}, // no major releases
+			{URL: server.URL},
+		},
+	}
+
+	deliveries, err := Send(notifications, testReport(), nil)
+	if err == nil {
+		t.Fatal("expected error for 500 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected one failed delivery, got %+v", deliveries)
+	}
+}
+
+func TestSend_DryRun(t *testing.T) {
+	notifications := &config.Notifications{
+		Webhooks: []*config.Webhook{{URL: "https://hooks.example.com/secret/path"}},
+	}
+
+	deliveries, err := Send(notifications, testReport(), &Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(deliveries))
+	}
+	if deliveries[0].Target != "https://hooks.example.com" {
+		t.Errorf("expected redacted target, got %s", deliveries[0].Target)
+	}
+	if deliveries[0].StatusCode != 0 {
+		t.Errorf("expected no request in dry run, got status %d", deliveries[0].StatusCode)
+	}
+}
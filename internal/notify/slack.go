@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// slackPostMessageURL is the Slack Web API endpoint used with bot tokens.
+// It's a variable so tests can point it at a local server.
+var slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// maxSlackBlocks and maxSlackSectionText are the most blocks Slack accepts
+// in one message and the longest section text, in characters.
+const (
+	maxSlackBlocks      = 50
+	maxSlackSectionText = 3000
+)
+
+// SlackMessage is a Slack message payload using Block Kit.
+type SlackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"`
+	Blocks  []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is a single Block Kit block.
+type SlackBlock struct {
+	Type string     `json:"type"`
+	Text *SlackText `json:"text,omitempty"`
+}
+
+// SlackText is a Block Kit text object.
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// BuildSlackMessage renders a Block Kit announcement for the released
+// components. Past Slack's block limit, the last section lists the
+// remaining components instead.
+func BuildSlackMessage(report *release.ReleaseReport) *SlackMessage {
+	title := fmt.Sprintf("Released %d component(s)", len(report.Releases))
+
+	msg := &SlackMessage{
+		// Text is the fallback shown in notifications and by clients without Block Kit
+		Text: fmt.Sprintf("%s: %s", title, strings.Join(report.Components, ", ")),
+		Blocks: []SlackBlock{
+			{Type: "header", Text: &SlackText{Type: "plain_text", Text: title}},
+		},
+	}
+
+	for i, rel := range report.Releases {
+		// The header takes one block
+		if i == maxSlackBlocks-2 && len(report.Releases) > maxSlackBlocks-1 {
+			msg.Blocks = append(msg.Blocks, slackOverflowBlock(report.Releases[i:]))
+			break
+		}
+		name := fmt.Sprintf("*%s* %s", rel.Component, rel.NewVersion)
+		if rel.ReleaseURL != "" {
+			name = fmt.Sprintf("*<%s|%s %s>*", rel.ReleaseURL, rel.Component, rel.NewVersion)
+		}
+
+		line := fmt.Sprintf("%s (%s bump from %s)", name, rel.BumpType, rel.OldVersion)
+		if highlights := highlightSummary(rel.Commits, ":warning:"); highlights != "" {
+			line += "\n" + highlights
+		}
+
+		msg.Blocks = append(msg.Blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: line},
+		})
+	}
+
+	return msg
+}
+
+// slackOverflowBlock summarizes the releases that don't get a section of
+// their own, one line each, as many as fit in the section.
+func slackOverflowBlock(releases []release.ComponentRelease) SlackBlock {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%d more component(s)*\n", len(releases))
+	for i, rel := range releases {
+		line := fmt.Sprintf("%s %s", rel.Component, rel.NewVersion)
+		if rel.ReleaseURL != "" {
+			line = fmt.Sprintf("<%s|%s>", rel.ReleaseURL, line)
+		}
+		// Leave room for the count of the rest
+		if utf8.RuneCountInString(text.String())+utf8.RuneCountInString(line)+40 > maxSlackSectionText {
+			fmt.Fprintf(&text, "…and %d more", len(releases)-i)
+			break
+		}
+		text.WriteString("• " + line + "\n")
+	}
+	return SlackBlock{
+		Type: "section",
+		Text: &SlackText{Type: "mrkdwn", Text: strings.TrimSuffix(text.String(), "\n")},
+	}
+}
+
+// highlightSummary counts features, fixes, and breaking changes, marking
+// breaking changes with the provider's warning emoji. Returns "" if there's
+// nothing notable (e.g., a linked bump).
+func highlightSummary(commits []release.CommitInfo, warning string) string {
+	var features, fixes, breaking int
+	for _, c := range commits {
+		switch c.Type {
+		case "feat":
+			features++
+		case "fix":
+			fixes++
+		}
+		if c.Breaking {
+			breaking++
+		}
+	}
+
+	var parts []string
+	if breaking > 0 {
+		parts = append(parts, fmt.Sprintf("%s %d breaking", warning, breaking))
+	}
+	if features > 0 {
+		parts = append(parts, fmt.Sprintf("%d feature(s)", features))
+	}
+	if fixes > 0 {
+		parts = append(parts, fmt.Sprintf("%d fix(es)", fixes))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// slackTarget builds a target that posts via an incoming webhook or the Web API.
+func slackTarget(slack *config.Slack) *target {
+	t := &target{
+		kind:   "slack",
+		filter: slack.NotificationFilter,
+	}
+
+	t.build = func(report *release.ReleaseReport) (*http.Request, error) {
+		msg := BuildSlackMessage(report)
+
+		endpoint := os.ExpandEnv(slack.WebhookURL)
+		var token string
+		if slack.WebhookURL == "" {
+			token = os.Getenv(slack.TokenEnv)
+			if token == "" {
+				return nil, fmt.Errorf("token env %s is not set", slack.TokenEnv)
+			}
+			endpoint = slackPostMessageURL
+			msg.Channel = slack.Channel
+		}
+
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		req, err := newJSONRequest(endpoint, payload)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	}
+
+	// The Web API returns 200 with {"ok": false} on failure
+	if slack.WebhookURL == "" {
+		t.check = func(body []byte) error {
+			var resp struct {
+				OK    bool   `json:"ok"`
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return fmt.Errorf("invalid Slack API response: %w", err)
+			}
+			if !resp.OK {
+				return fmt.Errorf("slack API error: %s", resp.Error)
+			}
+			return nil
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestBuildSlackMessage(t *testing.T) {
+	report := testReport()
+	report.Releases[0].OldVersion = "1.0.0"
+	report.Releases[0].ReleaseURL = "https://github.com/test/repo/releases/tag/service-a-v1.1.0"
+	report.Releases[0].Commits = []release.CommitInfo{
+		{Type: "feat", Description: "add thing"},
+		{Type: "feat", Description: "add other thing"},
+		{Type: "fix", Description: "fix thing", Breaking: true},
+	}
+
+	msg := BuildSlackMessage(report)
+
+	if len(msg.Blocks) != 3 {
+		t.Fatalf("expected header + 2 sections, got %d blocks", len(msg.Blocks))
+	}
+	if msg.Blocks[0].Type != "header" {
+		t.Errorf("expected header block first, got %s", msg.Blocks[0].Type)
+	}
+	if !strings.Contains(msg.Text, "service-a, service-b") {
+		t.Errorf("expected fallback text to list components, got %q", msg.Text)
+	}
+
+	section := msg.Blocks[1].Text.Text
+	if !strings.Contains(section, "<https://github.com/test/repo/releases/tag/service-a-v1.1.0|service-a 1.1.0>") {
+		t.Errorf("expected release link, got %q", section)
+	}
+	if !strings.Contains(section, ":warning: 1 breaking") || !strings.Contains(section, "2 feature(s)") || !strings.Contains(section, "1 fix(es)") {
+		t.Errorf("expected highlight counts, got %q", section)
+	}
+
+	// service-b has no commits and no URL
+	if strings.Contains(msg.Blocks[2].Text.Text, "<") {
+		t.Errorf("expected no link without release URL, got %q", msg.Blocks[2].Text.Text)
+	}
+}
+
+func TestBuildSlackMessage_Overflow(t *testing.T) {
+	report := testReport()
+	for i := 3; i <= 52; i++ {
+		component := fmt.Sprintf("service-%02d", i)
+		report.Releases = append(report.Releases, release.ComponentRelease{Component: component, NewVersion: "1.0.1", BumpType: "patch"})
+		report.Components = append(report.Components, component)
+	}
+
+	msg := BuildSlackMessage(report)
+	if len(msg.Blocks) != maxSlackBlocks {
+		t.Fatalf("expected %d blocks, got %d", maxSlackBlocks, len(msg.Blocks))
+	}
+	last := msg.Blocks[maxSlackBlocks-1].Text.Text
+	if !strings.HasPrefix(last, "*4 more component(s)*") || !strings.Contains(last, "• service-49 1.0.1\n• service-50 1.0.1\n• service-51 1.0.1\n• service-52 1.0.1") {
+		t.Errorf("expected the rest listed in the last section, got %q", last)
+	}
+
+	// A long tail is cut to fit the section
+	for i := 53; i < 1000; i++ {
+		report.Releases = append(report.Releases, release.ComponentRelease{Component: fmt.Sprintf("service-%03d", i), NewVersion: "1.0.1", BumpType: "patch"})
+	}
+	last = BuildSlackMessage(report).Blocks[maxSlackBlocks-1].Text.Text
+	if n := utf8.RuneCountInString(last); n > maxSlackSectionText || !strings.HasSuffix(last, "more") {
+		t.Errorf("expected a section within the limit ending with the count, got %d characters", n)
+	}
+}
+
+func TestSend_SlackWebhook(t *testing.T) {
+	var got SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	notifications := &config.Notifications{
+		Slack: []*config.Slack{{WebhookURL: server.URL}},
+	}
+
+	if _, err := Send(notifications, testReport(), nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got.Channel != "" {
+		t.Errorf("expected no channel for webhook delivery, got %q", got.Channel)
+	}
+	if len(got.Blocks) != 3 {
+		t.Errorf("expected 3 blocks, got %d", len(got.Blocks))
+	}
+}
+
+func TestSend_SlackBotToken(t *testing.T) {
+	var got SlackMessage
+	var gotAuth string
+	ok := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		if ok {
+			w.Write([]byte(`{"ok": true}`))
+		} else {
+			w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+		}
+	}))
+	defer server.Close()
+
+	orig := slackPostMessageURL
+	slackPostMessageURL = server.URL
+	defer func() { slackPostMessageURL = orig }()
+
+	t.Setenv("TEST_SLACK_TOKEN", "xoxb-test")
+
+	notifications := &config.Notifications{
+		Slack: []*config.Slack{{TokenEnv: "TEST_SLACK_TOKEN", Channel: "#releases"}},
+	}
+
+	if _, err := Send(notifications, testReport(), nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if gotAuth != "Bearer xoxb-test" {
+		t.Errorf("expected bearer token, got %q", gotAuth)
+	}
+	if got.Channel != "#releases" {
+		t.Errorf("expected channel #releases, got %q", got.Channel)
+	}
+
+	// API-level failure is reported even with HTTP 200
+	ok = false
+	_, err := Send(notifications, testReport(), nil)
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("expected channel_not_found error, got %v", err)
+	}
+}
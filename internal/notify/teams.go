@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// maxTeamsActions is the most release buttons a card gets; Teams renders
+// only a handful of actions.
+const maxTeamsActions = 5
+
+// TeamsMessage is a Microsoft Teams webhook payload wrapping an Adaptive Card.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment carries a single card.
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is a minimal Adaptive Card (schema 1.4).
+type AdaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	Actions []map[string]interface{} `json:"actions,omitempty"`
+}
+
+// BuildTeamsMessage renders an Adaptive Card listing released components.
+// The first releases get a button; the rest link from their fact.
+func BuildTeamsMessage(report *release.ReleaseReport) *TeamsMessage {
+	card := AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]interface{}{
+			{
+				"type":   "TextBlock",
+				"size":   "Large",
+				"weight": "Bolder",
+				"text":   fmt.Sprintf("Released %d component(s)", len(report.Releases)),
+			},
+		},
+	}
+
+	var facts []map[string]interface{}
+	for _, rel := range report.Releases {
+		version := rel.NewVersion
+		if rel.ReleaseURL != "" && len(card.Actions) == maxTeamsActions {
+			version = fmt.Sprintf("[%s](%s)", version, rel.ReleaseURL)
+		}
+		value := fmt.Sprintf("%s (%s from %s)", version, rel.BumpType, rel.OldVersion)
+		if highlights := highlightSummary(rel.Commits, "⚠"); highlights != "" {
+			value += " — " + highlights
+		}
+		facts = append(facts, map[string]interface{}{"title": rel.Component, "value": value})
+
+		if rel.ReleaseURL != "" && len(card.Actions) < maxTeamsActions {
+			card.Actions = append(card.Actions, map[string]interface{}{
+				"type":  "Action.OpenUrl",
+				"title": fmt.Sprintf("%s %s", rel.Component, rel.NewVersion),
+				"url":   rel.ReleaseURL,
+			})
+		}
+	}
+	card.Body = append(card.Body, map[string]interface{}{"type": "FactSet", "facts": facts})
+
+	return &TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+}
+
+// teamsTarget builds a target that posts to a Teams incoming webhook.
+func teamsTarget(hook *config.ChatWebhook) *target {
+	return &target{
+		kind:   "teams",
+		filter: hook.NotificationFilter,
+		build: func(report *release.ReleaseReport) (*http.Request, error) {
+			payload, err := json.Marshal(BuildTeamsMessage(report))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal payload: %w", err)
+			}
+			return newJSONRequest(os.ExpandEnv(hook.WebhookURL), payload)
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+func TestBuildTeamsMessage(t *testing.T) {
+	report := testReport()
+	report.Releases[0].ReleaseURL = "https://github.com/test/repo/releases/tag/service-a-v1.1.0"
+
+	msg := BuildTeamsMessage(report)
+
+	if msg.Type != "message" || len(msg.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %+v", msg)
+	}
+	att := msg.Attachments[0]
+	if att.ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("unexpected content type: %s", att.ContentType)
+	}
+
+	card := att.Content
+	if card.Type != "AdaptiveCard" {
+		t.Errorf("unexpected card type: %s", card.Type)
+	}
+	if len(card.Body) != 2 {
+		t.Fatalf("expected title + fact set, got %d body elements", len(card.Body))
+	}
+	facts := card.Body[1]["facts"].([]map[string]interface{})
+	if len(facts) != 2 || facts[0]["title"] != "service-a" {
+		t.Errorf("unexpected facts: %+v", facts)
+	}
+
+	// Only releases with URLs get buttons
+	if len(card.Actions) != 1 || card.Actions[0]["url"] != report.Releases[0].ReleaseURL {
+		t.Errorf("unexpected actions: %+v", card.Actions)
+	}
+}
+
+func TestBuildTeamsMessage_ManyReleases(t *testing.T) {
+	report := &release.ReleaseReport{}
+	for i := 1; i <= maxTeamsActions+2; i++ {
+		component := fmt.Sprintf("service-%d", i)
+		report.Releases = append(report.Releases, release.ComponentRelease{
+			Component:  component,
+			NewVersion: "1.0.1",
+			ReleaseURL: "https://github.com/test/repo/releases/tag/" + component + "-v1.0.1",
+		})
+	}
+
+	card := BuildTeamsMessage(report).Attachments[0].Content
+	if len(card.Actions) != maxTeamsActions {
+		t.Errorf("expected %d buttons, got %d", maxTeamsActions, len(card.Actions))
+	}
+	// Releases past the buttons link from their fact instead
+	facts := card.Body[1]["facts"].([]map[string]interface{})
+	if value := facts[0]["value"].(string); strings.Contains(value, "](") {
+		t.Errorf("expected no link in a fact with a button, got %q", value)
+	}
+	if value := facts[maxTeamsActions]["value"].(string); !strings.HasPrefix(value, "[1.0.1](https://github.com/test/repo/releases/tag/service-6-v1.0.1)") {
+		t.Errorf("expected the release linked from its fact, got %q", value)
+	}
+}
+
+func TestSend_Teams(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifications := &config.Notifications{
+		Teams: []*config.ChatWebhook{{WebhookURL: server.URL}},
+	}
+
+	deliveries, err := Send(notifications, testReport(), nil)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Kind != "teams" {
+		t.Fatalf("expected one teams delivery, got %+v", deliveries)
+	}
+	if got["type"] != "message" {
+		t.Errorf("expected message payload, got %+v", got)
+	}
+}
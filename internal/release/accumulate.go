@@ -0,0 +1,123 @@
+package release
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// accumulateCommits returns the commits since the last release tag of any
+// package, oldest first, for Options.Accumulate. since maps each tagged
+// package's path to the SHAs of the commits since its own tag; untagged
+// packages aren't in it, as every commit is theirs.
+//
+// Commits are streamed through the package matcher as git lists them, and
+// those every touched package has already released are counted in released
+// but not kept, so catching up over thousands of commits holds only the
+// unreleased ones. Their files are read through cache, which may be nil.
+func accumulateCommits(repoPath string, cfg *config.Config, cache *git.Cache) (commits []*git.Commit, since map[string]map[string]bool, released int, err error) {
+	tags := make(map[string]string) // package path -> last release tag
+	var distinct []string
+	untagged := false
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		tag, err := lastReleaseTag(repoPath, pkg)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		tags[pkg.Path] = tag
+		switch {
+		case tag == "":
+			untagged = true
+		case !slices.Contains(distinct, tag):
+			distinct = append(distinct, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, nil, 0, nil
+	}
+
+	shasByTag := make(map[string]map[string]bool)
+	for _, tag := range distinct {
+		shas, err := git.ListSHAsSinceTag(repoPath, tag, "HEAD")
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		shasByTag[tag] = make(map[string]bool, len(shas))
+		for _, sha := range shas {
+			shasByTag[tag][sha] = true
+		}
+	}
+	since = make(map[string]map[string]bool)
+	for path, tag := range tags {
+		if tag != "" {
+			since[path] = shasByTag[tag]
+		}
+	}
+
+	// One listing covers every package: the commits since the newest common
+	// ancestor of their tags
+	var base string
+	if !untagged {
+		if base, err = git.MergeBase(repoPath, distinct...); err != nil {
+			// Tags with unrelated histories; list everything
+			slog.Debug("release tags have no common ancestor, listing every commit", "error", err)
+			base = ""
+		}
+	}
+	err = cache.EachCommitSinceTag(repoPath, base, "HEAD", func(commit *git.Commit) error {
+		if isReleased(cfg, since, commit) {
+			released++
+			return nil
+		}
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return commits, since, released, nil
+}
+
+// isReleased reports whether every package a commit touches has released
+// it, so it can't add to any release (see keepSinceTag). Commits touching
+// files outside every package aren't, as they're reported as orphaned.
+func isReleased(cfg *config.Config, since map[string]map[string]bool, commit *git.Commit) bool {
+	if len(commit.Files) == 0 {
+		return false
+	}
+	for _, file := range commit.Files {
+		pkg := cfg.FindPackageForPath(file)
+		if pkg == nil {
+			return false
+		}
+		shas, ok := since[pkg.Path]
+		if !ok || shas[commit.SHA] {
+			return false
+		}
+	}
+	return true
+}
+
+// keepSinceTag drops each package's commits that came before its last
+// release tag (see accumulateCommits).
+func keepSinceTag(packageCommits map[string][]*git.Commit, since map[string]map[string]bool) {
+	for path, commits := range packageCommits {
+		shas, ok := since[path]
+		if !ok {
+			continue
+		}
+		var kept []*git.Commit
+		for _, commit := range commits {
+			if shas[commit.SHA] {
+				kept = append(kept, commit)
+			}
+		}
+		if len(kept) == 0 {
+			delete(packageCommits, path)
+			continue
+		}
+		packageCommits[path] = kept
+	}
+}
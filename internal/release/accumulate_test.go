@@ -0,0 +1,117 @@
+package release
+
+import (
+	"testing"
+)
+
+func TestAnalyze_Accumulate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0", "HEAD~1")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.0", "HEAD~1")
+
+	// service-b released the fix since
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-b": "0.1.1"
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: release service-b 0.1.1")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.1")
+
+	mergeChange(t, dir, "feature/a", "// Feature\n", "feat(service-a): add feature")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Later fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): later fix")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].Package.Component != "service-b" {
+		t.Fatalf("expected only HEAD's service-b fix without --accumulate, got %+v", result.Releases)
+	}
+
+	result, err = Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, Accumulate: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if !result.Accumulated {
+		t.Error("expected the result marked accumulated")
+	}
+	if len(result.Releases) != 2 {
+		t.Fatalf("expected releases of both services, got %+v", result.Releases)
+	}
+
+	a, b := result.Releases[0], result.Releases[1]
+	if a.NewVersion != "0.1.1" || len(a.Commits) != 2 {
+		t.Errorf("expected service-a 0.1.1 with the fix and the feature, got %s with %d commit(s)", a.NewVersion, len(a.Commits))
+	}
+	if b.NewVersion != "0.1.2" || len(b.Commits) != 1 || b.Commits[0].Description != "later fix" {
+		t.Errorf("expected service-b 0.1.2 with only the fix since its tag, got %s with %+v", b.NewVersion, b.Commits)
+	}
+}
+
+func TestAnalyze_AccumulateUntagged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	mergeChange(t, dir, "feature/a", "// One\n", "fix(service-a): one")
+	mergeChange(t, dir, "feature/b", "// Two\n", "fix(service-a): two")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, Accumulate: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || len(result.Releases[0].Commits) != 2 {
+		t.Fatalf("expected one release with both fixes, got %+v", result.Releases)
+	}
+}
+
+func TestAnalyze_AccumulateSkipsReleased(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0", "HEAD~1")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.0", "HEAD~1")
+
+	// A service-b fix released on its own
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Released fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): released fix")
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-b": "0.1.1"
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: release service-b 0.1.1")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.1")
+
+	mergeChange(t, dir, "feature/a", "// Feature\n", "feat(service-a): add feature")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, Accumulate: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Stats.ReleasedCommits != 1 {
+		t.Errorf("expected 1 released commit passed over, got %d", result.Stats.ReleasedCommits)
+	}
+	for _, c := range result.Commits {
+		if c.Description == "released fix" {
+			t.Errorf("expected the released service-b fix not to be kept")
+		}
+	}
+	if len(result.Releases) != 1 || result.Releases[0].Package.Component != "service-a" {
+		t.Fatalf("expected only a service-a release, got %+v", result.Releases)
+	}
+	if n := len(result.Releases[0].Commits); n != 2 {
+		t.Errorf("expected service-a's fix and feature, got %d commit(s)", n)
+	}
+}
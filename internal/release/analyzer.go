@@ -4,9 +4,11 @@ package release
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/dsswift/release-damnit/internal/changelog"
@@ -14,16 +16,35 @@ import (
 	"github.com/dsswift/release-damnit/internal/git"
 	"github.com/dsswift/release-damnit/internal/version"
 	"github.com/dsswift/release-damnit/pkg/contracts"
+	"github.com/dsswift/release-damnit/pkg/extension"
 )
 
 // PackageRelease represents the release information for a single package.
 type PackageRelease struct {
-	Package     *config.Package
-	BumpType    version.BumpType
-	OldVersion  string
-	NewVersion  string
-	Commits     []*git.Commit
-	SkipReason  string // Set if this package is being skipped (e.g., linked to another)
+	Package         *config.Package
+	BumpType        version.BumpType
+	OldVersion      string
+	NewVersion      string
+	Commits         []*git.Commit
+	Build           string   // Build metadata for the version file (see BuildVersion)
+	ReconciledFrom  string   // Set if the package drifted behind its linked group: the group's version it was bumped from
+	FirstRelease    bool     // Set if the package was never released before (its version was 0.0.0)
+	DependencyChain []string // Set if released because a dependency was: the released component, then dependents in between
+	SkipReason      string   // Set if this package is being skipped (e.g., linked to another)
+	Verified        *bool    // Set once the GitHub release is created: whether the API shows it
+	ReleaseSHA      string   // Set once ApplyAndCommit commits the release: the commit holding its version bump
+}
+
+// TargetSHA returns the commit a release is tagged at: its release commit
+// once ApplyAndCommit made one, or else HEAD as analyzed.
+func (r *AnalysisResult) TargetSHA(rel *PackageRelease) string {
+	if rel.ReleaseSHA != "" {
+		return rel.ReleaseSHA
+	}
+	if r.MergeInfo == nil {
+		return ""
+	}
+	return r.MergeInfo.HeadSHA
 }
 
 // AnalysisStats tracks diagnostic statistics about the analysis.
@@ -39,6 +60,46 @@ type AnalysisStats struct {
 
 	// OrphanedDirs is a list of unique directories with changes but no package config.
 	OrphanedDirs []string
+
+	// PRTitleCommits is the number of commits parsed from their pull
+	// request's title or labels (only with Options.PRTitleFallback).
+	PRTitleCommits int
+
+	// ReleaseCommits is the number of release commits (matching the config's
+	// release-commit-pattern) left out of the analysis.
+	ReleaseCommits int
+
+	// IgnoredCommits is the number of commits left out of the analysis
+	// because they only touch files matching the config's ignore-files.
+	IgnoredCommits int
+
+	// BinaryCommits is the number of commits left out of the analysis
+	// because they only touch binary or Git LFS files (only with the
+	// config's ignore-binary-files).
+	BinaryCommits int
+
+	// CherryPicked lists commits skipped because a patch-equivalent commit
+	// was already released (only with Options.CherryPickDedup).
+	CherryPicked []*git.Commit
+
+	// Deferred lists packages not released because fewer than their
+	// min-commits releasable commits are pending.
+	Deferred []*DeferredRelease
+
+	// TagCollisions lists releases whose tag already existed at another
+	// commit, skipped or bumped again per the config's tag-collision.
+	TagCollisions []*TagCollision
+
+	// HugeMergeFiles is the number of files a merge changed when it was
+	// too many to list per commit, or 0. Commits were then mapped to
+	// packages by pathspec: their Files are the paths of the packages they
+	// touch, and OrphanedDirs and IgnoredCommits aren't counted.
+	HugeMergeFiles int
+
+	// ReleasedCommits counts the commits an accumulated analysis passed
+	// over because every package they touch had already released them.
+	// They aren't in TotalCommits.
+	ReleasedCommits int
 }
 
 // AnalysisResult contains the result of analyzing commits for releases.
@@ -58,10 +119,66 @@ type AnalysisResult struct {
 	// RepoURL is the GitHub repository URL (for changelog links).
 	RepoURL string
 
+	// Branch is the branch being released, or "" if HEAD is detached.
+	Branch string
+
+	// Bare reports that the repository is bare, so its config and manifest
+	// were read from HEAD's tree and nothing can be planned or applied (see
+	// ErrBareRepository).
+	Bare bool
+
+	// Remote is the GitHub repository (owner/repo) of a remote analysis
+	// (see AnalyzeRemote), or "". Like a bare repository's, it can't be
+	// planned or applied (see ErrRemoteAnalysis).
+	Remote string
+
+	// ReleaseDate is the date changelog entries are written with. Zero
+	// means today (see Date).
+	ReleaseDate time.Time
+
+	// Timings records how long each phase took, or is nil unless
+	// Options.Timings is set or the config has telemetry. Callers add later
+	// phases (apply, release creation) to it.
+	Timings *Timings
+
 	// Stats contains diagnostic statistics about the analysis.
 	Stats *AnalysisStats
+
+	// Base is the last commit before the analyzed ones: HEAD's first parent.
+	// It's empty for a root commit and with Accumulated.
+	Base string
+
+	// Accumulated reports whether Commits are those since each package's
+	// last release tag (Options.Accumulate) rather than those HEAD brought in.
+	Accumulated bool
+
+	// Freeze is the release freeze in effect during the analysis, or nil.
+	// Releases are still analyzed, but not applied (see CheckFreeze).
+	Freeze *config.Freeze
+
+	// FreezeOverridden reports whether Options.OverrideFreeze lifted Freeze.
+	FreezeOverridden bool
+}
+
+// Date returns the date changelog entries are written with: ReleaseDate, or
+// the current time if it's zero.
+func (r *AnalysisResult) Date() time.Time {
+	if r.ReleaseDate.IsZero() {
+		return time.Now()
+	}
+	return r.ReleaseDate
 }
 
+// ConfigError is returned by Analyze when the Release Please configuration or
+// manifest can't be loaded or is invalid, as opposed to git or I/O failures.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
 // Options configures the release analysis.
 type Options struct {
 	// RepoPath is the path to the git repository root.
@@ -71,10 +188,68 @@ type Options struct {
 	DryRun bool
 
 	// RepoURL is the GitHub repository URL (e.g., "https://github.com/owner/repo").
+	// When empty, remotes.canonical-url is used, then the URL of Remote.
 	RepoURL string
 
+	// Remote names the git remote RepoURL is detected from. Empty disables
+	// detection.
+	Remote string
+
 	// TreatPreMajorAsMinor if true, feat bumps patch for 0.x versions.
 	TreatPreMajorAsMinor bool
+
+	// ConfigFile and ManifestFile override the config and manifest
+	// locations. Relative paths are resolved against RepoPath.
+	ConfigFile   string
+	ManifestFile string
+
+	// WorkDir is where config discovery starts when ConfigFile is empty.
+	// Empty means RepoPath.
+	WorkDir string
+
+	// Branch names the branch being released, for matching the config's
+	// branches section. Empty means the checked-out branch, or with HEAD
+	// detached, the branch CI checked out or whose tip HEAD is.
+	Branch string
+
+	// PRTitleFallback if true, parses non-conventional commits from the
+	// title and labels of their GitHub pull request (requires gh CLI).
+	PRTitleFallback bool
+
+	// CherryPickDedup if true, skips commits that are patch-equivalent to a
+	// commit already released under another tag (e.g., a cherry-picked hotfix).
+	CherryPickDedup bool
+
+	// Clock returns the current time: the release date for changelog
+	// entries, and the time the config's freeze-windows are checked at. Nil
+	// means time.Now; set it for reproducible output.
+	Clock func() time.Time
+
+	// Timings if true, records how long each phase of the analysis takes
+	// in AnalysisResult.Timings.
+	Timings bool
+
+	// Accumulate if true, analyzes every commit since each package's last
+	// release tag instead of the commits HEAD brought in, for scheduled
+	// release trains that release once for many merges.
+	Accumulate bool
+
+	// PathScope, if set, restricts analysis to the packages under a path
+	// prefix (e.g., "workloads/jarvis/..."), for per-team release pipelines.
+	// See config.Config.ScopeTo.
+	PathScope string
+
+	// NoCache if true, lists every commit's files from git instead of the
+	// commit cache in the repository's git directory (see git.OpenCache).
+	NoCache bool
+
+	// OverrideFreeze if true, lets releases be applied during a freeze.
+	OverrideFreeze bool
+
+	// BuildMetadata stamps "+<metadata>" on the versions written to version
+	// files: BuildMetadataSHA, BuildMetadataDate, or the metadata itself.
+	// Empty means none.
+	BuildMetadata string
 }
 
 // Analyze analyzes HEAD for releasable changes.
@@ -82,10 +257,76 @@ func Analyze(opts *Options) (*AnalysisResult, error) {
 	contracts.RequireNotNil(opts, "opts")
 	contracts.RequireNotEmpty(opts.RepoPath, "RepoPath")
 
-	// Load config
-	cfg, err := config.Load(opts.RepoPath)
+	start := time.Now()
+
+	// Load config. A bare repository has no working tree, so it's read as
+	// committed at HEAD.
+	bare, err := git.IsBareRepository(opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	loadOpts := &config.LoadOptions{
+		ConfigFile:   opts.ConfigFile,
+		ManifestFile: opts.ManifestFile,
+		SearchDir:    opts.WorkDir,
+	}
+	if bare {
+		loadOpts.FS = git.TreeFS(opts.RepoPath, "HEAD")
+	}
+	cfg, err := config.LoadWithOptions(opts.RepoPath, loadOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, &ConfigError{Err: fmt.Errorf("failed to load config: %w", err)}
+	}
+	if opts.PathScope != "" {
+		if err := cfg.ScopeTo(opts.PathScope); err != nil {
+			return nil, &ConfigError{Err: fmt.Errorf("invalid --path-scope: %w", err)}
+		}
+	}
+	if err := validateExtensions(cfg); err != nil {
+		return nil, &ConfigError{Err: fmt.Errorf("invalid config: %w", err)}
+	}
+	if cfg.VersionSource == config.VersionSourceTags {
+		if err := resolveTagVersions(opts.RepoPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Telemetry exports phase durations, so time them whenever it's on
+	var timings *Timings
+	if opts.Timings || cfg.Telemetry != nil {
+		timings = &Timings{}
+	}
+	timings.Since(PhaseConfigLoad, start)
+	start = time.Now()
+	listingStart := git.FileListingTime()
+
+	// Reruns over the same commits reuse their cached file lists. A nil
+	// cache lists every commit's files from git.
+	var cache *git.Cache
+	if !opts.NoCache {
+		opened, err := git.OpenCache(opts.RepoPath)
+		if err != nil {
+			slog.Warn("commit cache unavailable", "error", err)
+		}
+		cache = opened
+		defer func() {
+			if err := cache.Close(); err != nil {
+				slog.Warn("failed to save commit cache", "error", err)
+			}
+		}()
+	}
+
+	// Find the branch's release rules (e.g., a maintenance branch's max-bump)
+	branchName := opts.Branch
+	if branchName == "" {
+		if branchName, err = currentBranch(opts.RepoPath, os.Getenv); err != nil {
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
+		}
+	}
+	branch := cfg.BranchFor(branchName)
+	var maxBump version.BumpType
+	if branch != nil {
+		maxBump = branch.MaxBump
 	}
 
 	// Analyze HEAD
@@ -94,21 +335,130 @@ func Analyze(opts *Options) (*AnalysisResult, error) {
 		return nil, fmt.Errorf("failed to analyze HEAD: %w", err)
 	}
 
-	// Get commits to analyze
+	// Get commits to analyze. base is the last commit before them.
 	var commits []*git.Commit
-	if mergeInfo.IsMerge {
-		// Get commits from merge base to merge head (second parent)
-		commits, err = git.GetCommitsInRange(opts.RepoPath, mergeInfo.MergeBase, mergeInfo.MergeHead)
+	var base string
+	var since map[string]map[string]bool
+	var hugeFiles, releasedCommits int
+	if opts.Accumulate {
+		// Everything since each package's last release, whatever HEAD is
+		commits, since, releasedCommits, err = accumulateCommits(opts.RepoPath, cfg, cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits since release tags: %w", err)
+		}
+	} else if mergeInfo.IsMerge {
+		base = mergeInfo.FirstParent
+		if hugeFiles, err = hugeMergeSize(opts.RepoPath, mergeInfo); err != nil {
+			return nil, err
+		}
+		// Get every commit the merge brought in (all merged parents)
+		if hugeFiles > 0 {
+			slog.Debug("merge changed too many files to list per commit, mapping commits to packages by pathspec", "files", hugeFiles)
+			commits, err = git.GetMergedCommitsWithoutFiles(opts.RepoPath, mergeInfo)
+		} else {
+			commits, err = cache.GetMergedCommits(opts.RepoPath, mergeInfo)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to get merge commits: %w", err)
 		}
+		if commits, err = addMergeCommit(opts.RepoPath, cfg, mergeInfo, commits); err != nil {
+			return nil, err
+		}
 	} else {
 		// Fall back to HEAD~1..HEAD for non-merge commits
 		// This may fail if there's only one commit in the repo
-		commits, err = git.GetCommitsInRange(opts.RepoPath, "HEAD~1", "HEAD")
+		commits, err = cache.GetCommitsInRange(opts.RepoPath, "HEAD~1", "HEAD")
 		if err != nil {
 			// If HEAD~1 doesn't exist (single commit repo), return empty commits
 			commits = nil
+		} else if base, err = git.ResolveCommit(opts.RepoPath, "HEAD~1"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := reparseCommits(cfg, commits); err != nil {
+		return nil, err
+	}
+
+	// Listing each commit's files is timed on its own
+	listing := git.FileListingTime() - listingStart
+	timings.Add(PhaseGitTraversal, time.Since(start)-listing)
+	timings.Add(PhaseFileListing, listing)
+	start = time.Now()
+
+	// Release commits only record versions; their file changes aren't releasable
+	var releaseCommits int
+	kept := commits[:0]
+	for _, commit := range commits {
+		if cfg.IsReleaseCommit(commit.Subject) {
+			releaseCommits++
+			continue
+		}
+		kept = append(kept, commit)
+	}
+	commits = kept
+
+	// Lockfiles and generated code never count toward a release; commits
+	// touching nothing else are left out like release commits
+	var ignoredCommits int
+	if len(cfg.IgnoreFiles) > 0 {
+		kept = commits[:0]
+		for _, commit := range commits {
+			var files []string
+			for _, file := range commit.Files {
+				if !cfg.IsIgnoredFile(file) {
+					files = append(files, file)
+				}
+			}
+			if len(files) == 0 && len(commit.Files) > 0 {
+				ignoredCommits++
+				continue
+			}
+			commit.Files = files
+			kept = append(kept, commit)
+		}
+		commits = kept
+	}
+
+	// Binary assets can be summarized, or left out like ignore-files
+	var binaryCounts map[string]int
+	var binaryCommits int
+	if (cfg.SummarizeBinaryFiles || cfg.IgnoreBinaryFiles) && hugeFiles == 0 {
+		commits, binaryCounts, binaryCommits, err = applyBinaryFiles(opts.RepoPath, cfg, commits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, commit := range commits {
+		commit.Notes = commit.ReleaseNotes(cfg.IncludeCommitBody)
+		if n := binaryCounts[commit.SHA]; n > 0 && cfg.SummarizeBinaryFiles {
+			commit.Notes = append(commit.Notes, binaryNote(n))
+		}
+	}
+	addSubmoduleNotes(opts.RepoPath, cfg, commits)
+
+	// Squash merges with messy subjects may still have a conventional PR title
+	var prTitleCommits int
+	if opts.PRTitleFallback {
+		parser, err := commitParser(cfg)
+		if err != nil {
+			return nil, err
+		}
+		prTitleCommits = applyPullRequestTitles(opts.RepoPath, parser, commits)
+	}
+
+	// Shorthand scopes ("ui") read as the scope they stand for ("jarvis-web")
+	for _, commit := range commits {
+		commit.Scope = cfg.NormalizeScope(commit.Scope)
+	}
+
+	// Drop commits whose changes already shipped in another release
+	var cherryPicked []*git.Commit
+	if opts.CherryPickDedup {
+		commits, cherryPicked, err = dropReleasedEquivalents(opts.RepoPath, commits)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -117,7 +467,20 @@ func Analyze(opts *Options) (*AnalysisResult, error) {
 	matchedSHAs := make(map[string]bool)
 	orphanedDirSet := make(map[string]bool)
 
+	if hugeFiles > 0 {
+		if packageCommits, err = mapCommitsByPathspec(opts.RepoPath, cfg, mergeInfo, commits); err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			if len(commit.Files) > 0 {
+				matchedSHAs[commit.SHA] = true
+			}
+		}
+	}
 	for _, commit := range commits {
+		if hugeFiles > 0 {
+			break
+		}
 		commitMatched := false
 		for _, file := range commit.Files {
 			pkg := cfg.FindPackageForPath(file)
@@ -135,6 +498,16 @@ func Analyze(opts *Options) (*AnalysisResult, error) {
 		}
 	}
 
+	if opts.Accumulate {
+		keepSinceTag(packageCommits, since)
+	}
+
+	// Low-churn packages wait for enough commits to batch into one release
+	deferred, err := applyMinCommits(opts.RepoPath, base, cfg, cache, packageCommits)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build stats
 	var orphanedDirs []string
 	for dir := range orphanedDirSet {
@@ -147,25 +520,136 @@ func Analyze(opts *Options) (*AnalysisResult, error) {
 		MatchedCommits:   len(matchedSHAs),
 		UnmatchedCommits: len(commits) - len(matchedSHAs),
 		OrphanedDirs:     orphanedDirs,
+		PRTitleCommits:   prTitleCommits,
+		ReleaseCommits:   releaseCommits,
+		IgnoredCommits:   ignoredCommits,
+		BinaryCommits:    binaryCommits,
+		CherryPicked:     cherryPicked,
+		Deferred:         deferred,
+		HugeMergeFiles:   hugeFiles,
+		ReleasedCommits:  releasedCommits,
 	}
 
 	// Calculate bumps per package
-	releases := calculateReleases(cfg, packageCommits, opts.TreatPreMajorAsMinor)
+	releases, err := calculateReleases(cfg, packageCommits, opts.TreatPreMajorAsMinor, maxBump)
+	if err != nil {
+		return nil, err
+	}
+	releases, err = scheduleDependents(cfg, releases, opts.TreatPreMajorAsMinor)
+	if err != nil {
+		return nil, err
+	}
+	releases, stats.TagCollisions, err = resolveTagCollisions(opts.RepoPath, cfg, mergeInfo.HeadSHA, releases)
+	if err != nil {
+		return nil, err
+	}
+	if branch != nil {
+		if err := checkBranchReleases(branchName, branch, releases); err != nil {
+			return nil, err
+		}
+	}
+
+	timings.Since(PhaseAnalysis, start)
 
 	result := &AnalysisResult{
 		MergeInfo: mergeInfo,
 		Commits:   commits,
 		Releases:  releases,
 		Config:    cfg,
-		RepoURL:   opts.RepoURL,
+		RepoURL:   ResolveRepoURL(opts, cfg),
+		Branch:    branchName,
+		Bare:      bare,
 		Stats:     stats,
+		Timings:   timings,
+
+		Base:             base,
+		Accumulated:      opts.Accumulate,
+		Freeze:           freezeAt(cfg, opts),
+		FreezeOverridden: opts.OverrideFreeze,
+	}
+	if opts.Clock != nil {
+		result.ReleaseDate = opts.Clock()
+	}
+	if opts.BuildMetadata != "" {
+		if err := stampBuildMetadata(result, opts.BuildMetadata); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
+// addMergeCommit adds the merge commit itself to the merged commits when the
+// config's merge-commits setting asks for it and its subject is conventional.
+// With MergeCommitsOnly it replaces them; a non-conventional merge subject
+// leaves the merged commits as they are.
+func addMergeCommit(repoPath string, cfg *config.Config, info *git.MergeInfo, commits []*git.Commit) ([]*git.Commit, error) {
+	if cfg.MergeCommits == "" || cfg.MergeCommits == config.MergeCommitsIgnore {
+		return commits, nil
+	}
+
+	merge, err := git.GetMergeCommit(repoPath, info)
+	if err != nil {
+		return nil, err
+	}
+	if err := reparseCommits(cfg, []*git.Commit{merge}); err != nil {
+		return nil, err
+	}
+	if merge.Type == "" {
+		return commits, nil
+	}
+	if cfg.MergeCommits == config.MergeCommitsOnly {
+		return []*git.Commit{merge}, nil
+	}
+	return append(commits, merge), nil
+}
+
+// reparseCommits parses commits again with the config's commit-parser
+// options, if it has any, so subjects such as "✨ feat: ..." are conventional.
+func reparseCommits(cfg *config.Config, commits []*git.Commit) error {
+	if cfg.CommitParser == nil {
+		return nil
+	}
+	parser, err := commitParser(cfg)
+	if err != nil {
+		return err
+	}
+	for _, c := range commits {
+		parser.Reparse(c)
+	}
+	return nil
+}
+
+// commitParser returns a parser with the config's commit-parser options, or
+// the defaults if it has none.
+func commitParser(cfg *config.Config) (*git.Parser, error) {
+	parser, err := git.NewParser(cfg.CommitParser.ParserOptions())
+	if err != nil {
+		return nil, &ConfigError{Err: fmt.Errorf("invalid commit-parser: %w", err)}
+	}
+	return parser, nil
+}
+
+// dropReleasedEquivalents splits commits into those still to be released and
+// those patch-equivalent to a commit already released under a release tag.
+func dropReleasedEquivalents(repoPath string, commits []*git.Commit) (kept, dropped []*git.Commit, err error) {
+	equivalent, err := git.GetReleasedEquivalents(repoPath, git.ReleaseTagPattern, commits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to detect cherry-picked commits: %w", err)
+	}
+	for _, c := range commits {
+		if equivalent[c.SHA] {
+			dropped = append(dropped, c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	return kept, dropped, nil
+}
+
 // calculateReleases determines which packages need releases and their version bumps.
-func calculateReleases(cfg *config.Config, packageCommits map[string][]*git.Commit, treatPreMajorAsMinor bool) []*PackageRelease {
+// Bumps are capped at limit unless it's None.
+func calculateReleases(cfg *config.Config, packageCommits map[string][]*git.Commit, treatPreMajorAsMinor bool, limit version.BumpType) ([]*PackageRelease, error) {
 	var releases []*PackageRelease
 	processedLinkedGroups := make(map[string]bool)
 
@@ -183,7 +667,7 @@ func calculateReleases(cfg *config.Config, packageCommits map[string][]*git.Comm
 				maxBump = version.Major
 				break // Can't go higher
 			}
-			bump := version.CommitTypeToBump(commit.Type)
+			bump := cfg.BumpFor(commit.Type)
 			maxBump = version.MaxBump(maxBump, bump)
 		}
 
@@ -208,19 +692,34 @@ func calculateReleases(cfg *config.Config, packageCommits map[string][]*git.Comm
 						maxBump = version.Major
 						break
 					}
-					bump := version.CommitTypeToBump(commit.Type)
+					bump := cfg.BumpFor(commit.Type)
 					maxBump = version.MaxBump(maxBump, bump)
 				}
 			}
 
-			// Create releases for all linked packages
+			maxBump = capBump(pkg.LinkedGroup, maxBump, limit)
+
+			// Create releases for all linked packages. Members whose
+			// versions drifted apart are all bumped from the highest, so
+			// they converge again.
+			base := linkedBase(linkedPackages)
 			for _, linkedPkg := range linkedPackages {
-				release := createRelease(linkedPkg, packageCommits[linkedPkg.Path], maxBump, treatPreMajorAsMinor)
+				release, err := createReleaseFrom(linkedPkg, base, packageCommits[linkedPkg.Path], maxBump, treatPreMajorAsMinor)
+				if err != nil {
+					return nil, err
+				}
+				if release.ReconciledFrom != "" {
+					slog.Warn("linked package drifted from its group, reconciling", "component", linkedPkg.Component, "group", pkg.LinkedGroup, "version", release.OldVersion, "group_version", base, "new_version", release.NewVersion)
+				}
 				releases = append(releases, release)
 			}
 		} else {
 			// Not linked - create single release
-			release := createRelease(pkg, commits, maxBump, treatPreMajorAsMinor)
+			maxBump = capBump(pkg.Component, maxBump, limit)
+			release, err := createRelease(pkg, commits, maxBump, treatPreMajorAsMinor)
+			if err != nil {
+				return nil, err
+			}
 			releases = append(releases, release)
 		}
 	}
@@ -230,24 +729,57 @@ func calculateReleases(cfg *config.Config, packageCommits map[string][]*git.Comm
 		return releases[i].Package.Path < releases[j].Package.Path
 	})
 
-	return releases
+	return releases, nil
 }
 
 // createRelease creates a PackageRelease for a package.
-func createRelease(pkg *config.Package, commits []*git.Commit, bumpType version.BumpType, treatPreMajorAsMinor bool) *PackageRelease {
+func createRelease(pkg *config.Package, commits []*git.Commit, bumpType version.BumpType, treatPreMajorAsMinor bool) (*PackageRelease, error) {
+	return createReleaseFrom(pkg, "", commits, bumpType, treatPreMajorAsMinor)
+}
+
+// createReleaseFrom creates a PackageRelease bumped from base if the
+// package's version is behind it, as for a linked package that drifted
+// behind its group. An empty base means the package's own version.
+func createReleaseFrom(pkg *config.Package, base string, commits []*git.Commit, bumpType version.BumpType, treatPreMajorAsMinor bool) (*PackageRelease, error) {
 	oldVersion := pkg.CurrentVersion
 	if oldVersion == "" {
 		oldVersion = "0.0.0"
 	}
 
-	v, err := version.Parse(oldVersion)
-	if err != nil {
+	if _, err := version.Parse(oldVersion); err != nil {
 		// Invalid version - start from 0.1.0
-		v = &version.Version{Major: 0, Minor: 1, Patch: 0}
 		oldVersion = "0.1.0"
 	}
 
-	newVersion := v.Bump(bumpType, treatPreMajorAsMinor)
+	from, reconciledFrom := oldVersion, ""
+	if base != "" {
+		old, _ := version.Parse(oldVersion)
+		if b, err := version.Parse(base); err == nil && old.Compare(b) < 0 {
+			from, reconciledFrom = base, base
+		}
+	}
+
+	// strict-zero picks the 0.x bump itself, instead of the analyzer setting
+	versionBump := bumpType
+	if pkg.PrereleaseSemantics == config.PrereleaseStrictZero {
+		if v, err := version.Parse(from); err == nil {
+			versionBump = v.StrictZeroBump(bumpType)
+		}
+		treatPreMajorAsMinor = false
+	}
+
+	// A first release takes the configured initial-version as is
+	firstRelease := oldVersion == "0.0.0"
+	var newVersion string
+	if firstRelease && reconciledFrom == "" && pkg.InitialVersion != "" {
+		newVersion = pkg.InitialVersion
+	} else {
+		var err error
+		newVersion, err = nextVersion(pkg, from, commits, versionBump, treatPreMajorAsMinor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate version for %s: %w", pkg.Component, err)
+		}
+	}
 
 	// Deduplicate commits (a commit might touch multiple files in the package)
 	seen := make(map[string]bool)
@@ -260,143 +792,362 @@ func createRelease(pkg *config.Package, commits []*git.Commit, bumpType version.
 	}
 
 	return &PackageRelease{
-		Package:    pkg,
-		BumpType:   bumpType,
-		OldVersion: oldVersion,
-		NewVersion: newVersion.String(),
-		Commits:    uniqueCommits,
+		Package:        pkg,
+		BumpType:       bumpType,
+		OldVersion:     oldVersion,
+		NewVersion:     newVersion,
+		Commits:        uniqueCommits,
+		ReconciledFrom: reconciledFrom,
+		FirstRelease:   firstRelease,
+	}, nil
+}
+
+// previousVersion returns the version released before this one, or "" for
+// a first release.
+func (rel *PackageRelease) previousVersion() string {
+	if rel.FirstRelease {
+		return ""
 	}
+	return rel.OldVersion
 }
 
-// Apply writes the version updates, changelogs, and manifest updates.
-func Apply(result *AnalysisResult, dryRun bool) error {
+// compareURL returns the compare view from the previous release to this
+// one, or "" for a first release, which has no previous tag to compare.
+func (rel *PackageRelease) compareURL(repoURL string) string {
+	return changelog.BuildCompareURL(repoURL, rel.Package.Component, rel.previousVersion(), rel.NewVersion)
+}
+
+// linkedBase returns the highest current version among a linked group's
+// packages, or "" if none has a valid one.
+func linkedBase(pkgs []*config.Package) string {
+	var highest *version.Version
+	var base string
+	for _, pkg := range pkgs {
+		if pkg.CurrentVersion == "" {
+			continue
+		}
+		v, err := version.Parse(pkg.CurrentVersion)
+		if err != nil {
+			continue
+		}
+		if highest == nil || v.Compare(highest) > 0 {
+			highest, base = v, pkg.CurrentVersion
+		}
+	}
+	return base
+}
+
+// FileChange is a file write planned by Apply.
+type FileChange struct {
+	// Path is relative to the repository root.
+	Path string
+	Old  string // empty if the file doesn't exist yet
+	New  string
+}
+
+// PlanChanges computes the file contents Apply would write, without touching
+// the filesystem. Changes are ordered VERSION, extra files, Dockerfiles, docs,
+// CHANGELOG, and CHANGELOG.json per release, then the manifest.
+func PlanChanges(result *AnalysisResult) ([]*FileChange, error) {
 	contracts.RequireNotNil(result, "result")
+	if result.Bare {
+		return nil, ErrBareRepository
+	}
+	if result.Remote != "" {
+		return nil, ErrRemoteAnalysis
+	}
 
 	if len(result.Releases) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// Update manifest with new versions
+	repoRoot := result.Config.RepoRoot
+	var changes []*FileChange
+
 	manifestUpdates := make(map[string]string)
 	for _, rel := range result.Releases {
 		manifestUpdates[rel.Package.Path] = rel.NewVersion
-	}
 
-	// Update VERSION files and CHANGELOGs
-	for _, rel := range result.Releases {
-		if dryRun {
-			continue
+		// VERSION file
+		if rel.Package.VersionFile != "" {
+			versionPath := filepath.Join(rel.Package.Path, rel.Package.VersionFile)
+			change, err := planVersionFile(repoRoot, versionPath, rel.BuildVersion())
+			if err != nil {
+				return nil, fmt.Errorf("failed to update %s for %s: %w", rel.Package.VersionFile, rel.Package.Component, err)
+			}
+			changes = append(changes, change)
 		}
 
-		// Update VERSION file
-		versionPath := filepath.Join(result.Config.RepoRoot, rel.Package.Path, "VERSION")
-		if err := updateVersionFile(versionPath, rel.NewVersion); err != nil {
-			return fmt.Errorf("failed to update VERSION for %s: %w", rel.Package.Component, err)
+		// Extra files
+		for _, extra := range rel.Package.ExtraFiles {
+			change, err := planExtraFile(repoRoot, rel, extra)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update %s for %s: %w", extra.Path, rel.Package.Component, err)
+			}
+			changes = append(changes, change)
 		}
 
-		// Update CHANGELOG
-		changelogPath := filepath.Join(result.Config.RepoRoot, rel.Package.Path, rel.Package.ChangelogPath)
-		compareURL := changelog.BuildCompareURL(result.RepoURL, rel.Package.Component, rel.OldVersion, rel.NewVersion)
-		if err := updateChangelog(changelogPath, rel, compareURL, result.RepoURL); err != nil {
-			return fmt.Errorf("failed to update CHANGELOG for %s: %w", rel.Package.Component, err)
+		// Dockerfiles
+		dockerChanges, err := planDockerfiles(result, rel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update Dockerfiles for %s: %w", rel.Package.Component, err)
 		}
-	}
+		changes = append(changes, dockerChanges...)
 
-	// Update manifest file
-	if !dryRun {
-		if err := updateManifest(result.Config.RepoRoot, manifestUpdates); err != nil {
-			return fmt.Errorf("failed to update manifest: %w", err)
+		// Marked regions in docs
+		docChanges, err := planMarkdownFiles(result, rel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update docs for %s: %w", rel.Package.Component, err)
+		}
+		changes = append(changes, docChanges...)
+
+		// CHANGELOG. Linked packages that weren't directly modified get a
+		// note pointing at the release that bumped them.
+		entry := changelogEntry(result, rel, result.RepoURL)
+		if len(entry.Commits) == 0 && entry.Note == "" {
+			continue
+		}
+		changelogPath := filepath.Join(rel.Package.Path, rel.Package.ChangelogPath)
+		if rel.Package.ChangelogLayout == config.ChangelogLayoutDirectory {
+			dirChanges, err := planChangelogDir(repoRoot, changelogPath, entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update changelogs for %s: %w", rel.Package.Component, err)
+			}
+			changes = append(changes, dirChanges...)
+		} else {
+			change, err := planChangelog(repoRoot, changelogPath, entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update CHANGELOG for %s: %w", rel.Package.Component, err)
+			}
+			changes = append(changes, change)
+		}
+		if rel.Package.ChangelogJSON {
+			jsonPath := changelogJSONPath(rel.Package)
+			change, err := planChangelogJSON(repoRoot, jsonPath, entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update %s for %s: %w", jsonPath, rel.Package.Component, err)
+			}
+			changes = append(changes, change)
 		}
 	}
 
-	return nil
-}
+	// Without a manifest, the release tags are the record of versions
+	if result.Config.VersionSource == config.VersionSourceTags {
+		return changes, nil
+	}
 
-// updateVersionFile updates a VERSION file with the new version.
-func updateVersionFile(path, newVersion string) error {
-	// Read existing content to preserve format
-	existing, err := os.ReadFile(path)
-	if err != nil && !os.IsNotExist(err) {
-		return err
+	manifestPath := result.Config.ManifestPath
+	if manifestPath == "" {
+		manifestPath = config.ManifestFile
+	}
+	change, err := planManifest(repoRoot, manifestPath, manifestUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update manifest: %w", err)
 	}
+	changes = append(changes, change)
 
-	content := version.FormatVersionFile(newVersion, string(existing))
+	return changes, nil
+}
+
+// Apply writes the version updates, changelogs, and manifest updates.
+// With dryRun, the changes are planned but nothing is written. Nothing is
+// written either if a release's version wouldn't go up, or if the manifest
+// or a version file changed since the analysis.
+func Apply(result *AnalysisResult, dryRun bool) error {
+	contracts.RequireNotNil(result, "result")
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+	changes, err := PlanChanges(result)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	if err := CheckFreeze(result); err != nil {
+		return err
+	}
+	if err := checkVersions(result); err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	return writeChanges(result.Config.RepoRoot, changes)
 }
 
-// updateChangelog updates a CHANGELOG.md file with a new entry.
-func updateChangelog(path string, rel *PackageRelease, compareURL, repoURL string) error {
-	// Skip changelog update if there are no commits
-	// This can happen for linked packages that weren't directly modified
-	if len(rel.Commits) == 0 {
-		return nil
+// writeChanges writes planned changes under repoRoot.
+func writeChanges(repoRoot string, changes []*FileChange) error {
+	for _, change := range changes {
+		path := filepath.Join(repoRoot, change.Path)
+
+		// Ensure directory exists
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(change.New), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.Path, err)
+		}
 	}
 
-	// Read existing content
-	existing, err := os.ReadFile(path)
+	return nil
+}
+
+// readOptional reads a file, reporting whether it exists. A missing file reads as "".
+func readOptional(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			existing = []byte(changelog.InitialChangelog())
-		} else {
-			return err
+			return "", false, nil
 		}
+		return "", false, err
 	}
+	return string(data), true, nil
+}
 
-	// Generate new entry
-	entry := &changelog.Entry{
-		Version:     rel.NewVersion,
-		Date:        time.Now(),
-		CompareURL:  compareURL,
-		Commits:     rel.Commits,
-		Component:   rel.Package.Component,
-		RepoURL:     repoURL,
-		PrevVersion: rel.OldVersion,
+// planVersionFile plans a VERSION file update, preserving its existing format.
+func planVersionFile(repoRoot, path, newVersion string) (*FileChange, error) {
+	existing, _, err := readOptional(filepath.Join(repoRoot, path))
+	if err != nil {
+		return nil, err
 	}
 
-	newEntry := changelog.Generate(entry)
-	updated := changelog.Prepend(string(existing), newEntry)
-
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
+	// Files with more than a version (e.g. version.go) are updated in place
+	// at their x-release-please-version markers. Without a marker the file
+	// is rewritten whole, as it always was.
+	if !isPlainVersionFile(existing) {
+		if !strings.Contains(existing, "x-release-please-") {
+			slog.Warn("version file has no x-release-please-version marker, overwriting it", "path", path)
+			return &FileChange{Path: path, Old: existing, New: version.FormatVersionFile(newVersion, existing)}, nil
+		}
+		updated, err := genericUpdate(&extension.UpdateRequest{Path: path, Content: existing, NewVersion: newVersion})
+		if err != nil {
+			return nil, err
+		}
+		return &FileChange{Path: path, Old: existing, New: updated}, nil
 	}
 
-	return os.WriteFile(path, []byte(updated), 0644)
+	return &FileChange{
+		Path: path,
+		Old:  existing,
+		New:  version.FormatVersionFile(newVersion, existing),
+	}, nil
 }
 
-// updateManifest updates release-please-manifest.json with new versions.
-func updateManifest(repoRoot string, updates map[string]string) error {
-	manifestPath := filepath.Join(repoRoot, "release-please-manifest.json")
+// isPlainVersionFile reports whether content is empty or a single line
+// holding just a version, as in a VERSION file.
+func isPlainVersionFile(content string) bool {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return true
+	}
+	if strings.Contains(content, "\n") {
+		return false
+	}
+	_, err := version.ParseVersionFile(content)
+	return err == nil
+}
 
-	// Read existing manifest
-	data, err := os.ReadFile(manifestPath)
+// planChangelog plans a CHANGELOG.md update with a new entry.
+func planChangelog(repoRoot, path string, entry *changelog.Entry) (*FileChange, error) {
+	existing, found, err := readOptional(filepath.Join(repoRoot, path))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	base := existing
+	if !found {
+		base = changelog.InitialChangelog()
 	}
 
-	// Parse as generic JSON (preserves order)
-	// For simplicity, we'll use string manipulation to update values
-	content := string(data)
+	return &FileChange{
+		Path: path,
+		Old:  existing,
+		New:  changelog.Prepend(base, changelog.Generate(entry)),
+	}, nil
+}
+
+// RenderChangelog returns the CHANGELOG.md entry Apply writes for a release.
+// With ReleaseDate set, the output depends only on the commits and config.
+func RenderChangelog(result *AnalysisResult, rel *PackageRelease) string {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotNil(rel, "rel")
+	return changelog.Generate(changelogEntry(result, rel, result.RepoURL))
+}
+
+// changelogEntry builds the changelog entry for a release, dated
+// result.Date(), linking to repoURL.
+func changelogEntry(result *AnalysisResult, rel *PackageRelease, repoURL string) *changelog.Entry {
+	entry := &changelog.Entry{
+		Version:      rel.NewVersion,
+		Date:         result.Date(),
+		CompareURL:   rel.compareURL(repoURL),
+		Commits:      rel.Commits,
+		Component:    rel.Package.Component,
+		RepoURL:      repoURL,
+		PrevVersion:  rel.previousVersion(),
+		FirstRelease: rel.FirstRelease,
+		Sections:     rel.Package.ChangelogSections,
+		Sort:         rel.Package.ChangelogSort,
+		MaxBullets:   rel.Package.ChangelogMaxEntries,
+		Note:         releaseNote(result, rel),
+	}
+	if result.Config != nil && result.Config.Jira != nil {
+		entry.JiraBaseURL = result.Config.Jira.BaseURL
+		entry.JiraProjects = result.Config.Jira.Projects
+	}
+	return entry
+}
+
+// releaseNote explains a release without commits of its own: why it was
+// scheduled, by a dependency (see dependencyNote) or its linked-versions
+// group (see linkedBumpNote). Other releases get "".
+func releaseNote(result *AnalysisResult, rel *PackageRelease) string {
+	if note := dependencyNote(result, rel); note != "" {
+		return note
+	}
+	return linkedBumpNote(result, rel)
+}
+
+// linkedBumpNote explains a linked-versions release without commits of its
+// own, e.g. "Version bump to stay in sync with services; see api 1.2.0.",
+// naming the group's releases that have commits. Other releases get "".
+func linkedBumpNote(result *AnalysisResult, rel *PackageRelease) string {
+	group := rel.Package.LinkedGroup
+	if len(rel.Commits) > 0 || group == "" {
+		return ""
+	}
+	var sources []string
+	for _, other := range result.Releases {
+		if other != rel && other.Package.LinkedGroup == group && len(other.Commits) > 0 {
+			sources = append(sources, other.Package.Component+" "+other.NewVersion)
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Sprintf("Version bump to stay in sync with %s.", group)
+	}
+	return fmt.Sprintf("Version bump to stay in sync with %s; see %s.", group, strings.Join(sources, ", "))
+}
 
-	for path, version := range updates {
-		// Find and replace the version for this path
-		// Format: "path": "version"
-		oldPattern := fmt.Sprintf(`"%s": "[^"]*"`, path)
-		newValue := fmt.Sprintf(`"%s": "%s"`, path, version)
+// planManifest plans the manifest update with new versions.
+func planManifest(repoRoot, manifestPath string, updates map[string]string) (*FileChange, error) {
+	data, err := os.ReadFile(filepath.Join(repoRoot, manifestPath))
+	if err != nil {
+		return nil, err
+	}
 
-		// Simple replacement (works for well-formatted JSON)
-		content = replaceJSONValue(content, path, version)
-		_ = oldPattern
-		_ = newValue
+	// String replacement rather than re-marshaling preserves key order and formatting
+	content := string(data)
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content = replaceJSONValue(content, path, updates[path])
 	}
 
-	return os.WriteFile(manifestPath, []byte(content), 0644)
+	return &FileChange{
+		Path: manifestPath,
+		Old:  string(data),
+		New:  content,
+	}, nil
 }
 
 // replaceJSONValue replaces a value in a JSON object.
@@ -414,7 +1165,7 @@ func replaceJSONValue(json, key, newValue string) string {
 
 		// Find the value (skip whitespace, find opening quote)
 		valueStart := idx + len(keyStr)
-		for valueStart < len(json) && (json[valueStart] == ' ' || json[valueStart] == '\t') {
+		for valueStart < len(json) && strings.IndexByte(" \t\r\n", json[valueStart]) >= 0 {
 			valueStart++
 		}
 
@@ -1,10 +1,19 @@
 package release
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
 )
 
 // createTestRepo creates a temporary git repository for testing.
@@ -183,6 +192,245 @@ func TestAnalyze_MergeCommit(t *testing.T) {
 	}
 }
 
+func TestAnalyze_BackMergedMainlineNotReleasedAgain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-b": "0.1.0"
+	}`)
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add service-b")
+
+	runCmd(t, dir, "git", "checkout", "-b", "feature/a")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Feature\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add feature")
+
+	// service-b is fixed (and released) on main, then main is merged into the
+	// feature branch, both by merge and by cherry-pick
+	runCmd(t, dir, "git", "checkout", "main")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): fix bug")
+	writeFile(t, dir, "workloads/service-b/src/util.go", "// Util\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): fix util")
+	runCmd(t, dir, "git", "checkout", "feature/a")
+	runCmd(t, dir, "git", "cherry-pick", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "main~1", "-m", "Merge branch 'main' into feature/a")
+
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature/a", "-m", "Merge branch 'feature/a'")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var components []string
+	for _, rel := range result.Releases {
+		components = append(components, rel.Package.Component)
+	}
+	if len(components) != 1 || components[0] != "service-a" {
+		t.Errorf("expected only service-a to release, got %v", components)
+	}
+}
+
+func TestAnalyze_IgnoresReleaseCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	runCmd(t, dir, "git", "checkout", "-b", "feature/a")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+	writeFile(t, dir, "workloads/service-a/VERSION", "1.0.0 # x-release-please-version\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore(main): release service-a 1.0.0")
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature/a", "-m", "Merge branch 'feature/a'")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(result.Commits) != 1 || result.Commits[0].Type != "fix" {
+		t.Errorf("expected only the fix commit, got %d commits", len(result.Commits))
+	}
+	if result.Stats.ReleaseCommits != 1 {
+		t.Errorf("expected 1 release commit skipped, got %d", result.Stats.ReleaseCommits)
+	}
+	if len(result.Releases) != 1 || len(result.Releases[0].Commits) != 1 {
+		t.Fatalf("expected 1 release with 1 commit, got %+v", result.Releases)
+	}
+}
+
+func TestAnalyze_IgnoreFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, ".release-damnit.yaml", "ignore-files:\n  - package-lock.json\n  - '**/*.pb.go'\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: ignore generated files")
+
+	runCmd(t, dir, "git", "checkout", "-b", "feature/a")
+	writeFile(t, dir, "workloads/service-a/package-lock.json", "{}\n")
+	writeFile(t, dir, "workloads/service-a/api/service.pb.go", "package api\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): regenerate API")
+	writeFile(t, dir, "tools/package-lock.json", "{}\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: bump tool deps")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	writeFile(t, dir, "workloads/service-a/package-lock.json", "{\"v\": 2}\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature/a", "-m", "Merge branch 'feature/a'")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if result.Stats.IgnoredCommits != 2 || result.Stats.UnmatchedCommits != 0 || len(result.Stats.OrphanedDirs) != 0 {
+		t.Errorf("expected 2 ignored commits and nothing unmatched, got %+v", result.Stats)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.1" {
+		t.Fatalf("expected only the fix to release 0.1.1, got %+v", result.Releases)
+	}
+	if files := result.Releases[0].Commits[0].Files; len(files) != 1 || files[0] != "workloads/service-a/src/main.go" {
+		t.Errorf("expected the lockfile dropped from the fix's files, got %v", files)
+	}
+}
+
+func TestAnalyze_MergeCommitSubject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		mode        string
+		wantCommits int
+		wantVersion string
+	}{
+		{"", 1, "0.1.1"},
+		{"include", 2, "0.2.0"},
+		{"only", 1, "0.2.0"},
+	}
+
+	for _, tc := range tests {
+		t.Run("mode="+tc.mode, func(t *testing.T) {
+			dir := setupBasicRepo(t)
+			if tc.mode != "" {
+				writeFile(t, dir, "release-please-config.json", `{
+					"packages": {"workloads/service-a": {"component": "service-a"}},
+					"merge-commits": "`+tc.mode+`"
+				}`)
+				runCmd(t, dir, "git", "add", "-A")
+				runCmd(t, dir, "git", "commit", "-m", "chore: configure merge commits")
+			}
+
+			runCmd(t, dir, "git", "checkout", "-b", "feature/a")
+			writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Feature\n")
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "fix(service-a): first pass")
+			runCmd(t, dir, "git", "checkout", "main")
+			runCmd(t, dir, "git", "merge", "--no-ff", "feature/a", "-m", "feat(service-a): add feature")
+
+			result, err := Analyze(&Options{RepoPath: dir})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(result.Commits) != tc.wantCommits {
+				t.Errorf("expected %d commits, got %d", tc.wantCommits, len(result.Commits))
+			}
+			if len(result.Releases) != 1 {
+				t.Fatalf("expected 1 release, got %d", len(result.Releases))
+			}
+			if got := result.Releases[0].NewVersion; got != tc.wantVersion {
+				t.Errorf("expected version %s, got %s", tc.wantVersion, got)
+			}
+		})
+	}
+}
+
+func TestAnalyze_CherryPickDedup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+
+	// main's next patch release collides with the hotfix's tag
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {"workloads/service-a": {"component": "service-a"}},
+		"tag-collision": "bump-again"
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: bump again on tag collisions")
+
+	// Hotfix released from a maintenance branch
+	runCmd(t, dir, "git", "checkout", "-b", "release-0.1.x")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Hotfix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): hotfix")
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.1")
+
+	// The hotfix is cherry-picked into a feature branch merged to main
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "checkout", "-b", "feature/a")
+	writeFile(t, dir, "workloads/service-a/src/util.go", "// Util\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add util")
+	runCmd(t, dir, "git", "cherry-pick", "release-0.1.x")
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature/a", "-m", "Merge branch 'feature/a'")
+
+	tests := []struct {
+		name        string
+		dedup       bool
+		wantCommits int
+		wantSkipped int
+	}{
+		{"disabled", false, 2, 0},
+		{"enabled", true, 1, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, CherryPickDedup: tc.dedup})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(result.Releases) != 1 {
+				t.Fatalf("expected 1 release, got %d", len(result.Releases))
+			}
+			if got := len(result.Releases[0].Commits); got != tc.wantCommits {
+				t.Errorf("expected %d commits in release, got %d", tc.wantCommits, got)
+			}
+			if got := len(result.Stats.CherryPicked); got != tc.wantSkipped {
+				t.Errorf("expected %d skipped commits, got %d", tc.wantSkipped, got)
+			}
+		})
+	}
+}
+
 func TestAnalyze_MultiplePackages(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -344,6 +592,61 @@ func TestAnalyze_LinkedVersions(t *testing.T) {
 	}
 }
 
+func TestAnalyze_LinkedVersionsDrift(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"}
+		},
+		"plugins": [
+			{"type": "linked-versions", "groupName": "services", "components": ["service-a", "service-b"]}
+		]
+	}`)
+	// service-b fell behind, e.g. after a hand-edited manifest
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "1.2.0",
+		"workloads/service-b": "1.1.3"
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// A\n")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// B\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// B\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 2 {
+		t.Fatalf("expected 2 releases (linked), got %d", len(result.Releases))
+	}
+	for _, rel := range result.Releases {
+		if rel.NewVersion != "1.2.1" {
+			t.Errorf("%s: expected both to converge on 1.2.1, got %s", rel.Package.Component, rel.NewVersion)
+		}
+	}
+
+	report := BuildReleaseReport(result, "")
+	reconciled := map[string]string{}
+	for _, rel := range report.Releases {
+		reconciled[rel.Component] = rel.OldVersion + " from " + rel.ReconciledFrom
+	}
+	if reconciled["service-a"] != "1.2.0 from " {
+		t.Errorf("service-a: expected no reconciliation, got %q", reconciled["service-a"])
+	}
+	if reconciled["service-b"] != "1.1.3 from 1.2.0" {
+		t.Errorf("service-b: expected reconciled from 1.2.0, got %q", reconciled["service-b"])
+	}
+}
+
 func TestAnalyze_NoReleasableCommits(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -433,6 +736,388 @@ func TestApply_UpdatesFiles(t *testing.T) {
 	}
 }
 
+func TestPlanChanges_DoesNotWrite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Feature\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add feature")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	want := []string{"workloads/service-a/VERSION", "workloads/service-a/CHANGELOG.md", "release-please-manifest.json"}
+	if len(paths) != len(want) {
+		t.Fatalf("expected changes to %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("expected changes to %v, got %v", want, paths)
+		}
+	}
+
+	if changes[0].New != "0.1.1 # x-release-please-version\n" {
+		t.Errorf("unexpected VERSION content: %q", changes[0].New)
+	}
+	if !contains(changes[1].New, "## [0.1.1]") {
+		t.Errorf("CHANGELOG missing new entry: %s", changes[1].New)
+	}
+	if !contains(changes[2].New, `"0.1.1"`) || changes[2].Old == changes[2].New {
+		t.Errorf("manifest not updated: %s", changes[2].New)
+	}
+
+	// Nothing on disk should have changed
+	versionContent, err := os.ReadFile(filepath.Join(dir, "workloads/service-a/VERSION"))
+	if err != nil {
+		t.Fatalf("failed to read VERSION file: %v", err)
+	}
+	if string(versionContent) != changes[0].Old {
+		t.Errorf("PlanChanges modified VERSION: %s", string(versionContent))
+	}
+}
+
+func TestPlanChanges_Clock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	releaseDate := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	plan := func() []*FileChange {
+		result, err := Analyze(&Options{RepoPath: dir, DryRun: true, Clock: func() time.Time { return releaseDate }})
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if !result.Date().Equal(releaseDate) {
+			t.Errorf("expected release date %v, got %v", releaseDate, result.Date())
+		}
+		changes, err := PlanChanges(result)
+		if err != nil {
+			t.Fatalf("PlanChanges failed: %v", err)
+		}
+		return changes
+	}
+
+	first, second := plan(), plan()
+	if len(first) != len(second) {
+		t.Fatalf("expected the same changes, got %d and %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].New != second[i].New {
+			t.Errorf("%s differs between runs", first[i].Path)
+		}
+	}
+	if !contains(first[1].New, "## [0.1.1] (2024-03-01)") {
+		t.Errorf("expected the changelog entry dated 2024-03-01, got:\n%s", first[1].New)
+	}
+
+	// Results built by hand are dated today
+	if d := (&AnalysisResult{}).Date(); time.Since(d) > time.Minute {
+		t.Errorf("expected the current time, got %v", d)
+	}
+}
+
+func TestAnalyze_CommitParser(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, ".release-damnit.yaml", "commit-parser:\n  strip-prefixes:\n    - '\\p{So}'\n")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Export\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "✨ feat(service-a): add export")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.2.0" {
+		t.Fatalf("expected the emoji feat to release 0.2.0, got %+v", result.Releases)
+	}
+	if c := result.Releases[0].Commits[0]; c.Type != "feat" || c.Scope != "service-a" || c.Description != "add export" {
+		t.Errorf("unexpected commit: %+v", c)
+	}
+}
+
+func TestAnalyze_ScopeAliases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, ".release-damnit.yaml", "scope-aliases:\n  a: service-a\n")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(A): handle nil config")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 {
+		t.Fatalf("expected 1 release, got %d", len(result.Releases))
+	}
+	if scope := result.Releases[0].Commits[0].Scope; scope != "service-a" {
+		t.Errorf("expected the alias to be normalized to service-a, got %q", scope)
+	}
+	if entry := RenderChangelog(result, result.Releases[0]); !contains(entry, "* **service-a:** handle nil config") {
+		t.Errorf("expected the canonical scope in the changelog, got:\n%s", entry)
+	}
+}
+
+func TestPlanChanges_VersionFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		name        string
+		versionFile string
+		existing    string
+		wantPath    string
+		wantContent string
+	}{
+		{
+			name:        "plain file",
+			versionFile: "version.txt",
+			existing:    "0.1.0\n",
+			wantPath:    "workloads/service-a/version.txt",
+			wantContent: "0.1.1\n",
+		},
+		{
+			name:        "go source with marker",
+			versionFile: "internal/version/version.go",
+			existing:    "package version\n\nconst Version = \"0.1.0\" // x-release-please-version\n",
+			wantPath:    "workloads/service-a/internal/version/version.go",
+			wantContent: "package version\n\nconst Version = \"0.1.1\" // x-release-please-version\n",
+		},
+		{
+			name:        "none",
+			versionFile: "none",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupBasicRepo(t)
+			writeFile(t, dir, "release-please-config.json", fmt.Sprintf(`{
+				"packages": {
+					"workloads/service-a": {"component": "service-a", "version-file": %q}
+				}
+			}`, tc.versionFile))
+			if tc.wantPath != "" {
+				writeFile(t, dir, tc.wantPath, tc.existing)
+			}
+			writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+			result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			changes, err := PlanChanges(result)
+			if err != nil {
+				t.Fatalf("PlanChanges failed: %v", err)
+			}
+
+			var found *FileChange
+			for _, c := range changes {
+				if c.Path == "workloads/service-a/VERSION" {
+					t.Errorf("default VERSION file should not be planned")
+				}
+				if c.Path == tc.wantPath {
+					found = c
+				}
+			}
+			if tc.wantPath == "" {
+				if len(changes) != 2 {
+					t.Errorf("expected only CHANGELOG and manifest changes, got %d", len(changes))
+				}
+				return
+			}
+			if found == nil {
+				t.Fatalf("expected a change to %s", tc.wantPath)
+			}
+			if found.New != tc.wantContent {
+				t.Errorf("expected %q, got %q", tc.wantContent, found.New)
+			}
+		})
+	}
+}
+
+func TestPlanChanges_VersionFileWithoutMarker(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a", "version-file": "version.go"}
+		}
+	}`)
+	writeFile(t, dir, "workloads/service-a/version.go", "package main\n\nconst Version = \"0.1.0\"\n")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+	for _, c := range changes {
+		if c.Path == "workloads/service-a/version.go" {
+			if c.New != "0.1.1\n" {
+				t.Errorf("expected the file overwritten with the version, got %q", c.New)
+			}
+			return
+		}
+	}
+	t.Error("expected version.go to be planned")
+}
+
+func TestApply_CustomConfigAndManifest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release/train.json", `{
+		"packages": {
+			"workloads/service-a": {
+				"component": "service-a"
+			}
+		}
+	}`)
+	writeFile(t, dir, "release/versions.json", `{
+		"workloads/service-a": "0.4.0"
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{
+		RepoPath:     dir,
+		ConfigFile:   "release/train.json",
+		ManifestFile: "release/versions.json",
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.4.1" {
+		t.Fatalf("expected service-a 0.4.0 → 0.4.1 from the custom manifest, got %+v", result.Releases)
+	}
+
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "release/versions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(manifest), `"0.4.1"`) {
+		t.Errorf("custom manifest not updated: %s", manifest)
+	}
+	defaultManifest, err := os.ReadFile(filepath.Join(dir, "release-please-manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(defaultManifest), `"0.1.0"`) {
+		t.Errorf("default manifest should be untouched: %s", defaultManifest)
+	}
+}
+
+func TestApply_RootPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			".": {
+				"component": "monorepo",
+				"exclude-paths": ["docs"]
+			},
+			"workloads/service-a": {
+				"component": "service-a"
+			}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		".": "1.0.0",
+		"workloads/service-a": "0.1.0"
+	}`)
+	writeFile(t, dir, "VERSION", "1.0.0\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add root component")
+
+	writeFile(t, dir, "scripts/build.sh", "#!/bin/sh\n")
+	writeFile(t, dir, "docs/guide.md", "# Guide\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat: add build script")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].Package.Component != "monorepo" {
+		t.Fatalf("expected only the root component to release, got %+v", result.Releases)
+	}
+	if result.Releases[0].NewVersion != "1.1.0" {
+		t.Errorf("expected 1.1.0, got %s", result.Releases[0].NewVersion)
+	}
+	if len(result.Stats.OrphanedDirs) != 1 || result.Stats.OrphanedDirs[0] != "docs" {
+		t.Errorf("expected excluded docs to be unmatched, got %v", result.Stats.OrphanedDirs)
+	}
+
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	versionContent, err := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(versionContent) != "1.1.0\n" {
+		t.Errorf("root VERSION not updated: %q", versionContent)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "release-please-manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(manifest), `".": "1.1.0"`) || !contains(string(manifest), `"0.1.0"`) {
+		t.Errorf("manifest not updated correctly: %s", manifest)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CHANGELOG.md")); err != nil {
+		t.Errorf("expected root CHANGELOG.md: %v", err)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsHelper(s, substr))
 }
@@ -694,3 +1379,220 @@ func TestAnalyze_Stats_OrphanedDirsSorted(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateRelease_PrereleaseSemantics(t *testing.T) {
+	tests := []struct {
+		name          string
+		semantics     string
+		current       string
+		bump          version.BumpType
+		treatPreMajor bool
+		want          string
+	}{
+		{"default breaking", "", "0.3.1", version.Major, true, "1.0.0"},
+		{"default feat", "", "0.3.1", version.Minor, true, "0.3.2"},
+		{"standard feat", "", "0.3.1", version.Minor, false, "0.4.0"},
+		{"strict-zero breaking", config.PrereleaseStrictZero, "0.3.1", version.Major, true, "0.4.0"},
+		{"strict-zero feat", config.PrereleaseStrictZero, "0.3.1", version.Minor, false, "0.3.2"},
+		{"strict-zero fix", config.PrereleaseStrictZero, "0.3.1", version.Patch, true, "0.3.2"},
+		{"strict-zero after 1.0", config.PrereleaseStrictZero, "1.2.0", version.Major, true, "2.0.0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg := &config.Package{Component: "api", CurrentVersion: tc.current, PrereleaseSemantics: tc.semantics}
+			commits := []*git.Commit{{SHA: "abc", Type: "feat"}}
+
+			rel, err := createRelease(pkg, commits, tc.bump, tc.treatPreMajor)
+			if err != nil {
+				t.Fatalf("createRelease failed: %v", err)
+			}
+			if rel.NewVersion != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, rel.NewVersion)
+			}
+			if rel.BumpType != tc.bump {
+				t.Errorf("expected the commits' bump %v reported, got %v", tc.bump, rel.BumpType)
+			}
+		})
+	}
+}
+
+func TestAnalyze_PathScope(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+
+	result, err := Analyze(&Options{RepoPath: dir, PathScope: "workloads/service-b/..."})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].Package.Component != "service-b" {
+		t.Fatalf("expected only service-b released, got %+v", result.Releases)
+	}
+	if len(result.Config.Packages) != 1 {
+		t.Errorf("expected the config scoped to service-b, got %d packages", len(result.Config.Packages))
+	}
+
+	_, err = Analyze(&Options{RepoPath: dir, PathScope: "workloads/service-c"})
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Errorf("expected a config error for a scope without packages, got %v", err)
+	}
+}
+
+func TestAnalyze_CommitCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	path := filepath.Join(dir, ".git", git.CacheFile)
+
+	if _, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, NoCache: true}); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no cache with NoCache, got %v", err)
+	}
+
+	want, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the commit cache written: %v", err)
+	}
+
+	got, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(got.Releases) != len(want.Releases) || len(got.Commits) != len(want.Commits) {
+		t.Fatalf("expected the cached run to match, got %d release(s) and %d commit(s), want %d and %d",
+			len(got.Releases), len(got.Commits), len(want.Releases), len(want.Commits))
+	}
+	for i, c := range got.Commits {
+		if strings.Join(c.Files, ",") != strings.Join(want.Commits[i].Files, ",") {
+			t.Errorf("%s: expected files %v from the cache, got %v", c.ShortSHA, want.Commits[i].Files, c.Files)
+		}
+	}
+}
+
+func TestPlanChanges_WindowsFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{"packages": {"workloads\\service-a\\": {"component": "service-a"}}}`)
+	writeFile(t, dir, "release-please-manifest.json", "{\r\n  \"workloads/service-a\":\r\n    \"0.1.0\"\r\n}\r\n")
+	writeFile(t, dir, "workloads/service-a/VERSION", "0.1.0 # x-release-please-version\r\n")
+	writeFile(t, dir, "workloads/service-a/CHANGELOG.md", "# Changelog\r\n\r\n## [0.1.0] - Initial\r\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: write files on windows")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\r\n// Fix\r\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.1" {
+		t.Fatalf("expected service-a 0.1.1, got %+v", result.Releases)
+	}
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, c := range changes {
+		got[filepath.ToSlash(c.Path)] = c.New
+	}
+	if want := "{\r\n  \"workloads/service-a\":\r\n    \"0.1.1\"\r\n}\r\n"; got["release-please-manifest.json"] != want {
+		t.Errorf("manifest = %q, want %q", got["release-please-manifest.json"], want)
+	}
+	if want := "0.1.1 # x-release-please-version\r\n"; got["workloads/service-a/VERSION"] != want {
+		t.Errorf("VERSION = %q, want %q", got["workloads/service-a/VERSION"], want)
+	}
+	if changelog := got["workloads/service-a/CHANGELOG.md"]; !strings.Contains(changelog, "## [0.1.1]") || strings.Count(changelog, "\n") != strings.Count(changelog, "\r\n") {
+		t.Errorf("expected a CRLF changelog with the new entry, got %q", changelog)
+	}
+}
+
+func TestAnalyze_FirstRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"initial-version": "1.0.0",
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.0.0",
+		"workloads/service-b": "0.2.0"
+	}`)
+	writeFile(t, dir, "workloads/service-a/main.go", "// a\n")
+	writeFile(t, dir, "workloads/service-b/main.go", "// b\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	writeFile(t, dir, "workloads/service-a/main.go", "// a\n// fix\n")
+	writeFile(t, dir, "workloads/service-b/main.go", "// b\n// fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: handle empty input")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	releases := make(map[string]*PackageRelease)
+	for _, rel := range result.Releases {
+		releases[rel.Package.Component] = rel
+	}
+
+	first := releases["service-a"]
+	if first == nil || !first.FirstRelease || first.NewVersion != "1.0.0" {
+		t.Fatalf("expected service-a's first release at 1.0.0, got %+v", first)
+	}
+	if later := releases["service-b"]; later == nil || later.FirstRelease || later.NewVersion != "0.2.1" {
+		t.Fatalf("expected service-b bumped to 0.2.1, got %+v", later)
+	}
+
+	repoURL := "https://github.com/owner/repo"
+	if first.compareURL(repoURL) != "" {
+		t.Error("expected no compare link for a first release")
+	}
+	notes := BuildReleaseNotes(first, repoURL)
+	if !strings.Contains(notes, changelog.InitialReleaseNote) || strings.Contains(notes, "/compare/") {
+		t.Errorf("expected initial release notes without a compare link, got:\n%s", notes)
+	}
+
+	report := BuildReleaseReport(result, repoURL)
+	for _, comp := range report.Releases {
+		if comp.FirstRelease != (comp.Component == "service-a") {
+			t.Errorf("expected first_release only on service-a, got %s: %v", comp.Component, comp.FirstRelease)
+		}
+		if comp.FirstRelease && (comp.PreviousTagName != "" || comp.CompareURL != "") {
+			t.Errorf("expected no previous tag or compare URL for a first release, got %+v", comp)
+		}
+	}
+
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+	for _, change := range changes {
+		if change.Path == "workloads/service-a/CHANGELOG.md" && !strings.Contains(change.New, "Initial release.") {
+			t.Errorf("expected an initial release changelog entry, got:\n%s", change.New)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// approvalPollInterval is how often WaitForApproval checks the issue. It's a
+// variable so tests don't have to wait.
+var approvalPollInterval = 30 * time.Second
+
+// maxApprovalTitle bounds the approval issue title, which lists the releases.
+const maxApprovalTitle = 200
+
+// approvalAssociations are the author associations that may approve when the
+// config names no approvers.
+var approvalAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// ApprovalRequest is the tracking issue a set of releases waits on.
+type ApprovalRequest struct {
+	// Number and URL identify the issue.
+	Number int
+	URL    string
+
+	// Approved reports whether an approver has commented the approval
+	// command, and ApprovedBy who did.
+	Approved   bool
+	ApprovedBy string
+}
+
+type approvalIssue struct {
+	Number      int             `json:"number"`
+	HTMLURL     string          `json:"html_url"`
+	Body        string          `json:"body"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type approvalComment struct {
+	Body              string `json:"body"`
+	AuthorAssociation string `json:"author_association"`
+	User              struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// approvalMarker identifies the issue for a set of releases: the same
+// planned releases always get the same marker, so later runs find it. It
+// includes a hash of each release's commits, so commits landing after the
+// approval need a new one even if the versions don't change.
+func approvalMarker(result *AnalysisResult) string {
+	names := make([]string, 0, len(result.Releases))
+	contents := make([]string, 0, len(result.Releases))
+	for _, rel := range result.Releases {
+		tag := buildTagName(rel.Package.Component, rel.NewVersion)
+		names = append(names, tag)
+		shas := make([]string, 0, len(rel.Commits))
+		for _, c := range rel.Commits {
+			shas = append(shas, c.SHA)
+		}
+		slices.Sort(shas)
+		contents = append(contents, tag+":"+strings.Join(shas, ","))
+	}
+	slices.Sort(names)
+	slices.Sort(contents)
+	sum := sha256.Sum256([]byte(strings.Join(contents, "\n")))
+	return fmt.Sprintf("<!-- release-damnit-approval: %s (commits %x) -->", strings.Join(names, " "), sum[:6])
+}
+
+// approvalTitle returns the approval issue title, e.g. "Release approval:
+// api 1.1.0, web 0.3.0".
+func approvalTitle(result *AnalysisResult) string {
+	names := make([]string, 0, len(result.Releases))
+	for _, rel := range result.Releases {
+		names = append(names, rel.Package.Component+" "+rel.NewVersion)
+	}
+	title := "Release approval: " + strings.Join(names, ", ")
+	if len(title) > maxApprovalTitle {
+		title = fmt.Sprintf("Release approval: %d releases", len(names))
+	}
+	return title
+}
+
+// approvalBody renders the approval issue body: the releases and how to
+// approve them.
+func approvalBody(result *AnalysisResult, approval *config.Approval) string {
+	var b strings.Builder
+	b.WriteString(approvalMarker(result) + "\n")
+	b.WriteString("These releases are waiting for approval:\n\n")
+	b.WriteString("| Component | Bump | Version | Commits |\n")
+	b.WriteString("|-----------|------|---------|---------|\n")
+	for _, rel := range result.Releases {
+		fmt.Fprintf(&b, "| %s | %s | %s → %s | %d |\n", rel.Package.Component, rel.BumpType, rel.OldVersion, rel.NewVersion, len(rel.Commits))
+	}
+	if result.MergeInfo != nil && result.MergeInfo.HeadSHA != "" {
+		fmt.Fprintf(&b, "\nAnalyzed at `%s`.\n", shortSHA(result.MergeInfo.HeadSHA))
+	}
+	fmt.Fprintf(&b, "\nComment `%s` to approve", approval.Command)
+	if len(approval.Approvers) > 0 {
+		fmt.Fprintf(&b, " (approvers: %s)", strings.Join(approval.Approvers, ", "))
+	}
+	b.WriteString(". A run waiting for approval picks it up; otherwise run the release again.\n")
+	return b.String()
+}
+
+// RequestApproval finds the open approval issue for the result's releases,
+// opening one if there's none, and reports whether it's been approved.
+func RequestApproval(repoPath string, result *AnalysisResult) (*ApprovalRequest, error) {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotNil(result.Config.Approval, "result.Config.Approval")
+	approval := result.Config.Approval
+
+	issue, err := findApprovalIssue(repoPath, approval.Label, approvalMarker(result))
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		out, err := ghAPI(repoPath, "--method", "POST", "repos/{owner}/{repo}/issues",
+			"-f", "title="+approvalTitle(result),
+			"-f", "body="+approvalBody(result, approval),
+			"-f", "labels[]="+approval.Label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open approval issue: %w", err)
+		}
+		issue = &approvalIssue{}
+		if err := json.Unmarshal(out, issue); err != nil {
+			return nil, fmt.Errorf("failed to parse approval issue: %w", err)
+		}
+		slog.Info("opened approval issue", "number", issue.Number, "url", issue.HTMLURL)
+		return &ApprovalRequest{Number: issue.Number, URL: issue.HTMLURL}, nil
+	}
+
+	req := &ApprovalRequest{Number: issue.Number, URL: issue.HTMLURL}
+	comments, err := listApprovalComments(repoPath, issue.Number)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		if isApproval(approval, c) {
+			req.Approved, req.ApprovedBy = true, c.User.Login
+			break
+		}
+	}
+	return req, nil
+}
+
+// WaitForApproval checks the approval issue until the releases are approved
+// or timeout passes. A zero timeout checks once. The request is returned
+// either way; check Approved.
+func WaitForApproval(repoPath string, result *AnalysisResult, timeout time.Duration) (*ApprovalRequest, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		req, err := RequestApproval(repoPath, result)
+		if err != nil || req.Approved || !time.Now().Add(approvalPollInterval).Before(deadline) {
+			return req, err
+		}
+		slog.Info("waiting for approval", "url", req.URL)
+		time.Sleep(approvalPollInterval)
+	}
+}
+
+// CloseApproval notes on the approval issue that the releases were applied
+// and closes it.
+func CloseApproval(repoPath string, req *ApprovalRequest) error {
+	endpoint := fmt.Sprintf("repos/{owner}/{repo}/issues/%d", req.Number)
+	if _, err := ghAPI(repoPath, "--method", "POST", endpoint+"/comments", "-f", "body=Released."); err != nil {
+		return fmt.Errorf("failed to comment on approval issue #%d: %w", req.Number, err)
+	}
+	if _, err := ghAPI(repoPath, "--method", "PATCH", endpoint, "-f", "state=closed"); err != nil {
+		return fmt.Errorf("failed to close approval issue #%d: %w", req.Number, err)
+	}
+	return nil
+}
+
+// findApprovalIssue returns the open issue with label whose body has the
+// marker, or nil.
+func findApprovalIssue(repoPath, label, marker string) (*approvalIssue, error) {
+	out, err := ghAPI(repoPath, "--paginate", fmt.Sprintf("repos/{owner}/{repo}/issues?state=open&labels=%s&per_page=100", url.QueryEscape(label)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval issues: %w", err)
+	}
+
+	// --paginate concatenates one JSON array per page
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for dec.More() {
+		var page []*approvalIssue
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to parse approval issues: %w", err)
+		}
+		for _, issue := range page {
+			// The issues API lists pull requests too
+			if issue.PullRequest == nil && strings.Contains(issue.Body, marker) {
+				return issue, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// listApprovalComments returns the comments on an approval issue.
+func listApprovalComments(repoPath string, number int) ([]*approvalComment, error) {
+	out, err := ghAPI(repoPath, "--paginate", fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments?per_page=100", number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments on approval issue #%d: %w", number, err)
+	}
+
+	var comments []*approvalComment
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for dec.More() {
+		var page []*approvalComment
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to parse approval comments: %w", err)
+		}
+		comments = append(comments, page...)
+	}
+	return comments, nil
+}
+
+// isApproval reports whether a comment approves: its first line is the
+// approval command and its author may approve.
+func isApproval(approval *config.Approval, c *approvalComment) bool {
+	line, _, _ := strings.Cut(strings.TrimSpace(c.Body), "\n")
+	if !strings.EqualFold(strings.TrimSpace(line), approval.Command) {
+		return false
+	}
+	if len(approval.Approvers) == 0 {
+		return slices.Contains(approvalAssociations, c.AuthorAssociation)
+	}
+	return slices.ContainsFunc(approval.Approvers, func(login string) bool {
+		return strings.EqualFold(login, c.User.Login)
+	})
+}
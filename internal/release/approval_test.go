@@ -0,0 +1,173 @@
+package release
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// approvalResult is checkRunResult with an approval section.
+func approvalResult(approvers ...string) *AnalysisResult {
+	result := checkRunResult()
+	result.Config.Approval = &config.Approval{
+		Label:     config.DefaultApprovalLabel,
+		Command:   config.DefaultApprovalCommand,
+		Approvers: approvers,
+	}
+	return result
+}
+
+// approvalIssueJSON lists the open approval issue for approvalResult.
+func approvalIssueJSON() string {
+	return `[{"number": 7, "html_url": "https://github.com/o/r/issues/7", "body": "` + approvalMarker(approvalResult()) + `\nThese releases..."}]`
+}
+
+func TestRequestApproval_OpensIssue(t *testing.T) {
+	var created []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		switch {
+		case args[0] == "--paginate":
+			if !strings.Contains(args[1], "labels=release-approval") {
+				t.Errorf("expected issues listed by label, got %q", args[1])
+			}
+			// A pull request and another release's issue don't count
+			return []byte(`[{"number": 3, "body": "<!-- release-damnit-approval: api-v1.1.0 -->", "pull_request": {}}, {"number": 5, "body": "<!-- release-damnit-approval: api-v1.0.9 -->"}]`), nil
+		case args[0] == "--method" && args[1] == "POST":
+			created = args
+			return []byte(`{"number": 8, "html_url": "https://github.com/o/r/issues/8"}`), nil
+		}
+		t.Fatalf("unexpected call %v", args)
+		return nil, nil
+	})
+
+	req, err := RequestApproval("", approvalResult())
+	if err != nil {
+		t.Fatalf("RequestApproval failed: %v", err)
+	}
+	if req.Number != 8 || req.Approved {
+		t.Errorf("expected a new, unapproved issue, got %+v", req)
+	}
+	joined := strings.Join(created, "\n")
+	for _, want := range []string{"title=Release approval: api 1.1.0", "<!-- release-damnit-approval: api-v1.1.0 (commits ", "| api | minor | 1.0.0 → 1.1.0 | 1 |", "Comment `/approve`", "labels[]=release-approval"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %q in the new issue, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestRequestApproval_Comments(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvers []string
+		comments  string
+		want      string
+	}{
+		{"no comments", nil, `[]`, ""},
+		{"collaborator", nil, `[{"body": " /Approve \nship it", "author_association": "COLLABORATOR", "user": {"login": "carol"}}]`, "carol"},
+		{"outsider", nil, `[{"body": "/approve", "author_association": "NONE", "user": {"login": "mallory"}}]`, ""},
+		{"other text", nil, `[{"body": "please /approve", "author_association": "OWNER", "user": {"login": "olive"}}]`, ""},
+		{"named approver", []string{"Alice"}, `[{"body": "/approve", "author_association": "OWNER", "user": {"login": "olive"}}, {"body": "/approve", "author_association": "NONE", "user": {"login": "alice"}}]`, "alice"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stubGHAPI(t, func(args ...string) ([]byte, error) {
+				if strings.Contains(args[1], "/comments") {
+					return []byte(tc.comments), nil
+				}
+				return []byte(approvalIssueJSON()), nil
+			})
+
+			req, err := RequestApproval("", approvalResult(tc.approvers...))
+			if err != nil {
+				t.Fatalf("RequestApproval failed: %v", err)
+			}
+			if req.Number != 7 || req.Approved != (tc.want != "") || req.ApprovedBy != tc.want {
+				t.Errorf("expected approval by %q, got %+v", tc.want, req)
+			}
+		})
+	}
+}
+
+func TestWaitForApproval(t *testing.T) {
+	orig := approvalPollInterval
+	approvalPollInterval = 0
+	t.Cleanup(func() { approvalPollInterval = orig })
+
+	checks := 0
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		if !strings.Contains(args[1], "/comments") {
+			return []byte(approvalIssueJSON()), nil
+		}
+		checks++
+		if checks < 3 {
+			return []byte(`[]`), nil
+		}
+		return []byte(`[{"body": "/approve", "author_association": "MEMBER", "user": {"login": "mo"}}]`), nil
+	})
+
+	req, err := WaitForApproval("", approvalResult(), time.Minute)
+	if err != nil {
+		t.Fatalf("WaitForApproval failed: %v", err)
+	}
+	if !req.Approved || checks != 3 {
+		t.Errorf("expected approval on the third check, got %+v after %d", req, checks)
+	}
+
+	checks = -100
+	if req, err := WaitForApproval("", approvalResult(), 0); err != nil || req.Approved || checks != -99 {
+		t.Errorf("expected a single unapproved check without a timeout, got %+v, %v after %d", req, err, checks)
+	}
+}
+
+func TestCloseApproval(t *testing.T) {
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return []byte(`{}`), nil
+	})
+
+	if err := CloseApproval("", &ApprovalRequest{Number: 7}); err != nil {
+		t.Fatalf("CloseApproval failed: %v", err)
+	}
+	if len(calls) != 2 || !strings.Contains(calls[0], "issues/7/comments") || !strings.Contains(calls[1], "PATCH repos/{owner}/{repo}/issues/7 -f state=closed") {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestApprovalMarker(t *testing.T) {
+	marker := approvalMarker(approvalResult())
+	if !strings.HasPrefix(marker, "<!-- release-damnit-approval: api-v1.1.0 (commits ") {
+		t.Errorf("unexpected marker %q", marker)
+	}
+
+	// A commit landing after the approval needs a new one, even at the same version
+	result := approvalResult()
+	rel := *result.Releases[0]
+	rel.Commits = append(append([]*git.Commit{}, rel.Commits...), &git.Commit{SHA: "feedface"})
+	result.Releases = []*PackageRelease{&rel}
+	updated := approvalMarker(result)
+	if updated == marker {
+		t.Error("expected another commit to change the marker")
+	}
+
+	// The order the commits were found in doesn't matter
+	slices.Reverse(rel.Commits)
+	if other := approvalMarker(result); other != updated {
+		t.Errorf("expected a stable marker, got %q and %q", updated, other)
+	}
+}
+
+func TestApprovalTitle_Long(t *testing.T) {
+	result := checkRunResult()
+	for i := 0; i < 20; i++ {
+		result.Releases = append(result.Releases, result.Releases[0])
+	}
+	if got := approvalTitle(result); got != "Release approval: 21 releases" {
+		t.Errorf("expected a count for long titles, got %q", got)
+	}
+}
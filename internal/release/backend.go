@@ -0,0 +1,552 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// GitHub backends for SelectGitHubBackend.
+const (
+	// GitHubBackendAuto uses the API if a token is set, and the gh CLI if
+	// not but gh is authenticated.
+	GitHubBackendAuto = "auto"
+
+	// GitHubBackendAPI calls the GitHub REST API with the token from
+	// GITHUB_TOKEN or GH_TOKEN.
+	GitHubBackendAPI = "api"
+
+	// GitHubBackendGH runs the gh CLI with its own authentication.
+	GitHubBackendGH = "gh"
+)
+
+// DefaultGitHubAPIURL is the REST API used when GITHUB_API_URL isn't set.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// tokenEnvVars are the environment variables a GitHub token is read from,
+// in order of preference.
+var tokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// githubBackend carries out GitHub requests for releases, milestones,
+// approvals, check runs, and API commits.
+type githubBackend interface {
+	// api performs a request given as `gh api` arguments (see ghAPI).
+	api(repoPath string, args ...string) ([]byte, error)
+
+	// apiInput performs a request like api, with input as its JSON body
+	// instead of -f/-F fields (`gh api --input -`), for bodies too large
+	// for the command line.
+	apiInput(repoPath string, input []byte, args ...string) ([]byte, error)
+
+	// createRelease creates a GitHub release and uploads its assets.
+	createRelease(repoPath string, ghRelease *GitHubRelease) error
+}
+
+// activeGitHubBackend is the backend chosen by SelectGitHubBackend, or nil
+// to choose one automatically on first use.
+var activeGitHubBackend githubBackend
+
+// currentGitHubBackend returns the selected backend, selecting one
+// automatically if none was.
+func currentGitHubBackend() (githubBackend, error) {
+	if activeGitHubBackend == nil {
+		if _, err := SelectGitHubBackend(GitHubBackendAuto, DetectGitHubCredentials()); err != nil {
+			return nil, err
+		}
+	}
+	return activeGitHubBackend, nil
+}
+
+// GitHubCredentials are the GitHub credentials found in the environment.
+type GitHubCredentials struct {
+	// TokenEnv is the environment variable the token was read from, or ""
+	// if none of GITHUB_TOKEN and GH_TOKEN is set.
+	TokenEnv string
+
+	// GHPath is the gh binary found on PATH, or "" if there is none.
+	GHPath string
+
+	// GHAuthError is why `gh auth status` failed, or nil if gh is
+	// authenticated (or missing).
+	GHAuthError error
+
+	token string
+}
+
+// lookPathGH and ghAuthStatus find and check the gh CLI. They're variables
+// so tests don't depend on gh being installed.
+var (
+	lookPathGH = func() (string, error) {
+		return exec.LookPath("gh")
+	}
+	ghAuthStatus = func(ghPath string) error {
+		out, err := exec.Command(ghPath, "auth", "status").CombinedOutput()
+		if err != nil {
+			if msg := joinLines(string(out)); msg != "" {
+				return fmt.Errorf("%s", msg)
+			}
+			return err
+		}
+		return nil
+	}
+)
+
+// DetectGitHubCredentials looks for a token in GITHUB_TOKEN and GH_TOKEN,
+// and for an authenticated gh CLI.
+func DetectGitHubCredentials() *GitHubCredentials {
+	creds := &GitHubCredentials{}
+	for _, name := range tokenEnvVars {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			creds.TokenEnv, creds.token = name, token
+			break
+		}
+	}
+	if path, err := lookPathGH(); err == nil {
+		creds.GHPath = path
+		creds.GHAuthError = ghAuthStatus(path)
+	}
+	return creds
+}
+
+// HasToken reports whether a GitHub token was found.
+func (c *GitHubCredentials) HasToken() bool {
+	return c.token != ""
+}
+
+// GHAuthenticated reports whether gh is installed and authenticated.
+func (c *GitHubCredentials) GHAuthenticated() bool {
+	return c.GHPath != "" && c.GHAuthError == nil
+}
+
+// String lists what was found, e.g. "GITHUB_TOKEN not set, GH_TOKEN not
+// set, gh not found on PATH".
+func (c *GitHubCredentials) String() string {
+	var found []string
+	for _, name := range tokenEnvVars {
+		switch {
+		case name == c.TokenEnv:
+			found = append(found, name+" set")
+		case c.TokenEnv == "":
+			found = append(found, name+" not set")
+		}
+	}
+	switch {
+	case c.GHPath == "":
+		found = append(found, "gh not found on PATH")
+	case c.GHAuthError != nil:
+		found = append(found, fmt.Sprintf("gh at %s not authenticated (%v)", c.GHPath, c.GHAuthError))
+	default:
+		found = append(found, fmt.Sprintf("gh at %s authenticated", c.GHPath))
+	}
+	return strings.Join(found, ", ")
+}
+
+// SelectGitHubBackend picks the backend for GitHub requests (GitHubBackendAuto,
+// GitHubBackendAPI, or GitHubBackendGH) and returns the one in use. The
+// error lists the credentials found, so a missing token or gh shows up
+// before anything is written rather than at the first request.
+func SelectGitHubBackend(backend string, creds *GitHubCredentials) (string, error) {
+	switch backend {
+	case GitHubBackendAuto, "":
+		if creds.HasToken() {
+			return useAPIBackend(creds), nil
+		}
+		if creds.GHAuthenticated() {
+			return useGHBackend(creds), nil
+		}
+		return "", fmt.Errorf("no GitHub credentials: %s; set GITHUB_TOKEN or run 'gh auth login'", creds)
+	case GitHubBackendAPI:
+		if !creds.HasToken() {
+			return "", fmt.Errorf("the %s backend needs a token: %s; set GITHUB_TOKEN", GitHubBackendAPI, creds)
+		}
+		return useAPIBackend(creds), nil
+	case GitHubBackendGH:
+		if !creds.GHAuthenticated() {
+			return "", fmt.Errorf("the %s backend needs an authenticated gh CLI: %s; install gh and run 'gh auth login'", GitHubBackendGH, creds)
+		}
+		return useGHBackend(creds), nil
+	default:
+		return "", ValidateGitHubBackend(backend)
+	}
+}
+
+// ValidateGitHubBackend checks a --github-backend value.
+func ValidateGitHubBackend(backend string) error {
+	switch backend {
+	case GitHubBackendAuto, GitHubBackendAPI, GitHubBackendGH:
+		return nil
+	}
+	return fmt.Errorf("invalid GitHub backend %q: must be %s, %s, or %s", backend, GitHubBackendAuto, GitHubBackendAPI, GitHubBackendGH)
+}
+
+func useAPIBackend(creds *GitHubCredentials) string {
+	baseURL := strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/")
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	activeGitHubBackend = &apiBackend{
+		baseURL: baseURL,
+		token:   creds.token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	return GitHubBackendAPI
+}
+
+func useGHBackend(creds *GitHubCredentials) string {
+	activeGitHubBackend = &ghBackend{path: creds.GHPath}
+	return GitHubBackendGH
+}
+
+// ghBackend runs the gh CLI.
+type ghBackend struct {
+	path string
+}
+
+func (b *ghBackend) api(repoPath string, args ...string) ([]byte, error) {
+	return b.apiInput(repoPath, nil, args...)
+}
+
+func (b *ghBackend) apiInput(repoPath string, input []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(b.path, append([]string{"api"}, args...)...)
+	if input != nil {
+		cmd.Args = append(cmd.Args, "--input", "-")
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	if repoPath != "" {
+		cmd.Dir = repoPath
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return out, fmt.Errorf("gh api %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+func (b *ghBackend) createRelease(repoPath string, ghRelease *GitHubRelease) error {
+	cmd := exec.Command(b.path, ghRelease.CreateArgs()...)
+	if repoPath != "" {
+		cmd.Dir = repoPath
+	}
+	cmd.Stdin = strings.NewReader(ghRelease.Notes)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// apiBackend calls the GitHub REST API with a token.
+type apiBackend struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// linkNextRegex finds the next page in a Link header.
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// api performs a request given as `gh api` arguments: an endpoint (with
+// {owner} and {repo} placeholders), --method, --paginate, and -f/-F fields,
+// whose keys may nest (output[title], parents[], tree[][path]). As with gh,
+// --paginate concatenates the pages' JSON.
+func (b *apiBackend) api(repoPath string, args ...string) ([]byte, error) {
+	return b.apiInput(repoPath, nil, args...)
+}
+
+func (b *apiBackend) apiInput(repoPath string, input []byte, args ...string) ([]byte, error) {
+	req, err := parseAPIArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := expandRepoPlaceholders(repoPath, req.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	target := b.baseURL + "/" + strings.TrimPrefix(endpoint, "/")
+	var body io.Reader
+	if input != nil {
+		body = bytes.NewReader(input)
+	} else if req.method == http.MethodGet {
+		if len(req.query) > 0 {
+			sep := "?"
+			if strings.Contains(target, "?") {
+				sep = "&"
+			}
+			target += sep + req.query.Encode()
+		}
+	} else if req.fields != nil {
+		data, err := json.Marshal(req.fields)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	var out []byte
+	for target != "" {
+		data, next, err := b.do(req.method, target, "application/json", body)
+		if err != nil {
+			return out, fmt.Errorf("GitHub API %s %s failed: %w", req.method, endpoint, err)
+		}
+		out = append(out, data...)
+		if !req.paginate {
+			break
+		}
+		target = next
+	}
+	return out, nil
+}
+
+func (b *apiBackend) createRelease(repoPath string, ghRelease *GitHubRelease) error {
+	args := []string{"--method", http.MethodPost, "repos/{owner}/{repo}/releases",
+		"-f", "tag_name=" + ghRelease.TagName,
+		"-f", "name=" + ghRelease.Title,
+		"-f", "body=" + ghRelease.Notes,
+	}
+	if ghRelease.TargetSHA != "" {
+		args = append(args, "-f", "target_commitish="+ghRelease.TargetSHA)
+	}
+	if ghRelease.DiscussionCategory != "" {
+		args = append(args, "-f", "discussion_category_name="+ghRelease.DiscussionCategory)
+	}
+	out, err := b.api(repoPath, args...)
+	if err != nil {
+		return err
+	}
+	var created struct {
+		UploadURL string `json:"upload_url"`
+	}
+	if err := json.Unmarshal(out, &created); err != nil {
+		return fmt.Errorf("failed to parse release: %w", err)
+	}
+
+	// upload_url is a URI template, e.g. ".../assets{?name,label}"
+	uploadURL, _, _ := strings.Cut(created.UploadURL, "{")
+	for _, asset := range ghRelease.Assets {
+		data, err := os.ReadFile(asset)
+		if err != nil {
+			return fmt.Errorf("failed to read asset: %w", err)
+		}
+		target := uploadURL + "?name=" + url.QueryEscape(filepath.Base(asset))
+		if _, _, err := b.do(http.MethodPost, target, "application/octet-stream", bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", filepath.Base(asset), err)
+		}
+	}
+	return nil
+}
+
+// do sends one request and returns the response body and the next page's
+// URL, if any.
+func (b *apiBackend) do(method, target, contentType string, body io.Reader) ([]byte, string, error) {
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return data, "", fmt.Errorf("%s: %s", resp.Status, apiErr.Message)
+		}
+		return data, "", fmt.Errorf("%s", resp.Status)
+	}
+
+	var next string
+	if m := linkNextRegex.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	return data, next, nil
+}
+
+// apiRequest is a request parsed from `gh api` arguments.
+type apiRequest struct {
+	method   string
+	endpoint string
+	paginate bool
+
+	// fields is the JSON body built from -f/-F; query holds the same fields
+	// for GET requests.
+	fields map[string]interface{}
+	query  url.Values
+}
+
+// parseAPIArgs parses the `gh api` arguments ghAPI is called with. Like gh,
+// the method defaults to POST when there are fields and GET otherwise.
+func parseAPIArgs(args []string) (*apiRequest, error) {
+	req := &apiRequest{query: url.Values{}}
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--method", "-X", "-f", "--raw-field", "-F", "--field":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("gh api argument %s needs a value", arg)
+			}
+			i++
+			if arg == "--method" || arg == "-X" {
+				req.method = strings.ToUpper(args[i])
+				continue
+			}
+			key, raw, ok := strings.Cut(args[i], "=")
+			if !ok {
+				return nil, fmt.Errorf("gh api field %q must be key=value", args[i])
+			}
+			var value interface{} = raw
+			if arg == "-F" || arg == "--field" {
+				value = typedFieldValue(raw)
+			}
+			if req.fields == nil {
+				req.fields = make(map[string]interface{})
+			}
+			if err := setField(req.fields, key, value); err != nil {
+				return nil, err
+			}
+			req.query.Add(key, raw)
+		case "--paginate":
+			req.paginate = true
+		default:
+			if strings.HasPrefix(arg, "-") || req.endpoint != "" {
+				return nil, fmt.Errorf("unsupported gh api argument %q", arg)
+			}
+			req.endpoint = arg
+		}
+	}
+	if req.endpoint == "" {
+		return nil, fmt.Errorf("gh api arguments have no endpoint")
+	}
+	if req.method == "" {
+		req.method = http.MethodGet
+		if req.fields != nil {
+			req.method = http.MethodPost
+		}
+	}
+	return req, nil
+}
+
+// typedFieldValue converts a -F value the way gh does: true, false, null,
+// and integers become JSON literals.
+func typedFieldValue(raw string) interface{} {
+	switch raw {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		return n
+	}
+	return raw
+}
+
+// setField sets a field in body by its gh key: "name", "output[title]",
+// "parents[]" (appends), or "tree[][path]" (sets path on the last object in
+// tree, starting a new object if it already has one).
+func setField(body map[string]interface{}, key string, value interface{}) error {
+	name, rest, nested := strings.Cut(key, "[")
+	if !nested {
+		body[key] = value
+		return nil
+	}
+	sub, rest, ok := strings.Cut(rest, "]")
+	if !ok {
+		return fmt.Errorf("invalid gh api field key %q", key)
+	}
+
+	if sub != "" {
+		child, _ := body[name].(map[string]interface{})
+		if child == nil {
+			child = make(map[string]interface{})
+			body[name] = child
+		}
+		return setField(child, sub+rest, value)
+	}
+
+	list, _ := body[name].([]interface{})
+	if rest == "" {
+		body[name] = append(list, value)
+		return nil
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return fmt.Errorf("invalid gh api field key %q", key)
+	}
+	field := rest[1 : len(rest)-1]
+	var last map[string]interface{}
+	if len(list) > 0 {
+		last, _ = list[len(list)-1].(map[string]interface{})
+	}
+	if _, taken := last[field]; last == nil || taken {
+		last = make(map[string]interface{})
+		list = append(list, last)
+	}
+	last[field] = value
+	body[name] = list
+	return nil
+}
+
+// expandRepoPlaceholders fills {owner} and {repo} in an endpoint from
+// GITHUB_REPOSITORY, or the origin remote of the repository at repoPath.
+func expandRepoPlaceholders(repoPath, endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "{owner}") && !strings.Contains(endpoint, "{repo}") {
+		return endpoint, nil
+	}
+	slug := os.Getenv("GITHUB_REPOSITORY")
+	if slug == "" {
+		remoteURL, err := git.RemoteURL(repoPath, DefaultRemote)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the GitHub repository: set GITHUB_REPOSITORY or add an %s remote: %w", DefaultRemote, err)
+		}
+		if repoURL := RepoURLFromRemote(remoteURL); repoURL != "" {
+			if u, err := url.Parse(repoURL); err == nil {
+				slug = strings.Trim(u.Path, "/")
+			}
+		}
+	}
+	owner, repo, ok := strings.Cut(slug, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", fmt.Errorf("failed to find the GitHub repository: %q isn't owner/repo", slug)
+	}
+	endpoint = strings.ReplaceAll(endpoint, "{owner}", owner)
+	return strings.ReplaceAll(endpoint, "{repo}", repo), nil
+}
+
+// joinLines joins the non-empty lines of s, trimmed, with "; ".
+func joinLines(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,341 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeGH makes DetectGitHubCredentials see gh at path (or none, if path is
+// empty) with the given auth status, and restores the selected backend.
+func fakeGH(t *testing.T, path string, authErr error) {
+	t.Helper()
+	origLook, origAuth, origBackend := lookPathGH, ghAuthStatus, activeGitHubBackend
+	lookPathGH = func() (string, error) {
+		if path == "" {
+			return "", errors.New("not found")
+		}
+		return path, nil
+	}
+	ghAuthStatus = func(string) error { return authErr }
+	t.Cleanup(func() {
+		lookPathGH, ghAuthStatus, activeGitHubBackend = origLook, origAuth, origBackend
+	})
+}
+
+func TestDetectGitHubCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		githubToken string
+		ghToken     string
+		ghPath      string
+		ghAuthErr   error
+		want        string
+	}{
+		{
+			name: "nothing",
+			want: "GITHUB_TOKEN not set, GH_TOKEN not set, gh not found on PATH",
+		},
+		{
+			name:        "github token",
+			githubToken: "t1",
+			ghToken:     "t2",
+			want:        "GITHUB_TOKEN set, gh not found on PATH",
+		},
+		{
+			name:    "gh token",
+			ghToken: "t2",
+			ghPath:  "/usr/bin/gh",
+			want:    "GH_TOKEN set, gh at /usr/bin/gh authenticated",
+		},
+		{
+			name:      "gh logged out",
+			ghPath:    "/usr/bin/gh",
+			ghAuthErr: errors.New("You are not logged into any GitHub hosts"),
+			want:      "GITHUB_TOKEN not set, GH_TOKEN not set, gh at /usr/bin/gh not authenticated (You are not logged into any GitHub hosts)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGH(t, tt.ghPath, tt.ghAuthErr)
+			t.Setenv("GITHUB_TOKEN", tt.githubToken)
+			t.Setenv("GH_TOKEN", tt.ghToken)
+
+			creds := DetectGitHubCredentials()
+			if got := creds.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if creds.HasToken() != (tt.githubToken != "" || tt.ghToken != "") {
+				t.Errorf("HasToken() = %v", creds.HasToken())
+			}
+		})
+	}
+}
+
+func TestSelectGitHubBackend(t *testing.T) {
+	token := &GitHubCredentials{TokenEnv: "GITHUB_TOKEN", token: "t"}
+	gh := &GitHubCredentials{GHPath: "/usr/bin/gh"}
+	both := &GitHubCredentials{TokenEnv: "GITHUB_TOKEN", token: "t", GHPath: "/usr/bin/gh"}
+	loggedOut := &GitHubCredentials{GHPath: "/usr/bin/gh", GHAuthError: errors.New("not logged in")}
+
+	tests := []struct {
+		backend string
+		creds   *GitHubCredentials
+		want    string
+		wantErr string
+	}{
+		{GitHubBackendAuto, both, GitHubBackendAPI, ""},
+		{GitHubBackendAuto, token, GitHubBackendAPI, ""},
+		{GitHubBackendAuto, gh, GitHubBackendGH, ""},
+		{GitHubBackendAuto, loggedOut, "", "no GitHub credentials: GITHUB_TOKEN not set, GH_TOKEN not set, gh at /usr/bin/gh not authenticated (not logged in)"},
+		{GitHubBackendAPI, token, GitHubBackendAPI, ""},
+		{GitHubBackendAPI, gh, "", "the api backend needs a token"},
+		{GitHubBackendGH, both, GitHubBackendGH, ""},
+		{GitHubBackendGH, token, "", "gh not found on PATH"},
+		{"octokit", both, "", "invalid GitHub backend"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend+" "+tt.creds.String(), func(t *testing.T) {
+			fakeGH(t, "", nil)
+			activeGitHubBackend = nil
+
+			got, err := SelectGitHubBackend(tt.backend, tt.creds)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				if activeGitHubBackend != nil {
+					t.Error("expected no backend selected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectGitHubBackend failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+			switch activeGitHubBackend.(type) {
+			case *apiBackend:
+				if got != GitHubBackendAPI {
+					t.Errorf("expected the API backend active for %q", got)
+				}
+			case *ghBackend:
+				if got != GitHubBackendGH {
+					t.Errorf("expected the gh backend active for %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestGHAPI_NoCredentials(t *testing.T) {
+	fakeGH(t, "", nil)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	activeGitHubBackend = nil
+
+	_, err := ghAPI("", "repos/{owner}/{repo}/milestones")
+	if err == nil || !strings.Contains(err.Error(), "gh not found on PATH") {
+		t.Errorf("expected the credentials listed, got %v", err)
+	}
+}
+
+func TestParseAPIArgs(t *testing.T) {
+	req, err := parseAPIArgs([]string{
+		"--method", "POST", "repos/{owner}/{repo}/git/trees",
+		"-f", "base_tree=abc",
+		"-f", "tree[][path]=a.txt", "-f", "tree[][mode]=100644",
+		"-f", "tree[][path]=b.txt", "-f", "tree[][mode]=100644",
+		"-f", "parents[]=p1", "-f", "parents[]=p2",
+		"-f", "output[title]=Release",
+		"-F", "force=false", "-F", "number=7",
+	})
+	if err != nil {
+		t.Fatalf("parseAPIArgs failed: %v", err)
+	}
+	if req.method != "POST" || req.endpoint != "repos/{owner}/{repo}/git/trees" || req.paginate {
+		t.Errorf("unexpected request %+v", req)
+	}
+	want := map[string]interface{}{
+		"base_tree": "abc",
+		"tree": []interface{}{
+			map[string]interface{}{"path": "a.txt", "mode": "100644"},
+			map[string]interface{}{"path": "b.txt", "mode": "100644"},
+		},
+		"parents": []interface{}{"p1", "p2"},
+		"output":  map[string]interface{}{"title": "Release"},
+		"force":   false,
+		"number":  7,
+	}
+	if !reflect.DeepEqual(req.fields, want) {
+		t.Errorf("fields = %#v\nwant %#v", req.fields, want)
+	}
+
+	req, err = parseAPIArgs([]string{"--paginate", "repos/{owner}/{repo}/milestones?state=open"})
+	if err != nil {
+		t.Fatalf("parseAPIArgs failed: %v", err)
+	}
+	if req.method != "GET" || !req.paginate || req.fields != nil {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	if req, err := parseAPIArgs([]string{"repos/x/y/issues", "-f", "title=T"}); err != nil || req.method != "POST" {
+		t.Errorf("expected fields to default to POST, got %+v, %v", req, err)
+	}
+	for _, args := range [][]string{{}, {"--jq", ".x", "a"}, {"a", "-f"}, {"a", "-f", "novalue"}} {
+		if _, err := parseAPIArgs(args); err == nil {
+			t.Errorf("expected %q to fail", args)
+		}
+	}
+}
+
+func TestExpandRepoPlaceholders(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	got, err := expandRepoPlaceholders("", "repos/{owner}/{repo}/releases")
+	if err != nil || got != "repos/acme/app/releases" {
+		t.Errorf("got %q, %v", got, err)
+	}
+
+	dir := createTestRepo(t)
+	runCmd(t, dir, "git", "remote", "add", "origin", "git@github.com:octo/widgets.git")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	got, err = expandRepoPlaceholders(dir, "repos/{owner}/{repo}/releases")
+	if err != nil || got != "repos/octo/widgets/releases" {
+		t.Errorf("got %q, %v", got, err)
+	}
+
+	t.Setenv("GITHUB_REPOSITORY", "not-a-slug")
+	if _, err := expandRepoPlaceholders("", "repos/{owner}/{repo}"); err == nil {
+		t.Error("expected an invalid GITHUB_REPOSITORY to fail")
+	}
+}
+
+// newTestAPIBackend returns an API backend talking to handler, for the
+// acme/app repository.
+func newTestAPIBackend(t *testing.T, handler http.HandlerFunc) *apiBackend {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	return &apiBackend{baseURL: server.URL, token: "secret", client: server.Client()}
+}
+
+func TestAPIBackend_Paginate(t *testing.T) {
+	var server string
+	b := newTestAPIBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/repos/acme/app/milestones" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/acme/app/milestones?state=open&page=2>; rel="next", <x>; rel="last"`, server))
+			fmt.Fprint(w, `[{"number": 1, "title": "api 1.0.0"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"number": 2, "title": "api 1.1.0"}]`)
+	})
+	server = b.baseURL
+	orig := activeGitHubBackend
+	activeGitHubBackend = b
+	t.Cleanup(func() { activeGitHubBackend = orig })
+
+	milestones, err := listOpenMilestones("")
+	if err != nil {
+		t.Fatalf("listOpenMilestones failed: %v", err)
+	}
+	if len(milestones) != 2 || milestones[1].Title != "api 1.1.0" {
+		t.Errorf("expected both pages, got %+v", milestones)
+	}
+}
+
+func TestAPIBackend_Error(t *testing.T) {
+	b := newTestAPIBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "Resource not accessible by integration"}`)
+	})
+	_, err := b.api("", "--method", "POST", "repos/{owner}/{repo}/check-runs", "-f", "name=x")
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden: Resource not accessible by integration") {
+		t.Errorf("expected the API message, got %v", err)
+	}
+}
+
+func TestAPIBackend_Input(t *testing.T) {
+	content := strings.Repeat("x", 200*1024)
+	b := newTestAPIBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/app/git/trees" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if len(body) < len(content) || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected the input as the JSON body, got %d bytes", len(body))
+		}
+		fmt.Fprint(w, `{"sha": "t"}`)
+	})
+	input, _ := json.Marshal(map[string]string{"content": content})
+	if _, err := b.apiInput("", input, "--method", "POST", "repos/{owner}/{repo}/git/trees"); err != nil {
+		t.Fatalf("apiInput failed: %v", err)
+	}
+}
+
+func TestAPIBackend_CreateRelease(t *testing.T) {
+	asset := filepath.Join(t.TempDir(), "api-v1.2.0.intoto.json")
+	if err := os.WriteFile(asset, []byte(`{"statement": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var created map[string]interface{}
+	var uploaded, uploadName string
+	var server string
+	b := newTestAPIBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/repos/acme/app/releases":
+			if err := json.Unmarshal(body, &created); err != nil {
+				t.Errorf("bad release body: %v", err)
+			}
+			fmt.Fprintf(w, `{"upload_url": "%s/uploads/1/assets{?name,label}"}`, server)
+		case "/uploads/1/assets":
+			uploaded, uploadName = string(body), r.URL.Query().Get("name")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	server = b.baseURL
+
+	err := b.createRelease("", &GitHubRelease{
+		TagName:            "api-v1.2.0",
+		Title:              "api v1.2.0",
+		Notes:              "## api v1.2.0\n",
+		TargetSHA:          "abc123",
+		Assets:             []string{asset},
+		DiscussionCategory: "Announcements",
+	})
+	if err != nil {
+		t.Fatalf("createRelease failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"tag_name":                 "api-v1.2.0",
+		"name":                     "api v1.2.0",
+		"body":                     "## api v1.2.0\n",
+		"target_commitish":         "abc123",
+		"discussion_category_name": "Announcements",
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("release = %v, want %v", created, want)
+	}
+	if uploadName != "api-v1.2.0.intoto.json" || uploaded != `{"statement": true}` {
+		t.Errorf("unexpected upload %q: %q", uploadName, uploaded)
+	}
+}
@@ -0,0 +1,9 @@
+package release
+
+import "errors"
+
+// ErrBareRepository is returned when planning or applying the releases of
+// a bare repository's analysis (AnalysisResult.Bare). There's no working
+// tree to write them to, so the analysis is read-only: its report is for a
+// server-side service, e.g. one evaluating pushes from a post-receive hook.
+var ErrBareRepository = errors.New("bare repository has no working tree to write releases to; its analysis is read-only")
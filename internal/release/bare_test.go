@@ -0,0 +1,52 @@
+package release
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyze_BareRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	want, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	runCmd(t, dir, "git", "clone", "--bare", dir, bare)
+
+	result, err := Analyze(&Options{RepoPath: bare, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze of bare repository failed: %v", err)
+	}
+	if !result.Bare {
+		t.Error("expected the result marked bare")
+	}
+	if result.Branch != "main" {
+		t.Errorf("expected branch main, got %q", result.Branch)
+	}
+	if len(result.Releases) != len(want.Releases) {
+		t.Fatalf("expected %d releases, got %d", len(want.Releases), len(result.Releases))
+	}
+	for i, rel := range result.Releases {
+		if rel.Package.Path != want.Releases[i].Package.Path || rel.NewVersion != want.Releases[i].NewVersion {
+			t.Errorf("release %d: expected %s %s, got %s %s", i, want.Releases[i].Package.Path, want.Releases[i].NewVersion, rel.Package.Path, rel.NewVersion)
+		}
+	}
+
+	// The report can be built, but nothing planned or applied
+	if report := BuildReleaseReport(result, ""); len(report.Releases) != len(want.Releases) {
+		t.Errorf("expected %d releases in the report, got %d", len(want.Releases), len(report.Releases))
+	}
+	if _, err := PlanChanges(result); !errors.Is(err, ErrBareRepository) {
+		t.Errorf("expected ErrBareRepository from PlanChanges, got %v", err)
+	}
+	if err := Apply(result, false); !errors.Is(err, ErrBareRepository) {
+		t.Errorf("expected ErrBareRepository from Apply, got %v", err)
+	}
+}
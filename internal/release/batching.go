@@ -0,0 +1,127 @@
+package release
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// DeferredRelease is a package whose release was held back because fewer
+// than its min-commits releasable commits are pending.
+type DeferredRelease struct {
+	Package *config.Package
+
+	// Pending is the number of releasable commits since the package's last
+	// release tag, including the ones being analyzed.
+	Pending int
+}
+
+// applyMinCommits holds back releases of packages with min-commits until
+// enough releasable commits have piled up since their last release tag
+// (component-vX.Y.Z). Packages that do release get every pending commit,
+// so the changelog covers the commits of the merges that were held back.
+// base is the last commit before the ones being analyzed. Pending commits'
+// files are read through cache, which may be nil.
+func applyMinCommits(repoPath, base string, cfg *config.Config, cache *git.Cache, packageCommits map[string][]*git.Commit) ([]*DeferredRelease, error) {
+	var deferred []*DeferredRelease
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		commits := packageCommits[pkg.Path]
+		if pkg.MinCommits == 0 || countReleasable(cfg, commits) == 0 {
+			continue
+		}
+
+		previous, err := pendingCommits(repoPath, base, cfg, cache, pkg)
+		if err != nil {
+			return nil, err
+		}
+		pending := append(previous, commits...)
+
+		n := countReleasable(cfg, pending)
+		if n < pkg.MinCommits && !releasesOnType(pkg, pending) {
+			deferred = append(deferred, &DeferredRelease{Package: pkg, Pending: n})
+			delete(packageCommits, pkg.Path)
+			continue
+		}
+		packageCommits[pkg.Path] = pending
+	}
+	return deferred, nil
+}
+
+// pendingCommits returns the commits touching pkg between the release tag of
+// its manifest version and base. A package that was never released (0.0.0)
+// has every commit up to base pending; one whose tag is missing is an error,
+// rather than counting its whole history.
+func pendingCommits(repoPath, base string, cfg *config.Config, cache *git.Cache, pkg *config.Package) ([]*git.Commit, error) {
+	if base == "" {
+		return nil, nil
+	}
+	tag, err := lastReleaseTag(repoPath, pkg)
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" && pkg.CurrentVersion != "" && pkg.CurrentVersion != "0.0.0" {
+		return nil, fmt.Errorf("failed to get pending commits of %s: release tag %s not found; tag version %s's release or drop min-commits",
+			pkg.Component, buildTagName(pkg.Component, pkg.CurrentVersion), pkg.CurrentVersion)
+	}
+	commits, err := cache.GetCommitsSinceTag(repoPath, tag, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending commits of %s: %w", pkg.Component, err)
+	}
+	if err := reparseCommits(cfg, commits); err != nil {
+		return nil, err
+	}
+
+	var pending []*git.Commit
+	for _, commit := range commits {
+		if cfg.IsReleaseCommit(commit.Subject) || !touchesPackage(cfg, pkg, commit) {
+			continue
+		}
+		commit.Notes = commit.ReleaseNotes(cfg.IncludeCommitBody)
+		commit.Scope = cfg.NormalizeScope(commit.Scope)
+		pending = append(pending, commit)
+	}
+	addSubmoduleNotes(repoPath, cfg, pending)
+	return pending, nil
+}
+
+// touchesPackage reports whether any of the commit's files, other than
+// ignore-files, belongs to pkg.
+func touchesPackage(cfg *config.Config, pkg *config.Package, commit *git.Commit) bool {
+	for _, file := range commit.Files {
+		if cfg.IsIgnoredFile(file) {
+			continue
+		}
+		if owner := cfg.FindPackageForPath(file); owner != nil && owner.Path == pkg.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// countReleasable returns the number of commits that bump the version.
+func countReleasable(cfg *config.Config, commits []*git.Commit) int {
+	var n int
+	for _, commit := range commits {
+		if commit.IsBreaking || cfg.BumpFor(commit.Type) != version.None {
+			n++
+		}
+	}
+	return n
+}
+
+// releasesOnType reports whether any commit has one of the package's
+// release-on-types, which release it regardless of min-commits.
+func releasesOnType(pkg *config.Package, commits []*git.Commit) bool {
+	for _, commit := range commits {
+		if slices.Contains(pkg.ReleaseOnTypes, commit.Type) {
+			return true
+		}
+		if commit.IsBreaking && slices.Contains(pkg.ReleaseOnTypes, "breaking") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package release
+
+import (
+	"testing"
+)
+
+// setupBatchedRepo creates a repo whose service-a package waits for three
+// releasable commits, or a feat, and tags its current release.
+func setupBatchedRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {
+				"component": "service-a",
+				"min-commits": 3,
+				"release-on-types": ["feat"]
+			}
+		}
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: batch service-a releases")
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0")
+	return dir
+}
+
+// mergeChange merges a branch with one commit changing service-a.
+func mergeChange(t *testing.T, dir, branch, content, subject string) {
+	t.Helper()
+	runCmd(t, dir, "git", "checkout", "-b", branch)
+	writeFile(t, dir, "workloads/service-a/src/main.go", content)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", subject)
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", branch, "-m", "Merge branch '"+branch+"'")
+}
+
+func TestAnalyze_MinCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBatchedRepo(t)
+
+	merges := []struct {
+		subject     string
+		wantPending int
+	}{
+		{"fix(service-a): first fix", 1},
+		{"docs(service-a): explain flags", 0}, // nothing to release, nothing deferred
+		{"fix(service-a): second fix", 2},
+	}
+	for i, m := range merges {
+		mergeChange(t, dir, "feature/"+string(rune('a'+i)), m.subject+"\n", m.subject)
+
+		result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(result.Releases) != 0 {
+			t.Fatalf("%s: expected the release deferred, got %+v", m.subject, result.Releases)
+		}
+		d := result.Stats.Deferred
+		if m.wantPending == 0 && len(d) != 0 {
+			t.Errorf("%s: expected nothing deferred, got %+v", m.subject, d)
+		}
+		if m.wantPending > 0 && (len(d) != 1 || d[0].Pending != m.wantPending) {
+			t.Errorf("%s: expected %d pending commit(s), got %+v", m.subject, m.wantPending, d)
+		}
+	}
+
+	mergeChange(t, dir, "feature/d", "third fix\n", "fix(service-a): third fix")
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Stats.Deferred) != 0 || len(result.Releases) != 1 {
+		t.Fatalf("expected a release, got %+v (deferred %+v)", result.Releases, result.Stats.Deferred)
+	}
+	rel := result.Releases[0]
+	if rel.NewVersion != "0.1.1" {
+		t.Errorf("expected 0.1.1, got %s", rel.NewVersion)
+	}
+	var fixes []string
+	for _, c := range rel.Commits {
+		if c.Type == "fix" {
+			fixes = append(fixes, c.Description)
+		}
+	}
+	if len(fixes) != 3 || fixes[0] != "first fix" || fixes[2] != "third fix" {
+		t.Errorf("expected every pending fix in the release, oldest first, got %v", fixes)
+	}
+}
+
+func TestAnalyze_MinCommitsReleaseOnType(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBatchedRepo(t)
+	mergeChange(t, dir, "feature/a", "fix\n", "fix(service-a): held back")
+	mergeChange(t, dir, "feature/b", "feat\n", "feat(service-a): new thing")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.1" {
+		t.Fatalf("expected the feat to release 0.1.1 right away, got %+v", result.Releases)
+	}
+	if n := len(result.Releases[0].Commits); n != 2 {
+		t.Errorf("expected the held-back fix included, got %d commit(s)", n)
+	}
+}
+
+func TestAnalyze_MinCommitsSinceTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBatchedRepo(t)
+	mergeChange(t, dir, "feature/a", "a\n", "fix(service-a): before the release")
+	mergeChange(t, dir, "feature/b", "b\n", "fix(service-a): also before")
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.1")
+	writeFile(t, dir, "release-please-manifest.json", `{"workloads/service-a": "0.1.1"}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: release service-a 0.1.1")
+	mergeChange(t, dir, "feature/c", "c\n", "fix(service-a): after the release")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if d := result.Stats.Deferred; len(d) != 1 || d[0].Pending != 1 {
+		t.Errorf("expected only the commit after the last tag pending, got %+v", d)
+	}
+}
+
+func TestAnalyze_MinCommitsMissingTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBatchedRepo(t)
+	runCmd(t, dir, "git", "tag", "-d", "service-a-v0.1.0")
+	mergeChange(t, dir, "feature/a", "a\n", "fix(service-a): fix bug")
+
+	_, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err == nil || !contains(err.Error(), "release tag service-a-v0.1.0 not found") {
+		t.Errorf("expected a missing tag error, got %v", err)
+	}
+}
+
+func TestAnalyze_MinCommitsNeverReleased(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBatchedRepo(t)
+	runCmd(t, dir, "git", "tag", "-d", "service-a-v0.1.0")
+	writeFile(t, dir, "release-please-manifest.json", `{"workloads/service-a": "0.0.0"}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: reset service-a")
+	mergeChange(t, dir, "feature/a", "a\n", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if d := result.Stats.Deferred; len(d) != 1 || d[0].Pending == 0 {
+		t.Errorf("expected the package's history pending, got %+v", d)
+	}
+}
@@ -0,0 +1,75 @@
+package release
+
+import (
+	"fmt"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// applyBinaryFiles finds the binary and Git LFS files of each commit and
+// returns how many each commit changes, by SHA. With ignore-binary-files,
+// they're also dropped from the commits' files, and commits touching
+// nothing else are left out of the returned commits and counted.
+func applyBinaryFiles(repoPath string, cfg *config.Config, commits []*git.Commit) ([]*git.Commit, map[string]int, int, error) {
+	binaries := make(map[string]map[string]bool, len(commits))
+	var all []string
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		files, err := git.BinaryFiles(repoPath, commit.SHA)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		binaries[commit.SHA] = make(map[string]bool, len(files))
+		for _, file := range files {
+			binaries[commit.SHA][file] = true
+		}
+		for _, file := range commit.Files {
+			if !seen[file] {
+				seen[file] = true
+				all = append(all, file)
+			}
+		}
+	}
+	lfs, err := git.LFSFiles(repoPath, all)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	lfsFiles := make(map[string]bool, len(lfs))
+	for _, file := range lfs {
+		lfsFiles[file] = true
+	}
+
+	counts := make(map[string]int)
+	var binaryCommits int
+	kept := commits[:0]
+	for _, commit := range commits {
+		var files []string
+		for _, file := range commit.Files {
+			if binaries[commit.SHA][file] || lfsFiles[file] {
+				counts[commit.SHA]++
+				if cfg.IgnoreBinaryFiles {
+					continue
+				}
+			}
+			files = append(files, file)
+		}
+		if cfg.IgnoreBinaryFiles {
+			if len(files) == 0 && len(commit.Files) > 0 {
+				binaryCommits++
+				continue
+			}
+			commit.Files = files
+		}
+		kept = append(kept, commit)
+	}
+	return kept, counts, binaryCommits, nil
+}
+
+// binaryNote summarizes a commit's binary files for its changelog entry.
+func binaryNote(count int) string {
+	if count == 1 {
+		return "1 binary asset updated"
+	}
+	return fmt.Sprintf("%d binary assets updated", count)
+}
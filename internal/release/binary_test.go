@@ -0,0 +1,63 @@
+package release
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnalyze_BinaryFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		name         string
+		options      string
+		wantCommits  int
+		wantNotes    []string
+		wantIgnored  int
+		wantReleases int
+	}{
+		{name: "default", wantCommits: 2, wantReleases: 1},
+		{name: "summarize", options: `"summarize-binary-files": true,`, wantCommits: 2, wantNotes: []string{"2 binary assets updated"}, wantReleases: 1},
+		{name: "ignore", options: `"ignore-binary-files": true,`, wantCommits: 1, wantIgnored: 1, wantReleases: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := setupBasicRepo(t)
+			writeFile(t, dir, "release-please-config.json", `{`+tc.options+`
+				"packages": {"workloads/service-a": {"component": "service-a"}}
+			}`)
+			writeFile(t, dir, ".gitattributes", "*.psd filter=lfs diff=lfs merge=lfs -text\n")
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "chore: configure")
+
+			runCmd(t, dir, "git", "checkout", "-b", "branding")
+			writeFile(t, dir, "workloads/service-a/assets/logo.png", "\x89PNG\x00\x01\x02")
+			writeFile(t, dir, "workloads/service-a/assets/hero.psd", "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 123\n")
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "feat(service-a): new branding")
+			writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+			runCmd(t, dir, "git", "checkout", "main")
+			runCmd(t, dir, "git", "merge", "--no-ff", "branding", "-m", "Merge branch 'branding'")
+
+			result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(result.Releases) != tc.wantReleases || len(result.Releases[0].Commits) != tc.wantCommits {
+				t.Fatalf("expected %d release(s) of %d commit(s), got %+v", tc.wantReleases, tc.wantCommits, result.Releases)
+			}
+			if result.Stats.BinaryCommits != tc.wantIgnored {
+				t.Errorf("expected %d binary-only commit(s) ignored, got %d", tc.wantIgnored, result.Stats.BinaryCommits)
+			}
+			for _, commit := range result.Releases[0].Commits {
+				if commit.Subject == "feat(service-a): new branding" && !slices.Equal(commit.Notes, tc.wantNotes) {
+					t.Errorf("expected notes %q, got %q", tc.wantNotes, commit.Notes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// currentBranch returns the branch being released: the checked-out branch,
+// or with HEAD detached, as actions/checkout leaves it, the branch the CI run
+// checked out HEAD for (GITHUB_REF, or GITHUB_BASE_REF for a pull request),
+// else the one branch whose tip HEAD is. It's "" when HEAD was checked out
+// for a tag or no single branch is found.
+func currentBranch(repoPath string, getenv func(string) string) (string, error) {
+	branch, err := git.CurrentBranch(repoPath)
+	if err != nil || branch != "" {
+		return branch, err
+	}
+
+	head, err := git.ResolveCommit(repoPath, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if sha := getenv("GITHUB_SHA"); sha == "" || sha == head {
+		ref := getenv("GITHUB_REF")
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			return strings.TrimPrefix(ref, "refs/heads/"), nil
+		case strings.HasPrefix(ref, "refs/pull/"):
+			return getenv("GITHUB_BASE_REF"), nil
+		case strings.HasPrefix(ref, "refs/tags/"):
+			return "", nil
+		}
+	}
+
+	branches, err := git.BranchesAt(repoPath, head)
+	if err != nil {
+		return "", err
+	}
+	if len(branches) != 1 {
+		slog.Debug("HEAD is detached and not the tip of exactly one branch", "branches", branches)
+		return "", nil
+	}
+	return branches[0], nil
+}
+
+// capBump lowers bump to limit, the branch's max-bump. None means no limit.
+func capBump(component string, bump, limit version.BumpType) version.BumpType {
+	if limit == version.None || bump <= limit {
+		return bump
+	}
+	slog.Warn("bump capped by branch max-bump", "component", component, "bump", bump.String(), "max_bump", limit.String())
+	return limit
+}
+
+// checkBranchReleases enforces a release branch's max-bump on the calculated
+// releases. A versioning strategy can still produce a bigger bump than the
+// capped one, so that's an error rather than silently exceeding it. Existing
+// tags are checked for every branch, with rules or not, by
+// resolveTagCollisions.
+func checkBranchReleases(branchName string, branch *config.Branch, releases []*PackageRelease) error {
+	if branch.MaxBump == version.None {
+		return nil
+	}
+	for _, rel := range releases {
+		oldVersion, oldErr := version.Parse(rel.OldVersion)
+		newVersion, newErr := version.Parse(rel.NewVersion)
+		if oldErr == nil && newErr == nil && version.BumpBetween(oldVersion, newVersion) > branch.MaxBump {
+			return fmt.Errorf("%s %s → %s exceeds max-bump %s on branch %s",
+				rel.Package.Component, rel.OldVersion, rel.NewVersion, branch.MaxBump, branchName)
+		}
+	}
+	return nil
+}
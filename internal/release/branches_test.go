@@ -0,0 +1,200 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// setupMaintenanceRepo creates a repo on a 1.x branch whose config caps bumps
+// at patch, with service-a at 1.4.2.
+func setupMaintenanceRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"}
+		},
+		"branches": {
+			"*.x": {"max-bump": "patch"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "1.4.2"
+	}`)
+	writeFile(t, dir, "workloads/service-a/VERSION", "1.4.2\n")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "checkout", "-b", "1.x")
+
+	return dir
+}
+
+func TestAnalyze_MaintenanceBranchCapsBump(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupMaintenanceRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Backport\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): backport feature")
+
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Branch != "1.x" {
+		t.Errorf("expected branch 1.x, got %q", result.Branch)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "1.4.3" {
+		t.Fatalf("expected feat capped to patch 1.4.3, got %+v", result.Releases)
+	}
+
+	// The same commit on an unconfigured branch bumps minor
+	result, err = Analyze(&Options{RepoPath: dir, Branch: "main"})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Releases[0].NewVersion != "1.5.0" {
+		t.Errorf("expected uncapped minor bump 1.5.0, got %s", result.Releases[0].NewVersion)
+	}
+}
+
+func TestAnalyze_MaintenanceBranchTagCollision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupMaintenanceRepo(t)
+	runCmd(t, dir, "git", "tag", "service-a-v1.4.3")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): backport fix")
+
+	_, err := Analyze(&Options{RepoPath: dir})
+	if err == nil || !strings.Contains(err.Error(), "service-a-v1.4.3 already exists") {
+		t.Fatalf("expected tag collision error, got %v", err)
+	}
+
+	// A branch without rules is checked too
+	_, err = Analyze(&Options{RepoPath: dir, Branch: "feature"})
+	if err == nil || !strings.Contains(err.Error(), "service-a-v1.4.3 already exists") {
+		t.Fatalf("expected tag collision error without branch rules, got %v", err)
+	}
+}
+
+func TestAnalyze_MaintenanceBranchRerun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupMaintenanceRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): backport fix")
+	runCmd(t, dir, "git", "tag", "service-a-v1.4.3")
+
+	// The tag at HEAD is this commit's own release, not a collision
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "1.4.3" {
+		t.Errorf("expected the release to be analyzed again, got %+v", result.Releases)
+	}
+}
+
+func TestAnalyze_MaintenanceBranchRejectsForcedBump(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupMaintenanceRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a", "versioning": "always-bump-minor"}
+		},
+		"branches": {
+			"1.x": {"max-bump": "patch"}
+		}
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): backport fix")
+
+	_, err := Analyze(&Options{RepoPath: dir})
+	if err == nil || !strings.Contains(err.Error(), "exceeds max-bump patch") {
+		t.Fatalf("expected max-bump error, got %v", err)
+	}
+}
+
+func TestCurrentBranch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	head := gitOutput(t, dir, "rev-parse", "HEAD")
+	runCmd(t, dir, "git", "branch", "1.x")
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0")
+	runCmd(t, dir, "git", "checkout", "--detach")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"branch ref", map[string]string{"GITHUB_REF": "refs/heads/release/2.x", "GITHUB_SHA": head}, "release/2.x"},
+		{"pull request", map[string]string{"GITHUB_REF": "refs/pull/7/merge", "GITHUB_BASE_REF": "1.x"}, "1.x"},
+		{"tag", map[string]string{"GITHUB_REF": "refs/tags/service-a-v0.1.0"}, ""},
+		{"another commit's run", map[string]string{"GITHUB_REF": "refs/heads/other", "GITHUB_SHA": "0123abc"}, ""},
+		{"no CI", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currentBranch(dir, func(name string) string { return tt.env[name] })
+			if err != nil {
+				t.Fatalf("currentBranch failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	// Outside CI, a detached HEAD at exactly one branch's tip is on it
+	runCmd(t, dir, "git", "branch", "-D", "1.x")
+	if got, err := currentBranch(dir, func(string) string { return "" }); err != nil || got != "main" {
+		t.Errorf("expected main, the only branch at HEAD, got %q (%v)", got, err)
+	}
+}
+
+func TestAnalyze_Worktree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	wt := filepath.Join(t.TempDir(), "wt")
+	runCmd(t, dir, "git", "worktree", "add", "-b", "1.x", wt)
+
+	result, err := Analyze(&Options{RepoPath: wt, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Branch != "1.x" {
+		t.Errorf("expected branch 1.x, got %q", result.Branch)
+	}
+	if len(result.Releases) != 2 {
+		t.Errorf("expected both services released, got %d release(s)", len(result.Releases))
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", git.CacheFile)); err != nil {
+		t.Errorf("expected the commit cache in the main repository's git directory: %v", err)
+	}
+}
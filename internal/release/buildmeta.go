@@ -0,0 +1,57 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Build metadata sources for Options.BuildMetadata. Any other value is used
+// as the metadata itself.
+const (
+	// BuildMetadataSHA stamps the short SHA of HEAD (e.g., "+a1b2c3d").
+	BuildMetadataSHA = "sha"
+
+	// BuildMetadataDate stamps the release date (e.g., "+20250314").
+	BuildMetadataDate = "date"
+)
+
+// buildMetadataRegex matches semver build metadata: dot-separated non-empty
+// identifiers of alphanumerics and hyphens.
+var buildMetadataRegex = regexp.MustCompile(`^[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*$`)
+
+// BuildVersion returns the version written to the package's version file:
+// NewVersion with the release's build metadata, if any. Tags, the manifest,
+// and changelogs use NewVersion.
+func (r *PackageRelease) BuildVersion() string {
+	if r.Build == "" {
+		return r.NewVersion
+	}
+	return r.NewVersion + "+" + r.Build
+}
+
+// ValidateBuildMetadata checks a --build-metadata value: "sha", "date", or
+// custom semver build metadata (e.g., "ci.1234").
+func ValidateBuildMetadata(source string) error {
+	if source == BuildMetadataSHA || source == BuildMetadataDate || buildMetadataRegex.MatchString(source) {
+		return nil
+	}
+	return fmt.Errorf("invalid build metadata %q: must be %s, %s, or dot-separated alphanumerics and hyphens", source, BuildMetadataSHA, BuildMetadataDate)
+}
+
+// stampBuildMetadata sets the build metadata of each release from source.
+func stampBuildMetadata(result *AnalysisResult, source string) error {
+	if err := ValidateBuildMetadata(source); err != nil {
+		return err
+	}
+	build := source
+	switch source {
+	case BuildMetadataSHA:
+		build = shortSHA(result.MergeInfo.HeadSHA)
+	case BuildMetadataDate:
+		build = result.Date().Format("20060102")
+	}
+	for _, rel := range result.Releases {
+		rel.Build = build
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestValidateBuildMetadata(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr bool
+	}{
+		{"sha", false},
+		{"date", false},
+		{"ci.1234", false},
+		{"exp-sha.5114f85", false},
+		{"", true},
+		{"ci..1", true},
+		{"ci 1", true},
+		{"+ci", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			err := ValidateBuildMetadata(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBuildMetadata(%q) error = %v, wantErr %v", tt.source, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStampBuildMetadata(t *testing.T) {
+	newResult := func() *AnalysisResult {
+		return &AnalysisResult{
+			MergeInfo:   &git.MergeInfo{HeadSHA: "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"},
+			ReleaseDate: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+			Releases: []*PackageRelease{
+				{Package: &config.Package{Component: "api"}, NewVersion: "1.2.0"},
+				{Package: &config.Package{Component: "web"}, NewVersion: "0.3.1"},
+			},
+		}
+	}
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{BuildMetadataSHA, "1.2.0+a1b2c3d"},
+		{BuildMetadataDate, "1.2.0+20250314"},
+		{"ci.42", "1.2.0+ci.42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			result := newResult()
+			if err := stampBuildMetadata(result, tt.source); err != nil {
+				t.Fatalf("stampBuildMetadata failed: %v", err)
+			}
+			if got := result.Releases[0].BuildVersion(); got != tt.want {
+				t.Errorf("BuildVersion() = %q, want %q", got, tt.want)
+			}
+			if result.Releases[0].NewVersion != "1.2.0" {
+				t.Errorf("NewVersion changed to %q", result.Releases[0].NewVersion)
+			}
+			if result.Releases[1].Build != result.Releases[0].Build {
+				t.Errorf("expected every release stamped alike, got %q and %q", result.Releases[0].Build, result.Releases[1].Build)
+			}
+		})
+	}
+
+	if err := stampBuildMetadata(newResult(), "not valid"); err == nil {
+		t.Error("expected invalid build metadata to fail")
+	}
+}
+
+func TestPackageRelease_BuildVersion_NoBuild(t *testing.T) {
+	rel := &PackageRelease{NewVersion: "1.2.0"}
+	if got := rel.BuildVersion(); got != "1.2.0" {
+		t.Errorf("BuildVersion() = %q, want 1.2.0", got)
+	}
+}
+
+func TestAnalyze_BuildMetadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, BuildMetadata: "ci.7"})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "workloads/service-a/VERSION"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0.1.1+ci.7 # x-release-please-version\n" {
+		t.Errorf("VERSION = %q, want the build metadata stamped", data)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, "release-please-manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(manifest), "+") || !strings.Contains(string(manifest), `"0.1.1"`) {
+		t.Errorf("expected the manifest to keep the plain version, got %s", manifest)
+	}
+
+	report := BuildReleaseReport(result, "")
+	rel := report.Releases[0]
+	if rel.BuildVersion != "0.1.1+ci.7" || rel.NewVersion != "0.1.1" || rel.TagName != "service-a-v0.1.1" {
+		t.Errorf("expected build_version stamped and the tag clean, got %+v", rel)
+	}
+}
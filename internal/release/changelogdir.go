@@ -0,0 +1,43 @@
+package release
+
+import (
+	"path/filepath"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// planChangelogDir plans the changelog for a package using the directory
+// layout: the entry goes in its own <version>.md under dir, and a line
+// linking to it is added to the top of the directory's index.
+func planChangelogDir(repoRoot, dir string, entry *changelog.Entry) ([]*FileChange, error) {
+	file := entry.Version + ".md"
+	entryPath := filepath.Join(dir, file)
+	existingEntry, _, err := readOptional(filepath.Join(repoRoot, entryPath))
+	if err != nil {
+		return nil, err
+	}
+
+	indexPath := filepath.Join(dir, config.ChangelogIndexFile)
+	existingIndex, found, err := readOptional(filepath.Join(repoRoot, indexPath))
+	if err != nil {
+		return nil, err
+	}
+	base := existingIndex
+	if !found {
+		base = changelog.InitialIndex()
+	}
+
+	return []*FileChange{
+		{
+			Path: entryPath,
+			Old:  existingEntry,
+			New:  changelog.Generate(entry),
+		},
+		{
+			Path: indexPath,
+			Old:  existingIndex,
+			New:  changelog.PrependIndex(base, changelog.IndexEntry(entry.Version, file, entry.Date)),
+		},
+	}, nil
+}
@@ -0,0 +1,82 @@
+package release
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestPlanChangelogDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "api/changelogs/README.md", "# Changelog\n\n"+changelog.InsertMarker+"\n\n* [1.0.0](1.0.0.md) (2024-01-01)\n")
+
+	pkg := &config.Package{Path: "api", Component: "api", ChangelogPath: "changelogs", ChangelogLayout: config.ChangelogLayoutDirectory}
+	rel := &PackageRelease{
+		Package:    pkg,
+		OldVersion: "1.0.0",
+		NewVersion: "1.1.0",
+		Commits:    []*git.Commit{{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "feat", Description: "add endpoint"}},
+	}
+	result := &AnalysisResult{Config: &config.Config{RepoRoot: dir}, ReleaseDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	changes, err := planChangelogDir(dir, "api/changelogs", changelogEntry(result, rel, result.RepoURL))
+	if err != nil {
+		t.Fatalf("planChangelogDir failed: %v", err)
+	}
+
+	if len(changes) != 2 || changes[0].Path != "api/changelogs/1.1.0.md" || changes[1].Path != "api/changelogs/README.md" {
+		t.Fatalf("expected the 1.1.0 entry and index, got %+v", changes)
+	}
+	if changes[0].Old != "" || !strings.HasPrefix(changes[0].New, "## [1.1.0] (2024-03-01)") || !strings.Contains(changes[0].New, "add endpoint") {
+		t.Errorf("unexpected entry: %q", changes[0].New)
+	}
+	index := changes[1].New
+	if !strings.Contains(index, "* [1.1.0](1.1.0.md) (2024-03-01)") || strings.Index(index, "[1.1.0]") > strings.Index(index, "[1.0.0]") {
+		t.Errorf("expected 1.1.0 listed above 1.0.0, got:\n%s", index)
+	}
+}
+
+func TestPlanChanges_ChangelogDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a", "changelog-layout": "directory"}
+		}
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	want := []string{
+		"workloads/service-a/VERSION",
+		"workloads/service-a/changelogs/0.1.1.md",
+		"workloads/service-a/changelogs/README.md",
+		"release-please-manifest.json",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected changes to %v, got %v", want, paths)
+	}
+	if index := changes[2].New; !strings.HasPrefix(index, changelog.InitialIndex()) || !strings.Contains(index, "* [0.1.1](0.1.1.md)") {
+		t.Errorf("unexpected index: %q", index)
+	}
+}
@@ -0,0 +1,41 @@
+package release
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// changelogJSONFile is the sidecar's name in a changelog directory.
+const changelogJSONFile = "CHANGELOG.json"
+
+// changelogJSONPath returns the path of a package's CHANGELOG.json sidecar,
+// relative to the repo root: next to the changelog file with a .json
+// extension, or inside the changelog directory.
+func changelogJSONPath(pkg *config.Package) string {
+	changelogPath := filepath.Join(pkg.Path, pkg.ChangelogPath)
+	if pkg.ChangelogLayout == config.ChangelogLayoutDirectory {
+		return filepath.Join(changelogPath, changelogJSONFile)
+	}
+	return strings.TrimSuffix(changelogPath, filepath.Ext(changelogPath)) + ".json"
+}
+
+// planChangelogJSON plans adding a release's entry to the package's
+// CHANGELOG.json sidecar.
+func planChangelogJSON(repoRoot, path string, entry *changelog.Entry) (*FileChange, error) {
+	existing, _, err := readOptional(filepath.Join(repoRoot, path))
+	if err != nil {
+		return nil, err
+	}
+	updated, err := changelog.PrependJSON(existing, changelog.GenerateJSON(entry))
+	if err != nil {
+		return nil, err
+	}
+	return &FileChange{
+		Path: path,
+		Old:  existing,
+		New:  updated,
+	}, nil
+}
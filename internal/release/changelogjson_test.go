@@ -0,0 +1,75 @@
+package release
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestChangelogJSONPath(t *testing.T) {
+	tests := []struct {
+		pkg  *config.Package
+		want string
+	}{
+		{&config.Package{Path: "api", ChangelogPath: "CHANGELOG.md"}, "api/CHANGELOG.json"},
+		{&config.Package{Path: "api", ChangelogPath: "docs/HISTORY.md"}, "api/docs/HISTORY.json"},
+		{&config.Package{Path: ".", ChangelogPath: "CHANGELOG.md"}, "CHANGELOG.json"},
+		{&config.Package{Path: "api", ChangelogPath: "changelogs", ChangelogLayout: config.ChangelogLayoutDirectory}, "api/changelogs/CHANGELOG.json"},
+	}
+	for _, tt := range tests {
+		if got := changelogJSONPath(tt.pkg); got != tt.want {
+			t.Errorf("changelogJSONPath(%s/%s) = %s, want %s", tt.pkg.Path, tt.pkg.ChangelogPath, got, tt.want)
+		}
+	}
+}
+
+func TestPlanChanges_ChangelogJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a", "changelog-json": true}
+		}
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	var sidecar *FileChange
+	for i, c := range changes {
+		if c.Path == "workloads/service-a/CHANGELOG.json" {
+			sidecar = c
+			if i == 0 || changes[i-1].Path != "workloads/service-a/CHANGELOG.md" {
+				t.Errorf("expected CHANGELOG.json right after CHANGELOG.md")
+			}
+		}
+	}
+	if sidecar == nil {
+		t.Fatal("expected a CHANGELOG.json change")
+	}
+
+	var versions []changelog.JSONVersion
+	if err := json.Unmarshal([]byte(sidecar.New), &versions); err != nil {
+		t.Fatalf("invalid CHANGELOG.json: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != "0.1.1" || versions[0].PreviousVersion != "0.1.0" {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+	if len(versions[0].Changes) != 1 || versions[0].Changes[0].Description != "fix bug" {
+		t.Errorf("unexpected changes: %+v", versions[0].Changes)
+	}
+}
@@ -0,0 +1,166 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// CheckRunName is the name shown for the check run on the commit page.
+const CheckRunName = "release-damnit"
+
+// maxCheckRunText is the longest check run output text GitHub accepts, in
+// characters.
+const maxCheckRunText = 65535
+
+// CheckRun is the content of a GitHub Check Run summarizing an analysis.
+type CheckRun struct {
+	HeadSHA    string
+	Conclusion string // "success" or "neutral" (dry run)
+	Title      string
+	Summary    string
+	Text       string
+
+	// HTMLURL is set after the check run is created.
+	HTMLURL string
+}
+
+// BuildCheckRun renders the analysis as a check run: a one-line title, a
+// markdown summary table of releases, and details on unmatched commits,
+// cut to fit GitHub's limit.
+func BuildCheckRun(result *AnalysisResult, dryRun bool) *CheckRun {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotNil(result.MergeInfo, "result.MergeInfo")
+
+	run := &CheckRun{
+		HeadSHA:    result.MergeInfo.HeadSHA,
+		Conclusion: "success",
+	}
+	if dryRun {
+		run.Conclusion = "neutral"
+	}
+
+	if len(result.Releases) == 0 {
+		run.Title = "No releasable changes"
+	} else {
+		var names []string
+		for _, rel := range result.Releases {
+			names = append(names, fmt.Sprintf("%s %s", rel.Package.Component, rel.NewVersion))
+		}
+		run.Title = fmt.Sprintf("%d release(s): %s", len(result.Releases), strings.Join(names, ", "))
+	}
+	if dryRun {
+		run.Title += " (dry run)"
+	}
+
+	var summary strings.Builder
+	if result.Accumulated {
+		summary.WriteString(fmt.Sprintf("Analyzed commits since each package's last release tag up to `%s` (%d commits).\n\n", shortSHA(result.MergeInfo.HeadSHA), len(result.Commits)))
+	} else if result.MergeInfo.IsMerge {
+		heads := make([]string, 0, len(result.MergeInfo.MergeHeads))
+		for _, head := range result.MergeInfo.MergeHeads {
+			heads = append(heads, shortSHA(head))
+		}
+		if len(heads) == 0 {
+			heads = append(heads, shortSHA(result.MergeInfo.MergeHead))
+		}
+		summary.WriteString(fmt.Sprintf("Analyzed merge `%s..%s` (%d commits).\n\n",
+			shortSHA(result.MergeInfo.MergeBase), strings.Join(heads, ","), len(result.Commits)))
+	} else {
+		summary.WriteString(fmt.Sprintf("Analyzed commit `%s` (%d commits).\n\n", shortSHA(result.MergeInfo.HeadSHA), len(result.Commits)))
+	}
+	if len(result.Releases) > 0 {
+		summary.WriteString("| Component | Bump | Version | Commits |\n")
+		summary.WriteString("|-----------|------|---------|---------|\n")
+		for _, rel := range result.Releases {
+			summary.WriteString(fmt.Sprintf("| %s | %s | %s → %s | %d |\n",
+				rel.Package.Component, rel.BumpType, rel.OldVersion, rel.NewVersion, len(rel.Commits)))
+		}
+	}
+	run.Summary = summary.String()
+
+	// The directories get their share of the limit first, as they're the
+	// shorter list and the one to act on
+	var dirs strings.Builder
+	if result.Stats != nil && len(result.Stats.OrphanedDirs) > 0 {
+		dirs.WriteString("### Directories without a package\n\n")
+		lines := make([]string, 0, len(result.Stats.OrphanedDirs))
+		for _, dir := range result.Stats.OrphanedDirs {
+			lines = append(lines, fmt.Sprintf("* `%s/`\n", dir))
+		}
+		writeLinesWithin(&dirs, lines, maxCheckRunText/2)
+	}
+	var text strings.Builder
+	if result.Stats != nil && result.Stats.UnmatchedCommits > 0 {
+		text.WriteString(fmt.Sprintf("### Unmatched commits (%d)\n\n", result.Stats.UnmatchedCommits))
+		var lines []string
+		for _, c := range result.Commits {
+			if len(findMatchingPackages(c.Files, result.Config)) == 0 {
+				lines = append(lines, fmt.Sprintf("* `%s` %s\n", c.ShortSHA, buildCommitMessage(c)))
+			}
+		}
+		writeLinesWithin(&text, lines, maxCheckRunText-dirs.Len()-1)
+		text.WriteString("\n")
+	}
+	text.WriteString(dirs.String())
+	run.Text = text.String()
+
+	return run
+}
+
+// CreateCheckRun posts the check run to GitHub through the selected backend
+// (see SelectGitHubBackend). Requires a token with checks:write (the Actions GITHUB_TOKEN qualifies).
+func CreateCheckRun(repoPath string, run *CheckRun) error {
+	contracts.RequireNotNil(run, "run")
+	contracts.RequireNotEmpty(run.HeadSHA, "run.HeadSHA")
+
+	args := []string{
+		"--method", "POST", "repos/{owner}/{repo}/check-runs",
+		"-f", "name=" + CheckRunName,
+		"-f", "head_sha=" + run.HeadSHA,
+		"-f", "status=completed",
+		"-f", "conclusion=" + run.Conclusion,
+		"-f", "output[title]=" + run.Title,
+		"-f", "output[summary]=" + run.Summary,
+	}
+	if run.Text != "" {
+		args = append(args, "-f", "output[text]="+run.Text)
+	}
+
+	out, err := ghAPI(repoPath, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create check run: %w", err)
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if json.Unmarshal(out, &created) == nil {
+		run.HTMLURL = created.HTMLURL
+	}
+	return nil
+}
+
+// writeLinesWithin appends lines to b while it stays within limit bytes,
+// ending with a count of the lines left out if they don't all fit. Bytes
+// never undercount GitHub's characters.
+func writeLinesWithin(b *strings.Builder, lines []string, limit int) {
+	for i, line := range lines {
+		// Leave room for the count of the rest
+		if b.Len()+len(line)+40 > limit {
+			fmt.Fprintf(b, "* …and %d more\n", len(lines)-i)
+			return
+		}
+		b.WriteString(line)
+	}
+}
+
+// shortSHA returns the 7-character prefix of a SHA.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,140 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// checkRunResult builds a merge analysis with one release and one unmatched commit.
+func checkRunResult() *AnalysisResult {
+	pkg := &config.Package{Path: "workloads/api", Component: "api"}
+	matched := &git.Commit{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "feat", Description: "add endpoint", Files: []string{"workloads/api/main.go"}}
+	unmatched := &git.Commit{SHA: "bbb2222222222", ShortSHA: "bbb2222", Type: "chore", Description: "tweak ci", Files: []string{".github/ci.yml"}}
+
+	return &AnalysisResult{
+		MergeInfo: &git.MergeInfo{
+			IsMerge:   true,
+			HeadSHA:   "ccc3333333333",
+			MergeBase: "ddd4444444444",
+			MergeHead: "eee5555555555",
+		},
+		Commits: []*git.Commit{matched, unmatched},
+		Releases: []*PackageRelease{
+			{Package: pkg, BumpType: version.Minor, OldVersion: "1.0.0", NewVersion: "1.1.0", Commits: []*git.Commit{matched}},
+		},
+		Config: &config.Config{Packages: map[string]*config.Package{pkg.Path: pkg}},
+		Stats: &AnalysisStats{
+			TotalCommits:     2,
+			MatchedCommits:   1,
+			UnmatchedCommits: 1,
+			OrphanedDirs:     []string{".github"},
+		},
+	}
+}
+
+func TestBuildCheckRun(t *testing.T) {
+	run := BuildCheckRun(checkRunResult(), false)
+
+	if run.HeadSHA != "ccc3333333333" {
+		t.Errorf("unexpected head SHA: %s", run.HeadSHA)
+	}
+	if run.Conclusion != "success" {
+		t.Errorf("expected success, got %s", run.Conclusion)
+	}
+	if run.Title != "1 release(s): api 1.1.0" {
+		t.Errorf("unexpected title: %s", run.Title)
+	}
+	if !strings.Contains(run.Summary, "`ddd4444..eee5555` (2 commits)") {
+		t.Errorf("expected merge range in summary, got:\n%s", run.Summary)
+	}
+	if !strings.Contains(run.Summary, "| api | minor | 1.0.0 → 1.1.0 | 1 |") {
+		t.Errorf("expected release row in summary, got:\n%s", run.Summary)
+	}
+	if !strings.Contains(run.Text, "`bbb2222` chore: tweak ci") {
+		t.Errorf("expected unmatched commit in text, got:\n%s", run.Text)
+	}
+	if !strings.Contains(run.Text, "`.github/`") {
+		t.Errorf("expected orphaned dir in text, got:\n%s", run.Text)
+	}
+}
+
+func TestBuildCheckRun_ManyUnmatched(t *testing.T) {
+	result := checkRunResult()
+	for i := 0; i < 5000; i++ {
+		result.Commits = append(result.Commits, &git.Commit{
+			SHA:         fmt.Sprintf("%040d", i),
+			ShortSHA:    fmt.Sprintf("%07d", i),
+			Type:        "chore",
+			Description: "tweak ci again, with a description long enough to add up",
+			Files:       []string{".github/ci.yml"},
+		})
+	}
+	result.Stats.UnmatchedCommits = 5001
+
+	run := BuildCheckRun(result, false)
+	if len(run.Text) > maxCheckRunText {
+		t.Errorf("expected text within %d characters, got %d", maxCheckRunText, len(run.Text))
+	}
+	if !strings.Contains(run.Text, "more\n") {
+		t.Errorf("expected the count of the unmatched commits left out, got:\n%s", run.Text[len(run.Text)-200:])
+	}
+	if !strings.Contains(run.Text, "`.github/`") {
+		t.Error("expected the orphaned directories kept")
+	}
+}
+
+func TestBuildCheckRun_DryRunNoReleases(t *testing.T) {
+	result := checkRunResult()
+	result.Releases = nil
+
+	run := BuildCheckRun(result, true)
+
+	if run.Conclusion != "neutral" {
+		t.Errorf("expected neutral for dry run, got %s", run.Conclusion)
+	}
+	if run.Title != "No releasable changes (dry run)" {
+		t.Errorf("unexpected title: %s", run.Title)
+	}
+	if strings.Contains(run.Summary, "| Component |") {
+		t.Error("expected no release table without releases")
+	}
+}
+
+func TestBuildCheckRun_Accumulated(t *testing.T) {
+	result := checkRunResult()
+	result.Accumulated = true
+
+	run := BuildCheckRun(result, false)
+
+	if !strings.Contains(run.Summary, "since each package's last release tag up to `ccc3333` (2 commits)") {
+		t.Errorf("expected accumulated range in summary, got:\n%s", run.Summary)
+	}
+}
+
+func TestCreateCheckRun(t *testing.T) {
+	var got []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		got = args
+		return []byte(`{"html_url": "https://github.com/o/r/runs/1"}`), nil
+	})
+
+	run := BuildCheckRun(checkRunResult(), false)
+	if err := CreateCheckRun("", run); err != nil {
+		t.Fatalf("CreateCheckRun failed: %v", err)
+	}
+
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{"repos/{owner}/{repo}/check-runs", "head_sha=ccc3333333333", "conclusion=success", "output[title]=1 release(s): api 1.1.0"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %q in gh args:\n%s", want, joined)
+		}
+	}
+	if run.HTMLURL != "https://github.com/o/r/runs/1" {
+		t.Errorf("expected HTMLURL to be set, got %q", run.HTMLURL)
+	}
+}
@@ -0,0 +1,150 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Commit modes for ApplyAndCommit.
+const (
+	// CommitSingle commits every release's changes together.
+	CommitSingle = "single"
+
+	// CommitPerPackage commits each release's changes separately.
+	CommitPerPackage = "per-package"
+)
+
+// CommitModes lists the valid commit modes.
+var CommitModes = []string{CommitSingle, CommitPerPackage}
+
+// CommitOptions configures ApplyAndCommit.
+type CommitOptions struct {
+	// Mode is CommitSingle or CommitPerPackage.
+	Mode string
+
+	// ViaAPI creates the commits through the GitHub Git Data API and moves
+	// the released branch to them, instead of committing locally. The
+	// commits are made by the gh token's identity (e.g., a GitHub App, whose
+	// commits GitHub signs), for branches that forbid direct pushes.
+	ViaAPI bool
+
+	// Push pushes the local release commits to the released branch on
+	// DefaultRemote, so release tags can point at them. The API commits are
+	// already on GitHub.
+	Push bool
+}
+
+// ApplyAndCommit writes the release changes like Apply and commits them.
+// With CommitPerPackage, each release gets its own commit ("chore(jarvis):
+// release 0.2.0") holding its files and its manifest entry, so history and
+// blame stay per component and a release can be reverted on its own.
+// Each release's ReleaseSHA is set to its commit, which its tag then points
+// at. Returns the SHAs of the commits made.
+func ApplyAndCommit(result *AnalysisResult, opts *CommitOptions) ([]string, error) {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotNil(opts, "opts")
+	contracts.RequireOneOf(opts.Mode, CommitModes, "unknown commit mode %q", opts.Mode)
+	if err := CheckFreeze(result); err != nil {
+		return nil, err
+	}
+	if err := checkVersions(result); err != nil {
+		return nil, err
+	}
+
+	groups := [][]*PackageRelease{result.Releases}
+	if opts.Mode == CommitPerPackage {
+		groups = nil
+		for _, rel := range result.Releases {
+			groups = append(groups, []*PackageRelease{rel})
+		}
+	}
+
+	repoRoot := result.Config.RepoRoot
+	var api *apiCommitter
+	if opts.ViaAPI {
+		if result.Branch == "" || result.MergeInfo == nil || result.MergeInfo.HeadSHA == "" {
+			return nil, fmt.Errorf("committing through the GitHub API needs a branch and HEAD commit (pass --branch when HEAD is detached)")
+		}
+		api = &apiCommitter{repoPath: repoRoot, parent: result.MergeInfo.HeadSHA, head: result.MergeInfo.HeadSHA}
+	} else if opts.Push && result.Branch == "" {
+		return nil, fmt.Errorf("pushing the release commits needs a branch (pass --branch when HEAD is detached)")
+	}
+
+	var shas []string
+	for _, releases := range groups {
+		if len(releases) == 0 {
+			continue
+		}
+
+		// Each group is planned against the files the previous one wrote,
+		// so the shared manifest only gains the group's own entries
+		group := *result
+		group.Releases = releases
+		changes, err := PlanChanges(&group)
+		if err != nil {
+			return shas, err
+		}
+		if err := writeChanges(repoRoot, changes); err != nil {
+			return shas, err
+		}
+
+		message := releaseCommitMessage(releases)
+		var sha string
+		if api != nil {
+			sha, err = api.commit(message, changes)
+		} else {
+			sha, err = git.CommitPaths(repoRoot, message, changedPaths(changes))
+		}
+		if err != nil {
+			return shas, err
+		}
+		slog.Debug("committed release", "sha", sha, "releases", len(releases))
+		shas = append(shas, sha)
+		for _, rel := range releases {
+			rel.ReleaseSHA = sha
+		}
+	}
+
+	if len(shas) == 0 {
+		return shas, nil
+	}
+	if api != nil {
+		if err := api.updateBranch(result.Branch); err != nil {
+			return shas, err
+		}
+	} else if opts.Push {
+		if err := git.PushBranch(repoRoot, DefaultRemote, shas[len(shas)-1], result.Branch); err != nil {
+			return shas, fmt.Errorf("failed to push release commits: %w", err)
+		}
+		slog.Info("pushed release commits", "remote", DefaultRemote, "branch", result.Branch, "commits", len(shas))
+	}
+	return shas, nil
+}
+
+// changedPaths returns the paths of planned changes.
+func changedPaths(changes []*FileChange) []string {
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+// releaseCommitMessage builds the commit message for releases. A single
+// release is named in the subject; several are listed in the body.
+func releaseCommitMessage(releases []*PackageRelease) string {
+	if len(releases) == 1 {
+		return fmt.Sprintf("chore(%s): release %s", releases[0].Package.Component, releases[0].NewVersion)
+	}
+
+	var msg strings.Builder
+	msg.WriteString("chore: release\n\n")
+	for _, rel := range releases {
+		msg.WriteString(fmt.Sprintf("- %s %s\n", rel.Package.Component, rel.NewVersion))
+	}
+	return msg.String()
+}
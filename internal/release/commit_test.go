@@ -0,0 +1,158 @@
+package release
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// setupTwoPackageRepo creates a repo with service-a and service-b at 0.1.0,
+// and a commit changing both.
+func setupTwoPackageRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-b": "0.1.0"
+	}`)
+	writeFile(t, dir, "workloads/service-b/VERSION", "0.1.0\n")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add service-b")
+
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: fix both services")
+	return dir
+}
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestApplyAndCommit_PerPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(result.Releases))
+	}
+
+	shas, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitPerPackage})
+	if err != nil {
+		t.Fatalf("ApplyAndCommit failed: %v", err)
+	}
+	if len(shas) != 2 {
+		t.Fatalf("expected 2 commits, got %d", len(shas))
+	}
+
+	subjects := gitOutput(t, dir, "log", "--format=%s", "-2")
+	if subjects != "chore(service-b): release 0.1.1\nchore(service-a): release 0.1.1" {
+		t.Errorf("unexpected commit subjects:\n%s", subjects)
+	}
+
+	files := gitOutput(t, dir, "show", "--name-only", "--format=", shas[0])
+	if files != "release-please-manifest.json\nworkloads/service-a/CHANGELOG.md\nworkloads/service-a/VERSION" {
+		t.Errorf("unexpected files in service-a commit:\n%s", files)
+	}
+
+	// The first commit's manifest only has service-a's bump
+	manifest := gitOutput(t, dir, "show", shas[0]+":release-please-manifest.json")
+	if !strings.Contains(manifest, `"workloads/service-a": "0.1.1"`) || !strings.Contains(manifest, `"workloads/service-b": "0.1.0"`) {
+		t.Errorf("unexpected manifest after the service-a commit:\n%s", manifest)
+	}
+	manifest = gitOutput(t, dir, "show", shas[1]+":release-please-manifest.json")
+	if !strings.Contains(manifest, `"workloads/service-b": "0.1.1"`) || !strings.Contains(manifest, `"workloads/service-a": "0.1.1"`) {
+		t.Errorf("unexpected manifest after the service-b commit:\n%s", manifest)
+	}
+
+	if status := gitOutput(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean tree, got:\n%s", status)
+	}
+
+	// Each release is tagged at its own commit
+	for i, rel := range result.Releases {
+		if got := result.TargetSHA(rel); got != shas[i] {
+			t.Errorf("%s: expected target %s, got %s", rel.Package.Component, shas[i], got)
+		}
+	}
+}
+
+func TestApplyAndCommit_Push(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	remote := t.TempDir()
+	runCmd(t, remote, "git", "init", "--bare", "-q")
+	runCmd(t, dir, "git", "remote", "add", DefaultRemote, remote)
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Branch == "" {
+		t.Fatal("expected the checked-out branch")
+	}
+
+	shas, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitSingle, Push: true})
+	if err != nil {
+		t.Fatalf("ApplyAndCommit failed: %v", err)
+	}
+	if pushed := gitOutput(t, remote, "rev-parse", "refs/heads/"+result.Branch); pushed != shas[0] {
+		t.Errorf("expected the release commit %s pushed, got %s", shas[0], pushed)
+	}
+	if report := BuildReleaseReport(result, ""); report.Releases[0].SHA != shas[0] {
+		t.Errorf("expected the report to name the release commit, got %s", report.Releases[0].SHA)
+	}
+}
+
+func TestApplyAndCommit_Single(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	shas, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitSingle})
+	if err != nil {
+		t.Fatalf("ApplyAndCommit failed: %v", err)
+	}
+	if len(shas) != 1 {
+		t.Fatalf("expected 1 commit, got %d", len(shas))
+	}
+
+	message := gitOutput(t, dir, "log", "--format=%B", "-1")
+	if message != "chore: release\n\n- service-a 0.1.1\n- service-b 0.1.1" {
+		t.Errorf("unexpected commit message:\n%s", message)
+	}
+	if status := gitOutput(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean tree, got:\n%s", status)
+	}
+}
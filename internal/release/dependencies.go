@@ -0,0 +1,92 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// scheduleDependents adds a patch release for each package that depends on
+// a released one (see config.Dependencies), transitively, so dependents
+// pick up the new version. A dependent in a linked-versions group brings
+// its group along. Dependents already being released are left as they are.
+//
+// Each added release records its DependencyChain: the released component
+// that started it, then each dependent in between, e.g. [api-client sdk]
+// for a web app depending on an sdk depending on api-client.
+func scheduleDependents(cfg *config.Config, releases []*PackageRelease, treatPreMajorAsMinor bool) ([]*PackageRelease, error) {
+	if len(cfg.Dependencies) == 0 {
+		return releases, nil
+	}
+
+	released := make(map[string]bool)
+	for _, rel := range releases {
+		released[rel.Package.Component] = true
+	}
+
+	// Breadth-first, so each dependent records its shortest chain
+	queue := append([]*PackageRelease(nil), releases...)
+	for len(queue) > 0 {
+		rel := queue[0]
+		queue = queue[1:]
+		for _, dependent := range cfg.Dependents(rel.Package.Component) {
+			if released[dependent] {
+				continue
+			}
+			pkg := cfg.PackageForComponent(dependent)
+			if pkg == nil {
+				continue
+			}
+			chain := append(append([]string(nil), rel.DependencyChain...), rel.Package.Component)
+
+			group := cfg.GetLinkedPackages(pkg)
+			base := ""
+			if pkg.LinkedGroup != "" {
+				base = linkedBase(group)
+			}
+			for _, member := range group {
+				added, err := createReleaseFrom(member, base, nil, version.Patch, treatPreMajorAsMinor)
+				if err != nil {
+					return nil, err
+				}
+				if member == pkg {
+					added.DependencyChain = chain
+				}
+				slog.Debug("scheduling dependent release", "component", member.Component, "dependency", rel.Package.Component, "new_version", added.NewVersion)
+				released[member.Component] = true
+				releases = append(releases, added)
+				queue = append(queue, added)
+			}
+		}
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Package.Path < releases[j].Package.Path
+	})
+	return releases, nil
+}
+
+// dependencyNote explains a release scheduled because a dependency was
+// released, e.g. "Dependency update: api-client 1.3.0 (via sdk 2.0.1).".
+// Other releases get "".
+func dependencyNote(result *AnalysisResult, rel *PackageRelease) string {
+	if len(rel.DependencyChain) == 0 {
+		return ""
+	}
+	versions := make(map[string]string)
+	for _, other := range result.Releases {
+		versions[other.Package.Component] = other.NewVersion
+	}
+	named := make([]string, len(rel.DependencyChain))
+	for i, component := range rel.DependencyChain {
+		named[i] = strings.TrimSpace(component + " " + versions[component])
+	}
+	if len(named) == 1 {
+		return fmt.Sprintf("Dependency update: %s.", named[0])
+	}
+	return fmt.Sprintf("Dependency update: %s (via %s).", named[0], strings.Join(named[1:], ", "))
+}
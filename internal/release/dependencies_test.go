@@ -0,0 +1,208 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+func TestScheduleDependents(t *testing.T) {
+	client := &config.Package{Path: "libs/client", Component: "api-client", CurrentVersion: "1.2.0"}
+	sdk := &config.Package{Path: "libs/sdk", Component: "sdk", CurrentVersion: "2.0.0"}
+	web := &config.Package{Path: "apps/web", Component: "web", CurrentVersion: "0.4.0"}
+	cli := &config.Package{Path: "apps/cli", Component: "cli", CurrentVersion: "3.1.0"}
+	cfg := &config.Config{
+		Packages: map[string]*config.Package{
+			client.Path: client, sdk.Path: sdk, web.Path: web, cli.Path: cli,
+		},
+		Dependencies: map[string][]string{
+			"sdk": {"api-client"},
+			"web": {"sdk", "api-client"},
+			"cli": {"sdk"},
+		},
+	}
+	releases := []*PackageRelease{
+		{Package: client, BumpType: version.Minor, OldVersion: "1.2.0", NewVersion: "1.3.0"},
+		{Package: cli, BumpType: version.Major, OldVersion: "3.1.0", NewVersion: "4.0.0"},
+	}
+
+	got, err := scheduleDependents(cfg, releases, true)
+	if err != nil {
+		t.Fatalf("scheduleDependents failed: %v", err)
+	}
+
+	type want struct {
+		version string
+		chain   string
+	}
+	wants := map[string]want{
+		"api-client": {"1.3.0", ""},
+		"sdk":        {"2.0.1", "api-client"},
+		"web":        {"0.4.1", "api-client"},
+		"cli":        {"4.0.0", ""},
+	}
+	if len(got) != len(wants) {
+		t.Fatalf("expected %d releases, got %d", len(wants), len(got))
+	}
+	for i, rel := range got {
+		if i > 0 && got[i-1].Package.Path > rel.Package.Path {
+			t.Errorf("releases not sorted by path: %s before %s", got[i-1].Package.Path, rel.Package.Path)
+		}
+		w := wants[rel.Package.Component]
+		if rel.NewVersion != w.version {
+			t.Errorf("%s: expected %s, got %s", rel.Package.Component, w.version, rel.NewVersion)
+		}
+		if chain := strings.Join(rel.DependencyChain, ","); chain != w.chain {
+			t.Errorf("%s: expected chain %q, got %q", rel.Package.Component, w.chain, chain)
+		}
+	}
+}
+
+func TestScheduleDependents_Transitive(t *testing.T) {
+	client := &config.Package{Path: "libs/client", Component: "api-client", CurrentVersion: "1.2.0"}
+	sdk := &config.Package{Path: "libs/sdk", Component: "sdk", CurrentVersion: "2.0.0"}
+	web := &config.Package{Path: "apps/web", Component: "web", CurrentVersion: "0.4.0"}
+	cfg := &config.Config{
+		Packages: map[string]*config.Package{client.Path: client, sdk.Path: sdk, web.Path: web},
+		Dependencies: map[string][]string{
+			"sdk": {"api-client"},
+			"web": {"sdk"},
+		},
+	}
+	releases := []*PackageRelease{
+		{Package: client, BumpType: version.Patch, OldVersion: "1.2.0", NewVersion: "1.2.1"},
+	}
+
+	got, err := scheduleDependents(cfg, releases, true)
+	if err != nil {
+		t.Fatalf("scheduleDependents failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 releases, got %d", len(got))
+	}
+	result := &AnalysisResult{Releases: got}
+	for _, rel := range got {
+		if rel.Package.Component != "web" {
+			continue
+		}
+		if chain := strings.Join(rel.DependencyChain, ","); chain != "api-client,sdk" {
+			t.Errorf("expected chain api-client,sdk, got %q", chain)
+		}
+		if note := dependencyNote(result, rel); note != "Dependency update: api-client 1.2.1 (via sdk 2.0.1)." {
+			t.Errorf("unexpected note %q", note)
+		}
+	}
+}
+
+func TestScheduleDependents_LinkedDependent(t *testing.T) {
+	client := &config.Package{Path: "libs/client", Component: "api-client", CurrentVersion: "1.2.0"}
+	web := &config.Package{Path: "apps/web", Component: "web", CurrentVersion: "0.4.0", LinkedGroup: "apps"}
+	admin := &config.Package{Path: "apps/admin", Component: "admin", CurrentVersion: "0.4.2", LinkedGroup: "apps"}
+	cfg := &config.Config{
+		Packages:     map[string]*config.Package{client.Path: client, web.Path: web, admin.Path: admin},
+		LinkedGroups: map[string][]string{"apps": {"web", "admin"}},
+		Dependencies: map[string][]string{"web": {"api-client"}},
+	}
+	releases := []*PackageRelease{
+		{Package: client, BumpType: version.Patch, OldVersion: "1.2.0", NewVersion: "1.2.1"},
+	}
+
+	got, err := scheduleDependents(cfg, releases, true)
+	if err != nil {
+		t.Fatalf("scheduleDependents failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected the linked group to come along, got %d releases", len(got))
+	}
+	for _, rel := range got[:2] {
+		if rel.NewVersion != "0.4.3" {
+			t.Errorf("%s: expected the group to converge on 0.4.3, got %s", rel.Package.Component, rel.NewVersion)
+		}
+		if hasChain := len(rel.DependencyChain) > 0; hasChain != (rel.Package == web) {
+			t.Errorf("%s: expected only web to record a chain, got %v", rel.Package.Component, rel.DependencyChain)
+		}
+	}
+}
+
+func TestScheduleDependents_NoDependencies(t *testing.T) {
+	pkg := &config.Package{Path: "libs/client", Component: "api-client", CurrentVersion: "1.2.0"}
+	cfg := &config.Config{Packages: map[string]*config.Package{pkg.Path: pkg}}
+	releases := []*PackageRelease{{Package: pkg, NewVersion: "1.2.1"}}
+
+	got, err := scheduleDependents(cfg, releases, true)
+	if err != nil {
+		t.Fatalf("scheduleDependents failed: %v", err)
+	}
+	if len(got) != 1 || got[0] != releases[0] {
+		t.Errorf("expected releases unchanged, got %+v", got)
+	}
+}
+
+func TestAnalyze_Dependencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"apps/jarvis-web": {"component": "jarvis-web"},
+			"libs/jarvis-api-client": {"component": "jarvis-api-client"}
+		},
+		"dependencies": {
+			"jarvis-web": ["jarvis-api-client"]
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"apps/jarvis-web": "2.4.0",
+		"libs/jarvis-api-client": "1.0.0"
+	}`)
+	writeFile(t, dir, "apps/jarvis-web/src/main.go", "// Web\n")
+	writeFile(t, dir, "libs/jarvis-api-client/src/client.go", "// Client\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+
+	writeFile(t, dir, "libs/jarvis-api-client/src/client.go", "// Client\n// Feature\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(jarvis-api-client): add endpoint")
+
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 2 {
+		t.Fatalf("expected jarvis-web released with its dependency, got %d releases", len(result.Releases))
+	}
+
+	report := BuildReleaseReport(result, "")
+	var web *ComponentRelease
+	for i := range report.Releases {
+		if report.Releases[i].Component == "jarvis-web" {
+			web = &report.Releases[i]
+		}
+	}
+	if web == nil {
+		t.Fatal("expected a jarvis-web release in the report")
+	}
+	if web.NewVersion != "2.4.1" || web.BumpType != "patch" {
+		t.Errorf("expected a patch release to 2.4.1, got %s %s", web.BumpType, web.NewVersion)
+	}
+	if len(web.DependencyChain) != 1 || web.DependencyChain[0] != "jarvis-api-client" || web.LinkedBump {
+		t.Errorf("expected dependency_chain [jarvis-api-client], got %v (linked_bump %v)", web.DependencyChain, web.LinkedBump)
+	}
+
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	changelog, err := os.ReadFile(filepath.Join(dir, "apps/jarvis-web/CHANGELOG.md"))
+	if err != nil {
+		t.Fatalf("expected a changelog for jarvis-web: %v", err)
+	}
+	if !strings.Contains(string(changelog), "Dependency update: jarvis-api-client 1.1.0.") {
+		t.Errorf("expected the dependency noted in the changelog, got:\n%s", changelog)
+	}
+}
@@ -0,0 +1,73 @@
+package release
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// imageVersionLabelRegex matches the OCI version label, quoted or not, in a
+// LABEL instruction or one of its continuation lines.
+var imageVersionLabelRegex = regexp.MustCompile(`(org\.opencontainers\.image\.version=)("?)([^"\s\\]+)("?)`)
+
+// argDefaultRegex matches an ARG instruction with a default value.
+var argDefaultRegex = regexp.MustCompile(`^(\s*ARG\s+(\w+)=)("?)([^"\s]+)("?)`)
+
+// isDockerfile reports whether a file name looks like a Dockerfile or
+// Containerfile (Dockerfile, Dockerfile.prod, api.Dockerfile, ...).
+func isDockerfile(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range []string{"dockerfile", "containerfile"} {
+		if lower == prefix || strings.HasPrefix(lower, prefix+".") || strings.HasSuffix(lower, "."+prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// planDockerfiles plans version updates to the Dockerfiles a package owns.
+func planDockerfiles(result *AnalysisResult, rel *PackageRelease) ([]*FileChange, error) {
+	return planPackageFiles(result, rel, isDockerfile, func(content string) string {
+		return updateDockerfile(content, rel.Package.DockerfileArgs, rel.NewVersion)
+	})
+}
+
+// updateDockerfile sets org.opencontainers.image.version labels to the new
+// version, and updates the defaults of the named ARGs (so ARG VERSION=1.2.3
+// follows releases when args is ["VERSION"], while ARG GO_VERSION=1.22
+// doesn't). A "v" prefix and quoting are kept; labels set from a variable and
+// ARG defaults that aren't versions are skipped.
+func updateDockerfile(content string, args []string, newVersion string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		line = imageVersionLabelRegex.ReplaceAllStringFunc(line, func(match string) string {
+			parts := imageVersionLabelRegex.FindStringSubmatch(match)
+			value, ok := replaceVersionValue(parts[3], newVersion)
+			if !ok {
+				return match
+			}
+			return parts[1] + parts[2] + value + parts[4]
+		})
+
+		if parts := argDefaultRegex.FindStringSubmatch(line); parts != nil && slices.Contains(args, parts[2]) {
+			if value, ok := replaceVersionValue(parts[4], newVersion); ok {
+				line = parts[1] + parts[3] + value + parts[5] + line[len(parts[0]):]
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// replaceVersionValue returns newVersion in place of a value that is a bare
+// version, keeping a "v" prefix. Returns false if the value isn't a version.
+func replaceVersionValue(value, newVersion string) (string, bool) {
+	prefix := ""
+	if strings.HasPrefix(value, "v") {
+		prefix = "v"
+	}
+	if markedVersionRegex.FindString(value) != strings.TrimPrefix(value, prefix) {
+		return value, false
+	}
+	return prefix + newVersion, true
+}
@@ -0,0 +1,130 @@
+package release
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIsDockerfile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Dockerfile", true},
+		{"dockerfile", true},
+		{"Dockerfile.prod", true},
+		{"api.Dockerfile", true},
+		{"Containerfile", true},
+		{"Dockerfile-notes.md", false},
+		{"docker-compose.yml", false},
+		{"main.go", false},
+	}
+
+	for _, tc := range tests {
+		if got := isDockerfile(tc.name); got != tc.want {
+			t.Errorf("isDockerfile(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestUpdateDockerfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "quoted label",
+			content: "FROM scratch\nLABEL org.opencontainers.image.version=\"1.2.3\"\n",
+			want:    "FROM scratch\nLABEL org.opencontainers.image.version=\"1.3.0\"\n",
+		},
+		{
+			name:    "label continuation with v prefix",
+			content: "LABEL org.opencontainers.image.title=api \\\n      org.opencontainers.image.version=v1.0.0\n",
+			want:    "LABEL org.opencontainers.image.title=api \\\n      org.opencontainers.image.version=v1.3.0\n",
+		},
+		{
+			name:    "label from a variable",
+			content: "LABEL org.opencontainers.image.version=\"${VERSION}\"\n",
+			want:    "LABEL org.opencontainers.image.version=\"${VERSION}\"\n",
+		},
+		{
+			name:    "configured arg defaults",
+			content: "ARG VERSION=1.2.3\nARG APP_VERSION=\"v1.2.0\" # keep\n",
+			want:    "ARG VERSION=1.3.0\nARG APP_VERSION=\"v1.3.0\" # keep\n",
+		},
+		{
+			name:    "unlisted arg holding the old version",
+			content: "ARG BASE_VERSION=1.2.3\nARG GO_VERSION=1.22\n",
+			want:    "ARG BASE_VERSION=1.2.3\nARG GO_VERSION=1.22\n",
+		},
+		{
+			name:    "configured arg that isn't a version",
+			content: "ARG VERSION=latest\nARG VERSION\n",
+			want:    "ARG VERSION=latest\nARG VERSION\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := updateDockerfile(tc.content, []string{"VERSION", "APP_VERSION"}, "1.3.0"); got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPlanChanges_Dockerfiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {
+				"component": "service-a",
+				"dockerfile-args": ["VERSION"],
+				"exclude-paths": ["workloads/service-a/examples"]
+			},
+			"workloads/service-a/plugin": {"component": "plugin"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-a/plugin": "0.1.0"
+	}`)
+	writeFile(t, dir, "workloads/service-a/Dockerfile", "FROM scratch\nARG VERSION=0.1.0\nLABEL org.opencontainers.image.version=\"0.1.0\"\n")
+	writeFile(t, dir, "workloads/service-a/deploy/api.Dockerfile", "FROM scratch\nARG BASE_VERSION=0.1.0\n")
+	writeFile(t, dir, "workloads/service-a/examples/Dockerfile", "ARG VERSION=0.1.0\n")
+	writeFile(t, dir, "workloads/service-a/plugin/Dockerfile", "ARG VERSION=0.1.0\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add Dockerfiles")
+
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	var dockerfiles []*FileChange
+	for _, c := range changes {
+		if isDockerfile(filepath.Base(c.Path)) {
+			dockerfiles = append(dockerfiles, c)
+		}
+	}
+	if len(dockerfiles) != 1 || dockerfiles[0].Path != "workloads/service-a/Dockerfile" {
+		t.Fatalf("expected only service-a's own Dockerfile to change, got %+v", dockerfiles)
+	}
+	want := "FROM scratch\nARG VERSION=0.1.1\nLABEL org.opencontainers.image.version=\"0.1.1\"\n"
+	if dockerfiles[0].New != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, dockerfiles[0].New)
+	}
+}
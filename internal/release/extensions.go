@@ -0,0 +1,205 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/internal/yaml"
+	"github.com/dsswift/release-damnit/pkg/extension"
+)
+
+// defaultVersioning is the strategy used when a package doesn't set versioning.
+const defaultVersioning = "default"
+
+func init() {
+	extension.RegisterVersionStrategy(defaultVersioning, extension.VersionStrategyFunc(defaultStrategy))
+	for _, bt := range []version.BumpType{version.Patch, version.Minor, version.Major} {
+		extension.RegisterVersionStrategy("always-bump-"+bt.String(), alwaysBumpStrategy(bt))
+	}
+	extension.RegisterFileUpdater("generic", extension.FileUpdaterFunc(genericUpdate))
+	extension.RegisterFileUpdater("yaml", extension.FileUpdaterFunc(yamlUpdate))
+	extension.RegisterFileUpdater("pom", extension.FileUpdaterFunc(pomUpdate))
+	extension.RegisterFileUpdater("gradle", extension.FileUpdaterFunc(gradleUpdate))
+}
+
+// defaultStrategy bumps by the type implied by the commits.
+func defaultStrategy(req *extension.VersionRequest) (string, error) {
+	v, err := version.Parse(req.CurrentVersion)
+	if err != nil {
+		return "", err
+	}
+	bt, err := version.ParseBumpType(req.BumpType)
+	if err != nil {
+		return "", err
+	}
+	return v.Bump(bt, req.TreatPreMajorAsMinor).String(), nil
+}
+
+// alwaysBumpStrategy bumps by a fixed type regardless of the commits.
+func alwaysBumpStrategy(bt version.BumpType) extension.VersionStrategy {
+	return extension.VersionStrategyFunc(func(req *extension.VersionRequest) (string, error) {
+		v, err := version.Parse(req.CurrentVersion)
+		if err != nil {
+			return "", err
+		}
+		return v.Bump(bt, false).String(), nil
+	})
+}
+
+// markedVersionRegex matches a semver anywhere in a line.
+var markedVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+(?:-[0-9A-Za-z-.]+)?(?:\+[0-9A-Za-z-.]+)?`)
+
+// genericUpdate replaces versions the way Release Please's generic updater does:
+// on any line containing x-release-please-version, and on every line between
+// x-release-please-start-version and x-release-please-end.
+func genericUpdate(req *extension.UpdateRequest) (string, error) {
+	if req.Content == "" {
+		return "", fmt.Errorf("%s does not exist or is empty", req.Path)
+	}
+
+	lines := strings.Split(req.Content, "\n")
+	inBlock := false
+	for i, line := range lines {
+		switch {
+		case strings.Contains(line, "x-release-please-start-version"):
+			inBlock = true
+		case strings.Contains(line, "x-release-please-end"):
+			inBlock = false
+		case inBlock || strings.Contains(line, "x-release-please-version"):
+			lines[i] = markedVersionRegex.ReplaceAllString(line, req.NewVersion)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// yamlUpdate sets the value at the entry's jsonpath option to the new version,
+// like Release Please's yaml updater, e.g. $.image.tag in Helm values or
+// $.images[?(@.name=='api')].newTag in a kustomization. Formatting and
+// comments are kept. For an image reference (repo:tag) only the tag changes,
+// and a "v" prefix stays.
+func yamlUpdate(req *extension.UpdateRequest) (string, error) {
+	if req.Content == "" {
+		return "", fmt.Errorf("%s does not exist or is empty", req.Path)
+	}
+	path, _ := req.Options["jsonpath"].(string)
+	if path == "" {
+		return "", fmt.Errorf("%s: yaml extra-files entries need a jsonpath", req.Path)
+	}
+
+	return yaml.ReplaceScalar(req.Content, path, func(old string) string {
+		prefix, _, digest := splitVersionValue(old)
+		if digest != "" {
+			// The digest pins the old image, so it can't be kept with a new tag
+			slog.Warn("dropping image digest pinned to the previous version", "path", req.Path, "digest", digest)
+		}
+		return prefix + req.NewVersion
+	})
+}
+
+// splitVersionValue splits a manifest value holding a version into what
+// comes before the version (an image reference's "repo:" and a "v"), the
+// version itself, and an image reference's "@sha256:..." digest, if any.
+func splitVersionValue(value string) (prefix, ver, digest string) {
+	if i := strings.Index(value, "@"); i >= 0 {
+		value, digest = value[:i], value[i:]
+	}
+	if i := strings.LastIndex(value, ":"); i >= 0 && !strings.Contains(value[i:], "/") {
+		prefix, value = value[:i+1], value[i+1:]
+	}
+	if len(value) > 1 && value[0] == 'v' && value[1] >= '0' && value[1] <= '9' {
+		prefix, value = prefix+"v", value[1:]
+	}
+	return prefix, value, digest
+}
+
+// validateExtensions checks that every package's versioning strategy and
+// extra-files updaters are registered.
+func validateExtensions(cfg *config.Config) error {
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		if pkg.Versioning != "" {
+			if _, ok := extension.LookupVersionStrategy(pkg.Versioning); !ok {
+				return fmt.Errorf("package %s: unknown versioning %q (available: %s)",
+					pkg.Path, pkg.Versioning, strings.Join(extension.VersionStrategies(), ", "))
+			}
+		}
+		for _, extra := range pkg.ExtraFiles {
+			if _, ok := extension.LookupFileUpdater(extra.Type); !ok {
+				return fmt.Errorf("package %s: unknown extra-files type %q for %s (available: %s)",
+					pkg.Path, extra.Type, extra.Path, strings.Join(extension.FileUpdaters(), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// nextVersion calculates a package's next version with its versioning strategy.
+func nextVersion(pkg *config.Package, oldVersion string, commits []*git.Commit, bumpType version.BumpType, treatPreMajorAsMinor bool) (string, error) {
+	name := pkg.Versioning
+	if name == "" {
+		name = defaultVersioning
+	}
+	strategy, ok := extension.LookupVersionStrategy(name)
+	if !ok {
+		return "", fmt.Errorf("unknown versioning %q", name)
+	}
+
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		messages = append(messages, buildCommitMessage(c))
+	}
+
+	newVersion, err := strategy.NextVersion(&extension.VersionRequest{
+		Component:            pkg.Component,
+		CurrentVersion:       oldVersion,
+		BumpType:             bumpType.String(),
+		TreatPreMajorAsMinor: treatPreMajorAsMinor,
+		Messages:             messages,
+	})
+	if err != nil {
+		return "", fmt.Errorf("versioning %q failed: %w", name, err)
+	}
+
+	// Everything downstream (manifest, tags, reports) assumes semver
+	parsed, err := version.Parse(newVersion)
+	if err != nil {
+		return "", fmt.Errorf("versioning %q returned invalid version %q: %w", name, newVersion, err)
+	}
+	if old, err := version.Parse(oldVersion); err == nil && parsed.Compare(old) <= 0 {
+		return "", fmt.Errorf("versioning %q returned %s, which is not greater than %s", name, newVersion, oldVersion)
+	}
+	return parsed.String(), nil
+}
+
+// planExtraFile plans an extra-files update with its registered updater.
+func planExtraFile(repoRoot string, rel *PackageRelease, extra *config.ExtraFile) (*FileChange, error) {
+	updater, ok := extension.LookupFileUpdater(extra.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown extra-files type %q", extra.Type)
+	}
+
+	path := filepath.Join(rel.Package.Path, extra.Path)
+	existing, _, err := readOptional(filepath.Join(repoRoot, path))
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := updater.Update(&extension.UpdateRequest{
+		Component:  rel.Package.Component,
+		Path:       path,
+		Content:    existing,
+		OldVersion: rel.OldVersion,
+		NewVersion: rel.NewVersion,
+		Options:    extra.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileChange{Path: path, Old: existing, New: updated}, nil
+}
@@ -0,0 +1,246 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/pkg/extension"
+)
+
+func TestGenericUpdate(t *testing.T) {
+	content := `package api
+
+const Version = "1.2.3" // x-release-please-version
+
+const Other = "1.2.3"
+
+// x-release-please-start-version
+image: api:1.2.3
+chart: 1.2.3
+// x-release-please-end
+`
+	got, err := genericUpdate(&extension.UpdateRequest{Path: "version.go", Content: content, NewVersion: "1.3.0"})
+	if err != nil {
+		t.Fatalf("genericUpdate failed: %v", err)
+	}
+
+	if !strings.Contains(got, `const Version = "1.3.0" // x-release-please-version`) {
+		t.Errorf("marked line not updated:\n%s", got)
+	}
+	if !strings.Contains(got, `const Other = "1.2.3"`) {
+		t.Errorf("unmarked line should be unchanged:\n%s", got)
+	}
+	if !strings.Contains(got, "image: api:1.3.0\nchart: 1.3.0\n") {
+		t.Errorf("block not updated:\n%s", got)
+	}
+
+	if _, err := genericUpdate(&extension.UpdateRequest{Path: "missing.go"}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestYAMLUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		jsonpath string
+		want     string
+	}{
+		{
+			name:     "helm values tag",
+			content:  "image:\n  repository: ghcr.io/acme/api\n  tag: \"1.2.3\" # bumped on release\n",
+			jsonpath: "$.image.tag",
+			want:     "image:\n  repository: ghcr.io/acme/api\n  tag: \"1.3.0\" # bumped on release\n",
+		},
+		{
+			name:     "kustomize newTag with v prefix",
+			content:  "images:\n- name: ghcr.io/acme/web\n  newTag: v0.9.0\n- name: ghcr.io/acme/api\n  newTag: v1.2.3\n",
+			jsonpath: "$.images[?(@.name=='ghcr.io/acme/api')].newTag",
+			want:     "images:\n- name: ghcr.io/acme/web\n  newTag: v0.9.0\n- name: ghcr.io/acme/api\n  newTag: v1.3.0\n",
+		},
+		{
+			name:     "image reference",
+			content:  "spec:\n  containers:\n    - name: api\n      image: registry:5000/acme/api:1.2.3\n",
+			jsonpath: "$.spec.containers[0].image",
+			want:     "spec:\n  containers:\n    - name: api\n      image: registry:5000/acme/api:1.3.0\n",
+		},
+		{
+			name:     "image reference with digest",
+			content:  "image: foo:1.0.0@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945\n",
+			jsonpath: "$.image",
+			want:     "image: foo:1.3.0\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := yamlUpdate(&extension.UpdateRequest{
+				Path:       "values.yaml",
+				Content:    tc.content,
+				NewVersion: "1.3.0",
+				Options:    map[string]interface{}{"jsonpath": tc.jsonpath},
+			})
+			if err != nil {
+				t.Fatalf("yamlUpdate failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := yamlUpdate(&extension.UpdateRequest{Path: "values.yaml", Content: "tag: 1.0.0\n"}); err == nil {
+		t.Error("expected error without a jsonpath")
+	}
+	if _, err := yamlUpdate(&extension.UpdateRequest{Path: "values.yaml", Options: map[string]interface{}{"jsonpath": "$.tag"}}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestNextVersion_Strategies(t *testing.T) {
+	commits := []*git.Commit{{Type: "fix", Description: "bug"}}
+
+	tests := []struct {
+		versioning string
+		want       string
+	}{
+		{"", "1.2.4"},
+		{"default", "1.2.4"},
+		{"always-bump-minor", "1.3.0"},
+		{"always-bump-major", "2.0.0"},
+	}
+	for _, tc := range tests {
+		pkg := &config.Package{Component: "api", Versioning: tc.versioning}
+		got, err := nextVersion(pkg, "1.2.3", commits, version.Patch, false)
+		if err != nil {
+			t.Fatalf("%q: nextVersion failed: %v", tc.versioning, err)
+		}
+		if got != tc.want {
+			t.Errorf("%q: expected %s, got %s", tc.versioning, tc.want, got)
+		}
+	}
+}
+
+// testStrategies backs strategies registered by tests. The registry can't
+// unregister, so re-running a test (-count > 1) swaps the function instead.
+var testStrategies = make(map[string]func(r *extension.VersionRequest) (string, error))
+
+func registerTestStrategy(name string, fn func(r *extension.VersionRequest) (string, error)) {
+	if _, ok := testStrategies[name]; !ok {
+		extension.RegisterVersionStrategy(name, extension.VersionStrategyFunc(func(r *extension.VersionRequest) (string, error) {
+			return testStrategies[name](r)
+		}))
+	}
+	testStrategies[name] = fn
+}
+
+func TestNextVersion_CustomStrategy(t *testing.T) {
+	var req *extension.VersionRequest
+	registerTestStrategy("test-calver", func(r *extension.VersionRequest) (string, error) {
+		req = r
+		return "2026.10.0", nil
+	})
+	registerTestStrategy("test-bad", func(r *extension.VersionRequest) (string, error) {
+		return "latest", nil
+	})
+	registerTestStrategy("test-backwards", func(r *extension.VersionRequest) (string, error) {
+		return "1.0.0", nil
+	})
+
+	commits := []*git.Commit{{Type: "feat", Scope: "api", Description: "add endpoint"}}
+	pkg := &config.Package{Component: "api", Versioning: "test-calver"}
+
+	got, err := nextVersion(pkg, "2026.9.4", commits, version.Minor, true)
+	if err != nil {
+		t.Fatalf("nextVersion failed: %v", err)
+	}
+	if got != "2026.10.0" {
+		t.Errorf("expected 2026.10.0, got %s", got)
+	}
+	if req.Component != "api" || req.BumpType != "minor" || !req.TreatPreMajorAsMinor {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if len(req.Messages) != 1 || req.Messages[0] != "feat(api): add endpoint" {
+		t.Errorf("unexpected messages: %v", req.Messages)
+	}
+
+	pkg.Versioning = "test-bad"
+	if _, err := nextVersion(pkg, "1.2.3", commits, version.Minor, false); err == nil {
+		t.Error("expected error for invalid version")
+	}
+	pkg.Versioning = "test-backwards"
+	if _, err := nextVersion(pkg, "1.2.3", commits, version.Minor, false); err == nil {
+		t.Error("expected error for version that doesn't increase")
+	}
+}
+
+func TestValidateExtensions(t *testing.T) {
+	cfg := &config.Config{Packages: map[string]*config.Package{
+		"api": {Path: "api", Component: "api", ExtraFiles: []*config.ExtraFile{{Type: "generic", Path: "v.go"}}},
+	}}
+	if err := validateExtensions(cfg); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	cfg.Packages["api"].Versioning = "nope"
+	if err := validateExtensions(cfg); err == nil || !strings.Contains(err.Error(), `unknown versioning "nope"`) {
+		t.Errorf("expected unknown versioning error, got %v", err)
+	}
+
+	cfg.Packages["api"].Versioning = ""
+	cfg.Packages["api"].ExtraFiles[0].Type = "nope"
+	if err := validateExtensions(cfg); err == nil || !strings.Contains(err.Error(), `unknown extra-files type "nope"`) {
+		t.Errorf("expected unknown updater error, got %v", err)
+	}
+}
+
+func TestPlanChanges_ExtraFiles(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite("release-please-manifest.json", `{"api": "1.0.0"}`)
+	mustWrite("api/VERSION", "1.0.0\n")
+	mustWrite("api/version.go", "const Version = \"1.0.0\" // x-release-please-version\n")
+
+	pkg := &config.Package{
+		Path:          "api",
+		Component:     "api",
+		ChangelogPath: "CHANGELOG.md",
+		ExtraFiles:    []*config.ExtraFile{{Type: "generic", Path: "version.go"}},
+	}
+	result := &AnalysisResult{
+		Config:   &config.Config{RepoRoot: dir, Packages: map[string]*config.Package{"api": pkg}},
+		Releases: []*PackageRelease{{Package: pkg, BumpType: version.Minor, OldVersion: "1.0.0", NewVersion: "1.1.0"}},
+	}
+
+	changes, err := PlanChanges(result)
+	if err != nil {
+		t.Fatalf("PlanChanges failed: %v", err)
+	}
+
+	var extra *FileChange
+	for _, c := range changes {
+		if c.Path == filepath.Join("api", "version.go") {
+			extra = c
+		}
+	}
+	if extra == nil {
+		t.Fatalf("expected change to api/version.go, got %d changes", len(changes))
+	}
+	if extra.New != "const Version = \"1.1.0\" // x-release-please-version\n" {
+		t.Errorf("unexpected extra file content: %q", extra.New)
+	}
+}
@@ -0,0 +1,37 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// FreezeError is returned by Apply, ApplyAndCommit, and CreateGitHubReleases
+// while a release freeze is in effect, unless Options.OverrideFreeze is set.
+type FreezeError struct {
+	Freeze *config.Freeze
+}
+
+func (e *FreezeError) Error() string {
+	return fmt.Sprintf("release freeze %q is in effect until %s (pass --override-freeze to release anyway)",
+		e.Freeze.Name, e.Freeze.End.Format(time.RFC3339))
+}
+
+// CheckFreeze returns a FreezeError if a freeze is in effect and wasn't
+// overridden.
+func CheckFreeze(result *AnalysisResult) error {
+	if result.Freeze == nil || result.FreezeOverridden {
+		return nil
+	}
+	return &FreezeError{Freeze: result.Freeze}
+}
+
+// freezeAt returns the freeze in effect when the analysis runs.
+func freezeAt(cfg *config.Config, opts *Options) *config.Freeze {
+	now := time.Now()
+	if opts.Clock != nil {
+		now = opts.Clock()
+	}
+	return cfg.FreezeAt(now)
+}
@@ -0,0 +1,101 @@
+package release
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupFrozenRepo creates a repo with a releasable fix and a year-end freeze.
+func setupFrozenRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, ".release-damnit.yaml", "freeze-windows:\n  - name: year-end\n    from: 2024-12-20\n    to: 2025-01-02\n")
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+	return dir
+}
+
+func TestAnalyze_Freeze(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupFrozenRepo(t)
+	frozen := func() time.Time { return time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC) }
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, Clock: frozen})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 {
+		t.Fatalf("expected the release still analyzed, got %+v", result.Releases)
+	}
+	if result.Freeze == nil || result.Freeze.Name != "year-end" {
+		t.Fatalf("expected the year-end freeze, got %+v", result.Freeze)
+	}
+
+	var freezeErr *FreezeError
+	err = Apply(result, false)
+	if !errors.As(err, &freezeErr) {
+		t.Fatalf("expected a FreezeError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--override-freeze") {
+		t.Errorf("expected the error to mention --override-freeze, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "workloads/service-a/VERSION")); !strings.HasPrefix(string(data), "0.1.0") {
+		t.Errorf("expected VERSION unchanged during the freeze, got %q", data)
+	}
+	if err := Apply(result, true); err != nil {
+		t.Errorf("expected a dry run to be allowed, got %v", err)
+	}
+	if _, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitSingle}); !errors.As(err, &freezeErr) {
+		t.Errorf("expected ApplyAndCommit to refuse, got %v", err)
+	}
+	if _, err := CreateGitHubReleases(result, &GitHubReleaseOptions{RepoPath: dir}); !errors.As(err, &freezeErr) {
+		t.Errorf("expected CreateGitHubReleases to refuse, got %v", err)
+	}
+
+	report := BuildReleaseReport(result, "")
+	if report.Freeze == nil || report.Freeze.Name != "year-end" || report.Freeze.End != "2025-01-03T00:00:00Z" || report.Freeze.Overridden {
+		t.Errorf("unexpected report freeze: %+v", report.Freeze)
+	}
+
+	result, err = Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true, Clock: frozen, OverrideFreeze: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("expected --override-freeze to apply, got %v", err)
+	}
+	if report := BuildReleaseReport(result, ""); report.Freeze == nil || !report.Freeze.Overridden {
+		t.Errorf("expected the report to show the override, got %+v", report.Freeze)
+	}
+}
+
+func TestAnalyze_NoFreeze(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupFrozenRepo(t)
+	result, err := Analyze(&Options{
+		RepoPath:             dir,
+		TreatPreMajorAsMinor: true,
+		Clock:                func() time.Time { return time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC) },
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Freeze != nil || CheckFreeze(result) != nil {
+		t.Errorf("expected no freeze after the window, got %+v", result.Freeze)
+	}
+	if report := BuildReleaseReport(result, ""); report.Freeze != nil {
+		t.Errorf("expected no freeze in the report, got %+v", report.Freeze)
+	}
+}
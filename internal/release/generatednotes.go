@@ -0,0 +1,77 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GenerateNotesMerge adds the sections of GitHub's generated release notes
+// ("What's Changed", "New Contributors") to ours.
+const GenerateNotesMerge = "merge"
+
+// ValidateGenerateNotes checks a --generate-notes value.
+func ValidateGenerateNotes(mode string) error {
+	if mode != GenerateNotesMerge {
+		return fmt.Errorf("invalid --generate-notes %q: must be %s", mode, GenerateNotesMerge)
+	}
+	return nil
+}
+
+// generateReleaseNotes asks GitHub to generate notes for a release, listing
+// pull requests since previousTag (GitHub picks the last release if it's
+// empty), and returns their markdown.
+func generateReleaseNotes(repoPath string, ghRelease *GitHubRelease, previousTag string) (string, error) {
+	args := []string{"--method", "POST", "repos/{owner}/{repo}/releases/generate-notes",
+		"-f", "tag_name=" + ghRelease.TagName,
+	}
+	if ghRelease.TargetSHA != "" {
+		args = append(args, "-f", "target_commitish="+ghRelease.TargetSHA)
+	}
+	if previousTag != "" {
+		args = append(args, "-f", "previous_tag_name="+previousTag)
+	}
+
+	out, err := ghAPI(repoPath, args...)
+	if err != nil {
+		return "", err
+	}
+	var generated struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal(out, &generated); err != nil {
+		return "", fmt.Errorf("failed to parse generated notes: %w", err)
+	}
+	return generated.Body, nil
+}
+
+// mergeGeneratedNotes adds GitHub's generated sections to our notes, above
+// our "**Full Changelog**" line. Their headers are demoted to sit under our
+// "## component vX.Y.Z" header, and their own compare link is dropped unless
+// ours has none.
+func mergeGeneratedNotes(notes, generated string) string {
+	hasCompare := strings.Contains(notes, "**Full Changelog**")
+
+	var lines []string
+	for _, line := range strings.Split(generated, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->"):
+			continue
+		case strings.HasPrefix(trimmed, "**Full Changelog**") && hasCompare:
+			continue
+		case strings.HasPrefix(line, "## "):
+			line = "#" + line
+		}
+		lines = append(lines, strings.TrimRight(line, " \r"))
+	}
+	extra := strings.TrimSpace(strings.Join(lines, "\n"))
+	if extra == "" {
+		return notes
+	}
+
+	if i := strings.Index(notes, "**Full Changelog**"); i >= 0 {
+		return notes[:i] + extra + "\n\n" + notes[i:]
+	}
+	return strings.TrimRight(notes, "\n") + "\n\n" + extra + "\n"
+}
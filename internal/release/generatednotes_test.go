@@ -0,0 +1,84 @@
+package release
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const githubGeneratedNotes = `<!-- Release notes generated using configuration in .github/release.yml at main -->
+
+## What's Changed
+* Add bulk export by @octocat in https://github.com/o/r/pull/12
+
+## New Contributors
+* @octocat made their first contribution in https://github.com/o/r/pull/12
+
+**Full Changelog**: https://github.com/o/r/compare/api-v1.1.0...api-v1.2.0`
+
+func TestValidateGenerateNotes(t *testing.T) {
+	if err := ValidateGenerateNotes(GenerateNotesMerge); err != nil {
+		t.Errorf("expected merge to be valid, got %v", err)
+	}
+	if err := ValidateGenerateNotes("replace"); err == nil {
+		t.Error("expected replace to be invalid")
+	}
+}
+
+func TestMergeGeneratedNotes(t *testing.T) {
+	ours := "## api v1.2.0\n\n### Features\n\n* add bulk export (abc1234)\n\n**Full Changelog**: https://github.com/o/r/compare/api-v1.1.0...api-v1.2.0\n"
+
+	want := "## api v1.2.0\n\n### Features\n\n* add bulk export (abc1234)\n\n" +
+		"### What's Changed\n* Add bulk export by @octocat in https://github.com/o/r/pull/12\n\n" +
+		"### New Contributors\n* @octocat made their first contribution in https://github.com/o/r/pull/12\n\n" +
+		"**Full Changelog**: https://github.com/o/r/compare/api-v1.1.0...api-v1.2.0\n"
+	if got := mergeGeneratedNotes(ours, githubGeneratedNotes); got != want {
+		t.Errorf("merged notes:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMergeGeneratedNotes_NoCompareLink(t *testing.T) {
+	ours := "## api v1.2.0\n\n### Bug Fixes\n\n* fix timeout (abc1234)\n\n"
+
+	got := mergeGeneratedNotes(ours, githubGeneratedNotes)
+	if !strings.HasPrefix(got, ours[:len(ours)-1]) {
+		t.Errorf("expected our notes first, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "**Full Changelog**: https://github.com/o/r/compare/api-v1.1.0...api-v1.2.0\n") {
+		t.Errorf("expected GitHub's compare link kept when ours is missing, got:\n%s", got)
+	}
+}
+
+func TestMergeGeneratedNotes_Empty(t *testing.T) {
+	ours := "## api v1.2.0\n\n**Full Changelog**: x\n"
+	if got := mergeGeneratedNotes(ours, "**Full Changelog**: y\n"); got != ours {
+		t.Errorf("expected our notes unchanged, got %q", got)
+	}
+}
+
+func TestGenerateReleaseNotes(t *testing.T) {
+	var got []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		got = args
+		return []byte(`{"name": "api-v1.2.0", "body": "## What's Changed\n* x"}`), nil
+	})
+
+	body, err := generateReleaseNotes("", &GitHubRelease{TagName: "api-v1.2.0", TargetSHA: "abc123"}, "api-v1.1.0")
+	if err != nil {
+		t.Fatalf("generateReleaseNotes failed: %v", err)
+	}
+	if body != "## What's Changed\n* x" {
+		t.Errorf("unexpected body %q", body)
+	}
+	want := "--method POST repos/{owner}/{repo}/releases/generate-notes -f tag_name=api-v1.2.0 -f target_commitish=abc123 -f previous_tag_name=api-v1.1.0"
+	if strings.Join(got, " ") != want {
+		t.Errorf("args = %q\nwant %q", strings.Join(got, " "), want)
+	}
+
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return nil, errors.New("422 Unprocessable Entity")
+	})
+	if _, err := generateReleaseNotes("", &GitHubRelease{TagName: "api-v1.2.0"}, ""); err == nil {
+		t.Error("expected the API error returned")
+	}
+}
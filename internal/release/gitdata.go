@@ -0,0 +1,113 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// defaultFileMode is the tree mode of a regular, non-executable file.
+const defaultFileMode = "100644"
+
+// apiCommitter creates commits through the GitHub Git Data API, each on top
+// of the last, starting from parent.
+type apiCommitter struct {
+	repoPath string
+	parent   string
+
+	// head is the analyzed commit, checked out locally. Changed files keep
+	// their modes from it; the commits on top don't change modes.
+	head string
+}
+
+// gitObject is the part of a Git Data API response the committer needs.
+type gitObject struct {
+	SHA  string `json:"sha"`
+	Tree struct {
+		SHA string `json:"sha"`
+	} `json:"tree"`
+}
+
+// treeEntry is a file in a Git Data API tree request.
+type treeEntry struct {
+	Path    string `json:"path"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// commit creates a commit with the changed files on top of the last one and
+// returns its SHA. The branch isn't moved until updateBranch.
+func (a *apiCommitter) commit(message string, changes []*FileChange) (string, error) {
+	parent, err := a.call("repos/{owner}/{repo}/git/commits/" + a.parent)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", shortSHA(a.parent), err)
+	}
+
+	modes := map[string]string{}
+	if a.head != "" {
+		if modes, err = git.FileModes(a.repoPath, a.head, changedPaths(changes)); err != nil {
+			return "", err
+		}
+	}
+	entries := make([]treeEntry, 0, len(changes))
+	for _, change := range changes {
+		mode := modes[change.Path]
+		if mode == "" {
+			mode = defaultFileMode
+		}
+		entries = append(entries, treeEntry{Path: change.Path, Mode: mode, Type: "blob", Content: change.New})
+	}
+	// Whole files don't fit on a command line, so the body goes on stdin
+	body, err := json.Marshal(map[string]interface{}{"base_tree": parent.Tree.SHA, "tree": entries})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tree: %w", err)
+	}
+	tree, err := a.decode(ghAPIInput(a.repoPath, body, "--method", "POST", "repos/{owner}/{repo}/git/trees"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	created, err := a.call("--method", "POST", "repos/{owner}/{repo}/git/commits",
+		"-f", "message="+message,
+		"-f", "tree="+tree.SHA,
+		"-f", "parents[]="+a.parent,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	a.parent = created.SHA
+	return created.SHA, nil
+}
+
+// updateBranch moves branch to the last commit created. It's not forced, so
+// it fails if the branch has moved on since the analyzed commit.
+func (a *apiCommitter) updateBranch(branch string) error {
+	_, err := a.call("--method", "PATCH", "repos/{owner}/{repo}/git/refs/heads/"+branch,
+		"-f", "sha="+a.parent,
+		"-F", "force=false",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", branch, err)
+	}
+	return nil
+}
+
+// call runs a Git Data API request and decodes the object in the response.
+func (a *apiCommitter) call(args ...string) (*gitObject, error) {
+	return a.decode(ghAPI(a.repoPath, args...))
+}
+
+// decode decodes the object in a Git Data API response.
+func (a *apiCommitter) decode(out []byte, err error) (*gitObject, error) {
+	if err != nil {
+		return nil, err
+	}
+	var obj gitObject
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &obj, nil
+}
@@ -0,0 +1,158 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// stubGHAPIInput replaces ghAPIInput, which gets request bodies on stdin,
+// for the duration of a test.
+func stubGHAPIInput(t *testing.T, fn func(input []byte, args ...string) ([]byte, error)) {
+	t.Helper()
+	orig := ghAPIInput
+	ghAPIInput = func(repoPath string, input []byte, args ...string) ([]byte, error) { return fn(input, args...) }
+	t.Cleanup(func() { ghAPIInput = orig })
+}
+
+func TestAPICommitter(t *testing.T) {
+	var calls []string
+	stubGHAPIInput(t, func(input []byte, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " ")+" < "+string(input))
+		return []byte(`{"sha": "new-tree"}`), nil
+	})
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch {
+		case strings.HasPrefix(args[0], "repos/{owner}/{repo}/git/commits/"):
+			return []byte(`{"sha": "` + strings.TrimPrefix(args[0], "repos/{owner}/{repo}/git/commits/") + `", "tree": {"sha": "tree-of-parent"}}`), nil
+		case args[2] == "repos/{owner}/{repo}/git/commits":
+			return []byte(fmt.Sprintf(`{"sha": "commit-%d"}`, len(calls))), nil
+		case args[1] == "PATCH":
+			return []byte(`{"ref": "refs/heads/main"}`), nil
+		}
+		return nil, fmt.Errorf("unexpected call: %v", args)
+	})
+
+	api := &apiCommitter{parent: "base"}
+	sha, err := api.commit("chore(api): release 1.1.0", []*FileChange{{Path: "api/VERSION", New: "1.1.0\n"}})
+	if err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if sha != "commit-3" {
+		t.Errorf("expected commit-3, got %s", sha)
+	}
+	if _, err := api.commit("chore(web): release 2.0.0", []*FileChange{{Path: "web/VERSION", New: "2.0.0\n"}}); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if err := api.updateBranch("main"); err != nil {
+		t.Fatalf("updateBranch failed: %v", err)
+	}
+
+	want := []string{
+		"repos/{owner}/{repo}/git/commits/base",
+		`--method POST repos/{owner}/{repo}/git/trees < {"base_tree":"tree-of-parent","tree":[{"path":"api/VERSION","mode":"100644","type":"blob","content":"1.1.0\n"}]}`,
+		"--method POST repos/{owner}/{repo}/git/commits -f message=chore(api): release 1.1.0 -f tree=new-tree -f parents[]=base",
+		// The second commit builds on the first
+		"repos/{owner}/{repo}/git/commits/commit-3",
+		`--method POST repos/{owner}/{repo}/git/trees < {"base_tree":"tree-of-parent","tree":[{"path":"web/VERSION","mode":"100644","type":"blob","content":"2.0.0\n"}]}`,
+		"--method POST repos/{owner}/{repo}/git/commits -f message=chore(web): release 2.0.0 -f tree=new-tree -f parents[]=commit-3",
+		"--method PATCH repos/{owner}/{repo}/git/refs/heads/main -f sha=commit-6 -F force=false",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %d:\n%s", len(want), len(calls), strings.Join(calls, "\n"))
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d:\nexpected %q\ngot      %q", i, want[i], calls[i])
+		}
+	}
+}
+
+func TestAPICommitter_Errors(t *testing.T) {
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("HTTP 422: Update is not a fast forward")
+	})
+
+	api := &apiCommitter{parent: "base"}
+	if _, err := api.commit("chore: release", nil); err == nil || !strings.Contains(err.Error(), "failed to read commit") {
+		t.Errorf("expected read error, got %v", err)
+	}
+	if err := api.updateBranch("main"); err == nil || !strings.Contains(err.Error(), "not a fast forward") {
+		t.Errorf("expected fast-forward error, got %v", err)
+	}
+}
+
+func TestApplyAndCommit_ViaAPI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	// An executable file stays executable
+	runCmd(t, dir, "git", "update-index", "--chmod=+x", "workloads/service-b/VERSION")
+	runCmd(t, dir, "git", "commit", "--amend", "--no-edit")
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	head := result.MergeInfo.HeadSHA
+
+	var messages, refs []string
+	var trees []treeEntry
+	stubGHAPIInput(t, func(input []byte, args ...string) ([]byte, error) {
+		var body struct {
+			Tree []treeEntry `json:"tree"`
+		}
+		if err := json.Unmarshal(input, &body); err != nil {
+			t.Errorf("invalid tree body: %v", err)
+		}
+		trees = append(trees, body.Tree...)
+		return []byte(`{"sha": "t"}`), nil
+	})
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		switch {
+		case len(args) == 1:
+			return []byte(`{"tree": {"sha": "t"}}`), nil
+		case args[2] == "repos/{owner}/{repo}/git/commits":
+			messages = append(messages, args[4])
+			return []byte(fmt.Sprintf(`{"sha": "c%d"}`, len(messages))), nil
+		default:
+			refs = append(refs, strings.Join(args, " "))
+			return []byte(`{}`), nil
+		}
+	})
+
+	shas, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitPerPackage, ViaAPI: true})
+	if err != nil {
+		t.Fatalf("ApplyAndCommit failed: %v", err)
+	}
+	if strings.Join(shas, ",") != "c1,c2" {
+		t.Errorf("expected commits c1,c2, got %v", shas)
+	}
+	if strings.Join(messages, ",") != "message=chore(service-a): release 0.1.1,message=chore(service-b): release 0.1.1" {
+		t.Errorf("unexpected commit messages: %v", messages)
+	}
+	if len(refs) != 1 || refs[0] != "--method PATCH repos/{owner}/{repo}/git/refs/heads/main -f sha=c2 -F force=false" {
+		t.Errorf("expected main to move to c2 once, got %v", refs)
+	}
+
+	modes := make(map[string]string)
+	for _, entry := range trees {
+		modes[entry.Path] = entry.Mode
+	}
+	if modes["workloads/service-b/VERSION"] != "100755" || modes["workloads/service-a/VERSION"] != "100644" {
+		t.Errorf("expected file modes kept, got %v", modes)
+	}
+
+	// Nothing is committed locally
+	if local := gitOutput(t, dir, "rev-parse", "HEAD"); local != head {
+		t.Errorf("expected local HEAD to stay at %s, got %s", head, local)
+	}
+
+	result.Branch = ""
+	if _, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitSingle, ViaAPI: true}); err == nil {
+		t.Error("expected error without a branch")
+	}
+}
@@ -2,11 +2,16 @@ package release
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/dsswift/release-damnit/internal/changelog"
+	"github.com/dsswift/release-damnit/internal/config"
 	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/jira"
 )
 
 // GitHubReleaseOptions configures GitHub release creation.
@@ -19,6 +24,18 @@ type GitHubReleaseOptions struct {
 
 	// Verbose if true, print release notes before creating.
 	Verbose bool
+
+	// Milestones if true, close the milestone matching each release
+	// (e.g., "jarvis 0.2.0"), link it from the notes, and open the next one.
+	Milestones bool
+
+	// GenerateNotes, if GenerateNotesMerge, adds GitHub's generated release
+	// notes (pull requests and new contributors) to each release's notes.
+	GenerateNotes string
+
+	// ProvenanceDir, if set, is where WriteProvenance wrote the releases'
+	// provenance statements. Each is attached to its release as an asset.
+	ProvenanceDir string
 }
 
 // GitHubRelease represents a GitHub release to be created.
@@ -28,6 +45,22 @@ type GitHubRelease struct {
 	Notes       string
 	TargetSHA   string
 	PackageInfo *PackageRelease
+
+	// Milestone is the milestone closed for this release, if any.
+	Milestone *Milestone
+
+	// URL is the release page, once verified.
+	URL string
+
+	// Assets are files uploaded to the release.
+	Assets []string
+
+	// DiscussionCategory, if set, is the Discussions category a discussion
+	// for the release is created in.
+	DiscussionCategory string
+
+	// DiscussionURL is the release's discussion, once verified.
+	DiscussionURL string
 }
 
 // CreateGitHubReleases creates GitHub releases for all packages in the result.
@@ -35,32 +68,118 @@ func CreateGitHubReleases(result *AnalysisResult, opts *GitHubReleaseOptions) ([
 	if opts == nil {
 		opts = &GitHubReleaseOptions{}
 	}
+	if !opts.DryRun {
+		if err := CheckFreeze(result); err != nil {
+			return nil, err
+		}
+	}
 
 	var releases []*GitHubRelease
+	var notesDir string
 
 	for _, rel := range result.Releases {
 		ghRelease := BuildGitHubRelease(rel, result.RepoURL)
-		ghRelease.TargetSHA = result.MergeInfo.HeadSHA
+		ghRelease.Title = ReleaseTitle(rel, result.Date())
+		if result.Config != nil {
+			ghRelease.DiscussionCategory = result.Config.DiscussionCategory
+		}
+		ghRelease.TargetSHA = result.TargetSHA(rel)
+		if opts.ProvenanceDir != "" {
+			ghRelease.Assets = append(ghRelease.Assets, ProvenancePath(opts.ProvenanceDir, ghRelease.TagName))
+		}
 
 		if opts.DryRun {
+			if oversizedNotes(ghRelease) {
+				slog.Warn("release notes exceed GitHub's size limit and will be truncated", "tag", ghRelease.TagName)
+			}
 			releases = append(releases, ghRelease)
 			continue
 		}
 
+		// A failure to generate notes only logs a warning; the release goes
+		// ahead with our notes alone
+		if opts.GenerateNotes == GenerateNotesMerge {
+			previousTag := buildTagName(rel.Package.Component, rel.OldVersion)
+			if commit, err := tagCommit(opts.RepoPath, previousTag); err != nil || commit == "" {
+				previousTag = ""
+			}
+			generated, err := generateReleaseNotes(opts.RepoPath, ghRelease, previousTag)
+			if err != nil {
+				slog.Warn("failed to generate release notes, using ours alone", "component", rel.Package.Component, "error", err)
+			} else {
+				ghRelease.Notes = mergeGeneratedNotes(ghRelease.Notes, generated)
+			}
+		}
+
+		// Milestone failures shouldn't block the release itself. The
+		// milestone is only linked here; it's closed once the release exists.
+		if opts.Milestones {
+			milestone, err := FindReleaseMilestone(opts.RepoPath, rel)
+			if err != nil {
+				slog.Warn("failed to find milestone", "component", rel.Package.Component, "error", err)
+			}
+			if milestone != nil {
+				ghRelease.Notes += fmt.Sprintf("\n**Milestone**: [%s](%s)\n", milestone.Title, milestone.HTMLURL)
+			}
+		}
+
+		// GitHub rejects oversized bodies; attach the full notes instead
+		if oversizedNotes(ghRelease) {
+			if notesDir == "" {
+				dir, err := os.MkdirTemp("", "release-damnit-notes-")
+				if err != nil {
+					return releases, fmt.Errorf("failed to create release notes directory: %w", err)
+				}
+				notesDir = dir
+				defer os.RemoveAll(notesDir)
+			}
+			if err := fitReleaseNotes(ghRelease, result.RepoURL, notesDir); err != nil {
+				return releases, err
+			}
+		}
+
+		if err := runReleaseHooks(result, config.HookPreRelease, rel); err != nil {
+			return releases, err
+		}
+
 		if err := executeGitHubRelease(opts.RepoPath, ghRelease); err != nil {
 			return releases, fmt.Errorf("failed to create release for %s: %w", rel.Package.Component, err)
 		}
 
 		releases = append(releases, ghRelease)
+
+		// Don't let the pipeline carry on with a release nobody can see
+		err := verifyGitHubRelease(opts.RepoPath, ghRelease)
+		verified := err == nil
+		rel.Verified = &verified
+		if err != nil {
+			return releases, fmt.Errorf("failed to verify release for %s: %w", rel.Package.Component, err)
+		}
+
+		if opts.Milestones {
+			milestone, err := CloseReleaseMilestone(opts.RepoPath, rel)
+			if err != nil {
+				slog.Warn("failed to update milestones", "component", rel.Package.Component, "error", err)
+			}
+			ghRelease.Milestone = milestone
+		}
+
+		pushMirrorTags(result, opts.RepoPath, ghRelease)
+
+		// The release exists at this point, so a failing post-release hook is only a warning
+		if err := runReleaseHooks(result, config.HookPostRelease, rel); err != nil {
+			slog.Warn("post-release hook failed", "component", rel.Package.Component, "error", err)
+		}
 	}
 
 	return releases, nil
 }
 
-// BuildGitHubRelease constructs a GitHubRelease from a PackageRelease.
+// BuildGitHubRelease constructs a GitHubRelease from a PackageRelease. Its
+// title is dated today; CreateGitHubReleases uses the release date.
 func BuildGitHubRelease(rel *PackageRelease, repoURL string) *GitHubRelease {
 	tagName := fmt.Sprintf("%s-v%s", rel.Package.Component, rel.NewVersion)
-	title := fmt.Sprintf("%s v%s", rel.Package.Component, rel.NewVersion)
+	title := ReleaseTitle(rel, time.Now())
 	notes := BuildReleaseNotes(rel, repoURL)
 
 	return &GitHubRelease{
@@ -71,54 +190,86 @@ func BuildGitHubRelease(rel *PackageRelease, repoURL string) *GitHubRelease {
 	}
 }
 
+// ReleaseTitle returns the GitHub release title: the package's
+// release-title-pattern with ${component}, ${version}, and ${date}
+// (YYYY-MM-DD) filled in, or "<component> v<version>" without one.
+func ReleaseTitle(rel *PackageRelease, date time.Time) string {
+	pattern := rel.Package.ReleaseTitlePattern
+	if pattern == "" {
+		return fmt.Sprintf("%s v%s", rel.Package.Component, rel.NewVersion)
+	}
+	return strings.NewReplacer(
+		"${component}", rel.Package.Component,
+		"${version}", rel.NewVersion,
+		"${date}", date.Format("2006-01-02"),
+	).Replace(pattern)
+}
+
 // BuildReleaseNotes generates release notes from commits.
 func BuildReleaseNotes(rel *PackageRelease, repoURL string) string {
 	var notes strings.Builder
 
 	notes.WriteString(fmt.Sprintf("## %s v%s\n\n", rel.Package.Component, rel.NewVersion))
+	if rel.FirstRelease {
+		notes.WriteString(changelog.InitialReleaseNote + "\n\n")
+	}
 
-	features := filterCommitsByType(rel.Commits, "feat")
-	fixes := filterCommitsByType(rel.Commits, "fix")
-	perfs := filterCommitsByType(rel.Commits, "perf")
-
-	if len(features) > 0 {
-		notes.WriteString("### Features\n\n")
-		for _, c := range features {
-			commitLink := formatCommitLink(c, repoURL)
-			notes.WriteString(fmt.Sprintf("* %s (%s)\n", c.Description, commitLink))
+	var breaking []*git.Commit
+	for _, c := range rel.Commits {
+		if c.IsBreaking {
+			breaking = append(breaking, c)
 		}
-		notes.WriteString("\n")
 	}
-
-	if len(fixes) > 0 {
-		notes.WriteString("### Bug Fixes\n\n")
-		for _, c := range fixes {
-			commitLink := formatCommitLink(c, repoURL)
-			notes.WriteString(fmt.Sprintf("* %s (%s)\n", c.Description, commitLink))
+	if len(breaking) > 0 {
+		notes.WriteString("### ⚠ BREAKING CHANGES\n\n")
+		for _, b := range changelog.Bullets(breaking) {
+			notes.WriteString(fmt.Sprintf("* %s (%s)\n", linkifyJira(rel, b.Commit.Description), formatBulletLinks(b, repoURL)))
+			if b.Commit.BreakingDescription != "" {
+				notes.WriteString(fmt.Sprintf("\n  %s\n", linkifyJira(rel, b.Commit.BreakingDescription)))
+			}
 		}
 		notes.WriteString("\n")
 	}
 
-	if len(perfs) > 0 {
-		notes.WriteString("### Performance Improvements\n\n")
-		for _, c := range perfs {
-			commitLink := formatCommitLink(c, repoURL)
-			notes.WriteString(fmt.Sprintf("* %s (%s)\n", c.Description, commitLink))
+	compareURL := rel.compareURL(repoURL)
+	groups, omitted := changelog.Layout(rel.Commits, rel.Package.ChangelogSections, rel.Package.ChangelogSort, rel.Package.ChangelogMaxEntries)
+	for _, group := range groups {
+		notes.WriteString("### " + group.Section + "\n\n")
+		for _, b := range group.Bullets {
+			notes.WriteString(fmt.Sprintf("* %s (%s)\n", linkifyJira(rel, b.Commit.Description), formatBulletLinks(b, repoURL)))
+			writeCommitNotes(&notes, rel, b)
 		}
 		notes.WriteString("\n")
 	}
 
+	if omitted > 0 {
+		notes.WriteString(changelog.MoreChanges(omitted, compareURL) + "\n")
+	}
+
 	// Add compare link if we have a repo URL and old version
-	if repoURL != "" && rel.OldVersion != "" {
-		oldTag := fmt.Sprintf("%s-v%s", rel.Package.Component, rel.OldVersion)
-		newTag := fmt.Sprintf("%s-v%s", rel.Package.Component, rel.NewVersion)
-		compareURL := fmt.Sprintf("%s/compare/%s...%s", repoURL, oldTag, newTag)
+	if compareURL != "" {
 		notes.WriteString(fmt.Sprintf("**Full Changelog**: %s\n", compareURL))
 	}
 
 	return notes.String()
 }
 
+// writeCommitNotes writes a bullet's detail lines as sub-bullets.
+func writeCommitNotes(notes *strings.Builder, rel *PackageRelease, b *changelog.Bullet) {
+	for _, note := range b.Notes() {
+		notes.WriteString(fmt.Sprintf("  * %s\n", linkifyJira(rel, note)))
+	}
+}
+
+// linkifyJira links the Jira keys in text, as changelogs do, if the package
+// has a Jira config.
+func linkifyJira(rel *PackageRelease, text string) string {
+	if rel.Package.Jira == nil {
+		return text
+	}
+	return jira.Linkify(text, rel.Package.Jira.BaseURL, rel.Package.Jira.Projects)
+}
+
 func filterCommitsByType(commits []*git.Commit, commitType string) []*git.Commit {
 	var result []*git.Commit
 	for _, c := range commits {
@@ -133,29 +284,62 @@ func formatCommitLink(c *git.Commit, repoURL string) string {
 	if repoURL == "" {
 		return c.ShortSHA
 	}
-	return fmt.Sprintf("[%s](%s/commit/%s)", c.ShortSHA, repoURL, c.SHA)
+	return fmt.Sprintf("[%s](%s)", c.ShortSHA, changelog.BuildCommitURL(repoURL, c.SHA))
 }
 
-// executeGitHubRelease creates a release using the gh CLI.
-func executeGitHubRelease(repoPath string, ghRelease *GitHubRelease) error {
-	args := []string{
-		"release", "create", ghRelease.TagName,
-		"--title", ghRelease.Title,
-		"--notes", ghRelease.Notes,
+// formatBulletLinks links each of a bullet's commits, comma-separated.
+func formatBulletLinks(b *changelog.Bullet, repoURL string) string {
+	var links []string
+	for _, c := range b.Commits() {
+		links = append(links, formatCommitLink(c, repoURL))
 	}
+	return strings.Join(links, ", ")
+}
 
-	if ghRelease.TargetSHA != "" {
-		args = append(args, "--target", ghRelease.TargetSHA)
+// CreateArgs returns the gh arguments that create the release. The notes
+// are read from stdin.
+func (r *GitHubRelease) CreateArgs() []string {
+	args := []string{"release", "create", r.TagName}
+	args = append(args, r.Assets...)
+	args = append(args,
+		"--title", r.Title,
+		"--notes-file", "-",
+	)
+	if r.TargetSHA != "" {
+		args = append(args, "--target", r.TargetSHA)
 	}
+	if r.DiscussionCategory != "" {
+		args = append(args, "--discussion-category", r.DiscussionCategory)
+	}
+	return args
+}
 
-	cmd := exec.Command("gh", args...)
-	if repoPath != "" {
-		cmd.Dir = repoPath
+// Command returns the shell command that creates the release, for showing
+// in dry runs. The notes are piped to it on stdin.
+func (r *GitHubRelease) Command() string {
+	quoted := []string{"gh"}
+	for _, arg := range r.CreateArgs() {
+		quoted = append(quoted, shellQuote(arg))
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	return strings.Join(quoted, " ")
+}
 
-	return cmd.Run()
+// shellQuote quotes s for a POSIX shell if it needs it.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.,/:=@+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// executeGitHubRelease creates a release through the selected backend (see
+// SelectGitHubBackend).
+func executeGitHubRelease(repoPath string, ghRelease *GitHubRelease) error {
+	backend, err := currentGitHubBackend()
+	if err != nil {
+		return err
+	}
+	return backend.createRelease(repoPath, ghRelease)
 }
 
 // CheckGHCLI verifies that the gh CLI is installed and authenticated.
@@ -1,8 +1,11 @@
 package release
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dsswift/release-damnit/internal/config"
 	"github.com/dsswift/release-damnit/internal/git"
@@ -42,6 +45,168 @@ func TestBuildGitHubRelease(t *testing.T) {
 	}
 }
 
+func TestReleaseTitle(t *testing.T) {
+	date := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"", "api v1.2.0"},
+		{"v${version}", "v1.2.0"},
+		{"${component} ${version} — ${date}", "api 1.2.0 — 2025-03-14"},
+		{"Release ${version} (${version})", "Release 1.2.0 (1.2.0)"},
+	}
+	for _, tt := range tests {
+		rel := &PackageRelease{
+			Package:    &config.Package{Component: "api", ReleaseTitlePattern: tt.pattern},
+			NewVersion: "1.2.0",
+		}
+		if got := ReleaseTitle(rel, date); got != tt.want {
+			t.Errorf("ReleaseTitle(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestCreateGitHubReleases_DryRun(t *testing.T) {
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		t.Errorf("dry run should not call the API: %v", args)
+		return nil, nil
+	})
+
+	rel := &PackageRelease{
+		Package:    &config.Package{Path: "workloads/service-a", Component: "service-a", ReleaseTitlePattern: "${component} ${version} (${date})"},
+		BumpType:   version.Patch,
+		OldVersion: "1.0.0",
+		NewVersion: "1.0.1",
+		Commits:    []*git.Commit{{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "fix", Description: "fix bug"}},
+	}
+	result := &AnalysisResult{
+		MergeInfo:   &git.MergeInfo{HeadSHA: "0123456789abcdef0123456789abcdef01234567"},
+		Releases:    []*PackageRelease{rel},
+		ReleaseDate: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	ghReleases, err := CreateGitHubReleases(result, &GitHubReleaseOptions{DryRun: true, Milestones: true, ProvenanceDir: "provenance"})
+	if err != nil {
+		t.Fatalf("CreateGitHubReleases failed: %v", err)
+	}
+	if len(ghReleases) != 1 {
+		t.Fatalf("expected 1 planned release, got %d", len(ghReleases))
+	}
+	if ghReleases[0].TargetSHA != result.MergeInfo.HeadSHA {
+		t.Errorf("expected target %s, got %s", result.MergeInfo.HeadSHA, ghReleases[0].TargetSHA)
+	}
+	if ghReleases[0].Title != "service-a 1.0.1 (2025-03-14)" {
+		t.Errorf("expected the title from the pattern and release date, got %q", ghReleases[0].Title)
+	}
+	if a := ghReleases[0].Assets; len(a) != 1 || a[0] != filepath.Join("provenance", "service-a-v1.0.1.intoto.json") {
+		t.Errorf("expected the provenance attached, got %v", a)
+	}
+	if rel.Verified != nil {
+		t.Error("dry run should not mark releases verified")
+	}
+}
+
+// orderedBackend records release creation among the GitHub calls, failing
+// it with err if set.
+type orderedBackend struct {
+	events *[]string
+	err    error
+}
+
+func (b *orderedBackend) api(repoPath string, args ...string) ([]byte, error) {
+	return ghAPI(repoPath, args...)
+}
+
+func (b *orderedBackend) apiInput(repoPath string, input []byte, args ...string) ([]byte, error) {
+	return ghAPIInput(repoPath, input, args...)
+}
+
+func (b *orderedBackend) createRelease(repoPath string, ghRelease *GitHubRelease) error {
+	*b.events = append(*b.events, "create "+ghRelease.TagName)
+	return b.err
+}
+
+func TestCreateGitHubReleases_MilestoneClosedAfterRelease(t *testing.T) {
+	var events []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		switch {
+		case len(args) == 1:
+			return []byte(`{"html_url": "https://github.com/o/r/releases/tag/api-v1.1.0"}`), nil
+		case args[0] == "--paginate":
+			events = append(events, "list milestones")
+			return []byte(`[{"number": 7, "title": "api 1.1.0", "state": "open", "html_url": "https://github.com/o/r/milestone/7"}]`), nil
+		case args[1] == "PATCH":
+			events = append(events, "close milestone")
+			return []byte(`{"number": 7, "title": "api 1.1.0", "state": "closed"}`), nil
+		}
+		return []byte(`{}`), nil
+	})
+	orig := activeGitHubBackend
+	t.Cleanup(func() { activeGitHubBackend = orig })
+
+	newResult := func() *AnalysisResult {
+		return &AnalysisResult{
+			MergeInfo: &git.MergeInfo{HeadSHA: "0123456789abcdef0123456789abcdef01234567"},
+			Releases: []*PackageRelease{{
+				Package:    &config.Package{Path: "api", Component: "api"},
+				BumpType:   version.Minor,
+				OldVersion: "1.0.0",
+				NewVersion: "1.1.0",
+				Commits:    []*git.Commit{{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "add export"}},
+			}},
+		}
+	}
+
+	// A failed release leaves its milestone open
+	activeGitHubBackend = &orderedBackend{events: &events, err: fmt.Errorf("HTTP 422")}
+	if _, err := CreateGitHubReleases(newResult(), &GitHubReleaseOptions{Milestones: true}); err == nil {
+		t.Fatal("expected the release to fail")
+	}
+	if strings.Join(events, ", ") != "list milestones, create api-v1.1.0" {
+		t.Errorf("expected the milestone left open, got %v", events)
+	}
+
+	events = nil
+	activeGitHubBackend = &orderedBackend{events: &events}
+	ghReleases, err := CreateGitHubReleases(newResult(), &GitHubReleaseOptions{Milestones: true})
+	if err != nil {
+		t.Fatalf("CreateGitHubReleases failed: %v", err)
+	}
+	if strings.Join(events, ", ") != "list milestones, create api-v1.1.0, list milestones, close milestone" {
+		t.Errorf("expected the milestone closed after the release, got %v", events)
+	}
+	if !strings.Contains(ghReleases[0].Notes, "[api 1.1.0](https://github.com/o/r/milestone/7)") || ghReleases[0].Milestone == nil {
+		t.Errorf("expected the milestone linked and recorded, got:\n%s", ghReleases[0].Notes)
+	}
+}
+
+func TestGitHubRelease_Command(t *testing.T) {
+	ghRelease := &GitHubRelease{TagName: "service-a-v1.1.0", Title: "service-a v1.1.0", TargetSHA: "abc123"}
+	want := "gh release create service-a-v1.1.0 --title 'service-a v1.1.0' --notes-file - --target abc123"
+	if got := ghRelease.Command(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	ghRelease = &GitHubRelease{TagName: "web-v2.0.0", Title: "it's web"}
+	want = `gh release create web-v2.0.0 --title 'it'\''s web' --notes-file -`
+	if got := ghRelease.Command(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	ghRelease = &GitHubRelease{TagName: "web-v2.0.0", Title: "web v2.0.0", DiscussionCategory: "Announcements"}
+	want = "gh release create web-v2.0.0 --title 'web v2.0.0' --notes-file - --discussion-category Announcements"
+	if got := ghRelease.Command(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	ghRelease = &GitHubRelease{TagName: "web-v2.0.0", Title: "web v2.0.0", Assets: []string{"provenance/web-v2.0.0.intoto.json"}}
+	want = "gh release create web-v2.0.0 provenance/web-v2.0.0.intoto.json --title 'web v2.0.0' --notes-file -"
+	if got := ghRelease.Command(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestBuildReleaseNotes_FeaturesAndFixes(t *testing.T) {
 	rel := &PackageRelease{
 		Package: &config.Package{
@@ -118,6 +283,69 @@ func TestBuildReleaseNotes_WithCommitLinks(t *testing.T) {
 	}
 }
 
+func TestBuildReleaseNotes_CommitNotes(t *testing.T) {
+	rel := &PackageRelease{
+		Package: &config.Package{
+			Path:      "workloads/service-a",
+			Component: "service-a",
+		},
+		NewVersion: "1.0.0",
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "fix", Description: "fix crash", Notes: []string{"Crash on empty input"}},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "")
+
+	if !strings.Contains(notes, "* fix crash (abc1234)\n  * Crash on empty input\n") {
+		t.Errorf("notes should have commit notes as sub-bullets, got:\n%s", notes)
+	}
+}
+
+func TestBuildReleaseNotes_BreakingChanges(t *testing.T) {
+	rel := &PackageRelease{
+		Package: &config.Package{
+			Path:      "workloads/service-a",
+			Component: "service-a",
+		},
+		NewVersion: "2.0.0",
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "v2 API", IsBreaking: true, BreakingDescription: "The /v1 endpoints are removed."},
+			{SHA: "def4567890123", ShortSHA: "def4567", Type: "fix", Description: "fix crash"},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "")
+
+	want := "## service-a v2.0.0\n\n### ⚠ BREAKING CHANGES\n\n* v2 API (abc1234)\n\n  The /v1 endpoints are removed.\n\n### Features\n"
+	if !strings.HasPrefix(notes, want) {
+		t.Errorf("notes should lead with the breaking change and its description, got:\n%s", notes)
+	}
+}
+
+func TestBuildReleaseNotes_JiraLinks(t *testing.T) {
+	rel := &PackageRelease{
+		Package: &config.Package{
+			Path:      "workloads/service-a",
+			Component: "service-a",
+			Jira:      &config.Jira{BaseURL: "https://acme.atlassian.net", Projects: []string{"PROJ"}},
+		},
+		NewVersion: "2.0.0",
+		Commits: []*git.Commit{
+			{SHA: "abc1234567890", ShortSHA: "abc1234", Type: "feat", Description: "v2 API (PROJ-1)", IsBreaking: true, BreakingDescription: "PROJ-2 removed the /v1 endpoints."},
+			{SHA: "def4567890123", ShortSHA: "def4567", Type: "fix", Description: "fix crash PROJ-3"},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "")
+
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3"} {
+		if !strings.Contains(notes, "["+key+"](https://acme.atlassian.net/browse/"+key+")") {
+			t.Errorf("expected %s linked, got:\n%s", key, notes)
+		}
+	}
+}
+
 func TestBuildReleaseNotes_NoRepoURL(t *testing.T) {
 	rel := &PackageRelease{
 		Package: &config.Package{
@@ -164,6 +392,88 @@ func TestBuildReleaseNotes_PerformanceImprovements(t *testing.T) {
 	}
 }
 
+func TestBuildReleaseNotes_Sections(t *testing.T) {
+	rel := &PackageRelease{
+		Package: &config.Package{
+			Path:      "workloads/service-a",
+			Component: "service-a",
+			ChangelogSections: []config.ChangelogSection{
+				{Type: "fix", Section: "Bug Fixes"},
+				{Type: "docs", Section: "Documentation", Hidden: false},
+			},
+		},
+		NewVersion: "1.0.1",
+		Commits: []*git.Commit{
+			{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "docs", Description: "document flags"},
+			{SHA: "bbb2222222222", ShortSHA: "bbb2222", Type: "fix", Description: "fix crash"},
+			{SHA: "ccc3333333333", ShortSHA: "ccc3333", Type: "perf", Description: "optimize query"},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "")
+
+	if !strings.Contains(notes, "### Bug Fixes\n\n* fix crash (bbb2222)\n\n### Documentation\n\n* document flags (aaa1111)\n") {
+		t.Errorf("expected fixes then documentation, got:\n%s", notes)
+	}
+	if strings.Contains(notes, "optimize query") {
+		t.Errorf("expected unlisted perf commit left out, got:\n%s", notes)
+	}
+}
+
+func TestBuildReleaseNotes_DuplicateCommits(t *testing.T) {
+	rel := &PackageRelease{
+		Package:    &config.Package{Path: "workloads/service-a", Component: "service-a"},
+		NewVersion: "2.0.0",
+		Commits: []*git.Commit{
+			{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "feat", Description: "drop v1", IsBreaking: true},
+			{SHA: "bbb2222222222", ShortSHA: "bbb2222", Type: "fix", Description: "fix crash", Notes: []string{"Null check"}},
+			{SHA: "ccc3333333333", ShortSHA: "ccc3333", Type: "feat", Description: "drop v1", IsBreaking: true},
+			{SHA: "ddd4444444444", ShortSHA: "ddd4444", Type: "fix", Description: "fix crash", Notes: []string{"Null check"}},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "")
+
+	for _, want := range []string{
+		"### ⚠ BREAKING CHANGES\n\n* drop v1 (aaa1111, ccc3333)\n\n",
+		"### Features\n\n* drop v1 (aaa1111, ccc3333)\n\n",
+		"### Bug Fixes\n\n* fix crash (bbb2222, ddd4444)\n  * Null check\n\n",
+	} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("expected %q in notes, got:\n%s", want, notes)
+		}
+	}
+}
+
+func TestBuildReleaseNotes_MaxEntries(t *testing.T) {
+	rel := &PackageRelease{
+		Package: &config.Package{
+			Path:                "workloads/service-a",
+			Component:           "service-a",
+			ChangelogSort:       config.ChangelogSortScope,
+			ChangelogMaxEntries: 2,
+		},
+		OldVersion: "1.0.0",
+		NewVersion: "1.1.0",
+		Commits: []*git.Commit{
+			{SHA: "aaa1111111111", ShortSHA: "aaa1111", Type: "fix", Scope: "web", Description: "fix layout"},
+			{SHA: "bbb2222222222", ShortSHA: "bbb2222", Type: "fix", Scope: "api", Description: "fix crash"},
+			{SHA: "ccc3333333333", ShortSHA: "ccc3333", Type: "fix", Scope: "web", Description: "fix colors"},
+		},
+	}
+
+	notes := BuildReleaseNotes(rel, "https://github.com/owner/repo")
+
+	compare := "https://github.com/owner/repo/compare/service-a-v1.0.0...service-a-v1.1.0"
+	want := "* fix crash ([bbb2222](https://github.com/owner/repo/commit/bbb2222222222))\n" +
+		"* fix colors ([ccc3333](https://github.com/owner/repo/commit/ccc3333333333))\n\n" +
+		"…and 1 more change: [full changelog](" + compare + ")\n\n" +
+		"**Full Changelog**: " + compare + "\n"
+	if !strings.HasSuffix(notes, want) {
+		t.Errorf("expected sorted, capped notes, got:\n%s", notes)
+	}
+}
+
 func TestBuildReleaseNotes_EmptyCommits(t *testing.T) {
 	rel := &PackageRelease{
 		Package: &config.Package{
@@ -0,0 +1,55 @@
+package release
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/fixture"
+	"github.com/dsswift/release-damnit/internal/golden"
+)
+
+// goldenRepoURL is the repo URL the golden changelogs and notes link to.
+const goldenRepoURL = "https://github.com/dsswift/mock--gitops-playground"
+
+// TestGolden_Scenarios renders the changelog entry and release notes of each
+// fixture scenario's releases and compares them with testdata/golden. After
+// an intended format change, update them with:
+//
+//	UPDATE_GOLDEN=1 go test ./internal/release -run Golden
+func TestGolden_Scenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	releaseDate := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, s := range fixture.Scenarios() {
+		t.Run(s.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			if _, err := fixture.Make(&fixture.Options{Dir: dir, Scenarios: []string{s.Name}, Merge: true}); err != nil {
+				t.Fatalf("failed to make fixture: %v", err)
+			}
+			result, err := Analyze(&Options{
+				RepoPath:             dir,
+				DryRun:               true,
+				RepoURL:              goldenRepoURL,
+				TreatPreMajorAsMinor: true,
+				Clock:                func() time.Time { return releaseDate },
+			})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(result.Releases) == 0 {
+				t.Fatal("expected releases")
+			}
+
+			goldenDir := filepath.Join("testdata", "golden", s.Name)
+			for _, rel := range result.Releases {
+				component := rel.Package.Component
+				// Linked packages that weren't directly modified get a note
+				golden.Assert(t, filepath.Join(goldenDir, component+".changelog.md"), RenderChangelog(result, rel))
+				golden.Assert(t, filepath.Join(goldenDir, component+".notes.md"), BuildReleaseNotes(rel, result.RepoURL))
+			}
+		})
+	}
+}
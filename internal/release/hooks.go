@@ -0,0 +1,72 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// hookCommand builds the command for a hook. It's a variable so tests can
+// capture output instead of streaming it to the terminal.
+var hookCommand = func(dir, command string, env []string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// RunHooks runs the configured commands for a stage once per release, from the
+// repo root, stopping at the first failure. Each command sees the release in
+// its environment:
+//
+//	RELEASE_DAMNIT_STAGE        pre-apply, post-apply, pre-release, post-release
+//	RELEASE_DAMNIT_COMPONENT    component name
+//	RELEASE_DAMNIT_PATH         package path relative to the repo root
+//	RELEASE_DAMNIT_OLD_VERSION  version before the bump
+//	RELEASE_DAMNIT_NEW_VERSION  version after the bump
+//	RELEASE_DAMNIT_BUMP         major, minor, or patch
+//	RELEASE_DAMNIT_TAG          release tag (e.g., jarvis-v0.2.0)
+func RunHooks(result *AnalysisResult, stage string) error {
+	contracts.RequireNotNil(result, "result")
+
+	for _, rel := range result.Releases {
+		if err := runReleaseHooks(result, stage, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runReleaseHooks runs the commands for a stage for a single release.
+func runReleaseHooks(result *AnalysisResult, stage string, rel *PackageRelease) error {
+	if result.Config == nil {
+		return nil
+	}
+	commands := result.Config.Hooks.Commands(stage)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	env := append(os.Environ(),
+		"RELEASE_DAMNIT_STAGE="+stage,
+		"RELEASE_DAMNIT_COMPONENT="+rel.Package.Component,
+		"RELEASE_DAMNIT_PATH="+rel.Package.Path,
+		"RELEASE_DAMNIT_OLD_VERSION="+rel.OldVersion,
+		"RELEASE_DAMNIT_NEW_VERSION="+rel.NewVersion,
+		"RELEASE_DAMNIT_BUMP="+rel.BumpType.String(),
+		fmt.Sprintf("RELEASE_DAMNIT_TAG=%s-v%s", rel.Package.Component, rel.NewVersion),
+	)
+
+	for _, command := range commands {
+		slog.Debug("running hook", "stage", stage, "component", rel.Package.Component, "command", command)
+		if err := hookCommand(result.Config.RepoRoot, command, env).Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed for %s: %w", stage, command, rel.Package.Component, err)
+		}
+	}
+	return nil
+}
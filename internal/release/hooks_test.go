@@ -0,0 +1,101 @@
+package release
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// captureHooks sends hook output to a buffer for the duration of a test.
+func captureHooks(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	orig := hookCommand
+	hookCommand = func(dir, command string, env []string) *exec.Cmd {
+		cmd := orig(dir, command, env)
+		cmd.Stdout = &buf
+		cmd.Stderr = &buf
+		return cmd
+	}
+	t.Cleanup(func() { hookCommand = orig })
+	return &buf
+}
+
+func hookResult(t *testing.T, hooks *config.Hooks) *AnalysisResult {
+	return &AnalysisResult{
+		Config: &config.Config{RepoRoot: t.TempDir(), Hooks: hooks},
+		Releases: []*PackageRelease{
+			{
+				Package:    &config.Package{Component: "jarvis", Path: "workloads/jarvis"},
+				BumpType:   version.Minor,
+				OldVersion: "0.1.0",
+				NewVersion: "0.2.0",
+			},
+			{
+				Package:    &config.Package{Component: "web", Path: "workloads/web"},
+				BumpType:   version.Patch,
+				OldVersion: "1.0.0",
+				NewVersion: "1.0.1",
+			},
+		},
+	}
+}
+
+func TestRunHooks_Environment(t *testing.T) {
+	out := captureHooks(t)
+	result := hookResult(t, &config.Hooks{
+		PostApply: []string{`echo "$RELEASE_DAMNIT_STAGE $RELEASE_DAMNIT_COMPONENT $RELEASE_DAMNIT_PATH $RELEASE_DAMNIT_OLD_VERSION $RELEASE_DAMNIT_NEW_VERSION $RELEASE_DAMNIT_BUMP $RELEASE_DAMNIT_TAG"`},
+	})
+
+	if err := RunHooks(result, config.HookPostApply); err != nil {
+		t.Fatalf("RunHooks failed: %v", err)
+	}
+
+	want := "post-apply jarvis workloads/jarvis 0.1.0 0.2.0 minor jarvis-v0.2.0\n" +
+		"post-apply web workloads/web 1.0.0 1.0.1 patch web-v1.0.1\n"
+	if out.String() != want {
+		t.Errorf("unexpected hook output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunHooks_RunsInRepoRoot(t *testing.T) {
+	out := captureHooks(t)
+	result := hookResult(t, &config.Hooks{PreApply: []string{"pwd"}})
+	result.Releases = result.Releases[:1]
+
+	if err := RunHooks(result, config.HookPreApply); err != nil {
+		t.Fatalf("RunHooks failed: %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out.String()), result.Config.RepoRoot) {
+		t.Errorf("expected hook to run in %s, got %s", result.Config.RepoRoot, out.String())
+	}
+}
+
+func TestRunHooks_StopsOnFailure(t *testing.T) {
+	out := captureHooks(t)
+	result := hookResult(t, &config.Hooks{
+		PreRelease: []string{"echo first", "exit 3", "echo never"},
+	})
+
+	err := RunHooks(result, config.HookPreRelease)
+	if err == nil {
+		t.Fatal("expected error from failing hook")
+	}
+	if !strings.Contains(err.Error(), "pre-release hook \"exit 3\" failed for jarvis") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if out.String() != "first\n" {
+		t.Errorf("expected hooks to stop at the failure, got output %q", out.String())
+	}
+}
+
+func TestRunHooks_NoHooks(t *testing.T) {
+	result := hookResult(t, nil)
+	if err := RunHooks(result, config.HookPreApply); err != nil {
+		t.Errorf("expected no error without hooks, got %v", err)
+	}
+}
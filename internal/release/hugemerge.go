@@ -0,0 +1,62 @@
+package release
+
+import (
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// hugeMergeFiles is the number of changed files above which a merge's
+// commits are mapped to packages with pathspec-limited git queries instead
+// of listing every file of every commit. It's a variable so tests and
+// benchmarks can pick either path.
+var hugeMergeFiles = 10000
+
+// hugeMergeSize returns the number of files a merge changed if it's above
+// hugeMergeFiles, or 0.
+func hugeMergeSize(repoPath string, info *git.MergeInfo) (int, error) {
+	n, err := git.CountChangedFiles(repoPath, info.FirstParent, info.HeadSHA)
+	if err != nil {
+		return 0, err
+	}
+	if n <= hugeMergeFiles {
+		return 0, nil
+	}
+	return n, nil
+}
+
+// mapCommitsByPathspec maps a huge merge's commits to packages with one
+// git query per package (see config.Config.Pathspecs), so no commit's file
+// list is held in memory. Each commit's Files is set to the paths of the
+// packages it touches, which FindPackageForPath maps back to them. A
+// commit that already has files, such as the merge commit itself with
+// merge-commits set, is mapped by its files, which are then replaced.
+func mapCommitsByPathspec(repoPath string, cfg *config.Config, info *git.MergeInfo, commits []*git.Commit) (map[string][]*git.Commit, error) {
+	touched := make(map[string]map[string]bool) // package path -> SHAs
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		shas, err := git.MergedCommitsTouching(repoPath, info, cfg.Pathspecs(pkg))
+		if err != nil {
+			return nil, err
+		}
+		touched[pkg.Path] = shas
+	}
+
+	packageCommits := make(map[string][]*git.Commit)
+	for _, commit := range commits {
+		owners := make(map[string]bool)
+		for _, file := range commit.Files {
+			if pkg := cfg.FindPackageForPath(file); pkg != nil {
+				owners[pkg.Path] = true
+			}
+		}
+
+		var paths []string
+		for _, pkg := range cfg.PackagesSortedByPath() {
+			if owners[pkg.Path] || touched[pkg.Path][commit.SHA] {
+				packageCommits[pkg.Path] = append(packageCommits[pkg.Path], commit)
+				paths = append(paths, pkg.Path)
+			}
+		}
+		commit.Files = paths
+	}
+	return packageCommits, nil
+}
@@ -0,0 +1,179 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lowerHugeMergeFiles makes every merge changing more than n files a huge
+// merge for the rest of the test.
+func lowerHugeMergeFiles(tb testing.TB, n int) {
+	tb.Helper()
+	old := hugeMergeFiles
+	hugeMergeFiles = n
+	tb.Cleanup(func() { hugeMergeFiles = old })
+}
+
+// mergeTwoPackages merges a branch with a commit per service and one
+// changing both.
+func mergeTwoPackages(t *testing.T, dir string) {
+	t.Helper()
+	runCmd(t, dir, "git", "checkout", "-b", "feature/both")
+	writeFile(t, dir, "workloads/service-a/src/a.go", "// a\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add a")
+	writeFile(t, dir, "workloads/service-b/src/b.go", "// b\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-b): fix b")
+	writeFile(t, dir, "workloads/service-a/src/a.go", "// a2\n")
+	writeFile(t, dir, "workloads/service-b/src/b.go", "// b2\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: touch both")
+	runCmd(t, dir, "git", "checkout", "main")
+	runCmd(t, dir, "git", "merge", "--no-ff", "feature/both", "-m", "Merge branch 'feature/both'")
+}
+
+func TestAnalyze_HugeMerge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	mergeTwoPackages(t, dir)
+
+	want, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if want.Stats.HugeMergeFiles != 0 {
+		t.Fatalf("expected a regular merge, got HugeMergeFiles %d", want.Stats.HugeMergeFiles)
+	}
+
+	lowerHugeMergeFiles(t, 1)
+	got, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if got.Stats.HugeMergeFiles != 2 {
+		t.Errorf("expected HugeMergeFiles 2, got %d", got.Stats.HugeMergeFiles)
+	}
+
+	if len(got.Releases) != len(want.Releases) {
+		t.Fatalf("expected %d releases, got %d", len(want.Releases), len(got.Releases))
+	}
+	for i, rel := range got.Releases {
+		w := want.Releases[i]
+		if rel.Package.Path != w.Package.Path || rel.NewVersion != w.NewVersion {
+			t.Errorf("release %d: expected %s %s, got %s %s", i, w.Package.Path, w.NewVersion, rel.Package.Path, rel.NewVersion)
+		}
+		if len(rel.Commits) != len(w.Commits) {
+			t.Errorf("%s: expected %d commits, got %d", rel.Package.Path, len(w.Commits), len(rel.Commits))
+		}
+	}
+
+	for _, rel := range got.Releases {
+		for _, c := range rel.Commits {
+			for _, f := range c.Files {
+				if !strings.HasPrefix(f, "workloads/service-") || strings.Contains(f, "/src/") {
+					t.Errorf("%s: expected package paths in Files, got %v", c.ShortSHA, c.Files)
+				}
+			}
+		}
+	}
+}
+
+// setupHugeMergeRepo creates a repo with ten packages and merges a branch
+// whose commits change files files spread across them.
+func setupHugeMergeRepo(b *testing.B, files int) string {
+	b.Helper()
+
+	dir := b.TempDir()
+	run := func(args ...string) {
+		b.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			b.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		b.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+
+	const packages = 10
+	var cfg, manifest []string
+	for p := range packages {
+		path := fmt.Sprintf("workloads/service-%d", p)
+		cfg = append(cfg, fmt.Sprintf(`%q: {"component": "service-%d"}`, path, p))
+		manifest = append(manifest, fmt.Sprintf(`%q: "0.1.0"`, path))
+		write(path+"/VERSION", "0.1.0\n")
+	}
+	write("release-please-config.json", `{"packages": {`+strings.Join(cfg, ",")+`}}`)
+	write("release-please-manifest.json", `{`+strings.Join(manifest, ",")+`}`)
+	run("add", "-A")
+	run("commit", "-m", "chore: initial commit")
+
+	run("checkout", "-b", "feature/huge")
+	const commits = 20
+	for c := range commits {
+		for f := c; f < files; f += commits {
+			write(fmt.Sprintf("workloads/service-%d/gen/%d.txt", f%packages, f), "generated\n")
+		}
+		run("add", "-A")
+		run("commit", "-m", fmt.Sprintf("feat: generate batch %d", c))
+	}
+	run("checkout", "main")
+	run("merge", "--no-ff", "feature/huge", "-m", "Merge branch 'feature/huge'")
+	return dir
+}
+
+// BenchmarkAnalyze_HugeMerge compares listing every file of a 50k-file
+// merge's commits with mapping the commits to packages by pathspec.
+func BenchmarkAnalyze_HugeMerge(b *testing.B) {
+	if testing.Short() {
+		b.Skip("skipping 50k-file benchmark in short mode")
+	}
+
+	const files = 50000
+	dir := setupHugeMergeRepo(b, files)
+
+	for _, bc := range []struct {
+		name      string
+		threshold int
+	}{
+		{"per-file", files},
+		{"pathspec", files - 1},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			lowerHugeMergeFiles(b, bc.threshold)
+			b.ReportAllocs()
+			var held int
+			for b.Loop() {
+				result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+				if err != nil {
+					b.Fatalf("Analyze failed: %v", err)
+				}
+				held = 0
+				for _, c := range result.Commits {
+					held += len(c.Files)
+				}
+			}
+			b.ReportMetric(float64(held), "files-held")
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dsswift/release-damnit/pkg/extension"
+)
+
+// xmlTokenRegex matches the XML constructs pomUpdate steps through: comments,
+// CDATA, processing instructions, and start, end, and empty-element tags.
+var xmlTokenRegex = regexp.MustCompile(`(?s)<!--.*?-->|<!\[CDATA\[.*?\]\]>|<[?!].*?>|<(/?)([A-Za-z_][\w.:-]*)[^>]*?(/?)>`)
+
+// propertyRefRegex matches a Maven property reference like ${revision}.
+var propertyRefRegex = regexp.MustCompile(`^\$\{([\w.-]+)\}$`)
+
+// pomUpdate sets the project version in a Maven pom.xml. A version taken from
+// a property (the CI-friendly <version>${revision}</version> pattern) is set
+// in <properties> instead. Parent and dependency versions are untouched.
+func pomUpdate(req *extension.UpdateRequest) (string, error) {
+	if req.Content == "" {
+		return "", fmt.Errorf("%s does not exist or is empty", req.Path)
+	}
+
+	elements := xmlElementContents(req.Content)
+	span, ok := elements["project/version"]
+	if !ok {
+		return "", fmt.Errorf("%s has no project <version>", req.Path)
+	}
+	if m := propertyRefRegex.FindStringSubmatch(strings.TrimSpace(req.Content[span[0]:span[1]])); m != nil {
+		span, ok = elements["project/properties/"+m[1]]
+		if !ok {
+			return "", fmt.Errorf("%s: project version is ${%s}, which isn't in <properties>", req.Path, m[1])
+		}
+	}
+
+	// Keep any whitespace around the value
+	value := req.Content[span[0]:span[1]]
+	start := span[0] + len(value) - len(strings.TrimLeft(value, " \t\r\n"))
+	end := span[0] + len(strings.TrimRight(value, " \t\r\n"))
+	if end < start {
+		end = start
+	}
+	return req.Content[:start] + req.NewVersion + req.Content[end:], nil
+}
+
+// xmlElementContents returns the content byte range of each element by its
+// path from the root (e.g., "project/version"). Only the first element at a
+// path is kept.
+func xmlElementContents(content string) map[string][2]int {
+	elements := make(map[string][2]int)
+	var stack []string
+	var starts []int
+	for _, m := range xmlTokenRegex.FindAllStringSubmatchIndex(content, -1) {
+		if m[4] < 0 {
+			// Comment, CDATA, or processing instruction
+			continue
+		}
+		name := content[m[4]:m[5]]
+		switch {
+		case m[3] > m[2]: // </name>
+			if len(stack) == 0 || stack[len(stack)-1] != name {
+				continue
+			}
+			path := strings.Join(stack, "/")
+			if _, seen := elements[path]; !seen {
+				elements[path] = [2]int{starts[len(starts)-1], m[0]}
+			}
+			stack = stack[:len(stack)-1]
+			starts = starts[:len(starts)-1]
+		case m[7] > m[6]: // <name/>
+		default:
+			stack = append(stack, name)
+			starts = append(starts, m[1])
+		}
+	}
+	return elements
+}
+
+// gradleUpdate sets the version in a gradle.properties file. The property is
+// "version" unless the entry sets a "property" option.
+func gradleUpdate(req *extension.UpdateRequest) (string, error) {
+	if req.Content == "" {
+		return "", fmt.Errorf("%s does not exist or is empty", req.Path)
+	}
+	property := "version"
+	if p, _ := req.Options["property"].(string); p != "" {
+		property = p
+	}
+
+	lines := strings.Split(req.Content, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 || strings.TrimSpace(line[:sep]) != property {
+			continue
+		}
+
+		// Keep the spacing around the separator and any trailing \r
+		value := line[sep+1:]
+		lead := len(value) - len(strings.TrimLeft(value, " \t"))
+		trail := strings.TrimRight(value, "\r")
+		lines[i] = line[:sep+1+lead] + req.NewVersion + value[len(trail):]
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("%s has no %s property", req.Path, property)
+}
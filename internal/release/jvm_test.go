@@ -0,0 +1,123 @@
+package release
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/pkg/extension"
+)
+
+func TestPomUpdate(t *testing.T) {
+	pom := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.acme</groupId>
+    <artifactId>parent</artifactId>
+    <version>9.9.9</version>
+  </parent>
+  <!-- <version>0.0.0</version> -->
+  <artifactId>api</artifactId>
+  <version>1.2.3</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.acme</groupId>
+      <artifactId>client</artifactId>
+      <version>1.2.3</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+	got, err := pomUpdate(&extension.UpdateRequest{Path: "pom.xml", Content: pom, NewVersion: "1.3.0"})
+	if err != nil {
+		t.Fatalf("pomUpdate failed: %v", err)
+	}
+	want := strings.Replace(pom, "<artifactId>api</artifactId>\n  <version>1.2.3</version>", "<artifactId>api</artifactId>\n  <version>1.3.0</version>", 1)
+	if got != want {
+		t.Errorf("expected only the project version to change, got:\n%s", got)
+	}
+}
+
+func TestPomUpdate_RevisionProperty(t *testing.T) {
+	pom := `<project>
+  <artifactId>api</artifactId>
+  <version>${revision}</version>
+  <properties>
+    <java.version>21</java.version>
+    <revision> 1.2.3-SNAPSHOT </revision>
+  </properties>
+</project>
+`
+	got, err := pomUpdate(&extension.UpdateRequest{Path: "pom.xml", Content: pom, NewVersion: "1.3.0"})
+	if err != nil {
+		t.Fatalf("pomUpdate failed: %v", err)
+	}
+	want := strings.Replace(pom, "<revision> 1.2.3-SNAPSHOT </revision>", "<revision> 1.3.0 </revision>", 1)
+	if got != want {
+		t.Errorf("expected the revision property to change, got:\n%s", got)
+	}
+}
+
+func TestPomUpdate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing file", ""},
+		{"inherited version", "<project><parent><version>1.0.0</version></parent></project>"},
+		{"undefined property", "<project><version>${revision}</version></project>"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := pomUpdate(&extension.UpdateRequest{Path: "pom.xml", Content: tc.content, NewVersion: "1.3.0"}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGradleUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		options map[string]interface{}
+		want    string
+	}{
+		{
+			name:    "version property",
+			content: "# build settings\ngroup=com.acme\nversion=1.2.3\norg.gradle.jvmargs=-Xmx2g\n",
+			want:    "# build settings\ngroup=com.acme\nversion=1.3.0\norg.gradle.jvmargs=-Xmx2g\n",
+		},
+		{
+			name:    "spacing and CRLF",
+			content: "kotlinVersion = 1.9.0\r\nversion = 1.2.3\r\n",
+			want:    "kotlinVersion = 1.9.0\r\nversion = 1.3.0\r\n",
+		},
+		{
+			name:    "custom property",
+			content: "#version=0.0.1\nversion=unspecified\napiVersion: 1.2.3\n",
+			options: map[string]interface{}{"property": "apiVersion"},
+			want:    "#version=0.0.1\nversion=unspecified\napiVersion: 1.3.0\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := gradleUpdate(&extension.UpdateRequest{Path: "gradle.properties", Content: tc.content, NewVersion: "1.3.0", Options: tc.options})
+			if err != nil {
+				t.Fatalf("gradleUpdate failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := gradleUpdate(&extension.UpdateRequest{Path: "gradle.properties", Content: "group=com.acme\n", NewVersion: "1.3.0"}); err == nil {
+		t.Error("expected error without a version property")
+	}
+	if _, err := gradleUpdate(&extension.UpdateRequest{Path: "gradle.properties", NewVersion: "1.3.0"}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
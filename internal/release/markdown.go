@@ -0,0 +1,103 @@
+package release
+
+import (
+	"path"
+	"strings"
+)
+
+// Markers around the parts of a markdown file holding the package version,
+// such as install snippets and badges.
+const (
+	markdownVersionStart = "<!-- x-release-damnit-version-start -->"
+	markdownVersionEnd   = "<!-- x-release-damnit-version-end -->"
+)
+
+// isMarkdownFile reports whether a file name is a markdown document.
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// planMarkdownFiles plans version updates to the marked regions of the
+// markdown files a package owns. Versions outside the markers are never
+// changed, since prose may name an old version on purpose. The changelog, or
+// everything in the changelog directory, is left to the changelog planners.
+func planMarkdownFiles(result *AnalysisResult, rel *PackageRelease) ([]*FileChange, error) {
+	changelogPath := path.Join(rel.Package.Path, rel.Package.ChangelogPath)
+	changes, err := planPackageFiles(result, rel, isMarkdownFile, func(content string) string {
+		return updateMarkdownRegions(content, rel.OldVersion, rel.NewVersion)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	planned := changes[:0]
+	for _, change := range changes {
+		if change.Path != changelogPath && !strings.HasPrefix(change.Path, changelogPath+"/") {
+			planned = append(planned, change)
+		}
+	}
+	return planned, nil
+}
+
+// updateMarkdownRegions replaces the old version with the new one between
+// each pair of version markers. Regions may span lines or sit inside one,
+// and an unterminated region is left alone.
+func updateMarkdownRegions(content, oldVersion, newVersion string) string {
+	if oldVersion == "" {
+		return content
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(content, markdownVersionStart)
+		if start < 0 {
+			break
+		}
+		start += len(markdownVersionStart)
+		end := strings.Index(content[start:], markdownVersionEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+
+		sb.WriteString(content[:start])
+		sb.WriteString(replaceVersion(content[start:end], oldVersion, newVersion))
+		sb.WriteString(markdownVersionEnd)
+		content = content[end+len(markdownVersionEnd):]
+	}
+	sb.WriteString(content)
+	return sb.String()
+}
+
+// replaceVersion replaces each occurrence of oldVersion in s that isn't part
+// of a longer version (1.2.3 in 11.2.3 or 1.2.3.4).
+func replaceVersion(s, oldVersion, newVersion string) string {
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, oldVersion)
+		if i < 0 {
+			break
+		}
+		end := i + len(oldVersion)
+		partial := (i > 0 && (isDigit(s[i-1]) || s[i-1] == '.')) ||
+			(end < len(s) && (isDigit(s[end]) || (s[end] == '.' && end+1 < len(s) && isDigit(s[end+1]))))
+
+		sb.WriteString(s[:i])
+		if partial {
+			sb.WriteString(oldVersion)
+		} else {
+			sb.WriteString(newVersion)
+		}
+		s = s[end:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,80 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestUpdateMarkdownRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "install snippet",
+			content: "# API\n\n<!-- x-release-damnit-version-start -->\n```sh\n" +
+				"go install example.com/api@v1.2.3\n# needs Go 11.2.3 or 1.2.3.4\n```\n<!-- x-release-damnit-version-end -->\n\nSince 1.2.3.\n",
+			want: "# API\n\n<!-- x-release-damnit-version-start -->\n```sh\n" +
+				"go install example.com/api@v1.3.0\n# needs Go 11.2.3 or 1.2.3.4\n```\n<!-- x-release-damnit-version-end -->\n\nSince 1.2.3.\n",
+		},
+		{
+			name:    "inline badge",
+			content: "<!-- x-release-damnit-version-start -->![version](https://img.shields.io/badge/version-1.2.3-blue)<!-- x-release-damnit-version-end --> and 1.2.3\n",
+			want:    "<!-- x-release-damnit-version-start -->![version](https://img.shields.io/badge/version-1.3.0-blue)<!-- x-release-damnit-version-end --> and 1.2.3\n",
+		},
+		{
+			name: "several regions",
+			content: "<!-- x-release-damnit-version-start -->1.2.3.<!-- x-release-damnit-version-end -->\n" +
+				"1.2.3\n<!-- x-release-damnit-version-start -->v1.2.3<!-- x-release-damnit-version-end -->\n",
+			want: "<!-- x-release-damnit-version-start -->1.3.0.<!-- x-release-damnit-version-end -->\n" +
+				"1.2.3\n<!-- x-release-damnit-version-start -->v1.3.0<!-- x-release-damnit-version-end -->\n",
+		},
+		{
+			name:    "no markers",
+			content: "Install v1.2.3:\n\n    go install example.com/api@v1.2.3\n",
+			want:    "Install v1.2.3:\n\n    go install example.com/api@v1.2.3\n",
+		},
+		{
+			name:    "unterminated region",
+			content: "<!-- x-release-damnit-version-start -->\n1.2.3\n",
+			want:    "<!-- x-release-damnit-version-start -->\n1.2.3\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := updateMarkdownRegions(tc.content, "1.2.3", "1.3.0"); got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPlanMarkdownFiles(t *testing.T) {
+	dir := t.TempDir()
+	region := "<!-- x-release-damnit-version-start -->1.0.0<!-- x-release-damnit-version-end -->\n"
+	for file, content := range map[string]string{
+		"api/README.md":     region,
+		"api/docs/usage.md": "No markers for 1.0.0\n",
+		"api/CHANGELOG.md":  region,
+		"api/notes.txt":     region,
+	} {
+		writeFile(t, dir, file, content)
+	}
+
+	pkg := &config.Package{Path: "api", Component: "api", ChangelogPath: "CHANGELOG.md"}
+	result := &AnalysisResult{Config: &config.Config{RepoRoot: dir, Packages: map[string]*config.Package{"api": pkg}}}
+	changes, err := planMarkdownFiles(result, &PackageRelease{Package: pkg, OldVersion: "1.0.0", NewVersion: "1.1.0"})
+	if err != nil {
+		t.Fatalf("planMarkdownFiles failed: %v", err)
+	}
+
+	if len(changes) != 1 || changes[0].Path != "api/README.md" {
+		t.Fatalf("expected only api/README.md to change, got %+v", changes)
+	}
+	if want := "<!-- x-release-damnit-version-start -->1.1.0<!-- x-release-damnit-version-end -->\n"; changes[0].New != want {
+		t.Errorf("expected %q, got %q", want, changes[0].New)
+	}
+}
@@ -0,0 +1,130 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// Milestone is a GitHub milestone.
+type Milestone struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+}
+
+// ghAPI performs a GitHub request given as `gh api` arguments for repoPath
+// and returns the response, through the selected backend (see
+// SelectGitHubBackend). It's a variable so tests can stub out GitHub.
+var ghAPI = func(repoPath string, args ...string) ([]byte, error) {
+	backend, err := currentGitHubBackend()
+	if err != nil {
+		return nil, err
+	}
+	return backend.api(repoPath, args...)
+}
+
+// ghAPIInput performs a request like ghAPI with input as its JSON body. It's
+// a variable so tests can stub out GitHub.
+var ghAPIInput = func(repoPath string, input []byte, args ...string) ([]byte, error) {
+	backend, err := currentGitHubBackend()
+	if err != nil {
+		return nil, err
+	}
+	return backend.apiInput(repoPath, input, args...)
+}
+
+// MilestoneTitle returns the milestone title for a component version (e.g., "jarvis 0.2.0").
+func MilestoneTitle(component, ver string) string {
+	return fmt.Sprintf("%s %s", component, ver)
+}
+
+// NextMilestoneTitle returns the title of the milestone that follows a release.
+// It assumes the next release is a patch; teams can rename it when planning a bigger bump.
+func NextMilestoneTitle(component, ver string) string {
+	v, err := version.Parse(ver)
+	if err != nil {
+		return ""
+	}
+	return MilestoneTitle(component, v.Bump(version.Patch, false).String())
+}
+
+// FindReleaseMilestone returns the open milestone for a release, or nil if
+// there is none. It's left open; CloseReleaseMilestone closes it once the
+// release exists.
+func FindReleaseMilestone(repoPath string, rel *PackageRelease) (*Milestone, error) {
+	milestones, err := listOpenMilestones(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return findMilestone(milestones, MilestoneTitle(rel.Package.Component, rel.NewVersion)), nil
+}
+
+// CloseReleaseMilestone closes the open milestone for a release (if one exists)
+// and creates the milestone for the next version. Returns the closed milestone,
+// or nil if there was no matching milestone. Call it after the release is
+// created, so a failed release doesn't leave its milestone closed.
+func CloseReleaseMilestone(repoPath string, rel *PackageRelease) (*Milestone, error) {
+	title := MilestoneTitle(rel.Package.Component, rel.NewVersion)
+
+	milestones, err := listOpenMilestones(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var closed *Milestone
+	if m := findMilestone(milestones, title); m != nil {
+		out, err := ghAPI(repoPath, "--method", "PATCH",
+			fmt.Sprintf("repos/{owner}/{repo}/milestones/%d", m.Number),
+			"-f", "state=closed")
+		if err != nil {
+			return nil, fmt.Errorf("failed to close milestone %q: %w", title, err)
+		}
+		closed = &Milestone{}
+		if err := json.Unmarshal(out, closed); err != nil {
+			closed = m
+		}
+	}
+
+	next := NextMilestoneTitle(rel.Package.Component, rel.NewVersion)
+	if next != "" && findMilestone(milestones, next) == nil {
+		if _, err := ghAPI(repoPath, "--method", "POST", "repos/{owner}/{repo}/milestones", "-f", "title="+next); err != nil {
+			return closed, fmt.Errorf("failed to create milestone %q: %w", next, err)
+		}
+	}
+
+	return closed, nil
+}
+
+// listOpenMilestones returns all open milestones in the repository.
+func listOpenMilestones(repoPath string) ([]*Milestone, error) {
+	out, err := ghAPI(repoPath, "--paginate", "repos/{owner}/{repo}/milestones?state=open&per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list milestones: %w", err)
+	}
+
+	// --paginate concatenates one JSON array per page
+	var milestones []*Milestone
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for dec.More() {
+		var page []*Milestone
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to parse milestones: %w", err)
+		}
+		milestones = append(milestones, page...)
+	}
+	return milestones, nil
+}
+
+// findMilestone returns the milestone with the given title, or nil.
+func findMilestone(milestones []*Milestone, title string) *Milestone {
+	for _, m := range milestones {
+		if m.Title == title {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package release
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// stubGHAPI replaces ghAPI for the duration of a test.
+func stubGHAPI(t *testing.T, fn func(args ...string) ([]byte, error)) {
+	t.Helper()
+	orig := ghAPI
+	ghAPI = func(repoPath string, args ...string) ([]byte, error) { return fn(args...) }
+	t.Cleanup(func() { ghAPI = orig })
+}
+
+func TestNextMilestoneTitle(t *testing.T) {
+	if got := NextMilestoneTitle("jarvis", "0.2.0"); got != "jarvis 0.2.1" {
+		t.Errorf("expected 'jarvis 0.2.1', got %q", got)
+	}
+	if got := NextMilestoneTitle("jarvis", "not-a-version"); got != "" {
+		t.Errorf("expected empty title for invalid version, got %q", got)
+	}
+}
+
+func TestCloseReleaseMilestone(t *testing.T) {
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch {
+		case args[0] == "--paginate":
+			// Two pages, as gh --paginate emits them
+			return []byte(`[{"number": 1, "title": "web 1.0.0", "state": "open"}]` +
+				`[{"number": 7, "title": "jarvis 0.2.0", "state": "open", "html_url": "https://github.com/o/r/milestone/7"}]`), nil
+		case args[1] == "PATCH":
+			return []byte(`{"number": 7, "title": "jarvis 0.2.0", "state": "closed", "html_url": "https://github.com/o/r/milestone/7"}`), nil
+		default:
+			return []byte(`{}`), nil
+		}
+	})
+
+	rel := &PackageRelease{Package: &config.Package{Component: "jarvis"}, NewVersion: "0.2.0"}
+	m, err := CloseReleaseMilestone("", rel)
+	if err != nil {
+		t.Fatalf("CloseReleaseMilestone failed: %v", err)
+	}
+
+	if m == nil || m.Number != 7 || m.State != "closed" {
+		t.Fatalf("expected closed milestone 7, got %+v", m)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected list, close, create calls, got %v", calls)
+	}
+	if !strings.Contains(calls[1], "milestones/7") || !strings.Contains(calls[1], "state=closed") {
+		t.Errorf("unexpected close call: %s", calls[1])
+	}
+	if !strings.Contains(calls[2], "POST") || !strings.Contains(calls[2], "title=jarvis 0.2.1") {
+		t.Errorf("unexpected create call: %s", calls[2])
+	}
+}
+
+func TestCloseReleaseMilestone_NoMatch(t *testing.T) {
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "--paginate" {
+			return []byte(`[{"number": 2, "title": "jarvis 0.2.1", "state": "open"}]`), nil
+		}
+		return []byte(`{}`), nil
+	})
+
+	rel := &PackageRelease{Package: &config.Package{Component: "jarvis"}, NewVersion: "0.2.0"}
+	m, err := CloseReleaseMilestone("", rel)
+	if err != nil {
+		t.Fatalf("CloseReleaseMilestone failed: %v", err)
+	}
+	if m != nil {
+		t.Errorf("expected no milestone, got %+v", m)
+	}
+	// Next milestone already exists, so only the list call is made
+	if len(calls) != 1 {
+		t.Errorf("expected only the list call, got %v", calls)
+	}
+}
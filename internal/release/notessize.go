@@ -0,0 +1,59 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxReleaseNotes is the longest release body GitHub accepts, in
+// characters.
+var maxReleaseNotes = 125000
+
+// notesAssetSuffix names the asset holding a truncated release's full notes.
+const notesAssetSuffix = "-release-notes.md"
+
+// oversizedNotes reports whether a release's notes are too long for GitHub.
+func oversizedNotes(ghRelease *GitHubRelease) bool {
+	return utf8.RuneCountInString(ghRelease.Notes) > maxReleaseNotes
+}
+
+// fitReleaseNotes keeps a release's notes within GitHub's size limit, so an
+// oversized release is still created. The full notes are written to dir and
+// attached as an asset, and the body is cut at a line before the limit and
+// ends pointing at the package's changelog and the asset. Notes that fit are
+// left alone.
+func fitReleaseNotes(ghRelease *GitHubRelease, repoURL, dir string) error {
+	if !oversizedNotes(ghRelease) {
+		return nil
+	}
+
+	asset := filepath.Join(dir, ghRelease.TagName+notesAssetSuffix)
+	if err := os.WriteFile(asset, []byte(ghRelease.Notes), 0644); err != nil {
+		return fmt.Errorf("failed to write full release notes: %w", err)
+	}
+	ghRelease.Assets = append(ghRelease.Assets, asset)
+
+	changelog := "CHANGELOG"
+	if rel := ghRelease.PackageInfo; rel != nil && repoURL != "" {
+		changelogPath := path.Join(filepath.ToSlash(rel.Package.Path), filepath.ToSlash(rel.Package.ChangelogPath))
+		changelog = fmt.Sprintf("[CHANGELOG](%s/blob/%s/%s)", strings.TrimSuffix(repoURL, "/"), ghRelease.TagName, changelogPath)
+	}
+	footer := fmt.Sprintf("\n---\n\n**Release notes truncated** to fit GitHub's size limit. See the %s or the attached `%s` for the full notes.\n",
+		changelog, filepath.Base(asset))
+
+	// Cut at the last line that fits with the footer
+	notes := []rune(ghRelease.Notes)
+	notes = notes[:maxReleaseNotes-utf8.RuneCountInString(footer)]
+	cut := string(notes)
+	if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+		cut = cut[:i+1]
+	}
+	slog.Warn("release notes exceed GitHub's size limit, truncating", "tag", ghRelease.TagName, "characters", utf8.RuneCountInString(ghRelease.Notes), "asset", filepath.Base(asset))
+	ghRelease.Notes = cut + footer
+	return nil
+}
@@ -0,0 +1,50 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestFitReleaseNotes(t *testing.T) {
+	old := maxReleaseNotes
+	maxReleaseNotes = 400
+	t.Cleanup(func() { maxReleaseNotes = old })
+
+	rel := &PackageRelease{Package: &config.Package{Path: "workloads/api", Component: "api", ChangelogPath: "CHANGELOG.md"}}
+	ghRelease := &GitHubRelease{TagName: "api-v1.2.0", Notes: "## api v1.2.0\n\n", PackageInfo: rel}
+	if err := fitReleaseNotes(ghRelease, "https://github.com/o/r", t.TempDir()); err != nil || len(ghRelease.Assets) != 0 {
+		t.Fatalf("expected notes that fit left alone, got %v, %v", ghRelease.Assets, err)
+	}
+
+	for i := 0; i < 20; i++ {
+		ghRelease.Notes += "* fix ünïcode bug number " + strings.Repeat("x", i) + "\n"
+	}
+	full := ghRelease.Notes
+	dir := t.TempDir()
+	if err := fitReleaseNotes(ghRelease, "https://github.com/o/r/", dir); err != nil {
+		t.Fatalf("fitReleaseNotes failed: %v", err)
+	}
+
+	asset := filepath.Join(dir, "api-v1.2.0-release-notes.md")
+	if len(ghRelease.Assets) != 1 || ghRelease.Assets[0] != asset {
+		t.Fatalf("expected the full notes attached, got %v", ghRelease.Assets)
+	}
+	if data, err := os.ReadFile(asset); err != nil || string(data) != full {
+		t.Errorf("expected the asset to hold the full notes, got %q, %v", data, err)
+	}
+	if n := utf8.RuneCountInString(ghRelease.Notes); n > maxReleaseNotes {
+		t.Errorf("expected at most %d characters, got %d", maxReleaseNotes, n)
+	}
+	body, footer, ok := strings.Cut(ghRelease.Notes, "\n---\n\n")
+	if !ok || !strings.HasPrefix(full, body) || !strings.HasSuffix(body, "\n") {
+		t.Errorf("expected the notes cut at a line, got:\n%s", ghRelease.Notes)
+	}
+	if !strings.Contains(footer, "[CHANGELOG](https://github.com/o/r/blob/api-v1.2.0/workloads/api/CHANGELOG.md)") || !strings.Contains(footer, "`api-v1.2.0-release-notes.md`") {
+		t.Errorf("expected links to the changelog and asset, got:\n%s", footer)
+	}
+}
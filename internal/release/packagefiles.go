@@ -0,0 +1,64 @@
+package release
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skippedDirs are directories never searched for package files.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// planPackageFiles plans updates to the files a package owns whose names
+// match, rewriting each with update. Hidden directories and skippedDirs
+// aren't searched, files under nested packages or exclude-paths are left to
+// their owner, and unchanged files aren't planned.
+func planPackageFiles(result *AnalysisResult, rel *PackageRelease, match func(name string) bool, update func(content string) string) ([]*FileChange, error) {
+	repoRoot := result.Config.RepoRoot
+	root := filepath.Join(repoRoot, rel.Package.Path)
+
+	var changes []*FileChange
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !match(d.Name()) {
+			return nil
+		}
+
+		file, err := filepath.Rel(repoRoot, p)
+		if err != nil {
+			return err
+		}
+		file = filepath.ToSlash(file)
+		if result.Config.FindPackageForPath(file) != rel.Package {
+			return nil
+		}
+
+		existing, _, err := readOptional(p)
+		if err != nil {
+			return err
+		}
+		if updated := update(existing); updated != existing {
+			changes = append(changes, &FileChange{Path: file, Old: existing, New: updated})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
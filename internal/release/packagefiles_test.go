@@ -0,0 +1,72 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestPlanPackageFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{
+		"api/app.conf",
+		"api/deploy/app.conf",
+		"api/.cache/app.conf",
+		"api/node_modules/dep/app.conf",
+		"api/vendor/dep/app.conf",
+		"api/examples/app.conf",
+		"api/plugin/app.conf",
+		"api/unchanged.conf",
+		"api/other.txt",
+	} {
+		content := "version=1.0.0\n"
+		if strings.HasSuffix(file, "unchanged.conf") {
+			content = "name=api\n"
+		}
+		full := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api := &config.Package{Path: "api", Component: "api", ExcludePaths: []string{"api/examples"}}
+	plugin := &config.Package{Path: "api/plugin", Component: "plugin"}
+	result := &AnalysisResult{
+		Config: &config.Config{RepoRoot: dir, Packages: map[string]*config.Package{"api": api, "api/plugin": plugin}},
+	}
+	rel := &PackageRelease{Package: api, OldVersion: "1.0.0", NewVersion: "1.1.0"}
+
+	changes, err := planPackageFiles(result, rel, func(name string) bool {
+		return strings.HasSuffix(name, ".conf")
+	}, func(content string) string {
+		return strings.ReplaceAll(content, "1.0.0", "1.1.0")
+	})
+	if err != nil {
+		t.Fatalf("planPackageFiles failed: %v", err)
+	}
+
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	want := []string{"api/app.conf", "api/deploy/app.conf"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("expected changes to %v, got %v", want, paths)
+	}
+	if len(changes) > 0 && changes[0].New != "version=1.1.0\n" {
+		t.Errorf("unexpected content: %q", changes[0].New)
+	}
+
+	// A package directory that doesn't exist yet has nothing to update
+	rel.Package = &config.Package{Path: "missing", Component: "missing"}
+	changes, err = planPackageFiles(result, rel, func(string) bool { return true }, func(s string) string { return s + "x" })
+	if err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes for a missing package dir, got %v, %v", changes, err)
+	}
+}
@@ -0,0 +1,86 @@
+package release
+
+import (
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// PendingPackage is where a package stands between releases: its version,
+// its latest release tag, and the release its unreleased commits call for.
+type PendingPackage struct {
+	Package *config.Package
+
+	// Version is the package's current version, from the manifest (or its
+	// tags with version-source: tags).
+	Version string
+
+	// LatestTag is the package's highest release tag reachable from HEAD,
+	// or "" if it has none.
+	LatestTag string
+
+	// Release is the release pending for the package, or nil if it has
+	// none.
+	Release *PackageRelease
+
+	// Deferred is the number of releasable commits held back by
+	// min-commits, or 0.
+	Deferred int
+}
+
+// TagDrift reports whether the package's latest release tag isn't the tag
+// of its current version, e.g. after a manifest bump that was never tagged.
+func (p *PendingPackage) TagDrift() bool {
+	return p.LatestTag != "" && p.LatestTag != buildTagName(p.Package.Component, p.Version)
+}
+
+// PendingReport lists every package with what releasing now would do.
+type PendingReport struct {
+	Result   *AnalysisResult
+	Packages []*PendingPackage
+}
+
+// Pending analyzes every commit since each package's last release tag, as
+// Options.Accumulate does, so it works whatever HEAD is. Nothing is written.
+func Pending(opts *Options) (*PendingReport, error) {
+	analyzeOpts := *opts
+	analyzeOpts.Accumulate = true
+	analyzeOpts.DryRun = true
+	result, err := Analyze(&analyzeOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make(map[string]*PackageRelease)
+	for _, rel := range result.Releases {
+		releases[rel.Package.Path] = rel
+	}
+	deferred := make(map[string]int)
+	if result.Stats != nil {
+		for _, d := range result.Stats.Deferred {
+			deferred[d.Package.Path] = d.Pending
+		}
+	}
+
+	report := &PendingReport{Result: result}
+	for _, pkg := range result.Config.PackagesSortedByPath() {
+		latest, err := latestTagVersion(opts.RepoPath, pkg)
+		if err != nil {
+			return nil, err
+		}
+		p := &PendingPackage{
+			Package:  pkg,
+			Version:  pkg.CurrentVersion,
+			Release:  releases[pkg.Path],
+			Deferred: deferred[pkg.Path],
+		}
+		if latest != nil {
+			p.LatestTag = buildTagName(pkg.Component, latest.String())
+		}
+		report.Packages = append(report.Packages, p)
+	}
+	return report, nil
+}
+
+// HasPending reports whether any package has a release pending.
+func (r *PendingReport) HasPending() bool {
+	return len(r.Result.Releases) > 0
+}
@@ -0,0 +1,64 @@
+package release
+
+import (
+	"testing"
+)
+
+func TestPending(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0", "HEAD~1")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.0", "HEAD~1")
+
+	// A manifest bump for service-b that was never tagged
+	writeFile(t, dir, "release-please-manifest.json", `{
+		"workloads/service-a": "0.1.0",
+		"workloads/service-b": "0.1.1"
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: release service-b 0.1.1")
+
+	// HEAD is a plain commit, not a merge
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Feature\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): add feature")
+
+	report, err := Pending(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if !report.HasPending() || len(report.Packages) != 2 {
+		t.Fatalf("expected both packages listed with a pending release, got %+v", report.Packages)
+	}
+
+	a, b := report.Packages[0], report.Packages[1]
+	if a.Release == nil || a.Release.NewVersion != "0.1.1" || a.LatestTag != "service-a-v0.1.0" || a.TagDrift() {
+		t.Errorf("expected service-a 0.1.0 → 0.1.1 since service-a-v0.1.0, got %+v", a)
+	}
+	if b.Version != "0.1.1" || b.LatestTag != "service-b-v0.1.0" || !b.TagDrift() {
+		t.Errorf("expected service-b's manifest ahead of its tag, got version %s tag %s", b.Version, b.LatestTag)
+	}
+}
+
+func TestPending_NothingPending(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	runCmd(t, dir, "git", "tag", "service-a-v0.1.0")
+
+	report, err := Pending(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+	if report.HasPending() {
+		t.Errorf("expected nothing pending, got %+v", report.Result.Releases)
+	}
+	if p := report.Packages[0]; p.Release != nil || p.LatestTag != "service-a-v0.1.0" || p.TagDrift() {
+		t.Errorf("expected service-a up to date at its tag, got %+v", p)
+	}
+}
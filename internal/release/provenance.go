@@ -0,0 +1,197 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// In-toto and SLSA identifiers used in provenance statements.
+const (
+	StatementType      = "https://in-toto.io/Statement/v1"
+	ProvenancePredType = "https://slsa.dev/provenance/v1"
+
+	// ProvenanceBuildType identifies how release-damnit decides versions, so
+	// verifiers know how to read ExternalParameters.
+	ProvenanceBuildType = "https://github.com/dsswift/release-damnit/release/v1"
+)
+
+// provenanceSuffix is appended to the tag name to name provenance files.
+const provenanceSuffix = ".intoto.json"
+
+// Statement is an in-toto statement about a release tag: its predicate is
+// SLSA provenance describing how the version was produced.
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// ResourceDescriptor identifies an artifact or input by name or URI and
+// digest.
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is a SLSA v1 provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs a release was produced from.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ReleaseParameters    `json:"externalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies"`
+}
+
+// ReleaseParameters are the release decision a provenance statement records.
+type ReleaseParameters struct {
+	Component       string `json:"component"`
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version"`
+	BumpType        string `json:"bump_type"`
+	Tag             string `json:"tag"`
+	Branch          string `json:"branch,omitempty"`
+
+	// Base and Head bound the analyzed commit range (see AnalysisResult.Base).
+	Base string `json:"base,omitempty"`
+	Head string `json:"head"`
+
+	// Accumulated is true if commits since the last release tag were
+	// analyzed (Options.Accumulate).
+	Accumulated bool `json:"accumulated"`
+
+	// Commits are the SHAs of the commits behind the release.
+	Commits []string `json:"commits"`
+}
+
+// RunDetails describes the run that produced a release.
+type RunDetails struct {
+	Builder  ProvenanceBuilder  `json:"builder"`
+	Metadata ProvenanceMetadata `json:"metadata"`
+}
+
+// ProvenanceBuilder identifies what ran release-damnit, e.g. a CI workflow.
+type ProvenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// ProvenanceMetadata identifies the run.
+type ProvenanceMetadata struct {
+	InvocationID string `json:"invocationId,omitempty"`
+	StartedOn    string `json:"startedOn,omitempty"`
+}
+
+// ProvenanceOptions describes the run for BuildProvenance.
+type ProvenanceOptions struct {
+	// Builder identifies what ran release-damnit.
+	Builder ProvenanceBuilder
+
+	// InvocationID identifies the run, e.g. a CI run URL.
+	InvocationID string
+
+	// StartedOn is when the run started.
+	StartedOn time.Time
+}
+
+// BuildProvenance returns the provenance statement for a release. Its
+// subject is the release tag at the commit it's created on; the config
+// digest and analyzed range show how the version was decided.
+func BuildProvenance(result *AnalysisResult, rel *PackageRelease, opts *ProvenanceOptions) *Statement {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotNil(result.MergeInfo, "result.MergeInfo")
+	contracts.RequireNotNil(rel, "rel")
+	if opts == nil {
+		opts = &ProvenanceOptions{}
+	}
+
+	head := result.MergeInfo.HeadSHA
+	tag := buildTagName(rel.Package.Component, rel.NewVersion)
+	params := ReleaseParameters{
+		Component:       rel.Package.Component,
+		Version:         rel.NewVersion,
+		PreviousVersion: rel.OldVersion,
+		BumpType:        rel.BumpType.String(),
+		Tag:             tag,
+		Branch:          result.Branch,
+		Base:            result.Base,
+		Head:            head,
+		Accumulated:     result.Accumulated,
+		Commits:         []string{},
+	}
+	for _, c := range rel.Commits {
+		params.Commits = append(params.Commits, c.SHA)
+	}
+
+	source := ResourceDescriptor{Name: "source", Digest: map[string]string{"gitCommit": head}}
+	if result.RepoURL != "" {
+		source.URI = "git+" + result.RepoURL
+		if result.Branch != "" {
+			source.URI += "@refs/heads/" + result.Branch
+		}
+	}
+	deps := []ResourceDescriptor{source}
+	if result.Config.Digest != "" {
+		deps = append(deps, ResourceDescriptor{Name: "config", Digest: map[string]string{"sha256": result.Config.Digest}})
+	}
+
+	metadata := ProvenanceMetadata{InvocationID: opts.InvocationID}
+	if !opts.StartedOn.IsZero() {
+		metadata.StartedOn = opts.StartedOn.UTC().Format(time.RFC3339)
+	}
+
+	return &Statement{
+		Type:          StatementType,
+		Subject:       []ResourceDescriptor{{Name: tag, Digest: map[string]string{"gitCommit": head}}},
+		PredicateType: ProvenancePredType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:            ProvenanceBuildType,
+				ExternalParameters:   params,
+				ResolvedDependencies: deps,
+			},
+			RunDetails: RunDetails{Builder: opts.Builder, Metadata: metadata},
+		},
+	}
+}
+
+// ProvenancePath returns where WriteProvenance writes a tag's statement in
+// dir.
+func ProvenancePath(dir, tagName string) string {
+	return filepath.Join(dir, tagName+provenanceSuffix)
+}
+
+// WriteProvenance writes each release's provenance statement to dir as
+// <tag>.intoto.json and returns the paths written.
+func WriteProvenance(result *AnalysisResult, dir string, opts *ProvenanceOptions) ([]string, error) {
+	contracts.RequireNotNil(result, "result")
+	contracts.RequireNotEmpty(dir, "dir")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create provenance directory: %w", err)
+	}
+	var paths []string
+	for _, rel := range result.Releases {
+		stmt := BuildProvenance(result, rel, opts)
+		data, err := json.MarshalIndent(stmt, "", "  ")
+		if err != nil {
+			return paths, fmt.Errorf("failed to marshal provenance for %s: %w", rel.Package.Component, err)
+		}
+		path := ProvenancePath(dir, stmt.Predicate.BuildDefinition.ExternalParameters.Tag)
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return paths, fmt.Errorf("failed to write provenance for %s: %w", rel.Package.Component, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
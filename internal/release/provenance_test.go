@@ -0,0 +1,105 @@
+package release
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// provenanceResult returns a merge that releases service-a 1.1.0.
+func provenanceResult() *AnalysisResult {
+	return &AnalysisResult{
+		MergeInfo: &git.MergeInfo{IsMerge: true, HeadSHA: "cccccccccccccccccccccccccccccccccccccccc"},
+		Base:      "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		Releases: []*PackageRelease{{
+			Package:    &config.Package{Path: "workloads/service-a", Component: "service-a"},
+			BumpType:   version.Minor,
+			OldVersion: "1.0.0",
+			NewVersion: "1.1.0",
+			Commits:    []*git.Commit{{SHA: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Type: "feat"}},
+		}},
+		Config:  &config.Config{Digest: "d1e5"},
+		RepoURL: "https://github.com/acme/platform",
+		Branch:  "main",
+	}
+}
+
+func TestBuildProvenance(t *testing.T) {
+	result := provenanceResult()
+	stmt := BuildProvenance(result, result.Releases[0], &ProvenanceOptions{
+		Builder:      ProvenanceBuilder{ID: "https://github.com/acme/platform/.github/workflows/release.yml@refs/heads/main"},
+		InvocationID: "https://github.com/acme/platform/actions/runs/42",
+		StartedOn:    time.Date(2024, 3, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600)),
+	})
+
+	if stmt.Type != StatementType || stmt.PredicateType != ProvenancePredType {
+		t.Errorf("unexpected statement types: %s, %s", stmt.Type, stmt.PredicateType)
+	}
+	if len(stmt.Subject) != 1 || stmt.Subject[0].Name != "service-a-v1.1.0" || stmt.Subject[0].Digest["gitCommit"] != result.MergeInfo.HeadSHA {
+		t.Errorf("expected the tag at HEAD as subject, got %+v", stmt.Subject)
+	}
+
+	params := stmt.Predicate.BuildDefinition.ExternalParameters
+	if params.Version != "1.1.0" || params.PreviousVersion != "1.0.0" || params.BumpType != "minor" || params.Base != result.Base || params.Head != result.MergeInfo.HeadSHA {
+		t.Errorf("unexpected parameters: %+v", params)
+	}
+	if len(params.Commits) != 1 || params.Commits[0] != "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" {
+		t.Errorf("expected the release's commits, got %v", params.Commits)
+	}
+
+	deps := stmt.Predicate.BuildDefinition.ResolvedDependencies
+	if len(deps) != 2 || deps[0].URI != "git+https://github.com/acme/platform@refs/heads/main" || deps[1].Digest["sha256"] != "d1e5" {
+		t.Errorf("expected the source and config digest, got %+v", deps)
+	}
+
+	run := stmt.Predicate.RunDetails
+	if run.Builder.ID == "" || run.Metadata.InvocationID == "" || run.Metadata.StartedOn != "2024-03-01T12:00:00Z" {
+		t.Errorf("unexpected run details: %+v", run)
+	}
+}
+
+func TestBuildProvenance_NoRepoURL(t *testing.T) {
+	result := provenanceResult()
+	result.RepoURL = ""
+	result.Config.Digest = ""
+
+	stmt := BuildProvenance(result, result.Releases[0], nil)
+	deps := stmt.Predicate.BuildDefinition.ResolvedDependencies
+	if len(deps) != 1 || deps[0].URI != "" || deps[0].Digest["gitCommit"] != result.MergeInfo.HeadSHA {
+		t.Errorf("expected only the source commit, got %+v", deps)
+	}
+	if stmt.Predicate.RunDetails.Metadata.StartedOn != "" {
+		t.Errorf("expected no start time, got %q", stmt.Predicate.RunDetails.Metadata.StartedOn)
+	}
+}
+
+func TestWriteProvenance(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "provenance")
+
+	paths, err := WriteProvenance(provenanceResult(), dir, nil)
+	if err != nil {
+		t.Fatalf("WriteProvenance failed: %v", err)
+	}
+	want := filepath.Join(dir, "service-a-v1.1.0.intoto.json")
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("expected %s, got %v", want, paths)
+	}
+
+	data, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stmt Statement
+	if err := json.Unmarshal(data, &stmt); err != nil {
+		t.Fatalf("invalid statement: %v", err)
+	}
+	if stmt.Subject[0].Name != "service-a-v1.1.0" {
+		t.Errorf("unexpected subject: %+v", stmt.Subject)
+	}
+}
@@ -0,0 +1,133 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// PullRequest is a GitHub pull request associated with a commit.
+type PullRequest struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	MergedAt string  `json:"merged_at"`
+	Labels   []Label `json:"labels"`
+}
+
+// Label is a GitHub issue or pull request label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// labelTypes maps pull request labels to conventional commit types, for
+// titles that aren't conventional themselves. Labels are matched
+// case-insensitively; type names (feat, fix, perf, ...) match as-is.
+var labelTypes = map[string]string{
+	"feature":     "feat",
+	"enhancement": "feat",
+	"bug":         "fix",
+	"bugfix":      "fix",
+	"performance": "perf",
+}
+
+// breakingLabels mark a pull request as a breaking change.
+var breakingLabels = map[string]bool{
+	"breaking":        true,
+	"breaking-change": true,
+	"breaking change": true,
+}
+
+// applyPullRequestTitles re-parses non-conventional commits (typically squash
+// merges with messy subjects) from the title and labels of their pull
+// request. A title parser reads as conventional wins; otherwise a type label
+// (e.g., "bug") sets the type, with the title as the description. Lookup
+// failures only warn, leaving the commit as it was. Returns the number of
+// commits updated.
+func applyPullRequestTitles(repoPath string, parser *git.Parser, commits []*git.Commit) int {
+	updated := 0
+	for _, c := range commits {
+		if c.Type != "" {
+			continue
+		}
+
+		pr, err := pullRequestForCommit(repoPath, c.SHA)
+		if err != nil {
+			slog.Warn("failed to look up pull request", "commit", c.ShortSHA, "error", err)
+			continue
+		}
+		if pr == nil || !applyPullRequest(c, parser, pr) {
+			continue
+		}
+
+		slog.Debug("parsed commit from pull request", "commit", c.ShortSHA, "pr", pr.Number, "type", c.Type)
+		updated++
+	}
+	return updated
+}
+
+// applyPullRequest updates a commit from a pull request's title, parsed with
+// parser, and labels. Returns false if neither gives a conventional type.
+func applyPullRequest(c *git.Commit, parser *git.Parser, pr *PullRequest) bool {
+	if !parser.Parse(c, pr.Title) {
+		commitType := ""
+		for _, label := range pr.Labels {
+			if t := labelType(label.Name); t != "" {
+				commitType = t
+				break
+			}
+		}
+		if commitType == "" {
+			return false
+		}
+		c.Type = commitType
+		c.Description = strings.TrimSpace(pr.Title)
+	}
+
+	for _, label := range pr.Labels {
+		if breakingLabels[strings.ToLower(label.Name)] {
+			c.IsBreaking = true
+		}
+	}
+	c.ParseBreakingChange()
+	return true
+}
+
+// labelType returns the conventional commit type for a label, or "".
+func labelType(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if t, ok := labelTypes[name]; ok {
+		return t
+	}
+	for _, section := range []string{"feat", "fix", "perf"} {
+		if name == section {
+			return name
+		}
+	}
+	return ""
+}
+
+// pullRequestForCommit returns the pull request a commit was merged through,
+// preferring a merged one, or nil if there's none.
+func pullRequestForCommit(repoPath, sha string) (*PullRequest, error) {
+	out, err := ghAPI(repoPath, fmt.Sprintf("repos/{owner}/{repo}/commits/%s/pulls", sha))
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []*PullRequest
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+	}
+	for _, pr := range prs {
+		if pr.MergedAt != "" {
+			return pr, nil
+		}
+	}
+	if len(prs) > 0 {
+		return prs[0], nil
+	}
+	return nil, nil
+}
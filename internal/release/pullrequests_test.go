@@ -0,0 +1,119 @@
+package release
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestApplyPullRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		pr           *PullRequest
+		wantOK       bool
+		wantType     string
+		wantScope    string
+		wantDesc     string
+		wantBreaking bool
+	}{
+		{
+			name:      "conventional title",
+			pr:        &PullRequest{Title: "fix(api): handle timeouts"},
+			wantOK:    true,
+			wantType:  "fix",
+			wantScope: "api",
+			wantDesc:  "handle timeouts",
+		},
+		{
+			name:     "type label",
+			pr:       &PullRequest{Title: "Handle timeouts", Labels: []Label{{Name: "docs"}, {Name: "Bug"}}},
+			wantOK:   true,
+			wantType: "fix",
+			wantDesc: "Handle timeouts",
+		},
+		{
+			name:         "breaking label",
+			pr:           &PullRequest{Title: "feat: new API", Labels: []Label{{Name: "breaking-change"}}},
+			wantOK:       true,
+			wantType:     "feat",
+			wantDesc:     "new API",
+			wantBreaking: true,
+		},
+		{
+			name:      "configured prefix",
+			pr:        &PullRequest{Title: "✨ feat(ui): dark mode"},
+			wantOK:    true,
+			wantType:  "feat",
+			wantScope: "ui",
+			wantDesc:  "dark mode",
+		},
+		{
+			name:     "nothing conventional",
+			pr:       &PullRequest{Title: "Misc updates", Labels: []Label{{Name: "breaking"}}},
+			wantOK:   false,
+			wantDesc: "WIP stuff",
+		},
+	}
+
+	parser, err := git.NewParser(&git.ParserOptions{StripPrefixes: []string{`✨\s*`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &git.Commit{SHA: "abc1234567890", ShortSHA: "abc1234", Description: "WIP stuff", Body: "Replaces the v1 API."}
+			if ok := applyPullRequest(c, parser, tc.pr); ok != tc.wantOK {
+				t.Fatalf("applyPullRequest() = %v, want %v", ok, tc.wantOK)
+			}
+			if c.Type != tc.wantType || c.Scope != tc.wantScope || c.Description != tc.wantDesc || c.IsBreaking != tc.wantBreaking {
+				t.Errorf("got %+v", c)
+			}
+			if c.IsBreaking && c.BreakingDescription != "Replaces the v1 API." {
+				t.Errorf("expected the body to describe the breaking change, got %q", c.BreakingDescription)
+			}
+		})
+	}
+}
+
+func TestApplyPullRequestTitles(t *testing.T) {
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch {
+		case strings.Contains(args[0], "/commits/aaa"):
+			// The open PR is listed first; the merged one should win
+			return []byte(`[{"number": 2, "title": "WIP"}, {"number": 1, "title": "feat: add export", "merged_at": "2024-01-15T00:00:00Z"}]`), nil
+		case strings.Contains(args[0], "/commits/bbb"):
+			return []byte(`[]`), nil
+		default:
+			return nil, errors.New("not found")
+		}
+	})
+
+	commits := []*git.Commit{
+		{SHA: "aaa1234567890", ShortSHA: "aaa1234", Description: "Squashed commits"},
+		{SHA: "bbb1234567890", ShortSHA: "bbb1234", Description: "No PR"},
+		{SHA: "ccc1234567890", ShortSHA: "ccc1234", Description: "Lookup fails"},
+		{SHA: "ddd1234567890", ShortSHA: "ddd1234", Type: "fix", Description: "already conventional"},
+	}
+
+	parser, err := git.NewParser(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := applyPullRequestTitles("", parser, commits); got != 1 {
+		t.Errorf("expected 1 commit updated, got %d", got)
+	}
+	if commits[0].Type != "feat" || commits[0].Description != "add export" {
+		t.Errorf("expected commit parsed from merged PR title, got %+v", commits[0])
+	}
+	if commits[1].Type != "" || commits[2].Type != "" {
+		t.Error("expected commits without a usable PR to be left alone")
+	}
+	if len(calls) != 3 {
+		t.Errorf("expected conventional commits to be skipped, got calls %v", calls)
+	}
+}
@@ -0,0 +1,359 @@
+package release
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// ErrRemoteAnalysis is returned when planning or applying the releases of
+// a remote analysis (AnalysisResult.Remote), which has no files to write.
+var ErrRemoteAnalysis = errors.New("remote analysis has no working tree to write releases to; it's read-only")
+
+// RemoteOptions configures AnalyzeRemote.
+type RemoteOptions struct {
+	// Repo is the GitHub repository to analyze, as owner/repo.
+	Repo string
+
+	// Ref is the commit to analyze, as HEAD is analyzed locally: a SHA, or
+	// a branch or tag name.
+	Ref string
+
+	// ConfigFile and ManifestFile are paths in the repository, as in Options.
+	ConfigFile   string
+	ManifestFile string
+
+	// Branch names the branch whose rules apply, as in Options. Empty
+	// means none.
+	Branch string
+
+	// RepoURL overrides the repository URL used for links. Empty means
+	// the config's canonical URL, else the repository on GITHUB_SERVER_URL
+	// (default https://github.com).
+	RepoURL string
+
+	// TreatPreMajorAsMinor is as in Options.
+	TreatPreMajorAsMinor bool
+}
+
+// remoteCommit is the part of a GitHub commit AnalyzeRemote needs.
+type remoteCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Files []struct {
+		Filename string `json:"filename"`
+	} `json:"files"`
+}
+
+// AnalyzeRemote analyzes a commit of a GitHub repository through the
+// GitHub API, without a clone: the config and manifest are read at the
+// commit, and its commits and their files come from the commits and
+// compare APIs. It's read-only, like the analysis of a bare repository.
+//
+// The commits are those Analyze would find for the same HEAD, except that
+// mainline commits cherry-picked into a merged branch aren't dropped.
+// Settings that need the history (version-source tags, min-commits, and
+// tag-collision) aren't applied.
+func AnalyzeRemote(opts *RemoteOptions) (*AnalysisResult, error) {
+	contracts.RequireNotNil(opts, "opts")
+	contracts.RequireNotEmpty(opts.Ref, "Ref")
+
+	owner, name, ok := strings.Cut(opts.Repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repository %q: must be owner/repo", opts.Repo)
+	}
+	repo := "repos/" + owner + "/" + name
+
+	head, err := fetchRemoteCommit(repo, opts.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// The config is read from the commit's tree; the root only anchors the
+	// relative paths
+	cfg, err := config.LoadWithOptions(string(filepath.Separator), &config.LoadOptions{
+		ConfigFile:   opts.ConfigFile,
+		ManifestFile: opts.ManifestFile,
+		FS:           &remoteFS{repo: repo, ref: head.SHA},
+	})
+	if err != nil {
+		return nil, &ConfigError{Err: fmt.Errorf("failed to load config: %w", err)}
+	}
+	if cfg.VersionSource == config.VersionSourceTags {
+		return nil, &ConfigError{Err: fmt.Errorf("version-source %s isn't supported by remote analysis", config.VersionSourceTags)}
+	}
+	if err := validateExtensions(cfg); err != nil {
+		return nil, &ConfigError{Err: fmt.Errorf("invalid config: %w", err)}
+	}
+
+	info := &git.MergeInfo{HeadSHA: head.SHA, IsMerge: len(head.Parents) > 1}
+	var commits []*git.Commit
+	if info.IsMerge {
+		info.FirstParent = head.Parents[0].SHA
+		for _, p := range head.Parents[1:] {
+			info.MergeHeads = append(info.MergeHeads, p.SHA)
+		}
+		info.MergeHead = info.MergeHeads[0]
+		if commits, info.MergeBase, err = fetchMergedCommits(repo, info); err != nil {
+			return nil, err
+		}
+		if cfg.MergeCommits != "" && cfg.MergeCommits != config.MergeCommitsIgnore {
+			merge := head.parse()
+			if err := reparseCommits(cfg, []*git.Commit{merge}); err != nil {
+				return nil, err
+			}
+			if merge.Type != "" {
+				if cfg.MergeCommits == config.MergeCommitsOnly {
+					commits = nil
+				}
+				commits = append(commits, merge)
+			}
+		}
+	} else {
+		if len(head.Parents) == 1 {
+			info.FirstParent = head.Parents[0].SHA
+		}
+		commits = []*git.Commit{head.parse()}
+	}
+
+	if err := reparseCommits(cfg, commits); err != nil {
+		return nil, err
+	}
+
+	stats := &AnalysisStats{}
+	packageCommits := make(map[string][]*git.Commit)
+	orphanedDirSet := make(map[string]bool)
+	var kept []*git.Commit
+	for _, commit := range commits {
+		if cfg.IsReleaseCommit(commit.Subject) {
+			stats.ReleaseCommits++
+			continue
+		}
+		var files []string
+		for _, file := range commit.Files {
+			if !cfg.IsIgnoredFile(file) {
+				files = append(files, file)
+			}
+		}
+		if len(files) == 0 && len(commit.Files) > 0 {
+			stats.IgnoredCommits++
+			continue
+		}
+		commit.Files = files
+		commit.Notes = commit.ReleaseNotes(cfg.IncludeCommitBody)
+		commit.Scope = cfg.NormalizeScope(commit.Scope)
+		kept = append(kept, commit)
+
+		matched := false
+		for _, file := range commit.Files {
+			if pkg := cfg.FindPackageForPath(file); pkg != nil {
+				packageCommits[pkg.Path] = append(packageCommits[pkg.Path], commit)
+				matched = true
+			} else {
+				orphanedDirSet[path.Dir(file)] = true
+			}
+		}
+		if matched {
+			stats.MatchedCommits++
+		}
+	}
+	stats.TotalCommits = len(kept)
+	stats.UnmatchedCommits = len(kept) - stats.MatchedCommits
+	for dir := range orphanedDirSet {
+		stats.OrphanedDirs = append(stats.OrphanedDirs, dir)
+	}
+	sort.Strings(stats.OrphanedDirs)
+
+	var maxBump version.BumpType
+	if branch := cfg.BranchFor(opts.Branch); branch != nil {
+		maxBump = branch.MaxBump
+	}
+	releases, err := calculateReleases(cfg, packageCommits, opts.TreatPreMajorAsMinor, maxBump)
+	if err != nil {
+		return nil, err
+	}
+	if releases, err = scheduleDependents(cfg, releases, opts.TreatPreMajorAsMinor); err != nil {
+		return nil, err
+	}
+
+	repoURL := ResolveRepoURL(&Options{RepoURL: opts.RepoURL}, cfg)
+	if repoURL == "" {
+		server := strings.TrimSuffix(os.Getenv("GITHUB_SERVER_URL"), "/")
+		if server == "" {
+			server = "https://github.com"
+		}
+		repoURL = server + "/" + opts.Repo
+	}
+
+	return &AnalysisResult{
+		MergeInfo: info,
+		Commits:   kept,
+		Releases:  releases,
+		Config:    cfg,
+		RepoURL:   repoURL,
+		Branch:    opts.Branch,
+		Remote:    opts.Repo,
+		Stats:     stats,
+		Base:      info.FirstParent,
+	}, nil
+}
+
+// parse returns the commit as a git.Commit.
+func (c *remoteCommit) parse() *git.Commit {
+	files := make([]string, 0, len(c.Files))
+	for _, f := range c.Files {
+		files = append(files, f.Filename)
+	}
+	return git.NewCommit(c.SHA, c.Commit.Message, files)
+}
+
+// fetchRemoteCommit fetches a commit with all its changed files.
+func fetchRemoteCommit(repo, ref string) (*remoteCommit, error) {
+	out, err := ghAPI("", "--paginate", repo+"/commits/"+ref+"?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commit %s: %w", ref, err)
+	}
+
+	// --paginate concatenates one commit per page, each with a page of files
+	var commit *remoteCommit
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for dec.More() {
+		var page remoteCommit
+		if err := dec.Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to parse commit %s: %w", ref, err)
+		}
+		if commit == nil {
+			commit = &page
+		} else {
+			commit.Files = append(commit.Files, page.Files...)
+		}
+	}
+	if commit == nil || commit.SHA == "" {
+		return nil, fmt.Errorf("commit %s not found", ref)
+	}
+	return commit, nil
+}
+
+// fetchMergedCommits returns the commits a merge brought in, oldest first,
+// with their files: those of each merged parent not on the first parent.
+// It also returns the merge base of the first two parents.
+func fetchMergedCommits(repo string, info *git.MergeInfo) ([]*git.Commit, string, error) {
+	var commits []*git.Commit
+	var mergeBase string
+	seen := make(map[string]bool)
+	for _, mergeHead := range info.MergeHeads {
+		out, err := ghAPI("", "--paginate", repo+"/compare/"+info.FirstParent+"..."+mergeHead+"?per_page=100")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to compare %.7s...%.7s: %w", info.FirstParent, mergeHead, err)
+		}
+
+		// --paginate concatenates one comparison per page
+		dec := json.NewDecoder(strings.NewReader(string(out)))
+		for dec.More() {
+			var page struct {
+				MergeBaseCommit struct {
+					SHA string `json:"sha"`
+				} `json:"merge_base_commit"`
+				Commits []remoteCommit `json:"commits"`
+			}
+			if err := dec.Decode(&page); err != nil {
+				return nil, "", fmt.Errorf("failed to parse comparison: %w", err)
+			}
+			if mergeBase == "" {
+				mergeBase = page.MergeBaseCommit.SHA
+			}
+			for _, c := range page.Commits {
+				if seen[c.SHA] {
+					continue
+				}
+				seen[c.SHA] = true
+
+				// The comparison doesn't list each commit's files
+				full, err := fetchRemoteCommit(repo, c.SHA)
+				if err != nil {
+					return nil, "", err
+				}
+				commits = append(commits, full.parse())
+			}
+		}
+	}
+	slog.Debug("fetched merged commits", "commits", len(commits))
+	return commits, mergeBase, nil
+}
+
+// remoteFS reads the files of a GitHub repository at a commit through the
+// contents API.
+type remoteFS struct {
+	repo string
+	ref  string
+}
+
+// Open implements fs.FS.
+func (r *remoteFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	out, err := ghAPI("", r.repo+"/contents/"+name+"?ref="+url.QueryEscape(r.ref))
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			err = fs.ErrNotExist
+		}
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	var content struct {
+		Type     string `json:"type"`
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	if err := json.Unmarshal(out, &content); err != nil || content.Type != "file" {
+		// A directory lists its entries as an array
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if content.Encoding != "base64" {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("unsupported encoding %q (file too large?)", content.Encoding)}
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &remoteFile{Reader: bytes.NewReader(data), name: path.Base(name)}, nil
+}
+
+// remoteFile is an open file of a remoteFS.
+type remoteFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *remoteFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *remoteFile) Close() error               { return nil }
+
+// Name, Mode, ModTime, IsDir, and Sys, with the Reader's Size, implement
+// fs.FileInfo.
+func (f *remoteFile) Name() string       { return f.name }
+func (f *remoteFile) Mode() fs.FileMode  { return 0444 }
+func (f *remoteFile) ModTime() time.Time { return time.Time{} }
+func (f *remoteFile) IsDir() bool        { return false }
+func (f *remoteFile) Sys() any           { return nil }
@@ -0,0 +1,151 @@
+package release
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// stubRemoteRepo serves a GitHub repository whose main is a merge of a
+// branch with a service-a feature and a service-b fix.
+func stubRemoteRepo(t *testing.T, files map[string]string) *[]string {
+	t.Helper()
+	content := func(s string) []byte {
+		return fmt.Appendf(nil, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(s)))
+	}
+	commit := func(sha, message string, parents []string, files ...string) []byte {
+		var ps, fs []string
+		for _, p := range parents {
+			ps = append(ps, fmt.Sprintf(`{"sha": %q}`, p))
+		}
+		for _, f := range files {
+			fs = append(fs, fmt.Sprintf(`{"filename": %q}`, f))
+		}
+		return fmt.Appendf(nil, `{"sha": %q, "commit": {"message": %q}, "parents": [%s], "files": [%s]}`,
+			sha, message, strings.Join(ps, ","), strings.Join(fs, ","))
+	}
+
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		endpoint := args[len(args)-1]
+		calls = append(calls, endpoint)
+		if path, ok := strings.CutPrefix(endpoint, "repos/o/r/contents/"); ok {
+			path, _, _ = strings.Cut(path, "?")
+			if data, ok := files[path]; ok {
+				return content(data), nil
+			}
+			return nil, errors.New("GitHub API GET failed: 404 Not Found")
+		}
+		switch endpoint {
+		case "repos/o/r/commits/main?per_page=100", "repos/o/r/commits/m1?per_page=100":
+			return commit("m1", "Merge pull request #1 from o/feature", []string{"p1", "p2"}, "workloads/service-a/a.go", "workloads/service-b/b.go"), nil
+		case "repos/o/r/compare/p1...p2?per_page=100":
+			return []byte(`{"merge_base_commit": {"sha": "b0"}, "commits": [{"sha": "c1"}, {"sha": "c2"}]}`), nil
+		case "repos/o/r/commits/c1?per_page=100":
+			return commit("c1", "feat(service-a): add a\n\nDetails.", []string{"b0"}, "workloads/service-a/a.go"), nil
+		case "repos/o/r/commits/c2?per_page=100":
+			// Files split over two pages
+			return append(commit("c2", "fix: fix b", []string{"c1"}, "workloads/service-b/b.go"),
+				commit("c2", "fix: fix b", []string{"c1"}, "docs/notes.md")...), nil
+		}
+		return nil, fmt.Errorf("unexpected call %v", args)
+	})
+	return &calls
+}
+
+var remoteConfig = map[string]string{
+	"release-please-config.json": `{"packages": {
+		"workloads/service-a": {"component": "service-a"},
+		"workloads/service-b": {"component": "service-b"}
+	}}`,
+	"release-please-manifest.json": `{"workloads/service-a": "0.1.0", "workloads/service-b": "1.2.0"}`,
+}
+
+func TestAnalyzeRemote(t *testing.T) {
+	calls := stubRemoteRepo(t, remoteConfig)
+
+	result, err := AnalyzeRemote(&RemoteOptions{Repo: "o/r", Ref: "main", TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("AnalyzeRemote failed: %v", err)
+	}
+	if result.Remote != "o/r" || result.RepoURL != "https://github.com/o/r" {
+		t.Errorf("expected remote o/r at https://github.com/o/r, got %q at %q", result.Remote, result.RepoURL)
+	}
+	info := result.MergeInfo
+	if !info.IsMerge || info.HeadSHA != "m1" || info.FirstParent != "p1" || info.MergeHead != "p2" || info.MergeBase != "b0" {
+		t.Errorf("unexpected merge info %+v", info)
+	}
+	if len(result.Commits) != 2 {
+		t.Fatalf("expected the 2 merged commits, got %d", len(result.Commits))
+	}
+	if c := result.Commits[1]; len(c.Files) != 2 || c.Files[1] != "docs/notes.md" {
+		t.Errorf("expected c2's files from both pages, got %v", c.Files)
+	}
+	if result.Stats.UnmatchedCommits != 0 || len(result.Stats.OrphanedDirs) != 1 || result.Stats.OrphanedDirs[0] != "docs" {
+		t.Errorf("unexpected stats %+v", result.Stats)
+	}
+
+	want := map[string]string{"service-a": "0.1.1", "service-b": "1.2.1"}
+	if len(result.Releases) != len(want) {
+		t.Fatalf("expected %d releases, got %d", len(want), len(result.Releases))
+	}
+	for _, rel := range result.Releases {
+		if want[rel.Package.Component] != rel.NewVersion {
+			t.Errorf("%s: expected %s, got %s", rel.Package.Component, want[rel.Package.Component], rel.NewVersion)
+		}
+	}
+	if c := result.Commits[0]; c.Type != "feat" || c.Scope != "service-a" || c.Body != "Details." {
+		t.Errorf("expected c1 parsed from its message, got %+v", c)
+	}
+
+	if _, err := PlanChanges(result); !errors.Is(err, ErrRemoteAnalysis) {
+		t.Errorf("expected ErrRemoteAnalysis from PlanChanges, got %v", err)
+	}
+	for _, call := range *calls {
+		if strings.Contains(call, "{owner}") {
+			t.Errorf("expected explicit repository endpoints, got %s", call)
+		}
+	}
+}
+
+func TestAnalyzeRemote_MergeCommitsOnly(t *testing.T) {
+	files := map[string]string{
+		"release-please-config.json":   `{"merge-commits": "only", "packages": {"workloads/service-a": {"component": "service-a"}}}`,
+		"release-please-manifest.json": `{"workloads/service-a": "0.1.0"}`,
+	}
+	stubRemoteRepo(t, files)
+
+	// The merge subject isn't conventional, so the merged commits are kept
+	result, err := AnalyzeRemote(&RemoteOptions{Repo: "o/r", Ref: "m1"})
+	if err != nil {
+		t.Fatalf("AnalyzeRemote failed: %v", err)
+	}
+	if len(result.Commits) != 2 {
+		t.Errorf("expected the merged commits kept, got %d", len(result.Commits))
+	}
+}
+
+func TestAnalyzeRemote_Errors(t *testing.T) {
+	stubRemoteRepo(t, map[string]string{
+		"release-please-config.json":   `{"version-source": "tags", "packages": {"workloads/service-a": {"component": "service-a"}}}`,
+		"release-please-manifest.json": `{}`,
+	})
+
+	if _, err := AnalyzeRemote(&RemoteOptions{Repo: "o", Ref: "main"}); err == nil || !strings.Contains(err.Error(), "owner/repo") {
+		t.Errorf("expected an invalid repository error, got %v", err)
+	}
+	if _, err := AnalyzeRemote(&RemoteOptions{Repo: "o/r", Ref: "nope"}); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+	var cfgErr *ConfigError
+	if _, err := AnalyzeRemote(&RemoteOptions{Repo: "o/r", Ref: "main"}); !errors.As(err, &cfgErr) || !strings.Contains(err.Error(), "version-source") {
+		t.Errorf("expected a ConfigError for version-source tags, got %v", err)
+	}
+
+	stubRemoteRepo(t, map[string]string{})
+	if _, err := AnalyzeRemote(&RemoteOptions{Repo: "o/r", Ref: "main"}); !errors.As(err, &cfgErr) {
+		t.Errorf("expected a ConfigError without a config, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package release
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// DefaultRemote is the remote the repository URL is detected from when none is given.
+const DefaultRemote = "origin"
+
+// ResolveRepoURL picks the URL used for links: opts.RepoURL, then
+// remotes.canonical-url, then the URL of the opts.Remote git remote.
+func ResolveRepoURL(opts *Options, cfg *config.Config) string {
+	if opts.RepoURL != "" {
+		return opts.RepoURL
+	}
+	if cfg.Remotes != nil && cfg.Remotes.CanonicalURL != "" {
+		return strings.TrimSuffix(cfg.Remotes.CanonicalURL, "/")
+	}
+	if opts.Remote == "" {
+		return ""
+	}
+
+	remoteURL, err := git.RemoteURL(opts.RepoPath, opts.Remote)
+	if err != nil {
+		slog.Debug("could not detect repository URL", "remote", opts.Remote, "error", err)
+		return ""
+	}
+	return RepoURLFromRemote(remoteURL)
+}
+
+// RepoURLFromRemote converts a git remote URL into the repository's web URL.
+// It handles scp-style (git@host:group/sub/repo.git), ssh:// and git:// URLs,
+// whose ports are SSH ports and are dropped, and http(s) URLs, which keep
+// their scheme and port. Credentials are always dropped, so tokens embedded
+// in a remote never end up in changelog links. Returns "" for local paths
+// and anything else without a host.
+func RepoURLFromRemote(remoteURL string) string {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	scheme := "https"
+	var host, path string
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Hostname() == "" {
+			return ""
+		}
+		switch u.Scheme {
+		case "http", "https":
+			scheme = u.Scheme
+			host = u.Host
+		case "ssh", "git", "git+ssh", "ssh+git":
+			host = u.Hostname()
+		default:
+			return ""
+		}
+		path = u.Path
+	} else {
+		// scp-style: [user@]host:path. Local paths have no colon, or a
+		// slash before it.
+		colon := strings.Index(remoteURL, ":")
+		if colon <= 0 || strings.Contains(remoteURL[:colon], "/") {
+			return ""
+		}
+		host = remoteURL[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		path = remoteURL[colon+1:]
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+	return scheme + "://" + host + "/" + path
+}
+
+// pushMirrorTags pushes a created release's tag to each mirror remote.
+// Failures only warn, since the release itself already exists.
+func pushMirrorTags(result *AnalysisResult, repoPath string, ghRelease *GitHubRelease) {
+	if result.Config == nil || result.Config.Remotes == nil {
+		return
+	}
+	for _, remote := range result.Config.Remotes.Mirrors {
+		if err := git.PushTag(repoPath, remote, ghRelease.TagName, ghRelease.TargetSHA); err != nil {
+			slog.Warn("failed to push tag to mirror", "remote", remote, "tag", ghRelease.TagName, "error", err)
+			continue
+		}
+		slog.Info("pushed tag to mirror", "remote", remote, "tag", ghRelease.TagName)
+	}
+}
@@ -3,14 +3,26 @@
 package release
 
 import (
+	"path"
+	"sort"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/changelog"
 	"github.com/dsswift/release-damnit/internal/config"
 	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
 )
 
 // ReleaseReport is the comprehensive JSON output for downstream workflows.
 // It enables simple component checks (contains in components array) and
 // detailed access to version info, commits, and release URLs.
 type ReleaseReport struct {
+	// Schema is the identifier of the JSON Schema describing this report.
+	Schema string `json:"$schema,omitempty"`
+
+	// SchemaVersion is the report format version (see ReportSchemaVersion).
+	SchemaVersion int `json:"schema_version"`
+
 	// Releases contains details for each released package.
 	Releases []ComponentRelease `json:"releases"`
 
@@ -20,6 +32,31 @@ type ReleaseReport struct {
 
 	// Summary provides aggregate statistics about the release.
 	Summary ReleaseSummary `json:"summary"`
+
+	// Unmatched lists changes no configured package owns, to surface
+	// configuration gaps.
+	Unmatched UnmatchedChanges `json:"unmatched"`
+
+	// Metrics are the run's timings and sizes (only with --timings or
+	// telemetry).
+	Metrics *Metrics `json:"metrics,omitempty"`
+
+	// Freeze is the release freeze in effect during the run, if any. Unless
+	// it was overridden, the releases were analyzed but not applied.
+	Freeze *FreezeInfo `json:"freeze,omitempty"`
+}
+
+// FreezeInfo describes a release freeze window in effect.
+type FreezeInfo struct {
+	// Name is the freeze window's name from the config.
+	Name string `json:"name"`
+
+	// Start and End bound this occurrence of the window (RFC 3339).
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Overridden is true if the run released anyway (--override-freeze).
+	Overridden bool `json:"overridden"`
 }
 
 // ComponentRelease contains release information for a single component.
@@ -36,20 +73,62 @@ type ComponentRelease struct {
 	// NewVersion is the new version being released.
 	NewVersion string `json:"new_version"`
 
+	// BuildVersion is NewVersion with build metadata (e.g., "0.2.0+a1b2c3d"),
+	// as written to the version file. Absent without --build-metadata.
+	BuildVersion string `json:"build_version,omitempty"`
+
 	// BumpType is "major", "minor", or "patch".
 	BumpType string `json:"bump_type"`
 
 	// TagName is the git tag (e.g., "jarvis-v0.1.120").
 	TagName string `json:"tag_name"`
 
+	// SHA is the commit the release is tagged at: its release commit with
+	// --commit, or else the HEAD that was analyzed.
+	SHA string `json:"sha,omitempty"`
+
+	// PreviousTagName is the tag of the release before this one (e.g.,
+	// "jarvis-v0.1.119"). Absent for a first release.
+	PreviousTagName string `json:"previous_tag_name,omitempty"`
+
+	// CompareURL links the changes since PreviousTagName. Absent for a
+	// first release or without a repo URL.
+	CompareURL string `json:"compare_url,omitempty"`
+
 	// ReleaseURL is the GitHub release URL (if created).
 	ReleaseURL string `json:"release_url,omitempty"`
 
+	// Verified reports whether the created GitHub release was found via the
+	// API. Absent when no release was created.
+	Verified *bool `json:"verified,omitempty"`
+
 	// LinkedBump is true if this release was bumped due to linked-versions.
 	LinkedBump bool `json:"linked_bump"`
 
+	// ReconciledFrom is set if the package had drifted behind its
+	// linked-versions group: the group's highest version, which this release
+	// was bumped from instead of OldVersion so the group converges again.
+	ReconciledFrom string `json:"reconciled_from,omitempty"`
+
+	// FirstRelease is true if the component was never released before (its
+	// version was 0.0.0); OldVersion is then "0.0.0", with no tag of its own.
+	FirstRelease bool `json:"first_release"`
+
+	// DependencyChain is set if this release was scheduled because a
+	// dependency was released (see config dependencies): the released
+	// component first, then each dependent in between.
+	DependencyChain []string `json:"dependency_chain,omitempty"`
+
 	// Commits contains the commits that triggered this release.
 	Commits []CommitInfo `json:"commits"`
+
+	// ReleaseNotes is the rendered GitHub release notes markdown.
+	ReleaseNotes string `json:"release_notes"`
+
+	// ChangelogEntry is the rendered CHANGELOG.md entry for this version.
+	// For linked bumps with no commits of their own, it's a note naming the
+	// release they follow.
+	ChangelogEntry string `json:"changelog_entry,omitempty"`
 }
 
 // CommitInfo contains commit details for the release report.
@@ -92,6 +171,12 @@ type BumpTypeCounts struct {
 // AnalysisInput is the JSON output showing what data was used for release decisions.
 // This enables debugging, auditing, and verification of the release process.
 type AnalysisInput struct {
+	// Schema is the identifier of the JSON Schema describing this output.
+	Schema string `json:"$schema,omitempty"`
+
+	// SchemaVersion is the output format version (see ReportSchemaVersion).
+	SchemaVersion int `json:"schema_version"`
+
 	// Git contains information about the analyzed commit(s).
 	Git GitInfo `json:"git"`
 
@@ -100,6 +185,10 @@ type AnalysisInput struct {
 
 	// Config summarizes the release configuration used.
 	Config ConfigSummary `json:"config"`
+
+	// Unmatched lists changes no configured package owns, to surface
+	// configuration gaps.
+	Unmatched UnmatchedChanges `json:"unmatched"`
 }
 
 // GitInfo contains git state information.
@@ -116,6 +205,10 @@ type GitInfo struct {
 	// MergeHead is the tip of the merged branch (for merge commits).
 	MergeHead string `json:"merge_head,omitempty"`
 
+	// MergeHeads are the tips of all merged branches; more than one for
+	// octopus merges.
+	MergeHeads []string `json:"merge_heads,omitempty"`
+
 	// Branch is the current branch name (if available).
 	Branch string `json:"branch,omitempty"`
 }
@@ -144,6 +237,33 @@ type AnalyzedCommit struct {
 	PackagesMatched []string `json:"packages_matched"`
 }
 
+// UnmatchedChanges describes analyzed changes outside every configured package.
+type UnmatchedChanges struct {
+	// Commits are the commits that touched no configured package.
+	Commits []UnmatchedCommit `json:"commits"`
+
+	// Files are the changed files no package owns, sorted. Includes files
+	// from commits that also touched a package.
+	Files []string `json:"files"`
+
+	// SuggestedPaths are directories that could be added as packages: the
+	// outermost directories containing unmatched files, sorted. Files at the
+	// repo root aren't suggested.
+	SuggestedPaths []string `json:"suggested_paths"`
+}
+
+// UnmatchedCommit is a commit that touched no configured package.
+type UnmatchedCommit struct {
+	// SHA is the full commit hash.
+	SHA string `json:"sha"`
+
+	// Message is the commit message subject.
+	Message string `json:"message"`
+
+	// Files lists the files modified by the commit.
+	Files []string `json:"files"`
+}
+
 // ConfigSummary summarizes the release configuration.
 type ConfigSummary struct {
 	// Packages maps path to component name.
@@ -156,12 +276,24 @@ type ConfigSummary struct {
 // BuildReleaseReport creates a ReleaseReport from an AnalysisResult.
 func BuildReleaseReport(result *AnalysisResult, repoURL string) *ReleaseReport {
 	report := &ReleaseReport{
-		Releases:   make([]ComponentRelease, 0, len(result.Releases)),
-		Components: make([]string, 0, len(result.Releases)),
+		Schema:        ReleaseReportSchemaURL,
+		SchemaVersion: ReportSchemaVersion,
+		Releases:      make([]ComponentRelease, 0, len(result.Releases)),
+		Components:    make([]string, 0, len(result.Releases)),
 		Summary: ReleaseSummary{
 			TotalReleases: len(result.Releases),
 			TotalCommits:  len(result.Commits),
 		},
+		Unmatched: BuildUnmatchedChanges(result),
+		Metrics:   BuildMetrics(result),
+	}
+	if f := result.Freeze; f != nil {
+		report.Freeze = &FreezeInfo{
+			Name:       f.Name,
+			Start:      f.Start.Format(time.RFC3339),
+			End:        f.End.Format(time.RFC3339),
+			Overridden: result.FreezeOverridden,
+		}
 	}
 
 	for _, rel := range result.Releases {
@@ -172,8 +304,21 @@ func BuildReleaseReport(result *AnalysisResult, repoURL string) *ReleaseReport {
 			NewVersion: rel.NewVersion,
 			BumpType:   rel.BumpType.String(),
 			TagName:    buildTagName(rel.Package.Component, rel.NewVersion),
-			LinkedBump: len(rel.Commits) == 0 && rel.Package.LinkedGroup != "",
+			Verified:   rel.Verified,
+			LinkedBump: len(rel.Commits) == 0 && rel.Package.LinkedGroup != "" && len(rel.DependencyChain) == 0,
 			Commits:    make([]CommitInfo, 0, len(rel.Commits)),
+
+			ReconciledFrom:  rel.ReconciledFrom,
+			DependencyChain: rel.DependencyChain,
+			FirstRelease:    rel.FirstRelease,
+			CompareURL:      rel.compareURL(repoURL),
+		}
+		compRelease.SHA = result.TargetSHA(rel)
+		if prev := rel.previousVersion(); prev != "" {
+			compRelease.PreviousTagName = buildTagName(rel.Package.Component, prev)
+		}
+		if rel.Build != "" {
+			compRelease.BuildVersion = rel.BuildVersion()
 		}
 
 		// Build release URL if repo URL is available
@@ -192,6 +337,12 @@ func BuildReleaseReport(result *AnalysisResult, repoURL string) *ReleaseReport {
 			})
 		}
 
+		// Render notes so downstream jobs don't have to
+		compRelease.ReleaseNotes = BuildReleaseNotes(rel, repoURL)
+		if entry := changelogEntry(result, rel, repoURL); len(rel.Commits) > 0 || entry.Note != "" {
+			compRelease.ChangelogEntry = changelog.Generate(entry)
+		}
+
 		report.Releases = append(report.Releases, compRelease)
 		report.Components = append(report.Components, rel.Package.Component)
 
@@ -212,21 +363,26 @@ func BuildReleaseReport(result *AnalysisResult, repoURL string) *ReleaseReport {
 // BuildAnalysisInput creates an AnalysisInput from an AnalysisResult.
 func BuildAnalysisInput(result *AnalysisResult) *AnalysisInput {
 	input := &AnalysisInput{
+		Schema:        AnalysisInputSchemaURL,
+		SchemaVersion: ReportSchemaVersion,
 		Git: GitInfo{
 			HeadSHA:       result.MergeInfo.HeadSHA,
 			IsMergeCommit: result.MergeInfo.IsMerge,
+			Branch:        result.Branch,
 		},
 		CommitsAnalyzed: make([]AnalyzedCommit, 0, len(result.Commits)),
 		Config: ConfigSummary{
 			Packages:     make(map[string]string),
 			LinkedGroups: make(map[string][]string),
 		},
+		Unmatched: BuildUnmatchedChanges(result),
 	}
 
 	// Set merge info if applicable
 	if result.MergeInfo.IsMerge {
 		input.Git.MergeBase = result.MergeInfo.MergeBase
 		input.Git.MergeHead = result.MergeInfo.MergeHead
+		input.Git.MergeHeads = result.MergeInfo.MergeHeads
 	}
 
 	// Build package to component map for matching
@@ -249,7 +405,7 @@ func BuildAnalysisInput(result *AnalysisResult) *AnalysisInput {
 			Type:            c.Type,
 			Scope:           c.Scope,
 			Breaking:        c.IsBreaking,
-			FilesChanged:    c.Files,
+			FilesChanged:    emptyIfNil(c.Files),
 			PackagesMatched: findMatchingPackages(c.Files, result.Config),
 		}
 		input.CommitsAnalyzed = append(input.CommitsAnalyzed, analyzed)
@@ -258,14 +414,96 @@ func BuildAnalysisInput(result *AnalysisResult) *AnalysisInput {
 	return input
 }
 
+// BuildUnmatchedChanges collects the analyzed changes that no configured
+// package owns.
+func BuildUnmatchedChanges(result *AnalysisResult) UnmatchedChanges {
+	return collectUnmatched(result, func(*git.Commit) bool { return true })
+}
+
+// BuildUnreleasedChanges collects the releasable changes (breaking, or of a
+// type that bumps the version) that no configured package owns: changes that
+// went unreleased because of a configuration gap.
+func BuildUnreleasedChanges(result *AnalysisResult) UnmatchedChanges {
+	return collectUnmatched(result, func(c *git.Commit) bool {
+		return c.IsBreaking || result.Config.BumpFor(c.Type) != version.None
+	})
+}
+
+// collectUnmatched collects the unowned changes of the analyzed commits
+// that keep accepts.
+func collectUnmatched(result *AnalysisResult, keep func(*git.Commit) bool) UnmatchedChanges {
+	unmatched := UnmatchedChanges{
+		Commits:        []UnmatchedCommit{},
+		Files:          []string{},
+		SuggestedPaths: []string{},
+	}
+	if result.Config == nil {
+		return unmatched
+	}
+
+	fileSet := make(map[string]bool)
+	for _, c := range result.Commits {
+		if !keep(c) {
+			continue
+		}
+		matched := false
+		for _, file := range c.Files {
+			if result.Config.FindPackageForPath(file) != nil {
+				matched = true
+			} else {
+				fileSet[file] = true
+			}
+		}
+		if !matched {
+			unmatched.Commits = append(unmatched.Commits, UnmatchedCommit{
+				SHA:     c.SHA,
+				Message: buildCommitMessage(c),
+				Files:   emptyIfNil(c.Files),
+			})
+		}
+	}
+
+	dirSet := make(map[string]bool)
+	for file := range fileSet {
+		unmatched.Files = append(unmatched.Files, file)
+		if dir := path.Dir(file); dir != "." {
+			dirSet[dir] = true
+		}
+	}
+	sort.Strings(unmatched.Files)
+	unmatched.SuggestedPaths = outermostDirs(dirSet)
+
+	return unmatched
+}
+
+// outermostDirs returns the directories in dirs that aren't inside another
+// one, sorted.
+func outermostDirs(dirs map[string]bool) []string {
+	result := []string{}
+	for dir := range dirs {
+		nested := false
+		for parent := path.Dir(dir); parent != "." && parent != "/"; parent = path.Dir(parent) {
+			if dirs[parent] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			result = append(result, dir)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 // buildTagName creates a tag name from component and version.
 func buildTagName(component, version string) string {
 	return component + "-v" + version
 }
 
-// buildReleaseURL creates a GitHub release URL.
+// buildReleaseURL creates a release URL for the repository's hosting platform.
 func buildReleaseURL(repoURL, tagName string) string {
-	return repoURL + "/releases/tag/" + tagName
+	return changelog.BuildReleaseURL(repoURL, tagName)
 }
 
 // buildCommitMessage reconstructs the commit message from parsed parts.
@@ -290,7 +528,7 @@ func buildCommitMessage(c *git.Commit) string {
 // findMatchingPackages returns component names for packages that match the given files.
 func findMatchingPackages(files []string, cfg *config.Config) []string {
 	seen := make(map[string]bool)
-	var result []string
+	result := []string{}
 
 	for _, file := range files {
 		pkg := cfg.FindPackageForPath(file)
@@ -302,3 +540,12 @@ func findMatchingPackages(files []string, cfg *config.Config) []string {
 
 	return result
 }
+
+// emptyIfNil returns s, or an empty slice if s is nil, so it marshals as []
+// rather than null.
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -2,6 +2,8 @@ package release
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dsswift/release-damnit/internal/config"
@@ -34,6 +36,12 @@ func TestBuildReleaseReport_Empty(t *testing.T) {
 	if report.Summary.TotalReleases != 0 {
 		t.Errorf("expected 0 total releases, got %d", report.Summary.TotalReleases)
 	}
+	if report.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("expected schema_version %d, got %d", ReportSchemaVersion, report.SchemaVersion)
+	}
+	if report.Schema != ReleaseReportSchemaURL {
+		t.Errorf("expected $schema %s, got %s", ReleaseReportSchemaURL, report.Schema)
+	}
 
 	// Verify it marshals to valid JSON
 	jsonBytes, err := json.Marshal(report)
@@ -120,6 +128,15 @@ func TestBuildReleaseReport_SingleRelease(t *testing.T) {
 	if rel.LinkedBump {
 		t.Error("expected linked_bump to be false")
 	}
+	if rel.SHA != "def7890123456abc" {
+		t.Errorf("expected the release SHA to be HEAD, got %s", rel.SHA)
+	}
+	if rel.PreviousTagName != "service-a-v0.1.0" {
+		t.Errorf("expected previous tag name service-a-v0.1.0, got %s", rel.PreviousTagName)
+	}
+	if rel.CompareURL != "https://github.com/test/repo/compare/service-a-v0.1.0...service-a-v0.2.0" {
+		t.Errorf("unexpected compare URL: %s", rel.CompareURL)
+	}
 
 	// Check commits in release
 	if len(rel.Commits) != 1 {
@@ -135,6 +152,19 @@ func TestBuildReleaseReport_SingleRelease(t *testing.T) {
 		t.Errorf("expected scope service-a, got %s", rel.Commits[0].Scope)
 	}
 
+	// Check rendered notes
+	if !contains(rel.ReleaseNotes, "### Features") || !contains(rel.ReleaseNotes, "add new feature") {
+		t.Errorf("expected release notes with feature, got:\n%s", rel.ReleaseNotes)
+	}
+	if !contains(rel.ChangelogEntry, "## [0.2.0](https://github.com/test/repo/compare/service-a-v0.1.0...service-a-v0.2.0)") {
+		t.Errorf("expected changelog entry header, got:\n%s", rel.ChangelogEntry)
+	}
+	// The report's entry is the one written to CHANGELOG.md
+	result.RepoURL = "https://github.com/test/repo"
+	if want := RenderChangelog(result, result.Releases[0]); rel.ChangelogEntry != want {
+		t.Errorf("expected the rendered changelog entry, got:\n%s\nwant:\n%s", rel.ChangelogEntry, want)
+	}
+
 	// Check components array
 	if len(report.Components) != 1 {
 		t.Fatalf("expected 1 component, got %d", len(report.Components))
@@ -241,6 +271,9 @@ func TestBuildReleaseReport_LinkedBump(t *testing.T) {
 	if !relB.LinkedBump {
 		t.Error("service-b should be marked as linked bump")
 	}
+	if !strings.Contains(relB.ChangelogEntry, "Version bump to stay in sync with services; see service-a 1.1.0.") {
+		t.Errorf("linked bump without commits should have a note entry, got:\n%s", relB.ChangelogEntry)
+	}
 
 	// Both should be in components array
 	if len(report.Components) != 2 {
@@ -294,6 +327,34 @@ func TestBuildReleaseReport_AllBumpTypes(t *testing.T) {
 	}
 }
 
+func TestBuildReleaseReport_Verified(t *testing.T) {
+	verified := true
+	result := &AnalysisResult{
+		MergeInfo: &git.MergeInfo{HeadSHA: "abc123"},
+		Releases: []*PackageRelease{
+			{Package: &config.Package{Path: "a", Component: "a"}, BumpType: version.Patch, OldVersion: "1.0.0", NewVersion: "1.0.1", Verified: &verified},
+			{Package: &config.Package{Path: "b", Component: "b"}, BumpType: version.Patch, OldVersion: "1.0.0", NewVersion: "1.0.1"},
+		},
+		Config: &config.Config{Packages: make(map[string]*config.Package)},
+	}
+
+	report := BuildReleaseReport(result, "")
+	if v := report.Releases[0].Verified; v == nil || !*v {
+		t.Errorf("expected a verified, got %v", v)
+	}
+	if v := report.Releases[1].Verified; v != nil {
+		t.Errorf("expected no verification result for b, got %v", *v)
+	}
+
+	data, err := json.Marshal(report.Releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"verified":true`) || strings.Count(string(data), `"verified"`) != 1 {
+		t.Errorf("expected verified only on the created release: %s", data)
+	}
+}
+
 func TestBuildAnalysisInput_Empty(t *testing.T) {
 	result := &AnalysisResult{
 		MergeInfo: &git.MergeInfo{
@@ -319,6 +380,9 @@ func TestBuildAnalysisInput_Empty(t *testing.T) {
 	if len(input.CommitsAnalyzed) != 0 {
 		t.Errorf("expected 0 commits, got %d", len(input.CommitsAnalyzed))
 	}
+	if input.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("expected schema_version %d, got %d", ReportSchemaVersion, input.SchemaVersion)
+	}
 
 	// Verify JSON marshaling
 	jsonBytes, err := json.Marshal(input)
@@ -331,6 +395,26 @@ func TestBuildAnalysisInput_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildAnalysisInput_UnmatchedCommitEmptyLists(t *testing.T) {
+	result := &AnalysisResult{
+		MergeInfo: &git.MergeInfo{HeadSHA: "abc1234567890"},
+		Commits:   []*git.Commit{{SHA: "abc1234567890", Type: "chore", Description: "tidy"}},
+		Config: &config.Config{
+			Packages:     make(map[string]*config.Package),
+			LinkedGroups: make(map[string][]string),
+		},
+	}
+
+	jsonBytes, err := json.Marshal(BuildAnalysisInput(result))
+	if err != nil {
+		t.Fatalf("failed to marshal input: %v", err)
+	}
+	// A commit matching no package, with no files, still has lists
+	if strings.Contains(string(jsonBytes), "null") {
+		t.Errorf("expected empty lists rather than null, got %s", jsonBytes)
+	}
+}
+
 func TestBuildAnalysisInput_MergeCommit(t *testing.T) {
 	result := &AnalysisResult{
 		MergeInfo: &git.MergeInfo{
@@ -339,6 +423,7 @@ func TestBuildAnalysisInput_MergeCommit(t *testing.T) {
 			MergeBase: "base123456",
 			MergeHead: "head123456",
 		},
+		Branch: "main",
 		Commits: []*git.Commit{
 			{
 				SHA:         "commit1",
@@ -383,6 +468,9 @@ func TestBuildAnalysisInput_MergeCommit(t *testing.T) {
 	if input.Git.MergeHead != "head123456" {
 		t.Errorf("expected merge head head123456, got %s", input.Git.MergeHead)
 	}
+	if input.Git.Branch != "main" {
+		t.Errorf("expected branch main, got %q", input.Git.Branch)
+	}
 
 	// Check commits
 	if len(input.CommitsAnalyzed) != 2 {
@@ -421,6 +509,60 @@ func TestBuildAnalysisInput_MergeCommit(t *testing.T) {
 	}
 }
 
+func TestBuildUnmatchedChanges(t *testing.T) {
+	result := &AnalysisResult{
+		MergeInfo: &git.MergeInfo{HeadSHA: "abc1234567890"},
+		Commits: []*git.Commit{
+			{
+				SHA:         "commit1",
+				Type:        "feat",
+				Description: "add endpoint",
+				Files:       []string{"workloads/api/src/endpoint.go", "tools/gen/main.go"},
+			},
+			{
+				SHA:         "commit2",
+				Type:        "chore",
+				Scope:       "ci",
+				Description: "tweak scripts",
+				Files:       []string{"tools/gen/templates/a.tmpl", "tools-extra/run.sh", "Makefile"},
+			},
+		},
+		Config: &config.Config{
+			Packages: map[string]*config.Package{
+				"workloads/api": {Path: "workloads/api", Component: "api"},
+			},
+		},
+	}
+
+	unmatched := BuildUnmatchedChanges(result)
+
+	if len(unmatched.Commits) != 1 || unmatched.Commits[0].SHA != "commit2" {
+		t.Fatalf("expected only commit2 unmatched, got %+v", unmatched.Commits)
+	}
+	if unmatched.Commits[0].Message != "chore(ci): tweak scripts" {
+		t.Errorf("unexpected message %q", unmatched.Commits[0].Message)
+	}
+
+	wantFiles := []string{"Makefile", "tools-extra/run.sh", "tools/gen/main.go", "tools/gen/templates/a.tmpl"}
+	if !reflect.DeepEqual(unmatched.Files, wantFiles) {
+		t.Errorf("expected files %v, got %v", wantFiles, unmatched.Files)
+	}
+
+	// Nested directories collapse into their outermost unmatched directory
+	wantPaths := []string{"tools-extra", "tools/gen"}
+	if !reflect.DeepEqual(unmatched.SuggestedPaths, wantPaths) {
+		t.Errorf("expected suggested paths %v, got %v", wantPaths, unmatched.SuggestedPaths)
+	}
+
+	// Both outputs carry the section
+	if report := BuildReleaseReport(result, ""); len(report.Unmatched.Files) != len(wantFiles) {
+		t.Errorf("expected release report to include unmatched files, got %v", report.Unmatched.Files)
+	}
+	if input := BuildAnalysisInput(result); len(input.Unmatched.Commits) != 1 {
+		t.Errorf("expected analysis input to include unmatched commits, got %v", input.Unmatched.Commits)
+	}
+}
+
 func TestBuildCommitMessage(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -609,3 +751,40 @@ func TestAnalysisInput_JSONRoundtrip(t *testing.T) {
 		t.Errorf("config packages mismatch")
 	}
 }
+
+func TestBuildUnreleasedChanges(t *testing.T) {
+	result := &AnalysisResult{
+		Commits: []*git.Commit{
+			{SHA: "commit1", Type: "feat", Description: "add generator", Files: []string{"tools/gen/main.go"}},
+			{SHA: "commit2", Type: "docs", Description: "document scripts", Files: []string{"scripts/README.md"}},
+			{SHA: "commit3", Type: "chore", IsBreaking: true, Description: "drop old flag", Files: []string{"tools/cli/flags.go"}},
+			{SHA: "commit4", Type: "fix", Description: "fix endpoint", Files: []string{"workloads/api/src/endpoint.go"}},
+		},
+		Config: &config.Config{
+			Packages: map[string]*config.Package{
+				"workloads/api": {Path: "workloads/api", Component: "api"},
+			},
+		},
+	}
+
+	unreleased := BuildUnreleasedChanges(result)
+
+	var shas []string
+	for _, c := range unreleased.Commits {
+		shas = append(shas, c.SHA)
+	}
+	if want := []string{"commit1", "commit3"}; !reflect.DeepEqual(shas, want) {
+		t.Errorf("expected unreleased commits %v, got %v", want, shas)
+	}
+	if want := []string{"tools/cli/flags.go", "tools/gen/main.go"}; !reflect.DeepEqual(unreleased.Files, want) {
+		t.Errorf("expected files %v, got %v", want, unreleased.Files)
+	}
+	if want := []string{"tools/cli", "tools/gen"}; !reflect.DeepEqual(unreleased.SuggestedPaths, want) {
+		t.Errorf("expected suggested paths %v, got %v", want, unreleased.SuggestedPaths)
+	}
+
+	// Unreleasable changes are still unmatched
+	if unmatched := BuildUnmatchedChanges(result); len(unmatched.Commits) != 3 {
+		t.Errorf("expected 3 unmatched commits, got %+v", unmatched.Commits)
+	}
+}
@@ -0,0 +1,267 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// RereleaseOptions configures Rerelease.
+type RereleaseOptions struct {
+	// RepoPath is the path to the git repository.
+	RepoPath string
+
+	// RepoURL and Remote pick the URL used for links, as in Options.
+	RepoURL string
+	Remote  string
+
+	// Force deletes the release and creates it again instead of editing it.
+	// The tag is kept.
+	Force bool
+
+	// DryRun if true, only regenerate the notes.
+	DryRun bool
+}
+
+// RereleaseResult is an existing release with its regenerated notes.
+type RereleaseResult struct {
+	// Release is the release with its regenerated title and notes.
+	Release *GitHubRelease
+
+	// OldTitle and OldNotes are what the release had on GitHub.
+	OldTitle string
+	OldNotes string
+
+	// Recreated is true if the release was deleted and created again.
+	Recreated bool
+}
+
+// Changed reports whether the regenerated title or notes differ from the
+// release's.
+func (r *RereleaseResult) Changed() bool {
+	return r.Release.Title != r.OldTitle || r.Release.Notes != r.OldNotes
+}
+
+// existingRelease is the part of a GitHub release Rerelease needs.
+type existingRelease struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// Rerelease regenerates the notes of an already published release from the
+// commits between its tag and the package's previous release tag, and
+// updates the release's title and body in place. With opts.Force the
+// release is deleted and created again at the tag instead. Notes of a
+// release without commits of its own (a linked or dependency bump) are
+// regenerated without the bump's explanation.
+func Rerelease(cfg *config.Config, tag string, opts *RereleaseOptions) (*RereleaseResult, error) {
+	pkg, ver, err := packageForTag(cfg, tag)
+	if err != nil {
+		return nil, err
+	}
+	head, err := tagCommit(opts.RepoPath, tag)
+	if err != nil {
+		return nil, err
+	}
+	if head == "" {
+		return nil, fmt.Errorf("tag %s not found", tag)
+	}
+
+	prevVersion, err := previousTagVersion(opts.RepoPath, pkg, ver)
+	if err != nil {
+		return nil, err
+	}
+	prevTag := ""
+	if prevVersion != "" {
+		prevTag = buildTagName(pkg.Component, prevVersion)
+	}
+	commits, err := releaseCommits(opts.RepoPath, cfg, pkg, prevTag, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &PackageRelease{Package: pkg, OldVersion: prevVersion, NewVersion: ver.String(), Commits: commits, FirstRelease: prevVersion == ""}
+	repoURL := ResolveRepoURL(&Options{RepoPath: opts.RepoPath, RepoURL: opts.RepoURL, Remote: opts.Remote}, cfg)
+	ghRelease := BuildGitHubRelease(rel, repoURL)
+	ghRelease.TargetSHA = head
+	ghRelease.DiscussionCategory = cfg.DiscussionCategory
+
+	existing, err := fetchRelease(opts.RepoPath, tag)
+	if err != nil {
+		return nil, err
+	}
+	date := existing.PublishedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+	ghRelease.Title = ReleaseTitle(rel, date)
+
+	// GitHub rejects oversized bodies; attach the full notes instead
+	if oversizedNotes(ghRelease) {
+		notesDir, err := os.MkdirTemp("", "release-damnit-notes-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create release notes directory: %w", err)
+		}
+		defer os.RemoveAll(notesDir)
+		if err := fitReleaseNotes(ghRelease, repoURL, notesDir); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &RereleaseResult{Release: ghRelease, OldTitle: existing.Name, OldNotes: existing.Body}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if !opts.Force {
+		if !result.Changed() {
+			slog.Info("release notes unchanged", "tag", tag)
+			return result, nil
+		}
+		if len(ghRelease.Assets) > 0 {
+			slog.Warn("full release notes can't be attached when editing a release; use --force to recreate it with them", "tag", tag)
+		}
+		// Notes can be too long for the command line, so the body goes on stdin
+		body, err := json.Marshal(map[string]string{"name": ghRelease.Title, "body": ghRelease.Notes})
+		if err != nil {
+			return result, fmt.Errorf("failed to encode release %s: %w", tag, err)
+		}
+		_, err = ghAPIInput(opts.RepoPath, body, "--method", "PATCH", "repos/{owner}/{repo}/releases/"+strconv.FormatInt(existing.ID, 10))
+		if err != nil {
+			return result, fmt.Errorf("failed to update release %s: %w", tag, err)
+		}
+		return result, verifyGitHubRelease(opts.RepoPath, ghRelease)
+	}
+
+	if len(existing.Assets) > 0 {
+		slog.Warn("recreated release won't have the old release's assets", "tag", tag, "assets", len(existing.Assets))
+	}
+	if _, err := ghAPI(opts.RepoPath, "--method", "DELETE", "repos/{owner}/{repo}/releases/"+strconv.FormatInt(existing.ID, 10)); err != nil {
+		return result, fmt.Errorf("failed to delete release %s: %w", tag, err)
+	}
+	if err := executeGitHubRelease(opts.RepoPath, ghRelease); err != nil {
+		return result, fmt.Errorf("failed to recreate release %s: %w", tag, err)
+	}
+	result.Recreated = true
+	return result, verifyGitHubRelease(opts.RepoPath, ghRelease)
+}
+
+// packageForTag returns the package a release tag (component-vX.Y.Z)
+// belongs to and its version. The longest matching component wins, so
+// "api-v2-v1.0.0" is api-v2's.
+func packageForTag(cfg *config.Config, tag string) (*config.Package, *version.Version, error) {
+	var pkg *config.Package
+	var ver *version.Version
+	for _, p := range cfg.PackagesSortedByPath() {
+		prefix := buildTagName(p.Component, "")
+		if !strings.HasPrefix(tag, prefix) || (pkg != nil && len(p.Component) <= len(pkg.Component)) {
+			continue
+		}
+		suffix := strings.TrimPrefix(tag, prefix)
+		if suffix == "" {
+			// A bare prefix tag, such as "jarvis-v"
+			continue
+		}
+		v, err := version.Parse(suffix)
+		if err != nil {
+			continue
+		}
+		pkg, ver = p, v
+	}
+	if pkg == nil {
+		return nil, nil, fmt.Errorf("tag %s isn't a release tag (component-vX.Y.Z) of a configured package", tag)
+	}
+	return pkg, ver, nil
+}
+
+// previousTagVersion returns the highest version below ver that the package
+// has a release tag for among the tags reachable from ver's tag, or "" for
+// its first release.
+func previousTagVersion(repoPath string, pkg *config.Package, ver *version.Version) (string, error) {
+	prefix := buildTagName(pkg.Component, "")
+	tags, err := git.ListTagsReachableFrom(repoPath, "refs/tags/"+buildTagName(pkg.Component, ver.String()), prefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to find previous release of %s: %w", pkg.Component, err)
+	}
+
+	var prev *version.Version
+	for _, tag := range tags {
+		suffix := strings.TrimPrefix(tag, prefix)
+		if suffix == "" {
+			// A bare prefix tag, such as "jarvis-v"
+			continue
+		}
+		v, err := version.Parse(suffix)
+		if err != nil {
+			// Another component's tag sharing the prefix, or not a release tag
+			continue
+		}
+		if v.Compare(ver) < 0 && (prev == nil || v.Compare(prev) > 0) {
+			prev = v
+		}
+	}
+	if prev == nil {
+		return "", nil
+	}
+	return prev.String(), nil
+}
+
+// releaseCommits returns the package's commits between two tags, prepared
+// the way Analyze prepares them: release commits and ignored files dropped,
+// notes extracted, and scopes normalized.
+func releaseCommits(repoPath string, cfg *config.Config, pkg *config.Package, prevTag, tag string) ([]*git.Commit, error) {
+	commits, err := git.GetCommitsSinceTag(repoPath, prevTag, "refs/tags/"+tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits of %s: %w", tag, err)
+	}
+	if err := reparseCommits(cfg, commits); err != nil {
+		return nil, err
+	}
+
+	var kept []*git.Commit
+	for _, commit := range commits {
+		if cfg.IsReleaseCommit(commit.Subject) {
+			continue
+		}
+		var touches bool
+		for _, file := range commit.Files {
+			if !cfg.IsIgnoredFile(file) && cfg.FindPackageForPath(file) == pkg {
+				touches = true
+				break
+			}
+		}
+		if !touches {
+			continue
+		}
+		commit.Notes = commit.ReleaseNotes(cfg.IncludeCommitBody)
+		commit.Scope = cfg.NormalizeScope(commit.Scope)
+		kept = append(kept, commit)
+	}
+	return kept, nil
+}
+
+// fetchRelease looks up the GitHub release of a tag.
+func fetchRelease(repoPath, tag string) (*existingRelease, error) {
+	out, err := ghAPI(repoPath, "repos/{owner}/{repo}/releases/tags/"+tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the GitHub release of %s: %w", tag, err)
+	}
+	var existing existingRelease
+	if err := json.Unmarshal(out, &existing); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &existing, nil
+}
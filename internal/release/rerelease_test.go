@@ -0,0 +1,312 @@
+package release
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// setupRereleaseRepo creates a repo with api released as 1.0.0 and 1.1.0,
+// and a web commit in between that isn't api's.
+func setupRereleaseRepo(t *testing.T) (string, *config.Config) {
+	t.Helper()
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"services/api": {"component": "api"},
+			"apps/web": {"component": "web"}
+		}
+	}`)
+	writeFile(t, dir, "release-please-manifest.json", `{"services/api": "1.1.0", "apps/web": "0.1.0"}`)
+	writeFile(t, dir, "services/api/main.go", "// API\n")
+	writeFile(t, dir, "apps/web/main.go", "// Web\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	writeFile(t, dir, "services/api/main.go", "// API v1\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(api): initial api")
+	runCmd(t, dir, "git", "tag", "api-v1.0.0")
+
+	writeFile(t, dir, "services/api/main.go", "// API v1\n// Export\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(api): add bulk export")
+	writeFile(t, dir, "apps/web/main.go", "// Web\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(web): fix layout")
+	writeFile(t, dir, "services/api/main.go", "// API v1\n// Export\n// Timeout\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(api): fix timeout")
+	runCmd(t, dir, "git", "commit", "--allow-empty", "-m", "chore: release main")
+	runCmd(t, dir, "git", "tag", "api-v1.1.0")
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	return dir, cfg
+}
+
+// recordingBackend records the release it's asked to create.
+type recordingBackend struct {
+	created *GitHubRelease
+}
+
+func (b *recordingBackend) api(repoPath string, args ...string) ([]byte, error) {
+	return ghAPI(repoPath, args...)
+}
+
+func (b *recordingBackend) apiInput(repoPath string, input []byte, args ...string) ([]byte, error) {
+	return ghAPIInput(repoPath, input, args...)
+}
+
+func (b *recordingBackend) createRelease(repoPath string, ghRelease *GitHubRelease) error {
+	b.created = ghRelease
+	return nil
+}
+
+func TestPackageForTag(t *testing.T) {
+	api := &config.Package{Path: "services/api", Component: "api"}
+	apiV2 := &config.Package{Path: "services/api-v2", Component: "api-v2"}
+	cfg := &config.Config{Packages: map[string]*config.Package{api.Path: api, apiV2.Path: apiV2}}
+
+	tests := []struct {
+		tag     string
+		pkg     *config.Package
+		version string
+	}{
+		{"api-v1.2.0", api, "1.2.0"},
+		{"api-v2-v1.0.0", apiV2, "1.0.0"},
+		{"api-v2.0.0-rc.1", api, "2.0.0-rc.1"},
+	}
+	for _, tt := range tests {
+		pkg, ver, err := packageForTag(cfg, tt.tag)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.tag, err)
+			continue
+		}
+		if pkg != tt.pkg || ver.String() != tt.version {
+			t.Errorf("%s: expected %s %s, got %s %s", tt.tag, tt.pkg.Component, tt.version, pkg.Component, ver)
+		}
+	}
+
+	for _, tag := range []string{"web-v1.0.0", "api-v1", "v1.0.0", "api-v"} {
+		if _, _, err := packageForTag(cfg, tag); err == nil {
+			t.Errorf("%s: expected an error", tag)
+		}
+	}
+}
+
+func TestRerelease_DryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, cfg := setupRereleaseRepo(t)
+	var calls []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return []byte(`{"id": 7, "name": "api v1.1.0", "body": "## api v1.1.0\n\n* tpyo\n", "published_at": "2026-03-02T10:00:00Z"}`), nil
+	})
+
+	result, err := Rerelease(cfg, "api-v1.1.0", &RereleaseOptions{RepoPath: dir, RepoURL: "https://github.com/o/r", DryRun: true})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	notes := result.Release.Notes
+	for _, want := range []string{"## api v1.1.0", "add bulk export", "fix timeout", "compare/api-v1.0.0...api-v1.1.0"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("expected notes to contain %q, got:\n%s", want, notes)
+		}
+	}
+	for _, unwanted := range []string{"initial api", "fix layout", "release main"} {
+		if strings.Contains(notes, unwanted) {
+			t.Errorf("expected notes without %q, got:\n%s", unwanted, notes)
+		}
+	}
+	if !result.Changed() || result.OldNotes != "## api v1.1.0\n\n* tpyo\n" {
+		t.Errorf("expected the old notes kept for comparison, got %q", result.OldNotes)
+	}
+	if len(calls) != 1 || calls[0] != "repos/{owner}/{repo}/releases/tags/api-v1.1.0" {
+		t.Errorf("expected only the release looked up, got %v", calls)
+	}
+}
+
+func TestRerelease_Update(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	noVerifyDelay(t)
+
+	dir, cfg := setupRereleaseRepo(t)
+	cfg.PackagesSortedByPath()[1].ReleaseTitlePattern = "${component} ${version} (${date})"
+	var patched map[string]string
+	stubGHAPIInput(t, func(input []byte, args ...string) ([]byte, error) {
+		if strings.Join(args, " ") != "--method PATCH repos/{owner}/{repo}/releases/7" {
+			t.Errorf("unexpected call %v", args)
+		}
+		if err := json.Unmarshal(input, &patched); err != nil {
+			t.Errorf("expected a JSON body, got %q", input)
+		}
+		return []byte(`{}`), nil
+	})
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		if args[0] == "--method" {
+			t.Errorf("expected the release edited with a JSON body, got %v", args)
+		}
+		return []byte(`{"id": 7, "name": "api v1.1.0", "body": "old", "published_at": "2026-03-02T10:00:00Z", "html_url": "https://github.com/o/r/releases/tag/api-v1.1.0"}`), nil
+	})
+
+	result, err := Rerelease(cfg, "api-v1.1.0", &RereleaseOptions{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	if result.Recreated {
+		t.Error("expected the release edited in place")
+	}
+	if patched["name"] != "api 1.1.0 (2026-03-02)" {
+		t.Errorf("expected the title dated by the release, got %q", patched["name"])
+	}
+	if patched["body"] != result.Release.Notes || !strings.Contains(patched["body"], "fix timeout") {
+		t.Errorf("expected the regenerated notes sent, got %q", patched["body"])
+	}
+	if result.Release.URL != "https://github.com/o/r/releases/tag/api-v1.1.0" {
+		t.Errorf("expected the release verified, got URL %q", result.Release.URL)
+	}
+}
+
+func TestRerelease_Force(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	noVerifyDelay(t)
+
+	dir, cfg := setupRereleaseRepo(t)
+	var methods []string
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		if args[0] == "--method" {
+			methods = append(methods, args[1]+" "+args[2])
+		}
+		return json.Marshal(map[string]interface{}{"id": 7, "name": "api v1.1.0", "body": "old"})
+	})
+	backend := &recordingBackend{}
+	orig := activeGitHubBackend
+	activeGitHubBackend = backend
+	t.Cleanup(func() { activeGitHubBackend = orig })
+
+	result, err := Rerelease(cfg, "api-v1.1.0", &RereleaseOptions{RepoPath: dir, Force: true})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	created := backend.created
+	if !result.Recreated || created == nil {
+		t.Fatal("expected the release recreated")
+	}
+	if len(methods) != 1 || methods[0] != "DELETE repos/{owner}/{repo}/releases/7" {
+		t.Errorf("expected the old release deleted, got %v", methods)
+	}
+	head, _ := tagCommit(dir, "api-v1.1.0")
+	if created.TargetSHA != head || created.TagName != "api-v1.1.0" {
+		t.Errorf("expected the release recreated at its tag, got %s at %s", created.TagName, created.TargetSHA)
+	}
+}
+
+func TestRerelease_FirstRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, cfg := setupRereleaseRepo(t)
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return []byte(`{"id": 1, "name": "api v1.0.0", "body": ""}`), nil
+	})
+
+	result, err := Rerelease(cfg, "api-v1.0.0", &RereleaseOptions{RepoPath: dir, RepoURL: "https://github.com/o/r", DryRun: true})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	if !strings.Contains(result.Release.Notes, "initial api") || strings.Contains(result.Release.Notes, "Full Changelog") {
+		t.Errorf("expected every commit up to the tag and no compare link, got:\n%s", result.Release.Notes)
+	}
+}
+
+func TestRerelease_MissingTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, cfg := setupRereleaseRepo(t)
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		t.Errorf("unexpected GitHub call %v", args)
+		return nil, nil
+	})
+	if _, err := Rerelease(cfg, "api-v9.9.9", &RereleaseOptions{RepoPath: dir}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a missing tag error, got %v", err)
+	}
+}
+
+func TestRerelease_BarePrefixTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, cfg := setupRereleaseRepo(t)
+	runCmd(t, dir, "git", "tag", "api-v", "api-v1.0.0")
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return []byte(`{"id": 7, "name": "api v1.1.0", "body": ""}`), nil
+	})
+
+	result, err := Rerelease(cfg, "api-v1.1.0", &RereleaseOptions{RepoPath: dir, RepoURL: "https://github.com/o/r", DryRun: true})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	if !strings.Contains(result.Release.Notes, "compare/api-v1.0.0...api-v1.1.0") {
+		t.Errorf("expected the bare prefix tag skipped, got:\n%s", result.Release.Notes)
+	}
+	if _, err := Rerelease(cfg, "api-v", &RereleaseOptions{RepoPath: dir, DryRun: true}); err == nil || !strings.Contains(err.Error(), "isn't a release tag") {
+		t.Errorf("expected a bare prefix tag rejected, got %v", err)
+	}
+}
+
+func TestRerelease_OversizedNotes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	noVerifyDelay(t)
+
+	orig := maxReleaseNotes
+	maxReleaseNotes = 300
+	t.Cleanup(func() { maxReleaseNotes = orig })
+
+	dir, cfg := setupRereleaseRepo(t)
+	writeFile(t, dir, "services/api/main.go", "// API v1\n// Export\n// Timeout\n// Long\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(api): "+strings.Repeat("a long description ", 20))
+	runCmd(t, dir, "git", "tag", "api-v1.1.1")
+
+	var body string
+	stubGHAPIInput(t, func(input []byte, args ...string) ([]byte, error) {
+		var patched map[string]string
+		if err := json.Unmarshal(input, &patched); err != nil {
+			t.Fatal(err)
+		}
+		body = patched["body"]
+		return []byte(`{}`), nil
+	})
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return []byte(`{"id": 7, "name": "api v1.1.1", "body": "old", "html_url": "https://github.com/o/r/releases/tag/api-v1.1.1"}`), nil
+	})
+
+	result, err := Rerelease(cfg, "api-v1.1.1", &RereleaseOptions{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Rerelease failed: %v", err)
+	}
+	if len([]rune(body)) > maxReleaseNotes || !strings.Contains(body, "Release notes truncated") {
+		t.Errorf("expected the notes cut to fit, got %d characters:\n%s", len([]rune(body)), body)
+	}
+	if len(result.Release.Assets) != 1 {
+		t.Errorf("expected the full notes as an asset, got %v", result.Release.Assets)
+	}
+}
@@ -0,0 +1,136 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ReportSchemaVersion is the schema version stamped on ReleaseReport and
+// AnalysisInput. It only increments on breaking changes (removed or renamed
+// fields, changed types or semantics). Adding new fields does not change it.
+const ReportSchemaVersion = 1
+
+// Schema identifiers for the JSON outputs. They are used as the "$id" of the
+// generated JSON Schema and as the "$schema" value in the outputs themselves.
+const (
+	ReleaseReportSchemaURL = "https://github.com/dsswift/release-damnit/schemas/release-report/v1.json"
+	AnalysisInputSchemaURL = "https://github.com/dsswift/release-damnit/schemas/analysis-input/v1.json"
+)
+
+// SchemaNames lists the report names accepted by Schema.
+var SchemaNames = []string{"release_report", "analysis_input"}
+
+// Schema returns the JSON Schema for the named report as indented JSON.
+// The schema is derived from the Go types so it never drifts from the output.
+func Schema(name string) (string, error) {
+	var schema map[string]interface{}
+	switch name {
+	case "release_report":
+		schema = buildSchema(reflect.TypeOf(ReleaseReport{}), ReleaseReportSchemaURL, "release_report")
+	case "analysis_input":
+		schema = buildSchema(reflect.TypeOf(AnalysisInput{}), AnalysisInputSchemaURL, "analysis_input")
+	default:
+		return "", fmt.Errorf("unknown schema %q (expected one of: %s)", name, strings.Join(SchemaNames, ", "))
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return string(data), nil
+}
+
+// buildSchema builds the top-level schema document for a report type.
+func buildSchema(t reflect.Type, id, title string) map[string]interface{} {
+	schema := typeSchema(t)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = id
+	schema["title"] = title
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON Schema representation using the
+// same rules encoding/json applies when marshaling. Pointers, slices and
+// maps may be nil, which marshals as null, so they're nullable.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(typeSchema(t.Elem()))
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return nullable(map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())})
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return nullable(map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())})
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, omitEmpty := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			properties[name] = typeSchema(field.Type)
+			if !omitEmpty {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// nullable widens a schema's type to also accept null. Schemas without a
+// single type (already nullable, or unconstrained) are returned unchanged.
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	}
+	return schema
+}
+
+// jsonFieldName returns the JSON property name for a struct field and
+// whether it is tagged omitempty.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
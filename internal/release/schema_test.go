@@ -0,0 +1,84 @@
+package release
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchema_ReleaseReport(t *testing.T) {
+	out, err := Schema("release_report")
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	if schema["$id"] != ReleaseReportSchemaURL {
+		t.Errorf("expected $id %s, got %v", ReleaseReportSchemaURL, schema["$id"])
+	}
+
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected properties object")
+	}
+	for _, name := range []string{"schema_version", "releases", "components", "summary"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("expected property %s in schema", name)
+		}
+	}
+
+	required, _ := schema["required"].([]interface{})
+	hasSchemaVersion := false
+	for _, r := range required {
+		if r == "schema_version" {
+			hasSchemaVersion = true
+		}
+		if r == "$schema" {
+			t.Error("$schema is omitempty and must not be required")
+		}
+	}
+	if !hasSchemaVersion {
+		t.Error("expected schema_version to be required")
+	}
+}
+
+func TestSchema_AnalysisInput(t *testing.T) {
+	out, err := Schema("analysis_input")
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	config := props["config"].(map[string]interface{})
+	configProps := config["properties"].(map[string]interface{})
+	packages := configProps["packages"].(map[string]interface{})
+	if !reflect.DeepEqual(packages["type"], []interface{}{"object", "null"}) {
+		t.Errorf("expected packages to be a nullable object, got %v", packages["type"])
+	}
+	additional := packages["additionalProperties"].(map[string]interface{})
+	if additional["type"] != "string" {
+		t.Errorf("expected packages values to be strings, got %v", additional["type"])
+	}
+
+	commits := props["commits_analyzed"].(map[string]interface{})
+	commit := commits["items"].(map[string]interface{})
+	matched := commit["properties"].(map[string]interface{})["packages_matched"].(map[string]interface{})
+	if !reflect.DeepEqual(matched["type"], []interface{}{"array", "null"}) {
+		t.Errorf("expected packages_matched to be a nullable array, got %v", matched["type"])
+	}
+}
+
+func TestSchema_Unknown(t *testing.T) {
+	if _, err := Schema("nope"); err == nil {
+		t.Error("expected error for unknown schema name")
+	}
+}
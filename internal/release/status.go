@@ -0,0 +1,122 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/internal/yaml"
+)
+
+// ComponentStatus is a component's latest release and the versions deployed
+// to each of the config's environments.
+type ComponentStatus struct {
+	Package *config.Package
+
+	// Released is the version of the component's highest release tag
+	// reachable from HEAD, or "" if it has none.
+	Released string
+
+	// Deployments are in the config's environment order.
+	Deployments []*Deployment
+}
+
+// Deployment is what an environment's manifest says about a component.
+type Deployment struct {
+	Environment *config.Environment
+
+	// Version is the deployed version, or "" if the component isn't
+	// deployed there (no manifest, or nothing at the jsonpath).
+	Version string
+
+	// Behind is true if Version is older than the latest release.
+	Behind bool
+
+	// Err is why the manifest couldn't be read, such as a value that isn't
+	// a version.
+	Err error
+}
+
+// Deployed reports whether the component is deployed to any environment.
+func (s *ComponentStatus) Deployed() bool {
+	for _, d := range s.Deployments {
+		if d.Version != "" || d.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Undeployed returns the deployments the latest release hasn't reached.
+func (s *ComponentStatus) Undeployed() []*Deployment {
+	var behind []*Deployment
+	for _, d := range s.Deployments {
+		if d.Behind {
+			behind = append(behind, d)
+		}
+	}
+	return behind
+}
+
+// Status cross-references each package's latest release tag with the
+// versions recorded in the config's environment manifests, for seeing what's
+// released but not yet deployed.
+func Status(repoPath string, cfg *config.Config) ([]*ComponentStatus, error) {
+	if len(cfg.Environments) == 0 {
+		return nil, fmt.Errorf("no environments configured; add an environments section naming each environment's manifest")
+	}
+
+	var statuses []*ComponentStatus
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		latest, err := latestTagVersion(repoPath, pkg)
+		if err != nil {
+			return nil, err
+		}
+		status := &ComponentStatus{Package: pkg}
+		if latest != nil {
+			status.Released = latest.String()
+		}
+		for _, env := range cfg.Environments {
+			d := readDeployment(repoPath, env, pkg.Component)
+			if d.Version != "" && latest != nil {
+				if v, err := version.Parse(d.Version); err != nil {
+					d.Err = fmt.Errorf("deployed version %q is not a version: %w", d.Version, err)
+				} else {
+					d.Behind = v.Compare(latest) < 0
+				}
+			}
+			status.Deployments = append(status.Deployments, d)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// readDeployment reads a component's version from an environment's
+// manifest. An image reference's repository and a "v" prefix are dropped.
+func readDeployment(repoPath string, env *config.Environment, component string) *Deployment {
+	d := &Deployment{Environment: env}
+	manifest := env.ManifestPath(component)
+	content, err := os.ReadFile(filepath.Join(repoPath, manifest))
+	if errors.Is(err, os.ErrNotExist) {
+		return d
+	}
+	if err != nil {
+		d.Err = fmt.Errorf("failed to read %s: %w", manifest, err)
+		return d
+	}
+
+	value, err := yaml.LookupScalar(string(content), env.VersionPath(component))
+	if errors.Is(err, yaml.ErrNotFound) {
+		return d
+	}
+	if err != nil {
+		d.Err = fmt.Errorf("%s: %w", manifest, err)
+		return d
+	}
+	_, d.Version, _ = splitVersionValue(value)
+	return d
+}
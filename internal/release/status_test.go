@@ -0,0 +1,89 @@
+package release
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestSplitVersionValue(t *testing.T) {
+	tests := []struct {
+		value, prefix, ver, digest string
+	}{
+		{"1.2.3", "", "1.2.3", ""},
+		{"v1.2.3", "v", "1.2.3", ""},
+		{"ghcr.io/acme/api:v1.2.3", "ghcr.io/acme/api:v", "1.2.3", ""},
+		{"localhost:5000/api:1.2.3", "localhost:5000/api:", "1.2.3", ""},
+		{"localhost:5000/api", "", "localhost:5000/api", ""},
+		{"vendor", "", "vendor", ""},
+		{"foo:1.0.0@sha256:abc123", "foo:", "1.0.0", "@sha256:abc123"},
+	}
+	for _, tt := range tests {
+		prefix, ver, digest := splitVersionValue(tt.value)
+		if prefix != tt.prefix || ver != tt.ver || digest != tt.digest {
+			t.Errorf("%s: expected %q %q %q, got %q %q %q", tt.value, tt.prefix, tt.ver, tt.digest, prefix, ver, digest)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupTwoPackageRepo(t)
+	writeFile(t, dir, "deploy/staging/values.yaml", "service-a:\n  image: ghcr.io/acme/service-a:v0.2.0\nservice-b:\n  image: ghcr.io/acme/service-b:0.1.0\n")
+	writeFile(t, dir, "deploy/prod/values.yaml", "service-a:\n  image: ghcr.io/acme/service-a:v0.1.0\nservice-b:\n  image: ghcr.io/acme/service-b:latest\n")
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-b": {"component": "service-b"},
+			"libs/shared": {"component": "shared"}
+		},
+		"allow-missing-versions": true,
+		"environments": [
+			{"name": "staging", "path": "deploy/staging/values.yaml", "jsonpath": "$.${component}.image"},
+			{"name": "prod", "path": "deploy/prod/values.yaml", "jsonpath": "$.${component}.image"}
+		]
+	}`)
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: add deployments")
+	runCmd(t, dir, "git", "tag", "service-a-v0.2.0")
+	runCmd(t, dir, "git", "tag", "service-b-v0.1.0")
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("config.Load failed: %v", err)
+	}
+	statuses, err := Status(dir, cfg)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("expected a status per package, got %d", len(statuses))
+	}
+
+	shared, a, b := statuses[0], statuses[1], statuses[2]
+	if shared.Deployed() || shared.Released != "" {
+		t.Errorf("expected the untagged library deployed nowhere, got %+v", shared)
+	}
+	if a.Released != "0.2.0" || a.Deployments[0].Version != "0.2.0" || a.Deployments[1].Version != "0.1.0" {
+		t.Fatalf("unexpected service-a status %+v", a.Deployments)
+	}
+	if undeployed := a.Undeployed(); len(undeployed) != 1 || undeployed[0].Environment.Name != "prod" {
+		t.Errorf("expected service-a behind in prod only, got %+v", undeployed)
+	}
+	if err := b.Deployments[1].Err; err == nil || !strings.Contains(err.Error(), `"latest" is not a version`) {
+		t.Errorf("expected an error for service-b's latest tag in prod, got %v", err)
+	}
+	if len(b.Undeployed()) != 0 {
+		t.Errorf("expected service-b up to date, got %+v", b.Undeployed())
+	}
+}
+
+func TestStatus_NoEnvironments(t *testing.T) {
+	if _, err := Status(t.TempDir(), &config.Config{}); err == nil {
+		t.Error("expected an error without environments")
+	}
+}
@@ -0,0 +1,83 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+// maxSubmoduleSubjects caps the submodule commit subjects listed under one
+// update; the rest are counted.
+const maxSubmoduleSubjects = 10
+
+// addSubmoduleNotes adds a note to each commit that moves a submodule's
+// pinned commit, saying what moved, so a commit updating only the pointer
+// doesn't read as an empty entry. With submodule-log set, the subjects of
+// the submodule's new commits follow when it's checked out. The commit's
+// files already attribute it to the package holding the submodule.
+func addSubmoduleNotes(repoPath string, cfg *config.Config, commits []*git.Commit) {
+	paths, err := git.SubmodulePaths(repoPath)
+	if err != nil || len(paths) == 0 {
+		return
+	}
+	submodules := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		submodules[path] = true
+	}
+
+	for _, commit := range commits {
+		touched := false
+		for _, file := range commit.Files {
+			if submodules[file] {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			continue
+		}
+		updates, err := git.SubmoduleUpdates(repoPath, commit.SHA)
+		if err != nil {
+			slog.Warn("failed to list submodule updates", "commit", commit.ShortSHA, "error", err)
+			continue
+		}
+		for _, update := range updates {
+			commit.Notes = append(commit.Notes, submoduleNote(update))
+			if cfg.SubmoduleLog && update.Old != "" && update.New != "" {
+				subjects, err := git.SubmoduleLog(repoPath, update)
+				if err != nil {
+					slog.Debug("not listing submodule commits", "commit", commit.ShortSHA, "error", err)
+					continue
+				}
+				commit.Notes = append(commit.Notes, submoduleSubjects(update.Path, subjects)...)
+			}
+		}
+	}
+}
+
+// submoduleNote describes a submodule update.
+func submoduleNote(update git.SubmoduleUpdate) string {
+	switch {
+	case update.Old == "":
+		return fmt.Sprintf("Adds submodule %s at %s", update.Path, shortSHA(update.New))
+	case update.New == "":
+		return fmt.Sprintf("Removes submodule %s", update.Path)
+	}
+	return fmt.Sprintf("Updates submodule %s from %s to %s", update.Path, shortSHA(update.Old), shortSHA(update.New))
+}
+
+// submoduleSubjects returns notes for the commits a submodule update brings
+// in, up to maxSubmoduleSubjects.
+func submoduleSubjects(path string, subjects []string) []string {
+	var notes []string
+	for i, subject := range subjects {
+		if i == maxSubmoduleSubjects {
+			notes = append(notes, fmt.Sprintf("%s: and %d more", path, len(subjects)-i))
+			break
+		}
+		notes = append(notes, fmt.Sprintf("%s: %s", path, subject))
+	}
+	return notes
+}
@@ -0,0 +1,68 @@
+package release
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze_SubmoduleUpdate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	for _, log := range []bool{false, true} {
+		t.Run(map[bool]string{false: "pointer", true: "log"}[log], func(t *testing.T) {
+			dir := setupBasicRepo(t)
+			if log {
+				writeFile(t, dir, "release-please-config.json", `{
+					"submodule-log": true,
+					"packages": {"workloads/service-a": {"component": "service-a"}}
+				}`)
+			}
+			lib := createTestRepo(t)
+			writeFile(t, lib, "lib.go", "// v1\n")
+			runCmd(t, lib, "git", "add", "-A")
+			runCmd(t, lib, "git", "commit", "-m", "feat: first")
+			runCmd(t, dir, "git", "-c", "protocol.file.allow=always", "submodule", "add", lib, "workloads/service-a/lib")
+			runCmd(t, dir, "git", "commit", "-m", "chore: add lib")
+			old := gitOutput(t, dir, "rev-parse", "--short", "HEAD:workloads/service-a/lib")
+
+			writeFile(t, lib, "lib.go", "// v2\n")
+			runCmd(t, lib, "git", "commit", "-am", "fix: handle empty input")
+			runCmd(t, filepath.Join(dir, "workloads/service-a/lib"), "git", "pull", "-q", "origin", "main")
+			runCmd(t, dir, "git", "commit", "-am", "fix(service-a): update lib")
+			newSHA := gitOutput(t, dir, "rev-parse", "--short", "HEAD:workloads/service-a/lib")
+
+			result, err := Analyze(&Options{RepoPath: dir, DryRun: true, TreatPreMajorAsMinor: true})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(result.Releases) != 1 || result.Releases[0].Package.Component != "service-a" {
+				t.Fatalf("expected a service-a release, got %+v", result.Releases)
+			}
+			want := []string{"Updates submodule workloads/service-a/lib from " + old + " to " + newSHA}
+			if log {
+				want = append(want, "workloads/service-a/lib: fix: handle empty input")
+			}
+			if notes := result.Releases[0].Commits[0].Notes; !slices.Equal(notes, want) {
+				t.Errorf("expected notes %q, got %q", want, notes)
+			}
+			if entry := RenderChangelog(result, result.Releases[0]); !strings.Contains(entry, "  * "+want[0]) {
+				t.Errorf("expected the update under the changelog entry, got:\n%s", entry)
+			}
+		})
+	}
+}
+
+func TestSubmoduleSubjects(t *testing.T) {
+	subjects := make([]string, maxSubmoduleSubjects+3)
+	for i := range subjects {
+		subjects[i] = "fix: bug"
+	}
+	notes := submoduleSubjects("lib", subjects)
+	if len(notes) != maxSubmoduleSubjects+1 || notes[0] != "lib: fix: bug" || notes[maxSubmoduleSubjects] != "lib: and 3 more" {
+		t.Errorf("unexpected notes %q", notes)
+	}
+}
@@ -0,0 +1,190 @@
+package release
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// resolveTagVersions sets each package's current version from the highest
+// release tag (component-vX.Y.Z) reachable from HEAD, for configs with
+// version-source: tags. Packages without a tag start at 0.0.0.
+func resolveTagVersions(repoPath string, cfg *config.Config) error {
+	for _, pkg := range cfg.PackagesSortedByPath() {
+		latest, err := latestTagVersion(repoPath, pkg)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			pkg.CurrentVersion = "0.0.0"
+			slog.Debug("no release tag found, starting at 0.0.0", "component", pkg.Component)
+			continue
+		}
+		pkg.CurrentVersion = latest.String()
+		slog.Debug("version from release tag", "component", pkg.Component, "version", pkg.CurrentVersion)
+	}
+	return nil
+}
+
+// latestTagVersion returns the version of the package's highest release tag
+// reachable from HEAD, or nil if it has none.
+func latestTagVersion(repoPath string, pkg *config.Package) (*version.Version, error) {
+	prefix := buildTagName(pkg.Component, "")
+	tags, err := git.ListMergedTags(repoPath, prefix+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find version of %s: %w", pkg.Component, err)
+	}
+
+	var latest *version.Version
+	for _, tag := range tags {
+		ver := strings.TrimPrefix(tag, prefix)
+		if ver == "" {
+			// A bare prefix tag, such as "jarvis-v"
+			continue
+		}
+		v, err := version.Parse(ver)
+		if err != nil {
+			// Another component's tag sharing the prefix, or not a release tag
+			continue
+		}
+		if latest == nil || v.Compare(latest) > 0 {
+			latest = v
+		}
+	}
+	return latest, nil
+}
+
+// lastReleaseTag returns the tag of the package's current version
+// (component-vX.Y.Z), or "" if it hasn't been tagged.
+func lastReleaseTag(repoPath string, pkg *config.Package) (string, error) {
+	tag := buildTagName(pkg.Component, pkg.CurrentVersion)
+	exists, err := git.TagExists(repoPath, tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to find release tag of %s: %w", pkg.Component, err)
+	}
+	if !exists {
+		return "", nil
+	}
+	return tag, nil
+}
+
+// TagCollision is a release whose tag already existed at another commit,
+// and what the config's tag-collision policy did about it.
+type TagCollision struct {
+	Package *config.Package
+
+	// Tag is the existing tag and Commit the commit it points at.
+	Tag    string
+	Commit string
+
+	// NewTag is the tag released instead with TagCollisionBumpAgain, or ""
+	// if the release was skipped.
+	NewTag string
+}
+
+// resolveTagCollisions applies the config's tag-collision policy to releases
+// whose tag already exists at a commit other than head, which gh would
+// otherwise refuse to create. A tag at head is left alone: it's this
+// commit's own release. Returns the releases left and the collisions.
+func resolveTagCollisions(repoPath string, cfg *config.Config, head string, releases []*PackageRelease) ([]*PackageRelease, []*TagCollision, error) {
+	var kept []*PackageRelease
+	var collisions []*TagCollision
+	for _, rel := range releases {
+		tag := buildTagName(rel.Package.Component, rel.NewVersion)
+		commit, err := tagCommit(repoPath, tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if commit == "" || commit == head {
+			kept = append(kept, rel)
+			continue
+		}
+
+		collision := &TagCollision{Package: rel.Package, Tag: tag, Commit: commit}
+		switch cfg.TagCollision {
+		case config.TagCollisionSkip:
+			slog.Warn("release tag already exists, skipping release", "component", rel.Package.Component, "tag", tag, "commit", shortSHA(commit))
+			collisions = append(collisions, collision)
+			continue
+		case config.TagCollisionBumpAgain:
+			// A linked group moves together, keeping its shared version
+			group := linkedReleases(releases, rel)
+			next, err := nextFreeVersion(repoPath, group, rel.NewVersion, head)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, member := range group {
+				member.NewVersion = next
+			}
+			collision.NewTag = buildTagName(rel.Package.Component, rel.NewVersion)
+			slog.Warn("release tag already exists, bumping again", "component", rel.Package.Component, "tag", tag, "commit", shortSHA(commit), "new_tag", collision.NewTag)
+			collisions = append(collisions, collision)
+			kept = append(kept, rel)
+		default:
+			return nil, nil, fmt.Errorf("tag %s already exists at %s, not HEAD (%s); bump the version in the manifest past it, or set tag-collision to %s or %s",
+				tag, shortSHA(commit), shortSHA(head), config.TagCollisionSkip, config.TagCollisionBumpAgain)
+		}
+	}
+	return kept, collisions, nil
+}
+
+// linkedReleases returns rel and the other releases in its linked-versions
+// group, or just rel if it isn't in one.
+func linkedReleases(releases []*PackageRelease, rel *PackageRelease) []*PackageRelease {
+	group := []*PackageRelease{rel}
+	if rel.Package.LinkedGroup == "" {
+		return group
+	}
+	for _, other := range releases {
+		if other != rel && other.Package.LinkedGroup == rel.Package.LinkedGroup {
+			group = append(group, other)
+		}
+	}
+	return group
+}
+
+// nextFreeVersion returns the first patch version after from whose tag
+// doesn't exist or already points at head, for every release in group.
+func nextFreeVersion(repoPath string, group []*PackageRelease, from, head string) (string, error) {
+	v, err := version.Parse(from)
+	if err != nil {
+		return "", fmt.Errorf("invalid version for %s: %w", group[0].Package.Component, err)
+	}
+	for {
+		v = v.Bump(version.Patch, false)
+		free := true
+		for _, rel := range group {
+			commit, err := tagCommit(repoPath, buildTagName(rel.Package.Component, v.String()))
+			if err != nil {
+				return "", err
+			}
+			if commit != "" && commit != head {
+				free = false
+				break
+			}
+		}
+		if free {
+			return v.String(), nil
+		}
+	}
+}
+
+// tagCommit returns the commit a tag points at, or "" if it doesn't exist.
+func tagCommit(repoPath, tag string) (string, error) {
+	exists, err := git.TagExists(repoPath, tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up tag %s: %w", tag, err)
+	}
+	if !exists {
+		return "", nil
+	}
+	commit, err := git.ResolveCommit(repoPath, "refs/tags/"+tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tag %s: %w", tag, err)
+	}
+	return commit, nil
+}
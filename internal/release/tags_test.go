@@ -0,0 +1,193 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze_VersionsFromTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := createTestRepo(t)
+	writeFile(t, dir, "release-please-config.json", `{
+		"packages": {
+			"workloads/service-a": {"component": "service-a"},
+			"workloads/service-ab": {"component": "service-ab"},
+			"workloads/service-b": {"component": "service-b"}
+		},
+		"version-source": "tags"
+	}`)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n")
+	writeFile(t, dir, "workloads/service-ab/src/main.go", "// Initial\n")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+	runCmd(t, dir, "git", "tag", "service-a-v1.2.0")
+	runCmd(t, dir, "git", "tag", "service-a-v1.10.0")
+	runCmd(t, dir, "git", "tag", "service-ab-v5.0.0")
+	runCmd(t, dir, "git", "tag", "service-b-v") // bare prefix, not a version
+
+	// A later tag on an unmerged branch doesn't count
+	runCmd(t, dir, "git", "checkout", "-b", "experiment")
+	writeFile(t, dir, "workloads/service-a/src/exp.go", "// Experiment\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "feat(service-a): experiment")
+	runCmd(t, dir, "git", "tag", "service-a-v2.0.0")
+	runCmd(t, dir, "git", "checkout", "main")
+
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	writeFile(t, dir, "workloads/service-b/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix: fix both services")
+
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	versions := make(map[string]string)
+	for _, rel := range result.Releases {
+		versions[rel.Package.Component] = rel.OldVersion + " -> " + rel.NewVersion
+	}
+	// Highest reachable tag wins (1.10.0 over 1.2.0); untagged packages start at 0.0.0
+	if versions["service-a"] != "1.10.0 -> 1.10.1" {
+		t.Errorf("service-a: expected 1.10.0 -> 1.10.1, got %q", versions["service-a"])
+	}
+	if versions["service-b"] != "0.0.0 -> 0.0.1" {
+		t.Errorf("service-b: expected 0.0.0 -> 0.0.1, got %q", versions["service-b"])
+	}
+
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "release-please-manifest.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no manifest to be written, got err=%v", err)
+	}
+}
+
+func TestAnalyze_TagCollision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	// setup tags service-a-v0.1.1 and v0.1.2 on a branch, then fixes
+	// service-a on main, whose next version is 0.1.1 again
+	setup := func(t *testing.T, policy string) string {
+		t.Helper()
+		dir := setupBasicRepo(t)
+		if policy != "" {
+			writeFile(t, dir, "release-please-config.json", `{
+				"packages": {"workloads/service-a": {"component": "service-a"}},
+				"tag-collision": "`+policy+`"
+			}`)
+			runCmd(t, dir, "git", "add", "-A")
+			runCmd(t, dir, "git", "commit", "-m", "chore: set tag-collision")
+		}
+		runCmd(t, dir, "git", "checkout", "-b", "hotfix")
+		writeFile(t, dir, "workloads/service-a/src/hotfix.go", "// Hotfix\n")
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "fix(service-a): hotfix")
+		runCmd(t, dir, "git", "tag", "service-a-v0.1.1")
+		runCmd(t, dir, "git", "tag", "service-a-v0.1.2")
+		runCmd(t, dir, "git", "checkout", "main")
+		writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+		return dir
+	}
+
+	t.Run("error", func(t *testing.T) {
+		dir := setup(t, "")
+		_, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+		if err == nil || !strings.Contains(err.Error(), "tag service-a-v0.1.1 already exists") {
+			t.Fatalf("expected a tag collision error, got %v", err)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		dir := setup(t, "skip")
+		result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(result.Releases) != 0 {
+			t.Errorf("expected the release skipped, got %+v", result.Releases)
+		}
+		collisions := result.Stats.TagCollisions
+		if len(collisions) != 1 || collisions[0].Tag != "service-a-v0.1.1" || collisions[0].NewTag != "" {
+			t.Errorf("expected the collision recorded, got %+v", collisions)
+		}
+	})
+
+	t.Run("bump-again", func(t *testing.T) {
+		dir := setup(t, "bump-again")
+		result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.3" {
+			t.Fatalf("expected service-a bumped to the free 0.1.3, got %+v", result.Releases)
+		}
+		collisions := result.Stats.TagCollisions
+		if len(collisions) != 1 || collisions[0].NewTag != "service-a-v0.1.3" {
+			t.Errorf("expected the collision recorded, got %+v", collisions)
+		}
+	})
+
+	t.Run("bump-again linked", func(t *testing.T) {
+		dir := createTestRepo(t)
+		writeFile(t, dir, "release-please-config.json", `{
+			"packages": {
+				"workloads/service-a": {"component": "service-a"},
+				"workloads/service-b": {"component": "service-b"}
+			},
+			"plugins": [
+				{"type": "linked-versions", "groupName": "services", "components": ["service-a", "service-b"]}
+			],
+			"tag-collision": "bump-again"
+		}`)
+		writeFile(t, dir, "release-please-manifest.json", `{"workloads/service-a": "1.0.0", "workloads/service-b": "1.0.0"}`)
+		writeFile(t, dir, "workloads/service-a/src/main.go", "// A\n")
+		writeFile(t, dir, "workloads/service-b/src/main.go", "// B\n")
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "chore: initial commit")
+		// Only service-b's next two versions are taken
+		runCmd(t, dir, "git", "tag", "service-b-v1.0.1")
+		runCmd(t, dir, "git", "tag", "service-b-v1.0.2")
+		writeFile(t, dir, "workloads/service-a/src/main.go", "// A\n// Fix\n")
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+		result, err := Analyze(&Options{RepoPath: dir})
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		versions := make(map[string]string)
+		for _, rel := range result.Releases {
+			versions[rel.Package.Component] = rel.NewVersion
+		}
+		if versions["service-a"] != "1.0.3" || versions["service-b"] != "1.0.3" {
+			t.Errorf("expected the group bumped together to 1.0.3, got %v", versions)
+		}
+	})
+
+	t.Run("tag at HEAD", func(t *testing.T) {
+		dir := setupBasicRepo(t)
+		writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+		runCmd(t, dir, "git", "add", "-A")
+		runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+		runCmd(t, dir, "git", "tag", "service-a-v0.1.1")
+
+		result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+		if err != nil {
+			t.Fatalf("expected HEAD's own tag not to collide, got %v", err)
+		}
+		if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.1" {
+			t.Errorf("expected service-a 0.1.1, got %+v", result.Releases)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"math"
+	"time"
+)
+
+// Timed phases of a run, in the order they happen.
+const (
+	PhaseConfigLoad      = "config_load"
+	PhaseGitTraversal    = "git_traversal"
+	PhaseFileListing     = "file_listing"
+	PhaseAnalysis        = "analysis"
+	PhaseApply           = "apply"
+	PhaseReleaseCreation = "release_creation"
+)
+
+// Timings records how long each phase of a run took. Methods on a nil
+// Timings do nothing, so callers can time phases whether or not timing is on.
+type Timings struct {
+	// Phases are the timed phases in the order they were first recorded.
+	Phases []PhaseTiming
+}
+
+// PhaseTiming is the time spent in one phase.
+type PhaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Add adds d to the named phase, recording the phase if it's new.
+func (t *Timings) Add(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	for i := range t.Phases {
+		if t.Phases[i].Name == name {
+			t.Phases[i].Duration += d
+			return
+		}
+	}
+	t.Phases = append(t.Phases, PhaseTiming{Name: name, Duration: d})
+}
+
+// Since adds the time since start to the named phase.
+func (t *Timings) Since(name string, start time.Time) {
+	t.Add(name, time.Since(start))
+}
+
+// Total returns the time spent across all phases.
+func (t *Timings) Total() time.Duration {
+	if t == nil {
+		return 0
+	}
+	var total time.Duration
+	for _, p := range t.Phases {
+		total += p.Duration
+	}
+	return total
+}
+
+// Metrics are the run metrics in the release report (with Options.Timings).
+type Metrics struct {
+	// Commits is the number of commits analyzed.
+	Commits int `json:"commits"`
+
+	// Files is the number of changed files across the analyzed commits.
+	Files int `json:"files"`
+
+	// Phases are the timed phases in the order they ran.
+	Phases []PhaseMetric `json:"phases"`
+
+	// TotalMS is the time spent across all phases, in milliseconds.
+	TotalMS float64 `json:"total_ms"`
+}
+
+// PhaseMetric is the time spent in one phase.
+type PhaseMetric struct {
+	// Name is the phase (config_load, git_traversal, file_listing,
+	// analysis, apply, or release_creation).
+	Name string `json:"name"`
+
+	// DurationMS is the time spent in the phase, in milliseconds.
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// BuildMetrics returns the metrics for a result, or nil if it wasn't timed.
+func BuildMetrics(result *AnalysisResult) *Metrics {
+	if result.Timings == nil {
+		return nil
+	}
+
+	metrics := &Metrics{
+		Commits: len(result.Commits),
+		Phases:  []PhaseMetric{},
+		TotalMS: milliseconds(result.Timings.Total()),
+	}
+	for _, c := range result.Commits {
+		metrics.Files += len(c.Files)
+	}
+	for _, p := range result.Timings.Phases {
+		metrics.Phases = append(metrics.Phases, PhaseMetric{Name: p.Name, DurationMS: milliseconds(p.Duration)})
+	}
+	return metrics
+}
+
+// milliseconds converts d to milliseconds, rounded to the microsecond.
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/git"
+)
+
+func TestTimings(t *testing.T) {
+	timings := &Timings{}
+	timings.Add(PhaseConfigLoad, 2*time.Millisecond)
+	timings.Add(PhaseAnalysis, 3*time.Millisecond)
+	timings.Add(PhaseConfigLoad, time.Millisecond)
+
+	if len(timings.Phases) != 2 || timings.Phases[0].Name != PhaseConfigLoad || timings.Phases[0].Duration != 3*time.Millisecond {
+		t.Errorf("expected repeated phases to add up in first-seen order, got %+v", timings.Phases)
+	}
+	if total := timings.Total(); total != 6*time.Millisecond {
+		t.Errorf("expected total 6ms, got %v", total)
+	}
+
+	// A nil Timings ignores everything
+	var off *Timings
+	off.Add(PhaseApply, time.Second)
+	off.Since(PhaseApply, time.Now())
+	if off.Total() != 0 {
+		t.Error("expected nil Timings to record nothing")
+	}
+}
+
+func TestBuildMetrics(t *testing.T) {
+	result := &AnalysisResult{
+		Commits: []*git.Commit{
+			{SHA: "aaa1111", Files: []string{"a.go", "b.go"}},
+			{SHA: "bbb2222", Files: []string{"c.go"}},
+		},
+	}
+	if BuildMetrics(result) != nil {
+		t.Error("expected no metrics without timings")
+	}
+
+	result.Timings = &Timings{}
+	result.Timings.Add(PhaseGitTraversal, 1500*time.Microsecond)
+	result.Timings.Add(PhaseFileListing, 2*time.Millisecond+345678*time.Nanosecond)
+
+	metrics := BuildMetrics(result)
+	if metrics.Commits != 2 || metrics.Files != 3 {
+		t.Errorf("expected 2 commits and 3 files, got %d and %d", metrics.Commits, metrics.Files)
+	}
+	if len(metrics.Phases) != 2 || metrics.Phases[0].DurationMS != 1.5 || metrics.Phases[1].DurationMS != 2.346 {
+		t.Errorf("unexpected phases: %+v", metrics.Phases)
+	}
+	if metrics.TotalMS != 3.846 {
+		t.Errorf("expected total 3.846ms, got %v", metrics.TotalMS)
+	}
+	if report := BuildReleaseReport(result, ""); report.Metrics == nil || report.Metrics.Files != 3 {
+		t.Errorf("expected metrics in the release report, got %+v", report.Metrics)
+	}
+}
+
+func TestAnalyze_Timings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Timings != nil {
+		t.Error("expected no timings unless requested")
+	}
+
+	result, err = Analyze(&Options{RepoPath: dir, DryRun: true, Timings: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	want := []string{PhaseConfigLoad, PhaseGitTraversal, PhaseFileListing, PhaseAnalysis}
+	if len(result.Timings.Phases) != len(want) {
+		t.Fatalf("expected phases %v, got %+v", want, result.Timings.Phases)
+	}
+	for i, name := range want {
+		if p := result.Timings.Phases[i]; p.Name != name || p.Duration <= 0 {
+			t.Errorf("expected phase %d to be a timed %s, got %+v", i, name, p)
+		}
+	}
+}
+
+func TestAnalyze_TimingsWithTelemetry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, ".release-damnit.yaml", "telemetry:\n  otlp:\n    endpoint: http://collector:4318\n")
+
+	result, err := Analyze(&Options{RepoPath: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if result.Timings == nil || len(result.Timings.Phases) == 0 {
+		t.Error("expected telemetry to turn on timings")
+	}
+}
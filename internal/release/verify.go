@@ -0,0 +1,58 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// verifyAttempts and verifyDelay bound the wait for a new release to show up
+// in the API, which can lag behind its creation. They're variables so tests
+// don't have to wait.
+var (
+	verifyAttempts = 5
+	verifyDelay    = 2 * time.Second
+)
+
+// verifyGitHubRelease checks that a created release can be fetched by its
+// tag and is published, retrying with a growing delay. On success the
+// release's URL is set.
+func verifyGitHubRelease(repoPath string, ghRelease *GitHubRelease) error {
+	var lastErr error
+	for attempt := 1; attempt <= verifyAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * verifyDelay)
+		}
+
+		out, err := ghAPI(repoPath, "repos/{owner}/{repo}/releases/tags/"+ghRelease.TagName)
+		if err != nil {
+			lastErr = err
+			slog.Debug("release not visible yet", "tag", ghRelease.TagName, "attempt", attempt, "error", err)
+			continue
+		}
+
+		var found struct {
+			HTMLURL       string `json:"html_url"`
+			Draft         bool   `json:"draft"`
+			DiscussionURL string `json:"discussion_url"`
+		}
+		if err := json.Unmarshal(out, &found); err != nil {
+			lastErr = fmt.Errorf("failed to parse release: %w", err)
+			continue
+		}
+		if found.Draft {
+			lastErr = fmt.Errorf("release is a draft")
+			continue
+		}
+
+		ghRelease.URL = found.HTMLURL
+		ghRelease.DiscussionURL = found.DiscussionURL
+		if ghRelease.DiscussionCategory != "" && found.DiscussionURL == "" {
+			slog.Warn("release has no discussion; check that Discussions are enabled and the category exists", "tag", ghRelease.TagName, "category", ghRelease.DiscussionCategory)
+		}
+		slog.Debug("verified release", "tag", ghRelease.TagName, "url", found.HTMLURL)
+		return nil
+	}
+	return fmt.Errorf("release %s not found after %d attempts: %w", ghRelease.TagName, verifyAttempts, lastErr)
+}
@@ -0,0 +1,88 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// noVerifyDelay makes release verification retry immediately.
+func noVerifyDelay(t *testing.T) {
+	t.Helper()
+	orig := verifyDelay
+	verifyDelay = 0
+	t.Cleanup(func() { verifyDelay = orig })
+}
+
+func TestVerifyGitHubRelease_Retries(t *testing.T) {
+	noVerifyDelay(t)
+
+	attempts := 0
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		attempts++
+		if args[0] != "repos/{owner}/{repo}/releases/tags/jarvis-v0.2.0" {
+			t.Errorf("unexpected endpoint %q", args[0])
+		}
+		if attempts < 3 {
+			return nil, fmt.Errorf("HTTP 404: Not Found")
+		}
+		return []byte(`{"html_url": "https://github.com/o/r/releases/tag/jarvis-v0.2.0", "draft": false}`), nil
+	})
+
+	ghRelease := &GitHubRelease{TagName: "jarvis-v0.2.0"}
+	if err := verifyGitHubRelease("", ghRelease); err != nil {
+		t.Fatalf("verifyGitHubRelease failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if ghRelease.URL != "https://github.com/o/r/releases/tag/jarvis-v0.2.0" {
+		t.Errorf("unexpected URL %q", ghRelease.URL)
+	}
+}
+
+func TestVerifyGitHubRelease_Fails(t *testing.T) {
+	noVerifyDelay(t)
+
+	tests := []struct {
+		name    string
+		respond func() ([]byte, error)
+		want    string
+	}{
+		{"never visible", func() ([]byte, error) { return nil, fmt.Errorf("HTTP 404: Not Found") }, "404"},
+		{"draft", func() ([]byte, error) { return []byte(`{"draft": true}`), nil }, "draft"},
+		{"bad response", func() ([]byte, error) { return []byte(`not json`), nil }, "failed to parse"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			stubGHAPI(t, func(args ...string) ([]byte, error) {
+				attempts++
+				return tc.respond()
+			})
+
+			err := verifyGitHubRelease("", &GitHubRelease{TagName: "jarvis-v0.2.0"})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+			if attempts != verifyAttempts {
+				t.Errorf("expected %d attempts, got %d", verifyAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestVerifyGitHubRelease_Discussion(t *testing.T) {
+	stubGHAPI(t, func(args ...string) ([]byte, error) {
+		return []byte(`{"html_url": "https://github.com/o/r/releases/tag/jarvis-v0.2.0", "discussion_url": "https://github.com/o/r/discussions/42"}`), nil
+	})
+
+	ghRelease := &GitHubRelease{TagName: "jarvis-v0.2.0", DiscussionCategory: "Announcements"}
+	if err := verifyGitHubRelease("", ghRelease); err != nil {
+		t.Fatalf("verifyGitHubRelease failed: %v", err)
+	}
+	if ghRelease.DiscussionURL != "https://github.com/o/r/discussions/42" {
+		t.Errorf("unexpected discussion URL %q", ghRelease.DiscussionURL)
+	}
+}
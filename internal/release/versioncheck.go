@@ -0,0 +1,80 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/version"
+)
+
+// checkVersions guards Apply and ApplyAndCommit against writing a version
+// that isn't an upgrade: each release's new version must be newer than its
+// old one, its manifest entry must still hold the version the analysis
+// read, and its version file must not be ahead of that. If another run
+// bumped them in the meantime, releasing again would overwrite its versions.
+func checkVersions(result *AnalysisResult) error {
+	repoRoot := result.Config.RepoRoot
+
+	var manifest map[string]string
+	if result.Config.VersionSource != config.VersionSourceTags {
+		manifestPath := result.Config.ManifestPath
+		if manifestPath == "" {
+			manifestPath = config.ManifestFile
+		}
+		content, found, err := readOptional(filepath.Join(repoRoot, manifestPath))
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if found {
+			if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+				return fmt.Errorf("failed to parse manifest %s: %w", manifestPath, err)
+			}
+		}
+	}
+
+	for _, rel := range result.Releases {
+		oldVersion, err := version.Parse(rel.OldVersion)
+		if err != nil {
+			return fmt.Errorf("invalid old version for %s: %w", rel.Package.Component, err)
+		}
+		newVersion, err := version.Parse(rel.NewVersion)
+		if err != nil {
+			return fmt.Errorf("invalid new version for %s: %w", rel.Package.Component, err)
+		}
+		if newVersion.Compare(oldVersion) <= 0 {
+			return fmt.Errorf("refusing to release %s %s: it isn't newer than %s", rel.Package.Component, rel.NewVersion, rel.OldVersion)
+		}
+
+		if onDisk, ok := manifest[rel.Package.Path]; ok && onDisk != rel.Package.CurrentVersion {
+			return fmt.Errorf("manifest has %s at %s, but it was %s when analyzed; another release may have run, so analyze again",
+				rel.Package.Component, onDisk, rel.Package.CurrentVersion)
+		}
+
+		if rel.Package.VersionFile == "" {
+			continue
+		}
+		versionPath := filepath.Join(rel.Package.Path, rel.Package.VersionFile)
+		content, _, err := readOptional(filepath.Join(repoRoot, versionPath))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", versionPath, err)
+		}
+		// Only plain version files say which version they hold
+		if !isPlainVersionFile(content) || content == "" {
+			continue
+		}
+		fileVersion, err := version.ParseVersionFile(content)
+		if err != nil {
+			continue
+		}
+		// A version file behind the manifest is only stale, and this release
+		// fixes it; one ahead was bumped by someone else. Compare ignores
+		// build metadata (see Options.BuildMetadata).
+		if v, err := version.Parse(fileVersion); err == nil && v.Compare(oldVersion) > 0 {
+			return fmt.Errorf("%s already has %s, but %s is being released from %s; another release may have run, so analyze again",
+				versionPath, fileVersion, rel.Package.Component, rel.OldVersion)
+		}
+	}
+	return nil
+}
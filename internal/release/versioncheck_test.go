@@ -0,0 +1,118 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// analyzeFix commits a fix to service-a in a basic repo and analyzes it.
+func analyzeFix(t *testing.T) (string, *AnalysisResult) {
+	t.Helper()
+
+	dir := setupBasicRepo(t)
+	writeFile(t, dir, "workloads/service-a/src/main.go", "// Initial\n// Fix\n")
+	runCmd(t, dir, "git", "add", "-A")
+	runCmd(t, dir, "git", "commit", "-m", "fix(service-a): fix bug")
+
+	result, err := Analyze(&Options{RepoPath: dir, TreatPreMajorAsMinor: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 || result.Releases[0].NewVersion != "0.1.1" {
+		t.Fatalf("expected service-a 0.1.1, got %+v", result.Releases)
+	}
+	return dir, result
+}
+
+func TestApply_VersionChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(t *testing.T, dir string, result *AnalysisResult)
+		wantErr string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {},
+		},
+		{
+			name: "stale version file",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {
+				writeFile(t, dir, "workloads/service-a/VERSION", "0.0.9\n")
+			},
+		},
+		{
+			name: "downgrade",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {
+				result.Releases[0].NewVersion = "0.0.9"
+			},
+			wantErr: "isn't newer than 0.1.0",
+		},
+		{
+			name: "same version",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {
+				result.Releases[0].NewVersion = "0.1.0"
+			},
+			wantErr: "isn't newer than 0.1.0",
+		},
+		{
+			name: "manifest bumped",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {
+				writeFile(t, dir, "release-please-manifest.json", `{"workloads/service-a": "0.1.1"}`)
+			},
+			wantErr: "manifest has service-a at 0.1.1, but it was 0.1.0",
+		},
+		{
+			name: "version file bumped",
+			modify: func(t *testing.T, dir string, result *AnalysisResult) {
+				writeFile(t, dir, "workloads/service-a/VERSION", "0.1.1 # x-release-please-version\n")
+			},
+			wantErr: "workloads/service-a/VERSION already has 0.1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, result := analyzeFix(t)
+			tt.modify(t, dir, result)
+			changelog := filepath.Join(dir, "workloads/service-a/CHANGELOG.md")
+			before, _ := os.ReadFile(changelog)
+
+			err := Apply(result, false)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Apply failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if after, _ := os.ReadFile(changelog); string(after) != string(before) {
+				t.Error("expected nothing written after a failed check")
+			}
+		})
+	}
+}
+
+func TestApplyAndCommit_VersionChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir, result := analyzeFix(t)
+	writeFile(t, dir, "release-please-manifest.json", `{"workloads/service-a": "0.2.0"}`)
+
+	shas, err := ApplyAndCommit(result, &CommitOptions{Mode: CommitSingle})
+	if err == nil || !strings.Contains(err.Error(), "another release may have run") {
+		t.Fatalf("expected the manifest change to be caught, got %v", err)
+	}
+	if len(shas) != 0 {
+		t.Errorf("expected no commits, got %v", shas)
+	}
+}
@@ -0,0 +1,216 @@
+// Package signing signs release-damnit's JSON outputs so downstream
+// automation can check they came from the release workflow. Outputs are
+// signed with a provided private key (Ed25519 or ECDSA, PEM-encoded), or
+// keyless through cosign and Sigstore, using the workflow's OIDC identity.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Signature algorithms.
+const (
+	// AlgorithmEd25519 is an Ed25519 signature of the data.
+	AlgorithmEd25519 = "ed25519"
+
+	// AlgorithmECDSA is an ASN.1 ECDSA signature of the data's SHA-256.
+	AlgorithmECDSA = "ecdsa-sha256"
+
+	// AlgorithmSigstore is a cosign sign-blob bundle: the signature, the
+	// Fulcio certificate for the workflow identity, and the Rekor entry.
+	AlgorithmSigstore = "sigstore"
+)
+
+// Signature is a detached signature of a JSON output.
+type Signature struct {
+	// Algorithm is AlgorithmEd25519, AlgorithmECDSA, or AlgorithmSigstore.
+	Algorithm string `json:"algorithm"`
+
+	// KeyID is "sha256:" and the hex SHA-256 of the public key (PKIX DER),
+	// for key signatures.
+	KeyID string `json:"key_id,omitempty"`
+
+	// Signature is the base64 signature, for key signatures.
+	Signature string `json:"signature,omitempty"`
+
+	// Bundle is the cosign bundle, for AlgorithmSigstore.
+	Bundle json.RawMessage `json:"bundle,omitempty"`
+}
+
+// Signer signs outputs with a private key, or keyless through cosign.
+type Signer struct {
+	key crypto.Signer
+}
+
+// runCosign runs cosign with args. It's a variable so tests can stub it.
+var runCosign = func(args ...string) error {
+	cmd := exec.Command("cosign", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// NewKeySigner returns a Signer for a PEM-encoded private key: PKCS #8
+// (Ed25519 or ECDSA) or SEC 1 ("EC PRIVATE KEY").
+func NewKeySigner(keyPEM []byte) (*Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM-encoded")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %q (encrypted keys aren't supported)", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return &Signer{key: k}, nil
+	case *ecdsa.PrivateKey:
+		return &Signer{key: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key %T: use Ed25519 or ECDSA", key)
+	}
+}
+
+// NewKeylessSigner returns a Signer that signs with cosign sign-blob, which
+// must be installed and able to get an OIDC token (in GitHub Actions, the
+// job needs the id-token: write permission).
+func NewKeylessSigner() *Signer {
+	return &Signer{}
+}
+
+// Sign returns a detached signature of data.
+func (s *Signer) Sign(data []byte) (*Signature, error) {
+	contracts.RequireNotNil(s, "signer")
+
+	if s.key == nil {
+		return signKeyless(data)
+	}
+
+	keyID, err := KeyID(s.key.Public())
+	if err != nil {
+		return nil, err
+	}
+	sig := &Signature{KeyID: keyID}
+	var raw []byte
+	switch k := s.key.(type) {
+	case ed25519.PrivateKey:
+		sig.Algorithm = AlgorithmEd25519
+		raw = ed25519.Sign(k, data)
+	case *ecdsa.PrivateKey:
+		sig.Algorithm = AlgorithmECDSA
+		digest := sha256.Sum256(data)
+		if raw, err = ecdsa.SignASN1(rand.Reader, k, digest[:]); err != nil {
+			return nil, fmt.Errorf("failed to sign: %w", err)
+		}
+	}
+	sig.Signature = base64.StdEncoding.EncodeToString(raw)
+	return sig, nil
+}
+
+// signKeyless signs data with cosign sign-blob and returns its bundle.
+func signKeyless(data []byte) (*Signature, error) {
+	dir, err := os.MkdirTemp("", "release-damnit-sign-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	blob := filepath.Join(dir, "blob.json")
+	bundle := filepath.Join(dir, "blob.bundle")
+	if err := os.WriteFile(blob, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := runCosign("sign-blob", "--yes", "--bundle", bundle, blob); err != nil {
+		return nil, fmt.Errorf("cosign sign-blob failed: %w", err)
+	}
+	out, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cosign bundle: %w", err)
+	}
+	if !json.Valid(out) {
+		return nil, errors.New("cosign bundle is not valid JSON")
+	}
+	return &Signature{Algorithm: AlgorithmSigstore, Bundle: json.RawMessage(out)}, nil
+}
+
+// Verify checks a key signature of data against a PEM-encoded public key.
+// Sigstore bundles are verified with cosign verify-blob instead.
+func Verify(data []byte, sig *Signature, publicKeyPEM []byte) error {
+	contracts.RequireNotNil(sig, "sig")
+
+	if sig.Algorithm == AlgorithmSigstore {
+		return errors.New("verify sigstore signatures with cosign verify-blob --bundle")
+	}
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return errors.New("public key is not PEM-encoded")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+	keyID, err := KeyID(pub)
+	if err != nil {
+		return err
+	}
+	if sig.KeyID != keyID {
+		return fmt.Errorf("signed with key %s, not %s", sig.KeyID, keyID)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	var ok bool
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		ok = sig.Algorithm == AlgorithmEd25519 && ed25519.Verify(k, data, raw)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = sig.Algorithm == AlgorithmECDSA && ecdsa.VerifyASN1(k, digest[:], raw)
+	default:
+		return fmt.Errorf("unsupported public key %T", pub)
+	}
+	if !ok {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// KeyID returns "sha256:" and the hex SHA-256 of a public key's PKIX DER
+// encoding.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,204 @@
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// tracesPath is the OTLP/HTTP traces path under the collector's base URL.
+const tracesPath = "/v1/traces"
+
+// OTLP span kind and status codes (see the OpenTelemetry trace proto).
+const (
+	spanKindInternal = 1
+	statusOK         = 1
+	statusError      = 2
+)
+
+// otlpExporter builds an exporter that POSTs a trace of the run to an
+// OTLP/HTTP collector, JSON encoded.
+func otlpExporter(otlp *config.OTLP) *exporter {
+	return &exporter{
+		kind: "otlp",
+		build: func(run *Run) (*http.Request, error) {
+			payload, err := json.Marshal(BuildTrace(run, otlp.ServiceName))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal trace: %w", err)
+			}
+			target := strings.TrimSuffix(os.ExpandEnv(otlp.Endpoint), "/")
+			if !strings.HasSuffix(target, tracesPath) {
+				target += tracesPath
+			}
+			return newRequest(http.MethodPost, target, "application/json", payload, otlp.Headers)
+		},
+	}
+}
+
+// Trace is an OTLP ExportTraceServiceRequest.
+type Trace struct {
+	ResourceSpans []ResourceSpans `json:"resourceSpans"`
+}
+
+// ResourceSpans are the spans of one resource (the release-damnit service).
+type ResourceSpans struct {
+	Resource   Resource     `json:"resource"`
+	ScopeSpans []ScopeSpans `json:"scopeSpans"`
+}
+
+// Resource identifies the service that produced the spans.
+type Resource struct {
+	Attributes []Attribute `json:"attributes"`
+}
+
+// ScopeSpans are the spans from one instrumentation scope.
+type ScopeSpans struct {
+	Scope Scope  `json:"scope"`
+	Spans []Span `json:"spans"`
+}
+
+// Scope is the instrumentation scope.
+type Scope struct {
+	Name string `json:"name"`
+}
+
+// Span is one timed operation. Timestamps are Unix nanoseconds, as strings
+// per the OTLP JSON encoding of 64-bit integers.
+type Span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []Attribute `json:"attributes,omitempty"`
+	Events            []Event     `json:"events,omitempty"`
+	Status            Status      `json:"status"`
+}
+
+// Event is a point in time during a span.
+type Event struct {
+	TimeUnixNano string      `json:"timeUnixNano"`
+	Name         string      `json:"name"`
+	Attributes   []Attribute `json:"attributes,omitempty"`
+}
+
+// Status is a span's outcome.
+type Status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Attribute is a key and a typed value.
+type Attribute struct {
+	Key   string         `json:"key"`
+	Value AttributeValue `json:"value"`
+}
+
+// AttributeValue holds one of the supported value types.
+type AttributeValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+// BuildTrace returns a trace of the run: a root span for the whole run with
+// a child span per timed phase. Phase spans are laid end to end from the
+// start of the run, since only their durations are recorded. Each planned
+// release is an event on the root span.
+func BuildTrace(run *Run, serviceName string) *Trace {
+	traceID := randomID(16)
+	root := Span{
+		TraceID:           traceID,
+		SpanID:            randomID(8),
+		Name:              "release-damnit run",
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: unixNano(run.Start),
+		EndTimeUnixNano:   unixNano(run.End),
+		Attributes: []Attribute{
+			boolAttr("release_damnit.dry_run", run.DryRun),
+			intAttr("release_damnit.releases_planned", len(run.Report.Releases)),
+			intAttr("release_damnit.releases_created", run.ReleasesCreated),
+			intAttr("release_damnit.release_failures", run.Failures),
+		},
+		Status: Status{Code: statusOK},
+	}
+	if run.Failures > 0 {
+		root.Status = Status{Code: statusError, Message: fmt.Sprintf("%d release(s) failed", run.Failures)}
+	}
+	for _, rel := range run.Report.Releases {
+		root.Events = append(root.Events, Event{
+			TimeUnixNano: unixNano(run.End),
+			Name:         "release",
+			Attributes: []Attribute{
+				stringAttr("release_damnit.component", rel.Component),
+				stringAttr("release_damnit.version", rel.NewVersion),
+				stringAttr("release_damnit.bump_type", rel.BumpType),
+			},
+		})
+	}
+
+	metrics := run.Report.Metrics
+	if metrics != nil {
+		root.Attributes = append(root.Attributes,
+			intAttr("release_damnit.commits", metrics.Commits),
+			intAttr("release_damnit.files", metrics.Files))
+	}
+
+	spans := []Span{root}
+	if metrics != nil {
+		start := run.Start
+		for _, p := range metrics.Phases {
+			end := start.Add(time.Duration(p.DurationMS * float64(time.Millisecond)))
+			spans = append(spans, Span{
+				TraceID:           traceID,
+				SpanID:            randomID(8),
+				ParentSpanID:      root.SpanID,
+				Name:              p.Name,
+				Kind:              spanKindInternal,
+				StartTimeUnixNano: unixNano(start),
+				EndTimeUnixNano:   unixNano(end),
+				Status:            Status{Code: statusOK},
+			})
+			start = end
+		}
+	}
+
+	return &Trace{ResourceSpans: []ResourceSpans{{
+		Resource:   Resource{Attributes: []Attribute{stringAttr("service.name", serviceName)}},
+		ScopeSpans: []ScopeSpans{{Scope: Scope{Name: "release-damnit"}, Spans: spans}},
+	}}}
+}
+
+// randomID returns n random bytes as hex, for trace and span IDs.
+func randomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringAttr(key, value string) Attribute {
+	return Attribute{Key: key, Value: AttributeValue{StringValue: &value}}
+}
+
+func intAttr(key string, value int) Attribute {
+	s := strconv.Itoa(value)
+	return Attribute{Key: key, Value: AttributeValue{IntValue: &s}}
+}
+
+func boolAttr(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: AttributeValue{BoolValue: &value}}
+}
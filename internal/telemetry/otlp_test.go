@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+func TestBuildTrace(t *testing.T) {
+	run := testRun()
+	trace := BuildTrace(run, "release-damnit")
+
+	if len(trace.ResourceSpans) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(trace.ResourceSpans))
+	}
+	rs := trace.ResourceSpans[0]
+	if attr := rs.Resource.Attributes[0]; attr.Key != "service.name" || *attr.Value.StringValue != "release-damnit" {
+		t.Errorf("unexpected resource attribute: %+v", attr)
+	}
+
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("expected root and 2 phase spans, got %d", len(spans))
+	}
+	root := spans[0]
+	if len(root.TraceID) != 32 || len(root.SpanID) != 16 || root.ParentSpanID != "" {
+		t.Errorf("unexpected root ids: %+v", root)
+	}
+	if root.StartTimeUnixNano != "1709294400000000000" || root.EndTimeUnixNano != "1709294401500000000" {
+		t.Errorf("unexpected root times: %s - %s", root.StartTimeUnixNano, root.EndTimeUnixNano)
+	}
+	if root.Status.Code != statusOK || len(root.Events) != 2 {
+		t.Errorf("unexpected root status or events: %+v", root)
+	}
+
+	// Phases are laid end to end from the start of the run
+	load, traversal := spans[1], spans[2]
+	if load.Name != "config_load" || load.ParentSpanID != root.SpanID || load.TraceID != root.TraceID {
+		t.Errorf("unexpected config span: %+v", load)
+	}
+	if load.EndTimeUnixNano != "1709294400002000000" || traversal.StartTimeUnixNano != load.EndTimeUnixNano {
+		t.Errorf("expected sequential phases, got %+v and %+v", load, traversal)
+	}
+	if traversal.EndTimeUnixNano != "1709294400042500000" {
+		t.Errorf("unexpected traversal end: %s", traversal.EndTimeUnixNano)
+	}
+}
+
+func TestBuildTrace_Failures(t *testing.T) {
+	run := testRun()
+	run.Failures = 1
+	run.Report.Metrics = nil
+
+	spans := BuildTrace(run, "svc").ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("expected only the root span without metrics, got %d", len(spans))
+	}
+	if spans[0].Status.Code != statusError || !strings.Contains(spans[0].Status.Message, "1 release(s) failed") {
+		t.Errorf("unexpected status: %+v", spans[0].Status)
+	}
+}
+
+func TestBuildTrace_JSON(t *testing.T) {
+	data, err := json.Marshal(BuildTrace(testRun(), "svc"))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		`"key":"release_damnit.releases_planned","value":{"intValue":"2"}`,
+		`"key":"release_damnit.dry_run","value":{"boolValue":false}`,
+		`"name":"release","attributes":[{"key":"release_damnit.component","value":{"stringValue":"service-a"}}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in %s", want, got)
+		}
+	}
+}
+
+func TestOTLPExporter_Endpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"http://collector:4318", "http://collector:4318/v1/traces"},
+		{"http://collector:4318/", "http://collector:4318/v1/traces"},
+		{"https://otlp.example.com/v1/traces", "https://otlp.example.com/v1/traces"},
+	}
+
+	for _, tc := range tests {
+		req, err := otlpExporter(&config.OTLP{Endpoint: tc.endpoint}).build(testRun())
+		if err != nil {
+			t.Fatalf("build failed: %v", err)
+		}
+		if req.Method != http.MethodPost || req.URL.String() != tc.want {
+			t.Errorf("endpoint %s: got %s %s, want POST %s", tc.endpoint, req.Method, req.URL, tc.want)
+		}
+	}
+}
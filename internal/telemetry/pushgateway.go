@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dsswift/release-damnit/internal/config"
+)
+
+// metricsContentType is the Prometheus text exposition format.
+const metricsContentType = "text/plain; version=0.0.4"
+
+// pushgatewayExporter builds an exporter that PUTs the run's metrics to the
+// Pushgateway, replacing the previous run's metrics in the same group.
+func pushgatewayExporter(push *config.Pushgateway) *exporter {
+	return &exporter{
+		kind: "pushgateway",
+		build: func(run *Run) (*http.Request, error) {
+			target := strings.TrimSuffix(os.ExpandEnv(push.URL), "/") + groupingPath(push.Job, push.Grouping)
+			return newRequest(http.MethodPut, target, metricsContentType, []byte(FormatMetrics(run)), push.Headers)
+		},
+	}
+}
+
+// groupingPath returns the Pushgateway path for a job and grouping labels:
+// /metrics/job/<job>/<label>/<value>... with labels sorted. Values that
+// can't appear in a path segment use the @base64 form.
+func groupingPath(job string, grouping map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("/metrics")
+	writeLabel := func(name, value string) {
+		if value == "" || strings.Contains(value, "/") {
+			fmt.Fprintf(&sb, "/%s@base64/%s", url.PathEscape(name), base64.RawURLEncoding.EncodeToString([]byte(value)))
+			return
+		}
+		fmt.Fprintf(&sb, "/%s/%s", url.PathEscape(name), url.PathEscape(value))
+	}
+
+	writeLabel("job", job)
+	labels := make([]string, 0, len(grouping))
+	for name := range grouping {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+	for _, name := range labels {
+		writeLabel(name, grouping[name])
+	}
+	return sb.String()
+}
+
+// FormatMetrics renders the run's metrics in the Prometheus text format.
+func FormatMetrics(run *Run) string {
+	var sb strings.Builder
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(&sb, "# HELP release_damnit_%s %s\n", name, help)
+		fmt.Fprintf(&sb, "# TYPE release_damnit_%s gauge\n", name)
+		fmt.Fprintf(&sb, "release_damnit_%s %s\n", name, formatFloat(value))
+	}
+
+	dryRun := 0.0
+	if run.DryRun {
+		dryRun = 1
+	}
+	gauge("last_run_timestamp_seconds", "When the last run finished, in Unix seconds.", float64(run.End.UnixMilli())/1000)
+	gauge("run_duration_seconds", "How long the last run took.", run.End.Sub(run.Start).Seconds())
+	gauge("dry_run", "Whether the last run was a dry run (1) or not (0).", dryRun)
+	gauge("releases_planned", "Releases the last run planned.", float64(len(run.Report.Releases)))
+	gauge("releases_created", "GitHub releases the last run created.", float64(run.ReleasesCreated))
+	gauge("release_failures", "Releases the last run failed to create.", float64(run.Failures))
+
+	metrics := run.Report.Metrics
+	if metrics == nil {
+		return sb.String()
+	}
+	gauge("commits_analyzed", "Commits the last run analyzed.", float64(metrics.Commits))
+	gauge("files_changed", "Changed files across the commits the last run analyzed.", float64(metrics.Files))
+
+	sb.WriteString("# HELP release_damnit_phase_duration_seconds How long each phase of the last run took.\n")
+	sb.WriteString("# TYPE release_damnit_phase_duration_seconds gauge\n")
+	for _, p := range metrics.Phases {
+		fmt.Fprintf(&sb, "release_damnit_phase_duration_seconds{phase=%q} %s\n", p.Name, formatFloat(p.DurationMS/1000))
+	}
+	return sb.String()
+}
+
+// formatFloat formats a sample value as Prometheus expects.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
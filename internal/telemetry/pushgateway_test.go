@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupingPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      string
+		grouping map[string]string
+		want     string
+	}{
+		{"job only", "release_damnit", nil, "/metrics/job/release_damnit"},
+		{"sorted labels", "j", map[string]string{"repo": "api", "env": "prod"}, "/metrics/job/j/env/prod/repo/api"},
+		{"slash in value", "j", map[string]string{"repo": "acme/api"}, "/metrics/job/j/repo@base64/YWNtZS9hcGk"},
+		{"empty value", "j", map[string]string{"repo": ""}, "/metrics/job/j/repo@base64/"},
+		{"escaped value", "j", map[string]string{"repo": "a b"}, "/metrics/job/j/repo/a%20b"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := groupingPath(tc.job, tc.grouping); got != tc.want {
+				t.Errorf("groupingPath() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatMetrics(t *testing.T) {
+	got := FormatMetrics(testRun())
+
+	for _, want := range []string{
+		"# TYPE release_damnit_releases_planned gauge\nrelease_damnit_releases_planned 2\n",
+		"release_damnit_releases_created 2\n",
+		"release_damnit_release_failures 0\n",
+		"release_damnit_dry_run 0\n",
+		"release_damnit_run_duration_seconds 1.5\n",
+		"release_damnit_last_run_timestamp_seconds 1.7092944015e+09\n",
+		"release_damnit_commits_analyzed 3\n",
+		"release_damnit_files_changed 5\n",
+		"release_damnit_phase_duration_seconds{phase=\"config_load\"} 0.002\n",
+		"release_damnit_phase_duration_seconds{phase=\"git_traversal\"} 0.0405\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatMetrics_Untimed(t *testing.T) {
+	run := testRun()
+	run.Report.Metrics = nil
+	run.DryRun = true
+
+	got := FormatMetrics(run)
+	if strings.Contains(got, "phase_duration_seconds") || strings.Contains(got, "commits_analyzed") {
+		t.Errorf("expected no timing metrics without report metrics:\n%s", got)
+	}
+	if !strings.Contains(got, "release_damnit_dry_run 1\n") {
+		t.Errorf("expected dry run gauge:\n%s", got)
+	}
+}
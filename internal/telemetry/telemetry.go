@@ -0,0 +1,165 @@
+// Package telemetry exports run metrics for release-damnit running as a
+// service or on a schedule: pushed to a Prometheus Pushgateway, or sent as an
+// OTLP trace. Exporters are configured in the "telemetry" section of
+// release-please-config.json.
+package telemetry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// defaultTimeout bounds each export request.
+const defaultTimeout = 10 * time.Second
+
+// Run describes one release-damnit run.
+type Run struct {
+	// Report is the run's release report. Its Metrics supply the phase
+	// durations and commit and file counts.
+	Report *release.ReleaseReport
+
+	// Start is when the run started.
+	Start time.Time
+
+	// End is when the run finished. Defaults to the time of Send.
+	End time.Time
+
+	// DryRun is true if the run made no changes.
+	DryRun bool
+
+	// ReleasesCreated is the number of GitHub releases created.
+	ReleasesCreated int
+
+	// Failures is the number of releases that failed to be created.
+	Failures int
+}
+
+// Options configures telemetry export.
+type Options struct {
+	// Client is the HTTP client to use. Defaults to a client with a 10s timeout.
+	Client *http.Client
+}
+
+// Delivery records the outcome of a single export.
+type Delivery struct {
+	// Kind is the exporter ("pushgateway" or "otlp").
+	Kind string
+
+	// Target identifies the destination for display. Only the scheme and host
+	// are kept, since URLs may embed credentials.
+	Target string
+
+	// StatusCode is the HTTP status returned (0 if not sent).
+	StatusCode int
+}
+
+// exporter is a single configured telemetry destination.
+type exporter struct {
+	kind  string
+	build func(run *Run) (*http.Request, error)
+}
+
+// Send exports the run to every configured exporter. Failures on one exporter
+// don't prevent export to the others; all errors are combined into the
+// returned error.
+func Send(telemetry *config.Telemetry, run *Run, opts *Options) ([]*Delivery, error) {
+	contracts.RequireNotNil(run, "run")
+	contracts.RequireNotNil(run.Report, "run.Report")
+
+	if telemetry == nil {
+		return nil, nil
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	if run.End.IsZero() {
+		finished := *run
+		finished.End = time.Now()
+		run = &finished
+	}
+
+	var exporters []*exporter
+	if telemetry.Pushgateway != nil {
+		exporters = append(exporters, pushgatewayExporter(telemetry.Pushgateway))
+	}
+	if telemetry.OTLP != nil {
+		exporters = append(exporters, otlpExporter(telemetry.OTLP))
+	}
+
+	var deliveries []*Delivery
+	var errs []error
+
+	for _, e := range exporters {
+		req, err := e.build(run)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.kind, err))
+			continue
+		}
+
+		delivery := &Delivery{Kind: e.kind, Target: redactURL(req.URL)}
+		deliveries = append(deliveries, delivery)
+
+		status, err := do(client, req)
+		delivery.StatusCode = status
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", e.kind, delivery.Target, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return deliveries, errors.Join(errs...)
+	}
+	return deliveries, nil
+}
+
+// newRequest creates a request with the given body and headers. Header
+// values support ${VAR} expansion.
+func newRequest(method, target, contentType string, body []byte, headers map[string]string) (*http.Request, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "release-damnit")
+	for name, value := range headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+	return req, nil
+}
+
+// do sends a request and checks for a 2xx response.
+func do(client *http.Client, req *http.Request) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// redactURL strips everything but the scheme and host from a URL.
+func redactURL(u *url.URL) string {
+	if u == nil || u.Host == "" {
+		return "(unknown)"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,148 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/release"
+)
+
+// testRun returns a timed run that planned and created two releases.
+func testRun() *Run {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &Run{
+		Report: &release.ReleaseReport{
+			Releases: []release.ComponentRelease{
+				{Component: "service-a", NewVersion: "1.1.0", BumpType: "minor"},
+				{Component: "service-b", NewVersion: "0.2.1", BumpType: "patch"},
+			},
+			Metrics: &release.Metrics{
+				Commits: 3,
+				Files:   5,
+				Phases: []release.PhaseMetric{
+					{Name: release.PhaseConfigLoad, DurationMS: 2},
+					{Name: release.PhaseGitTraversal, DurationMS: 40.5},
+				},
+				TotalMS: 42.5,
+			},
+		},
+		Start:           start,
+		End:             start.Add(1500 * time.Millisecond),
+		ReleasesCreated: 2,
+	}
+}
+
+// recorder is a test server that records the requests it receives.
+type recorder struct {
+	mu       sync.Mutex
+	requests map[string]string // "METHOD path" -> body
+	status   int
+}
+
+func newRecorder(t *testing.T, status int) (*recorder, *httptest.Server) {
+	rec := &recorder{requests: map[string]string{}, status: status}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.requests[r.Method+" "+r.URL.Path] = string(body)
+		rec.mu.Unlock()
+		w.WriteHeader(rec.status)
+	}))
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+func TestSend_NoTelemetry(t *testing.T) {
+	deliveries, err := Send(nil, testRun(), nil)
+	if err != nil || deliveries != nil {
+		t.Errorf("expected nothing sent, got %v, %v", deliveries, err)
+	}
+}
+
+func TestSend_AllExporters(t *testing.T) {
+	rec, server := newRecorder(t, http.StatusOK)
+	telemetry := &config.Telemetry{
+		Pushgateway: &config.Pushgateway{URL: server.URL, Job: "release_damnit"},
+		OTLP:        &config.OTLP{Endpoint: server.URL + "/", ServiceName: "release-damnit"},
+	}
+
+	deliveries, err := Send(telemetry, testRun(), nil)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Kind != "pushgateway" || deliveries[1].Kind != "otlp" {
+		t.Fatalf("unexpected deliveries: %+v", deliveries)
+	}
+	for _, d := range deliveries {
+		if d.StatusCode != http.StatusOK || !strings.HasPrefix(d.Target, "http://127.0.0.1") {
+			t.Errorf("unexpected delivery: %+v", d)
+		}
+	}
+
+	if body := rec.requests["PUT /metrics/job/release_damnit"]; !strings.Contains(body, "release_damnit_releases_created 2\n") {
+		t.Errorf("expected pushed metrics, got requests %v", rec.requests)
+	}
+	if body := rec.requests["POST /v1/traces"]; !strings.Contains(body, `"resourceSpans"`) {
+		t.Errorf("expected trace, got requests %v", rec.requests)
+	}
+}
+
+func TestSend_Headers(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	t.Setenv("OTLP_TOKEN", "s3cret")
+	telemetry := &config.Telemetry{OTLP: &config.OTLP{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "Bearer ${OTLP_TOKEN}"},
+	}}
+	if _, err := Send(telemetry, testRun(), nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got != "Bearer s3cret" {
+		t.Errorf("expected expanded header, got %q", got)
+	}
+}
+
+func TestSend_ErrorStatus(t *testing.T) {
+	_, failing := newRecorder(t, http.StatusInternalServerError)
+	_, ok := newRecorder(t, http.StatusOK)
+	telemetry := &config.Telemetry{
+		Pushgateway: &config.Pushgateway{URL: failing.URL, Job: "release_damnit"},
+		OTLP:        &config.OTLP{Endpoint: ok.URL, ServiceName: "release-damnit"},
+	}
+
+	deliveries, err := Send(telemetry, testRun(), nil)
+	if err == nil || !strings.Contains(err.Error(), "pushgateway") {
+		t.Fatalf("expected pushgateway error, got %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[1].StatusCode != http.StatusOK {
+		t.Errorf("expected otlp to still be sent, got %+v", deliveries)
+	}
+}
+
+func TestSend_DefaultsEnd(t *testing.T) {
+	rec, server := newRecorder(t, http.StatusOK)
+	run := testRun()
+	run.End = time.Time{}
+
+	telemetry := &config.Telemetry{Pushgateway: &config.Pushgateway{URL: server.URL, Job: "j"}}
+	if _, err := Send(telemetry, run, nil); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !run.End.IsZero() {
+		t.Error("expected the caller's run to be left alone")
+	}
+	if strings.Contains(rec.requests["PUT /metrics/job/j"], "release_damnit_run_duration_seconds -") {
+		t.Error("expected a positive run duration")
+	}
+}
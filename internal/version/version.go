@@ -42,6 +42,21 @@ func (b BumpType) String() string {
 	}
 }
 
+// ParseBumpType parses the string form of a BumpType ("none", "patch", "minor", "major").
+func ParseBumpType(s string) (BumpType, error) {
+	switch s {
+	case "none":
+		return None, nil
+	case "patch":
+		return Patch, nil
+	case "minor":
+		return Minor, nil
+	case "major":
+		return Major, nil
+	}
+	return None, fmt.Errorf("invalid bump type: %q", s)
+}
+
 // Version represents a parsed semantic version.
 type Version struct {
 	Major      int
@@ -56,7 +71,8 @@ type Version struct {
 var semverRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z-.]+))?(?:\+([0-9A-Za-z-.]+))?$`)
 
 // Parse parses a version string into a Version struct.
-// Accepts versions with or without 'v' prefix.
+// Accepts versions with or without 'v' prefix. Components must fit in 32
+// bits, so bumping one can't overflow.
 func Parse(s string) (*Version, error) {
 	contracts.RequireNotEmpty(s, "version string")
 
@@ -65,14 +81,19 @@ func Parse(s string) (*Version, error) {
 		return nil, fmt.Errorf("invalid semver: %s", s)
 	}
 
-	major, _ := strconv.Atoi(matches[1])
-	minor, _ := strconv.Atoi(matches[2])
-	patch, _ := strconv.Atoi(matches[3])
+	var parts [3]int
+	for i := range parts {
+		n, err := strconv.ParseInt(matches[i+1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid semver: %s: component %s is out of range", s, matches[i+1])
+		}
+		parts[i] = int(n)
+	}
 
 	return &Version{
-		Major:      major,
-		Minor:      minor,
-		Patch:      patch,
+		Major:      parts[0],
+		Minor:      parts[1],
+		Patch:      parts[2],
 		Prerelease: matches[4],
 		Build:      matches[5],
 	}, nil
@@ -100,6 +121,23 @@ func (v *Version) IsPreMajor() bool {
 	return v.Major == 0
 }
 
+// StrictZeroBump returns the bump semver recommends for bumpType while the
+// version is 0.x: breaking changes bump minor and features bump patch. Other
+// versions keep bumpType.
+func (v *Version) StrictZeroBump(bumpType BumpType) BumpType {
+	if !v.IsPreMajor() {
+		return bumpType
+	}
+	switch bumpType {
+	case Major:
+		return Minor
+	case Minor:
+		return Patch
+	default:
+		return bumpType
+	}
+}
+
 // Bump returns a new Version with the specified bump applied.
 // For pre-1.0 versions with treatPreMajorAsMinor=true, minor bumps become patch.
 func (v *Version) Bump(bumpType BumpType, treatPreMajorAsMinor bool) *Version {
@@ -207,6 +245,23 @@ func CommitTypeToBump(commitType string) BumpType {
 	}
 }
 
+// BumpBetween returns the bump that takes from to to: the most significant
+// component that changed. A prerelease-only change counts as a patch.
+func BumpBetween(from, to *Version) BumpType {
+	contracts.RequireNotNil(from, "from")
+	contracts.RequireNotNil(to, "to")
+
+	switch {
+	case from.Major != to.Major:
+		return Major
+	case from.Minor != to.Minor:
+		return Minor
+	case from.Patch != to.Patch, from.Prerelease != to.Prerelease:
+		return Patch
+	}
+	return None
+}
+
 // MaxBump returns the higher-priority bump type.
 func MaxBump(a, b BumpType) BumpType {
 	if a > b {
@@ -238,11 +293,16 @@ func ParseVersionFile(content string) (string, error) {
 }
 
 // FormatVersionFile formats a version for writing to a VERSION file.
-// Preserves the x-release-please-version marker if present in the original.
+// Preserves the x-release-please-version marker if present in the original,
+// and its CRLF line ending.
 func FormatVersionFile(version string, originalContent string) string {
+	eol := "\n"
+	if strings.HasSuffix(originalContent, "\r\n") {
+		eol = "\r\n"
+	}
 	// Check if original had the marker
 	if strings.Contains(originalContent, "x-release-please-version") {
-		return fmt.Sprintf("%s # x-release-please-version\n", version)
+		return fmt.Sprintf("%s # x-release-please-version%s", version, eol)
 	}
-	return version + "\n"
+	return version + eol
 }
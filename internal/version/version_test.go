@@ -46,6 +46,8 @@ func TestParse_InvalidVersions(t *testing.T) {
 		"a.b.c",
 		"1.2.3.4",
 		"not-a-version",
+		"99999999999999999999.0.0",
+		"1.2147483648.0",
 	}
 
 	for _, tc := range tests {
@@ -138,6 +140,30 @@ func TestVersion_Bump(t *testing.T) {
 	}
 }
 
+func TestVersion_StrictZeroBump(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Version
+		bump BumpType
+		want BumpType
+	}{
+		{"breaking pre-1.0", Version{0, 3, 1, "", ""}, Major, Minor},
+		{"feat pre-1.0", Version{0, 3, 1, "", ""}, Minor, Patch},
+		{"fix pre-1.0", Version{0, 3, 1, "", ""}, Patch, Patch},
+		{"none pre-1.0", Version{0, 3, 1, "", ""}, None, None},
+		{"breaking post-1.0", Version{1, 0, 0, "", ""}, Major, Major},
+		{"feat post-1.0", Version{1, 0, 0, "", ""}, Minor, Minor},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.v.StrictZeroBump(tc.bump); got != tc.want {
+				t.Errorf("StrictZeroBump(%v) = %v, want %v", tc.bump, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestVersion_Compare(t *testing.T) {
 	tests := []struct {
 		a    string
@@ -260,6 +286,8 @@ func TestFormatVersionFile(t *testing.T) {
 		{"no marker", "1.2.4", "1.2.3\n", "1.2.4\n"},
 		{"with marker", "0.1.120", "0.1.119 # x-release-please-version\n", "0.1.120 # x-release-please-version\n"},
 		{"empty original", "1.0.0", "", "1.0.0\n"},
+		{"crlf", "1.2.4", "1.2.3\r\n", "1.2.4\r\n"},
+		{"crlf with marker", "0.1.120", "0.1.119 # x-release-please-version\r\n", "0.1.120 # x-release-please-version\r\n"},
 	}
 
 	for _, tc := range tests {
@@ -292,3 +320,105 @@ func TestBumpType_String(t *testing.T) {
 		})
 	}
 }
+
+func TestBumpBetween(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected BumpType
+	}{
+		{"1.4.2", "1.4.3", Patch},
+		{"1.4.2", "1.5.0", Minor},
+		{"1.4.2", "2.0.0", Major},
+		{"0.1.0", "0.2.0", Minor},
+		{"1.0.0-rc.1", "1.0.0", Patch},
+		{"1.4.2", "1.4.2", None},
+	}
+	for _, tc := range tests {
+		from, _ := Parse(tc.from)
+		to, _ := Parse(tc.to)
+		if got := BumpBetween(from, to); got != tc.expected {
+			t.Errorf("BumpBetween(%s, %s) = %s, want %s", tc.from, tc.to, got, tc.expected)
+		}
+	}
+}
+
+func TestParseBumpType(t *testing.T) {
+	for _, bt := range []BumpType{None, Patch, Minor, Major} {
+		got, err := ParseBumpType(bt.String())
+		if err != nil || got != bt {
+			t.Errorf("ParseBumpType(%q) = %v, %v; want %v", bt.String(), got, err, bt)
+		}
+	}
+	if _, err := ParseBumpType("huge"); err == nil {
+		t.Error("expected error for unknown bump type")
+	}
+}
+
+// FuzzParse checks that no version string panics Parse, and that parsed
+// versions round-trip through String and bump without overflowing. Run with:
+//
+//	go test ./internal/version -fuzz FuzzParse
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"1.2.3",
+		"v0.1.0",
+		"1.2.3-alpha.1+build.5",
+		"2147483647.2147483647.2147483647",
+		"9223372036854775807.0.0",
+		"1.2.3-",
+		"v",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if s == "" {
+			return // Parse requires a non-empty string
+		}
+		v, err := Parse(s)
+		if err != nil {
+			return
+		}
+		again, err := Parse(v.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) failed on its own output %q: %v", s, v.String(), err)
+		}
+		if again.Compare(v) != 0 || again.Build != v.Build {
+			t.Errorf("%q round-tripped to %q", s, again.String())
+		}
+		for _, bt := range []BumpType{Patch, Minor, Major} {
+			if bumped := v.Bump(bt, false); bumped.Compare(v) <= 0 {
+				t.Errorf("%s bump of %s gave %s", bt, v, bumped)
+			}
+		}
+	})
+}
+
+// FuzzParseVersionFile checks that no VERSION file content panics
+// ParseVersionFile. Run with:
+//
+//	go test ./internal/version -fuzz FuzzParseVersionFile
+func FuzzParseVersionFile(f *testing.F) {
+	for _, seed := range []string{
+		"1.2.3\n",
+		"0.1.119 # x-release-please-version\n",
+		"# only a comment",
+		"  v1.0.0-rc.1  ",
+		"1.2.3\n4.5.6\n",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		if content == "" {
+			return // ParseVersionFile requires content
+		}
+		got, err := ParseVersionFile(content)
+		if err != nil {
+			return
+		}
+		if _, err := Parse(got); err != nil {
+			t.Errorf("ParseVersionFile(%q) returned unparseable %q: %v", content, got, err)
+		}
+	})
+}
@@ -0,0 +1,332 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a path: a mapping key, a sequence index, or a
+// filter selecting the sequence items whose field equals a value.
+type pathSegment struct {
+	key   string
+	index int // -1 unless the step is an index
+	field string
+	value string
+}
+
+// parsePath parses a JSONPath-style path to a scalar, such as "$.image.tag",
+// "$.images[0].newTag", or "$.images[?(@.name=='api')].newTag". The leading
+// "$" is optional; keys may also be written ['quoted'].
+func parsePath(path string) ([]pathSegment, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var segments []pathSegment
+	for s != "" {
+		switch {
+		case s[0] == '.':
+			end := strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			key := s[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: key, index: -1})
+			s = s[end+1:]
+
+		case s[0] == '[':
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unterminated [", path)
+			}
+			seg, err := parseBracket(s[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			segments = append(segments, seg)
+			s = s[end+1:]
+
+		case len(segments) == 0:
+			// "image.tag" without the leading "$."
+			s = "." + s
+
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", path, s)
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: no keys", path)
+	}
+	return segments, nil
+}
+
+// parseBracket parses the inside of [...]: an index, a quoted key, or a
+// ?(@.field==value) filter.
+func parseBracket(s string) (pathSegment, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")") {
+		field, value, ok := strings.Cut(s[2:len(s)-1], "==")
+		field = strings.TrimSpace(field)
+		if !ok || !strings.HasPrefix(field, "@.") {
+			return pathSegment{}, fmt.Errorf("unsupported filter %q (want ?(@.field=='value'))", s)
+		}
+		return pathSegment{index: -1, field: field[2:], value: unquotePathValue(strings.TrimSpace(value))}, nil
+	}
+	if s != "" && (s[0] == '\'' || s[0] == '"') {
+		return pathSegment{key: unquotePathValue(s), index: -1}, nil
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("invalid index %q", s)
+	}
+	return pathSegment{index: index}, nil
+}
+
+func unquotePathValue(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// block is a node spanning raw lines [start, end). Its content starts at
+// column col on the first line; later lines belong to it if indented at
+// least as far.
+type block struct {
+	start, end int
+	col        int
+}
+
+// ErrNotFound is returned by ReplaceScalar and LookupScalar when nothing in
+// the document matches the path.
+var ErrNotFound = errors.New("not found")
+
+// editor locates nodes in raw source lines.
+type editor struct {
+	lines []string
+}
+
+// ReplaceScalar replaces the scalar at path with fn(old value), leaving the
+// rest of the document byte-for-byte unchanged. Quoting is kept. The path
+// must lead to a plain or quoted scalar in block-style YAML.
+func ReplaceScalar(content, path string, fn func(string) string) (string, error) {
+	e := &editor{lines: strings.Split(content, "\n")}
+	node, err := e.find(path)
+	if err != nil {
+		return "", err
+	}
+
+	value, from, to, err := e.scalar(node)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	l := e.lines[node.start]
+	e.lines[node.start] = l[:from] + fn(value) + l[to:]
+	return strings.Join(e.lines, "\n"), nil
+}
+
+// LookupScalar returns the scalar at path, unquoted, with the same path
+// syntax and restrictions as ReplaceScalar.
+func LookupScalar(content, path string) (string, error) {
+	e := &editor{lines: strings.Split(content, "\n")}
+	node, err := e.find(path)
+	if err != nil {
+		return "", err
+	}
+
+	value, _, _, err := e.scalar(node)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return value, nil
+}
+
+// find returns the node at path.
+func (e *editor) find(path string) (block, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return block{}, err
+	}
+
+	root, ok := e.root()
+	if !ok {
+		return block{}, fmt.Errorf("%s %w: document is empty", path, ErrNotFound)
+	}
+
+	node := root
+	for i, seg := range segments {
+		var found bool
+		switch {
+		case seg.key != "":
+			node, found = e.findKey(node, seg.key)
+		case seg.field != "":
+			node, found = e.findItem(node, func(item block) bool {
+				field, ok := e.findKey(item, seg.field)
+				if !ok {
+					return false
+				}
+				value, _, _, err := e.scalar(field)
+				return err == nil && value == seg.value
+			})
+		default:
+			n := 0
+			node, found = e.findItem(node, func(block) bool {
+				n++
+				return n-1 == seg.index
+			})
+		}
+		if !found {
+			return block{}, fmt.Errorf("%s %w: no match for step %d", path, ErrNotFound, i+1)
+		}
+	}
+	return node, nil
+}
+
+// root returns the top-level node, skipping a leading "---".
+func (e *editor) root() (block, bool) {
+	for i, l := range e.lines {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		return block{start: i, end: len(e.lines), col: indentOf(l)}, true
+	}
+	return block{}, false
+}
+
+// entries returns the lines in b starting an entry at b.col: sequence items
+// if seq, otherwise mapping keys. Sequence items at a mapping's own indent
+// belong to the preceding key.
+func (e *editor) entries(b block, seq bool) []int {
+	var starts []int
+	for i := b.start; i < b.end; i++ {
+		col := b.col
+		if i != b.start {
+			col = indentOf(e.lines[i])
+		}
+		text := e.textAt(i, col)
+		if col != b.col || text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if isSequenceItem(text) == seq {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// findKey returns the value of a mapping key in b.
+func (e *editor) findKey(b block, key string) (block, bool) {
+	starts := e.entries(b, false)
+	for n, i := range starts {
+		col := b.col
+		if i != b.start {
+			col = indentOf(e.lines[i])
+		}
+		text := stripComment(e.textAt(i, col))
+		end := mappingKeyEnd(text)
+		if end < 0 {
+			continue
+		}
+		if k, err := parseKey(text[:end], i+1); err != nil || k != key {
+			continue
+		}
+
+		next := b.end
+		if n+1 < len(starts) {
+			next = starts[n+1]
+		}
+		valueCol := skipSpaces(e.lines[i], col+end+1)
+		if rest := e.textAt(i, valueCol); rest != "" && !strings.HasPrefix(rest, "#") {
+			return block{start: i, end: i + 1, col: valueCol}, true
+		}
+		for j := i + 1; j < next; j++ {
+			if t := strings.TrimSpace(e.lines[j]); t != "" && !strings.HasPrefix(t, "#") {
+				return block{start: j, end: next, col: indentOf(e.lines[j])}, true
+			}
+		}
+		return block{}, false
+	}
+	return block{}, false
+}
+
+// findItem returns the first item of the sequence b that match accepts.
+func (e *editor) findItem(b block, match func(block) bool) (block, bool) {
+	starts := e.entries(b, true)
+	for n, i := range starts {
+		col := b.col
+		if i != b.start {
+			col = indentOf(e.lines[i])
+		}
+		next := b.end
+		if n+1 < len(starts) {
+			next = starts[n+1]
+		}
+
+		// Content after "- " is the item itself; a bare "-" puts it on the next lines
+		itemCol := skipSpaces(e.lines[i], col+1)
+		item := block{start: i, end: next, col: itemCol}
+		if rest := e.textAt(i, itemCol); rest == "" || strings.HasPrefix(rest, "#") {
+			item.start = -1
+			for j := i + 1; j < next; j++ {
+				if t := strings.TrimSpace(e.lines[j]); t != "" && !strings.HasPrefix(t, "#") {
+					item = block{start: j, end: next, col: indentOf(e.lines[j])}
+					break
+				}
+			}
+			if item.start < 0 {
+				continue
+			}
+		}
+		if match(item) {
+			return item, true
+		}
+	}
+	return block{}, false
+}
+
+// scalar returns the scalar value at the start of b and the byte range of
+// the value on its line, inside any quotes.
+func (e *editor) scalar(b block) (string, int, int, error) {
+	l := e.lines[b.start]
+	text := strings.TrimRight(stripComment(l[b.col:]), " \t")
+	switch {
+	case text == "":
+		return "", 0, 0, fmt.Errorf("line %d: empty value", b.start+1)
+	case text[0] == '"' || text[0] == '\'':
+		value, n, err := parseQuoted(text, b.start+1)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		return value, b.col + 1, b.col + n - 1, nil
+	case isFlow(text) || text[0] == '|' || text[0] == '>' || text[0] == '&' || text[0] == '*' || text[0] == '!':
+		return "", 0, 0, fmt.Errorf("line %d: %q is not a plain or quoted scalar", b.start+1, text)
+	case isSequenceItem(text) || mappingKeyEnd(text) >= 0:
+		return "", 0, 0, fmt.Errorf("line %d: expected a scalar, found a collection", b.start+1)
+	}
+	return text, b.col, b.col + len(text), nil
+}
+
+// textAt returns line i from column col, without leading spaces.
+func (e *editor) textAt(i, col int) string {
+	l := e.lines[i]
+	if col >= len(l) {
+		return ""
+	}
+	return strings.TrimSpace(l[col:])
+}
+
+// skipSpaces returns the first column at or after col that isn't a space.
+func skipSpaces(l string, col int) int {
+	for col < len(l) && l[col] == ' ' {
+		col++
+	}
+	return col
+}
+
+// indentOf returns the number of leading spaces on a line.
+func indentOf(l string) int {
+	return len(l) - len(strings.TrimLeft(l, " "))
+}
@@ -0,0 +1,141 @@
+package yaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReplaceScalar(t *testing.T) {
+	kustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+images:
+- name: ghcr.io/acme/web
+  newTag: 1.0.0
+- name: ghcr.io/acme/api   # the API
+  newTag: "1.2.3"   # x-release
+`
+	values := `---
+# Helm values
+image:
+  repository: ghcr.io/acme/api
+  tag: 'v1.2.3'
+sidecars:
+  -
+    name: proxy
+    tag: 2.0.0
+`
+
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		want    string
+	}{
+		{
+			name:    "kustomize filter",
+			content: kustomization,
+			path:    "$.images[?(@.name=='ghcr.io/acme/api')].newTag",
+			want:    strings.Replace(kustomization, `newTag: "1.2.3"`, `newTag: "1.3.0"`, 1),
+		},
+		{
+			name:    "kustomize index",
+			content: kustomization,
+			path:    "$.images[0].newTag",
+			want:    strings.Replace(kustomization, "newTag: 1.0.0", "newTag: 1.3.0", 1),
+		},
+		{
+			name:    "helm values",
+			content: values,
+			path:    "$.image.tag",
+			want:    strings.Replace(values, "tag: 'v1.2.3'", "tag: '1.3.0'", 1),
+		},
+		{
+			name:    "without $ and with a bracketed key",
+			content: values,
+			path:    "sidecars[0]['tag']",
+			want:    strings.Replace(values, "tag: 2.0.0", "tag: 1.3.0", 1),
+		},
+		{
+			name:    "sequence scalar",
+			content: kustomization,
+			path:    "$.resources[0]",
+			want:    strings.Replace(kustomization, "- deployment.yaml", "- 1.3.0", 1),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReplaceScalar(tc.content, tc.path, func(string) string { return "1.3.0" })
+			if err != nil {
+				t.Fatalf("ReplaceScalar failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReplaceScalar_PassesOldValue(t *testing.T) {
+	var old string
+	_, err := ReplaceScalar("image:\n  tag: \"v1.2.3\" # pinned\n", "$.image.tag", func(v string) string {
+		old = v
+		return v
+	})
+	if err != nil {
+		t.Fatalf("ReplaceScalar failed: %v", err)
+	}
+	if old != "v1.2.3" {
+		t.Errorf("expected old value v1.2.3, got %q", old)
+	}
+}
+
+func TestReplaceScalar_Errors(t *testing.T) {
+	content := "image:\n  tag: 1.0.0\n  ports: [80, 443]\nimages:\n- name: api\n  newTag: 1.0.0\n"
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing key", "$.image.digest"},
+		{"missing filter match", "$.images[?(@.name=='web')].newTag"},
+		{"index out of range", "$.images[3].newTag"},
+		{"not a scalar", "$.image"},
+		{"flow collection", "$.image.ports"},
+		{"invalid path", "$.images[x]"},
+		{"unsupported filter", "$.images[?(@.name!='web')]"},
+		{"empty key", "$..tag"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReplaceScalar(content, tc.path, func(v string) string { return v }); err == nil {
+				t.Errorf("expected error for %s", tc.path)
+			}
+		})
+	}
+
+	if _, err := ReplaceScalar("# only a comment\n", "$.tag", func(v string) string { return v }); err == nil {
+		t.Error("expected error for an empty document")
+	}
+}
+
+func TestLookupScalar(t *testing.T) {
+	content := "image:\n  tag: \"v1.2.3\" # pinned\nimages:\n- name: api\n  newTag: 2.0.1\n"
+
+	if got, err := LookupScalar(content, "$.image.tag"); err != nil || got != "v1.2.3" {
+		t.Errorf("expected v1.2.3, got %q, %v", got, err)
+	}
+	if got, err := LookupScalar(content, "$.images[?(@.name=='api')].newTag"); err != nil || got != "2.0.1" {
+		t.Errorf("expected 2.0.1, got %q, %v", got, err)
+	}
+	if _, err := LookupScalar(content, "$.images[?(@.name=='web')].newTag"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing filter match, got %v", err)
+	}
+	if _, err := LookupScalar(content, "$.image"); err == nil {
+		t.Error("expected error for a mapping")
+	}
+}
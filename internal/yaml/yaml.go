@@ -0,0 +1,660 @@
+// Package yaml implements the subset of YAML used by .release-damnit.yaml.
+// It keeps the module free of third-party dependencies.
+//
+// Supported: block mappings and sequences (including "- key: value" items),
+// plain, single-quoted, and double-quoted scalars, literal (|) and folded (>)
+// block scalars, single-line flow collections ([a, b] and {a: 1}), comments,
+// and a leading "---". Not supported: anchors, aliases, tags, multi-line plain
+// scalars, and multiple documents.
+//
+// Values decode to the same types encoding/json produces for interface{}
+// (map[string]interface{}, []interface{}, string, bool, nil), except that
+// integers decode to int64 and other numbers to float64.
+//
+// ReplaceScalar edits a single scalar in place, for updating version fields
+// in files release-damnit doesn't own, and LookupScalar reads one.
+package yaml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// line is a non-blank, non-comment source line.
+type line struct {
+	num    int // 1-based
+	indent int
+	text   string // without indentation or trailing comment
+}
+
+// parser holds the state of a single Unmarshal call.
+type parser struct {
+	raw   []string // all source lines, for block scalars
+	lines []*line
+	pos   int
+}
+
+var (
+	intRegex   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	floatRegex = regexp.MustCompile(`^[-+]?([0-9]+\.[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+)
+
+// Unmarshal parses a YAML document. An empty document decodes to nil.
+func Unmarshal(data []byte) (interface{}, error) {
+	p := &parser{raw: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")}
+	for i, raw := range p.raw {
+		if strings.TrimLeft(raw, " ") != strings.TrimLeft(raw, " \t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text := strings.TrimRight(stripComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (len(p.lines) == 0 && trimmed == "---") {
+			continue
+		}
+		p.lines = append(p.lines, &line{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		return nil, fmt.Errorf("line %d: unexpected content %q", l.num, l.text)
+	}
+	return v, nil
+}
+
+// parseBlock parses the node starting at the current line, which must be at indent.
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	l := p.lines[p.pos]
+	switch {
+	case isSequenceItem(l.text):
+		return p.parseSequence(indent)
+	case mappingKeyEnd(l.text) >= 0:
+		return p.parseMapping(indent)
+	default:
+		p.pos++
+		return parseInline(l.text, l.num)
+	}
+}
+
+// parseMapping parses consecutive "key: value" lines at indent.
+func (p *parser) parseMapping(indent int) (interface{}, error) {
+	m := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		end := mappingKeyEnd(l.text)
+		if end < 0 {
+			if isSequenceItem(l.text) {
+				break
+			}
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", l.num, l.text)
+		}
+
+		key, err := parseKey(l.text[:end], l.num)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, key)
+		}
+		rest := strings.TrimSpace(l.text[end+1:])
+		p.pos++
+
+		value, err := p.parseValue(rest, indent, l, true)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+// parseSequence parses consecutive "- item" lines at indent.
+func (p *parser) parseSequence(indent int) (interface{}, error) {
+	seq := []interface{}{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent != indent || !isSequenceItem(l.text) {
+			if l.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+			}
+			break
+		}
+
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest != "" && mappingKeyEnd(rest) >= 0 && !isFlow(rest) {
+			// "- key: value" starts a mapping indented to the key's column
+			l.indent += len(l.text) - len(rest)
+			l.text = rest
+			item, err := p.parseMapping(l.indent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, item)
+			continue
+		}
+
+		p.pos++
+		item, err := p.parseValue(rest, indent, l, false)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, item)
+	}
+	return seq, nil
+}
+
+// parseValue parses the value after "key:" or "-". An empty value is a nested
+// block on the following lines, or null. In a mapping, a sequence may start at
+// the same indent as its key.
+func (p *parser) parseValue(rest string, indent int, l *line, inMapping bool) (interface{}, error) {
+	if rest == "" {
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (inMapping && next.indent == indent && isSequenceItem(next.text)) {
+				return p.parseBlock(next.indent)
+			}
+		}
+		return nil, nil
+	}
+	if rest[0] == '|' || rest[0] == '>' {
+		return p.parseBlockScalar(rest, indent, l)
+	}
+	return parseInline(rest, l.num)
+}
+
+// parseBlockScalar reads a literal (|) or folded (>) block scalar from the raw
+// lines following l. Chomping indicators (- and +) are supported.
+func (p *parser) parseBlockScalar(header string, indent int, l *line) (interface{}, error) {
+	folded := header[0] == '>'
+	chomp := strings.TrimSpace(header[1:])
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, fmt.Errorf("line %d: unsupported block scalar header %q", l.num, header)
+	}
+
+	// Collect raw lines until one is indented at or below the parent
+	var body []string
+	blockIndent := -1
+	end := l.num // index into raw of the first line after l
+	for ; end < len(p.raw); end++ {
+		raw := strings.TrimRight(p.raw[end], " \t")
+		if strings.TrimSpace(raw) == "" {
+			body = append(body, "")
+			continue
+		}
+		lineIndent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if lineIndent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = lineIndent
+		}
+		if lineIndent < blockIndent {
+			return nil, fmt.Errorf("line %d: block scalar is less indented than its first line", end+1)
+		}
+		body = append(body, raw[blockIndent:])
+	}
+
+	// Skip the parsed lines past the block
+	for p.pos < len(p.lines) && p.lines[p.pos].num <= end {
+		p.pos++
+	}
+
+	// Trailing blank lines belong to chomping, not content
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+
+	var text string
+	if folded {
+		var sb strings.Builder
+		for i, b := range body {
+			switch {
+			case i == 0:
+			case b == "" || body[i-1] == "":
+				sb.WriteString("\n")
+			default:
+				sb.WriteString(" ")
+			}
+			sb.WriteString(b)
+		}
+		text = sb.String()
+	} else {
+		text = strings.Join(body, "\n")
+	}
+
+	switch chomp {
+	case "-":
+	case "+":
+		text += "\n" + strings.Repeat("\n", trailing)
+	default:
+		if len(body) > 0 {
+			text += "\n"
+		}
+	}
+	return text, nil
+}
+
+// parseInline parses a scalar or a single-line flow collection.
+func parseInline(s string, num int) (interface{}, error) {
+	if isFlow(s) {
+		f := &flowParser{s: s, num: num}
+		v, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpace()
+		if f.pos < len(f.s) {
+			return nil, fmt.Errorf("line %d: unexpected %q after flow collection", num, f.s[f.pos:])
+		}
+		return v, nil
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		str, n, err := parseQuoted(s, num)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(s[n:]) != "" {
+			return nil, fmt.Errorf("line %d: unexpected %q after quoted string", num, s[n:])
+		}
+		return str, nil
+	}
+	return plainScalar(s), nil
+}
+
+// plainScalar resolves an unquoted scalar to null, bool, number, or string.
+func plainScalar(s string) interface{} {
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if intRegex.MatchString(s) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	}
+	if floatRegex.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// parseKey parses a mapping key, which may be quoted.
+func parseKey(s string, num int) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("line %d: empty mapping key", num)
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		key, n, err := parseQuoted(s, num)
+		if err != nil {
+			return "", err
+		}
+		if n != len(s) {
+			return "", fmt.Errorf("line %d: invalid key %q", num, s)
+		}
+		return key, nil
+	}
+	return s, nil
+}
+
+// parseQuoted parses a quoted string at the start of s, returning the value
+// and the number of bytes consumed.
+func parseQuoted(s string, num int) (string, int, error) {
+	quote := s[0]
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'' && c == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i++
+				continue
+			}
+			return sb.String(), i + 1, nil
+		case quote == '"' && c == '"':
+			return sb.String(), i + 1, nil
+		case quote == '"' && c == '\\':
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("line %d: unterminated escape", num)
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '0':
+				sb.WriteByte(0)
+			case '"', '\\', '/':
+				sb.WriteByte(s[i])
+			case 'u':
+				if i+4 >= len(s) {
+					return "", 0, fmt.Errorf("line %d: invalid \\u escape", num)
+				}
+				r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("line %d: invalid \\u escape", num)
+				}
+				sb.WriteRune(rune(r))
+				i += 4
+			default:
+				return "", 0, fmt.Errorf("line %d: unsupported escape \\%c", num, s[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("line %d: unterminated quoted string", num)
+}
+
+// flowParser parses single-line flow collections.
+type flowParser struct {
+	s   string
+	pos int
+	num int
+}
+
+func (f *flowParser) skipSpace() {
+	for f.pos < len(f.s) && f.s[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flowParser) parseValue() (interface{}, error) {
+	f.skipSpace()
+	if f.pos >= len(f.s) {
+		return nil, fmt.Errorf("line %d: unexpected end of flow collection", f.num)
+	}
+	switch f.s[f.pos] {
+	case '[':
+		return f.parseSequence()
+	case '{':
+		return f.parseMapping()
+	case '"', '\'':
+		str, n, err := parseQuoted(f.s[f.pos:], f.num)
+		if err != nil {
+			return nil, err
+		}
+		f.pos += n
+		return str, nil
+	}
+
+	// Plain scalars end at a flow indicator
+	start := f.pos
+	for f.pos < len(f.s) && !strings.ContainsRune(",]}", rune(f.s[f.pos])) {
+		if f.s[f.pos] == ':' && (f.pos+1 == len(f.s) || f.s[f.pos+1] == ' ') {
+			break
+		}
+		f.pos++
+	}
+	return plainScalar(strings.TrimSpace(f.s[start:f.pos])), nil
+}
+
+func (f *flowParser) parseSequence() (interface{}, error) {
+	f.pos++ // [
+	seq := []interface{}{}
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == ']' {
+			f.pos++
+			return seq, nil
+		}
+		v, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f *flowParser) parseMapping() (interface{}, error) {
+	f.pos++ // {
+	m := make(map[string]interface{})
+	for {
+		f.skipSpace()
+		if f.pos < len(f.s) && f.s[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		k, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		f.skipSpace()
+		if f.pos >= len(f.s) || f.s[f.pos] != ':' {
+			return nil, fmt.Errorf("line %d: expected ':' after key %q", f.num, key)
+		}
+		f.pos++
+		v, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes a comma, or leaves the closing bracket for the caller.
+func (f *flowParser) separator(closer byte) error {
+	f.skipSpace()
+	if f.pos >= len(f.s) {
+		return fmt.Errorf("line %d: unterminated flow collection", f.num)
+	}
+	switch f.s[f.pos] {
+	case ',':
+		f.pos++
+		return nil
+	case closer:
+		return nil
+	}
+	return fmt.Errorf("line %d: expected ',' or '%c', got %q", f.num, closer, f.s[f.pos:])
+}
+
+// stripComment removes a trailing # comment that is outside quotes.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || s[i-1] == ' ' || strings.ContainsRune("[{,:-", rune(s[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// mappingKeyEnd returns the index of the ':' ending a mapping key, or -1.
+func mappingKeyEnd(s string) int {
+	if isFlow(s) {
+		return -1
+	}
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(s) || s[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// isSequenceItem reports whether a line starts a block sequence item.
+func isSequenceItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// isFlow reports whether s starts a flow collection.
+func isFlow(s string) bool {
+	return strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{")
+}
+
+// Marshal renders a value decoded from JSON or YAML as a YAML document.
+// Mapping keys are sorted.
+func Marshal(v interface{}) string {
+	var sb strings.Builder
+	writeNode(&sb, v, 0)
+	return sb.String()
+}
+
+// writeNode writes a value that starts on its own line at indent.
+func writeNode(sb *strings.Builder, v interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			sb.WriteString(pad + "{}\n")
+			return
+		}
+		for _, k := range sortedKeys(val) {
+			sb.WriteString(pad + formatKey(k) + ":")
+			writeChild(sb, val[k], indent)
+		}
+	case []interface{}:
+		if len(val) == 0 {
+			sb.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range val {
+			if m, ok := item.(map[string]interface{}); ok && len(m) > 0 {
+				// Compact "- key: value" form, remaining keys aligned with the first
+				var inner strings.Builder
+				writeNode(&inner, m, indent+2)
+				sb.WriteString(pad + "- " + strings.TrimPrefix(inner.String(), pad+"  "))
+				continue
+			}
+			sb.WriteString(pad + "-")
+			writeChild(sb, item, indent)
+		}
+	default:
+		sb.WriteString(pad + formatScalar(v) + "\n")
+	}
+}
+
+// writeChild writes the value after "key:" or "-": scalars and empty
+// collections inline, everything else as a nested block.
+func writeChild(sb *strings.Builder, v interface{}, indent int) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			sb.WriteString(" {}\n")
+			return
+		}
+		sb.WriteString("\n")
+		writeNode(sb, val, indent+2)
+	case []interface{}:
+		if len(val) == 0 {
+			sb.WriteString(" []\n")
+			return
+		}
+		sb.WriteString("\n")
+		writeNode(sb, val, indent+2)
+	default:
+		sb.WriteString(" " + formatScalar(v) + "\n")
+	}
+}
+
+// formatKey renders a mapping key, quoting it if needed.
+func formatKey(k string) string {
+	if needsQuotes(k) {
+		return strconv.Quote(k)
+	}
+	return k
+}
+
+// formatScalar renders a scalar value.
+func formatScalar(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		if needsQuotes(val) {
+			return strconv.Quote(val)
+		}
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// needsQuotes reports whether a string would be misread as a plain scalar.
+func needsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	if _, ok := plainScalar(s).(string); !ok {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	return strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsAny(s, "\n\t\\\"")
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
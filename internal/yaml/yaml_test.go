@@ -0,0 +1,163 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decode parses YAML and normalizes it through JSON for easy comparison.
+func decode(t *testing.T, src string) string {
+	t.Helper()
+	v, err := Unmarshal([]byte(src))
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, src)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestUnmarshal_Config(t *testing.T) {
+	src := `---
+# release-damnit config
+packages:
+  workloads/jarvis:
+    component: jarvis
+    extra-files:
+      - src/version.go
+      - type: generic   # explicit updater
+        path: chart/Chart.yaml
+  "workloads/web":
+    component: 'jarvis-web'
+plugins:
+- type: linked-versions
+  groupName: observe
+  components: [observe-client, "observe-server"]
+hooks:
+  post-apply:
+    - npm install --package-lock-only
+jira: {base-url: "https://acme.atlassian.net", comment: true}
+count: 3
+ratio: 0.5
+empty:
+nothing: ~
+`
+	want := `{"count":3,"empty":null,"hooks":{"post-apply":["npm install --package-lock-only"]},` +
+		`"jira":{"base-url":"https://acme.atlassian.net","comment":true},"nothing":null,` +
+		`"packages":{"workloads/jarvis":{"component":"jarvis","extra-files":["src/version.go",{"path":"chart/Chart.yaml","type":"generic"}]},` +
+		`"workloads/web":{"component":"jarvis-web"}},` +
+		`"plugins":[{"components":["observe-client","observe-server"],"groupName":"observe","type":"linked-versions"}],"ratio":0.5}`
+
+	if got := decode(t, src); got != want {
+		t.Errorf("unexpected decode:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUnmarshal_Scalars(t *testing.T) {
+	tests := []struct {
+		src  string
+		want interface{}
+	}{
+		{"v: 1.2.3", "1.2.3"},
+		{"v: 42", int64(42)},
+		{"v: -1.5", -1.5},
+		{"v: true", true},
+		{"v: null", nil},
+		{`v: "quoted # not a comment"`, "quoted # not a comment"},
+		{`v: 'it''s'`, "it's"},
+		{`v: "tab\tand \u00e9"`, "tab\tand é"},
+		{"v: don't # comment", "don't"},
+		{"v: https://example.com/a#b", "https://example.com/a#b"},
+		{`v: "123"`, "123"},
+	}
+	for _, tc := range tests {
+		v, err := Unmarshal([]byte(tc.src))
+		if err != nil {
+			t.Errorf("%s: %v", tc.src, err)
+			continue
+		}
+		got := v.(map[string]interface{})["v"]
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %#v, want %#v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestUnmarshal_BlockScalars(t *testing.T) {
+	src := `hooks:
+  pre-release:
+    - |
+      make dist
+      make checksums
+    - >-
+      echo one
+      two
+after: x
+`
+	want := `{"after":"x","hooks":{"pre-release":["make dist\nmake checksums\n","echo one two"]}}`
+	if got := decode(t, src); got != want {
+		t.Errorf("unexpected decode:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUnmarshal_Empty(t *testing.T) {
+	v, err := Unmarshal([]byte("# only a comment\n\n"))
+	if err != nil || v != nil {
+		t.Errorf("expected nil document, got %v, %v", v, err)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := map[string]string{
+		"tab indent":     "a:\n\tb: 1",
+		"bad indent":     "a: 1\n  b: 2",
+		"duplicate key":  "a: 1\na: 2",
+		"unterminated":   `a: "open`,
+		"bad flow":       "a: [1, 2",
+		"mixed sequence": "a:\n  - 1\n  b: 2",
+	}
+	for name, src := range tests {
+		if _, err := Unmarshal([]byte(src)); err == nil {
+			t.Errorf("%s: expected error", name)
+		} else if !strings.Contains(err.Error(), "line ") {
+			t.Errorf("%s: expected line number in error, got %v", name, err)
+		}
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	var v interface{}
+	src := `{
+		"packages": {
+			"workloads/jarvis": {"component": "jarvis", "extra-files": ["a.go", {"type": "generic", "path": "b.yaml"}]}
+		},
+		"plugins": [{"type": "linked-versions", "components": ["a", "b"]}],
+		"hooks": {"pre-apply": [], "post-apply": ["echo \"done\": ok"]},
+		"notifications": {},
+		"version": "1.0",
+		"comment": true,
+		"empty": ""
+	}`
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	out := Marshal(v)
+	if !strings.Contains(out, "plugins:\n  - components:\n      - a\n      - b\n    type: linked-versions\n") {
+		t.Errorf("expected compact sequence items:\n%s", out)
+	}
+
+	back, err := Unmarshal([]byte(out))
+	if err != nil {
+		t.Fatalf("Unmarshal of Marshal output failed: %v\n%s", err, out)
+	}
+	a, _ := json.Marshal(v)
+	b, _ := json.Marshal(back)
+	if string(a) != string(b) {
+		t.Errorf("round trip mismatch:\n got: %s\nwant: %s\nyaml:\n%s", b, a, out)
+	}
+}
@@ -129,6 +129,28 @@ func (v ContractViolation) Error() string {
 	return fmt.Sprintf("contract violation (%s) at %s: %s", v.Type, v.Location, v.Message)
 }
 
+// Recover converts a ContractViolation panic into an error. Defer it at API
+// boundaries that should report violations to callers instead of crashing.
+// Other panics are re-raised unchanged.
+//
+// Example:
+//
+//	func Analyze(opts *Options) (result *AnalysisResult, err error) {
+//	    defer contracts.Recover(&err)
+//	    // ...
+//	}
+func Recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if v, ok := r.(ContractViolation); ok {
+		*err = v
+		return
+	}
+	panic(r)
+}
+
 // newViolation creates a new ContractViolation with caller information.
 func newViolation(violationType string, format string, args ...interface{}) ContractViolation {
 	message := fmt.Sprintf(format, args...)
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -240,3 +241,47 @@ func TestViolation_IncludesCallerLocation(t *testing.T) {
 
 	Require(false, "test")
 }
+
+func TestRecover_ConvertsViolation(t *testing.T) {
+	fn := func() (err error) {
+		defer Recover(&err)
+		RequireNotEmpty("", "name")
+		return nil
+	}
+
+	err := fn()
+	if err == nil {
+		t.Fatal("expected error from recovered violation")
+	}
+	var violation ContractViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("expected ContractViolation, got %T", err)
+	}
+	if violation.Type != "precondition" || violation.Message != "name cannot be empty" {
+		t.Errorf("unexpected violation: %+v", violation)
+	}
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	fn := func() (err error) {
+		defer Recover(&err)
+		return nil
+	}
+	if err := fn(); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestRecover_RepanicsOtherValues(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected original panic value, got %v", r)
+		}
+	}()
+
+	fn := func() (err error) {
+		defer Recover(&err)
+		panic("boom")
+	}
+	_ = fn()
+}
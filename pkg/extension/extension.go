@@ -0,0 +1,156 @@
+// Package extension is the public extension API for release-damnit.
+// Organizations that build their own binary can register custom version
+// strategies and file updaters at init time, then select them per package in
+// release-please-config.json without forking the analyzer:
+//
+//	"packages": {
+//	  "services/api": {
+//	    "component": "api",
+//	    "versioning": "calver",
+//	    "extra-files": [{"type": "helm-chart", "path": "chart/Chart.yaml"}]
+//	  }
+//	}
+//
+// Built-in strategies are "default", "always-bump-patch", "always-bump-minor",
+// and "always-bump-major". The built-in "generic" updater replaces versions on
+// lines marked x-release-please-version.
+package extension
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// VersionRequest describes the version bump being calculated for a package.
+type VersionRequest struct {
+	Component      string
+	CurrentVersion string
+
+	// BumpType is the bump implied by the commits: "major", "minor", or "patch".
+	BumpType string
+
+	// TreatPreMajorAsMinor is the analyzer's --treat-pre-major-as-minor setting.
+	TreatPreMajorAsMinor bool
+
+	// Messages are the conventional subject lines of the commits in the release
+	// (e.g., "feat(api): add endpoint").
+	Messages []string
+}
+
+// VersionStrategy calculates the next version of a package. The returned
+// version must be valid semver and greater than the current version.
+type VersionStrategy interface {
+	NextVersion(req *VersionRequest) (string, error)
+}
+
+// VersionStrategyFunc adapts a function to a VersionStrategy.
+type VersionStrategyFunc func(req *VersionRequest) (string, error)
+
+// NextVersion calls f(req).
+func (f VersionStrategyFunc) NextVersion(req *VersionRequest) (string, error) {
+	return f(req)
+}
+
+// UpdateRequest describes a file whose version is being updated.
+type UpdateRequest struct {
+	Component string
+
+	// Path is relative to the repository root.
+	Path string
+
+	// Content is the current file content, or "" if the file doesn't exist.
+	Content string
+
+	OldVersion string
+	NewVersion string
+
+	// Options holds the extra keys of the extra-files entry (e.g., "jsonpath").
+	Options map[string]interface{}
+}
+
+// FileUpdater rewrites a file for a new version and returns the updated content.
+type FileUpdater interface {
+	Update(req *UpdateRequest) (string, error)
+}
+
+// FileUpdaterFunc adapts a function to a FileUpdater.
+type FileUpdaterFunc func(req *UpdateRequest) (string, error)
+
+// Update calls f(req).
+func (f FileUpdaterFunc) Update(req *UpdateRequest) (string, error) {
+	return f(req)
+}
+
+var (
+	mu         sync.RWMutex
+	strategies = make(map[string]VersionStrategy)
+	updaters   = make(map[string]FileUpdater)
+)
+
+// RegisterVersionStrategy makes a version strategy available by name.
+// Panics if the name is empty or already registered.
+func RegisterVersionStrategy(name string, strategy VersionStrategy) {
+	contracts.RequireNotEmpty(name, "name")
+	contracts.RequireNotNil(strategy, "strategy")
+
+	mu.Lock()
+	defer mu.Unlock()
+	_, exists := strategies[name]
+	contracts.Require(!exists, "version strategy %q already registered", name)
+	strategies[name] = strategy
+}
+
+// LookupVersionStrategy returns the version strategy registered under name.
+func LookupVersionStrategy(name string) (VersionStrategy, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	strategy, ok := strategies[name]
+	return strategy, ok
+}
+
+// VersionStrategies returns the registered strategy names, sorted.
+func VersionStrategies() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return sortedKeys(strategies)
+}
+
+// RegisterFileUpdater makes a file updater available by name.
+// Panics if the name is empty or already registered.
+func RegisterFileUpdater(name string, updater FileUpdater) {
+	contracts.RequireNotEmpty(name, "name")
+	contracts.RequireNotNil(updater, "updater")
+
+	mu.Lock()
+	defer mu.Unlock()
+	_, exists := updaters[name]
+	contracts.Require(!exists, "file updater %q already registered", name)
+	updaters[name] = updater
+}
+
+// LookupFileUpdater returns the file updater registered under name.
+func LookupFileUpdater(name string) (FileUpdater, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	updater, ok := updaters[name]
+	return updater, ok
+}
+
+// FileUpdaters returns the registered updater names, sorted.
+func FileUpdaters() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return sortedKeys(updaters)
+}
+
+// sortedKeys returns the keys of a registry map in sorted order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package extension
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterVersionStrategy(t *testing.T) {
+	t.Cleanup(func() { unregister("test-fixed") })
+	RegisterVersionStrategy("test-fixed", VersionStrategyFunc(func(req *VersionRequest) (string, error) {
+		return "9.9.9", nil
+	}))
+
+	strategy, ok := LookupVersionStrategy("test-fixed")
+	if !ok {
+		t.Fatal("expected registered strategy to be found")
+	}
+	got, err := strategy.NextVersion(&VersionRequest{CurrentVersion: "1.0.0", BumpType: "patch"})
+	if err != nil || got != "9.9.9" {
+		t.Errorf("expected 9.9.9, got %q (%v)", got, err)
+	}
+
+	if _, ok := LookupVersionStrategy("missing"); ok {
+		t.Error("expected unknown strategy to be missing")
+	}
+
+	found := false
+	for _, name := range VersionStrategies() {
+		if name == "test-fixed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected test-fixed in %v", VersionStrategies())
+	}
+}
+
+func TestRegisterFileUpdater(t *testing.T) {
+	t.Cleanup(func() { unregister("test-upper") })
+	RegisterFileUpdater("test-upper", FileUpdaterFunc(func(req *UpdateRequest) (string, error) {
+		return strings.ToUpper(req.Content), nil
+	}))
+
+	updater, ok := LookupFileUpdater("test-upper")
+	if !ok {
+		t.Fatal("expected registered updater to be found")
+	}
+	got, err := updater.Update(&UpdateRequest{Content: "version"})
+	if err != nil || got != "VERSION" {
+		t.Errorf("expected VERSION, got %q (%v)", got, err)
+	}
+	if names := FileUpdaters(); len(names) == 0 {
+		t.Error("expected registered updater names")
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	t.Cleanup(func() { unregister("test-dup") })
+	RegisterFileUpdater("test-dup", FileUpdaterFunc(func(req *UpdateRequest) (string, error) { return "", nil }))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate registration")
+		}
+	}()
+	RegisterFileUpdater("test-dup", FileUpdaterFunc(func(req *UpdateRequest) (string, error) { return "", nil }))
+}
+
+// unregister removes test registrations so tests can run with -count > 1.
+func unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(strategies, name)
+	delete(updaters, name)
+}
@@ -0,0 +1,156 @@
+// Package releasedamnit is the public Go API for release-damnit. It lets other
+// tooling (custom CI orchestrators, bots, binaries that register extensions)
+// embed the analysis instead of shelling out to the CLI.
+//
+// Typical use mirrors the CLI:
+//
+//	result, err := releasedamnit.Analyze(&releasedamnit.Options{RepoPath: "."})
+//	if err != nil {
+//	    return err
+//	}
+//	if err := releasedamnit.Apply(result, false); err != nil {
+//	    return err
+//	}
+//	report := releasedamnit.BuildReleaseReport(result, repoURL)
+//
+// Functions that return an error also report violated preconditions (for
+// example a nil result) as a contracts.ContractViolation error rather than
+// panicking.
+//
+// # Stability
+//
+// This package follows the module's semantic version. Within a major version,
+// exported identifiers here are not removed or renamed, function signatures
+// don't change, and struct fields are only added. Everything under internal/
+// may change at any time; the types below are aliases so values pass freely
+// between the two, but only what's reachable from this package is covered.
+package releasedamnit
+
+import (
+	"github.com/dsswift/release-damnit/internal/config"
+	"github.com/dsswift/release-damnit/internal/git"
+	"github.com/dsswift/release-damnit/internal/release"
+	"github.com/dsswift/release-damnit/internal/version"
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// Analysis types.
+type (
+	// Options configures Analyze.
+	Options = release.Options
+
+	// AnalysisResult is the outcome of Analyze.
+	AnalysisResult = release.AnalysisResult
+
+	// AnalysisStats holds diagnostic counts from the analysis.
+	AnalysisStats = release.AnalysisStats
+
+	// PackageRelease is the planned release of a single package.
+	PackageRelease = release.PackageRelease
+
+	// FileChange is a file write planned by Apply.
+	FileChange = release.FileChange
+
+	// Config is the parsed Release Please configuration and manifest.
+	Config = config.Config
+
+	// Package is a single package's configuration.
+	Package = config.Package
+
+	// Commit is a parsed conventional commit.
+	Commit = git.Commit
+
+	// MergeInfo describes the analyzed HEAD commit.
+	MergeInfo = git.MergeInfo
+
+	// BumpType is the kind of version bump.
+	BumpType = version.BumpType
+
+	// FreezeError is returned by Apply, ApplyAndCommit, and
+	// CreateGitHubReleases during a release freeze.
+	FreezeError = release.FreezeError
+)
+
+// Bump types.
+const (
+	// BumpNone means no release is needed.
+	BumpNone  = version.None
+	BumpPatch = version.Patch
+	BumpMinor = version.Minor
+	BumpMajor = version.Major
+)
+
+// Report types. These are the JSON documents the CLI writes to the
+// release_report and analysis_input GitHub Actions outputs.
+type (
+	ReleaseReport    = release.ReleaseReport
+	ComponentRelease = release.ComponentRelease
+	ReleaseSummary   = release.ReleaseSummary
+	AnalysisInput    = release.AnalysisInput
+)
+
+// GitHub release types.
+type (
+	// GitHubReleaseOptions configures CreateGitHubReleases.
+	GitHubReleaseOptions = release.GitHubReleaseOptions
+
+	// GitHubRelease is a created (or, in dry-run, planned) GitHub release.
+	GitHubRelease = release.GitHubRelease
+)
+
+// Analyze analyzes HEAD of the repository for releasable changes.
+func Analyze(opts *Options) (result *AnalysisResult, err error) {
+	defer contracts.Recover(&err)
+	return release.Analyze(opts)
+}
+
+// PlanChanges returns the file changes Apply would make, without writing.
+func PlanChanges(result *AnalysisResult) (changes []*FileChange, err error) {
+	defer contracts.Recover(&err)
+	return release.PlanChanges(result)
+}
+
+// Apply writes VERSION files, extra files, changelogs, and the manifest.
+func Apply(result *AnalysisResult, dryRun bool) (err error) {
+	defer contracts.Recover(&err)
+	return release.Apply(result, dryRun)
+}
+
+// Commit modes for CommitOptions.
+const (
+	CommitSingle     = release.CommitSingle
+	CommitPerPackage = release.CommitPerPackage
+)
+
+// CommitOptions configures ApplyAndCommit.
+type CommitOptions = release.CommitOptions
+
+// ApplyAndCommit writes the changes like Apply and commits them, in one
+// commit (CommitSingle) or one per release (CommitPerPackage), locally or
+// through the GitHub API. Returns the SHAs of the commits made.
+func ApplyAndCommit(result *AnalysisResult, opts *CommitOptions) (shas []string, err error) {
+	defer contracts.Recover(&err)
+	return release.ApplyAndCommit(result, opts)
+}
+
+// BuildReleaseReport builds the release report for downstream tooling.
+func BuildReleaseReport(result *AnalysisResult, repoURL string) *ReleaseReport {
+	return release.BuildReleaseReport(result, repoURL)
+}
+
+// BuildAnalysisInput builds the full analysis input document for debugging.
+func BuildAnalysisInput(result *AnalysisResult) *AnalysisInput {
+	return release.BuildAnalysisInput(result)
+}
+
+// BuildReleaseNotes renders the GitHub release notes for a package release.
+func BuildReleaseNotes(rel *PackageRelease, repoURL string) string {
+	return release.BuildReleaseNotes(rel, repoURL)
+}
+
+// CreateGitHubReleases creates GitHub releases for all releases in the result
+// using the gh CLI.
+func CreateGitHubReleases(result *AnalysisResult, opts *GitHubReleaseOptions) (releases []*GitHubRelease, err error) {
+	defer contracts.Recover(&err)
+	return release.CreateGitHubReleases(result, opts)
+}
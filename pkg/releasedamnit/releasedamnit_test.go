@@ -0,0 +1,99 @@
+package releasedamnit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsswift/release-damnit/pkg/contracts"
+)
+
+// run executes a command in dir, failing the test on error.
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v failed: %v\n%s", args, err, out)
+	}
+}
+
+func write(t *testing.T, dir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnalyzeAndApply(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	run(t, dir, "git", "init", "--initial-branch=main")
+	run(t, dir, "git", "config", "user.email", "test@test.com")
+	run(t, dir, "git", "config", "user.name", "Test")
+	write(t, dir, "release-please-config.json", `{"packages": {"api": {"component": "api"}}}`)
+	write(t, dir, "release-please-manifest.json", `{"api": "1.0.0"}`)
+	write(t, dir, "api/VERSION", "1.0.0\n")
+	run(t, dir, "git", "add", "-A")
+	run(t, dir, "git", "commit", "-m", "chore: initial")
+
+	write(t, dir, "api/main.go", "package main\n")
+	run(t, dir, "git", "add", "-A")
+	run(t, dir, "git", "commit", "-m", "feat(api): add endpoint")
+
+	result, err := Analyze(&Options{RepoPath: dir})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(result.Releases) != 1 {
+		t.Fatalf("expected 1 release, got %d", len(result.Releases))
+	}
+	rel := result.Releases[0]
+	if rel.BumpType != BumpMinor || rel.NewVersion != "1.1.0" {
+		t.Errorf("expected minor bump to 1.1.0, got %s %s", rel.BumpType, rel.NewVersion)
+	}
+
+	if err := Apply(result, false); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "api/VERSION"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1.1.0\n" {
+		t.Errorf("unexpected VERSION: %q", data)
+	}
+
+	report := BuildReleaseReport(result, "https://github.com/o/r")
+	if len(report.Releases) != 1 || report.Releases[0].Component != "api" {
+		t.Errorf("unexpected report: %+v", report.Releases)
+	}
+}
+
+func TestAnalyze_ViolationReturnsError(t *testing.T) {
+	_, err := Analyze(&Options{})
+	if err == nil {
+		t.Fatal("expected error for missing RepoPath")
+	}
+	var violation contracts.ContractViolation
+	if !errors.As(err, &violation) {
+		t.Errorf("expected ContractViolation error, got %T: %v", err, err)
+	}
+}
+
+func TestApplyAndCommit_InvalidModeReturnsError(t *testing.T) {
+	_, err := ApplyAndCommit(&AnalysisResult{}, &CommitOptions{Mode: "squash"})
+	var violation contracts.ContractViolation
+	if !errors.As(err, &violation) {
+		t.Errorf("expected ContractViolation error, got %T: %v", err, err)
+	}
+}